@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,6 +13,7 @@ import (
 	"github.com/kevinsudut/wallet-system/app/handler"
 	"github.com/kevinsudut/wallet-system/pkg/lib/database"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
 	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
 	"github.com/kevinsudut/wallet-system/pkg/lib/token"
 )
@@ -32,16 +34,35 @@ func Init() error {
 		return err
 	}
 
+	// Shutdown waits for requests to finish, which streams only do once their context is done. The background
+	// jobs stop with the same context.
+	baseCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// One cache is shared by every domain, so the process listens to the invalidations of other instances once.
+	cache := lrucache.InitShared(baseCtx, redis)
+
+	router := handler.Init(baseCtx, token, db, redis, cache).RegisterHandlers(mux.NewRouter())
+
+	// Streams stay open for as long as the client listens, so they skip the timeout of the other requests.
+	routes := http.NewServeMux()
+	routes.Handle("/events/stream", router)
+	routes.Handle("/", http.TimeoutHandler(
+		router,
+		1*time.Second, // 1 second as timeout
+		"",
+	))
+
 	server := &http.Server{
-		Addr: ":8000",
-		Handler: http.TimeoutHandler(
-			handler.Init(token, db, redis).RegisterHandlers(mux.NewRouter()),
-			1*time.Second, // 1 second as timeout
-			"",
-		),
+		Addr:    ":8000",
+		Handler: routes,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
 		ReadTimeout:  1 * time.Second, // 1 second as timeout
 		WriteTimeout: 1 * time.Second, // 1 second as timeout
 	}
+	server.RegisterOnShutdown(cancel)
 
 	// Terminated gracefully using SIGTERM
 	go func() {
@@ -2,42 +2,106 @@ package domainauth
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/kevinsudut/wallet-system/pkg/helper/singleflight"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/helper/tiercache"
 	"github.com/kevinsudut/wallet-system/pkg/lib/database"
 	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
 	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
 )
 
+// Users are cached with these TTLs in every tier of their loader. They are never served stale, the auth middleware
+// reads their status to reject closed and frozen accounts.
+const (
+	localCacheTTL    = time.Minute * 5
+	redisCacheTTL    = time.Minute * 30
+	negativeCacheTTL = time.Second * 30
+	cacheTTLJitter   = 0.1
+)
+
 type domain struct {
-	db           database.DatabaseItf
-	redis        redis.RedisItf
-	cache        lrucache.LRUCacheItf
-	stmts        databaseStmts
-	singleflight singleflight.SingleFlightItf
+	db      database.DatabaseItf
+	redis   redis.RedisItf
+	loaders loaders
+	stmts   databaseStmts
+}
+
+type loaders struct {
+	userById       *tiercache.Loader[string, entity.User]
+	userByUsername *tiercache.Loader[string, entity.User]
 }
 
 type databaseStmts struct {
-	insertUser        *sqlx.Stmt
-	getUserById       *sqlx.Stmt
-	getUserByUsername *sqlx.Stmt
+	insertUser                   *sqlx.Stmt
+	insertUserCredential         *sqlx.Stmt
+	insertMissingUserCredential  *sqlx.Stmt
+	getUserById                  *sqlx.Stmt
+	getUserByUsername            *sqlx.Stmt
+	getUserCredentialByUserId    *sqlx.Stmt
+	getUserStatusChangesByUserId *sqlx.Stmt
+	insertRefreshToken           *sqlx.Stmt
+	getRefreshTokenByTokenHash   *sqlx.Stmt
+	rotateRefreshTokenById       *sqlx.Stmt
+	revokeRefreshTokenByFamilyId *sqlx.Stmt
+	insertOutboxEvent            *sqlx.Stmt
 }
 
-func Init(db database.DatabaseItf, redis redis.RedisItf) DomainItf {
+func Init(db database.DatabaseItf, redis redis.RedisItf, cache lrucache.LRUCacheItf) DomainItf {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
 	return &domain{
-		db:    db,
-		redis: redis,
-		cache: lrucache.Init(),
+		db:      db,
+		redis:   redis,
+		loaders: newLoaders(redis, cache),
 		stmts: databaseStmts{
-			insertUser:        db.PreparexContext(ctx, queryInsertUser),
-			getUserById:       db.PreparexContext(ctx, queryGetUserById),
-			getUserByUsername: db.PreparexContext(ctx, queryGetUserByUsername),
+			insertUser:                   db.PreparexContext(ctx, queryInsertUser),
+			insertUserCredential:         db.PreparexContext(ctx, queryInsertUserCredential),
+			insertMissingUserCredential:  db.PreparexContext(ctx, queryInsertMissingUserCredential),
+			getUserById:                  db.PreparexContext(ctx, queryGetUserById),
+			getUserByUsername:            db.PreparexContext(ctx, queryGetUserByUsername),
+			getUserCredentialByUserId:    db.PreparexContext(ctx, queryGetUserCredentialByUserId),
+			getUserStatusChangesByUserId: db.PreparexContext(ctx, queryGetUserStatusChangesByUserId),
+			insertRefreshToken:           db.PreparexContext(ctx, queryInsertRefreshToken),
+			getRefreshTokenByTokenHash:   db.PreparexContext(ctx, queryGetRefreshTokenByTokenHash),
+			rotateRefreshTokenById:       db.PreparexContext(ctx, queryRotateRefreshTokenById),
+			revokeRefreshTokenByFamilyId: db.PreparexContext(ctx, queryRevokeRefreshTokenByFamilyId),
+			insertOutboxEvent:            db.PreparexContext(ctx, queryInsertOutboxEvent),
 		},
-		singleflight: singleflight.Init(),
+	}
+}
+
+func newLoaders(redis redis.RedisItf, cache lrucache.LRUCacheItf) loaders {
+	return loaders{
+		userById: tiercache.New[string, entity.User](tiercache.Config[string]{
+			Name: "user_by_id",
+			Key: func(id string) string {
+				return fmt.Sprintf(cacheKeyGetUserById, id)
+			},
+			Local:       cache,
+			LocalTTL:    localCacheTTL,
+			Redis:       redis,
+			RedisTTL:    redisCacheTTL,
+			Jitter:      cacheTTLJitter,
+			NotFound:    sql.ErrNoRows,
+			NegativeTTL: negativeCacheTTL,
+		}),
+		userByUsername: tiercache.New[string, entity.User](tiercache.Config[string]{
+			Name: "user_by_username",
+			Key: func(username string) string {
+				return fmt.Sprintf(cacheKeyGetUserByUsername, username)
+			},
+			Local:       cache,
+			LocalTTL:    localCacheTTL,
+			Redis:       redis,
+			RedisTTL:    redisCacheTTL,
+			Jitter:      cacheTTLJitter,
+			NotFound:    sql.ErrNoRows,
+			NegativeTTL: negativeCacheTTL,
+		}),
 	}
 }
@@ -3,118 +3,267 @@ package domainauth
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
 )
 
-func (d domain) InsertUser(ctx context.Context, user entity.User) (err error) {
-	err = d.db.ExecContextStmt(ctx, d.stmts.insertUser, user.Id, user.Username)
+func (d domain) InsertUser(ctx context.Context, user entity.User, credential entity.UserCredential) (err error) {
+	err = d.insertUser(ctx, user, credential)
 	if err != nil {
 		return err
 	}
 
-	json, err := jsoniter.MarshalToString(user)
+	// The username may have been looked up before it was taken, so a cached miss is replaced on every instance.
+	err = d.loaders.userById.Set(ctx, user.Id, user)
 	if err != nil {
 		return err
 	}
 
-	_, err = d.redis.SetEx(ctx, fmt.Sprintf(cacheKeyGetUserById, user.Id), json, time.Minute*30)
+	err = d.loaders.userByUsername.Set(ctx, user.Username, user)
 	if err != nil {
 		return err
 	}
 
-	_, err = d.redis.SetEx(ctx, fmt.Sprintf(cacheKeyGetUserByUsername, user.Username), json, time.Minute*30)
+	return nil
+}
+
+func (d domain) insertUser(ctx context.Context, user entity.User, credential entity.UserCredential) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+	}()
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertUser, user.Id, user.Username, user.Currency)
+	if err != nil {
+		return err
+	}
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertUserCredential, credential.UserId, credential.PasswordHash)
+	if err != nil {
+		return err
+	}
+
+	payload, err := jsoniter.MarshalToString(entity.UserRegisteredEvent{
+		UserId:   user.Id,
+		Username: user.Username,
+		Currency: user.Currency,
+	})
 	if err != nil {
 		return err
 	}
 
-	d.cache.Set(fmt.Sprintf(cacheKeyGetUserById, user.Id), user, time.Minute*5)
-	d.cache.Set(fmt.Sprintf(cacheKeyGetUserByUsername, user.Username), user, time.Minute*5)
+	// The event is written in the transaction of the user, so it is only published for a user that was registered.
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertOutboxEvent, uuid.NewString(), user.Id, string(enum.EVENT_USER_REGISTERED), enum.EVENT_VERSION, payload)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func (d domain) GetUserById(ctx context.Context, id string) (resp entity.User, err error) {
-	user, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetUserById, id), func() (interface{}, error) {
-		var resp entity.User
-		user, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetUserById, id), time.Minute*5, func() (interface{}, error) {
-			var respRedis entity.User
-			userStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetUserById, id), time.Duration(time.Minute*30), func() (interface{}, error) {
-				var user entity.User
-				err := d.db.GetContextStmt(ctx, d.stmts.getUserById, &user, id)
-				if err != nil {
-					return user, err
-				}
-
-				return user, nil
-			})
-			if err != nil {
-				return respRedis, err
-			}
-
-			err = jsoniter.UnmarshalFromString(userStr, &respRedis)
-			if err != nil {
-				return respRedis, err
-			}
-
-			return respRedis, nil
-		})
+	return d.loaders.userById.Load(ctx, id, func(ctx context.Context) (entity.User, error) {
+		var user entity.User
+		err := d.db.GetContextStmt(ctx, d.stmts.getUserById, &user, id)
+		if err != nil {
+			return user, err
+		}
+
+		return user, nil
+	})
+}
+
+func (d domain) GetUserByUsername(ctx context.Context, username string) (resp entity.User, err error) {
+	return d.loaders.userByUsername.Load(ctx, username, func(ctx context.Context) (entity.User, error) {
+		var user entity.User
+		err := d.db.GetContextStmt(ctx, d.stmts.getUserByUsername, &user, username)
 		if err != nil {
-			return resp, err
+			return user, err
 		}
 
-		return user.Value().(entity.User), nil
+		return user, nil
 	})
+}
+
+// GetUserCredentialByUserId always reads from the database so password hashes never end up in a cache.
+func (d domain) GetUserCredentialByUserId(ctx context.Context, userId string) (resp entity.UserCredential, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getUserCredentialByUserId, &resp, userId)
 	if err != nil {
 		return resp, err
 	}
 
-	return user.(entity.User), nil
+	return resp, nil
 }
 
-func (d domain) GetUserByUsername(ctx context.Context, username string) (resp entity.User, err error) {
+// InsertMissingUserCredential gives a password to a user registered before passwords existed, so they can log in.
+// It returns ErrCredentialExists when the user already has one.
+func (d domain) InsertMissingUserCredential(ctx context.Context, credential entity.UserCredential) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.insertMissingUserCredential, credential.UserId, credential.PasswordHash)
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrCredentialExists
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetUserStatusChanges returns every status change of the user, the latest first.
+func (d domain) GetUserStatusChanges(ctx context.Context, userId string) (resp []entity.UserStatusChange, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getUserStatusChangesByUserId, &resp, userId)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// DeleteUserCache drops the cached user after a change, in Redis and in the memory of every instance.
+func (d domain) DeleteUserCache(ctx context.Context, user entity.User) (err error) {
+	err = d.loaders.userById.Invalidate(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+
+	err = d.loaders.userByUsername.Invalidate(ctx, user.Username)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d domain) GetFailedLoginAttempt(ctx context.Context, userId string) (resp int64, err error) {
+	attemptStr, err := d.redis.Get(ctx, fmt.Sprintf(cacheKeyFailedLoginAttempt, userId))
+	if redis.IsNil(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	return strconv.ParseInt(attemptStr, 10, 64)
+}
+
+// IncrFailedLoginAttempt counts a failed login. The window starts at the first failure and is not extended by later ones.
+func (d domain) IncrFailedLoginAttempt(ctx context.Context, userId string, ttl time.Duration) (resp int64, err error) {
+	resp, err = d.redis.IncrByEx(ctx, fmt.Sprintf(cacheKeyFailedLoginAttempt, userId), 1, ttl)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) DeleteFailedLoginAttempt(ctx context.Context, userId string) (err error) {
+	_, err = d.redis.Delete(ctx, fmt.Sprintf(cacheKeyFailedLoginAttempt, userId))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d domain) InsertRefreshToken(ctx context.Context, refreshToken entity.RefreshToken) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.insertRefreshToken, refreshToken.Id, refreshToken.UserId, refreshToken.FamilyId, refreshToken.TokenHash, refreshToken.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d domain) GetRefreshTokenByTokenHash(ctx context.Context, tokenHash string) (resp entity.RefreshToken, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getRefreshTokenByTokenHash, &resp, tokenHash)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// RotateRefreshToken marks the token as used and stores its successor in one transaction. It returns
+// ErrRefreshTokenReused when the token was already rotated or revoked, including by a concurrent request.
+func (d domain) RotateRefreshToken(ctx context.Context, id string, refreshToken entity.RefreshToken) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
 	defer func() {
-		if err == nil && resp.Id == "" {
-			err = sql.ErrNoRows
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
 		}
 	}()
 
-	user, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetUserById, username), func() (interface{}, error) {
-		var resp entity.User
-		user, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetUserByUsername, username), time.Minute*5, func() (interface{}, error) {
-			var respRedis entity.User
-			userStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetUserByUsername, username), time.Duration(time.Minute*30), func() (interface{}, error) {
-				var user entity.User
-				err := d.db.GetContextStmt(ctx, d.stmts.getUserByUsername, &user, username)
-				if err != nil && err != sql.ErrNoRows {
-					return user, err
-				}
-
-				return user, nil
-			})
-			if err != nil {
-				return respRedis, err
-			}
-
-			err = jsoniter.UnmarshalFromString(userStr, &respRedis)
-			if err != nil {
-				return respRedis, err
-			}
-
-			return respRedis, nil
-		})
-		if err != nil {
-			return resp, err
-		}
+	var rotatedId string
+	err = d.db.GetContextStmtTx(ctx, tx, d.stmts.rotateRefreshTokenById, &rotatedId, id)
+	if err == sql.ErrNoRows {
+		return ErrRefreshTokenReused
+	}
+	if err != nil {
+		return err
+	}
 
-		return user.Value().(entity.User), nil
-	})
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertRefreshToken, refreshToken.Id, refreshToken.UserId, refreshToken.FamilyId, refreshToken.TokenHash, refreshToken.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d domain) RevokeRefreshTokenFamily(ctx context.Context, familyId string) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.revokeRefreshTokenByFamilyId, familyId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAccessToken adds the token id to the denylist. The entry only needs to live until the token expires.
+func (d domain) RevokeAccessToken(ctx context.Context, id string, ttl time.Duration) (err error) {
+	if ttl <= 0 {
+		return nil
+	}
+
+	_, err = d.redis.SetEx(ctx, fmt.Sprintf(cacheKeyRevokedAccessToken, id), 1, ttl)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d domain) IsAccessTokenRevoked(ctx context.Context, id string) (resp bool, err error) {
+	_, err = d.redis.Get(ctx, fmt.Sprintf(cacheKeyRevokedAccessToken, id))
+	if redis.IsNil(err) {
+		return false, nil
+	}
 	if err != nil {
 		return resp, err
 	}
 
-	return user.(entity.User), nil
+	return true, nil
 }
@@ -2,6 +2,7 @@ package domainauth
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"reflect"
@@ -10,29 +11,34 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/kevinsudut/wallet-system/app/entity"
-	"github.com/kevinsudut/wallet-system/pkg/helper/singleflight"
+	"github.com/kevinsudut/wallet-system/app/enum"
 	"github.com/kevinsudut/wallet-system/pkg/lib/database"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
 	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+	goredis "github.com/redis/go-redis/v9"
 	gomock "go.uber.org/mock/gomock"
 )
 
 var (
-	cache = lrucache.Init()
+	cache  = lrucache.Init()
+	errFoo = fmt.Errorf("foo")
 )
 
 func TestMain(m *testing.M) {
 	log.Init()
-	cache.Set(fmt.Sprintf(cacheKeyGetUserById, "id"), entity.User{
+	seed := newLoaders(nil, cache)
+	seed.userById.Set(context.Background(), "id", entity.User{
 		Id:       "id",
 		Username: "username",
-	}, time.Minute*5)
-	cache.Set(fmt.Sprintf(cacheKeyGetUserByUsername, "username"), entity.User{
+	})
+	seed.userByUsername.Set(context.Background(), "username", entity.User{
 		Id:       "id",
 		Username: "username",
-	}, time.Minute*5)
-	cache.Set(fmt.Sprintf(cacheKeyGetUserByUsername, "test"), entity.User{}, time.Minute*5)
+	})
+	seed.userByUsername.Load(context.Background(), "test", func(ctx context.Context) (entity.User, error) {
+		return entity.User{}, sql.ErrNoRows
+	})
 	os.Exit(m.Run())
 }
 
@@ -44,15 +50,15 @@ func Test_domain_InsertUser(t *testing.T) {
 	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
 
 	type fields struct {
-		db           database.DatabaseItf
-		redis        redis.RedisItf
-		cache        lrucache.LRUCacheItf
-		stmts        databaseStmts
-		singleflight singleflight.SingleFlightItf
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
 	}
 	type args struct {
-		ctx  context.Context
-		user entity.User
+		ctx        context.Context
+		user       entity.User
+		credential entity.UserCredential
 	}
 	tests := []struct {
 		name    string
@@ -68,7 +74,8 @@ func Test_domain_InsertUser(t *testing.T) {
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					insertUser: &sqlx.Stmt{},
+					insertUser:           &sqlx.Stmt{},
+					insertUserCredential: &sqlx.Stmt{},
 				},
 			},
 			args: args{
@@ -77,21 +84,25 @@ func Test_domain_InsertUser(t *testing.T) {
 					Id:       "id",
 					Username: "username",
 				},
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
 			},
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "username").Return(nil),
-					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "id"), gomock.Any(), time.Minute*30).Return("", nil),
-					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserByUsername, "username"), gomock.Any(), time.Minute*30).Return("", nil),
-					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetUserById, "id"), entity.User{
-						Id:       "id",
-						Username: "username",
-					}, time.Minute*5),
-					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetUserByUsername, "username"), entity.User{
-						Id:       "id",
-						Username: "username",
-					}, time.Minute*5),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "username", "").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "hash").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_USER_REGISTERED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "id"), gomock.Any(), gomock.Any()).Return("", nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "id")).Return(nil),
+					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetUserById, "id"), gomock.Any(), gomock.Any()),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserByUsername, "username"), gomock.Any(), gomock.Any()).Return("", nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetUserByUsername, "username")).Return(nil),
+					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetUserByUsername, "username"), gomock.Any(), gomock.Any()),
 				)
 			},
 		},
@@ -102,7 +113,8 @@ func Test_domain_InsertUser(t *testing.T) {
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					insertUser: &sqlx.Stmt{},
+					insertUser:           &sqlx.Stmt{},
+					insertUserCredential: &sqlx.Stmt{},
 				},
 			},
 			args: args{
@@ -111,12 +123,20 @@ func Test_domain_InsertUser(t *testing.T) {
 					Id:       "id",
 					Username: "username",
 				},
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "username").Return(nil),
-					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "id"), gomock.Any(), time.Minute*30).Return("", fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "username", "").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "hash").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_USER_REGISTERED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "id"), gomock.Any(), gomock.Any()).Return("", fmt.Errorf("foo")),
 				)
 			},
 		},
@@ -127,7 +147,8 @@ func Test_domain_InsertUser(t *testing.T) {
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					insertUser: &sqlx.Stmt{},
+					insertUser:           &sqlx.Stmt{},
+					insertUserCredential: &sqlx.Stmt{},
 				},
 			},
 			args: args{
@@ -136,24 +157,165 @@ func Test_domain_InsertUser(t *testing.T) {
 					Id:       "id",
 					Username: "username",
 				},
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "username").Return(nil),
-					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "id"), gomock.Any(), time.Minute*30).Return("", nil),
-					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserByUsername, "username"), gomock.Any(), time.Minute*30).Return("", fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "username", "").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "hash").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_USER_REGISTERED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "id"), gomock.Any(), gomock.Any()).Return("", nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "id")).Return(nil),
+					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetUserById, "id"), gomock.Any(), gomock.Any()),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserByUsername, "username"), gomock.Any(), gomock.Any()).Return("", fmt.Errorf("foo")),
 				)
 			},
 		},
 		{
-			name: "error ExecContextStmt",
+			name: "error Commit",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					insertUser:           &sqlx.Stmt{},
+					insertUserCredential: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				user: entity.User{
+					Id:       "id",
+					Username: "username",
+				},
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "username", "").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "hash").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_USER_REGISTERED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error insertOutboxEvent.ExecContextStmtTx",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					insertUser:           &sqlx.Stmt{},
+					insertUserCredential: &sqlx.Stmt{},
+					insertOutboxEvent:    &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				user: entity.User{
+					Id:       "id",
+					Username: "username",
+				},
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "username", "").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "hash").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_USER_REGISTERED), enum.EVENT_VERSION, gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insertUserCredential.ExecContextStmtTx",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					insertUser:           &sqlx.Stmt{},
+					insertUserCredential: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				user: entity.User{
+					Id:       "id",
+					Username: "username",
+				},
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "username", "").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "hash").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insertUser.ExecContextStmtTx",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					insertUser:           &sqlx.Stmt{},
+					insertUserCredential: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				user: entity.User{
+					Id:       "id",
+					Username: "username",
+				},
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "username", "").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					insertUser: &sqlx.Stmt{},
+					insertUser:           &sqlx.Stmt{},
+					insertUserCredential: &sqlx.Stmt{},
 				},
 			},
 			args: args{
@@ -162,11 +324,15 @@ func Test_domain_InsertUser(t *testing.T) {
 					Id:       "id",
 					Username: "username",
 				},
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "username").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
 				)
 			},
 		},
@@ -174,14 +340,13 @@ func Test_domain_InsertUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := domain{
-				db:           tt.fields.db,
-				redis:        tt.fields.redis,
-				cache:        tt.fields.cache,
-				stmts:        tt.fields.stmts,
-				singleflight: tt.fields.singleflight,
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
 			}
 			tt.mock()
-			if err := d.InsertUser(tt.args.ctx, tt.args.user); (err != nil) != tt.wantErr {
+			if err := d.InsertUser(tt.args.ctx, tt.args.user, tt.args.credential); (err != nil) != tt.wantErr {
 				t.Errorf("domain.InsertUser() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -196,11 +361,10 @@ func Test_domain_GetUserById(t *testing.T) {
 	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
 
 	type fields struct {
-		db           database.DatabaseItf
-		redis        redis.MockRedisItf
-		cache        lrucache.LRUCacheItf
-		stmts        databaseStmts
-		singleflight singleflight.SingleFlightItf
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
 	}
 	type args struct {
 		ctx context.Context
@@ -215,15 +379,14 @@ func Test_domain_GetUserById(t *testing.T) {
 		mock     func()
 	}{
 		{
-			name: "success",
+			name: "success cache",
 			fields: fields{
 				db:    mockDatabase,
-				redis: *mockRedis,
+				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					getUserById: &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
 				ctx: context.Background(),
@@ -236,32 +399,89 @@ func Test_domain_GetUserById(t *testing.T) {
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetUserById, "id"), time.Minute*5, gomock.Any()).Return(
-						cache.Get(fmt.Sprintf(cacheKeyGetUserById, "id")), nil,
-					),
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetUserById, "id")).Return(cache.Get(fmt.Sprintf(cacheKeyGetUserById, "id"))),
+				)
+			},
+		},
+		{
+			name: "success db",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getUserById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "other",
+			},
+			wantResp: entity.User{
+				Id:       "other",
+				Username: "other",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetUserById, "other")).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "other")).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "other").SetArg(2, entity.User{
+						Id:       "other",
+						Username: "other",
+					}).Return(nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "other"), gomock.Any(), gomock.Any()).Return("", nil),
+					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetUserById, "other"), gomock.Any(), gomock.Any()),
 				)
 			},
 		},
 		{
-			name: "error fetch",
+			name: "error no rows",
 			fields: fields{
 				db:    mockDatabase,
-				redis: *mockRedis,
+				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					getUserById: &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
 				ctx: context.Background(),
-				id:  "id",
+				id:  "other",
+			},
+			wantResp: entity.User{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetUserById, "other")).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "other")).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "other").Return(sql.ErrNoRows),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "other"), gomock.Any(), gomock.Any()).Return("", nil),
+					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetUserById, "other"), gomock.Any(), gomock.Any()),
+				)
+			},
+		},
+		{
+			name: "error db",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getUserById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "other",
 			},
 			wantResp: entity.User{},
 			wantErr:  true,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetUserById, "id"), time.Minute*5, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetUserById, "other")).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetUserById, "other")).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "other").Return(fmt.Errorf("foo")),
 				)
 			},
 		},
@@ -269,11 +489,10 @@ func Test_domain_GetUserById(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := domain{
-				db:           tt.fields.db,
-				redis:        &tt.fields.redis,
-				cache:        tt.fields.cache,
-				stmts:        tt.fields.stmts,
-				singleflight: tt.fields.singleflight,
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
 			}
 			tt.mock()
 			gotResp, err := d.GetUserById(tt.args.ctx, tt.args.id)
@@ -296,11 +515,10 @@ func Test_domain_GetUserByUsername(t *testing.T) {
 	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
 
 	type fields struct {
-		db           database.DatabaseItf
-		redis        redis.RedisItf
-		cache        lrucache.LRUCacheItf
-		stmts        databaseStmts
-		singleflight singleflight.SingleFlightItf
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
 	}
 	type args struct {
 		ctx      context.Context
@@ -315,15 +533,14 @@ func Test_domain_GetUserByUsername(t *testing.T) {
 		mock     func()
 	}{
 		{
-			name: "success",
+			name: "success cache",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					getUserById: &sqlx.Stmt{},
+					getUserByUsername: &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
 				ctx:      context.Background(),
@@ -336,22 +553,19 @@ func Test_domain_GetUserByUsername(t *testing.T) {
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetUserByUsername, "username"), time.Minute*5, gomock.Any()).Return(
-						cache.Get(fmt.Sprintf(cacheKeyGetUserByUsername, "username")), nil,
-					),
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetUserByUsername, "username")).Return(cache.Get(fmt.Sprintf(cacheKeyGetUserByUsername, "username"))),
 				)
 			},
 		},
 		{
-			name: "error no rows",
+			name: "error no rows cache",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					getUserById: &sqlx.Stmt{},
+					getUserByUsername: &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
 				ctx:      context.Background(),
@@ -361,30 +575,57 @@ func Test_domain_GetUserByUsername(t *testing.T) {
 			wantErr:  true,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetUserByUsername, "test"), time.Minute*5, gomock.Any()).Return(cache.Get(fmt.Sprintf(cacheKeyGetUserByUsername, "test")), nil),
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetUserByUsername, "test")).Return(cache.Get(fmt.Sprintf(cacheKeyGetUserByUsername, "test"))),
 				)
 			},
 		},
 		{
-			name: "error fetch",
+			name: "error no rows db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					getUserById: &sqlx.Stmt{},
+					getUserByUsername: &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
 				ctx:      context.Background(),
-				username: "username",
+				username: "other",
+			},
+			wantResp: entity.User{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetUserByUsername, "other")).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetUserByUsername, "other")).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "other").Return(sql.ErrNoRows),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetUserByUsername, "other"), gomock.Any(), gomock.Any()).Return("", nil),
+					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetUserByUsername, "other"), gomock.Any(), gomock.Any()),
+				)
+			},
+		},
+		{
+			name: "error db",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getUserByUsername: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				username: "other",
 			},
 			wantResp: entity.User{},
 			wantErr:  true,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetUserByUsername, "username"), time.Minute*5, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetUserByUsername, "other")).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetUserByUsername, "other")).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "other").Return(fmt.Errorf("foo")),
 				)
 			},
 		},
@@ -392,11 +633,10 @@ func Test_domain_GetUserByUsername(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := domain{
-				db:           tt.fields.db,
-				redis:        tt.fields.redis,
-				cache:        tt.fields.cache,
-				stmts:        tt.fields.stmts,
-				singleflight: tt.fields.singleflight,
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
 			}
 			tt.mock()
 			gotResp, err := d.GetUserByUsername(tt.args.ctx, tt.args.username)
@@ -410,3 +650,1098 @@ func Test_domain_GetUserByUsername(t *testing.T) {
 		})
 	}
 }
+
+func Test_domain_GetUserCredentialByUserId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.UserCredential
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getUserCredentialByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: entity.UserCredential{
+				UserId:       "id",
+				PasswordHash: "hash",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").SetArg(2, entity.UserCredential{
+						UserId:       "id",
+						PasswordHash: "hash",
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not found",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getUserCredentialByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: entity.UserCredential{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(sql.ErrNoRows),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetUserCredentialByUserId(tt.args.ctx, tt.args.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetUserCredentialByUserId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetUserCredentialByUserId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_InsertMissingUserCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx        context.Context
+		credential entity.UserCredential
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertMissingUserCredential: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "hash").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error credential exists",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertMissingUserCredential: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
+			},
+			wantErr: ErrCredentialExists,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "hash").Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertMissingUserCredential: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				credential: entity.UserCredential{
+					UserId:       "id",
+					PasswordHash: "hash",
+				},
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "hash").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			err := d.InsertMissingUserCredential(tt.args.ctx, tt.args.credential)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.InsertMissingUserCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetUserStatusChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	changes := []entity.UserStatusChange{
+		{
+			Id:         "changeid",
+			UserId:     "id",
+			FromStatus: int(enum.USER_STATUS_ACTIVE),
+			ToStatus:   int(enum.USER_STATUS_FROZEN),
+			Reason:     "compromised",
+			ActorId:    "adminid",
+		},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.UserStatusChange
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getUserStatusChangesByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: changes,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").SetArg(2, changes).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getUserStatusChangesByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(errFoo),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetUserStatusChanges(tt.args.ctx, tt.args.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetUserStatusChanges() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetUserStatusChanges() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetFailedLoginAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp int64
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: 3,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyFailedLoginAttempt, "id")).Return("3", nil),
+				)
+			},
+		},
+		{
+			name: "success no failed attempt",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: 0,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyFailedLoginAttempt, "id")).Return("", goredis.Nil),
+				)
+			},
+		},
+		{
+			name: "error get redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyFailedLoginAttempt, "id")).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			tt.mock()
+			gotResp, err := d.GetFailedLoginAttempt(tt.args.ctx, tt.args.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetFailedLoginAttempt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetFailedLoginAttempt() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_IncrFailedLoginAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+		ttl    time.Duration
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp int64
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				ttl:    time.Minute * 15,
+			},
+			wantResp: 1,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), fmt.Sprintf(cacheKeyFailedLoginAttempt, "id"), int64(1), time.Minute*15).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error incr redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				ttl:    time.Minute * 15,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), fmt.Sprintf(cacheKeyFailedLoginAttempt, "id"), int64(1), time.Minute*15).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			tt.mock()
+			gotResp, err := d.IncrFailedLoginAttempt(tt.args.ctx, tt.args.userId, tt.args.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.IncrFailedLoginAttempt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.IncrFailedLoginAttempt() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_DeleteFailedLoginAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyFailedLoginAttempt, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error delete redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyFailedLoginAttempt, "id")).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			tt.mock()
+			if err := d.DeleteFailedLoginAttempt(tt.args.ctx, tt.args.userId); (err != nil) != tt.wantErr {
+				t.Errorf("domain.DeleteFailedLoginAttempt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_InsertRefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	expiresAt := time.Now()
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx          context.Context
+		refreshToken entity.RefreshToken
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertRefreshToken: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				refreshToken: entity.RefreshToken{
+					Id:        "id",
+					UserId:    "user_id",
+					FamilyId:  "family_id",
+					TokenHash: "hash",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "user_id", "family_id", "hash", expiresAt).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertRefreshToken: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				refreshToken: entity.RefreshToken{
+					Id:        "id",
+					UserId:    "user_id",
+					FamilyId:  "family_id",
+					TokenHash: "hash",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "id", "user_id", "family_id", "hash", expiresAt).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.InsertRefreshToken(tt.args.ctx, tt.args.refreshToken); (err != nil) != tt.wantErr {
+				t.Errorf("domain.InsertRefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetRefreshTokenByTokenHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx       context.Context
+		tokenHash string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.RefreshToken
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getRefreshTokenByTokenHash: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:       context.Background(),
+				tokenHash: "hash",
+			},
+			wantResp: entity.RefreshToken{
+				Id:        "id",
+				UserId:    "user_id",
+				FamilyId:  "family_id",
+				TokenHash: "hash",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "hash").SetArg(2, entity.RefreshToken{
+						Id:        "id",
+						UserId:    "user_id",
+						FamilyId:  "family_id",
+						TokenHash: "hash",
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not found",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getRefreshTokenByTokenHash: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:       context.Background(),
+				tokenHash: "hash",
+			},
+			wantResp: entity.RefreshToken{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "hash").Return(sql.ErrNoRows),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetRefreshTokenByTokenHash(tt.args.ctx, tt.args.tokenHash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetRefreshTokenByTokenHash() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetRefreshTokenByTokenHash() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_RotateRefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx          context.Context
+		id           string
+		refreshToken entity.RefreshToken
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertRefreshToken:     &sqlx.Stmt{},
+					rotateRefreshTokenById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "old",
+				refreshToken: entity.RefreshToken{
+					Id: "new",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "old").SetArg(3, "old").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "new", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insertRefreshToken.ExecContextStmtTx",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertRefreshToken:     &sqlx.Stmt{},
+					rotateRefreshTokenById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "old",
+				refreshToken: entity.RefreshToken{
+					Id: "new",
+				},
+			},
+			wantErr: errFoo,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "old").SetArg(3, "old").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "new", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errFoo),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error already rotated",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertRefreshToken:     &sqlx.Stmt{},
+					rotateRefreshTokenById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "old",
+				refreshToken: entity.RefreshToken{
+					Id: "new",
+				},
+			},
+			wantErr: ErrRefreshTokenReused,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "old").Return(sql.ErrNoRows),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error rotateRefreshTokenById.GetContextStmtTx",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertRefreshToken:     &sqlx.Stmt{},
+					rotateRefreshTokenById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "old",
+				refreshToken: entity.RefreshToken{
+					Id: "new",
+				},
+			},
+			wantErr: errFoo,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "old").Return(errFoo),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertRefreshToken:     &sqlx.Stmt{},
+					rotateRefreshTokenById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "old",
+				refreshToken: entity.RefreshToken{
+					Id: "new",
+				},
+			},
+			wantErr: errFoo,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, errFoo),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.RotateRefreshToken(tt.args.ctx, tt.args.id, tt.args.refreshToken); err != tt.wantErr {
+				t.Errorf("domain.RotateRefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_RevokeRefreshTokenFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx      context.Context
+		familyId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					revokeRefreshTokenByFamilyId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				familyId: "family_id",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "family_id").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					revokeRefreshTokenByFamilyId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				familyId: "family_id",
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "family_id").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.RevokeRefreshTokenFamily(tt.args.ctx, tt.args.familyId); (err != nil) != tt.wantErr {
+				t.Errorf("domain.RevokeRefreshTokenFamily() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_RevokeAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+		ttl time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+				ttl: time.Minute,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyRevokedAccessToken, "id"), 1, time.Minute).Return("OK", nil),
+				)
+			},
+		},
+		{
+			name: "success already expired",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+				ttl: -time.Minute,
+			},
+			wantErr: false,
+			mock:    func() {},
+		},
+		{
+			name: "error set redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+				ttl: time.Minute,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyRevokedAccessToken, "id"), 1, time.Minute).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			tt.mock()
+			if err := d.RevokeAccessToken(tt.args.ctx, tt.args.id, tt.args.ttl); (err != nil) != tt.wantErr {
+				t.Errorf("domain.RevokeAccessToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_IsAccessTokenRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp bool
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success revoked",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: true,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyRevokedAccessToken, "id")).Return("1", nil),
+				)
+			},
+		},
+		{
+			name: "success not revoked",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: false,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyRevokedAccessToken, "id")).Return("", goredis.Nil),
+				)
+			},
+		},
+		{
+			name: "error get redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: false,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyRevokedAccessToken, "id")).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			tt.mock()
+			gotResp, err := d.IsAccessTokenRevoked(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.IsAccessTokenRevoked() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp != tt.wantResp {
+				t.Errorf("domain.IsAccessTokenRevoked() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
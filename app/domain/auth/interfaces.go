@@ -2,12 +2,30 @@ package domainauth
 
 import (
 	"context"
+	"time"
 
 	"github.com/kevinsudut/wallet-system/app/entity"
 )
 
 type DomainItf interface {
-	InsertUser(ctx context.Context, user entity.User) (err error)
+	InsertUser(ctx context.Context, user entity.User, credential entity.UserCredential) (err error)
 	GetUserById(ctx context.Context, id string) (resp entity.User, err error)
 	GetUserByUsername(ctx context.Context, username string) (resp entity.User, err error)
+	GetUserCredentialByUserId(ctx context.Context, userId string) (resp entity.UserCredential, err error)
+	InsertMissingUserCredential(ctx context.Context, credential entity.UserCredential) (err error)
+
+	GetUserStatusChanges(ctx context.Context, userId string) (resp []entity.UserStatusChange, err error)
+	DeleteUserCache(ctx context.Context, user entity.User) (err error)
+
+	GetFailedLoginAttempt(ctx context.Context, userId string) (resp int64, err error)
+	IncrFailedLoginAttempt(ctx context.Context, userId string, ttl time.Duration) (resp int64, err error)
+	DeleteFailedLoginAttempt(ctx context.Context, userId string) (err error)
+
+	InsertRefreshToken(ctx context.Context, refreshToken entity.RefreshToken) (err error)
+	GetRefreshTokenByTokenHash(ctx context.Context, tokenHash string) (resp entity.RefreshToken, err error)
+	RotateRefreshToken(ctx context.Context, id string, refreshToken entity.RefreshToken) (err error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyId string) (err error)
+
+	RevokeAccessToken(ctx context.Context, id string, ttl time.Duration) (err error)
+	IsAccessTokenRevoked(ctx context.Context, id string) (resp bool, err error)
 }
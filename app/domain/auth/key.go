@@ -1,11 +1,8 @@
 package domainauth
 
 const (
-	cacheKeyGetUserById       = "domain:user:id:%s"
-	cacheKeyGetUserByUsername = "domain:user:username:%s"
-)
-
-const (
-	singleFlightKeyGetUserById       = "sf:domain:user:id:%s"
-	singleFlightKeyGetUserByUsername = "sf:domain:user:username:%s"
+	cacheKeyGetUserById        = "domain:user:id:%s"
+	cacheKeyGetUserByUsername  = "domain:user:username:%s"
+	cacheKeyFailedLoginAttempt = "domain:user:failed_login_attempt:user_id:%s"
+	cacheKeyRevokedAccessToken = "domain:user:revoked_access_token:id:%s"
 )
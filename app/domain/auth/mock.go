@@ -12,6 +12,7 @@ package domainauth
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/kevinsudut/wallet-system/app/entity"
 	gomock "go.uber.org/mock/gomock"
@@ -40,6 +41,64 @@ func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
 	return m.recorder
 }
 
+// DeleteFailedLoginAttempt mocks base method.
+func (m *MockDomainItf) DeleteFailedLoginAttempt(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFailedLoginAttempt", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFailedLoginAttempt indicates an expected call of DeleteFailedLoginAttempt.
+func (mr *MockDomainItfMockRecorder) DeleteFailedLoginAttempt(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFailedLoginAttempt", reflect.TypeOf((*MockDomainItf)(nil).DeleteFailedLoginAttempt), ctx, userId)
+}
+
+// DeleteUserCache mocks base method.
+func (m *MockDomainItf) DeleteUserCache(ctx context.Context, user entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserCache", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserCache indicates an expected call of DeleteUserCache.
+func (mr *MockDomainItfMockRecorder) DeleteUserCache(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserCache", reflect.TypeOf((*MockDomainItf)(nil).DeleteUserCache), ctx, user)
+}
+
+// GetFailedLoginAttempt mocks base method.
+func (m *MockDomainItf) GetFailedLoginAttempt(ctx context.Context, userId string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailedLoginAttempt", ctx, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFailedLoginAttempt indicates an expected call of GetFailedLoginAttempt.
+func (mr *MockDomainItfMockRecorder) GetFailedLoginAttempt(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailedLoginAttempt", reflect.TypeOf((*MockDomainItf)(nil).GetFailedLoginAttempt), ctx, userId)
+}
+
+// GetRefreshTokenByTokenHash mocks base method.
+func (m *MockDomainItf) GetRefreshTokenByTokenHash(ctx context.Context, tokenHash string) (entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokenByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokenByTokenHash indicates an expected call of GetRefreshTokenByTokenHash.
+func (mr *MockDomainItfMockRecorder) GetRefreshTokenByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenByTokenHash", reflect.TypeOf((*MockDomainItf)(nil).GetRefreshTokenByTokenHash), ctx, tokenHash)
+}
+
 // GetUserById mocks base method.
 func (m *MockDomainItf) GetUserById(ctx context.Context, id string) (entity.User, error) {
 	m.ctrl.T.Helper()
@@ -70,16 +129,146 @@ func (mr *MockDomainItfMockRecorder) GetUserByUsername(ctx, username any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockDomainItf)(nil).GetUserByUsername), ctx, username)
 }
 
+// GetUserCredentialByUserId mocks base method.
+func (m *MockDomainItf) GetUserCredentialByUserId(ctx context.Context, userId string) (entity.UserCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCredentialByUserId", ctx, userId)
+	ret0, _ := ret[0].(entity.UserCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCredentialByUserId indicates an expected call of GetUserCredentialByUserId.
+func (mr *MockDomainItfMockRecorder) GetUserCredentialByUserId(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCredentialByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetUserCredentialByUserId), ctx, userId)
+}
+
+// GetUserStatusChanges mocks base method.
+func (m *MockDomainItf) GetUserStatusChanges(ctx context.Context, userId string) ([]entity.UserStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStatusChanges", ctx, userId)
+	ret0, _ := ret[0].([]entity.UserStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStatusChanges indicates an expected call of GetUserStatusChanges.
+func (mr *MockDomainItfMockRecorder) GetUserStatusChanges(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStatusChanges", reflect.TypeOf((*MockDomainItf)(nil).GetUserStatusChanges), ctx, userId)
+}
+
+// IncrFailedLoginAttempt mocks base method.
+func (m *MockDomainItf) IncrFailedLoginAttempt(ctx context.Context, userId string, ttl time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrFailedLoginAttempt", ctx, userId, ttl)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrFailedLoginAttempt indicates an expected call of IncrFailedLoginAttempt.
+func (mr *MockDomainItfMockRecorder) IncrFailedLoginAttempt(ctx, userId, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrFailedLoginAttempt", reflect.TypeOf((*MockDomainItf)(nil).IncrFailedLoginAttempt), ctx, userId, ttl)
+}
+
+// InsertMissingUserCredential mocks base method.
+func (m *MockDomainItf) InsertMissingUserCredential(ctx context.Context, credential entity.UserCredential) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMissingUserCredential", ctx, credential)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertMissingUserCredential indicates an expected call of InsertMissingUserCredential.
+func (mr *MockDomainItfMockRecorder) InsertMissingUserCredential(ctx, credential any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMissingUserCredential", reflect.TypeOf((*MockDomainItf)(nil).InsertMissingUserCredential), ctx, credential)
+}
+
+// InsertRefreshToken mocks base method.
+func (m *MockDomainItf) InsertRefreshToken(ctx context.Context, refreshToken entity.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRefreshToken", ctx, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertRefreshToken indicates an expected call of InsertRefreshToken.
+func (mr *MockDomainItfMockRecorder) InsertRefreshToken(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRefreshToken", reflect.TypeOf((*MockDomainItf)(nil).InsertRefreshToken), ctx, refreshToken)
+}
+
 // InsertUser mocks base method.
-func (m *MockDomainItf) InsertUser(ctx context.Context, user entity.User) error {
+func (m *MockDomainItf) InsertUser(ctx context.Context, user entity.User, credential entity.UserCredential) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertUser", ctx, user)
+	ret := m.ctrl.Call(m, "InsertUser", ctx, user, credential)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InsertUser indicates an expected call of InsertUser.
-func (mr *MockDomainItfMockRecorder) InsertUser(ctx, user any) *gomock.Call {
+func (mr *MockDomainItfMockRecorder) InsertUser(ctx, user, credential any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockDomainItf)(nil).InsertUser), ctx, user, credential)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockDomainItf) IsAccessTokenRevoked(ctx context.Context, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockDomainItfMockRecorder) IsAccessTokenRevoked(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockDomainItf)(nil).IsAccessTokenRevoked), ctx, id)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockDomainItf) RevokeAccessToken(ctx context.Context, id string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, id, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockDomainItfMockRecorder) RevokeAccessToken(ctx, id, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockDomainItf)(nil).RevokeAccessToken), ctx, id, ttl)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockDomainItf) RevokeRefreshTokenFamily(ctx context.Context, familyId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", ctx, familyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockDomainItfMockRecorder) RevokeRefreshTokenFamily(ctx, familyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockDomainItf)(nil).RevokeRefreshTokenFamily), ctx, familyId)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockDomainItf) RotateRefreshToken(ctx context.Context, id string, refreshToken entity.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, id, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockDomainItfMockRecorder) RotateRefreshToken(ctx, id, refreshToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockDomainItf)(nil).InsertUser), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockDomainItf)(nil).RotateRefreshToken), ctx, id, refreshToken)
 }
@@ -2,13 +2,33 @@ package domainauth
 
 const (
 	queryInsertUser = `
-		INSERT INTO users (id, username) VALUES ($1, $2);
+		INSERT INTO users (id, username, currency) VALUES ($1, $2, $3);
+	`
+
+	queryInsertUserCredential = `
+		INSERT INTO user_credentials (user_id, password_hash) VALUES ($1, $2);
+	`
+
+	// Only users registered before passwords existed have no credential, an existing one is never replaced.
+	queryInsertMissingUserCredential = `
+		INSERT INTO user_credentials (user_id, password_hash) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO NOTHING;
+	`
+
+	queryInsertOutboxEvent = `
+		INSERT INTO outbox_events (event_id, user_id, type, version, payload) VALUES ($1, $2, $3, $4, $5);
 	`
 
 	queryGetUserById = `
 		SELECT
 			id,
-			username
+			username,
+			tier,
+			role,
+			status,
+			status_reason,
+			status_changed_at,
+			currency
 		FROM
 			users
 		WHERE
@@ -18,10 +38,69 @@ const (
 	queryGetUserByUsername = `
 		SELECT
 			id,
-			username
+			username,
+			tier,
+			role,
+			status,
+			status_reason,
+			status_changed_at,
+			currency
 		FROM
 			users
 		WHERE
 			username = $1;
 	`
+
+	queryGetUserStatusChangesByUserId = `
+		SELECT
+			id,
+			user_id,
+			from_status,
+			to_status,
+			reason,
+			actor_id,
+			created_at
+		FROM
+			user_status_changes
+		WHERE
+			user_id = $1
+		ORDER BY created_at DESC, id DESC;
+	`
+
+	queryGetUserCredentialByUserId = `
+		SELECT
+			user_id,
+			password_hash
+		FROM
+			user_credentials
+		WHERE
+			user_id = $1;
+	`
+
+	queryInsertRefreshToken = `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at) VALUES ($1, $2, $3, $4, $5);
+	`
+
+	queryGetRefreshTokenByTokenHash = `
+		SELECT
+			id,
+			user_id,
+			family_id,
+			token_hash,
+			expires_at,
+			rotated_at,
+			revoked_at
+		FROM
+			refresh_tokens
+		WHERE
+			token_hash = $1;
+	`
+
+	queryRotateRefreshTokenById = `
+		UPDATE refresh_tokens SET rotated_at = NOW() WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL RETURNING id;
+	`
+
+	queryRevokeRefreshTokenByFamilyId = `
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL;
+	`
 )
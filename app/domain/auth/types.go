@@ -1 +1,10 @@
 package domainauth
+
+import (
+	"fmt"
+)
+
+var (
+	ErrRefreshTokenReused = fmt.Errorf("refresh token has already been rotated")
+	ErrCredentialExists   = fmt.Errorf("user already has a credential")
+)
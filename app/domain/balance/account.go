@@ -0,0 +1,292 @@
+package domainbalance
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+// lockUserStatuses locks both users of a money movement like lockUsers, and checks that their main balances are in
+// the same currency.
+func (d domain) lockUserStatuses(ctx context.Context, tx *sql.Tx, debitUserId string, creditUserId string) (err error) {
+	users, err := d.lockUsers(ctx, tx, debitUserId, creditUserId)
+	if err != nil {
+		return err
+	}
+
+	return sameCurrency(users[debitUserId], users[creditUserId])
+}
+
+// lockUsers locks both users of a money movement for the rest of tx, keyed by id, and checks that money can leave
+// the account of debitUserId and enter the account of creditUserId.
+func (d domain) lockUsers(ctx context.Context, tx *sql.Tx, debitUserId string, creditUserId string) (resp map[string]entity.User, err error) {
+	var users []entity.User
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.lockUserStatusesByIds, &users, debitUserId, creditUserId)
+	if err != nil {
+		return resp, err
+	}
+
+	resp = make(map[string]entity.User, len(users))
+	for _, user := range users {
+		if (user.Id == debitUserId && !user.CanDebit()) || (user.Id == creditUserId && !user.CanCredit()) {
+			return resp, AccountNotActiveError{
+				UserId: user.Id,
+				Status: user.Status,
+			}
+		}
+
+		resp[user.Id] = user
+	}
+
+	return resp, nil
+}
+
+// sameCurrency checks that money can move from the main balance of user to the one of toUser without a
+// conversion. A missing user, like the funding account of a top-up, has no currency to compare.
+func sameCurrency(user entity.User, toUser entity.User) error {
+	if user.Id == "" || toUser.Id == "" || user.Currency == toUser.Currency {
+		return nil
+	}
+
+	return CurrencyMismatchError{
+		Currency:   user.Currency,
+		ToCurrency: toUser.Currency,
+	}
+}
+
+func (d domain) CloseAccount(ctx context.Context, req CloseAccountRequest) (resp entity.User, err error) {
+	err = database.RetryTx(ctx, func() error {
+		resp, err = d.closeAccount(ctx, req)
+		return err
+	})
+
+	return resp, err
+}
+
+// closeAccount sweeps the balance and closes the account in one transaction, so no money can arrive between the
+// two. Funds reserved by holds have to be settled first, pockets emptied into the main wallet, other currencies
+// converted back to the main one, and shared wallets that the user is the last owner of emptied or joined by
+// another owner.
+func (d domain) closeAccount(ctx context.Context, req CloseAccountRequest) (resp entity.User, err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return resp, err
+	}
+
+	hooks := &txHooks{}
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	counterpartyId := req.UserId
+	if req.SweepToUserId != "" {
+		counterpartyId = req.SweepToUserId
+	}
+
+	var users []entity.User
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.lockUsersByIds, &users, req.UserId, counterpartyId)
+	if err != nil {
+		return resp, err
+	}
+
+	var counterparty entity.User
+	for _, user := range users {
+		if user.Id == req.UserId {
+			resp = user
+		}
+		if user.Id == counterpartyId {
+			counterparty = user
+		}
+	}
+
+	if resp.Id == "" {
+		return resp, sql.ErrNoRows
+	}
+
+	// A frozen user cannot close their own account, and only an active one can move its balance out.
+	if resp.IsClosed() || (!req.Admin && !resp.CanCredit()) {
+		return resp, AccountNotActiveError{
+			UserId: resp.Id,
+			Status: resp.Status,
+		}
+	}
+
+	walletIds, err := d.getLastOwnedSharedWalletIds(ctx, tx, req.UserId)
+	if err != nil {
+		return resp, err
+	}
+
+	if len(walletIds) > 0 {
+		return resp, SharedWalletLastOwnerError{
+			WalletIds: walletIds,
+		}
+	}
+
+	balances, err := d.lockBalancesByUserIds(ctx, tx, req.UserId, counterpartyId)
+	if err != nil {
+		return resp, err
+	}
+
+	pocketsAmount, err := d.getPocketsAmountByUserId(ctx, tx, req.UserId)
+	if err != nil {
+		return resp, err
+	}
+
+	var currencyBalances []entity.CurrencyBalance
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.getCurrencyBalancesByUserId, &currencyBalances, req.UserId)
+	if err != nil {
+		return resp, err
+	}
+
+	var currencies []string
+	for _, currencyBalance := range currencyBalances {
+		if currencyBalance.Amount != 0 {
+			currencies = append(currencies, currencyBalance.Currency)
+		}
+	}
+
+	balance := balances[req.UserId]
+	if balance.HeldAmount > 0 || pocketsAmount > 0 || len(currencies) > 0 || (balance.Amount > 0 && req.SweepToUserId == "") {
+		return resp, BalanceNotZeroError{
+			Balance:    balance.Amount,
+			Held:       balance.HeldAmount,
+			Pockets:    pocketsAmount,
+			Currencies: currencies,
+		}
+	}
+
+	if balance.Amount > 0 {
+		if !req.Admin && !resp.CanDebit() {
+			return resp, AccountNotActiveError{
+				UserId: resp.Id,
+				Status: resp.Status,
+			}
+		}
+
+		if !counterparty.CanCredit() {
+			return resp, AccountNotActiveError{
+				UserId: counterpartyId,
+				Status: counterparty.Status,
+			}
+		}
+
+		err = sameCurrency(resp, counterparty)
+		if err != nil {
+			return resp, err
+		}
+
+		err = d.postTransfer(ctx, tx, hooks, uuid.NewString(), req.UserId, req.SweepToUserId, balance.Amount, 0)
+		if err != nil {
+			return resp, err
+		}
+	}
+
+	return d.setUserStatus(ctx, tx, resp, enum.USER_STATUS_CLOSED, req.Reason, req.ActorId)
+}
+
+// getLastOwnedSharedWalletIds returns the shared wallets that userId is the last owner of and that still hold money.
+// Their members are locked for the rest of tx, so no other owner can leave before the account is closed.
+func (d domain) getLastOwnedSharedWalletIds(ctx context.Context, tx *sql.Tx, userId string) (resp []string, err error) {
+	var memberships []entity.SharedWalletMember
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.getSharedWalletMembersByUserId, &memberships, userId, int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE))
+	if err != nil {
+		return resp, err
+	}
+
+	for _, membership := range memberships {
+		if !membership.IsOwner() {
+			continue
+		}
+
+		var members map[string]entity.SharedWalletMember
+		members, err = d.lockSharedWalletMembers(ctx, tx, membership.WalletId)
+		if err != nil {
+			return resp, err
+		}
+
+		// The membership was read before it was locked, so it is checked again.
+		if !members[userId].IsOwner() || countOwners(members) > 1 {
+			continue
+		}
+
+		var wallet entity.SharedWallet
+		err = d.db.GetContextStmtTx(ctx, tx, d.stmts.lockSharedWalletById, &wallet, membership.WalletId)
+		if err != nil {
+			return resp, err
+		}
+
+		if wallet.Amount != 0 {
+			resp = append(resp, wallet.Id)
+		}
+	}
+
+	return resp, nil
+}
+
+// UpdateUserStatus changes the status of a user that is not closed. It locks the user while its status changes, so
+// it waits for the transfers already moving its money. The user is cached by the auth domain, so the caller drops it
+// from there once the status has changed.
+func (d domain) UpdateUserStatus(ctx context.Context, req UpdateUserStatusRequest) (resp entity.User, err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return resp, err
+	}
+
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+	}()
+
+	var users []entity.User
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.lockUsersByIds, &users, req.UserId, req.UserId)
+	if err != nil {
+		return resp, err
+	}
+
+	if len(users) == 0 {
+		return resp, sql.ErrNoRows
+	}
+
+	resp = users[0]
+	if resp.IsClosed() {
+		return resp, ErrUserClosed
+	}
+
+	return d.setUserStatus(ctx, tx, resp, req.Status, req.Reason, req.ActorId)
+}
+
+// setUserStatus moves user to status within tx and records the change on behalf of actorId.
+func (d domain) setUserStatus(ctx context.Context, tx *sql.Tx, user entity.User, status enum.UserStatus, reason string, actorId string) (resp entity.User, err error) {
+	now := time.Now().UTC()
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.updateUserStatusById, int(status), reason, now, user.Id)
+	if err != nil {
+		return user, err
+	}
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertUserStatusChange, uuid.NewString(), user.Id, user.Status, int(status), reason, actorId)
+	if err != nil {
+		return user, err
+	}
+
+	user.Status = int(status)
+	user.StatusReason = reason
+	user.StatusChangedAt = &now
+
+	return user, nil
+}
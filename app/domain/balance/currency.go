@@ -0,0 +1,134 @@
+package domainbalance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+// GetCurrencyBalancesByUserId reads the balances of a user in currencies other than its main one straight from
+// Postgres, like pockets.
+func (d domain) GetCurrencyBalancesByUserId(ctx context.Context, userId string) (resp []entity.CurrencyBalance, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getCurrencyBalancesByUserId, &resp, userId)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) ConvertBalance(ctx context.Context, req ConvertBalanceRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.convertBalance(ctx, req)
+	})
+}
+
+// convertBalance debits one balance of a user and credits another in one journal entry. The main balance is
+// locked even when only other currencies move, so a closure cannot miss money that lands in one of them.
+func (d domain) convertBalance(ctx context.Context, req ConvertBalanceRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	err = d.insertIdempotencyKey(ctx, tx, req.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	users, err := d.lockUsers(ctx, tx, req.UserId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	user, ok := users[req.UserId]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	balances, err := d.lockBalancesByUserIds(ctx, tx, req.UserId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	var currencyBalances []entity.CurrencyBalance
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.lockCurrencyBalancesByUserId, &currencyBalances, req.UserId, req.Conversion.FromCurrency, req.Conversion.ToCurrency)
+	if err != nil {
+		return err
+	}
+
+	var available money.Money
+	for _, currencyBalance := range currencyBalances {
+		if currencyBalance.Currency == req.Conversion.FromCurrency {
+			available = currencyBalance.Amount
+		}
+	}
+
+	// The main currency is kept in balances, and funds of it reserved by holds cannot be converted.
+	fromAccountId := entity.CurrencyBalance{UserId: req.UserId, Currency: req.Conversion.FromCurrency}.AccountId()
+	toAccountId := entity.CurrencyBalance{UserId: req.UserId, Currency: req.Conversion.ToCurrency}.AccountId()
+	if req.Conversion.FromCurrency == user.Currency {
+		available = balances[req.UserId].Available()
+		fromAccountId = req.UserId
+	}
+	if req.Conversion.ToCurrency == user.Currency {
+		toAccountId = req.UserId
+	}
+
+	if available < req.Amount {
+		return InsufficientBalanceError{
+			UserId:  req.UserId,
+			Balance: available,
+			Amount:  req.Amount,
+		}
+	}
+
+	notes := fmt.Sprintf("Convert %s %s to %s %s at %s", req.Amount, req.Conversion.FromCurrency, req.Conversion.ToAmount, req.Conversion.ToCurrency, req.Conversion.Rate)
+
+	journalEntryId := uuid.NewString()
+	err = d.postJournalEntry(ctx, tx, hooks, entity.JournalEntry{
+		Id:          journalEntryId,
+		Type:        int(enum.FX_CONVERSION),
+		Description: notes,
+		Postings: []entity.Posting{
+			{AccountId: fromAccountId, Amount: -req.Amount},
+			{AccountId: enum.FX_ACCOUNT_PREFIX + req.Conversion.FromCurrency, Amount: req.Amount},
+			{AccountId: enum.FX_ACCOUNT_PREFIX + req.Conversion.ToCurrency, Amount: -req.Conversion.ToAmount},
+			{AccountId: toAccountId, Amount: req.Conversion.ToAmount},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	history := entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		UserId:         req.UserId,
+		TargetUserId:   req.UserId,
+		Amount:         req.Amount,
+		Currency:       req.Conversion.FromCurrency,
+		Type:           int(enum.CONVERSION),
+		Notes:          notes,
+	}
+	return d.insertHistory(ctx, tx, hooks, history, history.Summary())
+}
@@ -2,50 +2,223 @@ package domainbalance
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/app/entity"
 	"github.com/kevinsudut/wallet-system/pkg/helper/singleflight"
+	"github.com/kevinsudut/wallet-system/pkg/helper/tiercache"
+	"github.com/kevinsudut/wallet-system/pkg/helper/timezone"
 	"github.com/kevinsudut/wallet-system/pkg/lib/database"
 	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
 	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
 )
 
+// Balances, latest histories and history summaries are cached with these TTLs in every tier of their loader, only
+// histories are served stale.
+const (
+	localCacheTTL        = time.Minute * 5
+	redisCacheTTL        = time.Minute * 30
+	negativeCacheTTL     = time.Second * 30
+	staleWhileRevalidate = time.Minute
+	cacheTTLJitter       = 0.1
+)
+
 type domain struct {
 	db           database.DatabaseItf
 	redis        redis.RedisItf
 	cache        lrucache.LRUCacheItf
+	loaders      loaders
 	stmts        databaseStmts
 	singleflight singleflight.SingleFlightItf
+	location     *time.Location
+}
+
+type loaders struct {
+	balanceByUserId               *tiercache.Loader[string, entity.Balance]
+	latestHistoryByUserId         *tiercache.Loader[string, []entity.History]
+	historySummaryByUserIdAndType *tiercache.Loader[historySummaryKey, []entity.HistorySummary]
 }
 
 type databaseStmts struct {
-	getBalanceByUserId               *sqlx.Stmt
-	getLatestHistoryByUserId         *sqlx.Stmt
-	getHistorySummaryByUserIdAndType *sqlx.Stmt
-	grantBalanceByUserId             *sqlx.Stmt
-	deductBalanceByUserId            *sqlx.Stmt
-	insertHistory                    *sqlx.Stmt
-	updateHistorySummaryById         *sqlx.Stmt
+	getBalanceByUserId                 *sqlx.Stmt
+	lockBalancesByUserIds              *sqlx.Stmt
+	getLatestHistoryByUserId           *sqlx.Stmt
+	getHistoriesByUserId               *sqlx.Stmt
+	getHistorySummaryByUserIdAndType   *sqlx.Stmt
+	getTopHistoriesByUserId            *sqlx.Stmt
+	getHistoriesByJournalEntryId       *sqlx.Stmt
+	getTransferById                    *sqlx.Stmt
+	lockTransferById                   *sqlx.Stmt
+	getRefundedAmountByTransferId      *sqlx.Stmt
+	getLeaderboard                     *sqlx.Stmt
+	getWindowedLeaderboard             *sqlx.Stmt
+	grantBalanceByUserId               *sqlx.Stmt
+	deductBalanceByUserId              *sqlx.Stmt
+	holdBalanceByUserId                *sqlx.Stmt
+	releaseBalanceByUserId             *sqlx.Stmt
+	insertHold                         *sqlx.Stmt
+	getHoldById                        *sqlx.Stmt
+	lockHoldById                       *sqlx.Stmt
+	updateHoldById                     *sqlx.Stmt
+	releaseExpiredHolds                *sqlx.Stmt
+	insertHistory                      *sqlx.Stmt
+	updateHistorySummaryById           *sqlx.Stmt
+	updateHistorySummaryBuckets        *sqlx.Stmt
+	deleteHistorySummaryBuckets        *sqlx.Stmt
+	insertIdempotencyKey               *sqlx.Stmt
+	insertJournalEntry                 *sqlx.Stmt
+	insertPosting                      *sqlx.Stmt
+	getJournalEntryById                *sqlx.Stmt
+	getPostingsByJournalEntryId        *sqlx.Stmt
+	getPostingsByAccountId             *sqlx.Stmt
+	countMonthlyPostingsByAccountId    *sqlx.Stmt
+	getIdempotencyKey                  *sqlx.Stmt
+	lockUserStatusesByIds              *sqlx.Stmt
+	lockUsersByIds                     *sqlx.Stmt
+	updateUserStatusById               *sqlx.Stmt
+	insertUserStatusChange             *sqlx.Stmt
+	insertPocket                       *sqlx.Stmt
+	getPocketsByUserId                 *sqlx.Stmt
+	lockPocketsByIds                   *sqlx.Stmt
+	getPocketsAmountByUserId           *sqlx.Stmt
+	postPocketBalanceById              *sqlx.Stmt
+	postCurrencyBalance                *sqlx.Stmt
+	getCurrencyBalancesByUserId        *sqlx.Stmt
+	lockCurrencyBalancesByUserId       *sqlx.Stmt
+	insertSharedWallet                 *sqlx.Stmt
+	getSharedWalletById                *sqlx.Stmt
+	lockSharedWalletById               *sqlx.Stmt
+	postSharedWalletBalanceById        *sqlx.Stmt
+	upsertSharedWalletMember           *sqlx.Stmt
+	updateSharedWalletMemberStatus     *sqlx.Stmt
+	getSharedWalletMembersByWalletId   *sqlx.Stmt
+	lockSharedWalletMembersByWalletId  *sqlx.Stmt
+	getSharedWalletMembersByUserId     *sqlx.Stmt
+	insertSharedWalletHistory          *sqlx.Stmt
+	getSharedWalletHistoriesByWalletId *sqlx.Stmt
+	insertOutboxEvent                  *sqlx.Stmt
 }
 
-func Init(db database.DatabaseItf, redis redis.RedisItf) DomainItf {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+func Init(ctx context.Context, db database.DatabaseItf, redis redis.RedisItf, cache lrucache.LRUCacheItf) DomainItf {
+	prepareCtx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
 
-	return &domain{
-		db:    db,
-		redis: redis,
-		cache: lrucache.Init(),
+	d := &domain{
+		db:      db,
+		redis:   redis,
+		cache:   cache,
+		loaders: newLoaders(redis, cache),
 		stmts: databaseStmts{
-			getBalanceByUserId:               db.PreparexContext(ctx, queryGetBalanceByUserId),
-			getLatestHistoryByUserId:         db.PreparexContext(ctx, queryGetLatestHistoryByUserId),
-			getHistorySummaryByUserIdAndType: db.PreparexContext(ctx, queryGetHistorySummaryByUserIdAndType),
-			grantBalanceByUserId:             db.PreparexContext(ctx, queryGrantBalanceByUserId),
-			deductBalanceByUserId:            db.PreparexContext(ctx, queryDeductBalanceByUserId),
-			insertHistory:                    db.PreparexContext(ctx, queryInsertHistory),
-			updateHistorySummaryById:         db.PreparexContext(ctx, queryUpdateHistorySummaryById),
+			getBalanceByUserId:                 db.PreparexContext(prepareCtx, queryGetBalanceByUserId),
+			lockBalancesByUserIds:              db.PreparexContext(prepareCtx, queryLockBalancesByUserIds),
+			getLatestHistoryByUserId:           db.PreparexContext(prepareCtx, queryGetLatestHistoryByUserId),
+			getHistoriesByUserId:               db.PreparexContext(prepareCtx, queryGetHistoriesByUserId),
+			getHistorySummaryByUserIdAndType:   db.PreparexContext(prepareCtx, queryGetHistorySummaryByUserIdAndType),
+			getTopHistoriesByUserId:            db.PreparexContext(prepareCtx, queryGetTopHistoriesByUserId),
+			getHistoriesByJournalEntryId:       db.PreparexContext(prepareCtx, queryGetHistoriesByJournalEntryId),
+			getTransferById:                    db.PreparexContext(prepareCtx, queryGetTransferById),
+			lockTransferById:                   db.PreparexContext(prepareCtx, queryLockTransferById),
+			getRefundedAmountByTransferId:      db.PreparexContext(prepareCtx, queryGetRefundedAmountByTransferId),
+			getLeaderboard:                     db.PreparexContext(prepareCtx, queryGetLeaderboard),
+			getWindowedLeaderboard:             db.PreparexContext(prepareCtx, queryGetWindowedLeaderboard),
+			grantBalanceByUserId:               db.PreparexContext(prepareCtx, queryGrantBalanceByUserId),
+			deductBalanceByUserId:              db.PreparexContext(prepareCtx, queryDeductBalanceByUserId),
+			holdBalanceByUserId:                db.PreparexContext(prepareCtx, queryHoldBalanceByUserId),
+			releaseBalanceByUserId:             db.PreparexContext(prepareCtx, queryReleaseBalanceByUserId),
+			insertHold:                         db.PreparexContext(prepareCtx, queryInsertHold),
+			getHoldById:                        db.PreparexContext(prepareCtx, queryGetHoldById),
+			lockHoldById:                       db.PreparexContext(prepareCtx, queryLockHoldById),
+			updateHoldById:                     db.PreparexContext(prepareCtx, queryUpdateHoldById),
+			releaseExpiredHolds:                db.PreparexContext(prepareCtx, queryReleaseExpiredHolds),
+			insertHistory:                      db.PreparexContext(prepareCtx, queryInsertHistory),
+			updateHistorySummaryById:           db.PreparexContext(prepareCtx, queryUpdateHistorySummaryById),
+			updateHistorySummaryBuckets:        db.PreparexContext(prepareCtx, queryUpdateHistorySummaryBuckets),
+			deleteHistorySummaryBuckets:        db.PreparexContext(prepareCtx, queryDeleteHistorySummaryBucketsBefore),
+			insertIdempotencyKey:               db.PreparexContext(prepareCtx, queryInsertIdempotencyKey),
+			insertJournalEntry:                 db.PreparexContext(prepareCtx, queryInsertJournalEntry),
+			insertPosting:                      db.PreparexContext(prepareCtx, queryInsertPosting),
+			getJournalEntryById:                db.PreparexContext(prepareCtx, queryGetJournalEntryById),
+			getPostingsByJournalEntryId:        db.PreparexContext(prepareCtx, queryGetPostingsByJournalEntryId),
+			getPostingsByAccountId:             db.PreparexContext(prepareCtx, queryGetPostingsByAccountId),
+			countMonthlyPostingsByAccountId:    db.PreparexContext(prepareCtx, queryCountMonthlyPostingsByAccountId),
+			getIdempotencyKey:                  db.PreparexContext(prepareCtx, queryGetIdempotencyKey),
+			lockUserStatusesByIds:              db.PreparexContext(prepareCtx, queryLockUserStatusesByIds),
+			lockUsersByIds:                     db.PreparexContext(prepareCtx, queryLockUsersByIds),
+			updateUserStatusById:               db.PreparexContext(prepareCtx, queryUpdateUserStatusById),
+			insertUserStatusChange:             db.PreparexContext(prepareCtx, queryInsertUserStatusChange),
+			insertPocket:                       db.PreparexContext(prepareCtx, queryInsertPocket),
+			getPocketsByUserId:                 db.PreparexContext(prepareCtx, queryGetPocketsByUserId),
+			lockPocketsByIds:                   db.PreparexContext(prepareCtx, queryLockPocketsByIds),
+			getPocketsAmountByUserId:           db.PreparexContext(prepareCtx, queryGetPocketsAmountByUserId),
+			postPocketBalanceById:              db.PreparexContext(prepareCtx, queryPostPocketBalanceById),
+			postCurrencyBalance:                db.PreparexContext(prepareCtx, queryPostCurrencyBalance),
+			getCurrencyBalancesByUserId:        db.PreparexContext(prepareCtx, queryGetCurrencyBalancesByUserId),
+			lockCurrencyBalancesByUserId:       db.PreparexContext(prepareCtx, queryLockCurrencyBalancesByUserId),
+			insertSharedWallet:                 db.PreparexContext(prepareCtx, queryInsertSharedWallet),
+			getSharedWalletById:                db.PreparexContext(prepareCtx, queryGetSharedWalletById),
+			lockSharedWalletById:               db.PreparexContext(prepareCtx, queryLockSharedWalletById),
+			postSharedWalletBalanceById:        db.PreparexContext(prepareCtx, queryPostSharedWalletBalanceById),
+			upsertSharedWalletMember:           db.PreparexContext(prepareCtx, queryUpsertSharedWalletMember),
+			updateSharedWalletMemberStatus:     db.PreparexContext(prepareCtx, queryUpdateSharedWalletMemberStatus),
+			getSharedWalletMembersByWalletId:   db.PreparexContext(prepareCtx, queryGetSharedWalletMembersByWalletId),
+			lockSharedWalletMembersByWalletId:  db.PreparexContext(prepareCtx, queryLockSharedWalletMembersByWalletId),
+			getSharedWalletMembersByUserId:     db.PreparexContext(prepareCtx, queryGetSharedWalletMembersByUserId),
+			insertSharedWalletHistory:          db.PreparexContext(prepareCtx, queryInsertSharedWalletHistory),
+			getSharedWalletHistoriesByWalletId: db.PreparexContext(prepareCtx, queryGetSharedWalletHistoriesByWalletId),
+			insertOutboxEvent:                  db.PreparexContext(prepareCtx, queryInsertOutboxEvent),
 		},
 		singleflight: singleflight.Init(),
+		location:     timezone.Business(),
+	}
+
+	go d.runHistorySummaryBucketCompaction(ctx)
+	go d.runExpiredHoldRelease(ctx)
+
+	return d
+}
+
+func newLoaders(redis redis.RedisItf, cache lrucache.LRUCacheItf) loaders {
+	return loaders{
+		balanceByUserId: tiercache.New[string, entity.Balance](tiercache.Config[string]{
+			Name: "balance_by_user_id",
+			Key: func(userId string) string {
+				return fmt.Sprintf(cacheKeyGetBalanceByUserId, userId)
+			},
+			Local:       cache,
+			LocalTTL:    localCacheTTL,
+			Redis:       redis,
+			RedisTTL:    redisCacheTTL,
+			Jitter:      cacheTTLJitter,
+			NotFound:    sql.ErrNoRows,
+			NegativeTTL: negativeCacheTTL,
+			// Balances are never served stale, a background refresh could bring back one a transfer just changed.
+		}),
+		latestHistoryByUserId: tiercache.New[string, []entity.History](tiercache.Config[string]{
+			Name: "latest_history_by_user_id",
+			Key: func(userId string) string {
+				return fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, userId)
+			},
+			Local:                cache,
+			LocalTTL:             localCacheTTL,
+			Redis:                redis,
+			RedisTTL:             redisCacheTTL,
+			Jitter:               cacheTTLJitter,
+			StaleWhileRevalidate: staleWhileRevalidate,
+		}),
+		historySummaryByUserIdAndType: tiercache.New[historySummaryKey, []entity.HistorySummary](tiercache.Config[historySummaryKey]{
+			Name: "history_summary_by_user_id_and_type",
+			Key: func(key historySummaryKey) string {
+				return fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, key.UserId, key.Type)
+			},
+			Local:                cache,
+			LocalTTL:             localCacheTTL,
+			Redis:                redis,
+			RedisTTL:             redisCacheTTL,
+			Jitter:               cacheTTLJitter,
+			StaleWhileRevalidate: staleWhileRevalidate,
+		}),
 	}
 }
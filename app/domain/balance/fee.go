@@ -0,0 +1,86 @@
+package domainbalance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// feePostingSigns is the sign of the posting on the account of the user of every operation that can be charged a
+// fee, which tells the operations of the user apart from the ones it received.
+var feePostingSigns = map[enum.JournalEntryType]int{
+	enum.TOPUP:    1,
+	enum.TRANSFER: -1,
+}
+
+// GetFee prices an operation without running it. The monthly free quota is read outside of a transaction, so the
+// operation itself may still be charged when a concurrent one uses up the quota first.
+func (d domain) GetFee(ctx context.Context, req GetFeeRequest) (resp money.Money, err error) {
+	return d.getFee(ctx, nil, req)
+}
+
+// getFee returns the fee of an operation, counting the monthly free quota of the rule within tx when it is set.
+func (d domain) getFee(ctx context.Context, tx *sql.Tx, req GetFeeRequest) (resp money.Money, err error) {
+	if req.FeeRule.IsZero() {
+		return 0, nil
+	}
+
+	var count int64
+	if req.FeeRule.FreePerMonth > 0 {
+		args := []interface{}{req.UserId, int(req.Operation), feePostingSigns[req.Operation], d.location.String()}
+		if tx != nil {
+			err = d.db.GetContextStmtTx(ctx, tx, d.stmts.countMonthlyPostingsByAccountId, &count, args...)
+		} else {
+			err = d.db.GetContextStmt(ctx, d.stmts.countMonthlyPostingsByAccountId, &count, args...)
+		}
+		if err != nil {
+			return resp, err
+		}
+	}
+
+	return req.FeeRule.Fee(req.Amount, count), nil
+}
+
+// postFee moves fee from the user to the revenue account under its own journal entry, with a history that
+// references the journal entry of the operation it is charged on.
+func (d domain) postFee(ctx context.Context, tx *sql.Tx, hooks *txHooks, userId string, referenceId string, fee money.Money, notes string) (err error) {
+	if fee <= 0 {
+		return nil
+	}
+
+	journalEntryId := uuid.NewString()
+	err = d.postJournalEntry(ctx, tx, hooks, entity.JournalEntry{
+		Id:          journalEntryId,
+		Type:        int(enum.FEE_CHARGE),
+		Description: fmt.Sprintf("Fee of %s charged to %s", referenceId, userId),
+		Postings: []entity.Posting{
+			{AccountId: userId, Amount: -fee},
+			{AccountId: enum.ACCOUNT_REVENUE, Amount: fee},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	history := entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		UserId:         userId,
+		TargetUserId:   userId,
+		Amount:         fee,
+		Type:           int(enum.FEE),
+		Notes:          notes,
+		ReferenceId:    referenceId,
+	}
+	err = d.insertHistory(ctx, tx, hooks, history, history.Summary())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package domainbalance
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/timezone"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	historySummaryBucketCompactionInterval = time.Hour
+	historySummaryBucketCompactionTimeout  = time.Minute
+
+	// Day buckets serve rolling windows of up to 30 days, the few extra days cover clock and time zone skew.
+	historySummaryDayBucketRetention = 35
+)
+
+// runHistorySummaryBucketCompaction compacts the history summary buckets on start and then every interval, until
+// ctx is done. Deleting is idempotent, so every instance runs it without coordinating with the others.
+func (d domain) runHistorySummaryBucketCompaction(ctx context.Context) {
+	ticker := time.NewTicker(historySummaryBucketCompactionInterval)
+	defer ticker.Stop()
+
+	for {
+		compactCtx, cancel := context.WithTimeout(ctx, historySummaryBucketCompactionTimeout)
+		err := d.compactHistorySummaryBuckets(compactCtx, time.Now())
+		cancel()
+		if err != nil {
+			log.Errorln("runHistorySummaryBucketCompaction.compactHistorySummaryBuckets", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// compactHistorySummaryBuckets drops the day buckets past their retention. Their amounts are already part of
+// the week and month buckets written with them, which are kept.
+func (d domain) compactHistorySummaryBuckets(ctx context.Context, now time.Time) (err error) {
+	before := timezone.StartOfDay(now, d.location).AddDate(0, 0, -historySummaryDayBucketRetention)
+
+	err = d.db.ExecContextStmt(ctx, d.stmts.deleteHistorySummaryBuckets, enum.SUMMARY_PERIOD_DAY, before.Format(time.DateOnly))
+	if err == database.ErrNoRowsAffected {
+		return nil
+	}
+
+	return err
+}
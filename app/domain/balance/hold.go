@@ -0,0 +1,324 @@
+package domainbalance
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	expiredHoldReleaseInterval = time.Minute
+	expiredHoldReleaseTimeout  = time.Minute
+	expiredHoldReleaseBatch    = 100
+)
+
+// GetHoldById reads a hold straight from Postgres. Like GetTransferById it is only read ahead of a capture
+// or void, which check the hold again once it is locked.
+func (d domain) GetHoldById(ctx context.Context, id string) (resp entity.Hold, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getHoldById, &resp, id)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) lockHoldById(ctx context.Context, tx *sql.Tx, id string) (resp entity.Hold, err error) {
+	err = d.db.GetContextStmtTx(ctx, tx, d.stmts.lockHoldById, &resp, id)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) holdBalanceByUserId(ctx context.Context, tx *sql.Tx, hooks *txHooks, balance entity.Balance) (err error) {
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.holdBalanceByUserId, balance.Amount, balance.UserId)
+	if err != nil {
+		return err
+	}
+
+	d.invalidateBalanceOnCommit(hooks, balance.UserId)
+
+	return nil
+}
+
+func (d domain) releaseBalanceByUserId(ctx context.Context, tx *sql.Tx, hooks *txHooks, balance entity.Balance) (err error) {
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.releaseBalanceByUserId, balance.Amount, balance.UserId)
+	if err != nil {
+		return err
+	}
+
+	d.invalidateBalanceOnCommit(hooks, balance.UserId)
+
+	return nil
+}
+
+func (d domain) updateHoldById(ctx context.Context, tx *sql.Tx, hold entity.Hold) (err error) {
+	var transferId interface{}
+	if hold.TransferId != "" {
+		transferId = hold.TransferId
+	}
+
+	return d.db.ExecContextStmtTx(ctx, tx, d.stmts.updateHoldById, hold.Status, hold.CapturedAmount, transferId, hold.Id)
+}
+
+func (d domain) CreateHold(ctx context.Context, req CreateHoldRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.createHold(ctx, req)
+	})
+}
+
+// createHold reserves the amount of the hold on the available balance of its user.
+func (d domain) createHold(ctx context.Context, req CreateHoldRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	err = d.insertIdempotencyKey(ctx, tx, req.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	hold := req.Hold
+
+	err = d.lockUserStatuses(ctx, tx, hold.UserId, hold.UserId)
+	if err != nil {
+		return err
+	}
+
+	balances, err := d.lockBalancesByUserIds(ctx, tx, hold.UserId, hold.UserId)
+	if err != nil {
+		return err
+	}
+
+	if balance := balances[hold.UserId]; balance.Available() < hold.Amount {
+		return InsufficientBalanceError{
+			UserId:  hold.UserId,
+			Balance: balance.Available(),
+			Amount:  hold.Amount,
+		}
+	}
+
+	err = d.holdBalanceByUserId(ctx, tx, hooks, entity.Balance{
+		UserId: hold.UserId,
+		Amount: hold.Amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertHold, hold.Id, hold.UserId, hold.TargetUserId, hold.Amount, int(enum.HOLD_STATUS_ACTIVE), hold.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d domain) CaptureHold(ctx context.Context, req CaptureHoldRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.captureHold(ctx, req)
+	})
+}
+
+// captureHold releases the whole hold and transfers the captured amount in the same transaction, so the rest of
+// a partial capture becomes available again.
+func (d domain) captureHold(ctx context.Context, req CaptureHoldRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	var hold entity.Hold
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	hold, err = d.lockAndCheckHold(ctx, tx, req.HoldId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	if !hold.IsActive(time.Now()) {
+		return ErrHoldNotActive
+	}
+
+	if req.Amount <= 0 || req.Amount > hold.Amount {
+		return CaptureExceedsHoldError{
+			HoldId: hold.Id,
+			Held:   hold.Amount,
+			Amount: req.Amount,
+		}
+	}
+
+	err = d.lockUserStatuses(ctx, tx, hold.UserId, hold.TargetUserId)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.lockBalancesByUserIds(ctx, tx, hold.UserId, hold.TargetUserId)
+	if err != nil {
+		return err
+	}
+
+	err = d.releaseBalanceByUserId(ctx, tx, hooks, entity.Balance{
+		UserId: hold.UserId,
+		Amount: hold.Amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only the held funds were reserved, so captures are not charged a fee.
+	err = d.postTransfer(ctx, tx, hooks, req.TransferId, hold.UserId, hold.TargetUserId, req.Amount, 0)
+	if err != nil {
+		return err
+	}
+
+	hold.Status = int(enum.HOLD_STATUS_CAPTURED)
+	hold.CapturedAmount = req.Amount
+	hold.TransferId = req.TransferId
+
+	return d.updateHoldById(ctx, tx, hold)
+}
+
+func (d domain) VoidHold(ctx context.Context, req VoidHoldRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.voidHold(ctx, req)
+	})
+}
+
+// voidHold releases a hold without moving money. A hold past its expiry that was not released yet can still
+// be voided.
+func (d domain) voidHold(ctx context.Context, req VoidHoldRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	hold, err := d.lockAndCheckHold(ctx, tx, req.HoldId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	if hold.Status != int(enum.HOLD_STATUS_ACTIVE) {
+		return ErrHoldNotActive
+	}
+
+	err = d.releaseBalanceByUserId(ctx, tx, hooks, entity.Balance{
+		UserId: hold.UserId,
+		Amount: hold.Amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	hold.Status = int(enum.HOLD_STATUS_VOIDED)
+
+	return d.updateHoldById(ctx, tx, hold)
+}
+
+// lockAndCheckHold locks the hold for userId. Only the target of a hold can capture or void it, anyone else
+// is told the hold does not exist.
+func (d domain) lockAndCheckHold(ctx context.Context, tx *sql.Tx, holdId string, userId string) (resp entity.Hold, err error) {
+	resp, err = d.lockHoldById(ctx, tx, holdId)
+	if err == sql.ErrNoRows {
+		return resp, ErrHoldNotFound
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.TargetUserId != userId {
+		return resp, ErrHoldNotFound
+	}
+
+	return resp, nil
+}
+
+// runExpiredHoldRelease releases expired holds on start and then every interval, until ctx is done. Holds are
+// claimed with SKIP LOCKED, so every instance runs it without coordinating with the others.
+func (d domain) runExpiredHoldRelease(ctx context.Context) {
+	ticker := time.NewTicker(expiredHoldReleaseInterval)
+	defer ticker.Stop()
+
+	for {
+		releaseCtx, cancel := context.WithTimeout(ctx, expiredHoldReleaseTimeout)
+		err := d.releaseExpiredHolds(releaseCtx, time.Now())
+		cancel()
+		if err != nil {
+			log.Errorln("runExpiredHoldRelease.releaseExpiredHolds", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// releaseExpiredHolds releases the holds expired at now batch by batch, until a batch releases nothing.
+func (d domain) releaseExpiredHolds(ctx context.Context, now time.Time) (err error) {
+	for {
+		var userIds []string
+		err = d.db.SelectContextStmt(ctx, d.stmts.releaseExpiredHolds, &userIds, int(enum.HOLD_STATUS_EXPIRED), int(enum.HOLD_STATUS_ACTIVE), now, expiredHoldReleaseBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, userId := range userIds {
+			err = d.loaders.balanceByUserId.Invalidate(ctx, userId)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Rows are returned per user rather than per hold, so only an empty batch means nothing is left.
+		if len(userIds) == 0 {
+			return nil
+		}
+	}
+}
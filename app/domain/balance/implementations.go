@@ -2,225 +2,490 @@ package domainbalance
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/kevinsudut/wallet-system/app/entity"
 	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
 )
 
 func (d domain) GetBalanceByUserId(ctx context.Context, userId string) (resp entity.Balance, err error) {
-	defer func() {
-		if err == nil && resp.UserId == "" {
-			err = sql.ErrNoRows
-		}
-	}()
-
-	balance, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetBalanceByUserId, userId), func() (interface{}, error) {
-		var resp entity.Balance
-		balance, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetBalanceByUserId, userId), time.Minute*5, func() (interface{}, error) {
-			var respRedis entity.Balance
-			balanceStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetBalanceByUserId, userId), time.Duration(time.Minute*30), func() (interface{}, error) {
-				var balance entity.Balance
-				err := d.db.GetContextStmt(ctx, d.stmts.getBalanceByUserId, &balance, userId)
-				if err != nil && err != sql.ErrNoRows {
-					return balance, err
-				}
-
-				return balance, nil
-			})
-			if err != nil {
-				return respRedis, err
-			}
-
-			err = jsoniter.UnmarshalFromString(balanceStr, &respRedis)
-			if err != nil {
-				return respRedis, err
-			}
-
-			return respRedis, nil
-		})
+	return d.loaders.balanceByUserId.Load(ctx, userId, func(ctx context.Context) (entity.Balance, error) {
+		var balance entity.Balance
+		err := d.db.GetContextStmt(ctx, d.stmts.getBalanceByUserId, &balance, userId)
 		if err != nil {
-			return resp, err
+			return balance, err
 		}
 
-		return balance.Value().(entity.Balance), nil
+		return balance, nil
 	})
+}
+
+// lockBalancesByUserIds locks the balance rows of both users with SELECT ... FOR UPDATE. Rows are
+// always locked in user id order so two opposite transfers cannot deadlock on each other.
+func (d domain) lockBalancesByUserIds(ctx context.Context, tx *sql.Tx, userId string, otherUserId string) (resp map[string]entity.Balance, err error) {
+	var balances []entity.Balance
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.lockBalancesByUserIds, &balances, userId, otherUserId)
 	if err != nil {
 		return resp, err
 	}
 
-	return balance.(entity.Balance), nil
+	resp = make(map[string]entity.Balance, len(balances))
+	for _, balance := range balances {
+		resp[balance.UserId] = balance
+	}
+
+	return resp, nil
 }
 
-func (d domain) grantBalanceByUserId(ctx context.Context, tx *sql.Tx, balance entity.Balance) (err error) {
+func (d domain) grantBalanceByUserId(ctx context.Context, tx *sql.Tx, hooks *txHooks, balance entity.Balance) (err error) {
 	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.grantBalanceByUserId, balance.UserId, balance.Amount)
 	if err != nil {
 		return err
 	}
 
-	_, err = d.redis.Delete(ctx, fmt.Sprintf(cacheKeyGetBalanceByUserId, balance.UserId))
+	d.invalidateBalanceOnCommit(hooks, balance.UserId)
+
+	return nil
+}
+
+func (d domain) deductBalanceByUserId(ctx context.Context, tx *sql.Tx, hooks *txHooks, balance entity.Balance) (err error) {
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.deductBalanceByUserId, balance.Amount, balance.UserId)
 	if err != nil {
 		return err
 	}
 
-	d.cache.Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, balance.UserId))
+	d.invalidateBalanceOnCommit(hooks, balance.UserId)
 
 	return nil
 }
 
-func (d domain) deductBalanceByUserId(ctx context.Context, tx *sql.Tx, balance entity.Balance) (err error) {
-	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.deductBalanceByUserId, balance.Amount, balance.UserId)
+// invalidateBalanceOnCommit drops the cached balance of userId once the transaction that changes it commits. Before
+// that, a reader on this or another instance could cache the old balance again.
+func (d domain) invalidateBalanceOnCommit(hooks *txHooks, userId string) {
+	hooks.onCommit(func(ctx context.Context) error {
+		return d.loaders.balanceByUserId.Invalidate(ctx, userId)
+	})
+}
+
+// insertHistory writes a history and applies summary to history_summaries, which is the history itself except
+// for refunds that reverse the transfer they refund.
+func (d domain) insertHistory(ctx context.Context, tx *sql.Tx, hooks *txHooks, history entity.History, summary entity.HistorySummary) (err error) {
+	var referenceId interface{}
+	if history.ReferenceId != "" {
+		referenceId = history.ReferenceId
+	}
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertHistory, history.Id, history.JournalEntryId, history.UserId, history.TargetUserId, history.Amount, history.Type, history.Notes, referenceId, history.Fee, history.Currency)
 	if err != nil {
 		return err
 	}
 
-	_, err = d.redis.Delete(ctx, fmt.Sprintf(cacheKeyGetBalanceByUserId, balance.UserId))
+	err = d.updateHistorySummary(ctx, tx, hooks, summary)
 	if err != nil {
 		return err
 	}
 
-	d.cache.Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, balance.UserId))
+	// Paginated histories are cached per filter, so they are invalidated by moving every page of the user to a new
+	// version. Like the latest histories, it moves after commit, or a reader could cache the old page under the new
+	// version.
+	hooks.onCommit(func(ctx context.Context) error {
+		return d.loaders.latestHistoryByUserId.Invalidate(ctx, history.UserId)
+	})
+	hooks.onCommit(func(ctx context.Context) error {
+		_, err := d.redis.Incr(ctx, fmt.Sprintf(cacheKeyHistoryVersionByUserId, history.UserId))
+		return err
+	})
 
 	return nil
 }
 
-func (d domain) insertHistory(ctx context.Context, tx *sql.Tx, history entity.History) (err error) {
-	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertHistory, history.Id, history.UserId, history.TargetUserId, history.Amount, history.Type, history.Notes)
-	if err != nil {
-		return err
+// postJournalEntry writes a balanced journal entry with its postings and projects every posting on a user
+// account onto that user's balance, and every posting on a pocket, shared wallet or currency account onto its
+// row. System accounts only live in the ledger. Postings on a balance of a user are also written to the outbox.
+func (d domain) postJournalEntry(ctx context.Context, tx *sql.Tx, hooks *txHooks, journalEntry entity.JournalEntry) (err error) {
+	if !journalEntry.IsBalanced() {
+		return ErrUnbalancedJournalEntry
 	}
 
-	err = d.updateHistorySummary(ctx, tx, entity.HistorySummary{
-		UserId:       history.UserId,
-		TargetUserId: history.TargetUserId,
-		Amount:       history.Amount,
-		Type:         history.Type,
-	})
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertJournalEntry, journalEntry.Id, journalEntry.Type, journalEntry.Description)
 	if err != nil {
 		return err
 	}
 
-	_, err = d.redis.Delete(ctx, fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, history.UserId))
-	if err != nil {
-		return err
-	}
+	for _, posting := range journalEntry.Postings {
+		err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertPosting, uuid.NewString(), journalEntry.Id, posting.AccountId, posting.Amount)
+		if err != nil {
+			return err
+		}
 
-	d.cache.Delete(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, history.UserId))
+		if posting.IsSystemAccount() {
+			continue
+		}
+
+		if posting.IsPocketAccount() {
+			err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.postPocketBalanceById, posting.Amount, posting.PocketId())
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if posting.IsSharedWalletAccount() {
+			err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.postSharedWalletBalanceById, posting.Amount, posting.SharedWalletId())
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if posting.IsCurrencyAccount() {
+			currencyBalance := posting.CurrencyBalance()
+			err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.postCurrencyBalance, currencyBalance.UserId, currencyBalance.Currency, posting.Amount)
+			if err != nil {
+				return err
+			}
+
+			err = d.insertBalanceChangedEvent(ctx, tx, journalEntry, currencyBalance.UserId, posting.Amount, currencyBalance.Currency)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if posting.Amount < 0 {
+			err = d.deductBalanceByUserId(ctx, tx, hooks, entity.Balance{
+				UserId: posting.AccountId,
+				Amount: -posting.Amount,
+			})
+		} else {
+			err = d.grantBalanceByUserId(ctx, tx, hooks, entity.Balance{
+				UserId: posting.AccountId,
+				Amount: posting.Amount,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		err = d.insertBalanceChangedEvent(ctx, tx, journalEntry, posting.AccountId, posting.Amount, "")
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (d domain) updateHistorySummary(ctx context.Context, tx *sql.Tx, historySummary entity.HistorySummary) (err error) {
+func (d domain) updateHistorySummary(ctx context.Context, tx *sql.Tx, hooks *txHooks, historySummary entity.HistorySummary) (err error) {
 	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.updateHistorySummaryById, historySummary.GetId(), historySummary.UserId, historySummary.TargetUserId, historySummary.Amount, historySummary.Type)
 	if err != nil {
 		return err
 	}
 
-	_, err = d.redis.Delete(ctx, fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, historySummary.UserId, historySummary.Type))
+	var at interface{}
+	if !historySummary.At.IsZero() {
+		at = historySummary.At
+	}
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.updateHistorySummaryBuckets, historySummary.UserId, historySummary.TargetUserId, historySummary.Type, historySummary.Amount, d.location.String(), at)
 	if err != nil {
 		return err
 	}
 
-	d.cache.Delete(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, historySummary.UserId, historySummary.Type))
+	hooks.onCommit(func(ctx context.Context) error {
+		return d.loaders.historySummaryByUserIdAndType.Invalidate(ctx, historySummaryKey{
+			UserId: historySummary.UserId,
+			Type:   historySummary.Type,
+		})
+	})
+	hooks.onCommit(func(ctx context.Context) error {
+		d.updateLeaderboard(ctx, historySummary)
+		return nil
+	})
 
 	return nil
 }
 
-func (d domain) GrantBalanceByUserId(ctx context.Context, balance entity.Balance) (err error) {
+func (d domain) insertIdempotencyKey(ctx context.Context, tx *sql.Tx, idempotencyKey entity.IdempotencyKey) (err error) {
+	if idempotencyKey.Key == "" {
+		return nil
+	}
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertIdempotencyKey, idempotencyKey.UserId, idempotencyKey.Key, idempotencyKey.Fingerprint, idempotencyKey.ResponseCode, idempotencyKey.ResponseBody)
+	if database.IsUniqueViolation(err) {
+		return ErrIdempotencyKeyExists
+	}
+
+	return err
+}
+
+func (d domain) GrantBalanceByUserId(ctx context.Context, req GrantBalanceByUserIdRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.grantBalance(ctx, req)
+	})
+}
+
+func (d domain) grantBalance(ctx context.Context, req GrantBalanceByUserIdRequest) (err error) {
 	tx, err := d.db.Begin()
 	if err != nil {
 		return err
 	}
 
+	hooks := &txHooks{}
 	defer func() {
 		if err == nil {
-			d.db.Commit(tx)
+			err = d.db.Commit(tx)
 		} else {
 			d.db.Rollback(tx)
 		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
 	}()
 
-	err = d.grantBalanceByUserId(ctx, tx, balance)
+	err = d.insertIdempotencyKey(ctx, tx, req.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	// A top-up only credits the user, the funding account has no status.
+	err = d.lockUserStatuses(ctx, tx, "", req.UserId)
 	if err != nil {
 		return err
 	}
 
-	err = d.insertHistory(ctx, tx, entity.History{
-		Id:           uuid.NewString(),
-		UserId:       balance.UserId,
-		TargetUserId: balance.UserId,
-		Amount:       balance.Amount,
-		Type:         int(enum.CREDIT),
-		Notes:        "Top-up money",
+	fee, err := d.getFee(ctx, tx, GetFeeRequest{
+		UserId:    req.UserId,
+		Operation: enum.TOPUP,
+		Amount:    req.Amount,
+		FeeRule:   req.FeeRule,
 	})
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// The fee is taken from the top-up, so it has to leave something to top up.
+	if fee >= req.Amount {
+		return ErrFeeExceedsAmount
+	}
+
+	journalEntryId := uuid.NewString()
+
+	err = d.postJournalEntry(ctx, tx, hooks, entity.JournalEntry{
+		Id:          journalEntryId,
+		Type:        int(enum.TOPUP),
+		Description: "Top-up money",
+		Postings: []entity.Posting{
+			{AccountId: enum.ACCOUNT_FUNDING, Amount: -req.Amount},
+			{AccountId: req.UserId, Amount: req.Amount},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	history := entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		UserId:         req.UserId,
+		TargetUserId:   req.UserId,
+		Amount:         req.Amount,
+		Fee:            fee,
+		Type:           int(enum.CREDIT),
+		Notes:          "Top-up money",
+	}
+	err = d.insertHistory(ctx, tx, hooks, history, history.Summary())
+	if err != nil {
+		return err
+	}
+
+	return d.postFee(ctx, tx, hooks, req.UserId, journalEntryId, fee, "Top-up fee")
 }
 
 func (d domain) DisburmentBalance(ctx context.Context, req DisburmentBalanceRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.disburmentBalance(ctx, req)
+	})
+}
+
+func (d domain) disburmentBalance(ctx context.Context, req DisburmentBalanceRequest) (err error) {
 	tx, err := d.db.Begin()
 	if err != nil {
 		return err
 	}
 
+	hooks := &txHooks{}
 	defer func() {
 		if err == nil {
-			d.db.Commit(tx)
+			err = d.db.Commit(tx)
 		} else {
 			d.db.Rollback(tx)
 		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
 	}()
 
-	err = d.grantBalanceByUserId(ctx, tx, entity.Balance{
-		UserId: req.ToUserId,
-		Amount: req.Amount,
-	})
+	err = d.insertIdempotencyKey(ctx, tx, req.IdempotencyKey)
 	if err != nil {
 		return err
 	}
 
-	err = d.deductBalanceByUserId(ctx, tx, entity.Balance{
-		UserId: req.UserId,
-		Amount: req.Amount,
-	})
+	users, err := d.lockUsers(ctx, tx, req.UserId, req.ToUserId)
 	if err != nil {
 		return err
 	}
 
-	err = d.insertHistory(ctx, tx, entity.History{
-		Id:           uuid.NewString(),
-		UserId:       req.ToUserId,
-		TargetUserId: req.UserId,
-		Amount:       req.Amount,
-		Type:         int(enum.CREDIT),
-		Notes:        fmt.Sprintf("Receive money from %s", req.UserId),
-	})
+	err = checkConversion(users[req.UserId], users[req.ToUserId], req.Conversion)
 	if err != nil {
 		return err
 	}
 
-	err = d.insertHistory(ctx, tx, entity.History{
-		Id:           uuid.NewString(),
-		UserId:       req.UserId,
-		TargetUserId: req.ToUserId,
-		Amount:       req.Amount,
-		Type:         int(enum.DEBIT),
-		Notes:        fmt.Sprintf("Transfer money to %s", req.ToUserId),
+	balances, err := d.lockBalancesByUserIds(ctx, tx, req.UserId, req.ToUserId)
+	if err != nil {
+		return err
+	}
+
+	// The free quota is counted after the balance of the sender is locked, so concurrent transfers cannot both
+	// take its last free transfer.
+	fee, err := d.getFee(ctx, tx, GetFeeRequest{
+		UserId:    req.UserId,
+		Operation: enum.TRANSFER,
+		Amount:    req.Amount,
+		FeeRule:   req.FeeRule,
 	})
 	if err != nil {
 		return err
 	}
 
+	// Funds reserved by holds cannot be transferred.
+	if balance := balances[req.UserId]; balance.Available() < req.Amount+fee {
+		return InsufficientBalanceError{
+			UserId:  req.UserId,
+			Balance: balance.Available(),
+			Amount:  req.Amount,
+			Fee:     fee,
+		}
+	}
+
+	journalEntryId := uuid.NewString()
+
+	return d.postConvertedTransfer(ctx, tx, hooks, journalEntryId, req.UserId, req.ToUserId, req.Amount, fee, req.Conversion)
+}
+
+// checkConversion checks that conversion, when there is one, converts from the main currency of user to the one
+// of toUser, which may have been read before they were locked. Without a conversion both have to be the same.
+func checkConversion(user entity.User, toUser entity.User, conversion Conversion) error {
+	if conversion.IsZero() {
+		return sameCurrency(user, toUser)
+	}
+
+	if (user.Id != "" && user.Currency != conversion.FromCurrency) || (toUser.Id != "" && toUser.Currency != conversion.ToCurrency) {
+		return CurrencyMismatchError{
+			Currency:   user.Currency,
+			ToCurrency: toUser.Currency,
+		}
+	}
+
 	return nil
 }
 
+// postTransfer moves amount between two users whose balances are already locked by tx, and writes the history
+// of both legs under the journal entry of the transfer. The fee is charged to the sender on top of amount.
+func (d domain) postTransfer(ctx context.Context, tx *sql.Tx, hooks *txHooks, journalEntryId string, fromUserId string, toUserId string, amount money.Money, fee money.Money) (err error) {
+	return d.postConvertedTransfer(ctx, tx, hooks, journalEntryId, fromUserId, toUserId, amount, fee, Conversion{})
+}
+
+// postConvertedTransfer is postTransfer between users of different currencies. The money goes through the FX
+// account of each currency, so the postings of every currency sum to zero on their own.
+func (d domain) postConvertedTransfer(ctx context.Context, tx *sql.Tx, hooks *txHooks, journalEntryId string, fromUserId string, toUserId string, amount money.Money, fee money.Money, conversion Conversion) (err error) {
+	postings := []entity.Posting{
+		{AccountId: fromUserId, Amount: -amount},
+		{AccountId: toUserId, Amount: amount},
+	}
+	toAmount := amount
+	if !conversion.IsZero() {
+		toAmount = conversion.ToAmount
+		postings = []entity.Posting{
+			{AccountId: fromUserId, Amount: -amount},
+			{AccountId: enum.FX_ACCOUNT_PREFIX + conversion.FromCurrency, Amount: amount},
+			{AccountId: enum.FX_ACCOUNT_PREFIX + conversion.ToCurrency, Amount: -toAmount},
+			{AccountId: toUserId, Amount: toAmount},
+		}
+	}
+
+	err = d.postJournalEntry(ctx, tx, hooks, entity.JournalEntry{
+		Id:          journalEntryId,
+		Type:        int(enum.TRANSFER),
+		Description: fmt.Sprintf("Transfer money from %s to %s", fromUserId, toUserId),
+		Postings:    postings,
+	})
+	if err != nil {
+		return err
+	}
+
+	creditHistory := entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		UserId:         toUserId,
+		TargetUserId:   fromUserId,
+		Amount:         toAmount,
+		Type:           int(enum.CREDIT),
+		Notes:          fmt.Sprintf("Receive money from %s", fromUserId),
+	}
+	err = d.insertHistory(ctx, tx, hooks, creditHistory, creditHistory.Summary())
+	if err != nil {
+		return err
+	}
+
+	debitHistory := entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		UserId:         fromUserId,
+		TargetUserId:   toUserId,
+		Amount:         amount,
+		Fee:            fee,
+		Type:           int(enum.DEBIT),
+		Notes:          fmt.Sprintf("Transfer money to %s", toUserId),
+	}
+	err = d.insertHistory(ctx, tx, hooks, debitHistory, debitHistory.Summary())
+	if err != nil {
+		return err
+	}
+
+	err = d.postFee(ctx, tx, hooks, fromUserId, journalEntryId, fee, fmt.Sprintf("Fee of transfer to %s", toUserId))
+	if err != nil {
+		return err
+	}
+
+	// The event belongs to the sender, whose balance row is locked, so it is ordered with the other events of the
+	// sender. A transfer without a conversion leaves both currencies to the outbox query, which fills in the main
+	// currency of the sender.
+	return d.insertOutboxEvent(ctx, tx, fromUserId, enum.EVENT_TRANSFER_COMPLETED, entity.TransferCompletedEvent{
+		TransferId: journalEntryId,
+		FromUserId: fromUserId,
+		ToUserId:   toUserId,
+		Amount:     amount,
+		Fee:        fee,
+		Currency:   conversion.FromCurrency,
+		ToAmount:   toAmount,
+		ToCurrency: conversion.ToCurrency,
+	})
+}
+
 func (d domain) GetLatestHistoryByUserId(ctx context.Context, userId string) (resp []entity.History, err error) {
 	defer func() {
 		if err == nil {
@@ -230,13 +495,43 @@ func (d domain) GetLatestHistoryByUserId(ctx context.Context, userId string) (re
 		}
 	}()
 
-	histories, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetLatestHistoryByUserId, userId), func() (interface{}, error) {
+	return d.loaders.latestHistoryByUserId.Load(ctx, userId, func(ctx context.Context) ([]entity.History, error) {
+		var histories []entity.History
+		err := d.db.SelectContextStmt(ctx, d.stmts.getLatestHistoryByUserId, &histories, userId)
+		if err != nil {
+			return histories, err
+		}
+
+		return histories, nil
+	})
+}
+
+func (d domain) GetHistoriesByUserId(ctx context.Context, req GetHistoriesByUserIdRequest) (resp []entity.History, err error) {
+	defer func() {
+		if err == nil {
+			for i := range resp {
+				resp[i].NormalizeAmount()
+			}
+		}
+	}()
+
+	version, err := d.getHistoryVersion(ctx, req.UserId)
+	if err != nil {
+		return resp, err
+	}
+
+	filter, err := hashHistoriesFilter(req)
+	if err != nil {
+		return resp, err
+	}
+
+	histories, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetHistoriesByUserId, req.UserId, version, filter), func() (interface{}, error) {
 		var resp []entity.History
-		histories, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, userId), time.Minute*5, func() (interface{}, error) {
+		histories, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetHistoriesByUserId, req.UserId, version, filter), time.Minute*5, func() (interface{}, error) {
 			var respRedis []entity.History
-			historiesStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, userId), time.Duration(time.Minute*30), func() (interface{}, error) {
+			historiesStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetHistoriesByUserId, req.UserId, version, filter), time.Duration(time.Minute*30), func() (interface{}, error) {
 				var history []entity.History
-				err := d.db.SelectContextStmt(ctx, d.stmts.getLatestHistoryByUserId, &history, userId)
+				err := d.db.SelectContextStmt(ctx, d.stmts.getHistoriesByUserId, &history, historiesFilterArgs(req)...)
 				if err != nil {
 					return history, err
 				}
@@ -267,25 +562,177 @@ func (d domain) GetLatestHistoryByUserId(ctx context.Context, userId string) (re
 	return histories.([]entity.History), nil
 }
 
+// GetHistoriesByJournalEntryId reads the histories a journal entry wrote, with the fee charged for it, straight
+// from Postgres. It is only read right after the journal entry is committed, when no cache has it yet.
+func (d domain) GetHistoriesByJournalEntryId(ctx context.Context, journalEntryId string) (resp []entity.History, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getHistoriesByJournalEntryId, &resp, journalEntryId, int(enum.FEE))
+	if err != nil {
+		return resp, err
+	}
+
+	for i := range resp {
+		resp[i].NormalizeAmount()
+	}
+
+	return resp, nil
+}
+
+// GetTopHistoriesByUserId returns the 10 largest histories of a user created in [from, to), cached per
+// history version like the paginated histories.
+func (d domain) GetTopHistoriesByUserId(ctx context.Context, userId string, from time.Time, to time.Time) (resp []entity.History, err error) {
+	defer func() {
+		if err == nil {
+			for i := range resp {
+				resp[i].NormalizeAmount()
+			}
+		}
+	}()
+
+	version, err := d.getHistoryVersion(ctx, userId)
+	if err != nil {
+		return resp, err
+	}
+
+	histories, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetTopHistoriesByUserId, userId, version, from.Unix(), to.Unix()), func() (interface{}, error) {
+		var resp []entity.History
+		histories, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetTopHistoriesByUserId, userId, version, from.Unix(), to.Unix()), time.Minute*5, func() (interface{}, error) {
+			var respRedis []entity.History
+			historiesStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetTopHistoriesByUserId, userId, version, from.Unix(), to.Unix()), time.Duration(time.Minute*30), func() (interface{}, error) {
+				var history []entity.History
+				err := d.db.SelectContextStmt(ctx, d.stmts.getTopHistoriesByUserId, &history, userId, from, to, int(enum.DEBIT), int(enum.FEE))
+				if err != nil {
+					return history, err
+				}
+
+				return history, nil
+			})
+			if err != nil {
+				return respRedis, err
+			}
+
+			err = jsoniter.UnmarshalFromString(historiesStr, &respRedis)
+			if err != nil {
+				return respRedis, err
+			}
+
+			return respRedis, nil
+		})
+		if err != nil {
+			return resp, err
+		}
+
+		return histories.Value().([]entity.History), nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	return histories.([]entity.History), nil
+}
+
+// getHistoryVersion returns the version of the cached history pages of a user, which is bumped by insertHistory.
+func (d domain) getHistoryVersion(ctx context.Context, userId string) (resp int64, err error) {
+	versionStr, err := d.redis.Get(ctx, fmt.Sprintf(cacheKeyHistoryVersionByUserId, userId))
+	if redis.IsNil(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	return strconv.ParseInt(versionStr, 10, 64)
+}
+
+func hashHistoriesFilter(req GetHistoriesByUserIdRequest) (string, error) {
+	reqJson, err := jsoniter.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(reqJson)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// historiesFilterArgs maps unset filters to NULL so queryGetHistoriesByUserId ignores them.
+func historiesFilterArgs(req GetHistoriesByUserIdRequest) []interface{} {
+	args := make([]interface{}, 10)
+	args[0] = req.UserId
+	if req.Type != 0 {
+		args[1] = req.Type
+	}
+	if req.TargetUserId != "" {
+		args[2] = req.TargetUserId
+	}
+	if req.MinAmount != nil {
+		args[3] = *req.MinAmount
+	}
+	if req.MaxAmount != nil {
+		args[4] = *req.MaxAmount
+	}
+	if !req.From.IsZero() {
+		args[5] = req.From
+	}
+	if !req.To.IsZero() {
+		args[6] = req.To
+	}
+	if !req.CursorCreatedAt.IsZero() {
+		args[7] = req.CursorCreatedAt
+		args[8] = req.CursorId
+	}
+	args[9] = req.Limit
+
+	return args
+}
+
 func (d domain) GetHistorySummaryByUserIdAndType(ctx context.Context, userId string, historyType int) (resp []entity.HistorySummary, err error) {
-	historySummaries, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetHistorySummaryByUserIdAndType, userId, historyType), func() (interface{}, error) {
-		var resp []entity.HistorySummary
-		historySummaries, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, userId, historyType), time.Minute*5, func() (interface{}, error) {
-			var respRedis []entity.HistorySummary
-			historySummariesStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, userId, historyType), time.Duration(time.Minute*30), func() (interface{}, error) {
-				var historySummary []entity.HistorySummary
-				err := d.db.SelectContextStmt(ctx, d.stmts.getHistorySummaryByUserIdAndType, &historySummary, userId, historyType)
+	return d.loaders.historySummaryByUserIdAndType.Load(ctx, historySummaryKey{
+		UserId: userId,
+		Type:   historyType,
+	}, func(ctx context.Context) ([]entity.HistorySummary, error) {
+		var historySummaries []entity.HistorySummary
+		err := d.db.SelectContextStmt(ctx, d.stmts.getHistorySummaryByUserIdAndType, &historySummaries, userId, historyType)
+		if err != nil {
+			return historySummaries, err
+		}
+
+		return historySummaries, nil
+	})
+}
+
+func (d domain) GetJournalEntryById(ctx context.Context, id string) (resp entity.JournalEntry, err error) {
+	defer func() {
+		if err == nil && resp.Id == "" {
+			err = sql.ErrNoRows
+		}
+	}()
+
+	journalEntry, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetJournalEntryById, id), func() (interface{}, error) {
+		var resp entity.JournalEntry
+		journalEntry, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetJournalEntryById, id), time.Minute*5, func() (interface{}, error) {
+			var respRedis entity.JournalEntry
+			journalEntryStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetJournalEntryById, id), time.Duration(time.Minute*30), func() (interface{}, error) {
+				var journalEntry entity.JournalEntry
+				err := d.db.GetContextStmt(ctx, d.stmts.getJournalEntryById, &journalEntry, id)
+				if err == sql.ErrNoRows {
+					return journalEntry, nil
+				}
 				if err != nil {
-					return historySummary, err
+					return journalEntry, err
 				}
 
-				return historySummary, nil
+				err = d.db.SelectContextStmt(ctx, d.stmts.getPostingsByJournalEntryId, &journalEntry.Postings, id)
+				if err != nil {
+					return journalEntry, err
+				}
+
+				return journalEntry, nil
 			})
 			if err != nil {
 				return respRedis, err
 			}
 
-			err = jsoniter.UnmarshalFromString(historySummariesStr, &respRedis)
+			err = jsoniter.UnmarshalFromString(journalEntryStr, &respRedis)
 			if err != nil {
 				return respRedis, err
 			}
@@ -296,11 +743,49 @@ func (d domain) GetHistorySummaryByUserIdAndType(ctx context.Context, userId str
 			return resp, err
 		}
 
-		return historySummaries.Value().([]entity.HistorySummary), nil
+		return journalEntry.Value().(entity.JournalEntry), nil
 	})
 	if err != nil {
 		return resp, err
 	}
 
-	return historySummaries.([]entity.HistorySummary), nil
+	return journalEntry.(entity.JournalEntry), nil
+}
+
+func (d domain) GetPostingsByAccountId(ctx context.Context, req GetPostingsByAccountIdRequest) (resp []entity.Posting, err error) {
+	var cursorCreatedAt, cursorId interface{}
+	if !req.CursorCreatedAt.IsZero() {
+		cursorCreatedAt, cursorId = req.CursorCreatedAt, req.CursorId
+	}
+
+	err = d.db.SelectContextStmt(ctx, d.stmts.getPostingsByAccountId, &resp, req.AccountId, cursorCreatedAt, cursorId, req.Limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) GetIdempotencyKey(ctx context.Context, userId string, key string) (resp entity.IdempotencyKey, err error) {
+	idempotencyKeyStr, err := d.redis.Get(ctx, fmt.Sprintf(cacheKeyGetIdempotencyKey, userId, key))
+	if err == nil && jsoniter.UnmarshalFromString(idempotencyKeyStr, &resp) == nil {
+		return resp, nil
+	}
+
+	err = d.db.GetContextStmt(ctx, d.stmts.getIdempotencyKey, &resp, userId, key)
+	if err != nil {
+		return resp, err
+	}
+
+	json, err := jsoniter.MarshalToString(resp)
+	if err != nil {
+		return resp, err
+	}
+
+	_, err = d.redis.SetEx(ctx, fmt.Sprintf(cacheKeyGetIdempotencyKey, userId, key), json, time.Hour*24)
+	if err != nil {
+		log.Errorln("GetIdempotencyKey.SetEx", err)
+	}
+
+	return resp, nil
 }
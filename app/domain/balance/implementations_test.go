@@ -11,11 +11,15 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 	"github.com/kevinsudut/wallet-system/pkg/helper/singleflight"
 	"github.com/kevinsudut/wallet-system/pkg/lib/database"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
 	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+	"github.com/lib/pq"
+	goredis "github.com/redis/go-redis/v9"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -25,27 +29,40 @@ var (
 
 func TestMain(m *testing.M) {
 	log.Init()
-	cache.Set(fmt.Sprintf(cacheKeyGetBalanceByUserId, "test"), entity.Balance{}, time.Minute*5)
-	cache.Set(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id"), entity.Balance{
+	seed := newLoaders(nil, cache)
+	seed.balanceByUserId.Load(context.Background(), "test", func(ctx context.Context) (entity.Balance, error) {
+		return entity.Balance{}, sql.ErrNoRows
+	})
+	seed.balanceByUserId.Set(context.Background(), "id", entity.Balance{
 		UserId: "id",
 		Amount: 10,
-	}, time.Minute*5)
-	cache.Set(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id"), []entity.History{
+	})
+	seed.latestHistoryByUserId.Set(context.Background(), "id", []entity.History{
 		{
 			UserId:       "id",
 			TargetUserId: "id",
 			Amount:       10,
 			Type:         1,
 		},
-	}, time.Minute*5)
-	cache.Set(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1), []entity.HistorySummary{
+	})
+	seed.historySummaryByUserIdAndType.Set(context.Background(), historySummaryKey{UserId: "id", Type: 1}, []entity.HistorySummary{
 		{
 			UserId:       "id",
 			TargetUserId: "id",
 			Amount:       10,
 			Type:         1,
 		},
+	})
+	cache.Set(fmt.Sprintf(cacheKeyGetJournalEntryById, "id"), entity.JournalEntry{
+		Id:          "id",
+		Type:        1,
+		Description: "Top-up money",
+		Postings: []entity.Posting{
+			{Id: "posting1", JournalEntryId: "id", AccountId: "system:funding", Amount: -10},
+			{Id: "posting2", JournalEntryId: "id", AccountId: "id", Amount: 10},
+		},
 	}, time.Minute*5)
+	cache.Set(fmt.Sprintf(cacheKeyGetJournalEntryById, "test"), entity.JournalEntry{}, time.Minute*5)
 	os.Exit(m.Run())
 }
 
@@ -97,14 +114,45 @@ func Test_domain_GetBalanceByUserId(t *testing.T) {
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id"), time.Minute*5, gomock.Any()).Return(
-						cache.Get(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")), nil,
-					),
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(cache.Get(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id"))),
 				)
 			},
 		},
 		{
-			name: "error fetch",
+			name: "success db",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getBalanceByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "other",
+			},
+			wantResp: entity.Balance{
+				UserId: "other",
+				Amount: 20,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetBalanceByUserId, "other")).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "other")).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "other").SetArg(2, entity.Balance{
+						UserId: "other",
+						Amount: 20,
+					}).Return(nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "other"), gomock.Any(), gomock.Any()).Return("", nil),
+					mockCache.EXPECT().Set(fmt.Sprintf(cacheKeyGetBalanceByUserId, "other"), gomock.Any(), gomock.Any()),
+				)
+			},
+		},
+		{
+			name: "error db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
@@ -122,7 +170,9 @@ func Test_domain_GetBalanceByUserId(t *testing.T) {
 			wantErr:  true,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id"), time.Minute*5, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(fmt.Errorf("foo")),
 				)
 			},
 		},
@@ -145,9 +195,7 @@ func Test_domain_GetBalanceByUserId(t *testing.T) {
 			wantErr:  true,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetBalanceByUserId, "test"), time.Minute*5, gomock.Any()).Return(
-						cache.Get(fmt.Sprintf(cacheKeyGetBalanceByUserId, "test")), nil,
-					),
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetBalanceByUserId, "test")).Return(cache.Get(fmt.Sprintf(cacheKeyGetBalanceByUserId, "test"))),
 				)
 			},
 		},
@@ -158,6 +206,7 @@ func Test_domain_GetBalanceByUserId(t *testing.T) {
 				db:           tt.fields.db,
 				redis:        tt.fields.redis,
 				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
 				stmts:        tt.fields.stmts,
 				singleflight: tt.fields.singleflight,
 			}
@@ -189,8 +238,8 @@ func Test_domain_GrantBalanceByUserId(t *testing.T) {
 		singleflight singleflight.SingleFlightItf
 	}
 	type args struct {
-		ctx     context.Context
-		balance entity.Balance
+		ctx context.Context
+		req GrantBalanceByUserIdRequest
 	}
 	tests := []struct {
 		name    string
@@ -207,13 +256,15 @@ func Test_domain_GrantBalanceByUserId(t *testing.T) {
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				balance: entity.Balance{
+				req: GrantBalanceByUserIdRequest{
 					UserId: "id",
 					Amount: 10,
 				},
@@ -222,40 +273,51 @@ func Test_domain_GrantBalanceByUserId(t *testing.T) {
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(false),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_BALANCE_CREDITED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
 
 					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
 					// updateHistorySummary
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(false),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(false),
 					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
 				)
 			},
 		},
 		{
-			name: "error insertHistory.Delete redis",
+			name: "error insertBalanceChangedEvent.ExecContextStmtTx db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					grantBalanceByUserId:     &sqlx.Stmt{},
-					insertHistory:            &sqlx.Stmt{},
-					updateHistorySummaryById: &sqlx.Stmt{},
+					grantBalanceByUserId: &sqlx.Stmt{},
+					insertJournalEntry:   &sqlx.Stmt{},
+					insertPosting:        &sqlx.Stmt{},
+					insertOutboxEvent:    &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				balance: entity.Balance{
+				req: GrantBalanceByUserIdRequest{
 					UserId: "id",
 					Amount: 10,
 				},
@@ -264,148 +326,198 @@ func Test_domain_GrantBalanceByUserId(t *testing.T) {
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(false),
-
-					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-
-					// updateHistorySummary
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(false),
-
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), fmt.Errorf("foo")),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_BALANCE_CREDITED), enum.EVENT_VERSION, gomock.Any()).Return(fmt.Errorf("foo")),
 					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
 		{
-			name: "error updateHistorySummary.Delete redis",
+			name: "success insertHistory.Delete redis error is only logged",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				balance: entity.Balance{
+				req: GrantBalanceByUserIdRequest{
 					UserId: "id",
 					Amount: 10,
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(false),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
 					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
 					// updateHistorySummary
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), fmt.Errorf("foo")),
-					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), fmt.Errorf("foo")),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
 				)
 			},
 		},
 		{
-			name: "error updateHistorySummary.ExecContextStmtTx db",
+			name: "success insertHistory.Incr redis error is only logged",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				balance: entity.Balance{
+				req: GrantBalanceByUserIdRequest{
 					UserId: "id",
 					Amount: 10,
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(false),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
 					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
 					// updateHistorySummary
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
-					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(0), fmt.Errorf("foo")),
 				)
 			},
 		},
 		{
-			name: "error insertHistory.ExecContextStmtTx db",
+			name: "success updateHistorySummary.Delete redis error is only logged",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				balance: entity.Balance{
+				req: GrantBalanceByUserIdRequest{
 					UserId: "id",
 					Amount: 10,
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(false),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
 					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
-					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// updateHistorySummary
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), fmt.Errorf("foo")),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
 				)
 			},
 		},
 		{
-			name: "error grantBalanceByUserId.Delete redis",
+			name: "error updateHistorySummaryBuckets.ExecContextStmtTx db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					grantBalanceByUserId:     &sqlx.Stmt{},
-					insertHistory:            &sqlx.Stmt{},
-					updateHistorySummaryById: &sqlx.Stmt{},
+					grantBalanceByUserId:        &sqlx.Stmt{},
+					insertJournalEntry:          &sqlx.Stmt{},
+					insertPosting:               &sqlx.Stmt{},
+					insertHistory:               &sqlx.Stmt{},
+					updateHistorySummaryById:    &sqlx.Stmt{},
+					updateHistorySummaryBuckets: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				balance: entity.Balance{
+				req: GrantBalanceByUserIdRequest{
 					UserId: "id",
 					Amount: 10,
 				},
@@ -414,28 +526,44 @@ func Test_domain_GrantBalanceByUserId(t *testing.T) {
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), fmt.Errorf("foo")),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// updateHistorySummary
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
 					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
 		{
-			name: "error grantBalanceByUserId.ExecContextStmtTx db",
+			name: "error updateHistorySummary.ExecContextStmtTx db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				balance: entity.Balance{
+				req: GrantBalanceByUserIdRequest{
 					UserId: "id",
 					Amount: 10,
 				},
@@ -444,27 +572,43 @@ func Test_domain_GrantBalanceByUserId(t *testing.T) {
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// updateHistorySummary
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
 					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
 		{
-			name: "error Begin",
+			name: "error insertHistory.ExecContextStmtTx db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				balance: entity.Balance{
+				req: GrantBalanceByUserIdRequest{
 					UserId: "id",
 					Amount: 10,
 				},
@@ -472,546 +616,7375 @@ func Test_domain_GrantBalanceByUserId(t *testing.T) {
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			d := domain{
-				db:           tt.fields.db,
-				redis:        tt.fields.redis,
-				cache:        tt.fields.cache,
-				stmts:        tt.fields.stmts,
-				singleflight: tt.fields.singleflight,
-			}
-			tt.mock()
-			if err := d.GrantBalanceByUserId(tt.args.ctx, tt.args.balance); (err != nil) != tt.wantErr {
-				t.Errorf("domain.GrantBalanceByUserId() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func Test_domain_DisburmentBalance(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	mockDatabase := database.NewMockDatabaseItf(ctrl)
-	mockRedis := redis.NewMockRedisItf(ctrl)
-	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
-
-	type fields struct {
-		db           database.DatabaseItf
-		redis        redis.RedisItf
-		cache        lrucache.LRUCacheItf
-		stmts        databaseStmts
-		singleflight singleflight.SingleFlightItf
-	}
-	type args struct {
-		ctx context.Context
-		req DisburmentBalanceRequest
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
-		mock    func()
-	}{
 		{
-			name: "success",
+			name: "success grantBalanceByUserId.Delete redis error is only logged",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				req: DisburmentBalanceRequest{
-					UserId:   "id",
-					ToUserId: "toid",
-					Amount:   10,
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
 				},
 			},
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(false),
-
-					// deductBalanceByUserId
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(false),
-
-					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(false),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(false),
 
 					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// updateHistorySummary
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(false),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(false),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
 					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), fmt.Errorf("foo")),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
 				)
 			},
 		},
 		{
-			name: "error insertHistory.ExecContextStmtTx db",
+			name: "success grantBalanceByUserId.Invalidate cache error is only logged",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				req: DisburmentBalanceRequest{
-					UserId:   "id",
-					ToUserId: "toid",
-					Amount:   10,
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(false),
-
-					// deductBalanceByUserId
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(false),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
 					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// updateHistorySummary
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(false),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(false),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
-					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
-					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(fmt.Errorf("foo")),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
 				)
 			},
 		},
 		{
-			name: "error insertHistory.ExecContextStmtTx db",
+			name: "error grantBalanceByUserId.ExecContextStmtTx db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				req: DisburmentBalanceRequest{
-					UserId:   "id",
-					ToUserId: "toid",
-					Amount:   10,
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
 				},
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(false),
-
-					// deductBalanceByUserId
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(false),
-
-					// insertHistory
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
 					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
 		{
-			name: "error deductBalanceByUserId.Delete redis",
+			name: "success with idempotency key",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
+					insertIdempotencyKey:     &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				req: DisburmentBalanceRequest{
-					UserId:   "id",
-					ToUserId: "toid",
-					Amount:   10,
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
+					IdempotencyKey: entity.IdempotencyKey{
+						UserId:       "id",
+						Key:          "key",
+						Fingerprint:  "fingerprint",
+						ResponseCode: 204,
+						ResponseBody: "{}",
+					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// insertIdempotencyKey
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "key", "fingerprint", 204, "{}").Return(nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(false),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
-					// deductBalanceByUserId
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), fmt.Errorf("foo")),
-					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
-				)
-			},
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// updateHistorySummary
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
 		},
 		{
-			name: "error deductBalanceByUserId.ExecContextStmtTx db",
+			name: "error insertIdempotencyKey duplicate key",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
+					insertIdempotencyKey:     &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				req: DisburmentBalanceRequest{
-					UserId:   "id",
-					ToUserId: "toid",
-					Amount:   10,
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
+					IdempotencyKey: entity.IdempotencyKey{
+						UserId: "id",
+						Key:    "key",
+					},
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// insertIdempotencyKey
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&pq.Error{Code: "23505"}),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Commit",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
 				},
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 					// grantBalanceByUserId
 					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
-					mockCache.EXPECT().Delete(fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(false),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 
-					// deductBalanceByUserId
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
-					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// updateHistorySummary
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(fmt.Errorf("foo")),
 				)
 			},
 		},
 		{
-			name: "error grantBalanceByUserId.ExecContextStmtTx db",
+			name: "error postJournalEntry.insertPosting db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				req: DisburmentBalanceRequest{
-					UserId:   "id",
-					ToUserId: "toid",
-					Amount:   10,
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
 				},
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
 					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
-					// grantBalanceByUserId
-					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
 					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
 		{
-			name: "error Begin",
+			name: "error postJournalEntry.insertJournalEntry db",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
 					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
 					insertHistory:            &sqlx.Stmt{},
 					updateHistorySummaryById: &sqlx.Stmt{},
 				},
 			},
 			args: args{
 				ctx: context.Background(),
-				req: DisburmentBalanceRequest{
-					UserId:   "id",
-					ToUserId: "toid",
-					Amount:   10,
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
 				},
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			d := domain{
-				db:           tt.fields.db,
-				redis:        tt.fields.redis,
-				cache:        tt.fields.cache,
-				stmts:        tt.fields.stmts,
-				singleflight: tt.fields.singleflight,
-			}
-			tt.mock()
-			if err := d.DisburmentBalance(tt.args.ctx, tt.args.req); (err != nil) != tt.wantErr {
-				t.Errorf("domain.DisburmentBalance() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func Test_domain_GetLatestHistoryByUserId(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	mockDatabase := database.NewMockDatabaseItf(ctrl)
-	mockRedis := redis.NewMockRedisItf(ctrl)
-	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
-
-	type fields struct {
-		db           database.DatabaseItf
-		redis        redis.RedisItf
-		cache        lrucache.LRUCacheItf
-		stmts        databaseStmts
-		singleflight singleflight.SingleFlightItf
-	}
-	type args struct {
-		ctx    context.Context
-		userId string
-	}
-	tests := []struct {
-		name     string
-		fields   fields
-		args     args
-		wantResp []entity.History
-		wantErr  bool
-		mock     func()
-	}{
 		{
-			name: "success",
+			name: "success with fee",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					getLatestHistoryByUserId: &sqlx.Stmt{},
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
+					grantBalanceByUserId:            &sqlx.Stmt{},
+					deductBalanceByUserId:           &sqlx.Stmt{},
+					insertJournalEntry:              &sqlx.Stmt{},
+					insertPosting:                   &sqlx.Stmt{},
+					insertHistory:                   &sqlx.Stmt{},
+					updateHistorySummaryById:        &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
-				ctx:    context.Background(),
-				userId: "id",
-			},
-			wantResp: []entity.History{
-				{
-					UserId:       "id",
-					TargetUserId: "id",
-					Amount:       10,
-					Type:         1,
+				ctx: context.Background(),
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 1000,
+					FeeRule: entity.FeeRule{
+						Id:           1,
+						RateBps:      100,
+						FreePerMonth: 1,
+					},
 				},
 			},
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id"), time.Minute*5, gomock.Any()).Return(
-						cache.Get(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")), nil,
-					),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					// getFee
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TOPUP), 1, "UTC").SetArg(3, int64(1)).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.TOPUP), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), enum.ACCOUNT_FUNDING, money.Money(-1000)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(1000)).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(1000)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id", money.Money(1000), int(enum.CREDIT), gomock.Any(), nil, money.Money(10), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// postFee
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.FEE_CHARGE), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(-10)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "id").Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), enum.ACCOUNT_REVENUE, money.Money(10)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id", money.Money(10), int(enum.FEE), gomock.Any(), gomock.Any(), money.Money(0), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 3)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 3)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
 				)
 			},
 		},
 		{
-			name: "error fetch",
+			name: "error fee exceeds amount",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					getLatestHistoryByUserId: &sqlx.Stmt{},
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
-				ctx:    context.Background(),
-				userId: "id",
+				ctx: context.Background(),
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
+					FeeRule: entity.FeeRule{
+						Id:      1,
+						FlatFee: 10,
+					},
+				},
 			},
-			wantResp: nil,
-			wantErr:  true,
+			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id"), time.Minute*5, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			d := domain{
-				db:           tt.fields.db,
-				redis:        tt.fields.redis,
-				cache:        tt.fields.cache,
-				stmts:        tt.fields.stmts,
-				singleflight: tt.fields.singleflight,
-			}
-			tt.mock()
-			gotResp, err := d.GetLatestHistoryByUserId(tt.args.ctx, tt.args.userId)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("domain.GetLatestHistoryByUserId() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("domain.GetLatestHistoryByUserId() = %v, want %v", gotResp, tt.wantResp)
-			}
-		})
-	}
-}
-
-func Test_domain_GetHistorySummaryByUserIdAndType(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	mockDatabase := database.NewMockDatabaseItf(ctrl)
-	mockRedis := redis.NewMockRedisItf(ctrl)
-	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
-
-	type fields struct {
-		db           database.DatabaseItf
-		redis        redis.RedisItf
-		cache        lrucache.LRUCacheItf
-		stmts        databaseStmts
-		singleflight singleflight.SingleFlightItf
-	}
-	type args struct {
-		ctx         context.Context
-		userId      string
-		historyType int
-	}
-	tests := []struct {
-		name     string
-		fields   fields
-		args     args
-		wantResp []entity.HistorySummary
-		wantErr  bool
-		mock     func()
-	}{
 		{
-			name: "success",
+			name: "error account frozen",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					getHistorySummaryByUserIdAndType: &sqlx.Stmt{},
+					lockUserStatusesByIds: &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
-				ctx:         context.Background(),
-				userId:      "id",
-				historyType: 1,
-			},
-			wantResp: []entity.HistorySummary{
-				{
-					UserId:       "id",
-					TargetUserId: "id",
-					Amount:       10,
-					Type:         1,
+				ctx: context.Background(),
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
 				},
 			},
-			wantErr: false,
+			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1), time.Minute*5, gomock.Any()).Return(
-						cache.Get(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)), nil,
-					),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_FROZEN)}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
 		{
-			name: "error fetch",
+			name: "error lockUserStatuses",
 			fields: fields{
 				db:    mockDatabase,
 				redis: mockRedis,
 				cache: mockCache,
 				stmts: databaseStmts{
-					getHistorySummaryByUserIdAndType: &sqlx.Stmt{},
+					lockUserStatusesByIds: &sqlx.Stmt{},
 				},
-				singleflight: &singleflight.MockSingleFlight{},
 			},
 			args: args{
-				ctx:         context.Background(),
-				userId:      "id",
-				historyType: 1,
+				ctx: context.Background(),
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
+				},
 			},
-			wantResp: nil,
-			wantErr:  true,
+			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1), time.Minute*5, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
 				)
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			d := domain{
+		{
+			name: "error getFee",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
+					FeeRule: entity.FeeRule{
+						Id:           1,
+						FlatFee:      1,
+						FreePerMonth: 1,
+					},
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", "id").Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TOPUP), 1, "UTC").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GrantBalanceByUserIdRequest{
+					UserId: "id",
+					Amount: 10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
 				db:           tt.fields.db,
 				redis:        tt.fields.redis,
 				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
 				stmts:        tt.fields.stmts,
 				singleflight: tt.fields.singleflight,
 			}
 			tt.mock()
-			gotResp, err := d.GetHistorySummaryByUserIdAndType(tt.args.ctx, tt.args.userId, tt.args.historyType)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("domain.GetHistorySummaryByUserIdAndType() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			if err := d.GrantBalanceByUserId(tt.args.ctx, tt.args.req); (err != nil) != tt.wantErr {
+				t.Errorf("domain.GrantBalanceByUserId() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("domain.GetHistorySummaryByUserIdAndType() = %v, want %v", gotResp, tt.wantResp)
+		})
+	}
+}
+
+func Test_domain_DisburmentBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	type fields struct {
+		db           database.DatabaseItf
+		redis        redis.RedisItf
+		cache        lrucache.LRUCacheItf
+		stmts        databaseStmts
+		singleflight singleflight.SingleFlightItf
+	}
+	type args struct {
+		ctx context.Context
+		req DisburmentBalanceRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertOutboxEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_TRANSFER_COMPLETED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), float64(10), "id").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "id").Return(float64(0), fmt.Errorf("foo")),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "success with conversion",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockUserStatusesByIds:    &sqlx.Stmt{},
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   16250,
+					Conversion: Conversion{
+						FromCurrency: "IDR",
+						ToCurrency:   "USD",
+						ToAmount:     1,
+						Rate:         6154,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUsers
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_ACTIVE), Currency: "IDR"}, {Id: "toid", Status: int(enum.USER_STATUS_ACTIVE), Currency: "USD"}}).Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 16250}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(-16250)).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(16250), "id").Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "system:fx:IDR", money.Money(16250)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "system:fx:USD", money.Money(-1)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", money.Money(1)).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "toid", money.Money(1)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id", money.Money(1), int(enum.CREDIT), gomock.Any(), gomock.Any(), gomock.Any(), "").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid", money.Money(16250), int(enum.DEBIT), gomock.Any(), gomock.Any(), gomock.Any(), "").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertOutboxEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_TRANSFER_COMPLETED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(1), "toid").Return(float64(1), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(1), "toid").Return(float64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), float64(16250), "id").Return(float64(16250), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(16250), "id").Return(float64(16250), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error currency mismatch",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockUserStatusesByIds: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_ACTIVE), Currency: "IDR"}, {Id: "toid", Status: int(enum.USER_STATUS_ACTIVE), Currency: "USD"}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error conversion to stale currency",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockUserStatusesByIds: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+					Conversion: Conversion{
+						FromCurrency: "IDR",
+						ToCurrency:   "SGD",
+						ToAmount:     1,
+						Rate:         8000,
+					},
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_ACTIVE), Currency: "IDR"}, {Id: "toid", Status: int(enum.USER_STATUS_ACTIVE), Currency: "USD"}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success retry on deadlock",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(&pq.Error{Code: "40P01"}),
+
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertOutboxEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_TRANSFER_COMPLETED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), float64(10), "id").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "id").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error Commit",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertOutboxEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_TRANSFER_COMPLETED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error insertHistory.ExecContextStmtTx db debit",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insertHistory.ExecContextStmtTx db credit",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error grantBalanceByUserId.ExecContextStmtTx db",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success deductBalanceByUserId.Delete redis error is only logged",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertOutboxEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_TRANSFER_COMPLETED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), fmt.Errorf("foo")),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), float64(10), "id").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "id").Return(float64(0), fmt.Errorf("foo")),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error deductBalanceByUserId.ExecContextStmtTx db",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insufficient balance",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 9}}).Return(nil),
+
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success with fee",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:           &sqlx.Stmt{},
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
+					grantBalanceByUserId:            &sqlx.Stmt{},
+					insertJournalEntry:              &sqlx.Stmt{},
+					insertPosting:                   &sqlx.Stmt{},
+					deductBalanceByUserId:           &sqlx.Stmt{},
+					insertHistory:                   &sqlx.Stmt{},
+					updateHistorySummaryById:        &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+					FeeRule: entity.FeeRule{
+						Id:           1,
+						FlatFee:      2,
+						FreePerMonth: 1,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 12}}).Return(nil),
+					// getFee
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TRANSFER), -1, "UTC").SetArg(3, int64(1)).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.TRANSFER), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(-10)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "id").Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", money.Money(10)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "toid", money.Money(10)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id", money.Money(10), int(enum.CREDIT), gomock.Any(), nil, money.Money(0), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid", money.Money(10), int(enum.DEBIT), gomock.Any(), nil, money.Money(2), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// postFee
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.FEE_CHARGE), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(-2)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(2), "id").Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), enum.ACCOUNT_REVENUE, money.Money(2)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id", money.Money(2), int(enum.FEE), gomock.Any(), gomock.Any(), money.Money(0), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertOutboxEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_TRANSFER_COMPLETED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), float64(10), "id").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "id").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 3)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 3)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error insufficient balance for fee",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:           &sqlx.Stmt{},
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+					FeeRule: entity.FeeRule{
+						Id:      1,
+						FlatFee: 2,
+					},
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 11}}).Return(nil),
+
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error getFee",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:           &sqlx.Stmt{},
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+					FeeRule: entity.FeeRule{
+						Id:           1,
+						FlatFee:      2,
+						FreePerMonth: 1,
+					},
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 12}}).Return(nil),
+					// getFee
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TRANSFER), -1, "UTC").Return(fmt.Errorf("foo")),
+
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error recipient not active",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockUserStatusesByIds: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{
+						{Id: "id", Status: int(enum.USER_STATUS_ACTIVE)},
+						{Id: "toid", Status: int(enum.USER_STATUS_CLOSED)},
+					}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error sender debit blocked",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockUserStatusesByIds: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{
+						{Id: "id", Status: int(enum.USER_STATUS_DEBIT_BLOCKED)},
+						{Id: "toid", Status: int(enum.USER_STATUS_ACTIVE)},
+					}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error lockBalancesByUserIds",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(fmt.Errorf("foo")),
+
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					lockBalancesByUserIds:    &sqlx.Stmt{},
+					grantBalanceByUserId:     &sqlx.Stmt{},
+					insertJournalEntry:       &sqlx.Stmt{},
+					insertPosting:            &sqlx.Stmt{},
+					deductBalanceByUserId:    &sqlx.Stmt{},
+					insertHistory:            &sqlx.Stmt{},
+					updateHistorySummaryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DisburmentBalanceRequest{
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   10,
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:           tt.fields.db,
+				redis:        tt.fields.redis,
+				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:        tt.fields.stmts,
+				singleflight: tt.fields.singleflight,
+			}
+			tt.mock()
+			if err := d.DisburmentBalance(tt.args.ctx, tt.args.req); (err != nil) != tt.wantErr {
+				t.Errorf("domain.DisburmentBalance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetLatestHistoryByUserId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	type fields struct {
+		db           database.DatabaseItf
+		redis        redis.RedisItf
+		cache        lrucache.LRUCacheItf
+		stmts        databaseStmts
+		singleflight singleflight.SingleFlightItf
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.History
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getLatestHistoryByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: []entity.History{
+				{
+					UserId:       "id",
+					TargetUserId: "id",
+					Amount:       10,
+					Type:         1,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(cache.Get(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id"))),
+				)
+			},
+		},
+		{
+			name: "error db",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getLatestHistoryByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return("", goredis.Nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:           tt.fields.db,
+				redis:        tt.fields.redis,
+				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:        tt.fields.stmts,
+				singleflight: tt.fields.singleflight,
+			}
+			tt.mock()
+			gotResp, err := d.GetLatestHistoryByUserId(tt.args.ctx, tt.args.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetLatestHistoryByUserId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetLatestHistoryByUserId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetHistoriesByUserId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	req := GetHistoriesByUserIdRequest{
+		UserId: "id",
+		Type:   2,
+		Limit:  10,
+	}
+	filter, _ := hashHistoriesFilter(req)
+	cache.Set(fmt.Sprintf(cacheKeyGetHistoriesByUserId, "id", 3, filter), []entity.History{
+		{
+			Id:           "history",
+			UserId:       "id",
+			TargetUserId: "target",
+			Amount:       10,
+			Type:         2,
+		},
+	}, time.Minute*5)
+
+	type fields struct {
+		db           database.DatabaseItf
+		redis        redis.RedisItf
+		cache        lrucache.LRUCacheItf
+		stmts        databaseStmts
+		singleflight singleflight.SingleFlightItf
+	}
+	type args struct {
+		ctx context.Context
+		req GetHistoriesByUserIdRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.History
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getHistoriesByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: []entity.History{
+				{
+					Id:           "history",
+					UserId:       "id",
+					TargetUserId: "target",
+					Amount:       -10,
+					Type:         2,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return("3", nil),
+					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetHistoriesByUserId, "id", 3, filter), time.Minute*5, gomock.Any()).Return(
+						cache.Get(fmt.Sprintf(cacheKeyGetHistoriesByUserId, "id", 3, filter)), nil,
+					),
+				)
+			},
+		},
+		{
+			name: "error fetch without version",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getHistoriesByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return("", goredis.Nil),
+					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetHistoriesByUserId, "id", 0, filter), time.Minute*5, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error get version",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getHistoriesByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:           tt.fields.db,
+				redis:        tt.fields.redis,
+				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:        tt.fields.stmts,
+				singleflight: tt.fields.singleflight,
+			}
+			tt.mock()
+			gotResp, err := d.GetHistoriesByUserId(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetHistoriesByUserId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetHistoriesByUserId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetHistoriesByJournalEntryId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx            context.Context
+		journalEntryId string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.History
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getHistoriesByJournalEntryId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:            context.Background(),
+				journalEntryId: "jid",
+			},
+			wantResp: []entity.History{
+				{Id: "hid1", JournalEntryId: "jid", UserId: "id", TargetUserId: "toid", Amount: -10, Type: int(enum.DEBIT)},
+				{Id: "hid2", JournalEntryId: "jid", UserId: "toid", TargetUserId: "id", Amount: 10, Type: int(enum.CREDIT)},
+				{Id: "hid3", JournalEntryId: "fid", UserId: "id", TargetUserId: "id", Amount: -1, Type: int(enum.FEE), ReferenceId: "jid"},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "jid", int(enum.FEE)).SetArg(2, []entity.History{
+						{Id: "hid1", JournalEntryId: "jid", UserId: "id", TargetUserId: "toid", Amount: 10, Type: int(enum.DEBIT)},
+						{Id: "hid2", JournalEntryId: "jid", UserId: "toid", TargetUserId: "id", Amount: 10, Type: int(enum.CREDIT)},
+						{Id: "hid3", JournalEntryId: "fid", UserId: "id", TargetUserId: "id", Amount: 1, Type: int(enum.FEE), ReferenceId: "jid"},
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getHistoriesByJournalEntryId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:            context.Background(),
+				journalEntryId: "jid",
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "jid", int(enum.FEE)).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetHistoriesByJournalEntryId(tt.args.ctx, tt.args.journalEntryId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetHistoriesByJournalEntryId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetHistoriesByJournalEntryId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetTopHistoriesByUserId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	cache.Set(fmt.Sprintf(cacheKeyGetTopHistoriesByUserId, "id", 3, from.Unix(), to.Unix()), []entity.History{
+		{
+			Id:           "history",
+			UserId:       "id",
+			TargetUserId: "target",
+			Amount:       10,
+			Type:         2,
+		},
+	}, time.Minute*5)
+
+	type fields struct {
+		db           database.DatabaseItf
+		redis        redis.RedisItf
+		cache        lrucache.LRUCacheItf
+		stmts        databaseStmts
+		singleflight singleflight.SingleFlightItf
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+		from   time.Time
+		to     time.Time
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.History
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getTopHistoriesByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				from:   from,
+				to:     to,
+			},
+			wantResp: []entity.History{
+				{
+					Id:           "history",
+					UserId:       "id",
+					TargetUserId: "target",
+					Amount:       -10,
+					Type:         2,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return("3", nil),
+					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetTopHistoriesByUserId, "id", 3, from.Unix(), to.Unix()), time.Minute*5, gomock.Any()).Return(
+						cache.Get(fmt.Sprintf(cacheKeyGetTopHistoriesByUserId, "id", 3, from.Unix(), to.Unix())), nil,
+					),
+				)
+			},
+		},
+		{
+			name: "error fetch without version",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getTopHistoriesByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				from:   from,
+				to:     to,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return("", goredis.Nil),
+					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetTopHistoriesByUserId, "id", 0, from.Unix(), to.Unix()), time.Minute*5, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error get version",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getTopHistoriesByUserId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				from:   from,
+				to:     to,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:           tt.fields.db,
+				redis:        tt.fields.redis,
+				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:        tt.fields.stmts,
+				singleflight: tt.fields.singleflight,
+			}
+			tt.mock()
+			gotResp, err := d.GetTopHistoriesByUserId(tt.args.ctx, tt.args.userId, tt.args.from, tt.args.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetTopHistoriesByUserId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetTopHistoriesByUserId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_historiesFilterArgs(t *testing.T) {
+	minAmount := money.Money(100)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		req  GetHistoriesByUserIdRequest
+		want []interface{}
+	}{
+		{
+			name: "without filter",
+			req: GetHistoriesByUserIdRequest{
+				UserId: "id",
+				Limit:  10,
+			},
+			want: []interface{}{"id", nil, nil, nil, nil, nil, nil, nil, nil, 10},
+		},
+		{
+			name: "with filter",
+			req: GetHistoriesByUserIdRequest{
+				UserId:          "id",
+				Type:            1,
+				TargetUserId:    "target",
+				MinAmount:       &minAmount,
+				From:            from,
+				CursorCreatedAt: cursor,
+				CursorId:        "cursor",
+				Limit:           10,
+			},
+			want: []interface{}{"id", 1, "target", minAmount, nil, from, nil, cursor, "cursor", 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := historiesFilterArgs(tt.req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("historiesFilterArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_domain_GetHistorySummaryByUserIdAndType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	type fields struct {
+		db           database.DatabaseItf
+		redis        redis.RedisItf
+		cache        lrucache.LRUCacheItf
+		stmts        databaseStmts
+		singleflight singleflight.SingleFlightItf
+	}
+	type args struct {
+		ctx         context.Context
+		userId      string
+		historyType int
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.HistorySummary
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getHistorySummaryByUserIdAndType: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx:         context.Background(),
+				userId:      "id",
+				historyType: 1,
+			},
+			wantResp: []entity.HistorySummary{
+				{
+					UserId:       "id",
+					TargetUserId: "id",
+					Amount:       10,
+					Type:         1,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(cache.Get(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1))),
+				)
+			},
+		},
+		{
+			name: "error db",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getHistorySummaryByUserIdAndType: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx:         context.Background(),
+				userId:      "id",
+				historyType: 1,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Get(fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return(nil),
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 1)).Return("", goredis.Nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", 1).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:           tt.fields.db,
+				redis:        tt.fields.redis,
+				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:        tt.fields.stmts,
+				singleflight: tt.fields.singleflight,
+			}
+			tt.mock()
+			gotResp, err := d.GetHistorySummaryByUserIdAndType(tt.args.ctx, tt.args.userId, tt.args.historyType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetHistorySummaryByUserIdAndType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetHistorySummaryByUserIdAndType() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetJournalEntryById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	type fields struct {
+		db           database.DatabaseItf
+		redis        redis.RedisItf
+		cache        lrucache.LRUCacheItf
+		stmts        databaseStmts
+		singleflight singleflight.SingleFlightItf
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.JournalEntry
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getJournalEntryById:         &sqlx.Stmt{},
+					getPostingsByJournalEntryId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: entity.JournalEntry{
+				Id:          "id",
+				Type:        1,
+				Description: "Top-up money",
+				Postings: []entity.Posting{
+					{Id: "posting1", JournalEntryId: "id", AccountId: "system:funding", Amount: -10},
+					{Id: "posting2", JournalEntryId: "id", AccountId: "id", Amount: 10},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetJournalEntryById, "id"), time.Minute*5, gomock.Any()).Return(
+						cache.Get(fmt.Sprintf(cacheKeyGetJournalEntryById, "id")), nil,
+					),
+				)
+			},
+		},
+		{
+			name: "error fetch",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getJournalEntryById:         &sqlx.Stmt{},
+					getPostingsByJournalEntryId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: entity.JournalEntry{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetJournalEntryById, "id"), time.Minute*5, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error not found",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getJournalEntryById:         &sqlx.Stmt{},
+					getPostingsByJournalEntryId: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "test",
+			},
+			wantResp: entity.JournalEntry{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Fetch(fmt.Sprintf(cacheKeyGetJournalEntryById, "test"), time.Minute*5, gomock.Any()).Return(
+						cache.Get(fmt.Sprintf(cacheKeyGetJournalEntryById, "test")), nil,
+					),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:           tt.fields.db,
+				redis:        tt.fields.redis,
+				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:        tt.fields.stmts,
+				singleflight: tt.fields.singleflight,
+			}
+			tt.mock()
+			gotResp, err := d.GetJournalEntryById(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetJournalEntryById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetJournalEntryById() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetPostingsByAccountId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req GetPostingsByAccountIdRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.Posting
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetPostingsByAccountIdRequest{
+					AccountId: "id",
+					Limit:     10,
+				},
+			},
+			wantResp: []entity.Posting{
+				{Id: "posting2", JournalEntryId: "id", AccountId: "id", Amount: 10},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", nil, nil, 10).SetArg(2, []entity.Posting{
+						{Id: "posting2", JournalEntryId: "id", AccountId: "id", Amount: 10},
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success with cursor",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetPostingsByAccountIdRequest{
+					AccountId:       "id",
+					CursorCreatedAt: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+					CursorId:        "posting2",
+					Limit:           10,
+				},
+			},
+			wantResp: []entity.Posting{
+				{Id: "posting1", JournalEntryId: "id", AccountId: "id", Amount: 10},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), "posting2", 10).SetArg(2, []entity.Posting{
+						{Id: "posting1", JournalEntryId: "id", AccountId: "id", Amount: 10},
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetPostingsByAccountIdRequest{
+					AccountId: "id",
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", nil, nil, 10).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetPostingsByAccountId(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetPostingsByAccountId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetPostingsByAccountId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetIdempotencyKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	idempotencyKey := entity.IdempotencyKey{
+		UserId:       "id",
+		Key:          "key",
+		Fingerprint:  "fingerprint",
+		ResponseCode: 204,
+		ResponseBody: "{}",
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+		key    string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.IdempotencyKey
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success from redis",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getIdempotencyKey: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				key:    "key",
+			},
+			wantResp: idempotencyKey,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetIdempotencyKey, "id", "key")).Return(`{"UserId":"id","Key":"key","Fingerprint":"fingerprint","ResponseCode":204,"ResponseBody":"{}"}`, nil),
+				)
+			},
+		},
+		{
+			name: "success from database",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getIdempotencyKey: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				key:    "key",
+			},
+			wantResp: idempotencyKey,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetIdempotencyKey, "id", "key")).Return("", fmt.Errorf("foo")),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", "key").SetArg(2, idempotencyKey).Return(nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), fmt.Sprintf(cacheKeyGetIdempotencyKey, "id", "key"), gomock.Any(), time.Hour*24).Return("", nil),
+				)
+			},
+		},
+		{
+			name: "error not found",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getIdempotencyKey: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				key:    "key",
+			},
+			wantResp: entity.IdempotencyKey{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), fmt.Sprintf(cacheKeyGetIdempotencyKey, "id", "key")).Return("", fmt.Errorf("foo")),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", "key").Return(sql.ErrNoRows),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				redis: tt.fields.redis,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetIdempotencyKey(tt.args.ctx, tt.args.userId, tt.args.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetIdempotencyKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetIdempotencyKey() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetLeaderboard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	entries := []entity.LeaderboardEntry{
+		{
+			UserId: "id",
+			Amount: 20,
+		},
+		{
+			UserId: "toid",
+			Amount: 10,
+		},
+	}
+
+	window := SummaryWindow{
+		Period: enum.SUMMARY_PERIOD_DAY,
+		From:   time.Date(2026, 9, 10, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, 9, 17, 0, 0, 0, 0, time.UTC),
+	}
+	cacheKeyWindow := fmt.Sprintf(cacheKeyGetWindowedLeaderboard, "sent", "day", "2026-09-10", "2026-09-17", 0, 10)
+	cache.Set(cacheKeyWindow, entries, leaderboardWindowCacheTTL)
+
+	type fields struct {
+		db           database.DatabaseItf
+		redis        redis.RedisItf
+		cache        lrucache.LRUCacheItf
+		stmts        databaseStmts
+		singleflight singleflight.SingleFlightItf
+	}
+	type args struct {
+		ctx context.Context
+		req GetLeaderboardRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.LeaderboardEntry
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_ALL,
+					Offset:    10,
+					Limit:     5,
+				},
+			},
+			wantResp: entries,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("1", nil),
+					mockRedis.EXPECT().ZRevRangeWithScores(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), int64(10), int64(14)).Return([]redis.Z{
+						{Member: "id", Score: 20},
+						{Member: "toid", Score: 10},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success rebuild",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: []entity.LeaderboardEntry{
+				{
+					UserId: "id",
+					Amount: 20,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("", goredis.Nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.DEBIT)).SetArg(2, []entity.LeaderboardEntry{entries[0]}).Return(nil),
+					mockRedis.EXPECT().ZAdd(gomock.Any(), gomock.Any(), redis.Z{Member: "id", Score: 20}).Return(int64(1), nil),
+					mockRedis.EXPECT().Rename(gomock.Any(), gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent")).Return("OK", nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.CREDIT)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received")).Return(int64(0), nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), nil).SetArg(2, entries).Return(nil),
+					mockRedis.EXPECT().ZAdd(gomock.Any(), gomock.Any(), redis.Z{Member: "id", Score: 20}, redis.Z{Member: "toid", Score: 10}).Return(int64(2), nil),
+					mockRedis.EXPECT().Rename(gomock.Any(), gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all")).Return("OK", nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), cacheKeyLeaderboardBuilt, 1, leaderboardRebuildInterval).Return("OK", nil),
+					mockRedis.EXPECT().ZRevRangeWithScores(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), int64(0), int64(9)).Return([]redis.Z{
+						{Member: "id", Score: 20},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error rebuild.SetEx",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("", goredis.Nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.DEBIT)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent")).Return(int64(0), nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.CREDIT)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received")).Return(int64(0), nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all")).Return(int64(0), nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), cacheKeyLeaderboardBuilt, 1, leaderboardRebuildInterval).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error rebuild.Rename",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("", goredis.Nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.DEBIT)).SetArg(2, []entity.LeaderboardEntry{entries[0]}).Return(nil),
+					mockRedis.EXPECT().ZAdd(gomock.Any(), gomock.Any(), redis.Z{Member: "id", Score: 20}).Return(int64(1), nil),
+					mockRedis.EXPECT().Rename(gomock.Any(), gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent")).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error rebuild.ZAdd",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("", goredis.Nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.DEBIT)).SetArg(2, []entity.LeaderboardEntry{entries[0]}).Return(nil),
+					mockRedis.EXPECT().ZAdd(gomock.Any(), gomock.Any(), redis.Z{Member: "id", Score: 20}).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error rebuild.Delete",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("", goredis.Nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.DEBIT)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent")).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error rebuild.SelectContextStmt",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("", goredis.Nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.DEBIT)).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "success window",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getWindowedLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Window:    window,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: entries,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Fetch(cacheKeyWindow, leaderboardWindowCacheTTL, gomock.Any()).Return(cache.Get(cacheKeyWindow), nil),
+				)
+			},
+		},
+		{
+			name: "error window",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					getWindowedLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Window:    window,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockCache.EXPECT().Fetch(cacheKeyWindow, leaderboardWindowCacheTTL, gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error get built",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error ZRevRangeWithScores",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getLeaderboard: &sqlx.Stmt{},
+				},
+				singleflight: &singleflight.MockSingleFlight{},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    0,
+					Limit:     10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), cacheKeyLeaderboardBuilt).Return("1", nil),
+					mockRedis.EXPECT().ZRevRangeWithScores(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), int64(0), int64(9)).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:           tt.fields.db,
+				redis:        tt.fields.redis,
+				cache:        tt.fields.cache,
+				loaders:      newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:        tt.fields.stmts,
+				singleflight: tt.fields.singleflight,
+			}
+			tt.mock()
+			gotResp, err := d.GetLeaderboard(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetLeaderboard() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetLeaderboard() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_compactHistorySummaryBuckets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type fields struct {
+		db       database.DatabaseItf
+		stmts    databaseStmts
+		location *time.Location
+	}
+	type args struct {
+		ctx context.Context
+		now time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deleteHistorySummaryBuckets: &sqlx.Stmt{},
+				},
+				location: jakarta,
+			},
+			args: args{
+				ctx: context.Background(),
+				now: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), enum.SUMMARY_PERIOD_DAY, "2026-09-13").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success nothing to compact",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deleteHistorySummaryBuckets: &sqlx.Stmt{},
+				},
+				location: time.UTC,
+			},
+			args: args{
+				ctx: context.Background(),
+				now: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), enum.SUMMARY_PERIOD_DAY, "2026-09-12").Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deleteHistorySummaryBuckets: &sqlx.Stmt{},
+				},
+				location: time.UTC,
+			},
+			args: args{
+				ctx: context.Background(),
+				now: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC),
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), enum.SUMMARY_PERIOD_DAY, "2026-09-12").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:       tt.fields.db,
+				stmts:    tt.fields.stmts,
+				location: tt.fields.location,
+			}
+			tt.mock()
+			if err := d.compactHistorySummaryBuckets(tt.args.ctx, tt.args.now); (err != nil) != tt.wantErr {
+				t.Errorf("domain.compactHistorySummaryBuckets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetTransferById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.Transfer
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getTransferById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "tid",
+			},
+			wantResp: entity.Transfer{
+				Id:             "tid",
+				FromUserId:     "id",
+				ToUserId:       "toid",
+				Amount:         10,
+				RefundedAmount: 4,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).SetArg(2, entity.Transfer{
+						Id:             "tid",
+						FromUserId:     "id",
+						ToUserId:       "toid",
+						Amount:         10,
+						RefundedAmount: 4,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error GetContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getTransferById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "tid",
+			},
+			wantResp: entity.Transfer{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).Return(sql.ErrNoRows),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetTransferById(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetTransferById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetTransferById() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_RefundTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	transfer := entity.Transfer{
+		Id:             "tid",
+		FromUserId:     "id",
+		ToUserId:       "toid",
+		Amount:         10,
+		RefundedAmount: 0,
+	}
+
+	stmts := databaseStmts{
+		lockTransferById:              &sqlx.Stmt{},
+		getRefundedAmountByTransferId: &sqlx.Stmt{},
+		lockBalancesByUserIds:         &sqlx.Stmt{},
+		grantBalanceByUserId:          &sqlx.Stmt{},
+		insertJournalEntry:            &sqlx.Stmt{},
+		insertPosting:                 &sqlx.Stmt{},
+		deductBalanceByUserId:         &sqlx.Stmt{},
+		insertHistory:                 &sqlx.Stmt{},
+		updateHistorySummaryById:      &sqlx.Stmt{},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req RefundTransferRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					RefundId:   "rid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     10,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockTransferById
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).SetArg(3, transfer).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).Return(nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id").SetArg(3, []entity.Balance{{UserId: "toid", Amount: 10}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "rid", int(enum.REFUND), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "rid", "toid", money.Money(-10)).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "rid", "id", money.Money(10)).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "rid", "id", "toid", money.Money(10), int(enum.CREDIT), gomock.Any(), "tid", money.Money(0), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid", money.Money(-10), int(enum.DEBIT)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid", int(enum.DEBIT), money.Money(-10), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "rid", "toid", "id", money.Money(10), int(enum.DEBIT), gomock.Any(), "tid", money.Money(0), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id", money.Money(-10), int(enum.CREDIT)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id", int(enum.CREDIT), money.Money(-10), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), float64(-10), "id").Return(float64(0), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(-10), "id").Return(float64(0), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(-10), "toid").Return(float64(0), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(-10), "toid").Return(float64(0), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error insufficient balance",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					RefundId:   "rid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     10,
+				},
+			},
+			wantErr: InsufficientBalanceError{UserId: "toid", Balance: 5, Amount: 10},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).SetArg(3, transfer).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).Return(nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id").SetArg(3, []entity.Balance{{UserId: "toid", Amount: 5}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error refund exceeds transfer",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					RefundId:   "rid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     5,
+				},
+			},
+			wantErr: RefundExceedsTransferError{TransferId: "tid", Refundable: 4, Amount: 5},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).SetArg(3, transfer).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).SetArg(3, money.Money(6)).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not the receiver",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					RefundId:   "rid",
+					TransferId: "tid",
+					UserId:     "id",
+					Amount:     10,
+				},
+			},
+			wantErr: ErrTransferNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).SetArg(3, transfer).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error transfer not found",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					RefundId:   "rid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     10,
+				},
+			},
+			wantErr: ErrTransferNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.DEBIT)).Return(sql.ErrNoRows),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					RefundId:   "rid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     10,
+				},
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.RefundTransfer(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.RefundTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetHoldById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	hold := entity.Hold{
+		Id:           "hid",
+		UserId:       "id",
+		TargetUserId: "toid",
+		Amount:       10,
+		Status:       int(enum.HOLD_STATUS_ACTIVE),
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.Hold
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getHoldById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "hid",
+			},
+			wantResp: hold,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "hid").SetArg(2, hold).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error GetContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getHoldById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "hid",
+			},
+			wantResp: entity.Hold{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "hid").Return(sql.ErrNoRows),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetHoldById(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetHoldById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetHoldById() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_CreateHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	expiresAt := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	hold := entity.Hold{
+		Id:           "hid",
+		UserId:       "id",
+		TargetUserId: "toid",
+		Amount:       10,
+		ExpiresAt:    expiresAt,
+	}
+
+	stmts := databaseStmts{
+		lockBalancesByUserIds: &sqlx.Stmt{},
+		holdBalanceByUserId:   &sqlx.Stmt{},
+		insertHold:            &sqlx.Stmt{},
+		insertIdempotencyKey:  &sqlx.Stmt{},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req CreateHoldRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					Hold: hold,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 20, HeldAmount: 10}}).Return(nil),
+					// holdBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "id").Return(nil),
+					// insertHold
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "hid", "id", "toid", money.Money(10), int(enum.HOLD_STATUS_ACTIVE), expiresAt).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insufficient available balance",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					Hold: hold,
+				},
+			},
+			wantErr: InsufficientBalanceError{UserId: "id", Balance: 5, Amount: 10},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 20, HeldAmount: 15}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insertIdempotencyKey",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					Hold: hold,
+					IdempotencyKey: entity.IdempotencyKey{
+						UserId: "id",
+						Key:    "key",
+					},
+				},
+			},
+			wantErr: ErrIdempotencyKeyExists,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "key", gomock.Any(), gomock.Any(), gomock.Any()).Return(&pq.Error{Code: "23505"}),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					Hold: hold,
+				},
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.CreateHold(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.CreateHold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_CaptureHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	hold := entity.Hold{
+		Id:           "hid",
+		UserId:       "id",
+		TargetUserId: "toid",
+		Amount:       10,
+		Status:       int(enum.HOLD_STATUS_ACTIVE),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	stmts := databaseStmts{
+		lockHoldById:             &sqlx.Stmt{},
+		updateHoldById:           &sqlx.Stmt{},
+		releaseBalanceByUserId:   &sqlx.Stmt{},
+		lockBalancesByUserIds:    &sqlx.Stmt{},
+		grantBalanceByUserId:     &sqlx.Stmt{},
+		insertJournalEntry:       &sqlx.Stmt{},
+		insertPosting:            &sqlx.Stmt{},
+		deductBalanceByUserId:    &sqlx.Stmt{},
+		insertHistory:            &sqlx.Stmt{},
+		updateHistorySummaryById: &sqlx.Stmt{},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req CaptureHoldRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success partial capture",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					HoldId:     "hid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     6,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "hid").SetArg(3, hold).Return(nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10, HeldAmount: 10}}).Return(nil),
+					// releaseBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "id").Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "tid", int(enum.TRANSFER), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", "id", money.Money(-6)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(6), "id").Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", "toid", money.Money(6)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "toid", money.Money(6)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", "toid", "id", money.Money(6), int(enum.CREDIT), gomock.Any(), nil, money.Money(0), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "tid", "id", "toid", money.Money(6), int(enum.DEBIT), gomock.Any(), nil, money.Money(0), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertOutboxEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_TRANSFER_COMPLETED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+
+					// updateHoldById
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.HOLD_STATUS_CAPTURED), money.Money(6), "tid", "hid").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(6), "toid").Return(float64(6), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(6), "toid").Return(float64(6), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), float64(6), "id").Return(float64(6), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(6), "id").Return(float64(6), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error capture exceeds hold",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					HoldId:     "hid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     11,
+				},
+			},
+			wantErr: CaptureExceedsHoldError{HoldId: "hid", Held: 10, Amount: 11},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "hid").SetArg(3, hold).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error hold expired",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					HoldId:     "hid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     10,
+				},
+			},
+			wantErr: ErrHoldNotActive,
+			mock: func() {
+				expired := hold
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "hid").SetArg(3, expired).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not the target",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					HoldId:     "hid",
+					TransferId: "tid",
+					UserId:     "id",
+					Amount:     10,
+				},
+			},
+			wantErr: ErrHoldNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "hid").SetArg(3, hold).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error hold not found",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					HoldId:     "hid",
+					TransferId: "tid",
+					UserId:     "toid",
+					Amount:     10,
+				},
+			},
+			wantErr: ErrHoldNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "hid").Return(sql.ErrNoRows),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.CaptureHold(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.CaptureHold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_VoidHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	hold := entity.Hold{
+		Id:           "hid",
+		UserId:       "id",
+		TargetUserId: "toid",
+		Amount:       10,
+		Status:       int(enum.HOLD_STATUS_ACTIVE),
+		ExpiresAt:    time.Now().Add(-time.Minute),
+	}
+
+	stmts := databaseStmts{
+		lockHoldById:           &sqlx.Stmt{},
+		updateHoldById:         &sqlx.Stmt{},
+		releaseBalanceByUserId: &sqlx.Stmt{},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req VoidHoldRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success expired hold not released yet",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: VoidHoldRequest{
+					HoldId: "hid",
+					UserId: "toid",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "hid").SetArg(3, hold).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.HOLD_STATUS_VOIDED), money.Money(0), nil, "hid").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error hold not active",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: VoidHoldRequest{
+					HoldId: "hid",
+					UserId: "toid",
+				},
+			},
+			wantErr: ErrHoldNotActive,
+			mock: func() {
+				captured := hold
+				captured.Status = int(enum.HOLD_STATUS_CAPTURED)
+
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "hid").SetArg(3, captured).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error releaseBalanceByUserId",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: VoidHoldRequest{
+					HoldId: "hid",
+					UserId: "toid",
+				},
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "hid").SetArg(3, hold).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "id").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.VoidHold(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.VoidHold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_releaseExpiredHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		now time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					releaseExpiredHolds: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				now: now,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.HOLD_STATUS_EXPIRED), int(enum.HOLD_STATUS_ACTIVE), now, expiredHoldReleaseBatch).SetArg(2, []string{"id"}).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.HOLD_STATUS_EXPIRED), int(enum.HOLD_STATUS_ACTIVE), now, expiredHoldReleaseBatch).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error delete redis",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					releaseExpiredHolds: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				now: now,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.HOLD_STATUS_EXPIRED), int(enum.HOLD_STATUS_ACTIVE), now, expiredHoldReleaseBatch).SetArg(2, []string{"id"}).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: databaseStmts{
+					releaseExpiredHolds: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				now: now,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.HOLD_STATUS_EXPIRED), int(enum.HOLD_STATUS_ACTIVE), now, expiredHoldReleaseBatch).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.releaseExpiredHolds(tt.args.ctx, tt.args.now); (err != nil) != tt.wantErr {
+				t.Errorf("domain.releaseExpiredHolds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetFee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req GetFeeRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp money.Money
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success without rule",
+			fields: fields{
+				db: mockDatabase,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetFeeRequest{
+					UserId:    "id",
+					Operation: enum.TRANSFER,
+					Amount:    1000,
+				},
+			},
+			wantResp: 0,
+			wantErr:  false,
+			mock:     func() {},
+		},
+		{
+			name: "success without free quota",
+			fields: fields{
+				db: mockDatabase,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetFeeRequest{
+					UserId:    "id",
+					Operation: enum.TRANSFER,
+					Amount:    1000,
+					FeeRule: entity.FeeRule{
+						Id:      1,
+						FlatFee: 5,
+						RateBps: 100,
+					},
+				},
+			},
+			wantResp: 15,
+			wantErr:  false,
+			mock:     func() {},
+		},
+		{
+			name: "success within free quota",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetFeeRequest{
+					UserId:    "id",
+					Operation: enum.TRANSFER,
+					Amount:    1000,
+					FeeRule: entity.FeeRule{
+						Id:           1,
+						FlatFee:      5,
+						FreePerMonth: 3,
+					},
+				},
+			},
+			wantResp: 0,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TRANSFER), -1, "UTC").SetArg(2, int64(2)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success after free quota",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetFeeRequest{
+					UserId:    "id",
+					Operation: enum.TRANSFER,
+					Amount:    1000,
+					FeeRule: entity.FeeRule{
+						Id:           1,
+						FlatFee:      5,
+						FreePerMonth: 3,
+					},
+				},
+			},
+			wantResp: 5,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TRANSFER), -1, "UTC").SetArg(2, int64(3)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error GetContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					countMonthlyPostingsByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetFeeRequest{
+					UserId:    "id",
+					Operation: enum.TRANSFER,
+					Amount:    1000,
+					FeeRule: entity.FeeRule{
+						Id:           1,
+						FlatFee:      5,
+						FreePerMonth: 3,
+					},
+				},
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TRANSFER), -1, "UTC").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:       tt.fields.db,
+				stmts:    tt.fields.stmts,
+				location: time.UTC,
+			}
+			tt.mock()
+			gotResp, err := d.GetFee(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetFee() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp != tt.wantResp {
+				t.Errorf("domain.GetFee() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_UpdateUserStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	stmts := databaseStmts{
+		lockUsersByIds:         &sqlx.Stmt{},
+		updateUserStatusById:   &sqlx.Stmt{},
+		insertUserStatusChange: &sqlx.Stmt{},
+	}
+	user := entity.User{
+		Id:       "id",
+		Username: "username",
+		Status:   int(enum.USER_STATUS_ACTIVE),
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req UpdateUserStatusRequest
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		wantErr    error
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					Status:  enum.USER_STATUS_FROZEN,
+					Reason:  "compromised",
+					ActorId: "adminid",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_FROZEN),
+			wantErr:    nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_STATUS_FROZEN), "compromised", gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.USER_STATUS_ACTIVE), int(enum.USER_STATUS_FROZEN), "compromised", "adminid").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insertUserStatusChange.ExecContextStmtTx",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					Status:  enum.USER_STATUS_FROZEN,
+					Reason:  "compromised",
+					ActorId: "adminid",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr:    fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_STATUS_FROZEN), "compromised", gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.USER_STATUS_ACTIVE), int(enum.USER_STATUS_FROZEN), "compromised", "adminid").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error updateUserStatusById.ExecContextStmtTx",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					Status:  enum.USER_STATUS_FROZEN,
+					Reason:  "compromised",
+					ActorId: "adminid",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr:    fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_STATUS_FROZEN), "compromised", gomock.Any(), "id").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error user closed",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					Status:  enum.USER_STATUS_ACTIVE,
+					Reason:  "reopen",
+					ActorId: "adminid",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_CLOSED),
+			wantErr:    ErrUserClosed,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{{
+						Id:     "id",
+						Status: int(enum.USER_STATUS_CLOSED),
+					}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					Status:  enum.USER_STATUS_FROZEN,
+					Reason:  "compromised",
+					ActorId: "adminid",
+				},
+			},
+			wantStatus: 0,
+			wantErr:    sql.ErrNoRows,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error lockUsersByIds.SelectContextStmtTx",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					Status:  enum.USER_STATUS_FROZEN,
+					Reason:  "compromised",
+					ActorId: "adminid",
+				},
+			},
+			wantStatus: 0,
+			wantErr:    fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					Status:  enum.USER_STATUS_FROZEN,
+					Reason:  "compromised",
+					ActorId: "adminid",
+				},
+			},
+			wantStatus: 0,
+			wantErr:    fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.UpdateUserStatus(tt.args.ctx, tt.args.req)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.UpdateUserStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp.Status != tt.wantStatus {
+				t.Errorf("domain.UpdateUserStatus() status = %v, want %v", gotResp.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_domain_CloseAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	stmts := databaseStmts{
+		lockUsersByIds:                    &sqlx.Stmt{},
+		getSharedWalletMembersByUserId:    &sqlx.Stmt{},
+		lockSharedWalletMembersByWalletId: &sqlx.Stmt{},
+		lockSharedWalletById:              &sqlx.Stmt{},
+		lockBalancesByUserIds:             &sqlx.Stmt{},
+		getPocketsAmountByUserId:          &sqlx.Stmt{},
+		getCurrencyBalancesByUserId:       &sqlx.Stmt{},
+		grantBalanceByUserId:              &sqlx.Stmt{},
+		insertJournalEntry:                &sqlx.Stmt{},
+		insertPosting:                     &sqlx.Stmt{},
+		deductBalanceByUserId:             &sqlx.Stmt{},
+		insertHistory:                     &sqlx.Stmt{},
+		updateHistorySummaryById:          &sqlx.Stmt{},
+		updateUserStatusById:              &sqlx.Stmt{},
+		insertUserStatusChange:            &sqlx.Stmt{},
+	}
+	user := entity.User{
+		Id:       "id",
+		Username: "username",
+		Status:   int(enum.USER_STATUS_ACTIVE),
+	}
+	owner := entity.SharedWalletMember{
+		WalletId: "wid",
+		UserId:   "id",
+		Role:     int(enum.SHARED_WALLET_ROLE_OWNER),
+		Status:   int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE),
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req CloseAccountRequest
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantStatus int
+		wantErr    error
+		mock       func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_CLOSED),
+			wantErr:    nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUsersByIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					// getSharedWalletMembersByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id"}}).Return(nil),
+					// getPocketsAmountByUserId
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					// getCurrencyBalancesByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					// updateUserStatusById
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_STATUS_CLOSED), "reason", gomock.Any(), "id").Return(nil),
+					// insertUserStatusChange
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.USER_STATUS_ACTIVE), int(enum.USER_STATUS_CLOSED), "reason", "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success with sweep",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:        "id",
+					SweepToUserId: "toid",
+					Reason:        "reason",
+					ActorId:       "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_CLOSED),
+			wantErr:    nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUsersByIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{user, {Id: "toid", Status: int(enum.USER_STATUS_ACTIVE)}}).Return(nil),
+					// getSharedWalletMembersByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+					// getPocketsAmountByUserId
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					// getCurrencyBalancesByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertOutboxEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", string(enum.EVENT_TRANSFER_COMPLETED), enum.EVENT_VERSION, gomock.Any()).Return(nil),
+
+					// updateUserStatusById
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_STATUS_CLOSED), "reason", gomock.Any(), "id").Return(nil),
+					// insertUserStatusChange
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.USER_STATUS_ACTIVE), int(enum.USER_STATUS_CLOSED), "reason", "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 2)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "sent"), float64(10), "id").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "id").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error sweep recipient not active",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:        "id",
+					SweepToUserId: "toid",
+					Reason:        "reason",
+					ActorId:       "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr: AccountNotActiveError{
+				UserId: "toid",
+				Status: int(enum.USER_STATUS_FROZEN),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{user, {Id: "toid", Status: int(enum.USER_STATUS_FROZEN)}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+					// getPocketsAmountByUserId
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					// getCurrencyBalancesByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error sweep debit blocked",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:        "id",
+					SweepToUserId: "toid",
+					Reason:        "reason",
+					ActorId:       "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_DEBIT_BLOCKED),
+			wantErr: AccountNotActiveError{
+				UserId: "id",
+				Status: int(enum.USER_STATUS_DEBIT_BLOCKED),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_DEBIT_BLOCKED)}, {Id: "toid", Status: int(enum.USER_STATUS_ACTIVE)}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+					// getPocketsAmountByUserId
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					// getCurrencyBalancesByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error balance not zero",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr: BalanceNotZeroError{
+				Balance: 10,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+					// getPocketsAmountByUserId
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					// getCurrencyBalancesByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error held funds",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:        "id",
+					SweepToUserId: "toid",
+					Reason:        "reason",
+					ActorId:       "adminid",
+					Admin:         true,
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr: BalanceNotZeroError{
+				Balance: 10,
+				Held:    5,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{user, {Id: "toid", Status: int(enum.USER_STATUS_ACTIVE)}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10, HeldAmount: 5}}).Return(nil),
+					// getPocketsAmountByUserId
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					// getCurrencyBalancesByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error currency balances not zero",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr: BalanceNotZeroError{
+				Currencies: []string{"USD"},
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id"}}).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").SetArg(3, []entity.CurrencyBalance{{UserId: "id", Currency: "SGD"}, {UserId: "id", Currency: "USD", Amount: 10}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error sweep currency mismatch",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:        "id",
+					SweepToUserId: "toid",
+					Reason:        "reason",
+					ActorId:       "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr: CurrencyMismatchError{
+				Currency:   "IDR",
+				ToCurrency: "USD",
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_ACTIVE), Currency: "IDR"}, {Id: "toid", Status: int(enum.USER_STATUS_ACTIVE), Currency: "USD"}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.Balance{{UserId: "id", Amount: 10}}).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error last owner of a shared wallet with money",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr: SharedWalletLastOwnerError{
+				WalletIds: []string{"wid"},
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).SetArg(3, []entity.SharedWalletMember{owner}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner}).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, entity.SharedWallet{Id: "wid", Amount: 10}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success shared wallet with another owner",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_CLOSED),
+			wantErr:    nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).SetArg(3, []entity.SharedWalletMember{owner}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, {WalletId: "wid", UserId: "otherid", Role: int(enum.SHARED_WALLET_ROLE_OWNER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_STATUS_CLOSED), "reason", gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.USER_STATUS_ACTIVE), int(enum.USER_STATUS_CLOSED), "reason", "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error getSharedWalletMembersByUserId",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr:    fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error frozen",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_FROZEN),
+			wantErr: AccountNotActiveError{
+				UserId: "id",
+				Status: int(enum.USER_STATUS_FROZEN),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_FROZEN)}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error pockets not empty",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr: BalanceNotZeroError{
+				Pockets: 10,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id"}}).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").SetArg(3, money.Money(10)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success admin closes frozen",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "fraud",
+					ActorId: "adminid",
+					Admin:   true,
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_CLOSED),
+			wantErr:    nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_FROZEN)}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_STATUS_CLOSED), "fraud", gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.USER_STATUS_FROZEN), int(enum.USER_STATUS_CLOSED), "fraud", "adminid").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error already closed",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "adminid",
+					Admin:   true,
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_CLOSED),
+			wantErr: AccountNotActiveError{
+				UserId: "id",
+				Status: int(enum.USER_STATUS_CLOSED),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_CLOSED)}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "adminid",
+					Admin:   true,
+				},
+			},
+			wantStatus: 0,
+			wantErr:    sql.ErrNoRows,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error updateUserStatusById",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: int(enum.USER_STATUS_ACTIVE),
+			wantErr:    fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_STATUS_CLOSED), "reason", gomock.Any(), "id").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					Reason:  "reason",
+					ActorId: "id",
+				},
+			},
+			wantStatus: 0,
+			wantErr:    fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.CloseAccount(tt.args.ctx, tt.args.req)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.CloseAccount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp.Status != tt.wantStatus {
+				t.Errorf("domain.CloseAccount() status = %v, want %v", gotResp.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_domain_CreatePocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	lockedUntil := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	pocket := entity.Pocket{
+		Id:          "pid",
+		UserId:      "id",
+		Name:        "rent",
+		GoalAmount:  100,
+		LockedUntil: &lockedUntil,
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		pocket entity.Pocket
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertPocket: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				pocket: pocket,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "pid", "id", "rent", money.Money(100), &lockedUntil).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error name taken",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertPocket: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				pocket: pocket,
+			},
+			wantErr: ErrPocketNameTaken,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "pid", "id", "rent", money.Money(100), &lockedUntil).Return(&pq.Error{Code: "23505"}),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertPocket: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				pocket: pocket,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "pid", "id", "rent", money.Money(100), &lockedUntil).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.CreatePocket(tt.args.ctx, tt.args.pocket); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.CreatePocket() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetPocketsByUserId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	pockets := []entity.Pocket{
+		{Id: "pid", UserId: "id", Name: "rent", Amount: 10},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.Pocket
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPocketsByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: pockets,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").SetArg(2, pockets).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPocketsByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetPocketsByUserId(tt.args.ctx, tt.args.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetPocketsByUserId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetPocketsByUserId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_MovePocketBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	lockedUntil := time.Now().Add(time.Hour).UTC()
+	pocket := entity.Pocket{
+		Id:     "pid",
+		UserId: "id",
+		Name:   "rent",
+		Amount: 10,
+	}
+	lockedPocket := pocket
+	lockedPocket.LockedUntil = &lockedUntil
+
+	stmts := databaseStmts{
+		insertIdempotencyKey:        &sqlx.Stmt{},
+		lockUserStatusesByIds:       &sqlx.Stmt{},
+		lockBalancesByUserIds:       &sqlx.Stmt{},
+		lockPocketsByIds:            &sqlx.Stmt{},
+		insertJournalEntry:          &sqlx.Stmt{},
+		insertPosting:               &sqlx.Stmt{},
+		deductBalanceByUserId:       &sqlx.Stmt{},
+		grantBalanceByUserId:        &sqlx.Stmt{},
+		postPocketBalanceById:       &sqlx.Stmt{},
+		insertHistory:               &sqlx.Stmt{},
+		updateHistorySummaryById:    &sqlx.Stmt{},
+		updateHistorySummaryBuckets: &sqlx.Stmt{},
+	}
+
+	insertHistoryMocks := func(notes string) []any {
+		return []any{
+			mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id", money.Money(10), int(enum.POCKET), notes, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+			mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+			mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		}
+	}
+
+	invalidateHistoryMocks := func() []any {
+		return []any{
+			mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 4)).Return(int64(0), nil),
+			mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 4)).Return(nil),
+			mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+			mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+			mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+		}
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req MovePocketBalanceRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success from main wallet",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				calls := []any{
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 20, HeldAmount: 5}}).Return(nil),
+					// lockPocketsByIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "", "pid").SetArg(3, []entity.Pocket{pocket}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.POCKET_MOVE), "Move money from main wallet to pocket rent").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(-10)).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "id").Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "pocket:pid", money.Money(10)).Return(nil),
+					// postPocketBalanceById
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "pid").Return(nil),
+				}
+				calls = append(calls, insertHistoryMocks("Move money from main wallet to pocket rent")...)
+				calls = append(calls, mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil))
+				calls = append(calls, mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil), mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil))
+				calls = append(calls, invalidateHistoryMocks()...)
+				gomock.InOrder(calls...)
+			},
+		},
+		{
+			name: "success to main wallet",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:       "id",
+					FromPocketId: "pid",
+					Amount:       10,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				calls := []any{
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUserStatuses
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					// lockPocketsByIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "pid", "").SetArg(3, []entity.Pocket{pocket}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.POCKET_MOVE), "Move money from pocket rent to main wallet").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "pocket:pid", money.Money(-10)).Return(nil),
+					// postPocketBalanceById
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(-10), "pid").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(10)).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(10)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+				}
+				calls = append(calls, insertHistoryMocks("Move money from pocket rent to main wallet")...)
+				calls = append(calls, mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil))
+				calls = append(calls, mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil), mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil))
+				calls = append(calls, invalidateHistoryMocks()...)
+				gomock.InOrder(calls...)
+			},
+		},
+		{
+			name: "error pocket not found",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "otherpid",
+					Amount:     10,
+				},
+			},
+			wantErr: ErrPocketNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 20}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "", "otherpid").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error pocket locked",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:       "id",
+					FromPocketId: "pid",
+					Amount:       10,
+				},
+			},
+			wantErr: PocketLockedError{
+				PocketId:    "pid",
+				LockedUntil: lockedUntil,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "pid", "").SetArg(3, []entity.Pocket{lockedPocket}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insufficient pocket balance",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:       "id",
+					FromPocketId: "pid",
+					Amount:       15,
+				},
+			},
+			wantErr: InsufficientBalanceError{
+				UserId:  "id",
+				Balance: 10,
+				Amount:  15,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 20}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "pid", "").SetArg(3, []entity.Pocket{pocket}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error account not active",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+				},
+			},
+			wantErr: AccountNotActiveError{
+				UserId: "id",
+				Status: int(enum.USER_STATUS_FROZEN),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_FROZEN)}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error lockPocketsByIds",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+				},
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "", "pid").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insertIdempotencyKey",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+					IdempotencyKey: entity.IdempotencyKey{
+						UserId: "id",
+						Key:    "key",
+					},
+				},
+			},
+			wantErr: ErrIdempotencyKeyExists,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "key", gomock.Any(), gomock.Any(), gomock.Any()).Return(&pq.Error{Code: "23505"}),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.MovePocketBalance(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.MovePocketBalance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetCurrencyBalancesByUserId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	currencyBalances := []entity.CurrencyBalance{
+		{UserId: "id", Currency: "USD", Amount: 10},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.CurrencyBalance
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getCurrencyBalancesByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: currencyBalances,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").SetArg(2, currencyBalances).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt db",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getCurrencyBalancesByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetCurrencyBalancesByUserId(tt.args.ctx, tt.args.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetCurrencyBalancesByUserId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetCurrencyBalancesByUserId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_ConvertBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	user := entity.User{
+		Id:       "id",
+		Status:   int(enum.USER_STATUS_ACTIVE),
+		Currency: "IDR",
+	}
+	toUsd := Conversion{
+		FromCurrency: "IDR",
+		ToCurrency:   "USD",
+		ToAmount:     100,
+		Rate:         6154,
+	}
+	toIdr := Conversion{
+		FromCurrency: "USD",
+		ToCurrency:   "IDR",
+		ToAmount:     1625000,
+		Rate:         1625000000000,
+	}
+
+	stmts := databaseStmts{
+		insertIdempotencyKey:         &sqlx.Stmt{},
+		lockUserStatusesByIds:        &sqlx.Stmt{},
+		lockBalancesByUserIds:        &sqlx.Stmt{},
+		lockCurrencyBalancesByUserId: &sqlx.Stmt{},
+		insertJournalEntry:           &sqlx.Stmt{},
+		insertPosting:                &sqlx.Stmt{},
+		deductBalanceByUserId:        &sqlx.Stmt{},
+		grantBalanceByUserId:         &sqlx.Stmt{},
+		postCurrencyBalance:          &sqlx.Stmt{},
+		insertHistory:                &sqlx.Stmt{},
+		updateHistorySummaryById:     &sqlx.Stmt{},
+		updateHistorySummaryBuckets:  &sqlx.Stmt{},
+	}
+
+	insertHistoryMocks := func(amount money.Money, currency string, notes string) []any {
+		return []any{
+			mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id", amount, int(enum.CONVERSION), notes, gomock.Any(), gomock.Any(), currency).Return(nil),
+			mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+			mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		}
+	}
+
+	invalidateHistoryMocks := func() []any {
+		return []any{
+			mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 6)).Return(int64(0), nil),
+			mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 6)).Return(nil),
+			mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+			mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+			mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+		}
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req ConvertBalanceRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success from main currency",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:     "id",
+					Amount:     1625000,
+					Conversion: toUsd,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				notes := "Convert 1625 IDR to 0.10 USD at 0.00006154"
+				calls := []any{
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					// lockUsers
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					// lockBalancesByUserIds
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 2000000}}).Return(nil),
+					// lockCurrencyBalancesByUserId
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "IDR", "USD").Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.FX_CONVERSION), notes).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(-1625000)).Return(nil),
+					// deductBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(1625000), "id").Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "system:fx:IDR", money.Money(1625000)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "system:fx:USD", money.Money(-100)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "currency:id:USD", money.Money(100)).Return(nil),
+					// postCurrencyBalance
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "USD", money.Money(100)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+				}
+				calls = append(calls, insertHistoryMocks(1625000, "IDR", notes)...)
+				calls = append(calls, mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil))
+				calls = append(calls, mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil), mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil))
+				calls = append(calls, invalidateHistoryMocks()...)
+				gomock.InOrder(calls...)
+			},
+		},
+		{
+			name: "success to main currency",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:     "id",
+					Amount:     100,
+					Conversion: toIdr,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				notes := "Convert 0.10 USD to 1625 IDR at 16250"
+				calls := []any{
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "USD", "IDR").SetArg(3, []entity.CurrencyBalance{{UserId: "id", Currency: "USD", Amount: 100}}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.FX_CONVERSION), notes).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "currency:id:USD", money.Money(-100)).Return(nil),
+					// postCurrencyBalance
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "USD", money.Money(-100)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "system:fx:USD", money.Money(100)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "system:fx:IDR", money.Money(-1625000)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(1625000)).Return(nil),
+					// grantBalanceByUserId
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(1625000)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+				}
+				calls = append(calls, insertHistoryMocks(100, "USD", notes)...)
+				calls = append(calls, mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil))
+				calls = append(calls, mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil), mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil))
+				calls = append(calls, invalidateHistoryMocks()...)
+				gomock.InOrder(calls...)
+			},
+		},
+		{
+			name: "error insufficient balance held",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:     "id",
+					Amount:     1625000,
+					Conversion: toUsd,
+				},
+			},
+			wantErr: InsufficientBalanceError{
+				UserId:  "id",
+				Balance: 1000000,
+				Amount:  1625000,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 2000000, HeldAmount: 1000000}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "IDR", "USD").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insufficient currency balance",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:     "id",
+					Amount:     100,
+					Conversion: toIdr,
+				},
+			},
+			wantErr: InsufficientBalanceError{
+				UserId: "id",
+				Amount: 100,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 2000000}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "USD", "IDR").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:     "id",
+					Amount:     100,
+					Conversion: toIdr,
+				},
+			},
+			wantErr: sql.ErrNoRows,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error account not active",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:     "id",
+					Amount:     100,
+					Conversion: toIdr,
+				},
+			},
+			wantErr: AccountNotActiveError{
+				UserId: "id",
+				Status: int(enum.USER_STATUS_DEBIT_BLOCKED),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_DEBIT_BLOCKED)}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error lockCurrencyBalancesByUserId",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:     "id",
+					Amount:     100,
+					Conversion: toIdr,
+				},
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{user}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "USD", "IDR").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insertIdempotencyKey",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:     "id",
+					Amount:     100,
+					Conversion: toIdr,
+					IdempotencyKey: entity.IdempotencyKey{
+						UserId: "id",
+						Key:    "key",
+					},
+				},
+			},
+			wantErr: ErrIdempotencyKeyExists,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "id", "key", gomock.Any(), gomock.Any(), gomock.Any()).Return(&pq.Error{Code: "23505"}),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.ConvertBalance(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.ConvertBalance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_CreateSharedWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	wallet := entity.SharedWallet{
+		Id:        "wid",
+		Name:      "family",
+		CreatedBy: "id",
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		wallet entity.SharedWallet
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertSharedWallet:       &sqlx.Stmt{},
+					upsertSharedWalletMember: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				wallet: wallet,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "wid", "family", "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "wid", "id", int(enum.SHARED_WALLET_ROLE_OWNER), 0, int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE), "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insert owner",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertSharedWallet:       &sqlx.Stmt{},
+					upsertSharedWalletMember: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				wallet: wallet,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "wid", "family", "id").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "wid", "id", int(enum.SHARED_WALLET_ROLE_OWNER), 0, int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE), "id").Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db: mockDatabase,
+			},
+			args: args{
+				ctx:    context.Background(),
+				wallet: wallet,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.CreateSharedWallet(tt.args.ctx, tt.args.wallet); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.CreateSharedWallet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_InviteSharedWalletMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	owner := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_OWNER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+	viewer := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_VIEWER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+	invited := entity.SharedWalletMember{WalletId: "wid", UserId: "memberid", Role: int(enum.SHARED_WALLET_ROLE_VIEWER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED)}
+	left := entity.SharedWalletMember{WalletId: "wid", UserId: "memberid", Role: int(enum.SHARED_WALLET_ROLE_VIEWER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_LEFT)}
+
+	stmts := databaseStmts{
+		lockSharedWalletMembersByWalletId: &sqlx.Stmt{},
+		upsertSharedWalletMember:          &sqlx.Stmt{},
+	}
+	req := InviteSharedWalletMemberRequest{
+		WalletId:   "wid",
+		ActorId:    "id",
+		UserId:     "memberid",
+		Role:       enum.SHARED_WALLET_ROLE_SPENDER,
+		SpendLimit: 100,
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req InviteSharedWalletMemberRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "wid", "memberid", int(enum.SHARED_WALLET_ROLE_SPENDER), money.Money(100), int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success invite again after leaving",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, left}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "wid", "memberid", int(enum.SHARED_WALLET_ROLE_SPENDER), money.Money(100), int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error wallet not found",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: ErrSharedWalletNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error actor not owner",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: ErrSharedWalletForbidden,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{viewer}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error already invited",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: ErrSharedWalletMemberExists,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, invited}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.InviteSharedWalletMember(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.InviteSharedWalletMember() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_RespondSharedWalletInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	invited := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_VIEWER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED)}
+	active := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_VIEWER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+
+	stmts := databaseStmts{
+		lockSharedWalletMembersByWalletId: &sqlx.Stmt{},
+		updateSharedWalletMemberStatus:    &sqlx.Stmt{},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req RespondSharedWalletInvitationRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success accept",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RespondSharedWalletInvitationRequest{
+					WalletId: "wid",
+					UserId:   "id",
+					Accept:   true,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{invited}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE), "wid", "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success decline",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RespondSharedWalletInvitationRequest{
+					WalletId: "wid",
+					UserId:   "id",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{invited}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.SHARED_WALLET_MEMBER_STATUS_DECLINED), "wid", "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not invited",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RespondSharedWalletInvitationRequest{
+					WalletId: "wid",
+					UserId:   "id",
+					Accept:   true,
+				},
+			},
+			wantErr: ErrSharedWalletInvitationNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{active}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.RespondSharedWalletInvitation(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.RespondSharedWalletInvitation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_RemoveSharedWalletMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	owner := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_OWNER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+	otherOwner := entity.SharedWalletMember{WalletId: "wid", UserId: "memberid", Role: int(enum.SHARED_WALLET_ROLE_OWNER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+	spender := entity.SharedWalletMember{WalletId: "wid", UserId: "memberid", Role: int(enum.SHARED_WALLET_ROLE_SPENDER), SpendLimit: 100, Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+	removed := entity.SharedWalletMember{WalletId: "wid", UserId: "memberid", Role: int(enum.SHARED_WALLET_ROLE_SPENDER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_REMOVED)}
+
+	stmts := databaseStmts{
+		lockSharedWalletMembersByWalletId: &sqlx.Stmt{},
+		updateSharedWalletMemberStatus:    &sqlx.Stmt{},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req RemoveSharedWalletMemberRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success remove",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveSharedWalletMemberRequest{
+					WalletId: "wid",
+					ActorId:  "id",
+					UserId:   "memberid",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, spender}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.SHARED_WALLET_MEMBER_STATUS_REMOVED), "wid", "memberid").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success leave",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveSharedWalletMemberRequest{
+					WalletId: "wid",
+					ActorId:  "memberid",
+					UserId:   "memberid",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, spender}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.SHARED_WALLET_MEMBER_STATUS_LEFT), "wid", "memberid").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success owner leaves with another owner",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveSharedWalletMemberRequest{
+					WalletId: "wid",
+					ActorId:  "id",
+					UserId:   "id",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, otherOwner}).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.SHARED_WALLET_MEMBER_STATUS_LEFT), "wid", "id").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error last owner leaves",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveSharedWalletMemberRequest{
+					WalletId: "wid",
+					ActorId:  "id",
+					UserId:   "id",
+				},
+			},
+			wantErr: ErrSharedWalletLastOwner,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, spender}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error spender removes owner",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveSharedWalletMemberRequest{
+					WalletId: "wid",
+					ActorId:  "memberid",
+					UserId:   "id",
+				},
+			},
+			wantErr: ErrSharedWalletForbidden,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, spender}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error member already removed",
+			fields: fields{
+				db:    mockDatabase,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveSharedWalletMemberRequest{
+					WalletId: "wid",
+					ActorId:  "id",
+					UserId:   "memberid",
+				},
+			},
+			wantErr: ErrSharedWalletMemberNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{owner, removed}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.RemoveSharedWalletMember(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.RemoveSharedWalletMember() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_DepositSharedWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	spender := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_SPENDER), SpendLimit: 5, Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+	viewer := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_VIEWER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+
+	stmts := databaseStmts{
+		lockUserStatusesByIds:             &sqlx.Stmt{},
+		lockSharedWalletMembersByWalletId: &sqlx.Stmt{},
+		lockBalancesByUserIds:             &sqlx.Stmt{},
+		lockSharedWalletById:              &sqlx.Stmt{},
+		insertJournalEntry:                &sqlx.Stmt{},
+		insertPosting:                     &sqlx.Stmt{},
+		deductBalanceByUserId:             &sqlx.Stmt{},
+		postSharedWalletBalanceById:       &sqlx.Stmt{},
+		insertHistory:                     &sqlx.Stmt{},
+		updateHistorySummaryById:          &sqlx.Stmt{},
+		updateHistorySummaryBuckets:       &sqlx.Stmt{},
+		insertSharedWalletHistory:         &sqlx.Stmt{},
+	}
+	req := DepositSharedWalletRequest{
+		WalletId: "wid",
+		UserId:   "id",
+		Amount:   10,
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req DepositSharedWalletRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{spender}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 20}}).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, entity.SharedWallet{Id: "wid", Name: "family"}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.SHARED_WALLET_DEPOSIT), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", money.Money(-10)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "id").Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wallet:wid", money.Money(10)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(10), "wid").Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id", money.Money(10), int(enum.SHARED_WALLET), "Deposit money to wallet family", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertSharedWalletHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid", "id", "id", money.Money(10), int(enum.CREDIT), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 5)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "id", 5)).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "id")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "id")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error viewer",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: ErrSharedWalletForbidden,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{viewer}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error currency mismatch",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: CurrencyMismatchError{
+				Currency:   "USD",
+				ToCurrency: "IDR",
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.User{{Id: "id", Status: int(enum.USER_STATUS_ACTIVE), Currency: "USD"}}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{spender}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 20}}).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, entity.SharedWallet{Id: "wid", Name: "family", Currency: "IDR"}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insufficient balance",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: InsufficientBalanceError{
+				UserId:  "id",
+				Balance: 5,
+				Amount:  10,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{spender}).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "id").SetArg(3, []entity.Balance{{UserId: "id", Amount: 5}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.DepositSharedWallet(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.DepositSharedWallet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_TransferFromSharedWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+	mockCache := lrucache.NewMockLRUCacheItf(ctrl)
+
+	spender := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_SPENDER), SpendLimit: 10, Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)}
+	left := entity.SharedWalletMember{WalletId: "wid", UserId: "id", Role: int(enum.SHARED_WALLET_ROLE_OWNER), Status: int(enum.SHARED_WALLET_MEMBER_STATUS_LEFT)}
+
+	stmts := databaseStmts{
+		lockUserStatusesByIds:             &sqlx.Stmt{},
+		lockSharedWalletMembersByWalletId: &sqlx.Stmt{},
+		lockSharedWalletById:              &sqlx.Stmt{},
+		insertJournalEntry:                &sqlx.Stmt{},
+		insertPosting:                     &sqlx.Stmt{},
+		grantBalanceByUserId:              &sqlx.Stmt{},
+		postSharedWalletBalanceById:       &sqlx.Stmt{},
+		insertHistory:                     &sqlx.Stmt{},
+		updateHistorySummaryById:          &sqlx.Stmt{},
+		updateHistorySummaryBuckets:       &sqlx.Stmt{},
+		insertSharedWalletHistory:         &sqlx.Stmt{},
+	}
+	req := TransferFromSharedWalletRequest{
+		WalletId: "wid",
+		UserId:   "id",
+		ToUserId: "toid",
+		Amount:   10,
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req TransferFromSharedWalletRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{spender}).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, entity.SharedWallet{Id: "wid", Name: "family", Amount: 20}).Return(nil),
+
+					// postJournalEntry
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int(enum.TRANSFER), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wallet:wid", money.Money(-10)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), money.Money(-10), "wid").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", money.Money(10)).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "toid", money.Money(10)).Return(nil),
+					// insertBalanceChangedEvent
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "toid", "id", money.Money(10), int(enum.CREDIT), "Receive money from wallet family", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+
+					// insertSharedWalletHistory
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid", "id", "toid", money.Money(10), int(enum.DEBIT), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetBalanceByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetHistorySummaryByUserIdAndType, "toid", 1)).Return(nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "received"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().ZIncrBy(gomock.Any(), fmt.Sprintf(cacheKeyLeaderboard, "all"), float64(10), "toid").Return(float64(10), nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(int64(0), nil),
+					mockCache.EXPECT().Invalidate(gomock.Any(), fmt.Sprintf(cacheKeyGetLatestHistoryByUserId, "toid")).Return(nil),
+					mockRedis.EXPECT().Incr(gomock.Any(), fmt.Sprintf(cacheKeyHistoryVersionByUserId, "toid")).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error spend limit exceeded",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferFromSharedWalletRequest{
+					WalletId: "wid",
+					UserId:   "id",
+					ToUserId: "toid",
+					Amount:   11,
+				},
+			},
+			wantErr: SpendLimitExceededError{
+				UserId: "id",
+				Limit:  10,
+				Amount: 11,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{spender}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error member left",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: ErrSharedWalletNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{left}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insufficient wallet balance",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: InsufficientBalanceError{
+				UserId:  "id",
+				Balance: 5,
+				Amount:  10,
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, []entity.SharedWalletMember{spender}).Return(nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "wid").SetArg(3, entity.SharedWallet{Id: "wid", Name: "family", Amount: 5}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error recipient not active",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				cache: mockCache,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: AccountNotActiveError{
+				UserId: "toid",
+				Status: int(enum.USER_STATUS_FROZEN),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "id", "toid").SetArg(3, []entity.User{{Id: "toid", Status: int(enum.USER_STATUS_FROZEN)}}).Return(nil),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:      tt.fields.db,
+				redis:   tt.fields.redis,
+				cache:   tt.fields.cache,
+				loaders: newLoaders(tt.fields.redis, tt.fields.cache),
+				stmts:   tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.TransferFromSharedWallet(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.TransferFromSharedWallet() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -2,15 +2,56 @@ package domainbalance
 
 import (
 	"context"
+	"time"
 
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 )
 
 type DomainItf interface {
 	GetBalanceByUserId(ctx context.Context, userId string) (resp entity.Balance, err error)
-	GrantBalanceByUserId(ctx context.Context, balance entity.Balance) (err error)
+	GrantBalanceByUserId(ctx context.Context, req GrantBalanceByUserIdRequest) (err error)
 	DisburmentBalance(ctx context.Context, req DisburmentBalanceRequest) (err error)
+	GetFee(ctx context.Context, req GetFeeRequest) (resp money.Money, err error)
+
+	UpdateUserStatus(ctx context.Context, req UpdateUserStatusRequest) (resp entity.User, err error)
+	CloseAccount(ctx context.Context, req CloseAccountRequest) (resp entity.User, err error)
+
+	CreatePocket(ctx context.Context, pocket entity.Pocket) (err error)
+	GetPocketsByUserId(ctx context.Context, userId string) (resp []entity.Pocket, err error)
+	MovePocketBalance(ctx context.Context, req MovePocketBalanceRequest) (err error)
+
+	GetCurrencyBalancesByUserId(ctx context.Context, userId string) (resp []entity.CurrencyBalance, err error)
+	ConvertBalance(ctx context.Context, req ConvertBalanceRequest) (err error)
+
+	CreateSharedWallet(ctx context.Context, wallet entity.SharedWallet) (err error)
+	GetSharedWalletById(ctx context.Context, id string) (resp entity.SharedWallet, err error)
+	GetSharedWalletMembersByWalletId(ctx context.Context, walletId string) (resp []entity.SharedWalletMember, err error)
+	GetSharedWalletMembersByUserId(ctx context.Context, userId string) (resp []entity.SharedWalletMember, err error)
+	GetSharedWalletHistoriesByWalletId(ctx context.Context, walletId string, limit int) (resp []entity.SharedWalletHistory, err error)
+	InviteSharedWalletMember(ctx context.Context, req InviteSharedWalletMemberRequest) (err error)
+	RespondSharedWalletInvitation(ctx context.Context, req RespondSharedWalletInvitationRequest) (err error)
+	RemoveSharedWalletMember(ctx context.Context, req RemoveSharedWalletMemberRequest) (err error)
+	DepositSharedWallet(ctx context.Context, req DepositSharedWalletRequest) (err error)
+	TransferFromSharedWallet(ctx context.Context, req TransferFromSharedWalletRequest) (err error)
+
+	GetTransferById(ctx context.Context, id string) (resp entity.Transfer, err error)
+	RefundTransfer(ctx context.Context, req RefundTransferRequest) (err error)
+
+	GetHoldById(ctx context.Context, id string) (resp entity.Hold, err error)
+	CreateHold(ctx context.Context, req CreateHoldRequest) (err error)
+	CaptureHold(ctx context.Context, req CaptureHoldRequest) (err error)
+	VoidHold(ctx context.Context, req VoidHoldRequest) (err error)
 
 	GetLatestHistoryByUserId(ctx context.Context, userId string) (resp []entity.History, err error)
+	GetHistoriesByUserId(ctx context.Context, req GetHistoriesByUserIdRequest) (resp []entity.History, err error)
+	GetHistoriesByJournalEntryId(ctx context.Context, journalEntryId string) (resp []entity.History, err error)
+	GetTopHistoriesByUserId(ctx context.Context, userId string, from time.Time, to time.Time) (resp []entity.History, err error)
 	GetHistorySummaryByUserIdAndType(ctx context.Context, userId string, historyType int) (resp []entity.HistorySummary, err error)
+	GetLeaderboard(ctx context.Context, req GetLeaderboardRequest) (resp []entity.LeaderboardEntry, err error)
+
+	GetJournalEntryById(ctx context.Context, id string) (resp entity.JournalEntry, err error)
+	GetPostingsByAccountId(ctx context.Context, req GetPostingsByAccountIdRequest) (resp []entity.Posting, err error)
+
+	GetIdempotencyKey(ctx context.Context, userId string, key string) (resp entity.IdempotencyKey, err error)
 }
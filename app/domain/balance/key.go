@@ -3,11 +3,21 @@ package domainbalance
 const (
 	cacheKeyGetBalanceByUserId               = "domain:balance:user_id:%s"
 	cacheKeyGetLatestHistoryByUserId         = "domain:balance:history:user_id:%s"
+	cacheKeyGetHistoriesByUserId             = "domain:balance:histories:user_id:%s:version:%d:filter:%s"
+	cacheKeyHistoryVersionByUserId           = "domain:balance:history_version:user_id:%s"
 	cacheKeyGetHistorySummaryByUserIdAndType = "domain:balance:history_summary:user_id:%s:type:%d"
+	cacheKeyGetTopHistoriesByUserId          = "domain:balance:top_histories:user_id:%s:version:%d:from:%d:to:%d"
+	cacheKeyGetIdempotencyKey                = "domain:balance:idempotency_key:user_id:%s:key:%s"
+	cacheKeyGetJournalEntryById              = "domain:balance:journal_entry:id:%s"
+	cacheKeyLeaderboard                      = "domain:balance:leaderboard:direction:%s"
+	cacheKeyLeaderboardBuilt                 = "domain:balance:leaderboard:built"
+	cacheKeyGetWindowedLeaderboard           = "domain:balance:leaderboard:direction:%s:period:%s:from:%s:to:%s:offset:%d:limit:%d"
 )
 
 const (
-	singleFlightKeyGetBalanceByUserId               = "sf:domain:balance:user_id:%s"
-	singleFlightKeyGetLatestHistoryByUserId         = "sf:domain:balance:history:user_id:%s"
-	singleFlightKeyGetHistorySummaryByUserIdAndType = "sf:domain:balance:history_summary:user_id:%s:type:%d"
+	singleFlightKeyGetHistoriesByUserId    = "sf:domain:balance:histories:user_id:%s:version:%d:filter:%s"
+	singleFlightKeyGetTopHistoriesByUserId = "sf:domain:balance:top_histories:user_id:%s:version:%d:from:%d:to:%d"
+	singleFlightKeyGetJournalEntryById     = "sf:domain:balance:journal_entry:id:%s"
+	singleFlightKeyRebuildLeaderboard      = "sf:domain:balance:leaderboard:rebuild"
+	singleFlightKeyGetWindowedLeaderboard  = "sf:domain:balance:leaderboard:direction:%s:period:%s:from:%s:to:%s:offset:%d:limit:%d"
 )
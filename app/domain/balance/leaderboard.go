@@ -0,0 +1,198 @@
+package domainbalance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+const (
+	// The leaderboard is rebuilt from history_summaries once per interval to drop any drift
+	// caused by increments lost while Redis was unavailable.
+	leaderboardRebuildInterval = time.Hour * 24
+
+	leaderboardWindowCacheTTL = time.Minute
+)
+
+// leaderboardDirections maps every leaderboard to the history type it ranks, 0 ranks both.
+var leaderboardDirections = []struct {
+	direction   enum.LeaderboardDirection
+	historyType int
+}{
+	{direction: enum.LEADERBOARD_SENT, historyType: int(enum.DEBIT)},
+	{direction: enum.LEADERBOARD_RECEIVED, historyType: int(enum.CREDIT)},
+	{direction: enum.LEADERBOARD_ALL, historyType: 0},
+}
+
+// updateLeaderboard ranks a committed history summary the same way rebuildLeaderboard does. It is only called
+// after commit so a rolled back transfer is never counted, and failures are only logged since the next rebuild
+// fixes the scores.
+func (d domain) updateLeaderboard(ctx context.Context, historySummary entity.HistorySummary) {
+	if historySummary.UserId == historySummary.TargetUserId {
+		return
+	}
+
+	for _, leaderboard := range leaderboardDirections {
+		if leaderboard.historyType != 0 && leaderboard.historyType != historySummary.Type {
+			continue
+		}
+
+		_, err := d.redis.ZIncrBy(ctx, fmt.Sprintf(cacheKeyLeaderboard, leaderboard.direction), float64(historySummary.Amount), historySummary.UserId)
+		if err != nil {
+			log.Errorln("updateLeaderboard.ZIncrBy", err)
+		}
+	}
+}
+
+func (d domain) GetLeaderboard(ctx context.Context, req GetLeaderboardRequest) (resp []entity.LeaderboardEntry, err error) {
+	if !req.Window.IsZero() {
+		return d.getWindowedLeaderboard(ctx, req)
+	}
+
+	err = d.ensureLeaderboard(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	members, err := d.redis.ZRevRangeWithScores(ctx, fmt.Sprintf(cacheKeyLeaderboard, req.Direction), req.Offset, req.Offset+req.Limit-1)
+	if err != nil {
+		return resp, err
+	}
+
+	resp = make([]entity.LeaderboardEntry, len(members))
+	for i, member := range members {
+		resp[i] = entity.LeaderboardEntry{
+			UserId: member.Member,
+			Amount: money.Money(math.Round(member.Score)),
+		}
+	}
+
+	return resp, nil
+}
+
+// getWindowedLeaderboard ranks users from the history summary buckets of the window. Windows are served from
+// Postgres and only cached briefly since the current buckets change with every transfer.
+func (d domain) getWindowedLeaderboard(ctx context.Context, req GetLeaderboardRequest) (resp []entity.LeaderboardEntry, err error) {
+	var historyType interface{}
+	for _, leaderboard := range leaderboardDirections {
+		if leaderboard.direction == req.Direction && leaderboard.historyType != 0 {
+			historyType = leaderboard.historyType
+		}
+	}
+
+	from, to := req.Window.From.Format(time.DateOnly), req.Window.To.Format(time.DateOnly)
+
+	entries, err, _ := d.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyGetWindowedLeaderboard, req.Direction, req.Window.Period, from, to, req.Offset, req.Limit), func() (interface{}, error) {
+		var resp []entity.LeaderboardEntry
+		entries, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetWindowedLeaderboard, req.Direction, req.Window.Period, from, to, req.Offset, req.Limit), leaderboardWindowCacheTTL, func() (interface{}, error) {
+			var respRedis []entity.LeaderboardEntry
+			entriesStr, err := d.redis.Fetch(ctx, fmt.Sprintf(cacheKeyGetWindowedLeaderboard, req.Direction, req.Window.Period, from, to, req.Offset, req.Limit), leaderboardWindowCacheTTL, func() (interface{}, error) {
+				var entry []entity.LeaderboardEntry
+				err := d.db.SelectContextStmt(ctx, d.stmts.getWindowedLeaderboard, &entry, req.Window.Period, from, to, historyType, req.Offset, req.Limit)
+				if err != nil {
+					return entry, err
+				}
+
+				return entry, nil
+			})
+			if err != nil {
+				return respRedis, err
+			}
+
+			err = jsoniter.UnmarshalFromString(entriesStr, &respRedis)
+			if err != nil {
+				return respRedis, err
+			}
+
+			return respRedis, nil
+		})
+		if err != nil {
+			return resp, err
+		}
+
+		return entries.Value().([]entity.LeaderboardEntry), nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	return entries.([]entity.LeaderboardEntry), nil
+}
+
+// ensureLeaderboard rebuilds the leaderboards when they were never built or the rebuild interval passed.
+func (d domain) ensureLeaderboard(ctx context.Context) (err error) {
+	_, err = d.redis.Get(ctx, cacheKeyLeaderboardBuilt)
+	if err == nil {
+		return nil
+	}
+	if !redis.IsNil(err) {
+		return err
+	}
+
+	_, err, _ = d.singleflight.DoSingleFlight(ctx, singleFlightKeyRebuildLeaderboard, func() (interface{}, error) {
+		return nil, d.rebuildLeaderboard(ctx)
+	})
+
+	return err
+}
+
+// rebuildLeaderboard recomputes every leaderboard from history_summaries into a temporary key and
+// renames it over the live one, so readers never see a partially built leaderboard.
+func (d domain) rebuildLeaderboard(ctx context.Context) (err error) {
+	for _, leaderboard := range leaderboardDirections {
+		var historyType interface{}
+		if leaderboard.historyType != 0 {
+			historyType = leaderboard.historyType
+		}
+
+		var entries []entity.LeaderboardEntry
+		err = d.db.SelectContextStmt(ctx, d.stmts.getLeaderboard, &entries, historyType)
+		if err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf(cacheKeyLeaderboard, leaderboard.direction)
+		if len(entries) == 0 {
+			_, err = d.redis.Delete(ctx, key)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		members := make([]redis.Z, len(entries))
+		for i, entry := range entries {
+			members[i] = redis.Z{
+				Member: entry.UserId,
+				Score:  float64(entry.Amount),
+			}
+		}
+
+		tmpKey := key + ":rebuild:" + uuid.NewString()
+		_, err = d.redis.ZAdd(ctx, tmpKey, members...)
+		if err != nil {
+			return err
+		}
+
+		_, err = d.redis.Rename(ctx, tmpKey, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = d.redis.SetEx(ctx, cacheKeyLeaderboardBuilt, 1, leaderboardRebuildInterval)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -12,8 +12,10 @@ package domainbalance
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/kevinsudut/wallet-system/app/entity"
+	money "github.com/kevinsudut/wallet-system/pkg/helper/money"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,6 +42,105 @@ func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
 	return m.recorder
 }
 
+// CaptureHold mocks base method.
+func (m *MockDomainItf) CaptureHold(ctx context.Context, req CaptureHoldRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockDomainItfMockRecorder) CaptureHold(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockDomainItf)(nil).CaptureHold), ctx, req)
+}
+
+// CloseAccount mocks base method.
+func (m *MockDomainItf) CloseAccount(ctx context.Context, req CloseAccountRequest) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccount", ctx, req)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockDomainItfMockRecorder) CloseAccount(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockDomainItf)(nil).CloseAccount), ctx, req)
+}
+
+// ConvertBalance mocks base method.
+func (m *MockDomainItf) ConvertBalance(ctx context.Context, req ConvertBalanceRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertBalance", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConvertBalance indicates an expected call of ConvertBalance.
+func (mr *MockDomainItfMockRecorder) ConvertBalance(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertBalance", reflect.TypeOf((*MockDomainItf)(nil).ConvertBalance), ctx, req)
+}
+
+// CreateHold mocks base method.
+func (m *MockDomainItf) CreateHold(ctx context.Context, req CreateHoldRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockDomainItfMockRecorder) CreateHold(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockDomainItf)(nil).CreateHold), ctx, req)
+}
+
+// CreatePocket mocks base method.
+func (m *MockDomainItf) CreatePocket(ctx context.Context, pocket entity.Pocket) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePocket", ctx, pocket)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePocket indicates an expected call of CreatePocket.
+func (mr *MockDomainItfMockRecorder) CreatePocket(ctx, pocket any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePocket", reflect.TypeOf((*MockDomainItf)(nil).CreatePocket), ctx, pocket)
+}
+
+// CreateSharedWallet mocks base method.
+func (m *MockDomainItf) CreateSharedWallet(ctx context.Context, wallet entity.SharedWallet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSharedWallet", ctx, wallet)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSharedWallet indicates an expected call of CreateSharedWallet.
+func (mr *MockDomainItfMockRecorder) CreateSharedWallet(ctx, wallet any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSharedWallet", reflect.TypeOf((*MockDomainItf)(nil).CreateSharedWallet), ctx, wallet)
+}
+
+// DepositSharedWallet mocks base method.
+func (m *MockDomainItf) DepositSharedWallet(ctx context.Context, req DepositSharedWalletRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DepositSharedWallet", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DepositSharedWallet indicates an expected call of DepositSharedWallet.
+func (mr *MockDomainItfMockRecorder) DepositSharedWallet(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DepositSharedWallet", reflect.TypeOf((*MockDomainItf)(nil).DepositSharedWallet), ctx, req)
+}
+
 // DisburmentBalance mocks base method.
 func (m *MockDomainItf) DisburmentBalance(ctx context.Context, req DisburmentBalanceRequest) error {
 	m.ctrl.T.Helper()
@@ -69,6 +170,66 @@ func (mr *MockDomainItfMockRecorder) GetBalanceByUserId(ctx, userId any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetBalanceByUserId), ctx, userId)
 }
 
+// GetCurrencyBalancesByUserId mocks base method.
+func (m *MockDomainItf) GetCurrencyBalancesByUserId(ctx context.Context, userId string) ([]entity.CurrencyBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyBalancesByUserId", ctx, userId)
+	ret0, _ := ret[0].([]entity.CurrencyBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyBalancesByUserId indicates an expected call of GetCurrencyBalancesByUserId.
+func (mr *MockDomainItfMockRecorder) GetCurrencyBalancesByUserId(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyBalancesByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetCurrencyBalancesByUserId), ctx, userId)
+}
+
+// GetFee mocks base method.
+func (m *MockDomainItf) GetFee(ctx context.Context, req GetFeeRequest) (money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFee", ctx, req)
+	ret0, _ := ret[0].(money.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFee indicates an expected call of GetFee.
+func (mr *MockDomainItfMockRecorder) GetFee(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFee", reflect.TypeOf((*MockDomainItf)(nil).GetFee), ctx, req)
+}
+
+// GetHistoriesByJournalEntryId mocks base method.
+func (m *MockDomainItf) GetHistoriesByJournalEntryId(ctx context.Context, journalEntryId string) ([]entity.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistoriesByJournalEntryId", ctx, journalEntryId)
+	ret0, _ := ret[0].([]entity.History)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistoriesByJournalEntryId indicates an expected call of GetHistoriesByJournalEntryId.
+func (mr *MockDomainItfMockRecorder) GetHistoriesByJournalEntryId(ctx, journalEntryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoriesByJournalEntryId", reflect.TypeOf((*MockDomainItf)(nil).GetHistoriesByJournalEntryId), ctx, journalEntryId)
+}
+
+// GetHistoriesByUserId mocks base method.
+func (m *MockDomainItf) GetHistoriesByUserId(ctx context.Context, req GetHistoriesByUserIdRequest) ([]entity.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistoriesByUserId", ctx, req)
+	ret0, _ := ret[0].([]entity.History)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistoriesByUserId indicates an expected call of GetHistoriesByUserId.
+func (mr *MockDomainItfMockRecorder) GetHistoriesByUserId(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoriesByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetHistoriesByUserId), ctx, req)
+}
+
 // GetHistorySummaryByUserIdAndType mocks base method.
 func (m *MockDomainItf) GetHistorySummaryByUserIdAndType(ctx context.Context, userId string, historyType int) ([]entity.HistorySummary, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +245,51 @@ func (mr *MockDomainItfMockRecorder) GetHistorySummaryByUserIdAndType(ctx, userI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistorySummaryByUserIdAndType", reflect.TypeOf((*MockDomainItf)(nil).GetHistorySummaryByUserIdAndType), ctx, userId, historyType)
 }
 
+// GetHoldById mocks base method.
+func (m *MockDomainItf) GetHoldById(ctx context.Context, id string) (entity.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHoldById", ctx, id)
+	ret0, _ := ret[0].(entity.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHoldById indicates an expected call of GetHoldById.
+func (mr *MockDomainItfMockRecorder) GetHoldById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHoldById", reflect.TypeOf((*MockDomainItf)(nil).GetHoldById), ctx, id)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockDomainItf) GetIdempotencyKey(ctx context.Context, userId, key string) (entity.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, userId, key)
+	ret0, _ := ret[0].(entity.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockDomainItfMockRecorder) GetIdempotencyKey(ctx, userId, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockDomainItf)(nil).GetIdempotencyKey), ctx, userId, key)
+}
+
+// GetJournalEntryById mocks base method.
+func (m *MockDomainItf) GetJournalEntryById(ctx context.Context, id string) (entity.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJournalEntryById", ctx, id)
+	ret0, _ := ret[0].(entity.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJournalEntryById indicates an expected call of GetJournalEntryById.
+func (mr *MockDomainItfMockRecorder) GetJournalEntryById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJournalEntryById", reflect.TypeOf((*MockDomainItf)(nil).GetJournalEntryById), ctx, id)
+}
+
 // GetLatestHistoryByUserId mocks base method.
 func (m *MockDomainItf) GetLatestHistoryByUserId(ctx context.Context, userId string) ([]entity.History, error) {
 	m.ctrl.T.Helper()
@@ -99,16 +305,264 @@ func (mr *MockDomainItfMockRecorder) GetLatestHistoryByUserId(ctx, userId any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestHistoryByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetLatestHistoryByUserId), ctx, userId)
 }
 
+// GetLeaderboard mocks base method.
+func (m *MockDomainItf) GetLeaderboard(ctx context.Context, req GetLeaderboardRequest) ([]entity.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboard", ctx, req)
+	ret0, _ := ret[0].([]entity.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboard indicates an expected call of GetLeaderboard.
+func (mr *MockDomainItfMockRecorder) GetLeaderboard(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboard", reflect.TypeOf((*MockDomainItf)(nil).GetLeaderboard), ctx, req)
+}
+
+// GetPocketsByUserId mocks base method.
+func (m *MockDomainItf) GetPocketsByUserId(ctx context.Context, userId string) ([]entity.Pocket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPocketsByUserId", ctx, userId)
+	ret0, _ := ret[0].([]entity.Pocket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPocketsByUserId indicates an expected call of GetPocketsByUserId.
+func (mr *MockDomainItfMockRecorder) GetPocketsByUserId(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPocketsByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetPocketsByUserId), ctx, userId)
+}
+
+// GetPostingsByAccountId mocks base method.
+func (m *MockDomainItf) GetPostingsByAccountId(ctx context.Context, req GetPostingsByAccountIdRequest) ([]entity.Posting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostingsByAccountId", ctx, req)
+	ret0, _ := ret[0].([]entity.Posting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostingsByAccountId indicates an expected call of GetPostingsByAccountId.
+func (mr *MockDomainItfMockRecorder) GetPostingsByAccountId(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostingsByAccountId", reflect.TypeOf((*MockDomainItf)(nil).GetPostingsByAccountId), ctx, req)
+}
+
+// GetSharedWalletById mocks base method.
+func (m *MockDomainItf) GetSharedWalletById(ctx context.Context, id string) (entity.SharedWallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedWalletById", ctx, id)
+	ret0, _ := ret[0].(entity.SharedWallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedWalletById indicates an expected call of GetSharedWalletById.
+func (mr *MockDomainItfMockRecorder) GetSharedWalletById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedWalletById", reflect.TypeOf((*MockDomainItf)(nil).GetSharedWalletById), ctx, id)
+}
+
+// GetSharedWalletHistoriesByWalletId mocks base method.
+func (m *MockDomainItf) GetSharedWalletHistoriesByWalletId(ctx context.Context, walletId string, limit int) ([]entity.SharedWalletHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedWalletHistoriesByWalletId", ctx, walletId, limit)
+	ret0, _ := ret[0].([]entity.SharedWalletHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedWalletHistoriesByWalletId indicates an expected call of GetSharedWalletHistoriesByWalletId.
+func (mr *MockDomainItfMockRecorder) GetSharedWalletHistoriesByWalletId(ctx, walletId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedWalletHistoriesByWalletId", reflect.TypeOf((*MockDomainItf)(nil).GetSharedWalletHistoriesByWalletId), ctx, walletId, limit)
+}
+
+// GetSharedWalletMembersByUserId mocks base method.
+func (m *MockDomainItf) GetSharedWalletMembersByUserId(ctx context.Context, userId string) ([]entity.SharedWalletMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedWalletMembersByUserId", ctx, userId)
+	ret0, _ := ret[0].([]entity.SharedWalletMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedWalletMembersByUserId indicates an expected call of GetSharedWalletMembersByUserId.
+func (mr *MockDomainItfMockRecorder) GetSharedWalletMembersByUserId(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedWalletMembersByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetSharedWalletMembersByUserId), ctx, userId)
+}
+
+// GetSharedWalletMembersByWalletId mocks base method.
+func (m *MockDomainItf) GetSharedWalletMembersByWalletId(ctx context.Context, walletId string) ([]entity.SharedWalletMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedWalletMembersByWalletId", ctx, walletId)
+	ret0, _ := ret[0].([]entity.SharedWalletMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedWalletMembersByWalletId indicates an expected call of GetSharedWalletMembersByWalletId.
+func (mr *MockDomainItfMockRecorder) GetSharedWalletMembersByWalletId(ctx, walletId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedWalletMembersByWalletId", reflect.TypeOf((*MockDomainItf)(nil).GetSharedWalletMembersByWalletId), ctx, walletId)
+}
+
+// GetTopHistoriesByUserId mocks base method.
+func (m *MockDomainItf) GetTopHistoriesByUserId(ctx context.Context, userId string, from, to time.Time) ([]entity.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopHistoriesByUserId", ctx, userId, from, to)
+	ret0, _ := ret[0].([]entity.History)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopHistoriesByUserId indicates an expected call of GetTopHistoriesByUserId.
+func (mr *MockDomainItfMockRecorder) GetTopHistoriesByUserId(ctx, userId, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopHistoriesByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetTopHistoriesByUserId), ctx, userId, from, to)
+}
+
+// GetTransferById mocks base method.
+func (m *MockDomainItf) GetTransferById(ctx context.Context, id string) (entity.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferById", ctx, id)
+	ret0, _ := ret[0].(entity.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferById indicates an expected call of GetTransferById.
+func (mr *MockDomainItfMockRecorder) GetTransferById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferById", reflect.TypeOf((*MockDomainItf)(nil).GetTransferById), ctx, id)
+}
+
 // GrantBalanceByUserId mocks base method.
-func (m *MockDomainItf) GrantBalanceByUserId(ctx context.Context, balance entity.Balance) error {
+func (m *MockDomainItf) GrantBalanceByUserId(ctx context.Context, req GrantBalanceByUserIdRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GrantBalanceByUserId", ctx, balance)
+	ret := m.ctrl.Call(m, "GrantBalanceByUserId", ctx, req)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // GrantBalanceByUserId indicates an expected call of GrantBalanceByUserId.
-func (mr *MockDomainItfMockRecorder) GrantBalanceByUserId(ctx, balance any) *gomock.Call {
+func (mr *MockDomainItfMockRecorder) GrantBalanceByUserId(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantBalanceByUserId", reflect.TypeOf((*MockDomainItf)(nil).GrantBalanceByUserId), ctx, req)
+}
+
+// InviteSharedWalletMember mocks base method.
+func (m *MockDomainItf) InviteSharedWalletMember(ctx context.Context, req InviteSharedWalletMemberRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteSharedWalletMember", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InviteSharedWalletMember indicates an expected call of InviteSharedWalletMember.
+func (mr *MockDomainItfMockRecorder) InviteSharedWalletMember(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteSharedWalletMember", reflect.TypeOf((*MockDomainItf)(nil).InviteSharedWalletMember), ctx, req)
+}
+
+// MovePocketBalance mocks base method.
+func (m *MockDomainItf) MovePocketBalance(ctx context.Context, req MovePocketBalanceRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MovePocketBalance", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MovePocketBalance indicates an expected call of MovePocketBalance.
+func (mr *MockDomainItfMockRecorder) MovePocketBalance(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MovePocketBalance", reflect.TypeOf((*MockDomainItf)(nil).MovePocketBalance), ctx, req)
+}
+
+// RefundTransfer mocks base method.
+func (m *MockDomainItf) RefundTransfer(ctx context.Context, req RefundTransferRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundTransfer", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefundTransfer indicates an expected call of RefundTransfer.
+func (mr *MockDomainItfMockRecorder) RefundTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTransfer", reflect.TypeOf((*MockDomainItf)(nil).RefundTransfer), ctx, req)
+}
+
+// RemoveSharedWalletMember mocks base method.
+func (m *MockDomainItf) RemoveSharedWalletMember(ctx context.Context, req RemoveSharedWalletMemberRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSharedWalletMember", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSharedWalletMember indicates an expected call of RemoveSharedWalletMember.
+func (mr *MockDomainItfMockRecorder) RemoveSharedWalletMember(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSharedWalletMember", reflect.TypeOf((*MockDomainItf)(nil).RemoveSharedWalletMember), ctx, req)
+}
+
+// RespondSharedWalletInvitation mocks base method.
+func (m *MockDomainItf) RespondSharedWalletInvitation(ctx context.Context, req RespondSharedWalletInvitationRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondSharedWalletInvitation", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RespondSharedWalletInvitation indicates an expected call of RespondSharedWalletInvitation.
+func (mr *MockDomainItfMockRecorder) RespondSharedWalletInvitation(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondSharedWalletInvitation", reflect.TypeOf((*MockDomainItf)(nil).RespondSharedWalletInvitation), ctx, req)
+}
+
+// TransferFromSharedWallet mocks base method.
+func (m *MockDomainItf) TransferFromSharedWallet(ctx context.Context, req TransferFromSharedWalletRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferFromSharedWallet", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferFromSharedWallet indicates an expected call of TransferFromSharedWallet.
+func (mr *MockDomainItfMockRecorder) TransferFromSharedWallet(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferFromSharedWallet", reflect.TypeOf((*MockDomainItf)(nil).TransferFromSharedWallet), ctx, req)
+}
+
+// UpdateUserStatus mocks base method.
+func (m *MockDomainItf) UpdateUserStatus(ctx context.Context, req UpdateUserStatusRequest) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserStatus", ctx, req)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserStatus indicates an expected call of UpdateUserStatus.
+func (mr *MockDomainItfMockRecorder) UpdateUserStatus(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*MockDomainItf)(nil).UpdateUserStatus), ctx, req)
+}
+
+// VoidHold mocks base method.
+func (m *MockDomainItf) VoidHold(ctx context.Context, req VoidHoldRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockDomainItfMockRecorder) VoidHold(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantBalanceByUserId", reflect.TypeOf((*MockDomainItf)(nil).GrantBalanceByUserId), ctx, balance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockDomainItf)(nil).VoidHold), ctx, req)
 }
@@ -0,0 +1,41 @@
+package domainbalance
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// insertOutboxEvent writes an event of userId to the outbox within tx, so it is published if and only if the
+// change it describes commits.
+func (d domain) insertOutboxEvent(ctx context.Context, tx *sql.Tx, userId string, eventType enum.EventType, payload interface{}) (err error) {
+	payloadStr, err := jsoniter.MarshalToString(payload)
+	if err != nil {
+		return err
+	}
+
+	return d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertOutboxEvent, uuid.NewString(), userId, string(eventType), enum.EVENT_VERSION, payloadStr)
+}
+
+// insertBalanceChangedEvent writes BalanceCredited or BalanceDebited for a posting on a balance of userId, in
+// currency or, when it is empty, in the main currency of the user.
+func (d domain) insertBalanceChangedEvent(ctx context.Context, tx *sql.Tx, journalEntry entity.JournalEntry, userId string, amount money.Money, currency string) (err error) {
+	eventType := enum.EVENT_BALANCE_CREDITED
+	if amount < 0 {
+		eventType = enum.EVENT_BALANCE_DEBITED
+		amount = -amount
+	}
+
+	return d.insertOutboxEvent(ctx, tx, userId, eventType, entity.BalanceChangedEvent{
+		UserId:         userId,
+		JournalEntryId: journalEntry.Id,
+		EntryType:      enum.JournalEntryType(journalEntry.Type).String(),
+		Amount:         amount,
+		Currency:       currency,
+	})
+}
@@ -0,0 +1,160 @@
+package domainbalance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+func (d domain) CreatePocket(ctx context.Context, pocket entity.Pocket) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.insertPocket, pocket.Id, pocket.UserId, pocket.Name, pocket.GoalAmount, pocket.LockedUntil)
+	if database.IsUniqueViolation(err) {
+		return ErrPocketNameTaken
+	}
+
+	return err
+}
+
+// GetPocketsByUserId reads the pockets of a user straight from Postgres. A user only has a few of them, and they
+// are read far less often than the main balance.
+func (d domain) GetPocketsByUserId(ctx context.Context, userId string) (resp []entity.Pocket, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getPocketsByUserId, &resp, userId)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) MovePocketBalance(ctx context.Context, req MovePocketBalanceRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.movePocketBalance(ctx, req)
+	})
+}
+
+// movePocketBalance moves money between the main wallet and the pockets of a user. The main balance is locked
+// even when only pockets move, so a closure cannot miss money that lands in a pocket concurrently.
+func (d domain) movePocketBalance(ctx context.Context, req MovePocketBalanceRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	err = d.insertIdempotencyKey(ctx, tx, req.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	err = d.lockUserStatuses(ctx, tx, req.UserId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	balances, err := d.lockBalancesByUserIds(ctx, tx, req.UserId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	var pockets []entity.Pocket
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.lockPocketsByIds, &pockets, req.UserId, req.FromPocketId, req.ToPocketId)
+	if err != nil {
+		return err
+	}
+
+	// The main wallet is the empty pocket id, and funds of it reserved by holds cannot be moved.
+	wallets := map[string]string{
+		"": "main wallet",
+	}
+	available := balances[req.UserId].Available()
+	fromAccountId, toAccountId := req.UserId, req.UserId
+	for _, pocket := range pockets {
+		wallets[pocket.Id] = fmt.Sprintf("pocket %s", pocket.Name)
+
+		if pocket.Id == req.FromPocketId {
+			if pocket.IsLocked(time.Now()) {
+				return PocketLockedError{
+					PocketId:    pocket.Id,
+					LockedUntil: *pocket.LockedUntil,
+				}
+			}
+
+			available = pocket.Amount
+			fromAccountId = pocket.AccountId()
+		}
+		if pocket.Id == req.ToPocketId {
+			toAccountId = pocket.AccountId()
+		}
+	}
+
+	for _, pocketId := range []string{req.FromPocketId, req.ToPocketId} {
+		if _, ok := wallets[pocketId]; !ok {
+			return ErrPocketNotFound
+		}
+	}
+
+	if available < req.Amount {
+		return InsufficientBalanceError{
+			UserId:  req.UserId,
+			Balance: available,
+			Amount:  req.Amount,
+		}
+	}
+
+	notes := fmt.Sprintf("Move money from %s to %s", wallets[req.FromPocketId], wallets[req.ToPocketId])
+
+	journalEntryId := uuid.NewString()
+	err = d.postJournalEntry(ctx, tx, hooks, entity.JournalEntry{
+		Id:          journalEntryId,
+		Type:        int(enum.POCKET_MOVE),
+		Description: notes,
+		Postings: []entity.Posting{
+			{AccountId: fromAccountId, Amount: -req.Amount},
+			{AccountId: toAccountId, Amount: req.Amount},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	history := entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		UserId:         req.UserId,
+		TargetUserId:   req.UserId,
+		Amount:         req.Amount,
+		Type:           int(enum.POCKET),
+		Notes:          notes,
+	}
+	return d.insertHistory(ctx, tx, hooks, history, history.Summary())
+}
+
+// getPocketsAmountByUserId sums the pockets of a user. Pockets only move under the lock of the main balance, so
+// the sum is stable within tx once that balance is locked.
+func (d domain) getPocketsAmountByUserId(ctx context.Context, tx *sql.Tx, userId string) (resp money.Money, err error) {
+	err = d.db.GetContextStmtTx(ctx, tx, d.stmts.getPocketsAmountByUserId, &resp, userId)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
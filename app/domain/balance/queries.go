@@ -4,15 +4,32 @@ const (
 	queryGetBalanceByUserId = `
 		SELECT
 			user_id,
-			amount
+			amount,
+			held_amount,
+			currency
 		FROM
 			balances
 		WHERE
 			user_id = $1;
 	`
 
+	queryLockBalancesByUserIds = `
+		SELECT
+			user_id,
+			amount,
+			held_amount,
+			currency
+		FROM
+			balances
+		WHERE
+			user_id IN ($1, $2)
+		ORDER BY user_id
+		FOR UPDATE;
+	`
+
+	// A balance is created in the main currency of its user by the first money it receives.
 	queryGrantBalanceByUserId = `
-		INSERT INTO balances (user_id, amount) VALUES ($1, $2)
+		INSERT INTO balances (user_id, amount, currency) VALUES ($1, $2, (SELECT currency FROM users WHERE id = $1))
 		ON CONFLICT (user_id)
 		DO UPDATE SET
 			amount = balances.amount + EXCLUDED.amount,
@@ -23,11 +40,165 @@ const (
 		UPDATE balances SET
 			amount = amount - $1,
 			updated_at = NOW()
-		WHERE user_id = $2 AND amount - $1 >= 0; 
+		WHERE user_id = $2 AND amount - held_amount - $1 >= 0;
+	`
+
+	queryHoldBalanceByUserId = `
+		UPDATE balances SET
+			held_amount = held_amount + $1,
+			updated_at = NOW()
+		WHERE user_id = $2 AND amount - held_amount - $1 >= 0;
+	`
+
+	queryReleaseBalanceByUserId = `
+		UPDATE balances SET
+			held_amount = held_amount - $1,
+			updated_at = NOW()
+		WHERE user_id = $2;
+	`
+
+	queryInsertHold = `
+		INSERT INTO holds (id, user_id, target_user_id, amount, status, expires_at) VALUES ($1, $2, $3, $4, $5, $6);
+	`
+
+	queryGetHoldById = `
+		SELECT
+			id,
+			user_id,
+			target_user_id,
+			amount,
+			captured_amount,
+			status,
+			COALESCE(journal_entry_id, '') AS journal_entry_id,
+			expires_at,
+			created_at
+		FROM
+			holds
+		WHERE
+			id = $1;
+	`
+
+	queryLockHoldById = `
+		SELECT
+			id,
+			user_id,
+			target_user_id,
+			amount,
+			captured_amount,
+			status,
+			COALESCE(journal_entry_id, '') AS journal_entry_id,
+			expires_at,
+			created_at
+		FROM
+			holds
+		WHERE
+			id = $1
+		FOR UPDATE;
+	`
+
+	queryUpdateHoldById = `
+		UPDATE holds SET
+			status = $1,
+			captured_amount = $2,
+			journal_entry_id = $3,
+			updated_at = NOW()
+		WHERE id = $4;
+	`
+
+	// Expired holds are released in batches. Holds locked by a capture or void are skipped, they are released
+	// by that transaction or by the next run.
+	queryReleaseExpiredHolds = `
+		WITH expired AS (
+			UPDATE holds SET
+				status = $1,
+				updated_at = NOW()
+			WHERE id IN (
+				SELECT id FROM holds
+				WHERE status = $2 AND expires_at <= $3
+				ORDER BY expires_at
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING user_id, amount
+		)
+		UPDATE balances SET
+			held_amount = balances.held_amount - expired_by_user.amount,
+			updated_at = NOW()
+		FROM (
+			SELECT user_id, SUM(amount) AS amount FROM expired GROUP BY user_id
+		) AS expired_by_user
+		WHERE balances.user_id = expired_by_user.user_id
+		RETURNING balances.user_id;
 	`
 
+	// An empty currency is the main currency of the user of the history.
 	queryInsertHistory = `
-		INSERT INTO histories (id, user_id, target_user_id, amount, type, notes) VALUES ($1, $2, $3, $4, $5, $6);
+		INSERT INTO histories (id, journal_entry_id, user_id, target_user_id, amount, type, notes, reference_id, fee, currency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE(NULLIF($10::VARCHAR, ''), (SELECT currency FROM users WHERE id = $3)));
+	`
+
+	queryInsertJournalEntry = `
+		INSERT INTO journal_entries (id, type, description) VALUES ($1, $2, $3);
+	`
+
+	queryInsertPosting = `
+		INSERT INTO postings (id, journal_entry_id, account_id, amount) VALUES ($1, $2, $3, $4);
+	`
+
+	queryGetJournalEntryById = `
+		SELECT
+			id,
+			type,
+			description
+		FROM
+			journal_entries
+		WHERE
+			id = $1;
+	`
+
+	queryGetPostingsByJournalEntryId = `
+		SELECT
+			id,
+			journal_entry_id,
+			account_id,
+			amount
+		FROM
+			postings
+		WHERE
+			journal_entry_id = $1
+		ORDER BY amount ASC;
+	`
+
+	// The cursor is the (created_at, id) pair of the last posting on the previous page.
+	queryGetPostingsByAccountId = `
+		SELECT
+			id,
+			journal_entry_id,
+			account_id,
+			amount,
+			created_at
+		FROM
+			postings
+		WHERE
+			account_id = $1
+			AND ($2::TIMESTAMPTZ IS NULL OR (created_at, id) < ($2::TIMESTAMPTZ, $3::CHAR(36)))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4;
+	`
+
+	// Counts the postings of a journal entry type with the sign of amount on an account since the start of the
+	// calendar month of the time zone, which is how many operations the free quota of a fee rule already used.
+	queryCountMonthlyPostingsByAccountId = `
+		SELECT
+			COUNT(*)
+		FROM
+			postings p
+			JOIN journal_entries je ON je.id = p.journal_entry_id
+		WHERE
+			p.account_id = $1
+			AND je.type = $2
+			AND SIGN(p.amount) = $3
+			AND p.created_at >= DATE_TRUNC('month', NOW() AT TIME ZONE $4) AT TIME ZONE $4;
 	`
 
 	queryUpdateHistorySummaryById = `
@@ -41,9 +212,12 @@ const (
 	queryGetLatestHistoryByUserId = `
 		SELECT
 			id,
+			journal_entry_id,
 			user_id,
 			target_user_id,
 			amount,
+			fee,
+			currency,
 			type,
 			notes
 		FROM
@@ -54,6 +228,146 @@ const (
 		LIMIT 10;
 	`
 
+	// Every filter is optional and matches everything when its parameter is NULL. The cursor is the
+	// (created_at, id) pair of the last history on the previous page.
+	queryGetHistoriesByUserId = `
+		SELECT
+			id,
+			journal_entry_id,
+			user_id,
+			target_user_id,
+			amount,
+			fee,
+			currency,
+			type,
+			notes,
+			COALESCE(reference_id, '') AS reference_id,
+			created_at
+		FROM
+			histories
+		WHERE
+			user_id = $1
+			AND ($2::SMALLINT IS NULL OR type = $2::SMALLINT)
+			AND ($3::CHAR(36) IS NULL OR target_user_id = $3::CHAR(36))
+			AND ($4::NUMERIC IS NULL OR amount >= $4::NUMERIC)
+			AND ($5::NUMERIC IS NULL OR amount <= $5::NUMERIC)
+			AND ($6::TIMESTAMPTZ IS NULL OR created_at >= $6::TIMESTAMPTZ)
+			AND ($7::TIMESTAMPTZ IS NULL OR created_at < $7::TIMESTAMPTZ)
+			AND ($8::TIMESTAMPTZ IS NULL OR (created_at, id) < ($8::TIMESTAMPTZ, $9::CHAR(36)))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $10;
+	`
+
+	// Every history is added to the day, ISO week and month bucket it falls in. NOW() is the start of the
+	// transaction, so the buckets agree with created_at of the history inserted in the same transaction.
+	// Refunds pass the time of the refunded transfer to reverse it in the buckets it was added to.
+	queryUpdateHistorySummaryBuckets = `
+		INSERT INTO history_summary_buckets (user_id, target_user_id, type, period, bucket_start, amount)
+		SELECT $1, $2, $3, buckets.period, buckets.bucket_start, $4
+		FROM (VALUES
+			('day', (COALESCE($6::TIMESTAMPTZ, NOW()) AT TIME ZONE $5)::DATE),
+			('week', DATE_TRUNC('week', COALESCE($6::TIMESTAMPTZ, NOW()) AT TIME ZONE $5)::DATE),
+			('month', DATE_TRUNC('month', COALESCE($6::TIMESTAMPTZ, NOW()) AT TIME ZONE $5)::DATE)
+		) AS buckets (period, bucket_start)
+		ON CONFLICT (user_id, target_user_id, type, period, bucket_start)
+		DO UPDATE SET
+			amount = history_summary_buckets.amount + EXCLUDED.amount,
+			updated_at = NOW();
+	`
+
+	queryDeleteHistorySummaryBucketsBefore = `
+		DELETE FROM history_summary_buckets WHERE period = $1 AND bucket_start < $2::DATE;
+	`
+
+	// The fee of a journal entry is a journal entry of its own that references it.
+	queryGetHistoriesByJournalEntryId = `
+		SELECT
+			id,
+			journal_entry_id,
+			user_id,
+			target_user_id,
+			amount,
+			fee,
+			currency,
+			type,
+			notes,
+			COALESCE(reference_id, '') AS reference_id,
+			created_at
+		FROM
+			histories
+		WHERE
+			journal_entry_id = $1
+			OR (reference_id = $1 AND type = $2)
+		ORDER BY created_at, id;
+	`
+
+	// Fees are left out, they are shown on the transfer or top-up they are charged on.
+	queryGetTopHistoriesByUserId = `
+		SELECT
+			id,
+			journal_entry_id,
+			user_id,
+			target_user_id,
+			amount,
+			fee,
+			currency,
+			type,
+			notes,
+			created_at
+		FROM
+			histories
+		WHERE
+			user_id = $1
+			AND created_at >= $2
+			AND created_at < $3
+			AND type <> $5
+		ORDER BY CASE WHEN type = $4 THEN -amount ELSE amount END DESC
+		LIMIT 10;
+	`
+
+	// A transfer is identified by its journal entry and read from the debit history of the sender. Refunds
+	// also debit a user, so they are told apart by referencing the transfer they refund.
+	queryGetTransferById = `
+		SELECT
+			h.journal_entry_id AS id,
+			h.user_id AS from_user_id,
+			h.target_user_id AS to_user_id,
+			h.amount,
+			COALESCE((
+				SELECT SUM(r.amount) FROM histories r WHERE r.reference_id = h.journal_entry_id AND r.type = $2
+			), 0) AS refunded_amount,
+			h.created_at
+		FROM
+			histories h
+		WHERE
+			h.journal_entry_id = $1 AND h.type = $2 AND h.reference_id IS NULL;
+	`
+
+	// Refunds of a transfer are serialized on the debit history of the sender. The refunded amount is read by a
+	// separate statement afterwards, since a statement only sees rows committed before it started.
+	queryLockTransferById = `
+		SELECT
+			journal_entry_id AS id,
+			user_id AS from_user_id,
+			target_user_id AS to_user_id,
+			amount,
+			created_at
+		FROM
+			histories
+		WHERE
+			journal_entry_id = $1 AND type = $2 AND reference_id IS NULL
+		FOR UPDATE;
+	`
+
+	queryGetRefundedAmountByTransferId = `
+		SELECT
+			COALESCE(SUM(amount), 0)
+		FROM
+			histories
+		WHERE
+			reference_id = $1 AND type = $2;
+	`
+
 	queryGetHistorySummaryByUserIdAndType = `
 		SELECT
 			user_id,
@@ -67,4 +381,335 @@ const (
 		ORDER BY amount DESC
 		LIMIT 10; 
 	`
+
+	// Top-ups are histories of a user with itself, so they are left out of the leaderboard.
+	queryGetLeaderboard = `
+		SELECT
+			user_id,
+			SUM(amount) AS amount
+		FROM
+			history_summaries
+		WHERE
+			user_id <> target_user_id
+			AND ($1::SMALLINT IS NULL OR type = $1::SMALLINT)
+		GROUP BY user_id;
+	`
+
+	queryGetWindowedLeaderboard = `
+		SELECT
+			user_id,
+			SUM(amount) AS amount
+		FROM
+			history_summary_buckets
+		WHERE
+			period = $1
+			AND bucket_start >= $2::DATE
+			AND bucket_start < $3::DATE
+			AND user_id <> target_user_id
+			AND ($4::SMALLINT IS NULL OR type = $4::SMALLINT)
+		GROUP BY user_id
+		ORDER BY amount DESC, user_id
+		OFFSET $5
+		LIMIT $6;
+	`
+
+	queryInsertIdempotencyKey = `
+		INSERT INTO idempotency_keys (user_id, key, fingerprint, response_code, response_body) VALUES ($1, $2, $3, $4, $5);
+	`
+
+	queryGetIdempotencyKey = `
+		SELECT
+			user_id,
+			key,
+			fingerprint,
+			response_code,
+			response_body
+		FROM
+			idempotency_keys
+		WHERE
+			user_id = $1 AND key = $2;
+	`
+
+	// Users are locked before their balances. Money movements share the lock, so they only wait for a status
+	// change or closure of one of their users, which takes it exclusively.
+	queryLockUserStatusesByIds = `
+		SELECT
+			id,
+			status,
+			currency
+		FROM
+			users
+		WHERE
+			id IN ($1, $2)
+		ORDER BY id
+		FOR SHARE;
+	`
+
+	queryLockUsersByIds = `
+		SELECT
+			id,
+			username,
+			status,
+			currency
+		FROM
+			users
+		WHERE
+			id IN ($1, $2)
+		ORDER BY id
+		FOR UPDATE;
+	`
+
+	queryUpdateUserStatusById = `
+		UPDATE users SET
+			status = $1,
+			status_reason = $2,
+			status_changed_at = $3,
+			updated_at = NOW()
+		WHERE id = $4;
+	`
+
+	queryInsertUserStatusChange = `
+		INSERT INTO user_status_changes (id, user_id, from_status, to_status, reason, actor_id) VALUES ($1, $2, $3, $4, $5, $6);
+	`
+
+	queryInsertPocket = `
+		INSERT INTO pockets (id, user_id, name, goal_amount, locked_until, currency) VALUES ($1, $2, $3, $4, $5, (SELECT currency FROM users WHERE id = $2));
+	`
+
+	queryGetPocketsByUserId = `
+		SELECT
+			id,
+			user_id,
+			name,
+			amount,
+			currency,
+			goal_amount,
+			locked_until,
+			created_at
+		FROM
+			pockets
+		WHERE
+			user_id = $1
+		ORDER BY created_at, id;
+	`
+
+	// Pockets are locked after the balance of their user, which serializes every move of the user.
+	queryLockPocketsByIds = `
+		SELECT
+			id,
+			user_id,
+			name,
+			amount,
+			currency,
+			goal_amount,
+			locked_until,
+			created_at
+		FROM
+			pockets
+		WHERE
+			user_id = $1 AND id IN ($2, $3)
+		ORDER BY id
+		FOR UPDATE;
+	`
+
+	queryGetPocketsAmountByUserId = `
+		SELECT
+			COALESCE(SUM(amount), 0)
+		FROM
+			pockets
+		WHERE
+			user_id = $1;
+	`
+
+	queryPostPocketBalanceById = `
+		UPDATE pockets SET
+			amount = amount + $1,
+			updated_at = NOW()
+		WHERE id = $2;
+	`
+
+	queryPostCurrencyBalance = `
+		INSERT INTO currency_balances (user_id, currency, amount) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, currency)
+		DO UPDATE SET
+			amount = currency_balances.amount + EXCLUDED.amount,
+			updated_at = NOW();
+	`
+
+	queryGetCurrencyBalancesByUserId = `
+		SELECT
+			user_id,
+			currency,
+			amount
+		FROM
+			currency_balances
+		WHERE
+			user_id = $1
+		ORDER BY currency;
+	`
+
+	// Currency balances are locked after the main balance of their user, like pockets. A currency without a row
+	// yet is not locked, the main balance lock already serializes the conversions of the user.
+	queryLockCurrencyBalancesByUserId = `
+		SELECT
+			user_id,
+			currency,
+			amount
+		FROM
+			currency_balances
+		WHERE
+			user_id = $1 AND currency IN ($2, $3)
+		ORDER BY currency
+		FOR UPDATE;
+	`
+
+	queryInsertSharedWallet = `
+		INSERT INTO shared_wallets (id, name, created_by, currency) VALUES ($1, $2, $3, (SELECT currency FROM users WHERE id = $3));
+	`
+
+	queryGetSharedWalletById = `
+		SELECT
+			id,
+			name,
+			amount,
+			currency,
+			created_by,
+			created_at
+		FROM
+			shared_wallets
+		WHERE
+			id = $1;
+	`
+
+	// The wallet row is locked after its members, so money moves and membership changes of a wallet are serialized.
+	queryLockSharedWalletById = `
+		SELECT
+			id,
+			name,
+			amount,
+			currency,
+			created_by,
+			created_at
+		FROM
+			shared_wallets
+		WHERE
+			id = $1
+		FOR UPDATE;
+	`
+
+	queryPostSharedWalletBalanceById = `
+		UPDATE shared_wallets SET
+			amount = amount + $1,
+			updated_at = NOW()
+		WHERE id = $2;
+	`
+
+	queryUpsertSharedWalletMember = `
+		INSERT INTO shared_wallet_members (wallet_id, user_id, role, spend_limit, status, invited_by) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (wallet_id, user_id) DO UPDATE SET
+			role = EXCLUDED.role,
+			spend_limit = EXCLUDED.spend_limit,
+			status = EXCLUDED.status,
+			invited_by = EXCLUDED.invited_by,
+			updated_at = NOW();
+	`
+
+	queryUpdateSharedWalletMemberStatus = `
+		UPDATE shared_wallet_members SET
+			status = $1,
+			updated_at = NOW()
+		WHERE wallet_id = $2 AND user_id = $3;
+	`
+
+	queryGetSharedWalletMembersByWalletId = `
+		SELECT
+			wallet_id,
+			user_id,
+			role,
+			spend_limit,
+			status,
+			invited_by,
+			created_at
+		FROM
+			shared_wallet_members
+		WHERE
+			wallet_id = $1
+		ORDER BY created_at, user_id;
+	`
+
+	queryLockSharedWalletMembersByWalletId = `
+		SELECT
+			wallet_id,
+			user_id,
+			role,
+			spend_limit,
+			status,
+			invited_by,
+			created_at
+		FROM
+			shared_wallet_members
+		WHERE
+			wallet_id = $1
+		ORDER BY user_id
+		FOR UPDATE;
+	`
+
+	// Memberships that were declined, left or removed are not listed.
+	queryGetSharedWalletMembersByUserId = `
+		SELECT
+			wallet_id,
+			user_id,
+			role,
+			spend_limit,
+			status,
+			invited_by,
+			created_at
+		FROM
+			shared_wallet_members
+		WHERE
+			user_id = $1 AND status IN ($2, $3)
+		ORDER BY created_at, wallet_id;
+	`
+
+	queryInsertSharedWalletHistory = `
+		INSERT INTO shared_wallet_histories (id, journal_entry_id, wallet_id, initiated_by, target_user_id, amount, type, notes) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+
+	queryGetSharedWalletHistoriesByWalletId = `
+		SELECT
+			id,
+			journal_entry_id,
+			wallet_id,
+			initiated_by,
+			target_user_id,
+			amount,
+			type,
+			notes,
+			created_at
+		FROM
+			shared_wallet_histories
+		WHERE
+			wallet_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2;
+	`
+
+	// Currencies left empty in the payload are the main currency of the user, which the postings on its main
+	// account and the transfers without a conversion do not know.
+	queryInsertOutboxEvent = `
+		INSERT INTO outbox_events (event_id, user_id, type, version, payload)
+		SELECT
+			$1::CHAR(36),
+			u.id,
+			$3::VARCHAR,
+			$4::SMALLINT,
+			$5::JSONB || JSONB_STRIP_NULLS(JSONB_BUILD_OBJECT(
+				'currency', CASE WHEN $5::JSONB->>'currency' = '' THEN u.currency END,
+				'to_currency', CASE WHEN $5::JSONB->>'to_currency' = '' THEN u.currency END
+			))
+		FROM
+			users u
+		WHERE
+			u.id = $2;
+	`
 )
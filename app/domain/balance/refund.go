@@ -0,0 +1,165 @@
+package domainbalance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+// GetTransferById reads a transfer straight from Postgres. It is not cached since it is only read to check a
+// refund, which is checked again against the locked transfer.
+func (d domain) GetTransferById(ctx context.Context, id string) (resp entity.Transfer, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getTransferById, &resp, id, int(enum.DEBIT))
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) lockTransferById(ctx context.Context, tx *sql.Tx, id string) (resp entity.Transfer, err error) {
+	err = d.db.GetContextStmtTx(ctx, tx, d.stmts.lockTransferById, &resp, id, int(enum.DEBIT))
+	if err != nil {
+		return resp, err
+	}
+
+	err = d.db.GetContextStmtTx(ctx, tx, d.stmts.getRefundedAmountByTransferId, &resp.RefundedAmount, id, int(enum.DEBIT))
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) RefundTransfer(ctx context.Context, req RefundTransferRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.refundTransfer(ctx, req)
+	})
+}
+
+// refundTransfer moves the refund from the receiver back to the sender of the transfer. Its histories reference
+// the transfer and reverse what the transfer added to history_summaries, in the buckets of the transfer.
+func (d domain) refundTransfer(ctx context.Context, req RefundTransferRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	var transfer entity.Transfer
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	err = d.insertIdempotencyKey(ctx, tx, req.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	transfer, err = d.lockTransferById(ctx, tx, req.TransferId)
+	if err == sql.ErrNoRows {
+		return ErrTransferNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only the receiver can refund, anyone else is told the transfer does not exist.
+	if transfer.ToUserId != req.UserId {
+		return ErrTransferNotFound
+	}
+
+	if req.Amount <= 0 || req.Amount > transfer.RefundableAmount() {
+		return RefundExceedsTransferError{
+			TransferId: transfer.Id,
+			Refundable: transfer.RefundableAmount(),
+			Amount:     req.Amount,
+		}
+	}
+
+	err = d.lockUserStatuses(ctx, tx, transfer.ToUserId, transfer.FromUserId)
+	if err != nil {
+		return err
+	}
+
+	balances, err := d.lockBalancesByUserIds(ctx, tx, transfer.ToUserId, transfer.FromUserId)
+	if err != nil {
+		return err
+	}
+
+	if balance := balances[transfer.ToUserId]; balance.Available() < req.Amount {
+		return InsufficientBalanceError{
+			UserId:  transfer.ToUserId,
+			Balance: balance.Available(),
+			Amount:  req.Amount,
+		}
+	}
+
+	err = d.postJournalEntry(ctx, tx, hooks, entity.JournalEntry{
+		Id:          req.RefundId,
+		Type:        int(enum.REFUND),
+		Description: fmt.Sprintf("Refund transfer %s from %s to %s", transfer.Id, transfer.ToUserId, transfer.FromUserId),
+		Postings: []entity.Posting{
+			{AccountId: transfer.ToUserId, Amount: -req.Amount},
+			{AccountId: transfer.FromUserId, Amount: req.Amount},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = d.insertHistory(ctx, tx, hooks, entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: req.RefundId,
+		UserId:         transfer.FromUserId,
+		TargetUserId:   transfer.ToUserId,
+		Amount:         req.Amount,
+		Type:           int(enum.CREDIT),
+		Notes:          fmt.Sprintf("Refund of transfer to %s", transfer.ToUserId),
+		ReferenceId:    transfer.Id,
+	}, reverseTransferSummary(transfer, transfer.FromUserId, transfer.ToUserId, enum.DEBIT, req.Amount))
+	if err != nil {
+		return err
+	}
+
+	err = d.insertHistory(ctx, tx, hooks, entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: req.RefundId,
+		UserId:         transfer.ToUserId,
+		TargetUserId:   transfer.FromUserId,
+		Amount:         req.Amount,
+		Type:           int(enum.DEBIT),
+		Notes:          fmt.Sprintf("Refund transfer from %s", transfer.FromUserId),
+		ReferenceId:    transfer.Id,
+	}, reverseTransferSummary(transfer, transfer.ToUserId, transfer.FromUserId, enum.CREDIT, req.Amount))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reverseTransferSummary subtracts amount from the summary the transfer added for userId.
+func reverseTransferSummary(transfer entity.Transfer, userId string, targetUserId string, historyType enum.HistoryType, amount money.Money) entity.HistorySummary {
+	return entity.HistorySummary{
+		UserId:       userId,
+		TargetUserId: targetUserId,
+		Amount:       -amount,
+		Type:         int(historyType),
+		At:           transfer.CreatedAt,
+	}
+}
@@ -0,0 +1,475 @@
+package domainbalance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+// CreateSharedWallet creates the wallet with its creator as its first owner.
+func (d domain) CreateSharedWallet(ctx context.Context, wallet entity.SharedWallet) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+	}()
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertSharedWallet, wallet.Id, wallet.Name, wallet.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	return d.db.ExecContextStmtTx(ctx, tx, d.stmts.upsertSharedWalletMember, wallet.Id, wallet.CreatedBy, int(enum.SHARED_WALLET_ROLE_OWNER), 0, int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE), wallet.CreatedBy)
+}
+
+func (d domain) GetSharedWalletById(ctx context.Context, id string) (resp entity.SharedWallet, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getSharedWalletById, &resp, id)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GetSharedWalletMembersByWalletId returns every membership of the wallet, including past ones.
+func (d domain) GetSharedWalletMembersByWalletId(ctx context.Context, walletId string) (resp []entity.SharedWalletMember, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getSharedWalletMembersByWalletId, &resp, walletId)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GetSharedWalletMembersByUserId returns the wallets the user is invited to or a member of.
+func (d domain) GetSharedWalletMembersByUserId(ctx context.Context, userId string) (resp []entity.SharedWalletMember, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getSharedWalletMembersByUserId, &resp, userId, int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE))
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) GetSharedWalletHistoriesByWalletId(ctx context.Context, walletId string, limit int) (resp []entity.SharedWalletHistory, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getSharedWalletHistoriesByWalletId, &resp, walletId, limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// lockSharedWalletMembers locks every membership of the wallet for the rest of tx, keyed by user id.
+func (d domain) lockSharedWalletMembers(ctx context.Context, tx *sql.Tx, walletId string) (resp map[string]entity.SharedWalletMember, err error) {
+	var members []entity.SharedWalletMember
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.lockSharedWalletMembersByWalletId, &members, walletId)
+	if err != nil {
+		return resp, err
+	}
+
+	resp = make(map[string]entity.SharedWalletMember, len(members))
+	for _, member := range members {
+		resp[member.UserId] = member
+	}
+
+	return resp, nil
+}
+
+// activeMember returns the active membership of userId. The wallet is not found for users who are not active
+// members of it, so its existence is not disclosed to them.
+func activeMember(members map[string]entity.SharedWalletMember, userId string) (resp entity.SharedWalletMember, err error) {
+	member, ok := members[userId]
+	if !ok || !member.IsActive() {
+		return resp, ErrSharedWalletNotFound
+	}
+
+	return member, nil
+}
+
+// countOwners returns the number of active owners among members.
+func countOwners(members map[string]entity.SharedWalletMember) (resp int) {
+	for _, member := range members {
+		if member.IsOwner() {
+			resp++
+		}
+	}
+
+	return resp
+}
+
+// InviteSharedWalletMember invites a user who is not already invited to or a member of the wallet. Users who
+// declined, left or were removed can be invited again.
+func (d domain) InviteSharedWalletMember(ctx context.Context, req InviteSharedWalletMemberRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+	}()
+
+	members, err := d.lockSharedWalletMembers(ctx, tx, req.WalletId)
+	if err != nil {
+		return err
+	}
+
+	actor, err := activeMember(members, req.ActorId)
+	if err != nil {
+		return err
+	}
+
+	if !actor.IsOwner() {
+		return ErrSharedWalletForbidden
+	}
+
+	if members[req.UserId].IsPending() {
+		return ErrSharedWalletMemberExists
+	}
+
+	return d.db.ExecContextStmtTx(ctx, tx, d.stmts.upsertSharedWalletMember, req.WalletId, req.UserId, int(req.Role), req.SpendLimit, int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED), req.ActorId)
+}
+
+func (d domain) RespondSharedWalletInvitation(ctx context.Context, req RespondSharedWalletInvitationRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+	}()
+
+	members, err := d.lockSharedWalletMembers(ctx, tx, req.WalletId)
+	if err != nil {
+		return err
+	}
+
+	if members[req.UserId].Status != int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED) {
+		return ErrSharedWalletInvitationNotFound
+	}
+
+	status := enum.SHARED_WALLET_MEMBER_STATUS_DECLINED
+	if req.Accept {
+		status = enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE
+	}
+
+	return d.db.ExecContextStmtTx(ctx, tx, d.stmts.updateSharedWalletMemberStatus, int(status), req.WalletId, req.UserId)
+}
+
+// RemoveSharedWalletMember lets a member leave the wallet, or an owner remove another member or revoke an
+// invitation. The last active owner can neither leave nor be removed.
+func (d domain) RemoveSharedWalletMember(ctx context.Context, req RemoveSharedWalletMemberRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+	}()
+
+	members, err := d.lockSharedWalletMembers(ctx, tx, req.WalletId)
+	if err != nil {
+		return err
+	}
+
+	actor, err := activeMember(members, req.ActorId)
+	if err != nil {
+		return err
+	}
+
+	status := enum.SHARED_WALLET_MEMBER_STATUS_LEFT
+	if req.ActorId != req.UserId {
+		if !actor.IsOwner() {
+			return ErrSharedWalletForbidden
+		}
+		status = enum.SHARED_WALLET_MEMBER_STATUS_REMOVED
+	}
+
+	member := members[req.UserId]
+	if !member.IsPending() {
+		return ErrSharedWalletMemberNotFound
+	}
+
+	if member.IsOwner() && countOwners(members) == 1 {
+		return ErrSharedWalletLastOwner
+	}
+
+	return d.db.ExecContextStmtTx(ctx, tx, d.stmts.updateSharedWalletMemberStatus, int(status), req.WalletId, req.UserId)
+}
+
+// spendingMember returns the membership of userId when it allows moving amount out of the wallet.
+func spendingMember(members map[string]entity.SharedWalletMember, userId string) (resp entity.SharedWalletMember, err error) {
+	member, err := activeMember(members, userId)
+	if err != nil {
+		return resp, err
+	}
+
+	if !member.CanSpend() {
+		return resp, ErrSharedWalletForbidden
+	}
+
+	return member, nil
+}
+
+func (d domain) DepositSharedWallet(ctx context.Context, req DepositSharedWalletRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.depositSharedWallet(ctx, req)
+	})
+}
+
+// depositSharedWallet moves money from the main wallet of a member into the shared wallet. Viewers cannot
+// deposit, since they cannot move the money of the wallet either.
+func (d domain) depositSharedWallet(ctx context.Context, req DepositSharedWalletRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	err = d.insertIdempotencyKey(ctx, tx, req.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	users, err := d.lockUsers(ctx, tx, req.UserId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	members, err := d.lockSharedWalletMembers(ctx, tx, req.WalletId)
+	if err != nil {
+		return err
+	}
+
+	_, err = spendingMember(members, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	balances, err := d.lockBalancesByUserIds(ctx, tx, req.UserId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	if balance := balances[req.UserId]; balance.Available() < req.Amount {
+		return InsufficientBalanceError{
+			UserId:  req.UserId,
+			Balance: balance.Available(),
+			Amount:  req.Amount,
+		}
+	}
+
+	var wallet entity.SharedWallet
+	err = d.db.GetContextStmtTx(ctx, tx, d.stmts.lockSharedWalletById, &wallet, req.WalletId)
+	if err != nil {
+		return err
+	}
+
+	// The wallet is in the main currency of the user who created it.
+	if user, ok := users[req.UserId]; ok && user.Currency != wallet.Currency {
+		return CurrencyMismatchError{
+			Currency:   user.Currency,
+			ToCurrency: wallet.Currency,
+		}
+	}
+
+	journalEntryId := uuid.NewString()
+	err = d.postJournalEntry(ctx, tx, hooks, entity.JournalEntry{
+		Id:          journalEntryId,
+		Type:        int(enum.SHARED_WALLET_DEPOSIT),
+		Description: fmt.Sprintf("Deposit money from %s to wallet %s", req.UserId, wallet.Id),
+		Postings: []entity.Posting{
+			{AccountId: req.UserId, Amount: -req.Amount},
+			{AccountId: wallet.AccountId(), Amount: req.Amount},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	history := entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		UserId:         req.UserId,
+		TargetUserId:   req.UserId,
+		Amount:         req.Amount,
+		Type:           int(enum.SHARED_WALLET),
+		Notes:          fmt.Sprintf("Deposit money to wallet %s", wallet.Name),
+	}
+	err = d.insertHistory(ctx, tx, hooks, history, history.Summary())
+	if err != nil {
+		return err
+	}
+
+	return d.insertSharedWalletHistory(ctx, tx, entity.SharedWalletHistory{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		WalletId:       wallet.Id,
+		InitiatedBy:    req.UserId,
+		TargetUserId:   req.UserId,
+		Amount:         req.Amount,
+		Type:           int(enum.CREDIT),
+		Notes:          "Deposit money from member",
+	})
+}
+
+func (d domain) TransferFromSharedWallet(ctx context.Context, req TransferFromSharedWalletRequest) (err error) {
+	return database.RetryTx(ctx, func() error {
+		return d.transferFromSharedWallet(ctx, req)
+	})
+}
+
+// transferFromSharedWallet moves money of the wallet to a user on behalf of a member. The member needs to be able
+// to send money from their own account too, so a frozen member cannot spend the money of their wallets.
+func (d domain) transferFromSharedWallet(ctx context.Context, req TransferFromSharedWalletRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	hooks := &txHooks{}
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+
+		if err == nil {
+			hooks.run(ctx)
+		}
+	}()
+
+	err = d.insertIdempotencyKey(ctx, tx, req.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	// The member and the recipient may have different currencies, only the one of the recipient has to match
+	// the wallet.
+	users, err := d.lockUsers(ctx, tx, req.UserId, req.ToUserId)
+	if err != nil {
+		return err
+	}
+
+	members, err := d.lockSharedWalletMembers(ctx, tx, req.WalletId)
+	if err != nil {
+		return err
+	}
+
+	member, err := spendingMember(members, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == int(enum.SHARED_WALLET_ROLE_SPENDER) && req.Amount > member.SpendLimit {
+		return SpendLimitExceededError{
+			UserId: req.UserId,
+			Limit:  member.SpendLimit,
+			Amount: req.Amount,
+		}
+	}
+
+	var wallet entity.SharedWallet
+	err = d.db.GetContextStmtTx(ctx, tx, d.stmts.lockSharedWalletById, &wallet, req.WalletId)
+	if err != nil {
+		return err
+	}
+
+	if toUser, ok := users[req.ToUserId]; ok && toUser.Currency != wallet.Currency {
+		return CurrencyMismatchError{
+			Currency:   wallet.Currency,
+			ToCurrency: toUser.Currency,
+		}
+	}
+
+	if wallet.Amount < req.Amount {
+		return InsufficientBalanceError{
+			UserId:  req.UserId,
+			Balance: wallet.Amount,
+			Amount:  req.Amount,
+		}
+	}
+
+	journalEntryId := uuid.NewString()
+	err = d.postJournalEntry(ctx, tx, hooks, entity.JournalEntry{
+		Id:          journalEntryId,
+		Type:        int(enum.TRANSFER),
+		Description: fmt.Sprintf("Transfer money from wallet %s to %s by %s", wallet.Id, req.ToUserId, req.UserId),
+		Postings: []entity.Posting{
+			{AccountId: wallet.AccountId(), Amount: -req.Amount},
+			{AccountId: req.ToUserId, Amount: req.Amount},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	creditHistory := entity.History{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		UserId:         req.ToUserId,
+		TargetUserId:   req.UserId,
+		Amount:         req.Amount,
+		Type:           int(enum.CREDIT),
+		Notes:          fmt.Sprintf("Receive money from wallet %s", wallet.Name),
+	}
+	err = d.insertHistory(ctx, tx, hooks, creditHistory, creditHistory.Summary())
+	if err != nil {
+		return err
+	}
+
+	return d.insertSharedWalletHistory(ctx, tx, entity.SharedWalletHistory{
+		Id:             uuid.NewString(),
+		JournalEntryId: journalEntryId,
+		WalletId:       wallet.Id,
+		InitiatedBy:    req.UserId,
+		TargetUserId:   req.ToUserId,
+		Amount:         req.Amount,
+		Type:           int(enum.DEBIT),
+		Notes:          "Transfer money to recipient",
+	})
+}
+
+func (d domain) insertSharedWalletHistory(ctx context.Context, tx *sql.Tx, history entity.SharedWalletHistory) (err error) {
+	return d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertSharedWalletHistory, history.Id, history.JournalEntryId, history.WalletId, history.InitiatedBy, history.TargetUserId, history.Amount, history.Type, history.Notes)
+}
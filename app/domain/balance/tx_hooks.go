@@ -0,0 +1,29 @@
+package domainbalance
+
+import (
+	"context"
+
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+// txHooks collects what a transaction changes outside Postgres and applies it once the transaction commits, so
+// no reader sees a change that can still roll back. It is created by the function that begins the transaction,
+// and every attempt of a retried transaction starts with a new one.
+type txHooks struct {
+	afterCommit []func(ctx context.Context) error
+}
+
+// onCommit runs f after the transaction commits. A failure is only logged since the money already moved, what
+// f changes has to expire or be rebuilt on its own.
+func (h *txHooks) onCommit(f func(ctx context.Context) error) {
+	h.afterCommit = append(h.afterCommit, f)
+}
+
+func (h *txHooks) run(ctx context.Context) {
+	for _, f := range h.afterCommit {
+		err := f(ctx)
+		if err != nil {
+			log.Errorln("txHooks.run", err)
+		}
+	}
+}
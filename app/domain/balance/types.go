@@ -1,7 +1,325 @@
 package domainbalance
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/currency"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+var (
+	ErrIdempotencyKeyExists   = fmt.Errorf("idempotency key already exists")
+	ErrUnbalancedJournalEntry = fmt.Errorf("journal entry postings do not sum to zero")
+	ErrTransferNotFound       = fmt.Errorf("transfer not found")
+	ErrHoldNotFound           = fmt.Errorf("hold not found")
+	ErrHoldNotActive          = fmt.Errorf("hold is not active")
+	ErrFeeExceedsAmount       = fmt.Errorf("fee is not less than the amount")
+	ErrPocketNotFound         = fmt.Errorf("pocket not found")
+	ErrPocketNameTaken        = fmt.Errorf("pocket name is already used")
+	ErrUserClosed             = fmt.Errorf("user is closed")
+
+	ErrSharedWalletNotFound           = fmt.Errorf("shared wallet not found")
+	ErrSharedWalletForbidden          = fmt.Errorf("role of the member does not allow this operation")
+	ErrSharedWalletMemberExists       = fmt.Errorf("user is already invited to or a member of the shared wallet")
+	ErrSharedWalletMemberNotFound     = fmt.Errorf("shared wallet member not found")
+	ErrSharedWalletInvitationNotFound = fmt.Errorf("shared wallet invitation not found")
+	ErrSharedWalletLastOwner          = fmt.Errorf("shared wallet must keep an owner")
+)
+
+// InsufficientBalanceError is returned when the available balance does not cover the amount and its fee.
+type InsufficientBalanceError struct {
+	UserId  string
+	Balance money.Money
+	Amount  money.Money
+	Fee     money.Money
+}
+
+func (e InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("insufficient balance: balance %s, amount %s, fee %s", e.Balance, e.Amount, e.Fee)
+}
+
+// AccountNotActiveError is returned when the status of UserId does not allow money to leave or enter its account.
+type AccountNotActiveError struct {
+	UserId string
+	Status int
+}
+
+func (e AccountNotActiveError) Error() string {
+	return fmt.Sprintf("account %s is %s", e.UserId, enum.UserStatus(e.Status))
+}
+
+// BalanceNotZeroError is returned when an account is closed while it still holds money that is not swept. Money
+// in Pockets has to be moved back to the main wallet first, and Currencies converted back to the main currency.
+type BalanceNotZeroError struct {
+	Balance    money.Money
+	Held       money.Money
+	Pockets    money.Money
+	Currencies []string
+}
+
+func (e BalanceNotZeroError) Error() string {
+	return fmt.Sprintf("balance is not zero: balance %s, held %s, pockets %s, currencies %v", e.Balance, e.Held, e.Pockets, e.Currencies)
+}
+
+// SharedWalletLastOwnerError is returned when an account is closed while it is the last owner of shared wallets that
+// still hold money, which would leave nobody able to move it out.
+type SharedWalletLastOwnerError struct {
+	WalletIds []string
+}
+
+func (e SharedWalletLastOwnerError) Error() string {
+	return fmt.Sprintf("last owner of shared wallets holding money: %v", e.WalletIds)
+}
+
+// CurrencyMismatchError is returned when money would move between balances of different currencies without a
+// conversion.
+type CurrencyMismatchError struct {
+	Currency   string
+	ToCurrency string
+}
+
+func (e CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("currency mismatch: %s to %s", e.Currency, e.ToCurrency)
+}
+
+// PocketLockedError is returned when money is moved out of a pocket before LockedUntil.
+type PocketLockedError struct {
+	PocketId    string
+	LockedUntil time.Time
+}
+
+func (e PocketLockedError) Error() string {
+	return fmt.Sprintf("pocket %s is locked until %s", e.PocketId, e.LockedUntil.Format(time.RFC3339))
+}
+
+// SpendLimitExceededError is returned when a spender moves more money out of a shared wallet than their limit.
+type SpendLimitExceededError struct {
+	UserId string
+	Limit  money.Money
+	Amount money.Money
+}
+
+func (e SpendLimitExceededError) Error() string {
+	return fmt.Sprintf("spend limit exceeded for user %s: limit %s, amount %s", e.UserId, e.Limit, e.Amount)
+}
+
+// RefundExceedsTransferError is returned when a refund is larger than what is left to refund of the transfer.
+type RefundExceedsTransferError struct {
+	TransferId string
+	Refundable money.Money
+	Amount     money.Money
+}
+
+func (e RefundExceedsTransferError) Error() string {
+	return fmt.Sprintf("refund exceeds transfer %s: refundable %s, amount %s", e.TransferId, e.Refundable, e.Amount)
+}
+
+// CaptureExceedsHoldError is returned when a capture is larger than the amount of the hold.
+type CaptureExceedsHoldError struct {
+	HoldId string
+	Held   money.Money
+	Amount money.Money
+}
+
+func (e CaptureExceedsHoldError) Error() string {
+	return fmt.Sprintf("capture exceeds hold %s: held %s, amount %s", e.HoldId, e.Held, e.Amount)
+}
+
+// GrantBalanceByUserIdRequest tops up Amount and charges the fee of FeeRule from it, a zero rule charges nothing.
+type GrantBalanceByUserIdRequest struct {
+	UserId         string
+	Amount         money.Money
+	FeeRule        entity.FeeRule
+	IdempotencyKey entity.IdempotencyKey
+}
+
+// Conversion prices Amount of a money movement in FromCurrency as ToAmount of ToCurrency at Rate.
+type Conversion struct {
+	FromCurrency string
+	ToCurrency   string
+	ToAmount     money.Money
+	Rate         currency.Rate
+}
+
+// IsZero reports whether there is no conversion, which is when money stays in one currency.
+func (c Conversion) IsZero() bool {
+	return c.FromCurrency == ""
+}
+
+// DisburmentBalanceRequest transfers Amount and charges the fee of FeeRule on top of it, a zero rule charges
+// nothing. Users with different main currencies need a Conversion, the recipient is credited its ToAmount and
+// the fee stays in the currency of the sender.
 type DisburmentBalanceRequest struct {
+	UserId         string
+	ToUserId       string
+	Amount         money.Money
+	FeeRule        entity.FeeRule
+	Conversion     Conversion
+	IdempotencyKey entity.IdempotencyKey
+}
+
+// GetFeeRequest prices an operation of Amount by UserId with FeeRule, Operation is the journal entry type it posts.
+type GetFeeRequest struct {
+	UserId    string
+	Operation enum.JournalEntryType
+	Amount    money.Money
+	FeeRule   entity.FeeRule
+}
+
+// RefundTransferRequest refunds Amount of a transfer back to its sender. UserId is the user asking for the
+// refund, who has to be the receiver of the transfer.
+type RefundTransferRequest struct {
+	RefundId       string
+	TransferId     string
+	UserId         string
+	Amount         money.Money
+	IdempotencyKey entity.IdempotencyKey
+}
+
+type CreateHoldRequest struct {
+	Hold           entity.Hold
+	IdempotencyKey entity.IdempotencyKey
+}
+
+// CaptureHoldRequest transfers Amount of a hold to its target and releases the rest. UserId is the user
+// capturing, who has to be the target of the hold.
+type CaptureHoldRequest struct {
+	HoldId     string
+	TransferId string
+	UserId     string
+	Amount     money.Money
+}
+
+// VoidHoldRequest releases a hold without moving money. UserId has to be the target of the hold.
+type VoidHoldRequest struct {
+	HoldId string
+	UserId string
+}
+
+// GetHistoriesByUserIdRequest filters the histories of a user. Zero values mean no filter, and
+// CursorCreatedAt with CursorId continue after the last history of the previous page.
+type GetHistoriesByUserIdRequest struct {
+	UserId          string
+	Type            int
+	TargetUserId    string
+	MinAmount       *money.Money
+	MaxAmount       *money.Money
+	From            time.Time
+	To              time.Time
+	CursorCreatedAt time.Time
+	CursorId        string
+	Limit           int
+}
+
+// GetPostingsByAccountIdRequest pages the postings of a ledger account, newest first. CursorCreatedAt with CursorId
+// continue after the last posting of the previous page.
+type GetPostingsByAccountIdRequest struct {
+	AccountId       string
+	CursorCreatedAt time.Time
+	CursorId        string
+	Limit           int
+}
+
+// SummaryWindow selects the buckets of Period starting in [From, To). From and To are midnights of the
+// business time zone, so they also bound created_at of the histories in the window.
+type SummaryWindow struct {
+	Period enum.SummaryPeriod
+	From   time.Time
+	To     time.Time
+}
+
+func (w SummaryWindow) IsZero() bool {
+	return w.Period == ""
+}
+
+// GetLeaderboardRequest ranks users over all time when Window is zero.
+type GetLeaderboardRequest struct {
+	Direction enum.LeaderboardDirection
+	Window    SummaryWindow
+	Offset    int64
+	Limit     int64
+}
+
+// UpdateUserStatusRequest changes the status of UserId on behalf of ActorId. Closing an account moves its balance,
+// so it goes through CloseAccount instead.
+type UpdateUserStatusRequest struct {
+	UserId  string
+	Status  enum.UserStatus
+	Reason  string
+	ActorId string
+}
+
+// CloseAccountRequest closes the account of UserId on behalf of ActorId, moving its balance to SweepToUserId when
+// it is set. Admin closures skip the status checks of the user, which lets them sweep a frozen account.
+type CloseAccountRequest struct {
+	UserId        string
+	SweepToUserId string
+	Reason        string
+	ActorId       string
+	Admin         bool
+}
+
+// MovePocketBalanceRequest moves Amount between two wallets of UserId. An empty pocket id is the main wallet.
+type MovePocketBalanceRequest struct {
+	UserId         string
+	FromPocketId   string
+	ToPocketId     string
+	Amount         money.Money
+	IdempotencyKey entity.IdempotencyKey
+}
+
+// ConvertBalanceRequest converts Amount between two balances of UserId as priced by Conversion. Either currency
+// can be the main currency of the user.
+type ConvertBalanceRequest struct {
+	UserId         string
+	Amount         money.Money
+	Conversion     Conversion
+	IdempotencyKey entity.IdempotencyKey
+}
+
+// InviteSharedWalletMemberRequest invites UserId to the wallet on behalf of ActorId, who must be an owner of it.
+type InviteSharedWalletMemberRequest struct {
+	WalletId   string
+	ActorId    string
+	UserId     string
+	Role       enum.SharedWalletRole
+	SpendLimit money.Money
+}
+
+type RespondSharedWalletInvitationRequest struct {
+	WalletId string
 	UserId   string
-	ToUserId string
-	Amount   float64
+	Accept   bool
+}
+
+// RemoveSharedWalletMemberRequest removes UserId from the wallet, which is leaving it when ActorId is UserId.
+type RemoveSharedWalletMemberRequest struct {
+	WalletId string
+	ActorId  string
+	UserId   string
+}
+
+type DepositSharedWalletRequest struct {
+	WalletId       string
+	UserId         string
+	Amount         money.Money
+	IdempotencyKey entity.IdempotencyKey
+}
+
+// TransferFromSharedWalletRequest moves Amount from the wallet to ToUserId on behalf of the member UserId.
+type TransferFromSharedWalletRequest struct {
+	WalletId       string
+	UserId         string
+	ToUserId       string
+	Amount         money.Money
+	IdempotencyKey entity.IdempotencyKey
+}
+
+// historySummaryKey is the key of the cached history summaries of a user and history type.
+type historySummaryKey struct {
+	UserId string
+	Type   int
 }
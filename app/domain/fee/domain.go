@@ -0,0 +1,33 @@
+package domainfee
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
+)
+
+type domain struct {
+	db    database.DatabaseItf
+	cache lrucache.LRUCacheItf
+	stmts databaseStmts
+}
+
+type databaseStmts struct {
+	getActiveFeeRulesByOperationCurrency *sqlx.Stmt
+}
+
+func Init(db database.DatabaseItf) DomainItf {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	return &domain{
+		db:    db,
+		cache: lrucache.Init(),
+		stmts: databaseStmts{
+			getActiveFeeRulesByOperationCurrency: db.PreparexContext(ctx, queryGetActiveFeeRulesByOperationCurrency),
+		},
+	}
+}
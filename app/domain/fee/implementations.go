@@ -0,0 +1,33 @@
+package domainfee
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+const (
+	// Rules are edited in the table by hand and rarely change, so every instance keeps them for a minute.
+	feeRulesCacheTTL = time.Minute
+)
+
+// GetFeeRules returns the active fee rules of an operation, which is the journal entry type it posts, in a currency.
+func (d domain) GetFeeRules(ctx context.Context, operation enum.JournalEntryType, currency string) (resp []entity.FeeRule, err error) {
+	rules, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetFeeRules, operation, currency), feeRulesCacheTTL, func() (interface{}, error) {
+		var rules []entity.FeeRule
+		err := d.db.SelectContextStmt(ctx, d.stmts.getActiveFeeRulesByOperationCurrency, &rules, int(operation), currency)
+		if err != nil {
+			return rules, err
+		}
+
+		return rules, nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	return rules.Value().([]entity.FeeRule), nil
+}
@@ -0,0 +1,137 @@
+package domainfee
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_domain_GetFeeRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	rules := []entity.FeeRule{
+		{Id: 1, Operation: int(enum.TRANSFER), Currency: "IDR", MinAmount: 0, MaxAmount: 10000, FlatFee: 100},
+		{Id: 2, Operation: int(enum.TRANSFER), Currency: "IDR", MinAmount: 10000, RateBps: 50, FreePerMonth: 5},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx       context.Context
+		operation enum.JournalEntryType
+		currency  string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.FeeRule
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				cache: lrucache.Init(),
+				stmts: databaseStmts{
+					getActiveFeeRulesByOperationCurrency: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:       context.Background(),
+				operation: enum.TRANSFER,
+				currency:  "IDR",
+			},
+			wantResp: rules,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.TRANSFER), "IDR").SetArg(2, rules).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success without rules",
+			fields: fields{
+				db:    mockDatabase,
+				cache: lrucache.Init(),
+				stmts: databaseStmts{
+					getActiveFeeRulesByOperationCurrency: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:       context.Background(),
+				operation: enum.TOPUP,
+				currency:  "IDR",
+			},
+			wantResp: nil,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.TOPUP), "IDR").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt db",
+			fields: fields{
+				db:    mockDatabase,
+				cache: lrucache.Init(),
+				stmts: databaseStmts{
+					getActiveFeeRulesByOperationCurrency: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:       context.Background(),
+				operation: enum.TRANSFER,
+				currency:  "IDR",
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.TRANSFER), "IDR").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				cache: tt.fields.cache,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetFeeRules(tt.args.ctx, tt.args.operation, tt.args.currency)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetFeeRules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetFeeRules() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
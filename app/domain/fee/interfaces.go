@@ -0,0 +1,12 @@
+package domainfee
+
+import (
+	"context"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+type DomainItf interface {
+	GetFeeRules(ctx context.Context, operation enum.JournalEntryType, currency string) (resp []entity.FeeRule, err error)
+}
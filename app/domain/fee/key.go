@@ -0,0 +1,5 @@
+package domainfee
+
+const (
+	cacheKeyGetFeeRules = "domain:fee:rules:operation:%d:currency:%s"
+)
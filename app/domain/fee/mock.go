@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/domain/fee/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/domain/fee/interfaces.go -destination=app/domain/fee/mock.go -package=domainfee
+//
+
+// Package domainfee is a generated GoMock package.
+package domainfee
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/kevinsudut/wallet-system/app/entity"
+	enum "github.com/kevinsudut/wallet-system/app/enum"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// GetFeeRules mocks base method.
+func (m *MockDomainItf) GetFeeRules(ctx context.Context, operation enum.JournalEntryType, currency string) ([]entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeeRules", ctx, operation, currency)
+	ret0, _ := ret[0].([]entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeeRules indicates an expected call of GetFeeRules.
+func (mr *MockDomainItfMockRecorder) GetFeeRules(ctx, operation, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeRules", reflect.TypeOf((*MockDomainItf)(nil).GetFeeRules), ctx, operation, currency)
+}
@@ -0,0 +1,20 @@
+package domainfee
+
+const (
+	queryGetActiveFeeRulesByOperationCurrency = `
+		SELECT
+			id,
+			operation,
+			currency,
+			min_amount,
+			max_amount,
+			flat_fee,
+			rate_bps,
+			free_per_month
+		FROM
+			fee_rules
+		WHERE
+			operation = $1 AND currency = $2 AND active
+		ORDER BY min_amount, id;
+	`
+)
@@ -0,0 +1,30 @@
+package domainfx
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+type domain struct {
+	db    database.DatabaseItf
+	stmts databaseStmts
+}
+
+type databaseStmts struct {
+	getFxRate *sqlx.Stmt
+}
+
+func Init(db database.DatabaseItf) DomainItf {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	return &domain{
+		db: db,
+		stmts: databaseStmts{
+			getFxRate: db.PreparexContext(ctx, queryGetFxRate),
+		},
+	}
+}
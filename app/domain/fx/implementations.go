@@ -0,0 +1,19 @@
+package domainfx
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+// GetFxRate returns the rate of the pair in effect at at, or sql.ErrNoRows when the pair has none. Rates move too
+// often to be cached, and a conversion has to be priced at the rate its quote showed.
+func (d domain) GetFxRate(ctx context.Context, baseCurrency string, quoteCurrency string, at time.Time) (resp entity.FxRate, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getFxRate, &resp, baseCurrency, quoteCurrency, at)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,117 @@
+package domainfx
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_domain_GetFxRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	rate := entity.FxRate{
+		Id:            1,
+		BaseCurrency:  "USD",
+		QuoteCurrency: "IDR",
+		Rate:          1625000000000,
+		EffectiveAt:   at.Add(-time.Hour),
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx           context.Context
+		baseCurrency  string
+		quoteCurrency string
+		at            time.Time
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.FxRate
+		wantErr  error
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getFxRate: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:           context.Background(),
+				baseCurrency:  "USD",
+				quoteCurrency: "IDR",
+				at:            at,
+			},
+			wantResp: rate,
+			wantErr:  nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "USD", "IDR", at).SetArg(2, rate).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error no rate",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getFxRate: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:           context.Background(),
+				baseCurrency:  "USD",
+				quoteCurrency: "XAF",
+				at:            at,
+			},
+			wantResp: entity.FxRate{},
+			wantErr:  sql.ErrNoRows,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "USD", "XAF", at).Return(sql.ErrNoRows),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetFxRate(tt.args.ctx, tt.args.baseCurrency, tt.args.quoteCurrency, tt.args.at)
+			if err != tt.wantErr {
+				t.Errorf("domain.GetFxRate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetFxRate() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package domainfx
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+type DomainItf interface {
+	GetFxRate(ctx context.Context, baseCurrency string, quoteCurrency string, at time.Time) (resp entity.FxRate, err error)
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/domain/fx/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/domain/fx/interfaces.go -destination=app/domain/fx/mock.go -package=domainfx
+//
+
+// Package domainfx is a generated GoMock package.
+package domainfx
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/kevinsudut/wallet-system/app/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// GetFxRate mocks base method.
+func (m *MockDomainItf) GetFxRate(ctx context.Context, baseCurrency, quoteCurrency string, at time.Time) (entity.FxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFxRate", ctx, baseCurrency, quoteCurrency, at)
+	ret0, _ := ret[0].(entity.FxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFxRate indicates an expected call of GetFxRate.
+func (mr *MockDomainItfMockRecorder) GetFxRate(ctx, baseCurrency, quoteCurrency, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFxRate", reflect.TypeOf((*MockDomainItf)(nil).GetFxRate), ctx, baseCurrency, quoteCurrency, at)
+}
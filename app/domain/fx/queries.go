@@ -0,0 +1,18 @@
+package domainfx
+
+const (
+	queryGetFxRate = `
+		SELECT
+			id,
+			base_currency,
+			quote_currency,
+			rate,
+			effective_at
+		FROM
+			fx_rates
+		WHERE
+			base_currency = $1 AND quote_currency = $2 AND effective_at <= $3
+		ORDER BY effective_at DESC, id DESC
+		LIMIT 1;
+	`
+)
@@ -0,0 +1,38 @@
+package domainlimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+type domain struct {
+	db    database.DatabaseItf
+	redis redis.RedisItf
+	cache lrucache.LRUCacheItf
+	stmts databaseStmts
+}
+
+type databaseStmts struct {
+	getUsageByAccountId         *sqlx.Stmt
+	getTierLimitsByTierCurrency *sqlx.Stmt
+}
+
+func Init(db database.DatabaseItf, redis redis.RedisItf) DomainItf {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	return &domain{
+		db:    db,
+		redis: redis,
+		cache: lrucache.Init(),
+		stmts: databaseStmts{
+			getUsageByAccountId:         db.PreparexContext(ctx, queryGetUsageByAccountId),
+			getTierLimitsByTierCurrency: db.PreparexContext(ctx, queryGetTierLimitsByTierCurrency),
+		},
+	}
+}
@@ -0,0 +1,185 @@
+package domainlimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+const (
+	// Counters drift when Redis loses an increment or a reservation is never released, so they expire and are
+	// seeded again from the ledger at least once per TTL.
+	usageCacheTTL = time.Hour
+
+	// Limits are edited in the table by hand and rarely change, so every instance keeps them for a minute.
+	tierLimitsCacheTTL = time.Minute
+)
+
+// usageJournalEntries maps every operation to the journal entries counted as its usage and the sign of the
+// posting on the account of the user.
+var usageJournalEntries = map[enum.LimitOperation]struct {
+	journalEntryType enum.JournalEntryType
+	sign             int
+}{
+	enum.LIMIT_OPERATION_TOPUP:    {journalEntryType: enum.TOPUP, sign: 1},
+	enum.LIMIT_OPERATION_TRANSFER: {journalEntryType: enum.TRANSFER, sign: -1},
+}
+
+// GetUsage returns the usage of the window from the Redis counters, seeding them from the ledger when they are
+// missing. The ledger is read directly while Redis is unavailable.
+func (d domain) GetUsage(ctx context.Context, req GetUsageRequest) (resp entity.LimitUsage, err error) {
+	amountKey, countKey := usageKeys(req.UserId, req.Operation, req.Window)
+
+	resp, err = d.getCachedUsage(ctx, amountKey, countKey)
+	if err == nil {
+		return resp, nil
+	}
+	if !redis.IsNil(err) {
+		log.Errorln("GetUsage.getCachedUsage", err)
+	}
+
+	resp, err = d.getUsageFromDatabase(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	// SetNX keeps a counter another request seeded and already reserved on.
+	_, err = d.redis.SetNX(ctx, amountKey, int64(resp.Amount), usageCacheTTL)
+	if err != nil {
+		log.Errorln("GetUsage.SetNX", amountKey, err)
+		return resp, nil
+	}
+
+	_, err = d.redis.SetNX(ctx, countKey, resp.Count, usageCacheTTL)
+	if err != nil {
+		log.Errorln("GetUsage.SetNX", countKey, err)
+	}
+
+	return resp, nil
+}
+
+// ReserveUsage adds one operation of req.Amount to the counters of the window and returns the usage including
+// it, so concurrent requests never see the same remaining limit. The increment sets the TTL of a counter that
+// expired since it was seeded in the same step, so a counter is never left without one. It fails when Redis is
+// unavailable.
+func (d domain) ReserveUsage(ctx context.Context, req ReserveUsageRequest) (resp entity.LimitUsage, err error) {
+	_, err = d.GetUsage(ctx, GetUsageRequest{
+		UserId:    req.UserId,
+		Operation: req.Operation,
+		Window:    req.Window,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	amountKey, countKey := usageKeys(req.UserId, req.Operation, req.Window)
+
+	amount, err := d.redis.IncrByEx(ctx, amountKey, int64(req.Amount), usageCacheTTL)
+	if err != nil {
+		return resp, err
+	}
+
+	count, err := d.redis.IncrByEx(ctx, countKey, 1, usageCacheTTL)
+	if err != nil {
+		_, errUndo := d.redis.IncrByEx(ctx, amountKey, -int64(req.Amount), usageCacheTTL)
+		if errUndo != nil {
+			log.Errorln("ReserveUsage.IncrByEx", amountKey, errUndo)
+		}
+
+		return resp, err
+	}
+
+	return entity.LimitUsage{
+		Amount: money.Money(amount),
+		Count:  count,
+	}, nil
+}
+
+// ReleaseUsage takes back an operation reserved by ReserveUsage that did not happen.
+func (d domain) ReleaseUsage(ctx context.Context, req ReserveUsageRequest) (err error) {
+	amountKey, countKey := usageKeys(req.UserId, req.Operation, req.Window)
+
+	_, err = d.redis.IncrByEx(ctx, amountKey, -int64(req.Amount), usageCacheTTL)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.redis.IncrByEx(ctx, countKey, -1, usageCacheTTL)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTierLimits returns the limits of tier in a currency set in tier_limits, none when the tier has no row there in
+// that currency.
+func (d domain) GetTierLimits(ctx context.Context, tier enum.UserTier, currency string) (resp []entity.Limit, err error) {
+	limits, err := d.cache.Fetch(fmt.Sprintf(cacheKeyGetTierLimits, tier, currency), tierLimitsCacheTTL, func() (interface{}, error) {
+		var limits []entity.Limit
+		err := d.db.SelectContextStmt(ctx, d.stmts.getTierLimitsByTierCurrency, &limits, int(tier), currency)
+		if err != nil {
+			return limits, err
+		}
+
+		return limits, nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	return limits.Value().([]entity.Limit), nil
+}
+
+func (d domain) getCachedUsage(ctx context.Context, amountKey string, countKey string) (resp entity.LimitUsage, err error) {
+	amountStr, err := d.redis.Get(ctx, amountKey)
+	if err != nil {
+		return resp, err
+	}
+
+	countStr, err := d.redis.Get(ctx, countKey)
+	if err != nil {
+		return resp, err
+	}
+
+	amount, err := strconv.ParseInt(amountStr, 10, 64)
+	if err != nil {
+		return resp, err
+	}
+
+	count, err := strconv.ParseInt(countStr, 10, 64)
+	if err != nil {
+		return resp, err
+	}
+
+	return entity.LimitUsage{
+		Amount: money.Money(amount),
+		Count:  count,
+	}, nil
+}
+
+func (d domain) getUsageFromDatabase(ctx context.Context, req GetUsageRequest) (resp entity.LimitUsage, err error) {
+	journalEntries, ok := usageJournalEntries[req.Operation]
+	if !ok {
+		return resp, fmt.Errorf("unknown limit operation %d", req.Operation)
+	}
+
+	err = d.db.GetContextStmt(ctx, d.stmts.getUsageByAccountId, &resp, req.UserId, int(journalEntries.journalEntryType), journalEntries.sign, req.Window.From, req.Window.To)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func usageKeys(userId string, operation enum.LimitOperation, window entity.LimitWindow) (amountKey string, countKey string) {
+	return fmt.Sprintf(cacheKeyUsageAmount, userId, operation, window.Period, window.From.Unix()),
+		fmt.Sprintf(cacheKeyUsageCount, userId, operation, window.Period, window.From.Unix())
+}
@@ -0,0 +1,564 @@
+package domainlimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+	goredis "github.com/redis/go-redis/v9"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	window = entity.LimitWindow{
+		Period: enum.LIMIT_PERIOD_DAY,
+		From:   time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+
+	amountKey = fmt.Sprintf(cacheKeyUsageAmount, "id", enum.LIMIT_OPERATION_TRANSFER, enum.LIMIT_PERIOD_DAY, window.From.Unix())
+	countKey  = fmt.Sprintf(cacheKeyUsageCount, "id", enum.LIMIT_OPERATION_TRANSFER, enum.LIMIT_PERIOD_DAY, window.From.Unix())
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_domain_GetUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req GetUsageRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.LimitUsage
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success from redis",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetUsageRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Window:    window,
+				},
+			},
+			wantResp: entity.LimitUsage{
+				Amount: 1000,
+				Count:  2,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), amountKey).Return("1000", nil),
+					mockRedis.EXPECT().Get(gomock.Any(), countKey).Return("2", nil),
+				)
+			},
+		},
+		{
+			name: "success seed from database",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getUsageByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetUsageRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Window:    window,
+				},
+			},
+			wantResp: entity.LimitUsage{
+				Amount: 1000,
+				Count:  2,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), amountKey).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TRANSFER), -1, window.From, window.To).SetArg(2, entity.LimitUsage{
+						Amount: 1000,
+						Count:  2,
+					}).Return(nil),
+					mockRedis.EXPECT().SetNX(gomock.Any(), amountKey, int64(1000), usageCacheTTL).Return(true, nil),
+					mockRedis.EXPECT().SetNX(gomock.Any(), countKey, int64(2), usageCacheTTL).Return(true, nil),
+				)
+			},
+		},
+		{
+			name: "success redis unavailable",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getUsageByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetUsageRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TOPUP,
+					Window:    window,
+				},
+			},
+			wantResp: entity.LimitUsage{
+				Amount: 500,
+				Count:  1,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", fmt.Errorf("foo")),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.TOPUP), 1, window.From, window.To).SetArg(2, entity.LimitUsage{
+						Amount: 500,
+						Count:  1,
+					}).Return(nil),
+					mockRedis.EXPECT().SetNX(gomock.Any(), gomock.Any(), int64(500), usageCacheTTL).Return(false, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error GetContextStmt",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getUsageByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetUsageRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Window:    window,
+				},
+			},
+			wantResp: entity.LimitUsage{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), amountKey).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unknown operation",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: GetUsageRequest{
+					UserId: "id",
+					Window: window,
+				},
+			},
+			wantResp: entity.LimitUsage{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), gomock.Any()).Return("", goredis.Nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				redis: tt.fields.redis,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetUsage(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetUsage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetUsage() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_ReserveUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	req := ReserveUsageRequest{
+		UserId:    "id",
+		Operation: enum.LIMIT_OPERATION_TRANSFER,
+		Window:    window,
+		Amount:    300,
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req ReserveUsageRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.LimitUsage
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: entity.LimitUsage{
+				Amount: 1300,
+				Count:  3,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), amountKey).Return("1000", nil),
+					mockRedis.EXPECT().Get(gomock.Any(), countKey).Return("2", nil),
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), amountKey, int64(300), usageCacheTTL).Return(int64(1300), nil),
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), countKey, int64(1), usageCacheTTL).Return(int64(3), nil),
+				)
+			},
+		},
+		{
+			name: "error GetUsage",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: databaseStmts{
+					getUsageByAccountId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: entity.LimitUsage{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), amountKey).Return("", goredis.Nil),
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error IncrByEx amount",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: entity.LimitUsage{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), amountKey).Return("1000", nil),
+					mockRedis.EXPECT().Get(gomock.Any(), countKey).Return("2", nil),
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), amountKey, int64(300), usageCacheTTL).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error IncrByEx count",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: entity.LimitUsage{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), amountKey).Return("1000", nil),
+					mockRedis.EXPECT().Get(gomock.Any(), countKey).Return("2", nil),
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), amountKey, int64(300), usageCacheTTL).Return(int64(1300), nil),
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), countKey, int64(1), usageCacheTTL).Return(int64(0), fmt.Errorf("foo")),
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), amountKey, int64(-300), usageCacheTTL).Return(int64(1000), nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				redis: tt.fields.redis,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.ReserveUsage(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.ReserveUsage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.ReserveUsage() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_ReleaseUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	req := ReserveUsageRequest{
+		UserId:    "id",
+		Operation: enum.LIMIT_OPERATION_TRANSFER,
+		Window:    window,
+		Amount:    300,
+	}
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx context.Context
+		req ReserveUsageRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), amountKey, int64(-300), usageCacheTTL).Return(int64(1000), nil),
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), countKey, int64(-1), usageCacheTTL).Return(int64(2), nil),
+				)
+			},
+		},
+		{
+			name: "error IncrByEx amount",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), amountKey, int64(-300), usageCacheTTL).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error IncrByEx count",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), amountKey, int64(-300), usageCacheTTL).Return(int64(1000), nil),
+					mockRedis.EXPECT().IncrByEx(gomock.Any(), countKey, int64(-1), usageCacheTTL).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			tt.mock()
+			if err := d.ReleaseUsage(tt.args.ctx, tt.args.req); (err != nil) != tt.wantErr {
+				t.Errorf("domain.ReleaseUsage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetTierLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	limits := []entity.Limit{
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_TRANSACTION, MaxAmount: 100000},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_DAY, MaxAmount: 200000, MaxCount: 5},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		cache lrucache.LRUCacheItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx      context.Context
+		tier     enum.UserTier
+		currency string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.Limit
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				cache: lrucache.Init(),
+				stmts: databaseStmts{
+					getTierLimitsByTierCurrency: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				tier:     enum.USER_TIER_VERIFIED,
+				currency: "IDR",
+			},
+			wantResp: limits,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_TIER_VERIFIED), "IDR").SetArg(2, limits).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success without limits",
+			fields: fields{
+				db:    mockDatabase,
+				cache: lrucache.Init(),
+				stmts: databaseStmts{
+					getTierLimitsByTierCurrency: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				tier:     enum.USER_TIER_BASIC,
+				currency: "IDR",
+			},
+			wantResp: nil,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_TIER_BASIC), "IDR").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt db",
+			fields: fields{
+				db:    mockDatabase,
+				cache: lrucache.Init(),
+				stmts: databaseStmts{
+					getTierLimitsByTierCurrency: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				tier:     enum.USER_TIER_BASIC,
+				currency: "IDR",
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.USER_TIER_BASIC), "IDR").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				cache: tt.fields.cache,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetTierLimits(tt.args.ctx, tt.args.tier, tt.args.currency)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetTierLimits() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetTierLimits() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package domainlimit
+
+import (
+	"context"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+type DomainItf interface {
+	GetUsage(ctx context.Context, req GetUsageRequest) (resp entity.LimitUsage, err error)
+	ReserveUsage(ctx context.Context, req ReserveUsageRequest) (resp entity.LimitUsage, err error)
+	ReleaseUsage(ctx context.Context, req ReserveUsageRequest) (err error)
+	GetTierLimits(ctx context.Context, tier enum.UserTier, currency string) (resp []entity.Limit, err error)
+}
@@ -0,0 +1,8 @@
+package domainlimit
+
+const (
+	cacheKeyUsageAmount = "domain:limit:usage:user_id:%s:operation:%d:period:%s:from:%d:amount"
+	cacheKeyUsageCount  = "domain:limit:usage:user_id:%s:operation:%d:period:%s:from:%d:count"
+
+	cacheKeyGetTierLimits = "domain:limit:tier_limits:tier:%d:currency:%s"
+)
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/domain/limit/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/domain/limit/interfaces.go -destination=app/domain/limit/mock.go -package=domainlimit
+//
+
+// Package domainlimit is a generated GoMock package.
+package domainlimit
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/kevinsudut/wallet-system/app/entity"
+	enum "github.com/kevinsudut/wallet-system/app/enum"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// GetTierLimits mocks base method.
+func (m *MockDomainItf) GetTierLimits(ctx context.Context, tier enum.UserTier, currency string) ([]entity.Limit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTierLimits", ctx, tier, currency)
+	ret0, _ := ret[0].([]entity.Limit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTierLimits indicates an expected call of GetTierLimits.
+func (mr *MockDomainItfMockRecorder) GetTierLimits(ctx, tier, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTierLimits", reflect.TypeOf((*MockDomainItf)(nil).GetTierLimits), ctx, tier, currency)
+}
+
+// GetUsage mocks base method.
+func (m *MockDomainItf) GetUsage(ctx context.Context, req GetUsageRequest) (entity.LimitUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", ctx, req)
+	ret0, _ := ret[0].(entity.LimitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockDomainItfMockRecorder) GetUsage(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockDomainItf)(nil).GetUsage), ctx, req)
+}
+
+// ReleaseUsage mocks base method.
+func (m *MockDomainItf) ReleaseUsage(ctx context.Context, req ReserveUsageRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseUsage", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseUsage indicates an expected call of ReleaseUsage.
+func (mr *MockDomainItfMockRecorder) ReleaseUsage(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseUsage", reflect.TypeOf((*MockDomainItf)(nil).ReleaseUsage), ctx, req)
+}
+
+// ReserveUsage mocks base method.
+func (m *MockDomainItf) ReserveUsage(ctx context.Context, req ReserveUsageRequest) (entity.LimitUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveUsage", ctx, req)
+	ret0, _ := ret[0].(entity.LimitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveUsage indicates an expected call of ReserveUsage.
+func (mr *MockDomainItfMockRecorder) ReserveUsage(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveUsage", reflect.TypeOf((*MockDomainItf)(nil).ReserveUsage), ctx, req)
+}
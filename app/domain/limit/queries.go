@@ -0,0 +1,32 @@
+package domainlimit
+
+const (
+	// Per-transaction limits come first so they are checked before any counter is reserved.
+	queryGetTierLimitsByTierCurrency = `
+		SELECT
+			operation,
+			period,
+			max_amount,
+			max_count
+		FROM
+			tier_limits
+		WHERE
+			tier = $1 AND currency = $2
+		ORDER BY CASE WHEN period = 'transaction' THEN 0 ELSE 1 END, operation, period;
+	`
+
+	queryGetUsageByAccountId = `
+		SELECT
+			COALESCE(SUM(ABS(p.amount)), 0) AS amount,
+			COUNT(*) AS count
+		FROM
+			postings p
+			JOIN journal_entries je ON je.id = p.journal_entry_id
+		WHERE
+			p.account_id = $1
+			AND je.type = $2
+			AND SIGN(p.amount) = $3
+			AND p.created_at >= $4
+			AND p.created_at < $5;
+	`
+)
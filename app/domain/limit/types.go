@@ -0,0 +1,20 @@
+package domainlimit
+
+import (
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+type GetUsageRequest struct {
+	UserId    string
+	Operation enum.LimitOperation
+	Window    entity.LimitWindow
+}
+
+type ReserveUsageRequest struct {
+	UserId    string
+	Operation enum.LimitOperation
+	Window    entity.LimitWindow
+	Amount    money.Money
+}
@@ -0,0 +1,15 @@
+package domainnotification
+
+import (
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+type domain struct {
+	redis redis.RedisItf
+}
+
+func Init(redis redis.RedisItf) DomainItf {
+	return &domain{
+		redis: redis,
+	}
+}
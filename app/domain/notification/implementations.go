@@ -0,0 +1,77 @@
+package domainnotification
+
+import (
+	"context"
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+// PublishNotification appends notification to the stream of its user, which gives it its id, and publishes it to
+// every instance. The stream of a user that gets no notification for a while expires.
+func (d domain) PublishNotification(ctx context.Context, notification entity.Notification) (resp entity.Notification, err error) {
+	key := fmt.Sprintf(streamKeyNotificationsByUserId, notification.UserId)
+
+	notification.Id, err = d.redis.XAdd(ctx, key, maxNotificationsPerUser, notification.StreamValues())
+	if err != nil {
+		return resp, err
+	}
+
+	_, err = d.redis.Expire(ctx, key, notificationsExpiration)
+	if err != nil {
+		return resp, err
+	}
+
+	payload, err := jsoniter.MarshalToString(notification)
+	if err != nil {
+		return resp, err
+	}
+
+	_, err = d.redis.Publish(ctx, channelNotifications, payload)
+	if err != nil {
+		return resp, err
+	}
+
+	return notification, nil
+}
+
+// GetNotificationsAfter reads up to count notifications of userId that came after the one with lastId. An entry
+// that is not a notification is skipped.
+func (d domain) GetNotificationsAfter(ctx context.Context, userId string, lastId string, count int64) (resp []entity.Notification, err error) {
+	messages, err := d.redis.XRange(ctx, fmt.Sprintf(streamKeyNotificationsByUserId, userId), "("+lastId, "+", count)
+	if err != nil {
+		return resp, err
+	}
+
+	resp = make([]entity.Notification, 0, len(messages))
+	for _, message := range messages {
+		notification, err := entity.NewNotificationFromStream(message.ID, message.Values)
+		if err != nil {
+			log.Errorln("GetNotificationsAfter.NewNotificationFromStream", err)
+			continue
+		}
+
+		resp = append(resp, notification)
+	}
+
+	return resp, nil
+}
+
+// SubscribeNotifications calls handle with the notifications published by any instance until ctx is done or the
+// subscription is lost, and returns why it stopped.
+func (d domain) SubscribeNotifications(ctx context.Context, handle func(entity.Notification)) (err error) {
+	return d.redis.Subscribe(ctx, channelNotifications, func(message redis.Message) {
+		var notification entity.Notification
+
+		err := jsoniter.UnmarshalFromString(message.Payload, &notification)
+		if err != nil {
+			log.Errorln("SubscribeNotifications.Unmarshal", err)
+			return
+		}
+
+		handle(notification)
+	})
+}
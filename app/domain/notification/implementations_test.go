@@ -0,0 +1,327 @@
+package domainnotification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_domain_PublishNotification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	notification := entity.Notification{
+		UserId:    "id",
+		Type:      "balance.changed",
+		Data:      jsoniter.RawMessage(`{"balance":1000}`),
+		CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+	published := notification
+	published.Id = "1-0"
+	payload, _ := jsoniter.MarshalToString(published)
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx          context.Context
+		notification entity.Notification
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.Notification
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:          context.Background(),
+				notification: notification,
+			},
+			wantResp: published,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XAdd(gomock.Any(), "stream:notifications:id", int64(maxNotificationsPerUser), notification.StreamValues()).Return("1-0", nil),
+					mockRedis.EXPECT().Expire(gomock.Any(), "stream:notifications:id", notificationsExpiration).Return(true, nil),
+					mockRedis.EXPECT().Publish(gomock.Any(), channelNotifications, payload).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error XAdd redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:          context.Background(),
+				notification: notification,
+			},
+			wantResp: entity.Notification{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XAdd(gomock.Any(), "stream:notifications:id", int64(maxNotificationsPerUser), notification.StreamValues()).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error Expire redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:          context.Background(),
+				notification: notification,
+			},
+			wantResp: entity.Notification{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XAdd(gomock.Any(), "stream:notifications:id", int64(maxNotificationsPerUser), notification.StreamValues()).Return("1-0", nil),
+					mockRedis.EXPECT().Expire(gomock.Any(), "stream:notifications:id", notificationsExpiration).Return(false, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error Publish redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:          context.Background(),
+				notification: notification,
+			},
+			wantResp: entity.Notification{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XAdd(gomock.Any(), "stream:notifications:id", int64(maxNotificationsPerUser), notification.StreamValues()).Return("1-0", nil),
+					mockRedis.EXPECT().Expire(gomock.Any(), "stream:notifications:id", notificationsExpiration).Return(true, nil),
+					mockRedis.EXPECT().Publish(gomock.Any(), channelNotifications, payload).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			gotResp, err := d.PublishNotification(tt.args.ctx, tt.args.notification)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.PublishNotification() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.PublishNotification() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetNotificationsAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	notification := entity.Notification{
+		Id:        "1-1",
+		UserId:    "id",
+		Type:      "balance.changed",
+		Data:      jsoniter.RawMessage(`{"balance":1000}`),
+		CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+		lastId string
+		count  int64
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.Notification
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success skip malformed entry",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				lastId: "1-0",
+				count:  100,
+			},
+			wantResp: []entity.Notification{notification},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XRange(gomock.Any(), "stream:notifications:id", "(1-0", "+", int64(100)).Return([]redis.XMessage{
+						{
+							ID:     "1-1",
+							Values: notification.StreamValues(),
+						},
+						{
+							ID: "1-2",
+							Values: map[string]interface{}{
+								"type": "balance.changed",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error XRange redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				lastId: "1-0",
+				count:  100,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XRange(gomock.Any(), "stream:notifications:id", "(1-0", "+", int64(100)).Return([]redis.XMessage{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			gotResp, err := d.GetNotificationsAfter(tt.args.ctx, tt.args.userId, tt.args.lastId, tt.args.count)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetNotificationsAfter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetNotificationsAfter() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_SubscribeNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	notification := entity.Notification{
+		Id:        "1-0",
+		UserId:    "id",
+		Type:      "balance.changed",
+		Data:      jsoniter.RawMessage(`{"balance":1000}`),
+		CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+	payload, _ := jsoniter.MarshalToString(notification)
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []entity.Notification
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success skip malformed message",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    []entity.Notification{notification},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Subscribe(gomock.Any(), channelNotifications, gomock.Any()).DoAndReturn(func(ctx context.Context, channel string, handle func(redis.Message)) error {
+						handle(redis.Message{Channel: channel, Payload: payload})
+						handle(redis.Message{Channel: channel, Payload: "{"})
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "error Subscribe redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    nil,
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Subscribe(gomock.Any(), channelNotifications, gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			var got []entity.Notification
+			err := d.SubscribeNotifications(tt.args.ctx, func(notification entity.Notification) {
+				got = append(got, notification)
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.SubscribeNotifications() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("domain.SubscribeNotifications() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package domainnotification
+
+import (
+	"context"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+type DomainItf interface {
+	PublishNotification(ctx context.Context, notification entity.Notification) (resp entity.Notification, err error)
+	GetNotificationsAfter(ctx context.Context, userId string, lastId string, count int64) (resp []entity.Notification, err error)
+	SubscribeNotifications(ctx context.Context, handle func(entity.Notification)) (err error)
+}
@@ -0,0 +1,16 @@
+package domainnotification
+
+import "time"
+
+const (
+	// streamKeyNotificationsByUserId keeps the latest notifications of a user, so a client can resume after the
+	// last one it has seen.
+	streamKeyNotificationsByUserId = "stream:notifications:%s"
+
+	// channelNotifications is the channel every instance subscribes to, to push the notifications of the users
+	// connected to it.
+	channelNotifications = "notifications"
+
+	maxNotificationsPerUser = 1000
+	notificationsExpiration = 24 * time.Hour
+)
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/domain/notification/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/domain/notification/interfaces.go -destination=app/domain/notification/mock.go -package=domainnotification
+//
+
+// Package domainnotification is a generated GoMock package.
+package domainnotification
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/kevinsudut/wallet-system/app/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// GetNotificationsAfter mocks base method.
+func (m *MockDomainItf) GetNotificationsAfter(ctx context.Context, userId, lastId string, count int64) ([]entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationsAfter", ctx, userId, lastId, count)
+	ret0, _ := ret[0].([]entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationsAfter indicates an expected call of GetNotificationsAfter.
+func (mr *MockDomainItfMockRecorder) GetNotificationsAfter(ctx, userId, lastId, count any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsAfter", reflect.TypeOf((*MockDomainItf)(nil).GetNotificationsAfter), ctx, userId, lastId, count)
+}
+
+// PublishNotification mocks base method.
+func (m *MockDomainItf) PublishNotification(ctx context.Context, notification entity.Notification) (entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishNotification", ctx, notification)
+	ret0, _ := ret[0].(entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishNotification indicates an expected call of PublishNotification.
+func (mr *MockDomainItfMockRecorder) PublishNotification(ctx, notification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishNotification", reflect.TypeOf((*MockDomainItf)(nil).PublishNotification), ctx, notification)
+}
+
+// SubscribeNotifications mocks base method.
+func (m *MockDomainItf) SubscribeNotifications(ctx context.Context, handle func(entity.Notification)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeNotifications", ctx, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeNotifications indicates an expected call of SubscribeNotifications.
+func (mr *MockDomainItfMockRecorder) SubscribeNotifications(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeNotifications", reflect.TypeOf((*MockDomainItf)(nil).SubscribeNotifications), ctx, handle)
+}
@@ -0,0 +1,57 @@
+package domainoutbox
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+type domain struct {
+	db    database.DatabaseItf
+	redis redis.RedisItf
+	stmts databaseStmts
+}
+
+type databaseStmts struct {
+	lockOutboxRelay             *sqlx.Stmt
+	getUnpublishedOutboxEvents  *sqlx.Stmt
+	markOutboxEventPublished    *sqlx.Stmt
+	deletePublishedOutboxEvents *sqlx.Stmt
+}
+
+func Init(ctx context.Context, db database.DatabaseItf, redis redis.RedisItf) DomainItf {
+	prepareCtx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	d := &domain{
+		db:    db,
+		redis: redis,
+		stmts: databaseStmts{
+			lockOutboxRelay:             db.PreparexContext(prepareCtx, queryLockOutboxRelay),
+			getUnpublishedOutboxEvents:  db.PreparexContext(prepareCtx, queryGetUnpublishedOutboxEvents),
+			markOutboxEventPublished:    db.PreparexContext(prepareCtx, queryMarkOutboxEventPublished),
+			deletePublishedOutboxEvents: db.PreparexContext(prepareCtx, queryDeletePublishedOutboxEvents),
+		},
+	}
+
+	go d.runOutboxRelay(ctx)
+	go d.runOutboxPruning(ctx)
+
+	return d
+}
+
+// ConsumerName names the instance in the consumer groups of the event stream, so it reads back the events it was
+// handed before a restart. It has to differ between instances and stay the same across restarts of one, as a
+// hostname does.
+func ConsumerName() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "wallet-system"
+	}
+
+	return hostname
+}
@@ -0,0 +1,201 @@
+package domainoutbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	outboxRelayInterval = time.Second
+	outboxRelayTimeout  = time.Minute
+	outboxRelayBatch    = 100
+
+	// outboxRelayLockId is the advisory lock of the relay, any number no other advisory lock uses.
+	outboxRelayLockId = 21
+
+	// streamMaxLen bounds the stream, consumers that fall further behind than that lose the oldest events.
+	streamMaxLen = 1000000
+
+	outboxPruneInterval = time.Hour
+	outboxPruneTimeout  = time.Minute
+
+	// outboxEventRetention is how long published events are kept, to look into what was sent to the stream.
+	outboxEventRetention = 7 * 24 * time.Hour
+)
+
+// runOutboxRelay relays the outbox on start and then every interval, until ctx is done. Every instance runs it,
+// the advisory lock keeps all but one of them waiting for the next interval.
+func (d domain) runOutboxRelay(ctx context.Context) {
+	ticker := time.NewTicker(outboxRelayInterval)
+	defer ticker.Stop()
+
+	for {
+		relayCtx, cancel := context.WithTimeout(ctx, outboxRelayTimeout)
+		err := d.relayOutbox(relayCtx)
+		cancel()
+		if err != nil {
+			log.Errorln("runOutboxRelay.relayOutbox", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayOutbox relays the outbox batch by batch, until a batch comes back short.
+func (d domain) relayOutbox(ctx context.Context) (err error) {
+	for {
+		count, err := d.RelayOutboxEvents(ctx)
+		if err != nil {
+			return err
+		}
+
+		if count < outboxRelayBatch {
+			return nil
+		}
+	}
+}
+
+// RelayOutboxEvents publishes the oldest unpublished events to the event stream and returns how many it
+// published, none when another instance is relaying. An event is marked in the transaction that holds the lock
+// only after it is on the stream, so a failure publishes the events of the batch again and consumers have to
+// tell them apart by event_id.
+func (d domain) RelayOutboxEvents(ctx context.Context) (resp int, err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return resp, err
+	}
+
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+	}()
+
+	var locked bool
+	err = d.db.GetContextStmtTx(ctx, tx, d.stmts.lockOutboxRelay, &locked, outboxRelayLockId)
+	if err != nil {
+		return resp, err
+	}
+
+	if !locked {
+		return 0, nil
+	}
+
+	var events []entity.OutboxEvent
+	err = d.db.SelectContextStmtTx(ctx, tx, d.stmts.getUnpublishedOutboxEvents, &events, outboxRelayBatch)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, event := range events {
+		_, err = d.redis.XAdd(ctx, streamKeyEvents, streamMaxLen, event.StreamValues())
+		if err != nil {
+			return resp, err
+		}
+
+		err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.markOutboxEventPublished, event.Id)
+		if err != nil {
+			return resp, err
+		}
+
+		resp++
+	}
+
+	return resp, nil
+}
+
+// runOutboxPruning prunes the outbox on start and then every interval, until ctx is done. Deleting is idempotent,
+// so every instance runs it without coordinating with the others.
+func (d domain) runOutboxPruning(ctx context.Context) {
+	ticker := time.NewTicker(outboxPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		pruneCtx, cancel := context.WithTimeout(ctx, outboxPruneTimeout)
+		err := d.pruneOutboxEvents(pruneCtx, time.Now())
+		cancel()
+		if err != nil {
+			log.Errorln("runOutboxPruning.pruneOutboxEvents", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneOutboxEvents deletes the events published longer than the retention ago. Unpublished events are kept however
+// old they are, the relay still has to publish them.
+func (d domain) pruneOutboxEvents(ctx context.Context, now time.Time) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.deletePublishedOutboxEvents, now.Add(-outboxEventRetention))
+	if err == database.ErrNoRowsAffected {
+		return nil
+	}
+
+	return err
+}
+
+// CreateEventGroup creates the consumer group of the event stream unless it exists. A new group starts at the end
+// of the stream, so it only reads the events published from then on.
+func (d domain) CreateEventGroup(ctx context.Context, group string) (err error) {
+	_, err = d.redis.XGroupCreateMkStream(ctx, streamKeyEvents, group, "$")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadEvents reads events of the event stream for a consumer of a group. An entry that is not an event is skipped
+// and acknowledged, reading it again would not make it one.
+func (d domain) ReadEvents(ctx context.Context, req ReadEventsRequest) (resp []entity.OutboxEvent, err error) {
+	id := ">"
+	if req.Pending {
+		id = "0"
+	}
+
+	messages, err := d.redis.XReadGroup(ctx, req.Group, req.Consumer, streamKeyEvents, id, req.Count, req.Block)
+	if err != nil {
+		return resp, err
+	}
+
+	resp = make([]entity.OutboxEvent, 0, len(messages))
+	for _, message := range messages {
+		event, err := entity.NewOutboxEventFromStream(message.ID, message.Values)
+		if err != nil {
+			log.Errorln("ReadEvents.NewOutboxEventFromStream", err)
+
+			err = d.AckEvents(ctx, req.Group, message.ID)
+			if err != nil {
+				return resp, err
+			}
+			continue
+		}
+
+		resp = append(resp, event)
+	}
+
+	return resp, nil
+}
+
+// AckEvents acknowledges the events with streamIds to group, which does not read them again.
+func (d domain) AckEvents(ctx context.Context, group string, streamIds ...string) (err error) {
+	_, err = d.redis.XAck(ctx, streamKeyEvents, group, streamIds...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,494 @@
+package domainoutbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_domain_RelayOutboxEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	events := []entity.OutboxEvent{
+		{
+			Id:        1,
+			EventId:   "eid1",
+			UserId:    "id",
+			Type:      "BalanceDebited",
+			Version:   1,
+			Payload:   `{"user_id":"id"}`,
+			CreatedAt: createdAt,
+		},
+		{
+			Id:        2,
+			EventId:   "eid2",
+			UserId:    "id",
+			Type:      "TransferCompleted",
+			Version:   1,
+			Payload:   `{"transfer_id":"tid"}`,
+			CreatedAt: createdAt,
+		},
+	}
+	stmts := databaseStmts{
+		lockOutboxRelay:            &sqlx.Stmt{},
+		getUnpublishedOutboxEvents: &sqlx.Stmt{},
+		markOutboxEventPublished:   &sqlx.Stmt{},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		redis redis.RedisItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp int
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantResp: 2,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayLockId).SetArg(3, true).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayBatch).SetArg(3, events).Return(nil),
+					mockRedis.EXPECT().XAdd(gomock.Any(), streamKeyEvents, int64(streamMaxLen), events[0].StreamValues()).Return("1-0", nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int64(1)).Return(nil),
+					mockRedis.EXPECT().XAdd(gomock.Any(), streamKeyEvents, int64(streamMaxLen), events[1].StreamValues()).Return("1-1", nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int64(2)).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success another instance is relaying",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantResp: 0,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayLockId).SetArg(3, false).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error lockOutboxRelay",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayLockId).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error getUnpublishedOutboxEvents",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayLockId).SetArg(3, true).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayBatch).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error XAdd redis",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantResp: 1,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayLockId).SetArg(3, true).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayBatch).SetArg(3, events).Return(nil),
+					mockRedis.EXPECT().XAdd(gomock.Any(), streamKeyEvents, int64(streamMaxLen), events[0].StreamValues()).Return("1-0", nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int64(1)).Return(nil),
+					mockRedis.EXPECT().XAdd(gomock.Any(), streamKeyEvents, int64(streamMaxLen), events[1].StreamValues()).Return("", fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error markOutboxEventPublished",
+			fields: fields{
+				db:    mockDatabase,
+				redis: mockRedis,
+				stmts: stmts,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().GetContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayLockId).SetArg(3, true).Return(nil),
+					mockDatabase.EXPECT().SelectContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), outboxRelayBatch).SetArg(3, events).Return(nil),
+					mockRedis.EXPECT().XAdd(gomock.Any(), streamKeyEvents, int64(streamMaxLen), events[0].StreamValues()).Return("1-0", nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int64(1)).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			d := domain{
+				db:    tt.fields.db,
+				redis: tt.fields.redis,
+				stmts: tt.fields.stmts,
+			}
+			gotResp, err := d.RelayOutboxEvents(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.RelayOutboxEvents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp != tt.wantResp {
+				t.Errorf("domain.RelayOutboxEvents() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_pruneOutboxEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		now time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deletePublishedOutboxEvents: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				now: now,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), time.Date(2026, 10, 11, 12, 0, 0, 0, time.UTC)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success nothing to prune",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deletePublishedOutboxEvents: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				now: now,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), time.Date(2026, 10, 11, 12, 0, 0, 0, time.UTC)).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deletePublishedOutboxEvents: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				now: now,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), time.Date(2026, 10, 11, 12, 0, 0, 0, time.UTC)).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.pruneOutboxEvents(tt.args.ctx, tt.args.now); (err != nil) != tt.wantErr {
+				t.Errorf("domain.pruneOutboxEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_ReadEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	event := entity.OutboxEvent{
+		EventId:   "eid",
+		UserId:    "id",
+		Type:      "BalanceCredited",
+		Version:   1,
+		Payload:   `{"user_id":"id"}`,
+		CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		StreamId:  "1-0",
+	}
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	type args struct {
+		ctx context.Context
+		req ReadEventsRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.OutboxEvent
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadEventsRequest{
+					Group:    "group",
+					Consumer: "consumer",
+					Count:    10,
+					Block:    time.Second,
+				},
+			},
+			wantResp: []entity.OutboxEvent{event},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XReadGroup(gomock.Any(), "group", "consumer", streamKeyEvents, ">", int64(10), time.Second).Return([]redis.XMessage{
+						{
+							ID:     "1-0",
+							Values: event.StreamValues(),
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success pending skips an entry that is not an event",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadEventsRequest{
+					Group:    "group",
+					Consumer: "consumer",
+					Pending:  true,
+					Count:    10,
+				},
+			},
+			wantResp: []entity.OutboxEvent{event},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XReadGroup(gomock.Any(), "group", "consumer", streamKeyEvents, "0", int64(10), time.Duration(0)).Return([]redis.XMessage{
+						{
+							ID:     "0-1",
+							Values: map[string]interface{}{"foo": "bar"},
+						},
+						{
+							ID:     "1-0",
+							Values: event.StreamValues(),
+						},
+					}, nil),
+					mockRedis.EXPECT().XAck(gomock.Any(), streamKeyEvents, "group", "0-1").Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error XReadGroup",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadEventsRequest{
+					Group:    "group",
+					Consumer: "consumer",
+					Count:    10,
+				},
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XReadGroup(gomock.Any(), "group", "consumer", streamKeyEvents, ">", int64(10), time.Duration(0)).Return([]redis.XMessage{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error XAck",
+			fields: fields{
+				redis: mockRedis,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadEventsRequest{
+					Group:    "group",
+					Consumer: "consumer",
+					Count:    10,
+				},
+			},
+			wantResp: []entity.OutboxEvent{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().XReadGroup(gomock.Any(), "group", "consumer", streamKeyEvents, ">", int64(10), time.Duration(0)).Return([]redis.XMessage{
+						{
+							ID:     "0-1",
+							Values: map[string]interface{}{"foo": "bar"},
+						},
+					}, nil),
+					mockRedis.EXPECT().XAck(gomock.Any(), streamKeyEvents, "group", "0-1").Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			d := domain{
+				redis: tt.fields.redis,
+			}
+			gotResp, err := d.ReadEvents(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.ReadEvents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.ReadEvents() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package domainoutbox
+
+import (
+	"context"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+type DomainItf interface {
+	RelayOutboxEvents(ctx context.Context) (resp int, err error)
+
+	CreateEventGroup(ctx context.Context, group string) (err error)
+	ReadEvents(ctx context.Context, req ReadEventsRequest) (resp []entity.OutboxEvent, err error)
+	AckEvents(ctx context.Context, group string, streamIds ...string) (err error)
+}
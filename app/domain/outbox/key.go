@@ -0,0 +1,6 @@
+package domainoutbox
+
+const (
+	// streamKeyEvents is the Redis stream every event is published to, see entity.OutboxEvent.StreamValues.
+	streamKeyEvents = "stream:events"
+)
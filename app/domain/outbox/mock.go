@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/domain/outbox/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/domain/outbox/interfaces.go -destination=app/domain/outbox/mock.go -package=domainoutbox
+//
+
+// Package domainoutbox is a generated GoMock package.
+package domainoutbox
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/kevinsudut/wallet-system/app/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// AckEvents mocks base method.
+func (m *MockDomainItf) AckEvents(ctx context.Context, group string, streamIds ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, group}
+	for _, a := range streamIds {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AckEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AckEvents indicates an expected call of AckEvents.
+func (mr *MockDomainItfMockRecorder) AckEvents(ctx, group any, streamIds ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, group}, streamIds...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckEvents", reflect.TypeOf((*MockDomainItf)(nil).AckEvents), varargs...)
+}
+
+// CreateEventGroup mocks base method.
+func (m *MockDomainItf) CreateEventGroup(ctx context.Context, group string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEventGroup", ctx, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEventGroup indicates an expected call of CreateEventGroup.
+func (mr *MockDomainItfMockRecorder) CreateEventGroup(ctx, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEventGroup", reflect.TypeOf((*MockDomainItf)(nil).CreateEventGroup), ctx, group)
+}
+
+// ReadEvents mocks base method.
+func (m *MockDomainItf) ReadEvents(ctx context.Context, req ReadEventsRequest) ([]entity.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadEvents", ctx, req)
+	ret0, _ := ret[0].([]entity.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadEvents indicates an expected call of ReadEvents.
+func (mr *MockDomainItfMockRecorder) ReadEvents(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadEvents", reflect.TypeOf((*MockDomainItf)(nil).ReadEvents), ctx, req)
+}
+
+// RelayOutboxEvents mocks base method.
+func (m *MockDomainItf) RelayOutboxEvents(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayOutboxEvents", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayOutboxEvents indicates an expected call of RelayOutboxEvents.
+func (mr *MockDomainItfMockRecorder) RelayOutboxEvents(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayOutboxEvents", reflect.TypeOf((*MockDomainItf)(nil).RelayOutboxEvents), ctx)
+}
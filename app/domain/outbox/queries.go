@@ -0,0 +1,36 @@
+package domainoutbox
+
+const (
+	// The relay holds the lock until its transaction ends, so only one instance publishes at a time and the events
+	// reach the stream in the order of their id.
+	queryLockOutboxRelay = `
+		SELECT PG_TRY_ADVISORY_XACT_LOCK($1);
+	`
+
+	queryGetUnpublishedOutboxEvents = `
+		SELECT
+			id,
+			event_id,
+			user_id,
+			type,
+			version,
+			payload,
+			created_at
+		FROM
+			outbox_events
+		WHERE
+			published_at IS NULL
+		ORDER BY id
+		LIMIT $1;
+	`
+
+	queryMarkOutboxEventPublished = `
+		UPDATE outbox_events SET
+			published_at = NOW()
+		WHERE id = $1;
+	`
+
+	queryDeletePublishedOutboxEvents = `
+		DELETE FROM outbox_events WHERE published_at < $1;
+	`
+)
@@ -0,0 +1,14 @@
+package domainoutbox
+
+import "time"
+
+// ReadEventsRequest reads up to Count events of the event stream for Consumer of Group. Pending reads the events
+// delivered to Consumer before and not acknowledged yet, otherwise it reads new events and waits up to Block for
+// them.
+type ReadEventsRequest struct {
+	Group    string
+	Consumer string
+	Pending  bool
+	Count    int64
+	Block    time.Duration
+}
@@ -0,0 +1,42 @@
+package domainpaymentrequest
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+type domain struct {
+	db    database.DatabaseItf
+	stmts databaseStmts
+}
+
+type databaseStmts struct {
+	insertPaymentRequest            *sqlx.Stmt
+	getPaymentRequestById           *sqlx.Stmt
+	getPaymentRequestsByRequesterId *sqlx.Stmt
+	getPaymentRequestsByPayerId     *sqlx.Stmt
+	claimPaymentRequestById         *sqlx.Stmt
+	acceptPaymentRequestById        *sqlx.Stmt
+	updatePaymentRequestStatusById  *sqlx.Stmt
+}
+
+func Init(db database.DatabaseItf) DomainItf {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	return &domain{
+		db: db,
+		stmts: databaseStmts{
+			insertPaymentRequest:            db.PreparexContext(ctx, queryInsertPaymentRequest),
+			getPaymentRequestById:           db.PreparexContext(ctx, queryGetPaymentRequestById),
+			getPaymentRequestsByRequesterId: db.PreparexContext(ctx, queryGetPaymentRequestsByRequesterId),
+			getPaymentRequestsByPayerId:     db.PreparexContext(ctx, queryGetPaymentRequestsByPayerId),
+			claimPaymentRequestById:         db.PreparexContext(ctx, queryClaimPaymentRequestById),
+			acceptPaymentRequestById:        db.PreparexContext(ctx, queryAcceptPaymentRequestById),
+			updatePaymentRequestStatusById:  db.PreparexContext(ctx, queryUpdatePaymentRequestStatusById),
+		},
+	}
+}
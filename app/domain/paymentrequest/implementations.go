@@ -0,0 +1,90 @@
+package domainpaymentrequest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+func (d domain) InsertPaymentRequest(ctx context.Context, paymentRequest entity.PaymentRequest) (err error) {
+	return d.db.ExecContextStmt(ctx, d.stmts.insertPaymentRequest,
+		paymentRequest.Id,
+		paymentRequest.RequesterId,
+		paymentRequest.PayerId,
+		paymentRequest.Amount,
+		paymentRequest.Status,
+		paymentRequest.ExpiresAt,
+		paymentRequest.CreatedAt,
+	)
+}
+
+func (d domain) GetPaymentRequestById(ctx context.Context, id string) (resp entity.PaymentRequest, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getPaymentRequestById, &resp, id)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) GetPaymentRequestsByRequesterId(ctx context.Context, requesterId string, limit int) (resp []entity.PaymentRequest, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getPaymentRequestsByRequesterId, &resp, requesterId, limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) GetPaymentRequestsByPayerId(ctx context.Context, payerId string, limit int) (resp []entity.PaymentRequest, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getPaymentRequestsByPayerId, &resp, payerId, limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ClaimPaymentRequest marks a request as accepting before it is paid, so it can no longer be declined or canceled.
+// Claiming a request that is already accepting succeeds, so an interrupted acceptance can be retried.
+func (d domain) ClaimPaymentRequest(ctx context.Context, id string, now time.Time) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.claimPaymentRequestById, int(enum.PAYMENT_REQUEST_STATUS_ACCEPTING), id, int(enum.PAYMENT_REQUEST_STATUS_PENDING), now)
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrPaymentRequestNotPending
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AcceptPaymentRequest marks a claimed request as paid.
+func (d domain) AcceptPaymentRequest(ctx context.Context, id string) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.acceptPaymentRequestById, int(enum.PAYMENT_REQUEST_STATUS_ACCEPTED), id, int(enum.PAYMENT_REQUEST_STATUS_ACCEPTING), int(enum.PAYMENT_REQUEST_STATUS_PENDING))
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrPaymentRequestNotPending
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePaymentRequestStatus moves a request from one status to another, and fails when it is no longer in from.
+func (d domain) UpdatePaymentRequestStatus(ctx context.Context, id string, from enum.PaymentRequestStatus, to enum.PaymentRequestStatus) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.updatePaymentRequestStatusById, int(to), id, int(from))
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrPaymentRequestNotPending
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,650 @@
+package domainpaymentrequest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	createdAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	expiresAt = createdAt.Add(time.Hour * 72)
+
+	paymentRequest = entity.PaymentRequest{
+		Id:          "prid",
+		RequesterId: "id",
+		PayerId:     "payerid",
+		Amount:      10,
+		Status:      int(enum.PAYMENT_REQUEST_STATUS_PENDING),
+		ExpiresAt:   expiresAt,
+		CreatedAt:   createdAt,
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_domain_InsertPaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx            context.Context
+		paymentRequest entity.PaymentRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertPaymentRequest: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:            context.Background(),
+				paymentRequest: paymentRequest,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "prid", "id", "payerid", paymentRequest.Amount, int(enum.PAYMENT_REQUEST_STATUS_PENDING), expiresAt, createdAt).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertPaymentRequest: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:            context.Background(),
+				paymentRequest: paymentRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.InsertPaymentRequest(tt.args.ctx, tt.args.paymentRequest); (err != nil) != tt.wantErr {
+				t.Errorf("domain.InsertPaymentRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetPaymentRequestById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.PaymentRequest
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPaymentRequestById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "prid",
+			},
+			wantResp: paymentRequest,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "prid").SetArg(2, paymentRequest).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error GetContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPaymentRequestById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "prid",
+			},
+			wantResp: entity.PaymentRequest{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "prid").Return(sql.ErrNoRows),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetPaymentRequestById(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetPaymentRequestById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetPaymentRequestById() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetPaymentRequestsByRequesterId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx         context.Context
+		requesterId string
+		limit       int
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.PaymentRequest
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPaymentRequestsByRequesterId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:         context.Background(),
+				requesterId: "id",
+				limit:       10,
+			},
+			wantResp: []entity.PaymentRequest{paymentRequest},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", 10).SetArg(2, []entity.PaymentRequest{paymentRequest}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPaymentRequestsByRequesterId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:         context.Background(),
+				requesterId: "id",
+				limit:       10,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", 10).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetPaymentRequestsByRequesterId(tt.args.ctx, tt.args.requesterId, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetPaymentRequestsByRequesterId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetPaymentRequestsByRequesterId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetPaymentRequestsByPayerId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx     context.Context
+		payerId string
+		limit   int
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.PaymentRequest
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPaymentRequestsByPayerId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:     context.Background(),
+				payerId: "payerid",
+				limit:   10,
+			},
+			wantResp: []entity.PaymentRequest{paymentRequest},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "payerid", 10).SetArg(2, []entity.PaymentRequest{paymentRequest}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getPaymentRequestsByPayerId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:     context.Background(),
+				payerId: "payerid",
+				limit:   10,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "payerid", 10).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetPaymentRequestsByPayerId(tt.args.ctx, tt.args.payerId, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetPaymentRequestsByPayerId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetPaymentRequestsByPayerId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_ClaimPaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	now := expiresAt.Add(-time.Hour)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+		now time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					claimPaymentRequestById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "prid",
+				now: now,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), int(enum.PAYMENT_REQUEST_STATUS_ACCEPTING), "prid", int(enum.PAYMENT_REQUEST_STATUS_PENDING), now).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not pending",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					claimPaymentRequestById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "prid",
+				now: now,
+			},
+			wantErr: ErrPaymentRequestNotPending,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					claimPaymentRequestById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "prid",
+				now: now,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.ClaimPaymentRequest(tt.args.ctx, tt.args.id, tt.args.now); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.ClaimPaymentRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_AcceptPaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					acceptPaymentRequestById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "prid",
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), int(enum.PAYMENT_REQUEST_STATUS_ACCEPTED), "prid", int(enum.PAYMENT_REQUEST_STATUS_ACCEPTING), int(enum.PAYMENT_REQUEST_STATUS_PENDING)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not pending",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					acceptPaymentRequestById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "prid",
+			},
+			wantErr: ErrPaymentRequestNotPending,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					acceptPaymentRequestById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "prid",
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.AcceptPaymentRequest(tt.args.ctx, tt.args.id); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.AcceptPaymentRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_UpdatePaymentRequestStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx  context.Context
+		id   string
+		from enum.PaymentRequestStatus
+		to   enum.PaymentRequestStatus
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					updatePaymentRequestStatusById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				id:   "prid",
+				from: enum.PAYMENT_REQUEST_STATUS_PENDING,
+				to:   enum.PAYMENT_REQUEST_STATUS_DECLINED,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), int(enum.PAYMENT_REQUEST_STATUS_DECLINED), "prid", int(enum.PAYMENT_REQUEST_STATUS_PENDING)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not pending",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					updatePaymentRequestStatusById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				id:   "prid",
+				from: enum.PAYMENT_REQUEST_STATUS_PENDING,
+				to:   enum.PAYMENT_REQUEST_STATUS_DECLINED,
+			},
+			wantErr: ErrPaymentRequestNotPending,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					updatePaymentRequestStatusById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				id:   "prid",
+				from: enum.PAYMENT_REQUEST_STATUS_PENDING,
+				to:   enum.PAYMENT_REQUEST_STATUS_DECLINED,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.UpdatePaymentRequestStatus(tt.args.ctx, tt.args.id, tt.args.from, tt.args.to); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.UpdatePaymentRequestStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
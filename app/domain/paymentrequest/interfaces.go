@@ -0,0 +1,20 @@
+package domainpaymentrequest
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+type DomainItf interface {
+	InsertPaymentRequest(ctx context.Context, paymentRequest entity.PaymentRequest) (err error)
+	GetPaymentRequestById(ctx context.Context, id string) (resp entity.PaymentRequest, err error)
+	GetPaymentRequestsByRequesterId(ctx context.Context, requesterId string, limit int) (resp []entity.PaymentRequest, err error)
+	GetPaymentRequestsByPayerId(ctx context.Context, payerId string, limit int) (resp []entity.PaymentRequest, err error)
+
+	ClaimPaymentRequest(ctx context.Context, id string, now time.Time) (err error)
+	AcceptPaymentRequest(ctx context.Context, id string) (err error)
+	UpdatePaymentRequestStatus(ctx context.Context, id string, from enum.PaymentRequestStatus, to enum.PaymentRequestStatus) (err error)
+}
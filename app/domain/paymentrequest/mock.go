@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/domain/paymentrequest/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/domain/paymentrequest/interfaces.go -destination=app/domain/paymentrequest/mock.go -package=domainpaymentrequest
+//
+
+// Package domainpaymentrequest is a generated GoMock package.
+package domainpaymentrequest
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/kevinsudut/wallet-system/app/entity"
+	enum "github.com/kevinsudut/wallet-system/app/enum"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// AcceptPaymentRequest mocks base method.
+func (m *MockDomainItf) AcceptPaymentRequest(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptPaymentRequest", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptPaymentRequest indicates an expected call of AcceptPaymentRequest.
+func (mr *MockDomainItfMockRecorder) AcceptPaymentRequest(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptPaymentRequest", reflect.TypeOf((*MockDomainItf)(nil).AcceptPaymentRequest), ctx, id)
+}
+
+// ClaimPaymentRequest mocks base method.
+func (m *MockDomainItf) ClaimPaymentRequest(ctx context.Context, id string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPaymentRequest", ctx, id, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimPaymentRequest indicates an expected call of ClaimPaymentRequest.
+func (mr *MockDomainItfMockRecorder) ClaimPaymentRequest(ctx, id, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPaymentRequest", reflect.TypeOf((*MockDomainItf)(nil).ClaimPaymentRequest), ctx, id, now)
+}
+
+// GetPaymentRequestById mocks base method.
+func (m *MockDomainItf) GetPaymentRequestById(ctx context.Context, id string) (entity.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestById", ctx, id)
+	ret0, _ := ret[0].(entity.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestById indicates an expected call of GetPaymentRequestById.
+func (mr *MockDomainItfMockRecorder) GetPaymentRequestById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestById", reflect.TypeOf((*MockDomainItf)(nil).GetPaymentRequestById), ctx, id)
+}
+
+// GetPaymentRequestsByPayerId mocks base method.
+func (m *MockDomainItf) GetPaymentRequestsByPayerId(ctx context.Context, payerId string, limit int) ([]entity.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestsByPayerId", ctx, payerId, limit)
+	ret0, _ := ret[0].([]entity.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestsByPayerId indicates an expected call of GetPaymentRequestsByPayerId.
+func (mr *MockDomainItfMockRecorder) GetPaymentRequestsByPayerId(ctx, payerId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestsByPayerId", reflect.TypeOf((*MockDomainItf)(nil).GetPaymentRequestsByPayerId), ctx, payerId, limit)
+}
+
+// GetPaymentRequestsByRequesterId mocks base method.
+func (m *MockDomainItf) GetPaymentRequestsByRequesterId(ctx context.Context, requesterId string, limit int) ([]entity.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestsByRequesterId", ctx, requesterId, limit)
+	ret0, _ := ret[0].([]entity.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestsByRequesterId indicates an expected call of GetPaymentRequestsByRequesterId.
+func (mr *MockDomainItfMockRecorder) GetPaymentRequestsByRequesterId(ctx, requesterId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestsByRequesterId", reflect.TypeOf((*MockDomainItf)(nil).GetPaymentRequestsByRequesterId), ctx, requesterId, limit)
+}
+
+// InsertPaymentRequest mocks base method.
+func (m *MockDomainItf) InsertPaymentRequest(ctx context.Context, paymentRequest entity.PaymentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPaymentRequest", ctx, paymentRequest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertPaymentRequest indicates an expected call of InsertPaymentRequest.
+func (mr *MockDomainItfMockRecorder) InsertPaymentRequest(ctx, paymentRequest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPaymentRequest", reflect.TypeOf((*MockDomainItf)(nil).InsertPaymentRequest), ctx, paymentRequest)
+}
+
+// UpdatePaymentRequestStatus mocks base method.
+func (m *MockDomainItf) UpdatePaymentRequestStatus(ctx context.Context, id string, from, to enum.PaymentRequestStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentRequestStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePaymentRequestStatus indicates an expected call of UpdatePaymentRequestStatus.
+func (mr *MockDomainItfMockRecorder) UpdatePaymentRequestStatus(ctx, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentRequestStatus", reflect.TypeOf((*MockDomainItf)(nil).UpdatePaymentRequestStatus), ctx, id, from, to)
+}
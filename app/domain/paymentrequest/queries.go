@@ -0,0 +1,91 @@
+package domainpaymentrequest
+
+const (
+	queryInsertPaymentRequest = `
+		INSERT INTO payment_requests (id, requester_id, payer_id, amount, status, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+
+	queryGetPaymentRequestById = `
+		SELECT
+			id,
+			requester_id,
+			payer_id,
+			amount,
+			status,
+			expires_at,
+			created_at
+		FROM
+			payment_requests
+		WHERE
+			id = $1;
+	`
+
+	queryGetPaymentRequestsByRequesterId = `
+		SELECT
+			id,
+			requester_id,
+			payer_id,
+			amount,
+			status,
+			expires_at,
+			created_at
+		FROM
+			payment_requests
+		WHERE
+			requester_id = $1
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT $2;
+	`
+
+	queryGetPaymentRequestsByPayerId = `
+		SELECT
+			id,
+			requester_id,
+			payer_id,
+			amount,
+			status,
+			expires_at,
+			created_at
+		FROM
+			payment_requests
+		WHERE
+			payer_id = $1
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT $2;
+	`
+
+	// queryClaimPaymentRequestById moves a pending request that has not expired, or one whose acceptance was
+	// interrupted, to accepting.
+	queryClaimPaymentRequestById = `
+		UPDATE payment_requests SET
+			status = $1,
+			updated_at = NOW()
+		WHERE
+			id = $2 AND
+			((status = $3 AND expires_at > $4) OR status = $1);
+	`
+
+	// queryAcceptPaymentRequestById also accepts a pending request, whose claim was released by a concurrent
+	// acceptance that failed while this one paid it.
+	queryAcceptPaymentRequestById = `
+		UPDATE payment_requests SET
+			status = $1,
+			updated_at = NOW()
+		WHERE
+			id = $2 AND
+			(status = $3 OR status = $4);
+	`
+
+	queryUpdatePaymentRequestStatusById = `
+		UPDATE payment_requests SET
+			status = $1,
+			updated_at = NOW()
+		WHERE
+			id = $2 AND
+			status = $3;
+	`
+)
@@ -0,0 +1,7 @@
+package domainpaymentrequest
+
+import "fmt"
+
+var (
+	ErrPaymentRequestNotPending = fmt.Errorf("payment request is not pending")
+)
@@ -0,0 +1,44 @@
+package domainscheduledtransfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+type domain struct {
+	db    database.DatabaseItf
+	stmts databaseStmts
+}
+
+type databaseStmts struct {
+	insertScheduledTransfer                       *sqlx.Stmt
+	getScheduledTransferById                      *sqlx.Stmt
+	getScheduledTransfersByUserId                 *sqlx.Stmt
+	cancelScheduledTransferById                   *sqlx.Stmt
+	claimDueScheduledTransfers                    *sqlx.Stmt
+	finishScheduledTransferById                   *sqlx.Stmt
+	insertScheduledTransferRun                    *sqlx.Stmt
+	getScheduledTransferRunsByScheduledTransferId *sqlx.Stmt
+}
+
+func Init(db database.DatabaseItf) DomainItf {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	return &domain{
+		db: db,
+		stmts: databaseStmts{
+			insertScheduledTransfer:                       db.PreparexContext(ctx, queryInsertScheduledTransfer),
+			getScheduledTransferById:                      db.PreparexContext(ctx, queryGetScheduledTransferById),
+			getScheduledTransfersByUserId:                 db.PreparexContext(ctx, queryGetScheduledTransfersByUserId),
+			cancelScheduledTransferById:                   db.PreparexContext(ctx, queryCancelScheduledTransferById),
+			claimDueScheduledTransfers:                    db.PreparexContext(ctx, queryClaimDueScheduledTransfers),
+			finishScheduledTransferById:                   db.PreparexContext(ctx, queryFinishScheduledTransferById),
+			insertScheduledTransferRun:                    db.PreparexContext(ctx, queryInsertScheduledTransferRun),
+			getScheduledTransferRunsByScheduledTransferId: db.PreparexContext(ctx, queryGetScheduledTransferRunsByScheduledTransferId),
+		},
+	}
+}
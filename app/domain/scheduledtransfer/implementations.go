@@ -0,0 +1,123 @@
+package domainscheduledtransfer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+func (d domain) InsertScheduledTransfer(ctx context.Context, scheduledTransfer entity.ScheduledTransfer) (err error) {
+	return d.db.ExecContextStmt(ctx, d.stmts.insertScheduledTransfer,
+		scheduledTransfer.Id,
+		scheduledTransfer.UserId,
+		scheduledTransfer.ToUserId,
+		scheduledTransfer.Amount,
+		scheduledTransfer.Recurrence,
+		scheduledTransfer.DayOfMonth,
+		scheduledTransfer.FailurePolicy,
+		scheduledTransfer.Status,
+		scheduledTransfer.ScheduledAt,
+		scheduledTransfer.NextRunAt,
+	)
+}
+
+func (d domain) GetScheduledTransferById(ctx context.Context, id string) (resp entity.ScheduledTransfer, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getScheduledTransferById, &resp, id)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) GetScheduledTransfersByUserId(ctx context.Context, userId string, limit int) (resp []entity.ScheduledTransfer, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getScheduledTransfersByUserId, &resp, userId, limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// CancelScheduledTransfer stops an active scheduled transfer of userId from running again. A run already in
+// progress still finishes.
+func (d domain) CancelScheduledTransfer(ctx context.Context, id string, userId string) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.cancelScheduledTransferById, int(enum.SCHEDULED_TRANSFER_STATUS_CANCELED), id, userId, int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE))
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrScheduledTransferNotActive
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDueScheduledTransfers leases due transfers with SKIP LOCKED, so every instance can claim concurrently and
+// each transfer is handed to one worker at a time. A lease that expires before its run finishes is claimed again.
+func (d domain) ClaimDueScheduledTransfers(ctx context.Context, req ClaimDueScheduledTransfersRequest) (resp []entity.ScheduledTransfer, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.claimDueScheduledTransfers, &resp, req.LeaseId, req.LeaseExpiresAt, int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE), req.Now, req.Limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// FinishScheduledTransferRun records the run together with the next state of the transfer, and releases the lease.
+// Nothing is written when the lease was lost to another worker, which records the run itself.
+func (d domain) FinishScheduledTransferRun(ctx context.Context, req FinishScheduledTransferRunRequest) (err error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err == nil {
+			err = d.db.Commit(tx)
+		} else {
+			d.db.Rollback(tx)
+		}
+	}()
+
+	scheduledTransfer := req.ScheduledTransfer
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.finishScheduledTransferById,
+		int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE),
+		scheduledTransfer.Status,
+		scheduledTransfer.ScheduledAt,
+		scheduledTransfer.NextRunAt,
+		scheduledTransfer.Attempt,
+		scheduledTransfer.Id,
+		req.LeaseId,
+	)
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrLeaseLost
+	}
+	if err != nil {
+		return err
+	}
+
+	var errorCode interface{}
+	if req.Run.ErrorCode != "" {
+		errorCode = req.Run.ErrorCode
+	}
+
+	err = d.db.ExecContextStmtTx(ctx, tx, d.stmts.insertScheduledTransferRun, req.Run.Id, req.Run.ScheduledTransferId, req.Run.ScheduledAt, req.Run.Attempt, req.Run.Status, errorCode)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d domain) GetScheduledTransferRunsByScheduledTransferId(ctx context.Context, scheduledTransferId string, limit int) (resp []entity.ScheduledTransferRun, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getScheduledTransferRunsByScheduledTransferId, &resp, scheduledTransferId, limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
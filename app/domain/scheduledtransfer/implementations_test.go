@@ -0,0 +1,731 @@
+package domainscheduledtransfer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	scheduledAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	scheduledTransfer = entity.ScheduledTransfer{
+		Id:            "sid",
+		UserId:        "id",
+		ToUserId:      "toid",
+		Amount:        10,
+		Recurrence:    int(enum.RECURRENCE_MONTHLY),
+		DayOfMonth:    18,
+		FailurePolicy: int(enum.FAILURE_POLICY_RETRY),
+		Status:        int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE),
+		ScheduledAt:   scheduledAt,
+		NextRunAt:     scheduledAt,
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_domain_InsertScheduledTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx               context.Context
+		scheduledTransfer entity.ScheduledTransfer
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertScheduledTransfer: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:               context.Background(),
+				scheduledTransfer: scheduledTransfer,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "sid", "id", "toid", scheduledTransfer.Amount, int(enum.RECURRENCE_MONTHLY), 18, int(enum.FAILURE_POLICY_RETRY), int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE), scheduledAt, scheduledAt).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertScheduledTransfer: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:               context.Background(),
+				scheduledTransfer: scheduledTransfer,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.InsertScheduledTransfer(tt.args.ctx, tt.args.scheduledTransfer); (err != nil) != tt.wantErr {
+				t.Errorf("domain.InsertScheduledTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetScheduledTransferById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.ScheduledTransfer
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getScheduledTransferById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "sid",
+			},
+			wantResp: scheduledTransfer,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "sid").SetArg(2, scheduledTransfer).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error GetContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getScheduledTransferById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "sid",
+			},
+			wantResp: entity.ScheduledTransfer{},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().GetContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "sid").Return(sql.ErrNoRows),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetScheduledTransferById(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetScheduledTransferById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetScheduledTransferById() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_GetScheduledTransfersByUserId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		userId string
+		limit  int
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.ScheduledTransfer
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getScheduledTransfersByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				limit:  10,
+			},
+			wantResp: []entity.ScheduledTransfer{scheduledTransfer},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", 10).SetArg(2, []entity.ScheduledTransfer{scheduledTransfer}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getScheduledTransfersByUserId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				userId: "id",
+				limit:  10,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", 10).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetScheduledTransfersByUserId(tt.args.ctx, tt.args.userId, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetScheduledTransfersByUserId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetScheduledTransfersByUserId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_CancelScheduledTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		id     string
+		userId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					cancelScheduledTransferById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				id:     "sid",
+				userId: "id",
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), int(enum.SCHEDULED_TRANSFER_STATUS_CANCELED), "sid", "id", int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not active",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					cancelScheduledTransferById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				id:     "sid",
+				userId: "id",
+			},
+			wantErr: ErrScheduledTransferNotActive,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					cancelScheduledTransferById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				id:     "sid",
+				userId: "id",
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.CancelScheduledTransfer(tt.args.ctx, tt.args.id, tt.args.userId); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.CancelScheduledTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_ClaimDueScheduledTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	req := ClaimDueScheduledTransfersRequest{
+		LeaseId:        "lid",
+		LeaseExpiresAt: now.Add(time.Minute),
+		Now:            now,
+		Limit:          10,
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req ClaimDueScheduledTransfersRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.ScheduledTransfer
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					claimDueScheduledTransfers: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: []entity.ScheduledTransfer{scheduledTransfer},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "lid", now.Add(time.Minute), int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE), now, 10).SetArg(2, []entity.ScheduledTransfer{scheduledTransfer}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					claimDueScheduledTransfers: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.ClaimDueScheduledTransfers(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.ClaimDueScheduledTransfers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.ClaimDueScheduledTransfers() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_FinishScheduledTransferRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	nextRunAt := scheduledAt.Add(time.Hour)
+	failedScheduledTransfer := scheduledTransfer
+	failedScheduledTransfer.NextRunAt = nextRunAt
+	failedScheduledTransfer.Attempt = 1
+
+	req := FinishScheduledTransferRunRequest{
+		LeaseId:           "lid",
+		ScheduledTransfer: failedScheduledTransfer,
+		Run: entity.ScheduledTransferRun{
+			Id:                  "rid",
+			ScheduledTransferId: "sid",
+			ScheduledAt:         scheduledAt,
+			Attempt:             1,
+			Status:              int(enum.SCHEDULED_TRANSFER_RUN_STATUS_FAILED),
+			ErrorCode:           "insufficient_balance",
+		},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req FinishScheduledTransferRunRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishScheduledTransferById: &sqlx.Stmt{},
+					insertScheduledTransferRun:  &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE), int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE), scheduledAt, nextRunAt, 1, "sid", "lid").Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "rid", "sid", scheduledAt, 1, int(enum.SCHEDULED_TRANSFER_RUN_STATUS_FAILED), "insufficient_balance").Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success without error code",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishScheduledTransferById: &sqlx.Stmt{},
+					insertScheduledTransferRun:  &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: FinishScheduledTransferRunRequest{
+					LeaseId:           "lid",
+					ScheduledTransfer: scheduledTransfer,
+					Run: entity.ScheduledTransferRun{
+						Id:                  "rid",
+						ScheduledTransferId: "sid",
+						ScheduledAt:         scheduledAt,
+						Attempt:             1,
+						Status:              int(enum.SCHEDULED_TRANSFER_RUN_STATUS_SUCCEEDED),
+					},
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), "rid", "sid", scheduledAt, 1, int(enum.SCHEDULED_TRANSFER_RUN_STATUS_SUCCEEDED), nil).Return(nil),
+					mockDatabase.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error lease lost",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishScheduledTransferById: &sqlx.Stmt{},
+					insertScheduledTransferRun:  &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: ErrLeaseLost,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error insert run",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishScheduledTransferById: &sqlx.Stmt{},
+					insertScheduledTransferRun:  &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(&sql.Tx{}, nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockDatabase.EXPECT().ExecContextStmtTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDatabase.EXPECT().Rollback(gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error Begin",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishScheduledTransferById: &sqlx.Stmt{},
+					insertScheduledTransferRun:  &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().Begin().Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.FinishScheduledTransferRun(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.FinishScheduledTransferRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetScheduledTransferRunsByScheduledTransferId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	runs := []entity.ScheduledTransferRun{
+		{
+			Id:                  "rid",
+			ScheduledTransferId: "sid",
+			ScheduledAt:         scheduledAt,
+			Attempt:             1,
+			Status:              int(enum.SCHEDULED_TRANSFER_RUN_STATUS_SUCCEEDED),
+		},
+	}
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx                 context.Context
+		scheduledTransferId string
+		limit               int
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.ScheduledTransferRun
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getScheduledTransferRunsByScheduledTransferId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:                 context.Background(),
+				scheduledTransferId: "sid",
+				limit:               10,
+			},
+			wantResp: runs,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "sid", 10).SetArg(2, runs).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getScheduledTransferRunsByScheduledTransferId: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:                 context.Background(),
+				scheduledTransferId: "sid",
+				limit:               10,
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "sid", 10).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetScheduledTransferRunsByScheduledTransferId(tt.args.ctx, tt.args.scheduledTransferId, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetScheduledTransferRunsByScheduledTransferId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetScheduledTransferRunsByScheduledTransferId() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package domainscheduledtransfer
+
+import (
+	"context"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+type DomainItf interface {
+	InsertScheduledTransfer(ctx context.Context, scheduledTransfer entity.ScheduledTransfer) (err error)
+	GetScheduledTransferById(ctx context.Context, id string) (resp entity.ScheduledTransfer, err error)
+	GetScheduledTransfersByUserId(ctx context.Context, userId string, limit int) (resp []entity.ScheduledTransfer, err error)
+	CancelScheduledTransfer(ctx context.Context, id string, userId string) (err error)
+
+	ClaimDueScheduledTransfers(ctx context.Context, req ClaimDueScheduledTransfersRequest) (resp []entity.ScheduledTransfer, err error)
+	FinishScheduledTransferRun(ctx context.Context, req FinishScheduledTransferRunRequest) (err error)
+	GetScheduledTransferRunsByScheduledTransferId(ctx context.Context, scheduledTransferId string, limit int) (resp []entity.ScheduledTransferRun, err error)
+}
@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/domain/scheduledtransfer/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/domain/scheduledtransfer/interfaces.go -destination=app/domain/scheduledtransfer/mock.go -package=domainscheduledtransfer
+//
+
+// Package domainscheduledtransfer is a generated GoMock package.
+package domainscheduledtransfer
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/kevinsudut/wallet-system/app/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// CancelScheduledTransfer mocks base method.
+func (m *MockDomainItf) CancelScheduledTransfer(ctx context.Context, id, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledTransfer", ctx, id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelScheduledTransfer indicates an expected call of CancelScheduledTransfer.
+func (mr *MockDomainItfMockRecorder) CancelScheduledTransfer(ctx, id, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledTransfer", reflect.TypeOf((*MockDomainItf)(nil).CancelScheduledTransfer), ctx, id, userId)
+}
+
+// ClaimDueScheduledTransfers mocks base method.
+func (m *MockDomainItf) ClaimDueScheduledTransfers(ctx context.Context, req ClaimDueScheduledTransfersRequest) ([]entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueScheduledTransfers", ctx, req)
+	ret0, _ := ret[0].([]entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueScheduledTransfers indicates an expected call of ClaimDueScheduledTransfers.
+func (mr *MockDomainItfMockRecorder) ClaimDueScheduledTransfers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueScheduledTransfers", reflect.TypeOf((*MockDomainItf)(nil).ClaimDueScheduledTransfers), ctx, req)
+}
+
+// FinishScheduledTransferRun mocks base method.
+func (m *MockDomainItf) FinishScheduledTransferRun(ctx context.Context, req FinishScheduledTransferRunRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishScheduledTransferRun", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishScheduledTransferRun indicates an expected call of FinishScheduledTransferRun.
+func (mr *MockDomainItfMockRecorder) FinishScheduledTransferRun(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishScheduledTransferRun", reflect.TypeOf((*MockDomainItf)(nil).FinishScheduledTransferRun), ctx, req)
+}
+
+// GetScheduledTransferById mocks base method.
+func (m *MockDomainItf) GetScheduledTransferById(ctx context.Context, id string) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransferById", ctx, id)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransferById indicates an expected call of GetScheduledTransferById.
+func (mr *MockDomainItfMockRecorder) GetScheduledTransferById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransferById", reflect.TypeOf((*MockDomainItf)(nil).GetScheduledTransferById), ctx, id)
+}
+
+// GetScheduledTransferRunsByScheduledTransferId mocks base method.
+func (m *MockDomainItf) GetScheduledTransferRunsByScheduledTransferId(ctx context.Context, scheduledTransferId string, limit int) ([]entity.ScheduledTransferRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransferRunsByScheduledTransferId", ctx, scheduledTransferId, limit)
+	ret0, _ := ret[0].([]entity.ScheduledTransferRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransferRunsByScheduledTransferId indicates an expected call of GetScheduledTransferRunsByScheduledTransferId.
+func (mr *MockDomainItfMockRecorder) GetScheduledTransferRunsByScheduledTransferId(ctx, scheduledTransferId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransferRunsByScheduledTransferId", reflect.TypeOf((*MockDomainItf)(nil).GetScheduledTransferRunsByScheduledTransferId), ctx, scheduledTransferId, limit)
+}
+
+// GetScheduledTransfersByUserId mocks base method.
+func (m *MockDomainItf) GetScheduledTransfersByUserId(ctx context.Context, userId string, limit int) ([]entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransfersByUserId", ctx, userId, limit)
+	ret0, _ := ret[0].([]entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransfersByUserId indicates an expected call of GetScheduledTransfersByUserId.
+func (mr *MockDomainItfMockRecorder) GetScheduledTransfersByUserId(ctx, userId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransfersByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetScheduledTransfersByUserId), ctx, userId, limit)
+}
+
+// InsertScheduledTransfer mocks base method.
+func (m *MockDomainItf) InsertScheduledTransfer(ctx context.Context, scheduledTransfer entity.ScheduledTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertScheduledTransfer", ctx, scheduledTransfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertScheduledTransfer indicates an expected call of InsertScheduledTransfer.
+func (mr *MockDomainItfMockRecorder) InsertScheduledTransfer(ctx, scheduledTransfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertScheduledTransfer", reflect.TypeOf((*MockDomainItf)(nil).InsertScheduledTransfer), ctx, scheduledTransfer)
+}
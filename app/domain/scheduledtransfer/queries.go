@@ -0,0 +1,128 @@
+package domainscheduledtransfer
+
+const (
+	queryInsertScheduledTransfer = `
+		INSERT INTO scheduled_transfers (id, user_id, to_user_id, amount, recurrence, day_of_month, failure_policy, status, scheduled_at, next_run_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+	`
+
+	queryGetScheduledTransferById = `
+		SELECT
+			id,
+			user_id,
+			to_user_id,
+			amount,
+			recurrence,
+			day_of_month,
+			failure_policy,
+			status,
+			scheduled_at,
+			next_run_at,
+			attempt,
+			created_at
+		FROM
+			scheduled_transfers
+		WHERE
+			id = $1;
+	`
+
+	queryGetScheduledTransfersByUserId = `
+		SELECT
+			id,
+			user_id,
+			to_user_id,
+			amount,
+			recurrence,
+			day_of_month,
+			failure_policy,
+			status,
+			scheduled_at,
+			next_run_at,
+			attempt,
+			created_at
+		FROM
+			scheduled_transfers
+		WHERE
+			user_id = $1
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT $2;
+	`
+
+	queryCancelScheduledTransferById = `
+		UPDATE scheduled_transfers SET
+			status = $1,
+			updated_at = NOW()
+		WHERE
+			id = $2 AND
+			user_id = $3 AND
+			status = $4;
+	`
+
+	// queryClaimDueScheduledTransfers leases the due transfers that no other worker holds a live lease on.
+	queryClaimDueScheduledTransfers = `
+		UPDATE scheduled_transfers SET
+			lease_id = $1,
+			lease_expires_at = $2,
+			updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM scheduled_transfers
+			WHERE status = $3 AND next_run_at <= $4 AND (lease_expires_at IS NULL OR lease_expires_at <= $4)
+			ORDER BY next_run_at
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING
+			id,
+			user_id,
+			to_user_id,
+			amount,
+			recurrence,
+			day_of_month,
+			failure_policy,
+			status,
+			scheduled_at,
+			next_run_at,
+			attempt,
+			created_at;
+	`
+
+	// queryFinishScheduledTransferById only applies while the lease is still held, and keeps a transfer canceled
+	// during its run canceled.
+	queryFinishScheduledTransferById = `
+		UPDATE scheduled_transfers SET
+			status = CASE WHEN status = $1 THEN $2 ELSE status END,
+			scheduled_at = $3,
+			next_run_at = $4,
+			attempt = $5,
+			lease_id = NULL,
+			lease_expires_at = NULL,
+			updated_at = NOW()
+		WHERE
+			id = $6 AND
+			lease_id = $7;
+	`
+
+	queryInsertScheduledTransferRun = `
+		INSERT INTO scheduled_transfer_runs (id, scheduled_transfer_id, scheduled_at, attempt, status, error_code) VALUES ($1, $2, $3, $4, $5, $6);
+	`
+
+	queryGetScheduledTransferRunsByScheduledTransferId = `
+		SELECT
+			id,
+			scheduled_transfer_id,
+			scheduled_at,
+			attempt,
+			status,
+			COALESCE(error_code, '') AS error_code,
+			created_at
+		FROM
+			scheduled_transfer_runs
+		WHERE
+			scheduled_transfer_id = $1
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT $2;
+	`
+)
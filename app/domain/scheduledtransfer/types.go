@@ -0,0 +1,29 @@
+package domainscheduledtransfer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+var (
+	ErrScheduledTransferNotActive = fmt.Errorf("scheduled transfer is not active")
+	ErrLeaseLost                  = fmt.Errorf("scheduled transfer lease was lost")
+)
+
+// ClaimDueScheduledTransfersRequest leases up to Limit transfers due at Now to LeaseId until LeaseExpiresAt.
+type ClaimDueScheduledTransfersRequest struct {
+	LeaseId        string
+	LeaseExpiresAt time.Time
+	Now            time.Time
+	Limit          int
+}
+
+// FinishScheduledTransferRunRequest records Run and moves ScheduledTransfer to its next state, as long as
+// LeaseId still holds the lease.
+type FinishScheduledTransferRunRequest struct {
+	LeaseId           string
+	ScheduledTransfer entity.ScheduledTransfer
+	Run               entity.ScheduledTransferRun
+}
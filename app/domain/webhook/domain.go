@@ -0,0 +1,81 @@
+package domainwebhook
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/pkg/helper/publicip"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+const (
+	// deliveryTimeout bounds an attempt, a slower endpoint fails it.
+	deliveryTimeout = 10 * time.Second
+)
+
+type domain struct {
+	db     database.DatabaseItf
+	client *http.Client
+	stmts  databaseStmts
+}
+
+type databaseStmts struct {
+	insertWebhookEndpoint            *sqlx.Stmt
+	getWebhookEndpointById           *sqlx.Stmt
+	getWebhookEndpointsByUserId      *sqlx.Stmt
+	deleteWebhookEndpointById        *sqlx.Stmt
+	getSubscribedWebhookEndpoints    *sqlx.Stmt
+	insertWebhookDelivery            *sqlx.Stmt
+	claimDueWebhookDeliveries        *sqlx.Stmt
+	finishWebhookDeliveryById        *sqlx.Stmt
+	getWebhookDeliveryById           *sqlx.Stmt
+	getWebhookDeliveriesByEndpointId *sqlx.Stmt
+	redeliverWebhookDeliveryById     *sqlx.Stmt
+}
+
+func Init(db database.DatabaseItf) DomainItf {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	return &domain{
+		db:     db,
+		client: newClient(publicip.Control),
+		stmts: databaseStmts{
+			insertWebhookEndpoint:            db.PreparexContext(ctx, queryInsertWebhookEndpoint),
+			getWebhookEndpointById:           db.PreparexContext(ctx, queryGetWebhookEndpointById),
+			getWebhookEndpointsByUserId:      db.PreparexContext(ctx, queryGetWebhookEndpointsByUserId),
+			deleteWebhookEndpointById:        db.PreparexContext(ctx, queryDeleteWebhookEndpointById),
+			getSubscribedWebhookEndpoints:    db.PreparexContext(ctx, queryGetSubscribedWebhookEndpoints),
+			insertWebhookDelivery:            db.PreparexContext(ctx, queryInsertWebhookDelivery),
+			claimDueWebhookDeliveries:        db.PreparexContext(ctx, queryClaimDueWebhookDeliveries),
+			finishWebhookDeliveryById:        db.PreparexContext(ctx, queryFinishWebhookDeliveryById),
+			getWebhookDeliveryById:           db.PreparexContext(ctx, queryGetWebhookDeliveryById),
+			getWebhookDeliveriesByEndpointId: db.PreparexContext(ctx, queryGetWebhookDeliveriesByEndpointId),
+			redeliverWebhookDeliveryById:     db.PreparexContext(ctx, queryRedeliverWebhookDeliveryById),
+		},
+	}
+}
+
+// newClient does not follow redirects, an endpoint that redirects has not accepted the delivery. control checks
+// every address the client connects to once its host name is resolved, and the client does not go through a proxy,
+// which would be checked instead of the endpoint.
+func newClient(control func(network string, address string, c syscall.RawConn) error) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout: deliveryTimeout,
+		Control: control,
+	}).DialContext
+
+	return &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,213 @@
+package domainwebhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/signature"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+)
+
+const (
+	// maxResponseBodySize is read off a response so its connection can be reused, the rest is dropped with it.
+	maxResponseBodySize = 64 << 10
+)
+
+func (d domain) InsertWebhookEndpoint(ctx context.Context, endpoint entity.WebhookEndpoint) (err error) {
+	return d.db.ExecContextStmt(ctx, d.stmts.insertWebhookEndpoint,
+		endpoint.Id,
+		endpoint.UserId,
+		endpoint.Url,
+		endpoint.Secret,
+		endpoint.EventTypes,
+		endpoint.Status,
+	)
+}
+
+func (d domain) GetWebhookEndpointById(ctx context.Context, id string) (resp entity.WebhookEndpoint, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getWebhookEndpointById, &resp, id)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GetWebhookEndpointsByUserId returns the active endpoints of userId, newest first.
+func (d domain) GetWebhookEndpointsByUserId(ctx context.Context, userId string, limit int) (resp []entity.WebhookEndpoint, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getWebhookEndpointsByUserId, &resp, userId, int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE), limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// DeleteWebhookEndpoint stops sending events to an active endpoint of userId, including the deliveries still
+// pending. An attempt already in progress still finishes.
+func (d domain) DeleteWebhookEndpoint(ctx context.Context, id string, userId string) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.deleteWebhookEndpointById, int(enum.WEBHOOK_ENDPOINT_STATUS_DELETED), id, userId, int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE))
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrWebhookEndpointNotActive
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetSubscribedWebhookEndpoints returns the active endpoints of userId subscribed to eventType.
+func (d domain) GetSubscribedWebhookEndpoints(ctx context.Context, userId string, eventType string) (resp []entity.WebhookEndpoint, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getSubscribedWebhookEndpoints, &resp, userId, int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE), eventType)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// InsertWebhookDelivery creates a delivery unless its endpoint already has one of the event.
+func (d domain) InsertWebhookDelivery(ctx context.Context, delivery entity.WebhookDelivery) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.insertWebhookDelivery,
+		delivery.Id,
+		delivery.EndpointId,
+		delivery.EventId,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		delivery.NextAttemptAt,
+	)
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDueWebhookDeliveries leases due deliveries with SKIP LOCKED, so every instance can claim concurrently and
+// each delivery is attempted by one worker at a time. A lease that expires before its attempt finishes is claimed
+// again.
+func (d domain) ClaimDueWebhookDeliveries(ctx context.Context, req ClaimDueWebhookDeliveriesRequest) (resp []entity.WebhookDelivery, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.claimDueWebhookDeliveries, &resp,
+		req.LeaseId,
+		req.LeaseExpiresAt,
+		int(enum.WEBHOOK_DELIVERY_STATUS_PENDING),
+		req.Now,
+		int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE),
+		req.Limit,
+	)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// SendWebhookDelivery posts the payload of delivery to endpoint, signed with its secret, and returns the status
+// code of the response. Any status other than 2xx fails the attempt, as does no response at all, for which the
+// status code is 0.
+func (d domain) SendWebhookDelivery(ctx context.Context, endpoint entity.WebhookEndpoint, delivery entity.WebhookDelivery) (resp int, err error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerWebhookId, delivery.EventId)
+	req.Header.Set(headerWebhookDeliveryId, delivery.Id)
+	req.Header.Set(headerWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(headerWebhookSignature, signature.Sign(endpoint.Secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	defer res.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxResponseBodySize))
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, fmt.Errorf("webhook endpoint responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// FinishWebhookDeliveryAttempt records the state of the delivery after an attempt and releases the lease. Nothing
+// is written when the lease was lost to another worker, which records its own attempt.
+func (d domain) FinishWebhookDeliveryAttempt(ctx context.Context, req FinishWebhookDeliveryAttemptRequest) (err error) {
+	delivery := req.Delivery
+
+	var lastStatusCode, lastError interface{}
+	if delivery.LastStatusCode != 0 {
+		lastStatusCode = delivery.LastStatusCode
+	}
+	if delivery.LastError != "" {
+		lastError = delivery.LastError
+	}
+
+	err = d.db.ExecContextStmt(ctx, d.stmts.finishWebhookDeliveryById,
+		delivery.Status,
+		delivery.Attempt,
+		delivery.NextAttemptAt,
+		lastStatusCode,
+		lastError,
+		delivery.DeliveredAt,
+		delivery.Id,
+		req.LeaseId,
+	)
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrLeaseLost
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d domain) GetWebhookDeliveryById(ctx context.Context, id string) (resp entity.WebhookDelivery, err error) {
+	err = d.db.GetContextStmt(ctx, d.stmts.getWebhookDeliveryById, &resp, id)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (d domain) GetWebhookDeliveriesByEndpointId(ctx context.Context, endpointId string, limit int) (resp []entity.WebhookDelivery, err error) {
+	err = d.db.SelectContextStmt(ctx, d.stmts.getWebhookDeliveriesByEndpointId, &resp, endpointId, limit)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// RedeliverWebhookDelivery makes a delivered or dead delivery of endpointId due now, with all its attempts again.
+func (d domain) RedeliverWebhookDelivery(ctx context.Context, id string, endpointId string) (err error) {
+	err = d.db.ExecContextStmt(ctx, d.stmts.redeliverWebhookDeliveryById, int(enum.WEBHOOK_DELIVERY_STATUS_PENDING), id, endpointId)
+	if errors.Is(err, database.ErrNoRowsAffected) {
+		return ErrWebhookDeliveryPending
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
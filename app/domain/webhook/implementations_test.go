@@ -0,0 +1,755 @@
+package domainwebhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/publicip"
+	"github.com/kevinsudut/wallet-system/pkg/helper/signature"
+	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	nextAttemptAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	endpoint = entity.WebhookEndpoint{
+		Id:         "wid",
+		UserId:     "id",
+		Url:        "https://example.com/webhooks",
+		Secret:     "whsec_secret",
+		EventTypes: "BalanceCredited,TransferCompleted",
+		Status:     int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE),
+	}
+
+	delivery = entity.WebhookDelivery{
+		Id:            "did",
+		EndpointId:    "wid",
+		EventId:       "eid",
+		EventType:     "BalanceCredited",
+		Payload:       `{"id":"eid","type":"BalanceCredited","version":1,"occurred_at":"2026-10-18T09:00:00Z","data":{"user_id":"id"}}`,
+		Status:        int(enum.WEBHOOK_DELIVERY_STATUS_PENDING),
+		NextAttemptAt: nextAttemptAt,
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_domain_InsertWebhookEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx      context.Context
+		endpoint entity.WebhookEndpoint
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertWebhookEndpoint: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				endpoint: endpoint,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "wid", "id", "https://example.com/webhooks", "whsec_secret", "BalanceCredited,TransferCompleted", int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertWebhookEndpoint: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				endpoint: endpoint,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.InsertWebhookEndpoint(tt.args.ctx, tt.args.endpoint); (err != nil) != tt.wantErr {
+				t.Errorf("domain.InsertWebhookEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_DeleteWebhookEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx    context.Context
+		id     string
+		userId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deleteWebhookEndpointById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				id:     "wid",
+				userId: "id",
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), int(enum.WEBHOOK_ENDPOINT_STATUS_DELETED), "wid", "id", int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error not active",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deleteWebhookEndpointById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				id:     "wid",
+				userId: "id",
+			},
+			wantErr: ErrWebhookEndpointNotActive,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					deleteWebhookEndpointById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:    context.Background(),
+				id:     "wid",
+				userId: "id",
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.DeleteWebhookEndpoint(tt.args.ctx, tt.args.id, tt.args.userId); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.DeleteWebhookEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_GetSubscribedWebhookEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx       context.Context
+		userId    string
+		eventType string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp []entity.WebhookEndpoint
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getSubscribedWebhookEndpoints: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:       context.Background(),
+				userId:    "id",
+				eventType: "BalanceCredited",
+			},
+			wantResp: []entity.WebhookEndpoint{endpoint},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), "id", int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE), "BalanceCredited").SetArg(2, []entity.WebhookEndpoint{endpoint}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error SelectContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					getSubscribedWebhookEndpoints: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:       context.Background(),
+				userId:    "id",
+				eventType: "BalanceCredited",
+			},
+			wantResp: nil,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().SelectContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			gotResp, err := d.GetSubscribedWebhookEndpoints(tt.args.ctx, tt.args.userId, tt.args.eventType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.GetSubscribedWebhookEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("domain.GetSubscribedWebhookEndpoints() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_InsertWebhookDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx      context.Context
+		delivery entity.WebhookDelivery
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertWebhookDelivery: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				delivery: delivery,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), "did", "wid", "eid", "BalanceCredited", delivery.Payload, int(enum.WEBHOOK_DELIVERY_STATUS_PENDING), nextAttemptAt).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success already delivered to the endpoint",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertWebhookDelivery: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				delivery: delivery,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					insertWebhookDelivery: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				delivery: delivery,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.InsertWebhookDelivery(tt.args.ctx, tt.args.delivery); (err != nil) != tt.wantErr {
+				t.Errorf("domain.InsertWebhookDelivery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_SendWebhookDelivery(t *testing.T) {
+	// receiver checks a delivery the way an endpoint is told to, and answers with status.
+	receiver := func(t *testing.T, status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("receiver: read body: %v", err)
+			}
+
+			timestamp, err := strconv.ParseInt(r.Header.Get(headerWebhookTimestamp), 10, 64)
+			if err != nil {
+				t.Errorf("receiver: invalid %s: %v", headerWebhookTimestamp, err)
+			}
+
+			if !signature.Verify(endpoint.Secret, timestamp, body, r.Header.Get(headerWebhookSignature)) {
+				t.Errorf("receiver: invalid %s", headerWebhookSignature)
+			}
+			if string(body) != delivery.Payload {
+				t.Errorf("receiver: body = %s, want %s", body, delivery.Payload)
+			}
+			if r.Header.Get(headerWebhookId) != "eid" || r.Header.Get(headerWebhookDeliveryId) != "did" {
+				t.Errorf("receiver: %s = %s, %s = %s", headerWebhookId, r.Header.Get(headerWebhookId), headerWebhookDeliveryId, r.Header.Get(headerWebhookDeliveryId))
+			}
+
+			w.WriteHeader(status)
+		}
+	}
+
+	type args struct {
+		ctx     context.Context
+		handler func(t *testing.T) http.HandlerFunc
+	}
+	tests := []struct {
+		name     string
+		args     args
+		control  func(network string, address string, c syscall.RawConn) error
+		wantResp int
+		wantErr  bool
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				handler: func(t *testing.T) http.HandlerFunc {
+					return receiver(t, http.StatusOK)
+				},
+			},
+			wantResp: http.StatusOK,
+			wantErr:  false,
+		},
+		{
+			name: "error address is not public",
+			args: args{
+				ctx: context.Background(),
+				handler: func(t *testing.T) http.HandlerFunc {
+					return func(w http.ResponseWriter, r *http.Request) {
+						t.Errorf("receiver: delivery reached a loopback address")
+					}
+				},
+			},
+			control:  publicip.Control,
+			wantResp: 0,
+			wantErr:  true,
+		},
+		{
+			name: "error status is not 2xx",
+			args: args{
+				ctx: context.Background(),
+				handler: func(t *testing.T) http.HandlerFunc {
+					return receiver(t, http.StatusInternalServerError)
+				},
+			},
+			wantResp: http.StatusInternalServerError,
+			wantErr:  true,
+		},
+		{
+			name: "error redirect is not followed",
+			args: args{
+				ctx: context.Background(),
+				handler: func(t *testing.T) http.HandlerFunc {
+					return func(w http.ResponseWriter, r *http.Request) {
+						http.Redirect(w, r, "/elsewhere", http.StatusFound)
+					}
+				},
+			},
+			wantResp: http.StatusFound,
+			wantErr:  true,
+		},
+		{
+			name: "error no response",
+			args: args{
+				ctx: context.Background(),
+				handler: func(t *testing.T) http.HandlerFunc {
+					return func(w http.ResponseWriter, r *http.Request) {
+						panic(http.ErrAbortHandler)
+					}
+				},
+			},
+			wantResp: 0,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.args.handler(t))
+			defer server.Close()
+
+			// The test server listens on a loopback address, which only the case checking addresses refuses.
+			d := domain{
+				client: newClient(tt.control),
+			}
+			webhookEndpoint := endpoint
+			webhookEndpoint.Url = server.URL
+
+			gotResp, err := d.SendWebhookDelivery(tt.args.ctx, webhookEndpoint, delivery)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("domain.SendWebhookDelivery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp != tt.wantResp {
+				t.Errorf("domain.SendWebhookDelivery() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_domain_FinishWebhookDeliveryAttempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	deliveredAt := nextAttemptAt.Add(time.Second)
+
+	delivered := delivery
+	delivered.Status = int(enum.WEBHOOK_DELIVERY_STATUS_DELIVERED)
+	delivered.Attempt = 1
+	delivered.LastStatusCode = http.StatusOK
+	delivered.DeliveredAt = &deliveredAt
+
+	failed := delivery
+	failed.Attempt = 1
+	failed.NextAttemptAt = nextAttemptAt.Add(time.Minute)
+	failed.LastError = "connection refused"
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx context.Context
+		req FinishWebhookDeliveryAttemptRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success delivered",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishWebhookDeliveryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: FinishWebhookDeliveryAttemptRequest{
+					LeaseId:  "lid",
+					Delivery: delivered,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), int(enum.WEBHOOK_DELIVERY_STATUS_DELIVERED), 1, nextAttemptAt, http.StatusOK, nil, &deliveredAt, "did", "lid").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success failed without a response",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishWebhookDeliveryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: FinishWebhookDeliveryAttemptRequest{
+					LeaseId:  "lid",
+					Delivery: failed,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), int(enum.WEBHOOK_DELIVERY_STATUS_PENDING), 1, nextAttemptAt.Add(time.Minute), nil, "connection refused", (*time.Time)(nil), "did", "lid").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error lease lost",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishWebhookDeliveryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: FinishWebhookDeliveryAttemptRequest{
+					LeaseId:  "lid",
+					Delivery: delivered,
+				},
+			},
+			wantErr: ErrLeaseLost,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					finishWebhookDeliveryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				req: FinishWebhookDeliveryAttemptRequest{
+					LeaseId:  "lid",
+					Delivery: delivered,
+				},
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.FinishWebhookDeliveryAttempt(tt.args.ctx, tt.args.req); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.FinishWebhookDeliveryAttempt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_domain_RedeliverWebhookDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDatabase := database.NewMockDatabaseItf(ctrl)
+
+	type fields struct {
+		db    database.DatabaseItf
+		stmts databaseStmts
+	}
+	type args struct {
+		ctx        context.Context
+		id         string
+		endpointId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					redeliverWebhookDeliveryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         "did",
+				endpointId: "wid",
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), int(enum.WEBHOOK_DELIVERY_STATUS_PENDING), "did", "wid").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error pending",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					redeliverWebhookDeliveryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         "did",
+				endpointId: "wid",
+			},
+			wantErr: ErrWebhookDeliveryPending,
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(database.ErrNoRowsAffected),
+				)
+			},
+		},
+		{
+			name: "error ExecContextStmt",
+			fields: fields{
+				db: mockDatabase,
+				stmts: databaseStmts{
+					redeliverWebhookDeliveryById: &sqlx.Stmt{},
+				},
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         "did",
+				endpointId: "wid",
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDatabase.EXPECT().ExecContextStmt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := domain{
+				db:    tt.fields.db,
+				stmts: tt.fields.stmts,
+			}
+			tt.mock()
+			if err := d.RedeliverWebhookDelivery(tt.args.ctx, tt.args.id, tt.args.endpointId); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("domain.RedeliverWebhookDelivery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
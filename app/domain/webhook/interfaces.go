@@ -0,0 +1,23 @@
+package domainwebhook
+
+import (
+	"context"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+type DomainItf interface {
+	InsertWebhookEndpoint(ctx context.Context, endpoint entity.WebhookEndpoint) (err error)
+	GetWebhookEndpointById(ctx context.Context, id string) (resp entity.WebhookEndpoint, err error)
+	GetWebhookEndpointsByUserId(ctx context.Context, userId string, limit int) (resp []entity.WebhookEndpoint, err error)
+	DeleteWebhookEndpoint(ctx context.Context, id string, userId string) (err error)
+	GetSubscribedWebhookEndpoints(ctx context.Context, userId string, eventType string) (resp []entity.WebhookEndpoint, err error)
+
+	InsertWebhookDelivery(ctx context.Context, delivery entity.WebhookDelivery) (err error)
+	ClaimDueWebhookDeliveries(ctx context.Context, req ClaimDueWebhookDeliveriesRequest) (resp []entity.WebhookDelivery, err error)
+	SendWebhookDelivery(ctx context.Context, endpoint entity.WebhookEndpoint, delivery entity.WebhookDelivery) (resp int, err error)
+	FinishWebhookDeliveryAttempt(ctx context.Context, req FinishWebhookDeliveryAttemptRequest) (err error)
+	GetWebhookDeliveryById(ctx context.Context, id string) (resp entity.WebhookDelivery, err error)
+	GetWebhookDeliveriesByEndpointId(ctx context.Context, endpointId string, limit int) (resp []entity.WebhookDelivery, err error)
+	RedeliverWebhookDelivery(ctx context.Context, id string, endpointId string) (err error)
+}
@@ -0,0 +1,216 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interfaces.go -destination=mock.go -package=domainwebhook
+//
+
+// Package domainwebhook is a generated GoMock package.
+package domainwebhook
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/kevinsudut/wallet-system/app/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockDomainItf) ClaimDueWebhookDeliveries(ctx context.Context, req ClaimDueWebhookDeliveriesRequest) ([]entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", ctx, req)
+	ret0, _ := ret[0].([]entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockDomainItfMockRecorder) ClaimDueWebhookDeliveries(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockDomainItf)(nil).ClaimDueWebhookDeliveries), ctx, req)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockDomainItf) DeleteWebhookEndpoint(ctx context.Context, id, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", ctx, id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockDomainItfMockRecorder) DeleteWebhookEndpoint(ctx, id, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockDomainItf)(nil).DeleteWebhookEndpoint), ctx, id, userId)
+}
+
+// FinishWebhookDeliveryAttempt mocks base method.
+func (m *MockDomainItf) FinishWebhookDeliveryAttempt(ctx context.Context, req FinishWebhookDeliveryAttemptRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishWebhookDeliveryAttempt", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishWebhookDeliveryAttempt indicates an expected call of FinishWebhookDeliveryAttempt.
+func (mr *MockDomainItfMockRecorder) FinishWebhookDeliveryAttempt(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishWebhookDeliveryAttempt", reflect.TypeOf((*MockDomainItf)(nil).FinishWebhookDeliveryAttempt), ctx, req)
+}
+
+// GetSubscribedWebhookEndpoints mocks base method.
+func (m *MockDomainItf) GetSubscribedWebhookEndpoints(ctx context.Context, userId, eventType string) ([]entity.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribedWebhookEndpoints", ctx, userId, eventType)
+	ret0, _ := ret[0].([]entity.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribedWebhookEndpoints indicates an expected call of GetSubscribedWebhookEndpoints.
+func (mr *MockDomainItfMockRecorder) GetSubscribedWebhookEndpoints(ctx, userId, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribedWebhookEndpoints", reflect.TypeOf((*MockDomainItf)(nil).GetSubscribedWebhookEndpoints), ctx, userId, eventType)
+}
+
+// GetWebhookDeliveriesByEndpointId mocks base method.
+func (m *MockDomainItf) GetWebhookDeliveriesByEndpointId(ctx context.Context, endpointId string, limit int) ([]entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveriesByEndpointId", ctx, endpointId, limit)
+	ret0, _ := ret[0].([]entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveriesByEndpointId indicates an expected call of GetWebhookDeliveriesByEndpointId.
+func (mr *MockDomainItfMockRecorder) GetWebhookDeliveriesByEndpointId(ctx, endpointId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveriesByEndpointId", reflect.TypeOf((*MockDomainItf)(nil).GetWebhookDeliveriesByEndpointId), ctx, endpointId, limit)
+}
+
+// GetWebhookDeliveryById mocks base method.
+func (m *MockDomainItf) GetWebhookDeliveryById(ctx context.Context, id string) (entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveryById", ctx, id)
+	ret0, _ := ret[0].(entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveryById indicates an expected call of GetWebhookDeliveryById.
+func (mr *MockDomainItfMockRecorder) GetWebhookDeliveryById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveryById", reflect.TypeOf((*MockDomainItf)(nil).GetWebhookDeliveryById), ctx, id)
+}
+
+// GetWebhookEndpointById mocks base method.
+func (m *MockDomainItf) GetWebhookEndpointById(ctx context.Context, id string) (entity.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpointById", ctx, id)
+	ret0, _ := ret[0].(entity.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpointById indicates an expected call of GetWebhookEndpointById.
+func (mr *MockDomainItfMockRecorder) GetWebhookEndpointById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpointById", reflect.TypeOf((*MockDomainItf)(nil).GetWebhookEndpointById), ctx, id)
+}
+
+// GetWebhookEndpointsByUserId mocks base method.
+func (m *MockDomainItf) GetWebhookEndpointsByUserId(ctx context.Context, userId string, limit int) ([]entity.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpointsByUserId", ctx, userId, limit)
+	ret0, _ := ret[0].([]entity.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpointsByUserId indicates an expected call of GetWebhookEndpointsByUserId.
+func (mr *MockDomainItfMockRecorder) GetWebhookEndpointsByUserId(ctx, userId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpointsByUserId", reflect.TypeOf((*MockDomainItf)(nil).GetWebhookEndpointsByUserId), ctx, userId, limit)
+}
+
+// InsertWebhookDelivery mocks base method.
+func (m *MockDomainItf) InsertWebhookDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookDelivery indicates an expected call of InsertWebhookDelivery.
+func (mr *MockDomainItfMockRecorder) InsertWebhookDelivery(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDelivery", reflect.TypeOf((*MockDomainItf)(nil).InsertWebhookDelivery), ctx, delivery)
+}
+
+// InsertWebhookEndpoint mocks base method.
+func (m *MockDomainItf) InsertWebhookEndpoint(ctx context.Context, endpoint entity.WebhookEndpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookEndpoint", ctx, endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookEndpoint indicates an expected call of InsertWebhookEndpoint.
+func (mr *MockDomainItfMockRecorder) InsertWebhookEndpoint(ctx, endpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookEndpoint", reflect.TypeOf((*MockDomainItf)(nil).InsertWebhookEndpoint), ctx, endpoint)
+}
+
+// RedeliverWebhookDelivery mocks base method.
+func (m *MockDomainItf) RedeliverWebhookDelivery(ctx context.Context, id, endpointId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhookDelivery", ctx, id, endpointId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeliverWebhookDelivery indicates an expected call of RedeliverWebhookDelivery.
+func (mr *MockDomainItfMockRecorder) RedeliverWebhookDelivery(ctx, id, endpointId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookDelivery", reflect.TypeOf((*MockDomainItf)(nil).RedeliverWebhookDelivery), ctx, id, endpointId)
+}
+
+// SendWebhookDelivery mocks base method.
+func (m *MockDomainItf) SendWebhookDelivery(ctx context.Context, endpoint entity.WebhookEndpoint, delivery entity.WebhookDelivery) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendWebhookDelivery", ctx, endpoint, delivery)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendWebhookDelivery indicates an expected call of SendWebhookDelivery.
+func (mr *MockDomainItfMockRecorder) SendWebhookDelivery(ctx, endpoint, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendWebhookDelivery", reflect.TypeOf((*MockDomainItf)(nil).SendWebhookDelivery), ctx, endpoint, delivery)
+}
@@ -0,0 +1,180 @@
+package domainwebhook
+
+const (
+	queryInsertWebhookEndpoint = `
+		INSERT INTO webhook_endpoints (id, user_id, url, secret, event_types, status) VALUES ($1, $2, $3, $4, $5, $6);
+	`
+
+	queryGetWebhookEndpointById = `
+		SELECT
+			id,
+			user_id,
+			url,
+			secret,
+			event_types,
+			status,
+			created_at
+		FROM
+			webhook_endpoints
+		WHERE
+			id = $1;
+	`
+
+	queryGetWebhookEndpointsByUserId = `
+		SELECT
+			id,
+			user_id,
+			url,
+			secret,
+			event_types,
+			status,
+			created_at
+		FROM
+			webhook_endpoints
+		WHERE
+			user_id = $1 AND
+			status = $2
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT $3;
+	`
+
+	queryDeleteWebhookEndpointById = `
+		UPDATE webhook_endpoints SET
+			status = $1,
+			updated_at = NOW()
+		WHERE
+			id = $2 AND
+			user_id = $3 AND
+			status = $4;
+	`
+
+	queryGetSubscribedWebhookEndpoints = `
+		SELECT
+			id,
+			user_id,
+			url,
+			secret,
+			event_types,
+			status,
+			created_at
+		FROM
+			webhook_endpoints
+		WHERE
+			user_id = $1 AND
+			status = $2 AND
+			$3 = ANY(STRING_TO_ARRAY(event_types, ','));
+	`
+
+	// queryInsertWebhookDelivery does nothing for an event the endpoint already has a delivery of, which happens
+	// when an event is read from the stream again.
+	queryInsertWebhookDelivery = `
+		INSERT INTO webhook_deliveries (id, endpoint_id, event_id, event_type, payload, status, next_attempt_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING;
+	`
+
+	// queryClaimDueWebhookDeliveries leases the due deliveries of active endpoints that no other worker holds a
+	// live lease on.
+	queryClaimDueWebhookDeliveries = `
+		UPDATE webhook_deliveries SET
+			lease_id = $1,
+			lease_expires_at = $2,
+			updated_at = NOW()
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_endpoints e ON e.id = d.endpoint_id
+			WHERE d.status = $3 AND d.next_attempt_at <= $4 AND (d.lease_expires_at IS NULL OR d.lease_expires_at <= $4) AND e.status = $5
+			ORDER BY d.next_attempt_at
+			LIMIT $6
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING
+			id,
+			endpoint_id,
+			event_id,
+			event_type,
+			payload,
+			status,
+			attempt,
+			next_attempt_at,
+			COALESCE(last_status_code, 0) AS last_status_code,
+			COALESCE(last_error, '') AS last_error,
+			delivered_at,
+			created_at;
+	`
+
+	// queryFinishWebhookDeliveryById only applies while the lease is still held.
+	queryFinishWebhookDeliveryById = `
+		UPDATE webhook_deliveries SET
+			status = $1,
+			attempt = $2,
+			next_attempt_at = $3,
+			last_status_code = $4,
+			last_error = $5,
+			delivered_at = $6,
+			lease_id = NULL,
+			lease_expires_at = NULL,
+			updated_at = NOW()
+		WHERE
+			id = $7 AND
+			lease_id = $8;
+	`
+
+	queryGetWebhookDeliveryById = `
+		SELECT
+			id,
+			endpoint_id,
+			event_id,
+			event_type,
+			payload,
+			status,
+			attempt,
+			next_attempt_at,
+			COALESCE(last_status_code, 0) AS last_status_code,
+			COALESCE(last_error, '') AS last_error,
+			delivered_at,
+			created_at
+		FROM
+			webhook_deliveries
+		WHERE
+			id = $1;
+	`
+
+	queryGetWebhookDeliveriesByEndpointId = `
+		SELECT
+			id,
+			endpoint_id,
+			event_id,
+			event_type,
+			payload,
+			status,
+			attempt,
+			next_attempt_at,
+			COALESCE(last_status_code, 0) AS last_status_code,
+			COALESCE(last_error, '') AS last_error,
+			delivered_at,
+			created_at
+		FROM
+			webhook_deliveries
+		WHERE
+			endpoint_id = $1
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT $2;
+	`
+
+	// queryRedeliverWebhookDeliveryById starts the attempts of a delivered or dead delivery over.
+	queryRedeliverWebhookDeliveryById = `
+		UPDATE webhook_deliveries SET
+			status = $1,
+			attempt = 0,
+			next_attempt_at = NOW(),
+			updated_at = NOW()
+		WHERE
+			id = $2 AND
+			endpoint_id = $3 AND
+			status <> $1;
+	`
+)
@@ -0,0 +1,38 @@
+package domainwebhook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+var (
+	ErrWebhookEndpointNotActive = fmt.Errorf("webhook endpoint is not active")
+	ErrWebhookDeliveryPending   = fmt.Errorf("webhook delivery is still pending")
+	ErrLeaseLost                = fmt.Errorf("webhook delivery lease was lost")
+)
+
+// Headers of a delivery. Webhook-Id is the event id, the same on every delivery of the event, and
+// Webhook-Signature signs the timestamp together with the body, see signature.Sign.
+const (
+	headerWebhookId         = "Webhook-Id"
+	headerWebhookDeliveryId = "Webhook-Delivery-Id"
+	headerWebhookTimestamp  = "Webhook-Timestamp"
+	headerWebhookSignature  = "Webhook-Signature"
+)
+
+// ClaimDueWebhookDeliveriesRequest leases up to Limit deliveries due at Now to LeaseId until LeaseExpiresAt.
+type ClaimDueWebhookDeliveriesRequest struct {
+	LeaseId        string
+	LeaseExpiresAt time.Time
+	Now            time.Time
+	Limit          int
+}
+
+// FinishWebhookDeliveryAttemptRequest moves Delivery to its state after an attempt, as long as LeaseId still
+// holds the lease.
+type FinishWebhookDeliveryAttemptRequest struct {
+	LeaseId  string
+	Delivery entity.WebhookDelivery
+}
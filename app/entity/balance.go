@@ -1,6 +1,15 @@
 package entity
 
+import "github.com/kevinsudut/wallet-system/pkg/helper/money"
+
 type Balance struct {
-	UserId string  `db:"user_id"`
-	Amount float64 `db:"amount"`
+	UserId     string      `db:"user_id"`
+	Amount     money.Money `db:"amount"`
+	HeldAmount money.Money `db:"held_amount"`
+	Currency   string      `db:"currency"`
+}
+
+// Available is the part of the balance that is not reserved by a hold, which is what can be spent.
+func (b Balance) Available() money.Money {
+	return b.Amount - b.HeldAmount
 }
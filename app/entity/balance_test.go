@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+func TestBalance_Available(t *testing.T) {
+	type fields struct {
+		Amount     money.Money
+		HeldAmount money.Money
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   money.Money
+	}{
+		{
+			name: "nothing held",
+			fields: fields{
+				Amount: 100,
+			},
+			want: 100,
+		},
+		{
+			name: "partially held",
+			fields: fields{
+				Amount:     100,
+				HeldAmount: 30,
+			},
+			want: 70,
+		},
+		{
+			name: "fully held",
+			fields: fields{
+				Amount:     100,
+				HeldAmount: 100,
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Balance{
+				Amount:     tt.fields.Amount,
+				HeldAmount: tt.fields.HeldAmount,
+			}
+			if got := b.Available(); got != tt.want {
+				t.Errorf("Balance.Available() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// CurrencyBalance is the balance of UserId in a currency other than its main one, whose balance is Balance.
+type CurrencyBalance struct {
+	UserId   string      `db:"user_id"`
+	Currency string      `db:"currency"`
+	Amount   money.Money `db:"amount"`
+}
+
+// AccountId is the ledger account of the balance.
+func (b CurrencyBalance) AccountId() string {
+	return enum.CURRENCY_ACCOUNT_PREFIX + b.UserId + ":" + b.Currency
+}
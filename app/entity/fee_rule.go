@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"github.com/kevinsudut/wallet-system/pkg/helper/currency"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// FeeRule prices an operation, the journal entry type it posts, in Currency whose amount is in [MinAmount,
+// MaxAmount). A zero MaxAmount is unbounded. The fee is FlatFee plus RateBps basis points of the amount, and the
+// first FreePerMonth operations of a user in a calendar month are free.
+type FeeRule struct {
+	Id           int64       `db:"id"`
+	Operation    int         `db:"operation"`
+	Currency     string      `db:"currency"`
+	MinAmount    money.Money `db:"min_amount"`
+	MaxAmount    money.Money `db:"max_amount"`
+	FlatFee      money.Money `db:"flat_fee"`
+	RateBps      int64       `db:"rate_bps"`
+	FreePerMonth int64       `db:"free_per_month"`
+}
+
+// IsZero reports whether the rule is missing, which charges no fee.
+func (r FeeRule) IsZero() bool {
+	return r.Id == 0
+}
+
+// Matches reports whether amount falls in the tier of the rule.
+func (r FeeRule) Matches(amount money.Money) bool {
+	return amount >= r.MinAmount && (r.MaxAmount == 0 || amount < r.MaxAmount)
+}
+
+// Fee returns the fee of an operation of amount when the user already did count operations this month. The
+// percentage is rounded half up to the minor unit of Currency.
+func (r FeeRule) Fee(amount money.Money, count int64) money.Money {
+	if r.IsZero() || count < r.FreePerMonth {
+		return 0
+	}
+
+	step := money.MinorUnit(currency.Exponent(r.Currency))
+	divisor := 10000 * step
+	return r.FlatFee + (amount*money.Money(r.RateBps)+divisor/2)/divisor*step
+}
+
+// MatchFeeRule returns the rule of rules that prices amount, the one with the highest MinAmount when tiers
+// overlap. It returns a zero rule when none matches.
+func MatchFeeRule(rules []FeeRule, amount money.Money) FeeRule {
+	var resp FeeRule
+	for _, rule := range rules {
+		if rule.Matches(amount) && (resp.IsZero() || rule.MinAmount > resp.MinAmount) {
+			resp = rule
+		}
+	}
+
+	return resp
+}
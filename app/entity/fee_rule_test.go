@@ -0,0 +1,169 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+func TestFeeRule_Fee(t *testing.T) {
+	type args struct {
+		amount money.Money
+		count  int64
+	}
+	tests := []struct {
+		name string
+		rule FeeRule
+		args args
+		want money.Money
+	}{
+		{
+			name: "zero rule",
+			rule: FeeRule{},
+			args: args{
+				amount: 10000,
+			},
+			want: 0,
+		},
+		{
+			name: "flat",
+			rule: FeeRule{
+				Id:      1,
+				FlatFee: 250,
+			},
+			args: args{
+				amount: 10000,
+			},
+			want: 250,
+		},
+		{
+			name: "percentage rounded half up",
+			rule: FeeRule{
+				Id:       1,
+				Currency: "IDR",
+				RateBps:  150,
+			},
+			args: args{
+				amount: 11000,
+			},
+			want: 170,
+		},
+		{
+			name: "percentage rounded to a currency without decimals",
+			rule: FeeRule{
+				Id:       1,
+				Currency: "JPY",
+				RateBps:  150,
+			},
+			args: args{
+				amount: 1030000,
+			},
+			want: 15000,
+		},
+		{
+			name: "percentage rounded to a currency with three decimals",
+			rule: FeeRule{
+				Id:       1,
+				Currency: "KWD",
+				RateBps:  150,
+			},
+			args: args{
+				amount: 10300,
+			},
+			want: 155,
+		},
+		{
+			name: "flat and percentage",
+			rule: FeeRule{
+				Id:      1,
+				FlatFee: 100,
+				RateBps: 100,
+			},
+			args: args{
+				amount: 10000,
+			},
+			want: 200,
+		},
+		{
+			name: "free within monthly quota",
+			rule: FeeRule{
+				Id:           1,
+				FlatFee:      100,
+				FreePerMonth: 3,
+			},
+			args: args{
+				amount: 10000,
+				count:  2,
+			},
+			want: 0,
+		},
+		{
+			name: "charged after monthly quota",
+			rule: FeeRule{
+				Id:           1,
+				FlatFee:      100,
+				FreePerMonth: 3,
+			},
+			args: args{
+				amount: 10000,
+				count:  3,
+			},
+			want: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Fee(tt.args.amount, tt.args.count); got != tt.want {
+				t.Errorf("FeeRule.Fee() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchFeeRule(t *testing.T) {
+	rules := []FeeRule{
+		{Id: 1, MinAmount: 0, MaxAmount: 10000, FlatFee: 100},
+		{Id: 2, MinAmount: 10000, MaxAmount: 0, RateBps: 50},
+		{Id: 3, MinAmount: 50000, MaxAmount: 100000, FlatFee: 0},
+	}
+
+	tests := []struct {
+		name   string
+		amount money.Money
+		want   FeeRule
+	}{
+		{
+			name:   "lower tier",
+			amount: 9999,
+			want:   rules[0],
+		},
+		{
+			name:   "max amount is exclusive",
+			amount: 10000,
+			want:   rules[1],
+		},
+		{
+			name:   "overlapping tier with the highest min amount",
+			amount: 50000,
+			want:   rules[2],
+		},
+		{
+			name:   "unbounded tier",
+			amount: 100000,
+			want:   rules[1],
+		},
+		{
+			name:   "no rule",
+			amount: -1,
+			want:   FeeRule{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchFeeRule(rules, tt.amount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchFeeRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
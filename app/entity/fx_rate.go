@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/currency"
+)
+
+// FxRate is the rate at which one unit of BaseCurrency buys units of QuoteCurrency from EffectiveAt on.
+type FxRate struct {
+	Id            int64         `db:"id"`
+	BaseCurrency  string        `db:"base_currency"`
+	QuoteCurrency string        `db:"quote_currency"`
+	Rate          currency.Rate `db:"rate"`
+	EffectiveAt   time.Time     `db:"effective_at"`
+}
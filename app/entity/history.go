@@ -1,18 +1,39 @@
 package entity
 
-import "github.com/kevinsudut/wallet-system/app/enum"
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
 
 type History struct {
-	Id           string  `db:"id"`
-	UserId       string  `db:"user_id"`
-	TargetUserId string  `db:"target_user_id"`
-	Amount       float64 `db:"amount"`
-	Type         int     `db:"type"`
-	Notes        string  `db:"notes"`
+	Id             string      `db:"id"`
+	JournalEntryId string      `db:"journal_entry_id"`
+	UserId         string      `db:"user_id"`
+	TargetUserId   string      `db:"target_user_id"`
+	Amount         money.Money `db:"amount"`
+	Fee            money.Money `db:"fee"`
+	Currency       string      `db:"currency"`
+	Type           int         `db:"type"`
+	Notes          string      `db:"notes"`
+	ReferenceId    string      `db:"reference_id"`
+	CreatedAt      time.Time   `db:"created_at"`
 }
 
+// NormalizeAmount makes the amount of histories that take money from the user negative.
 func (h *History) NormalizeAmount() {
-	if h.Amount > 0 && h.Type == int(enum.DEBIT) {
-		h.Amount *= -1
+	if h.Amount > 0 && (h.Type == int(enum.DEBIT) || h.Type == int(enum.FEE) || h.Type == int(enum.SHARED_WALLET)) {
+		h.Amount = -h.Amount
+	}
+}
+
+// Summary is what the history adds to history_summaries.
+func (h History) Summary() HistorySummary {
+	return HistorySummary{
+		UserId:       h.UserId,
+		TargetUserId: h.TargetUserId,
+		Amount:       h.Amount,
+		Type:         h.Type,
 	}
 }
@@ -1,13 +1,21 @@
 package entity
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
 
 type HistorySummary struct {
-	Id           string  `db:"id"`
-	UserId       string  `db:"user_id"`
-	TargetUserId string  `db:"target_user_id"`
-	Amount       float64 `db:"amount"`
-	Type         int     `db:"type"`
+	Id           string      `db:"id"`
+	UserId       string      `db:"user_id"`
+	TargetUserId string      `db:"target_user_id"`
+	Amount       money.Money `db:"amount"`
+	Type         int         `db:"type"`
+
+	// At places the amount in the summary buckets of that time instead of the current ones, zero means now.
+	At time.Time `db:"-"`
 }
 
 func (hs HistorySummary) GetId() string {
@@ -2,6 +2,8 @@ package entity
 
 import (
 	"testing"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 )
 
 func TestHistorySummary_GetId(t *testing.T) {
@@ -9,7 +11,7 @@ func TestHistorySummary_GetId(t *testing.T) {
 		Id           string
 		UserId       string
 		TargetUserId string
-		Amount       float64
+		Amount       money.Money
 		Type         int
 	}
 	tests := []struct {
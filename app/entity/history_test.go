@@ -2,6 +2,8 @@ package entity
 
 import (
 	"testing"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 )
 
 func TestHistory_NormalizeAmount(t *testing.T) {
@@ -9,14 +11,14 @@ func TestHistory_NormalizeAmount(t *testing.T) {
 		Id           string
 		UserId       string
 		TargetUserId string
-		Amount       float64
+		Amount       money.Money
 		Type         int
 		Notes        string
 	}
 	tests := []struct {
 		name   string
 		fields fields
-		want   float64
+		want   money.Money
 	}{
 		{
 			name: "+amount",
@@ -34,6 +36,30 @@ func TestHistory_NormalizeAmount(t *testing.T) {
 			},
 			want: -100,
 		},
+		{
+			name: "fee",
+			fields: fields{
+				Type:   3,
+				Amount: 100,
+			},
+			want: -100,
+		},
+		{
+			name: "shared wallet deposit",
+			fields: fields{
+				Type:   5,
+				Amount: 100,
+			},
+			want: -100,
+		},
+		{
+			name: "credit",
+			fields: fields{
+				Type:   1,
+				Amount: 100,
+			},
+			want: 100,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// Hold reserves Amount of the balance of UserId for TargetUserId until ExpiresAt.
+type Hold struct {
+	Id             string      `db:"id"`
+	UserId         string      `db:"user_id"`
+	TargetUserId   string      `db:"target_user_id"`
+	Amount         money.Money `db:"amount"`
+	CapturedAmount money.Money `db:"captured_amount"`
+	Status         int         `db:"status"`
+	TransferId     string      `db:"journal_entry_id"`
+	ExpiresAt      time.Time   `db:"expires_at"`
+	CreatedAt      time.Time   `db:"created_at"`
+}
+
+// IsActive reports whether the hold still reserves its amount at now. An expired hold stays active in the
+// database until it is released, but can no longer be captured.
+func (h Hold) IsActive(now time.Time) bool {
+	return h.Status == int(enum.HOLD_STATUS_ACTIVE) && now.Before(h.ExpiresAt)
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+func TestHold_IsActive(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		Status    int
+		ExpiresAt time.Time
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "active",
+			fields: fields{
+				Status:    int(enum.HOLD_STATUS_ACTIVE),
+				ExpiresAt: now.Add(time.Minute),
+			},
+			want: true,
+		},
+		{
+			name: "active but expired",
+			fields: fields{
+				Status:    int(enum.HOLD_STATUS_ACTIVE),
+				ExpiresAt: now,
+			},
+			want: false,
+		},
+		{
+			name: "captured",
+			fields: fields{
+				Status:    int(enum.HOLD_STATUS_CAPTURED),
+				ExpiresAt: now.Add(time.Minute),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Hold{
+				Status:    tt.fields.Status,
+				ExpiresAt: tt.fields.ExpiresAt,
+			}
+			if got := h.IsActive(now); got != tt.want {
+				t.Errorf("Hold.IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package entity
+
+type IdempotencyKey struct {
+	UserId       string `db:"user_id"`
+	Key          string `db:"key"`
+	Fingerprint  string `db:"fingerprint"`
+	ResponseCode int    `db:"response_code"`
+	ResponseBody string `db:"response_body"`
+}
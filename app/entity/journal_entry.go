@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+type JournalEntry struct {
+	Id          string    `db:"id"`
+	Type        int       `db:"type"`
+	Description string    `db:"description"`
+	Postings    []Posting `db:"-"`
+}
+
+// IsBalanced reports whether the entry moves money between at least two accounts and its postings sum to zero.
+func (je JournalEntry) IsBalanced() bool {
+	if len(je.Postings) < 2 {
+		return false
+	}
+
+	var sum money.Money
+	for _, posting := range je.Postings {
+		sum += posting.Amount
+	}
+
+	return sum == 0
+}
+
+type Posting struct {
+	Id             string      `db:"id"`
+	JournalEntryId string      `db:"journal_entry_id"`
+	AccountId      string      `db:"account_id"`
+	Amount         money.Money `db:"amount"`
+	CreatedAt      time.Time   `db:"created_at"`
+}
+
+func (p Posting) IsSystemAccount() bool {
+	return strings.HasPrefix(p.AccountId, enum.SYSTEM_ACCOUNT_PREFIX)
+}
+
+// IsPocketAccount reports whether the posting is on a pocket, whose id is PocketId.
+func (p Posting) IsPocketAccount() bool {
+	return strings.HasPrefix(p.AccountId, enum.POCKET_ACCOUNT_PREFIX)
+}
+
+func (p Posting) PocketId() string {
+	return strings.TrimPrefix(p.AccountId, enum.POCKET_ACCOUNT_PREFIX)
+}
+
+// IsSharedWalletAccount reports whether the posting is on a shared wallet, whose id is SharedWalletId.
+func (p Posting) IsSharedWalletAccount() bool {
+	return strings.HasPrefix(p.AccountId, enum.SHARED_WALLET_ACCOUNT_PREFIX)
+}
+
+func (p Posting) SharedWalletId() string {
+	return strings.TrimPrefix(p.AccountId, enum.SHARED_WALLET_ACCOUNT_PREFIX)
+}
+
+// IsCurrencyAccount reports whether the posting is on a balance of a user in another currency, see CurrencyBalance.
+func (p Posting) IsCurrencyAccount() bool {
+	return strings.HasPrefix(p.AccountId, enum.CURRENCY_ACCOUNT_PREFIX)
+}
+
+// CurrencyBalance returns the user and currency of a posting on a currency account.
+func (p Posting) CurrencyBalance() CurrencyBalance {
+	userId, currency, _ := strings.Cut(strings.TrimPrefix(p.AccountId, enum.CURRENCY_ACCOUNT_PREFIX), ":")
+
+	return CurrencyBalance{
+		UserId:   userId,
+		Currency: currency,
+	}
+}
@@ -0,0 +1,230 @@
+package entity
+
+import (
+	"testing"
+)
+
+func TestJournalEntry_IsBalanced(t *testing.T) {
+	type fields struct {
+		Postings []Posting
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "balanced",
+			fields: fields{
+				Postings: []Posting{
+					{
+						AccountId: "from",
+						Amount:    -100,
+					},
+					{
+						AccountId: "to",
+						Amount:    100,
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "unbalanced",
+			fields: fields{
+				Postings: []Posting{
+					{
+						AccountId: "from",
+						Amount:    -100,
+					},
+					{
+						AccountId: "to",
+						Amount:    99,
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "single posting",
+			fields: fields{
+				Postings: []Posting{
+					{
+						AccountId: "to",
+						Amount:    0,
+					},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			je := JournalEntry{
+				Postings: tt.fields.Postings,
+			}
+			if got := je.IsBalanced(); got != tt.want {
+				t.Errorf("JournalEntry.IsBalanced() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosting_IsSystemAccount(t *testing.T) {
+	type fields struct {
+		AccountId string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "system account",
+			fields: fields{
+				AccountId: "system:funding",
+			},
+			want: true,
+		},
+		{
+			name: "user account",
+			fields: fields{
+				AccountId: "id",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Posting{
+				AccountId: tt.fields.AccountId,
+			}
+			if got := p.IsSystemAccount(); got != tt.want {
+				t.Errorf("Posting.IsSystemAccount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosting_IsPocketAccount(t *testing.T) {
+	type fields struct {
+		AccountId string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "pocket account",
+			fields: fields{
+				AccountId: "pocket:pocketid",
+			},
+			want: true,
+		},
+		{
+			name: "user account",
+			fields: fields{
+				AccountId: "id",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Posting{
+				AccountId: tt.fields.AccountId,
+			}
+			if got := p.IsPocketAccount(); got != tt.want {
+				t.Errorf("Posting.IsPocketAccount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosting_IsSharedWalletAccount(t *testing.T) {
+	type fields struct {
+		AccountId string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "shared wallet account",
+			fields: fields{
+				AccountId: "wallet:walletid",
+			},
+			want: true,
+		},
+		{
+			name: "pocket account",
+			fields: fields{
+				AccountId: "pocket:pocketid",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Posting{
+				AccountId: tt.fields.AccountId,
+			}
+			if got := p.IsSharedWalletAccount(); got != tt.want {
+				t.Errorf("Posting.IsSharedWalletAccount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosting_CurrencyBalance(t *testing.T) {
+	type fields struct {
+		AccountId string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   CurrencyBalance
+		wantOk bool
+	}{
+		{
+			name: "currency account",
+			fields: fields{
+				AccountId: "currency:userid:USD",
+			},
+			want: CurrencyBalance{
+				UserId:   "userid",
+				Currency: "USD",
+			},
+			wantOk: true,
+		},
+		{
+			name: "fx account",
+			fields: fields{
+				AccountId: "system:fx:USD",
+			},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Posting{
+				AccountId: tt.fields.AccountId,
+			}
+			if got := p.IsCurrencyAccount(); got != tt.wantOk {
+				t.Errorf("Posting.IsCurrencyAccount() = %v, want %v", got, tt.wantOk)
+			}
+			if !tt.wantOk {
+				return
+			}
+			got := p.CurrencyBalance()
+			if got != tt.want {
+				t.Errorf("Posting.CurrencyBalance() = %v, want %v", got, tt.want)
+			}
+			if got.AccountId() != tt.fields.AccountId {
+				t.Errorf("CurrencyBalance.AccountId() = %v, want %v", got.AccountId(), tt.fields.AccountId)
+			}
+		})
+	}
+}
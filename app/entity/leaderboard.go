@@ -0,0 +1,8 @@
+package entity
+
+import "github.com/kevinsudut/wallet-system/pkg/helper/money"
+
+type LeaderboardEntry struct {
+	UserId string      `db:"user_id"`
+	Amount money.Money `db:"amount"`
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// Limit caps the amount and count of an operation within a period, a zero cap is unlimited. A per-transaction
+// limit only caps the amount of a single operation.
+type Limit struct {
+	Operation enum.LimitOperation `db:"operation"`
+	Period    enum.LimitPeriod    `db:"period"`
+	MaxAmount money.Money         `db:"max_amount"`
+	MaxCount  int64               `db:"max_count"`
+}
+
+// LimitWindow is the calendar day or month a period limit is counted in, From inclusive and To exclusive.
+type LimitWindow struct {
+	Period enum.LimitPeriod
+	From   time.Time
+	To     time.Time
+}
+
+// LimitUsage is the total amount and number of the operations of a user within a limit window.
+type LimitUsage struct {
+	Amount money.Money `db:"amount"`
+	Count  int64       `db:"count"`
+}
+
+// NewLimitWindow returns the window of period that now falls in, using the calendar of location.
+func NewLimitWindow(period enum.LimitPeriod, now time.Time, location *time.Location) LimitWindow {
+	now = now.In(location)
+
+	switch period {
+	case enum.LIMIT_PERIOD_DAY:
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+		return LimitWindow{
+			Period: period,
+			From:   from,
+			To:     from.AddDate(0, 0, 1),
+		}
+
+	case enum.LIMIT_PERIOD_MONTH:
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+		return LimitWindow{
+			Period: period,
+			From:   from,
+			To:     from.AddDate(0, 1, 0),
+		}
+	}
+
+	return LimitWindow{
+		Period: period,
+	}
+}
+
+// RemainingAmount returns how much more can be used within the limit, -1 when the amount is unlimited.
+func (l Limit) RemainingAmount(usage LimitUsage) money.Money {
+	if l.MaxAmount <= 0 {
+		return -1
+	}
+
+	if usage.Amount >= l.MaxAmount {
+		return 0
+	}
+
+	return l.MaxAmount - usage.Amount
+}
+
+// RemainingCount returns how many more operations fit within the limit, -1 when the count is unlimited.
+func (l Limit) RemainingCount(usage LimitUsage) int64 {
+	if l.MaxCount <= 0 {
+		return -1
+	}
+
+	if usage.Count >= l.MaxCount {
+		return 0
+	}
+
+	return l.MaxCount - usage.Count
+}
+
+// Allows reports whether usage, which already includes the operation being checked, stays within the limit.
+func (l Limit) Allows(usage LimitUsage) bool {
+	if l.MaxAmount > 0 && usage.Amount > l.MaxAmount {
+		return false
+	}
+
+	return l.MaxCount <= 0 || usage.Count <= l.MaxCount
+}
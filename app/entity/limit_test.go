@@ -0,0 +1,198 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+func TestNewLimitWindow(t *testing.T) {
+	jakarta := time.FixedZone("Asia/Jakarta", 7*60*60)
+
+	type args struct {
+		period   enum.LimitPeriod
+		now      time.Time
+		location *time.Location
+	}
+	tests := []struct {
+		name string
+		args args
+		want LimitWindow
+	}{
+		{
+			name: "day",
+			args: args{
+				period:   enum.LIMIT_PERIOD_DAY,
+				now:      time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: LimitWindow{
+				Period: enum.LIMIT_PERIOD_DAY,
+				From:   time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+				To:     time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "day in business time zone",
+			args: args{
+				period:   enum.LIMIT_PERIOD_DAY,
+				now:      time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC),
+				location: jakarta,
+			},
+			want: LimitWindow{
+				Period: enum.LIMIT_PERIOD_DAY,
+				From:   time.Date(2026, 10, 19, 0, 0, 0, 0, jakarta),
+				To:     time.Date(2026, 10, 20, 0, 0, 0, 0, jakarta),
+			},
+		},
+		{
+			name: "month",
+			args: args{
+				period:   enum.LIMIT_PERIOD_MONTH,
+				now:      time.Date(2026, 12, 31, 12, 0, 0, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: LimitWindow{
+				Period: enum.LIMIT_PERIOD_MONTH,
+				From:   time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+				To:     time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "transaction",
+			args: args{
+				period:   enum.LIMIT_PERIOD_TRANSACTION,
+				now:      time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: LimitWindow{
+				Period: enum.LIMIT_PERIOD_TRANSACTION,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewLimitWindow(tt.args.period, tt.args.now, tt.args.location); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewLimitWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimit_Remaining(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      Limit
+		usage      LimitUsage
+		wantAmount money.Money
+		wantCount  int64
+	}{
+		{
+			name: "within",
+			limit: Limit{
+				MaxAmount: 100 * money.Unit,
+				MaxCount:  5,
+			},
+			usage: LimitUsage{
+				Amount: 30 * money.Unit,
+				Count:  2,
+			},
+			wantAmount: 70 * money.Unit,
+			wantCount:  3,
+		},
+		{
+			name: "used up",
+			limit: Limit{
+				MaxAmount: 100 * money.Unit,
+				MaxCount:  5,
+			},
+			usage: LimitUsage{
+				Amount: 120 * money.Unit,
+				Count:  6,
+			},
+			wantAmount: 0,
+			wantCount:  0,
+		},
+		{
+			name:  "unlimited",
+			limit: Limit{},
+			usage: LimitUsage{
+				Amount: 120 * money.Unit,
+				Count:  6,
+			},
+			wantAmount: -1,
+			wantCount:  -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.RemainingAmount(tt.usage); got != tt.wantAmount {
+				t.Errorf("Limit.RemainingAmount() = %v, want %v", got, tt.wantAmount)
+			}
+			if got := tt.limit.RemainingCount(tt.usage); got != tt.wantCount {
+				t.Errorf("Limit.RemainingCount() = %v, want %v", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestLimit_Allows(t *testing.T) {
+	limit := Limit{
+		MaxAmount: 100 * money.Unit,
+		MaxCount:  5,
+	}
+
+	tests := []struct {
+		name  string
+		limit Limit
+		usage LimitUsage
+		want  bool
+	}{
+		{
+			name:  "at the limit",
+			limit: limit,
+			usage: LimitUsage{
+				Amount: 100 * money.Unit,
+				Count:  5,
+			},
+			want: true,
+		},
+		{
+			name:  "amount exceeded",
+			limit: limit,
+			usage: LimitUsage{
+				Amount: 100*money.Unit + 1,
+				Count:  1,
+			},
+			want: false,
+		},
+		{
+			name:  "count exceeded",
+			limit: limit,
+			usage: LimitUsage{
+				Amount: money.Unit,
+				Count:  6,
+			},
+			want: false,
+		},
+		{
+			name:  "unlimited",
+			limit: Limit{},
+			usage: LimitUsage{
+				Amount: 1000 * money.Unit,
+				Count:  1000,
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.Allows(tt.usage); got != tt.want {
+				t.Errorf("Limit.Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// Notification is pushed to the connected clients of UserId. Id is its entry in the notification stream of the
+// user, which orders the notifications and lets a client resume after the last one it has seen. Data is the JSON
+// of a BalanceChangedNotification or TransactionCreatedNotification, by Type.
+type Notification struct {
+	Id        string              `json:"id"`
+	UserId    string              `json:"user_id"`
+	Type      string              `json:"type"`
+	Data      jsoniter.RawMessage `json:"data"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// StreamValues are the fields of the notification as an entry of the notification stream.
+func (n Notification) StreamValues() map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":    n.UserId,
+		"type":       n.Type,
+		"data":       string(n.Data),
+		"created_at": n.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// IsAfter reports whether the notification comes after the one with id in the stream of its user.
+func (n Notification) IsAfter(id string) bool {
+	return CompareNotificationIds(n.Id, id) > 0
+}
+
+// NewNotificationFromStream reads back the notification that StreamValues published as the stream entry id.
+func NewNotificationFromStream(id string, values map[string]interface{}) (Notification, error) {
+	fields := make(map[string]string, len(values))
+	for key, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return Notification{}, fmt.Errorf("stream entry %s: field %s is not a string", id, key)
+		}
+		fields[key] = str
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, fields["created_at"])
+	if err != nil {
+		return Notification{}, fmt.Errorf("stream entry %s: invalid created_at: %w", id, err)
+	}
+
+	if fields["type"] == "" || !jsoniter.Valid([]byte(fields["data"])) {
+		return Notification{}, fmt.Errorf("stream entry %s: missing type or invalid data", id)
+	}
+
+	return Notification{
+		Id:        id,
+		UserId:    fields["user_id"],
+		Type:      fields["type"],
+		Data:      jsoniter.RawMessage(fields["data"]),
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// IsValidNotificationId reports whether id has the form of a stream entry id, which is what a client sends back
+// to resume.
+func IsValidNotificationId(id string) bool {
+	_, _, ok := parseNotificationId(id)
+	return ok
+}
+
+// CompareNotificationIds returns -1, 0 or 1 as a comes before, is or comes after b. An invalid id comes before
+// every valid one.
+func CompareNotificationIds(a string, b string) int {
+	aMs, aSeq, aOk := parseNotificationId(a)
+	bMs, bSeq, bOk := parseNotificationId(b)
+	if aOk != bOk {
+		if aOk {
+			return 1
+		}
+		return -1
+	}
+
+	if aMs != bMs {
+		if aMs > bMs {
+			return 1
+		}
+		return -1
+	}
+
+	if aSeq != bSeq {
+		if aSeq > bSeq {
+			return 1
+		}
+		return -1
+	}
+
+	return 0
+}
+
+func parseNotificationId(id string) (ms uint64, seq uint64, ok bool) {
+	msStr, seqStr, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	ms, err := strconv.ParseUint(msStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	seq, err = strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return ms, seq, true
+}
+
+// BalanceChangedNotification is the balance of the user once a change to it is committed.
+type BalanceChangedNotification struct {
+	Currency         string      `json:"currency"`
+	Balance          money.Money `json:"balance"`
+	AvailableBalance money.Money `json:"available_balance"`
+	HeldBalance      money.Money `json:"held_balance"`
+}
+
+// TransactionCreatedNotification is a history written for the user. Amount is negative when money left the user.
+type TransactionCreatedNotification struct {
+	Id                   string      `json:"id"`
+	JournalEntryId       string      `json:"journal_entry_id"`
+	Type                 string      `json:"type"`
+	CounterpartyUsername string      `json:"counterparty_username"`
+	Amount               money.Money `json:"amount"`
+	Currency             string      `json:"currency,omitempty"`
+	Fee                  money.Money `json:"fee"`
+	Notes                string      `json:"notes"`
+	CreatedAt            time.Time   `json:"created_at"`
+}
@@ -0,0 +1,228 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func TestNotification_StreamValues(t *testing.T) {
+	type fields struct {
+		Id        string
+		UserId    string
+		Type      string
+		Data      jsoniter.RawMessage
+		CreatedAt time.Time
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   map[string]interface{}
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Id:        "1-0",
+				UserId:    "id",
+				Type:      "balance.changed",
+				Data:      jsoniter.RawMessage(`{"balance":1000}`),
+				CreatedAt: time.Date(2026, 10, 18, 19, 0, 0, 0, time.FixedZone("WIB", 7*60*60)),
+			},
+			want: map[string]interface{}{
+				"user_id":    "id",
+				"type":       "balance.changed",
+				"data":       `{"balance":1000}`,
+				"created_at": "2026-10-18T12:00:00Z",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := Notification{
+				Id:        tt.fields.Id,
+				UserId:    tt.fields.UserId,
+				Type:      tt.fields.Type,
+				Data:      tt.fields.Data,
+				CreatedAt: tt.fields.CreatedAt,
+			}
+			if got := n.StreamValues(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Notification.StreamValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNotificationFromStream(t *testing.T) {
+	type args struct {
+		id     string
+		values map[string]interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Notification
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"user_id":    "id",
+					"type":       "balance.changed",
+					"data":       `{"balance":1000}`,
+					"created_at": "2026-10-18T12:00:00Z",
+				},
+			},
+			want: Notification{
+				Id:        "1-0",
+				UserId:    "id",
+				Type:      "balance.changed",
+				Data:      jsoniter.RawMessage(`{"balance":1000}`),
+				CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "error field is not a string",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"user_id": 1,
+				},
+			},
+			want:    Notification{},
+			wantErr: true,
+		},
+		{
+			name: "error invalid created_at",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"type":       "balance.changed",
+					"data":       `{}`,
+					"created_at": "yesterday",
+				},
+			},
+			want:    Notification{},
+			wantErr: true,
+		},
+		{
+			name: "error invalid data",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"type":       "balance.changed",
+					"data":       `{`,
+					"created_at": "2026-10-18T12:00:00Z",
+				},
+			},
+			want:    Notification{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewNotificationFromStream(tt.args.id, tt.args.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewNotificationFromStream() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewNotificationFromStream() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareNotificationIds(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "same id",
+			args: args{
+				a: "1700000000000-1",
+				b: "1700000000000-1",
+			},
+			want: 0,
+		},
+		{
+			name: "later millisecond",
+			args: args{
+				a: "1700000000001-0",
+				b: "1700000000000-5",
+			},
+			want: 1,
+		},
+		{
+			name: "earlier sequence",
+			args: args{
+				a: "1700000000000-1",
+				b: "1700000000000-10",
+			},
+			want: -1,
+		},
+		{
+			name: "invalid comes first",
+			args: args{
+				a: "foo",
+				b: "0-0",
+			},
+			want: -1,
+		},
+		{
+			name: "valid after invalid",
+			args: args{
+				a: "0-1",
+				b: "",
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareNotificationIds(tt.args.a, tt.args.b); got != tt.want {
+				t.Errorf("CompareNotificationIds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidNotificationId(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{
+			name: "valid",
+			id:   "1700000000000-0",
+			want: true,
+		},
+		{
+			name: "missing sequence",
+			id:   "1700000000000",
+			want: false,
+		},
+		{
+			name: "not a number",
+			id:   "a-b",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidNotificationId(tt.id); got != tt.want {
+				t.Errorf("IsValidNotificationId() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
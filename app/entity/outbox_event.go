@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// OutboxEvent is an event written in the transaction of the change it describes, kept until the relay has
+// published it. Id orders the events, EventId identifies them to consumers, which see an event again when the
+// relay stops between publishing and marking it. StreamId is the id of the event in the stream, and only set on
+// an event read from there, which carries no Id.
+type OutboxEvent struct {
+	Id        int64     `db:"id"`
+	EventId   string    `db:"event_id"`
+	UserId    string    `db:"user_id"`
+	Type      string    `db:"type"`
+	Version   int       `db:"version"`
+	Payload   string    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+	StreamId  string    `db:"-"`
+}
+
+// StreamValues are the fields of the event as an entry of the event stream.
+func (e OutboxEvent) StreamValues() map[string]interface{} {
+	return map[string]interface{}{
+		"event_id":    e.EventId,
+		"user_id":     e.UserId,
+		"type":        e.Type,
+		"version":     strconv.Itoa(e.Version),
+		"payload":     e.Payload,
+		"occurred_at": e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// NewOutboxEventFromStream reads back the event that StreamValues published as the stream entry id.
+func NewOutboxEventFromStream(id string, values map[string]interface{}) (OutboxEvent, error) {
+	fields := make(map[string]string, len(values))
+	for key, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return OutboxEvent{}, fmt.Errorf("stream entry %s: field %s is not a string", id, key)
+		}
+		fields[key] = str
+	}
+
+	version, err := strconv.Atoi(fields["version"])
+	if err != nil {
+		return OutboxEvent{}, fmt.Errorf("stream entry %s: invalid version: %w", id, err)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, fields["occurred_at"])
+	if err != nil {
+		return OutboxEvent{}, fmt.Errorf("stream entry %s: invalid occurred_at: %w", id, err)
+	}
+
+	if fields["event_id"] == "" || fields["type"] == "" {
+		return OutboxEvent{}, fmt.Errorf("stream entry %s: missing event_id or type", id)
+	}
+
+	return OutboxEvent{
+		EventId:   fields["event_id"],
+		UserId:    fields["user_id"],
+		Type:      fields["type"],
+		Version:   version,
+		Payload:   fields["payload"],
+		CreatedAt: createdAt,
+		StreamId:  id,
+	}, nil
+}
+
+// BalanceChangedEvent is the payload of BalanceCredited and BalanceDebited. Amount is always positive, the
+// event type tells its direction.
+type BalanceChangedEvent struct {
+	UserId         string      `json:"user_id"`
+	JournalEntryId string      `json:"journal_entry_id"`
+	EntryType      string      `json:"entry_type"`
+	Amount         money.Money `json:"amount"`
+	Currency       string      `json:"currency"`
+}
+
+// TransferCompletedEvent is the payload of TransferCompleted. ToAmount and ToCurrency differ from Amount and
+// Currency only when the transfer was converted.
+type TransferCompletedEvent struct {
+	TransferId string      `json:"transfer_id"`
+	FromUserId string      `json:"from_user_id"`
+	ToUserId   string      `json:"to_user_id"`
+	Amount     money.Money `json:"amount"`
+	Fee        money.Money `json:"fee"`
+	Currency   string      `json:"currency"`
+	ToAmount   money.Money `json:"to_amount"`
+	ToCurrency string      `json:"to_currency"`
+}
+
+// UserRegisteredEvent is the payload of UserRegistered.
+type UserRegisteredEvent struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+	Currency string `json:"currency"`
+}
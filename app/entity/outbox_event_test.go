@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutboxEvent_StreamValues(t *testing.T) {
+	type fields struct {
+		Id        int64
+		EventId   string
+		UserId    string
+		Type      string
+		Version   int
+		Payload   string
+		CreatedAt time.Time
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   map[string]interface{}
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Id:        1,
+				EventId:   "eid",
+				UserId:    "id",
+				Type:      "BalanceCredited",
+				Version:   1,
+				Payload:   `{"user_id":"id"}`,
+				CreatedAt: time.Date(2026, 10, 18, 19, 0, 0, 0, time.FixedZone("WIB", 7*60*60)),
+			},
+			want: map[string]interface{}{
+				"event_id":    "eid",
+				"user_id":     "id",
+				"type":        "BalanceCredited",
+				"version":     "1",
+				"payload":     `{"user_id":"id"}`,
+				"occurred_at": "2026-10-18T12:00:00Z",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := OutboxEvent{
+				Id:        tt.fields.Id,
+				EventId:   tt.fields.EventId,
+				UserId:    tt.fields.UserId,
+				Type:      tt.fields.Type,
+				Version:   tt.fields.Version,
+				Payload:   tt.fields.Payload,
+				CreatedAt: tt.fields.CreatedAt,
+			}
+			if got := e.StreamValues(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OutboxEvent.StreamValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOutboxEventFromStream(t *testing.T) {
+	type args struct {
+		id     string
+		values map[string]interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    OutboxEvent
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"event_id":    "eid",
+					"user_id":     "id",
+					"type":        "BalanceCredited",
+					"version":     "1",
+					"payload":     `{"user_id":"id"}`,
+					"occurred_at": "2026-10-18T12:00:00Z",
+				},
+			},
+			want: OutboxEvent{
+				EventId:   "eid",
+				UserId:    "id",
+				Type:      "BalanceCredited",
+				Version:   1,
+				Payload:   `{"user_id":"id"}`,
+				CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+				StreamId:  "1-0",
+			},
+		},
+		{
+			name: "error field is not a string",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"event_id": 1,
+				},
+			},
+			want:    OutboxEvent{},
+			wantErr: true,
+		},
+		{
+			name: "error invalid version",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"event_id":    "eid",
+					"type":        "BalanceCredited",
+					"version":     "v1",
+					"occurred_at": "2026-10-18T12:00:00Z",
+				},
+			},
+			want:    OutboxEvent{},
+			wantErr: true,
+		},
+		{
+			name: "error invalid occurred_at",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"event_id":    "eid",
+					"type":        "BalanceCredited",
+					"version":     "1",
+					"occurred_at": "yesterday",
+				},
+			},
+			want:    OutboxEvent{},
+			wantErr: true,
+		},
+		{
+			name: "error missing event_id",
+			args: args{
+				id: "1-0",
+				values: map[string]interface{}{
+					"type":        "BalanceCredited",
+					"version":     "1",
+					"occurred_at": "2026-10-18T12:00:00Z",
+				},
+			},
+			want:    OutboxEvent{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewOutboxEventFromStream(tt.args.id, tt.args.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewOutboxEventFromStream() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewOutboxEventFromStream() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// PaymentRequest asks PayerId to transfer Amount to RequesterId before ExpiresAt.
+type PaymentRequest struct {
+	Id          string      `db:"id"`
+	RequesterId string      `db:"requester_id"`
+	PayerId     string      `db:"payer_id"`
+	Amount      money.Money `db:"amount"`
+	Status      int         `db:"status"`
+	ExpiresAt   time.Time   `db:"expires_at"`
+	CreatedAt   time.Time   `db:"created_at"`
+}
+
+// StatusAt returns the status of the request at now, which is expired for a pending request past its expiry.
+// A request already being accepted does not expire.
+func (pr PaymentRequest) StatusAt(now time.Time) enum.PaymentRequestStatus {
+	if pr.Status == int(enum.PAYMENT_REQUEST_STATUS_PENDING) && !now.Before(pr.ExpiresAt) {
+		return enum.PAYMENT_REQUEST_STATUS_EXPIRED
+	}
+
+	return enum.PaymentRequestStatus(pr.Status)
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+func TestPaymentRequest_StatusAt(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		Status    int
+		ExpiresAt time.Time
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   enum.PaymentRequestStatus
+	}{
+		{
+			name: "pending",
+			fields: fields{
+				Status:    int(enum.PAYMENT_REQUEST_STATUS_PENDING),
+				ExpiresAt: now.Add(time.Minute),
+			},
+			want: enum.PAYMENT_REQUEST_STATUS_PENDING,
+		},
+		{
+			name: "pending but expired",
+			fields: fields{
+				Status:    int(enum.PAYMENT_REQUEST_STATUS_PENDING),
+				ExpiresAt: now,
+			},
+			want: enum.PAYMENT_REQUEST_STATUS_EXPIRED,
+		},
+		{
+			name: "accepting past expiry",
+			fields: fields{
+				Status:    int(enum.PAYMENT_REQUEST_STATUS_ACCEPTING),
+				ExpiresAt: now.Add(-time.Minute),
+			},
+			want: enum.PAYMENT_REQUEST_STATUS_ACCEPTING,
+		},
+		{
+			name: "declined",
+			fields: fields{
+				Status:    int(enum.PAYMENT_REQUEST_STATUS_DECLINED),
+				ExpiresAt: now.Add(time.Minute),
+			},
+			want: enum.PAYMENT_REQUEST_STATUS_DECLINED,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := PaymentRequest{
+				Status:    tt.fields.Status,
+				ExpiresAt: tt.fields.ExpiresAt,
+			}
+			if got := pr.StatusAt(now); got != tt.want {
+				t.Errorf("PaymentRequest.StatusAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
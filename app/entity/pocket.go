@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// Pocket is a named wallet of UserId next to its main wallet. A zero GoalAmount means no goal, and money cannot
+// leave the pocket before LockedUntil.
+type Pocket struct {
+	Id          string      `db:"id"`
+	UserId      string      `db:"user_id"`
+	Name        string      `db:"name"`
+	Amount      money.Money `db:"amount"`
+	Currency    string      `db:"currency"`
+	GoalAmount  money.Money `db:"goal_amount"`
+	LockedUntil *time.Time  `db:"locked_until"`
+	CreatedAt   time.Time   `db:"created_at"`
+}
+
+// AccountId is the ledger account of the pocket.
+func (p Pocket) AccountId() string {
+	return enum.POCKET_ACCOUNT_PREFIX + p.Id
+}
+
+// IsLocked reports whether money cannot be moved out of the pocket at now.
+func (p Pocket) IsLocked(now time.Time) bool {
+	return p.LockedUntil != nil && now.Before(*p.LockedUntil)
+}
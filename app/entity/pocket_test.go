@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPocket_IsLocked(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Minute)
+
+	type fields struct {
+		LockedUntil *time.Time
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "not locked",
+			fields: fields{
+				LockedUntil: nil,
+			},
+			want: false,
+		},
+		{
+			name: "locked",
+			fields: fields{
+				LockedUntil: &later,
+			},
+			want: true,
+		},
+		{
+			name: "lock passed",
+			fields: fields{
+				LockedUntil: &now,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Pocket{
+				LockedUntil: tt.fields.LockedUntil,
+			}
+			if got := p.IsLocked(now); got != tt.want {
+				t.Errorf("Pocket.IsLocked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// RefreshToken is a single-use refresh token. Only the sha256 hash of the token is stored, and every token
+// issued by rotating another one shares its FamilyId so a reused token can revoke the whole chain.
+type RefreshToken struct {
+	Id        string     `db:"id"`
+	UserId    string     `db:"user_id"`
+	FamilyId  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RotatedAt *time.Time `db:"rotated_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+func (rt RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(rt.ExpiresAt)
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshToken_IsExpired(t *testing.T) {
+	now := time.Now()
+
+	type fields struct {
+		ExpiresAt time.Time
+	}
+	type args struct {
+		now time.Time
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   bool
+	}{
+		{
+			name: "not expired",
+			fields: fields{
+				ExpiresAt: now.Add(time.Minute),
+			},
+			args: args{
+				now: now,
+			},
+			want: false,
+		},
+		{
+			name: "expired",
+			fields: fields{
+				ExpiresAt: now,
+			},
+			args: args{
+				now: now,
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := RefreshToken{
+				ExpiresAt: tt.fields.ExpiresAt,
+			}
+			if got := rt.IsExpired(tt.args.now); got != tt.want {
+				t.Errorf("RefreshToken.IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// ScheduledTransfer is a transfer of UserId to ToUserId run at ScheduledAt, and again on every recurrence.
+// NextRunAt is later than ScheduledAt while a failed occurrence waits to be retried.
+type ScheduledTransfer struct {
+	Id            string      `db:"id"`
+	UserId        string      `db:"user_id"`
+	ToUserId      string      `db:"to_user_id"`
+	Amount        money.Money `db:"amount"`
+	Recurrence    int         `db:"recurrence"`
+	DayOfMonth    int         `db:"day_of_month"`
+	FailurePolicy int         `db:"failure_policy"`
+	Status        int         `db:"status"`
+	ScheduledAt   time.Time   `db:"scheduled_at"`
+	NextRunAt     time.Time   `db:"next_run_at"`
+	Attempt       int         `db:"attempt"`
+	CreatedAt     time.Time   `db:"created_at"`
+}
+
+// NextOccurrence returns the first occurrence after both ScheduledAt and now, so occurrences missed while
+// nothing was running are skipped. It returns the zero time for a one-off transfer. Occurrences keep the
+// wall clock of ScheduledAt in location, and a monthly transfer on a day the month does not have runs on
+// its last day.
+func (st ScheduledTransfer) NextOccurrence(now time.Time, location *time.Location) time.Time {
+	if st.Recurrence == int(enum.RECURRENCE_ONCE) {
+		return time.Time{}
+	}
+
+	next := st.ScheduledAt.In(location)
+	for months := 1; !next.After(now) || next.Equal(st.ScheduledAt); months++ {
+		switch st.Recurrence {
+		case int(enum.RECURRENCE_DAILY):
+			next = next.AddDate(0, 0, 1)
+		case int(enum.RECURRENCE_WEEKLY):
+			next = next.AddDate(0, 0, 7)
+		case int(enum.RECURRENCE_MONTHLY):
+			// Months are counted from ScheduledAt, so a day clamped in a short month is not carried over.
+			start := st.ScheduledAt.In(location)
+			next = DayOfMonth(start.Year(), start.Month()+time.Month(months), st.DayOfMonth, start, location)
+		default:
+			return time.Time{}
+		}
+	}
+
+	return next
+}
+
+// DayOfMonth returns day of the month at the wall clock of clock, or the last day of a shorter month.
+func DayOfMonth(year int, month time.Month, day int, clock time.Time, location *time.Location) time.Time {
+	// Day 0 of the next month is the last day of this one.
+	if last := time.Date(year, month+1, 0, 0, 0, 0, 0, location).Day(); day > last {
+		day = last
+	}
+
+	return time.Date(year, month, day, clock.Hour(), clock.Minute(), clock.Second(), 0, location)
+}
+
+// ScheduledTransferRun is one execution of a scheduled transfer for the occurrence at ScheduledAt.
+type ScheduledTransferRun struct {
+	Id                  string    `db:"id"`
+	ScheduledTransferId string    `db:"scheduled_transfer_id"`
+	ScheduledAt         time.Time `db:"scheduled_at"`
+	Attempt             int       `db:"attempt"`
+	Status              int       `db:"status"`
+	ErrorCode           string    `db:"error_code"`
+	CreatedAt           time.Time `db:"created_at"`
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+func TestScheduledTransfer_NextOccurrence(t *testing.T) {
+	jakarta := time.FixedZone("Asia/Jakarta", 7*60*60)
+
+	type fields struct {
+		Recurrence  int
+		DayOfMonth  int
+		ScheduledAt time.Time
+	}
+	type args struct {
+		now      time.Time
+		location *time.Location
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   time.Time
+	}{
+		{
+			name: "once",
+			fields: fields{
+				Recurrence:  int(enum.RECURRENCE_ONCE),
+				ScheduledAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+			},
+			args: args{
+				now:      time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: time.Time{},
+		},
+		{
+			name: "daily",
+			fields: fields{
+				Recurrence:  int(enum.RECURRENCE_DAILY),
+				ScheduledAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+			},
+			args: args{
+				now:      time.Date(2026, 10, 18, 9, 0, 1, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "daily skips missed occurrences",
+			fields: fields{
+				Recurrence:  int(enum.RECURRENCE_DAILY),
+				ScheduledAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+			},
+			args: args{
+				now:      time.Date(2026, 10, 21, 10, 0, 0, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: time.Date(2026, 10, 22, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekly before the occurrence",
+			fields: fields{
+				Recurrence:  int(enum.RECURRENCE_WEEKLY),
+				ScheduledAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+			},
+			args: args{
+				now:      time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: time.Date(2026, 10, 25, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly on the last day of a shorter month",
+			fields: fields{
+				Recurrence:  int(enum.RECURRENCE_MONTHLY),
+				DayOfMonth:  31,
+				ScheduledAt: time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC),
+			},
+			args: args{
+				now:      time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: time.Date(2027, 2, 28, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly returns to its day after a shorter month",
+			fields: fields{
+				Recurrence:  int(enum.RECURRENCE_MONTHLY),
+				DayOfMonth:  31,
+				ScheduledAt: time.Date(2027, 1, 31, 9, 0, 0, 0, time.UTC),
+			},
+			args: args{
+				now:      time.Date(2027, 3, 1, 9, 0, 0, 0, time.UTC),
+				location: time.UTC,
+			},
+			want: time.Date(2027, 3, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly keeps the wall clock of location",
+			fields: fields{
+				Recurrence:  int(enum.RECURRENCE_MONTHLY),
+				DayOfMonth:  1,
+				ScheduledAt: time.Date(2026, 10, 31, 17, 0, 0, 0, time.UTC),
+			},
+			args: args{
+				now:      time.Date(2026, 10, 31, 17, 0, 0, 0, time.UTC),
+				location: jakarta,
+			},
+			want: time.Date(2026, 12, 1, 0, 0, 0, 0, jakarta),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := ScheduledTransfer{
+				Recurrence:  tt.fields.Recurrence,
+				DayOfMonth:  tt.fields.DayOfMonth,
+				ScheduledAt: tt.fields.ScheduledAt,
+			}
+			if got := st.NextOccurrence(tt.args.now, tt.args.location); !got.Equal(tt.want) {
+				t.Errorf("ScheduledTransfer.NextOccurrence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
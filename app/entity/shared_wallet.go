@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// SharedWallet is a wallet owned by all of its active members, see SharedWalletMember.
+type SharedWallet struct {
+	Id        string      `db:"id"`
+	Name      string      `db:"name"`
+	Amount    money.Money `db:"amount"`
+	Currency  string      `db:"currency"`
+	CreatedBy string      `db:"created_by"`
+	CreatedAt time.Time   `db:"created_at"`
+}
+
+// AccountId is the ledger account of the shared wallet.
+func (w SharedWallet) AccountId() string {
+	return enum.SHARED_WALLET_ACCOUNT_PREFIX + w.Id
+}
+
+// SharedWalletMember is the membership of UserId in a shared wallet. SpendLimit caps every transaction of a
+// spender and is 0 for the other roles.
+type SharedWalletMember struct {
+	WalletId   string      `db:"wallet_id"`
+	UserId     string      `db:"user_id"`
+	Role       int         `db:"role"`
+	SpendLimit money.Money `db:"spend_limit"`
+	Status     int         `db:"status"`
+	InvitedBy  string      `db:"invited_by"`
+	CreatedAt  time.Time   `db:"created_at"`
+}
+
+func (m SharedWalletMember) IsActive() bool {
+	return m.Status == int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE)
+}
+
+// IsPending reports whether the member was invited or already joined, which is when they cannot be invited again.
+func (m SharedWalletMember) IsPending() bool {
+	return m.Status == int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED) || m.IsActive()
+}
+
+func (m SharedWalletMember) IsOwner() bool {
+	return m.IsActive() && m.Role == int(enum.SHARED_WALLET_ROLE_OWNER)
+}
+
+// CanSpend reports whether the member can move money out of the wallet at all, regardless of the amount.
+func (m SharedWalletMember) CanSpend() bool {
+	return m.IsOwner() || (m.IsActive() && m.Role == int(enum.SHARED_WALLET_ROLE_SPENDER))
+}
+
+// SharedWalletHistory is a movement of the money of a shared wallet, with the member who initiated it.
+// TargetUserId is the recipient of a transfer, or the member itself for a deposit.
+type SharedWalletHistory struct {
+	Id             string      `db:"id"`
+	JournalEntryId string      `db:"journal_entry_id"`
+	WalletId       string      `db:"wallet_id"`
+	InitiatedBy    string      `db:"initiated_by"`
+	TargetUserId   string      `db:"target_user_id"`
+	Amount         money.Money `db:"amount"`
+	Type           int         `db:"type"`
+	Notes          string      `db:"notes"`
+	CreatedAt      time.Time   `db:"created_at"`
+}
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+func TestSharedWalletMember_CanSpend(t *testing.T) {
+	type fields struct {
+		Role   int
+		Status int
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "active owner",
+			fields: fields{
+				Role:   int(enum.SHARED_WALLET_ROLE_OWNER),
+				Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE),
+			},
+			want: true,
+		},
+		{
+			name: "active spender",
+			fields: fields{
+				Role:   int(enum.SHARED_WALLET_ROLE_SPENDER),
+				Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE),
+			},
+			want: true,
+		},
+		{
+			name: "active viewer",
+			fields: fields{
+				Role:   int(enum.SHARED_WALLET_ROLE_VIEWER),
+				Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE),
+			},
+			want: false,
+		},
+		{
+			name: "invited owner",
+			fields: fields{
+				Role:   int(enum.SHARED_WALLET_ROLE_OWNER),
+				Status: int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED),
+			},
+			want: false,
+		},
+		{
+			name: "spender who left",
+			fields: fields{
+				Role:   int(enum.SHARED_WALLET_ROLE_SPENDER),
+				Status: int(enum.SHARED_WALLET_MEMBER_STATUS_LEFT),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := SharedWalletMember{
+				Role:   tt.fields.Role,
+				Status: tt.fields.Status,
+			}
+			if got := m.CanSpend(); got != tt.want {
+				t.Errorf("SharedWalletMember.CanSpend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
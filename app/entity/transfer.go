@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// Transfer is a transfer between two users, identified by its journal entry, with the amount refunded so far.
+type Transfer struct {
+	Id             string      `db:"id"`
+	FromUserId     string      `db:"from_user_id"`
+	ToUserId       string      `db:"to_user_id"`
+	Amount         money.Money `db:"amount"`
+	RefundedAmount money.Money `db:"refunded_amount"`
+	CreatedAt      time.Time   `db:"created_at"`
+}
+
+func (t Transfer) RefundableAmount() money.Money {
+	return t.Amount - t.RefundedAmount
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+func TestTransfer_RefundableAmount(t *testing.T) {
+	type fields struct {
+		Amount         money.Money
+		RefundedAmount money.Money
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   money.Money
+	}{
+		{
+			name: "not refunded",
+			fields: fields{
+				Amount: 100,
+			},
+			want: 100,
+		},
+		{
+			name: "partially refunded",
+			fields: fields{
+				Amount:         100,
+				RefundedAmount: 40,
+			},
+			want: 60,
+		},
+		{
+			name: "fully refunded",
+			fields: fields{
+				Amount:         100,
+				RefundedAmount: 100,
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := Transfer{
+				Amount:         tt.fields.Amount,
+				RefundedAmount: tt.fields.RefundedAmount,
+			}
+			if got := tr.RefundableAmount(); got != tt.want {
+				t.Errorf("Transfer.RefundableAmount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
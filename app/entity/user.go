@@ -1,6 +1,48 @@
 package entity
 
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
 type User struct {
-	Id       string `db:"id"`
-	Username string `db:"username"`
+	Id              string     `db:"id"`
+	Username        string     `db:"username"`
+	Tier            int        `db:"tier"`
+	Role            int        `db:"role"`
+	Status          int        `db:"status"`
+	StatusReason    string     `db:"status_reason"`
+	StatusChangedAt *time.Time `db:"status_changed_at"`
+	Currency        string     `db:"currency"`
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == int(enum.USER_ROLE_ADMIN)
+}
+
+func (u User) IsClosed() bool {
+	return u.Status == int(enum.USER_STATUS_CLOSED)
+}
+
+// CanDebit reports whether money can leave the account of the user.
+func (u User) CanDebit() bool {
+	return u.Status == int(enum.USER_STATUS_ACTIVE)
+}
+
+// CanCredit reports whether money can enter the account of the user.
+func (u User) CanCredit() bool {
+	return u.Status == int(enum.USER_STATUS_ACTIVE) || u.Status == int(enum.USER_STATUS_DEBIT_BLOCKED)
+}
+
+// UserStatusChange is the audit record of a status change of UserId, made by ActorId, which is the user itself
+// when they closed their own account.
+type UserStatusChange struct {
+	Id         string    `db:"id"`
+	UserId     string    `db:"user_id"`
+	FromStatus int       `db:"from_status"`
+	ToStatus   int       `db:"to_status"`
+	Reason     string    `db:"reason"`
+	ActorId    string    `db:"actor_id"`
+	CreatedAt  time.Time `db:"created_at"`
 }
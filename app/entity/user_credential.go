@@ -0,0 +1,6 @@
+package entity
+
+type UserCredential struct {
+	UserId       string `db:"user_id"`
+	PasswordHash string `db:"password_hash"`
+}
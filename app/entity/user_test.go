@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/kevinsudut/wallet-system/app/enum"
+)
+
+func TestUser_CanDebit(t *testing.T) {
+	tests := []struct {
+		name   string
+		status enum.UserStatus
+		want   bool
+	}{
+		{
+			name:   "active",
+			status: enum.USER_STATUS_ACTIVE,
+			want:   true,
+		},
+		{
+			name:   "frozen",
+			status: enum.USER_STATUS_FROZEN,
+			want:   false,
+		},
+		{
+			name:   "debit blocked",
+			status: enum.USER_STATUS_DEBIT_BLOCKED,
+			want:   false,
+		},
+		{
+			name:   "closed",
+			status: enum.USER_STATUS_CLOSED,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := User{
+				Status: int(tt.status),
+			}
+			if got := u.CanDebit(); got != tt.want {
+				t.Errorf("User.CanDebit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUser_CanCredit(t *testing.T) {
+	tests := []struct {
+		name   string
+		status enum.UserStatus
+		want   bool
+	}{
+		{
+			name:   "active",
+			status: enum.USER_STATUS_ACTIVE,
+			want:   true,
+		},
+		{
+			name:   "frozen",
+			status: enum.USER_STATUS_FROZEN,
+			want:   false,
+		},
+		{
+			name:   "debit blocked",
+			status: enum.USER_STATUS_DEBIT_BLOCKED,
+			want:   true,
+		},
+		{
+			name:   "closed",
+			status: enum.USER_STATUS_CLOSED,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := User{
+				Status: int(tt.status),
+			}
+			if got := u.CanCredit(); got != tt.want {
+				t.Errorf("User.CanCredit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
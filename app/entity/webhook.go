@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// WebhookEndpoint is a URL of UserId that is sent the events of the types in EventTypes, a comma separated list.
+// Secret signs every delivery to it.
+type WebhookEndpoint struct {
+	Id         string    `db:"id"`
+	UserId     string    `db:"user_id"`
+	Url        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes string    `db:"event_types"`
+	Status     int       `db:"status"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// EventTypeList returns the event types the endpoint is subscribed to.
+func (we WebhookEndpoint) EventTypeList() []string {
+	if we.EventTypes == "" {
+		return []string{}
+	}
+
+	return strings.Split(we.EventTypes, ",")
+}
+
+// WebhookDelivery is an event sent to an endpoint. Payload is the body sent on every attempt, and Attempt counts
+// the attempts since the delivery was created or last redelivered. LastStatusCode is 0 when the last attempt got
+// no response.
+type WebhookDelivery struct {
+	Id             string     `db:"id"`
+	EndpointId     string     `db:"endpoint_id"`
+	EventId        string     `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        string     `db:"payload"`
+	Status         int        `db:"status"`
+	Attempt        int        `db:"attempt"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastStatusCode int        `db:"last_status_code"`
+	LastError      string     `db:"last_error"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// WebhookPayload is the body of a delivery, Data is the payload of the event.
+type WebhookPayload struct {
+	Id         string              `json:"id"`
+	Type       string              `json:"type"`
+	Version    int                 `json:"version"`
+	OccurredAt time.Time           `json:"occurred_at"`
+	Data       jsoniter.RawMessage `json:"data"`
+}
+
+// NewWebhookPayload wraps the payload of event for a delivery.
+func NewWebhookPayload(event OutboxEvent) WebhookPayload {
+	return WebhookPayload{
+		Id:         event.EventId,
+		Type:       event.Type,
+		Version:    event.Version,
+		OccurredAt: event.CreatedAt.UTC(),
+		Data:       jsoniter.RawMessage(event.Payload),
+	}
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWebhookEndpoint_EventTypeList(t *testing.T) {
+	type fields struct {
+		EventTypes string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   []string
+	}{
+		{
+			name: "one",
+			fields: fields{
+				EventTypes: "BalanceCredited",
+			},
+			want: []string{"BalanceCredited"},
+		},
+		{
+			name: "many",
+			fields: fields{
+				EventTypes: "BalanceCredited,TransferCompleted",
+			},
+			want: []string{"BalanceCredited", "TransferCompleted"},
+		},
+		{
+			name: "none",
+			fields: fields{
+				EventTypes: "",
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			we := WebhookEndpoint{
+				EventTypes: tt.fields.EventTypes,
+			}
+			if got := we.EventTypeList(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WebhookEndpoint.EventTypeList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,436 @@ package enum
 type HistoryType int
 
 var (
-	CREDIT HistoryType = 1
-	DEBIT  HistoryType = 2
+	CREDIT        HistoryType = 1
+	DEBIT         HistoryType = 2
+	FEE           HistoryType = 3
+	POCKET        HistoryType = 4
+	SHARED_WALLET HistoryType = 5
+	CONVERSION    HistoryType = 6
+)
+
+func (t HistoryType) String() string {
+	switch t {
+	case CREDIT:
+		return "credit"
+	case DEBIT:
+		return "debit"
+	case FEE:
+		return "fee"
+	case POCKET:
+		return "pocket"
+	case SHARED_WALLET:
+		return "shared_wallet"
+	case CONVERSION:
+		return "conversion"
+	}
+
+	return ""
+}
+
+type JournalEntryType int
+
+var (
+	TOPUP                 JournalEntryType = 1
+	TRANSFER              JournalEntryType = 2
+	REFUND                JournalEntryType = 3
+	FEE_CHARGE            JournalEntryType = 4
+	POCKET_MOVE           JournalEntryType = 5
+	SHARED_WALLET_DEPOSIT JournalEntryType = 6
+	FX_CONVERSION         JournalEntryType = 7
+	// OPENING_BALANCE entries are only written by database.sql, to put the balances kept before the ledger on it.
+	OPENING_BALANCE JournalEntryType = 8
+)
+
+func (t JournalEntryType) String() string {
+	switch t {
+	case TOPUP:
+		return "topup"
+	case TRANSFER:
+		return "transfer"
+	case REFUND:
+		return "refund"
+	case FEE_CHARGE:
+		return "fee_charge"
+	case POCKET_MOVE:
+		return "pocket_move"
+	case SHARED_WALLET_DEPOSIT:
+		return "shared_wallet_deposit"
+	case FX_CONVERSION:
+		return "fx_conversion"
+	case OPENING_BALANCE:
+		return "opening_balance"
+	}
+
+	return ""
+}
+
+type HoldStatus int
+
+var (
+	HOLD_STATUS_ACTIVE   HoldStatus = 1
+	HOLD_STATUS_CAPTURED HoldStatus = 2
+	HOLD_STATUS_VOIDED   HoldStatus = 3
+	HOLD_STATUS_EXPIRED  HoldStatus = 4
+)
+
+func (s HoldStatus) String() string {
+	switch s {
+	case HOLD_STATUS_ACTIVE:
+		return "active"
+	case HOLD_STATUS_CAPTURED:
+		return "captured"
+	case HOLD_STATUS_VOIDED:
+		return "voided"
+	case HOLD_STATUS_EXPIRED:
+		return "expired"
+	}
+
+	return ""
+}
+
+type Recurrence int
+
+var (
+	RECURRENCE_ONCE    Recurrence = 1
+	RECURRENCE_DAILY   Recurrence = 2
+	RECURRENCE_WEEKLY  Recurrence = 3
+	RECURRENCE_MONTHLY Recurrence = 4
+)
+
+func (r Recurrence) String() string {
+	switch r {
+	case RECURRENCE_ONCE:
+		return "once"
+	case RECURRENCE_DAILY:
+		return "daily"
+	case RECURRENCE_WEEKLY:
+		return "weekly"
+	case RECURRENCE_MONTHLY:
+		return "monthly"
+	}
+
+	return ""
+}
+
+type FailurePolicy int
+
+var (
+	FAILURE_POLICY_RETRY FailurePolicy = 1
+	FAILURE_POLICY_SKIP  FailurePolicy = 2
+)
+
+func (p FailurePolicy) String() string {
+	switch p {
+	case FAILURE_POLICY_RETRY:
+		return "retry"
+	case FAILURE_POLICY_SKIP:
+		return "skip"
+	}
+
+	return ""
+}
+
+type ScheduledTransferStatus int
+
+var (
+	SCHEDULED_TRANSFER_STATUS_ACTIVE    ScheduledTransferStatus = 1
+	SCHEDULED_TRANSFER_STATUS_COMPLETED ScheduledTransferStatus = 2
+	SCHEDULED_TRANSFER_STATUS_CANCELED  ScheduledTransferStatus = 3
+	SCHEDULED_TRANSFER_STATUS_FAILED    ScheduledTransferStatus = 4
+)
+
+func (s ScheduledTransferStatus) String() string {
+	switch s {
+	case SCHEDULED_TRANSFER_STATUS_ACTIVE:
+		return "active"
+	case SCHEDULED_TRANSFER_STATUS_COMPLETED:
+		return "completed"
+	case SCHEDULED_TRANSFER_STATUS_CANCELED:
+		return "canceled"
+	case SCHEDULED_TRANSFER_STATUS_FAILED:
+		return "failed"
+	}
+
+	return ""
+}
+
+type ScheduledTransferRunStatus int
+
+var (
+	SCHEDULED_TRANSFER_RUN_STATUS_SUCCEEDED ScheduledTransferRunStatus = 1
+	SCHEDULED_TRANSFER_RUN_STATUS_FAILED    ScheduledTransferRunStatus = 2
+)
+
+func (s ScheduledTransferRunStatus) String() string {
+	switch s {
+	case SCHEDULED_TRANSFER_RUN_STATUS_SUCCEEDED:
+		return "succeeded"
+	case SCHEDULED_TRANSFER_RUN_STATUS_FAILED:
+		return "failed"
+	}
+
+	return ""
+}
+
+// A payment request is accepting while the transfer paying it runs. Expired is never stored, a pending request
+// is expired once its expiry has passed.
+type PaymentRequestStatus int
+
+var (
+	PAYMENT_REQUEST_STATUS_PENDING   PaymentRequestStatus = 1
+	PAYMENT_REQUEST_STATUS_ACCEPTING PaymentRequestStatus = 2
+	PAYMENT_REQUEST_STATUS_ACCEPTED  PaymentRequestStatus = 3
+	PAYMENT_REQUEST_STATUS_DECLINED  PaymentRequestStatus = 4
+	PAYMENT_REQUEST_STATUS_CANCELED  PaymentRequestStatus = 5
+	PAYMENT_REQUEST_STATUS_EXPIRED   PaymentRequestStatus = 6
+)
+
+func (s PaymentRequestStatus) String() string {
+	switch s {
+	case PAYMENT_REQUEST_STATUS_PENDING:
+		return "pending"
+	case PAYMENT_REQUEST_STATUS_ACCEPTING:
+		return "accepting"
+	case PAYMENT_REQUEST_STATUS_ACCEPTED:
+		return "accepted"
+	case PAYMENT_REQUEST_STATUS_DECLINED:
+		return "declined"
+	case PAYMENT_REQUEST_STATUS_CANCELED:
+		return "canceled"
+	case PAYMENT_REQUEST_STATUS_EXPIRED:
+		return "expired"
+	}
+
+	return ""
+}
+
+type UserTier int
+
+var (
+	USER_TIER_BASIC    UserTier = 1
+	USER_TIER_VERIFIED UserTier = 2
+	USER_TIER_PREMIUM  UserTier = 3
+)
+
+func (t UserTier) String() string {
+	switch t {
+	case USER_TIER_BASIC:
+		return "basic"
+	case USER_TIER_VERIFIED:
+		return "verified"
+	case USER_TIER_PREMIUM:
+		return "premium"
+	}
+
+	return ""
+}
+
+// A frozen user can neither send nor receive money, a debit-blocked user can still receive it. Closed is final.
+type UserStatus int
+
+var (
+	USER_STATUS_ACTIVE        UserStatus = 1
+	USER_STATUS_FROZEN        UserStatus = 2
+	USER_STATUS_DEBIT_BLOCKED UserStatus = 3
+	USER_STATUS_CLOSED        UserStatus = 4
+)
+
+func (s UserStatus) String() string {
+	switch s {
+	case USER_STATUS_ACTIVE:
+		return "active"
+	case USER_STATUS_FROZEN:
+		return "frozen"
+	case USER_STATUS_DEBIT_BLOCKED:
+		return "debit_blocked"
+	case USER_STATUS_CLOSED:
+		return "closed"
+	}
+
+	return ""
+}
+
+type UserRole int
+
+var (
+	USER_ROLE_USER  UserRole = 1
+	USER_ROLE_ADMIN UserRole = 2
+)
+
+type LimitOperation int
+
+var (
+	LIMIT_OPERATION_TOPUP    LimitOperation = 1
+	LIMIT_OPERATION_TRANSFER LimitOperation = 2
+)
+
+func (o LimitOperation) String() string {
+	switch o {
+	case LIMIT_OPERATION_TOPUP:
+		return "topup"
+	case LIMIT_OPERATION_TRANSFER:
+		return "transfer"
+	}
+
+	return ""
+}
+
+// Day and month limits follow the calendar of the business time zone.
+type LimitPeriod string
+
+var (
+	LIMIT_PERIOD_TRANSACTION LimitPeriod = "transaction"
+	LIMIT_PERIOD_DAY         LimitPeriod = "day"
+	LIMIT_PERIOD_MONTH       LimitPeriod = "month"
+)
+
+type LeaderboardDirection string
+
+var (
+	LEADERBOARD_SENT     LeaderboardDirection = "sent"
+	LEADERBOARD_RECEIVED LeaderboardDirection = "received"
+	LEADERBOARD_ALL      LeaderboardDirection = "all"
+)
+
+type SummaryPeriod string
+
+var (
+	SUMMARY_PERIOD_DAY   SummaryPeriod = "day"
+	SUMMARY_PERIOD_WEEK  SummaryPeriod = "week"
+	SUMMARY_PERIOD_MONTH SummaryPeriod = "month"
+)
+
+// System accounts are ledger accounts that are not owned by a user, so they have no row in balances.
+const (
+	SYSTEM_ACCOUNT_PREFIX = "system:"
+	ACCOUNT_FUNDING       = SYSTEM_ACCOUNT_PREFIX + "funding"
+	ACCOUNT_REVENUE       = SYSTEM_ACCOUNT_PREFIX + "revenue"
+
+	// FX accounts take money in one currency and pay it out in another, one account per currency so the
+	// postings of every currency of a conversion sum to zero on their own.
+	FX_ACCOUNT_PREFIX = SYSTEM_ACCOUNT_PREFIX + "fx:"
+)
+
+// Pocket accounts are ledger accounts of the pockets of a user, whose balance is kept in pockets instead.
+const POCKET_ACCOUNT_PREFIX = "pocket:"
+
+// Shared wallet accounts are ledger accounts of wallets owned by several users, whose balance is kept in
+// shared_wallets.
+const SHARED_WALLET_ACCOUNT_PREFIX = "wallet:"
+
+// Currency accounts are ledger accounts of the balances of a user in currencies other than its main one, named
+// currency:<user id>:<currency> and kept in currency_balances.
+const CURRENCY_ACCOUNT_PREFIX = "currency:"
+
+// Owners manage the members of a shared wallet, spenders can move its money up to their spend limit per
+// transaction, and viewers can only read it.
+type SharedWalletRole int
+
+var (
+	SHARED_WALLET_ROLE_OWNER   SharedWalletRole = 1
+	SHARED_WALLET_ROLE_SPENDER SharedWalletRole = 2
+	SHARED_WALLET_ROLE_VIEWER  SharedWalletRole = 3
+)
+
+func (r SharedWalletRole) String() string {
+	switch r {
+	case SHARED_WALLET_ROLE_OWNER:
+		return "owner"
+	case SHARED_WALLET_ROLE_SPENDER:
+		return "spender"
+	case SHARED_WALLET_ROLE_VIEWER:
+		return "viewer"
+	}
+
+	return ""
+}
+
+// A member is invited until they accept or decline, and only an active member can use the wallet.
+type SharedWalletMemberStatus int
+
+var (
+	SHARED_WALLET_MEMBER_STATUS_INVITED  SharedWalletMemberStatus = 1
+	SHARED_WALLET_MEMBER_STATUS_ACTIVE   SharedWalletMemberStatus = 2
+	SHARED_WALLET_MEMBER_STATUS_DECLINED SharedWalletMemberStatus = 3
+	SHARED_WALLET_MEMBER_STATUS_LEFT     SharedWalletMemberStatus = 4
+	SHARED_WALLET_MEMBER_STATUS_REMOVED  SharedWalletMemberStatus = 5
+)
+
+func (s SharedWalletMemberStatus) String() string {
+	switch s {
+	case SHARED_WALLET_MEMBER_STATUS_INVITED:
+		return "invited"
+	case SHARED_WALLET_MEMBER_STATUS_ACTIVE:
+		return "active"
+	case SHARED_WALLET_MEMBER_STATUS_DECLINED:
+		return "declined"
+	case SHARED_WALLET_MEMBER_STATUS_LEFT:
+		return "left"
+	case SHARED_WALLET_MEMBER_STATUS_REMOVED:
+		return "removed"
+	}
+
+	return ""
+}
+
+// Events are written to the outbox with the change they describe and relayed to the event stream. A payload only
+// changes in a way a consumer can break on together with EVENT_VERSION, which every event carries.
+type EventType string
+
+var (
+	EVENT_BALANCE_CREDITED   EventType = "BalanceCredited"
+	EVENT_BALANCE_DEBITED    EventType = "BalanceDebited"
+	EVENT_TRANSFER_COMPLETED EventType = "TransferCompleted"
+	EVENT_USER_REGISTERED    EventType = "UserRegistered"
+)
+
+const EVENT_VERSION = 1
+
+type WebhookEndpointStatus int
+
+var (
+	WEBHOOK_ENDPOINT_STATUS_ACTIVE  WebhookEndpointStatus = 1
+	WEBHOOK_ENDPOINT_STATUS_DELETED WebhookEndpointStatus = 2
+)
+
+func (s WebhookEndpointStatus) String() string {
+	switch s {
+	case WEBHOOK_ENDPOINT_STATUS_ACTIVE:
+		return "active"
+	case WEBHOOK_ENDPOINT_STATUS_DELETED:
+		return "deleted"
+	}
+
+	return ""
+}
+
+// A delivery is pending until its endpoint accepts it, or until it has used up its attempts and is dead.
+type WebhookDeliveryStatus int
+
+var (
+	WEBHOOK_DELIVERY_STATUS_PENDING   WebhookDeliveryStatus = 1
+	WEBHOOK_DELIVERY_STATUS_DELIVERED WebhookDeliveryStatus = 2
+	WEBHOOK_DELIVERY_STATUS_DEAD      WebhookDeliveryStatus = 3
+)
+
+func (s WebhookDeliveryStatus) String() string {
+	switch s {
+	case WEBHOOK_DELIVERY_STATUS_PENDING:
+		return "pending"
+	case WEBHOOK_DELIVERY_STATUS_DELIVERED:
+		return "delivered"
+	case WEBHOOK_DELIVERY_STATUS_DEAD:
+		return "dead"
+	}
+
+	return ""
+}
+
+// Notifications are pushed to the connected clients of a user as soon as the change they describe is committed.
+type NotificationType string
+
+var (
+	NOTIFICATION_BALANCE_CHANGED     NotificationType = "balance.changed"
+	NOTIFICATION_TRANSACTION_CREATED NotificationType = "transaction.created"
 )
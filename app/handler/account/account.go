@@ -0,0 +1,132 @@
+package handleraccount
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	jsoniter "github.com/json-iterator/go"
+	usecaseaccount "github.com/kevinsudut/wallet-system/app/usecase/account"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/response"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+// readCloseAccountRequest reads the optional body of a closure, an empty one closes without a sweep or a reason.
+func readCloseAccountRequest(r *http.Request) (req usecaseaccount.CloseAccountRequest, err error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return req, err
+	}
+
+	if len(body) == 0 {
+		return req, nil
+	}
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func (h handler) ReadAccount(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ReadAccount(r.Context(), usecaseaccount.ReadAccountRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ReadAccount.ReadAccount", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) CloseAccount(w http.ResponseWriter, r *http.Request) {
+	req, err := readCloseAccountRequest(r)
+	if err != nil {
+		log.Errorln("CloseAccount.readCloseAccountRequest", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.ActorId = req.UserId
+
+	resp, err := h.usecase.CloseAccount(r.Context(), req)
+	if err != nil {
+		log.Errorln("CloseAccount.CloseAccount", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) AdminCloseAccount(w http.ResponseWriter, r *http.Request) {
+	req, err := readCloseAccountRequest(r)
+	if err != nil {
+		log.Errorln("AdminCloseAccount.readCloseAccountRequest", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = mux.Vars(r)["id"]
+	req.ActorId = context.GetAuth(r.Context()).Id
+	req.Admin = true
+
+	resp, err := h.usecase.CloseAccount(r.Context(), req)
+	if err != nil {
+		log.Errorln("AdminCloseAccount.CloseAccount", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) UpdateUserStatus(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("UpdateUserStatus.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecaseaccount.UpdateUserStatusRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("UpdateUserStatus.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = mux.Vars(r)["id"]
+	req.ActorId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.UpdateUserStatus(r.Context(), req)
+	if err != nil {
+		log.Errorln("UpdateUserStatus.UpdateUserStatus", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListUserStatusChanges(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListUserStatusChanges(r.Context(), usecaseaccount.ListUserStatusChangesRequest{
+		UserId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("ListUserStatusChanges.ListUserStatusChanges", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
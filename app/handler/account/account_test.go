@@ -0,0 +1,504 @@
+package handleraccount
+
+import (
+	"bytes"
+	ctx "context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecaseaccount "github.com/kevinsudut/wallet-system/app/usecase/account"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMain(t *testing.M) {
+	log.Init()
+	os.Exit(t.Run())
+}
+
+func Test_handler_ReadAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAccount := usecaseaccount.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecaseaccount.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/account", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().ReadAccount(gomock.Any(), usecaseaccount.ReadAccountRequest{
+						UserId: "id",
+					}).Return(usecaseaccount.ReadAccountResponse{
+						Code: http.StatusOK,
+						Account: usecaseaccount.Account{
+							Id:       "id",
+							Username: "username",
+							Status:   "active",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error account.ReadAccount",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/account", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().ReadAccount(gomock.Any(), gomock.Any()).Return(usecaseaccount.ReadAccountResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ReadAccount(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_CloseAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAccount := usecaseaccount.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecaseaccount.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/account/close", bytes.NewBufferString(`{"sweep_to_username":"sweepusername","reason":"moving away"}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().CloseAccount(gomock.Any(), usecaseaccount.CloseAccountRequest{
+						UserId:          "id",
+						ActorId:         "id",
+						SweepToUsername: "sweepusername",
+						Reason:          "moving away",
+					}).Return(usecaseaccount.CloseAccountResponse{
+						Code: http.StatusOK,
+						Account: usecaseaccount.Account{
+							Id:       "id",
+							Username: "username",
+							Status:   "closed",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success without body",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/account/close", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().CloseAccount(gomock.Any(), usecaseaccount.CloseAccountRequest{
+						UserId:  "id",
+						ActorId: "id",
+					}).Return(usecaseaccount.CloseAccountResponse{
+						Code: http.StatusOK,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error account.CloseAccount",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/account/close", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(usecaseaccount.CloseAccountResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/account/close", bytes.NewBufferString(`{"reason":1}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/account/close", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CloseAccount(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_AdminCloseAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAccount := usecaseaccount.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "adminid",
+		Username: "admin",
+	})
+
+	newRequest := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/admin/users/id/close", bytes.NewBufferString(body)).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "id"})
+	}
+
+	type fields struct {
+		usecase usecaseaccount.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"sweep_to_username":"sweepusername","reason":"fraud"}`),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().CloseAccount(gomock.Any(), usecaseaccount.CloseAccountRequest{
+						UserId:          "id",
+						ActorId:         "adminid",
+						Admin:           true,
+						SweepToUsername: "sweepusername",
+						Reason:          "fraud",
+					}).Return(usecaseaccount.CloseAccountResponse{
+						Code: http.StatusOK,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error account.CloseAccount",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"reason":"fraud"}`),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(usecaseaccount.CloseAccountResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"reason":1}`),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.AdminCloseAccount(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_UpdateUserStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAccount := usecaseaccount.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "adminid",
+		Username: "admin",
+	})
+
+	newRequest := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/admin/users/id/status", bytes.NewBufferString(body)).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "id"})
+	}
+
+	type fields struct {
+		usecase usecaseaccount.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"status":"frozen","reason":"compromised"}`),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().UpdateUserStatus(gomock.Any(), usecaseaccount.UpdateUserStatusRequest{
+						UserId:  "id",
+						ActorId: "adminid",
+						Status:  "frozen",
+						Reason:  "compromised",
+					}).Return(usecaseaccount.UpdateUserStatusResponse{
+						Code: http.StatusOK,
+						Account: usecaseaccount.Account{
+							Id:           "id",
+							Username:     "username",
+							Status:       "frozen",
+							StatusReason: "compromised",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error account.UpdateUserStatus",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"status":"closed","reason":"compromised"}`),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().UpdateUserStatus(gomock.Any(), gomock.Any()).Return(usecaseaccount.UpdateUserStatusResponse{
+						Code: http.StatusBadRequest,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(`{"status":1}`),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/admin/users/id/status", handlertemplate.ErrReader{}).WithContext(ctx), map[string]string{"id": "id"}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.UpdateUserStatus(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListUserStatusChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAccount := usecaseaccount.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "adminid",
+		Username: "admin",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/admin/users/id/status_changes", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "id"})
+	}
+
+	type fields struct {
+		usecase usecaseaccount.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().ListUserStatusChanges(gomock.Any(), usecaseaccount.ListUserStatusChangesRequest{
+						UserId: "id",
+					}).Return(usecaseaccount.ListUserStatusChangesResponse{
+						Code: http.StatusOK,
+						Data: []usecaseaccount.UserStatusChange{
+							{
+								Id:         "changeid",
+								FromStatus: "active",
+								ToStatus:   "frozen",
+								Reason:     "compromised",
+								ActorId:    "adminid",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error account.ListUserStatusChanges",
+			fields: fields{
+				usecase: mockUsecaseAccount,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAccount.EXPECT().ListUserStatusChanges(gomock.Any(), gomock.Any()).Return(usecaseaccount.ListUserStatusChangesResponse{
+						Code: http.StatusNotFound,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListUserStatusChanges(tt.args.w, tt.args.r)
+		})
+	}
+}
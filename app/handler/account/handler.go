@@ -0,0 +1,16 @@
+package handleraccount
+
+import (
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecaseaccount "github.com/kevinsudut/wallet-system/app/usecase/account"
+)
+
+type handler struct {
+	usecase usecaseaccount.UsecaseItf
+}
+
+func Init(usecase usecaseaccount.UsecaseItf) handlertemplate.HandlerItf {
+	return &handler{
+		usecase: usecase,
+	}
+}
@@ -0,0 +1,18 @@
+package handleraccount
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
+	router.HandleFunc("/account", h.ReadAccount).Methods(http.MethodGet)
+	router.HandleFunc("/account/close", h.CloseAccount).Methods(http.MethodPost)
+
+	router.HandleFunc("/admin/users/{id}/status", h.UpdateUserStatus).Methods(http.MethodPost)
+	router.HandleFunc("/admin/users/{id}/close", h.AdminCloseAccount).Methods(http.MethodPost)
+	router.HandleFunc("/admin/users/{id}/status_changes", h.ListUserStatusChanges).Methods(http.MethodGet)
+
+	return router
+}
@@ -4,8 +4,11 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/gorilla/mux"
 	jsoniter "github.com/json-iterator/go"
 	usecaseauth "github.com/kevinsudut/wallet-system/app/usecase/auth"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
 	"github.com/kevinsudut/wallet-system/pkg/helper/response"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 )
@@ -14,7 +17,7 @@ func (h handler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		log.Errorln("RegisterUser.ReadAll", err)
-		response.WriteErrorResponse(w, http.StatusBadRequest)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
@@ -23,14 +26,132 @@ func (h handler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	err = jsoniter.Unmarshal(body, &req)
 	if err != nil {
 		log.Errorln("RegisterUser.Unmarshal", err)
-		response.WriteErrorResponse(w, http.StatusBadRequest)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	resp, err := h.usecase.RegisterUser(r.Context(), req)
 	if err != nil {
 		log.Errorln("RegisterUser.RegisterUser", err)
-		response.WriteErrorResponse(w, resp.Code)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) Login(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("Login.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecaseauth.LoginRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("Login.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	resp, err := h.usecase.Login(r.Context(), req)
+	if err != nil {
+		log.Errorln("Login.Login", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) AdminSetUserPassword(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("AdminSetUserPassword.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecaseauth.SetUserPasswordRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("AdminSetUserPassword.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = mux.Vars(r)["id"]
+
+	resp, err := h.usecase.SetUserPassword(r.Context(), req)
+	if err != nil {
+		log.Errorln("AdminSetUserPassword.SetUserPassword", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("RefreshToken.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecaseauth.RefreshTokenRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("RefreshToken.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	resp, err := h.usecase.RefreshToken(r.Context(), req)
+	if err != nil {
+		log.Errorln("RefreshToken.RefreshToken", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) Logout(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("Logout.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecaseauth.LogoutRequest
+
+	// The refresh token is optional, without it only the current access token is revoked.
+	if len(body) > 0 {
+		err = jsoniter.Unmarshal(body, &req)
+		if err != nil {
+			log.Errorln("Logout.Unmarshal", err)
+			response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+			return
+		}
+	}
+
+	claims := context.GetTokenClaims(r.Context())
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.AccessTokenId = claims.Id
+	req.AccessTokenExpires = claims.ExpiresAt
+
+	resp, err := h.usecase.Logout(r.Context(), req)
+	if err != nil {
+		log.Errorln("Logout.Logout", err)
+		response.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -2,15 +2,22 @@ package handlerauth
 
 import (
 	"bytes"
+	ctx "context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/kevinsudut/wallet-system/app/entity"
 	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
 	usecaseauth "github.com/kevinsudut/wallet-system/app/usecase/auth"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/token"
 	"go.uber.org/mock/gomock"
 )
 
@@ -109,3 +116,411 @@ func Test_handler_RegisterUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAuth := usecaseauth.NewMockUsecaseItf(ctrl)
+
+	type fields struct {
+		usecase usecaseauth.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"username":"username","password":"password"}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().Login(gomock.Any(), usecaseauth.LoginRequest{
+						Username: "username",
+						Password: "password",
+					}).Return(usecaseauth.LoginResponse{
+						Code:  http.StatusOK,
+						Token: "token",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.Login",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"username":"username","password":"password"}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().Login(gomock.Any(), usecaseauth.LoginRequest{
+						Username: "username",
+						Password: "password",
+					}).Return(usecaseauth.LoginResponse{
+						Code: http.StatusInternalServerError,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`[]`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/login", handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.Login(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_AdminSetUserPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAuth := usecaseauth.NewMockUsecaseItf(ctrl)
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/admin/users/id/password", body)
+		return mux.SetURLVars(r, map[string]string{"id": "id"})
+	}
+
+	type fields struct {
+		usecase usecaseauth.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"password":"password"}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().SetUserPassword(gomock.Any(), usecaseauth.SetUserPasswordRequest{
+						UserId:   "id",
+						Password: "password",
+					}).Return(usecaseauth.SetUserPasswordResponse{
+						Code: http.StatusCreated,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.SetUserPassword",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"password":"password"}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().SetUserPassword(gomock.Any(), usecaseauth.SetUserPasswordRequest{
+						UserId:   "id",
+						Password: "password",
+					}).Return(usecaseauth.SetUserPasswordResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`[]`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.AdminSetUserPassword(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAuth := usecaseauth.NewMockUsecaseItf(ctrl)
+
+	type fields struct {
+		usecase usecaseauth.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewBufferString(`{"refresh_token":"refresh_token"}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().RefreshToken(gomock.Any(), usecaseauth.RefreshTokenRequest{
+						RefreshToken: "refresh_token",
+					}).Return(usecaseauth.RefreshTokenResponse{
+						Code:         http.StatusOK,
+						Token:        "token",
+						RefreshToken: "new_refresh_token",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.RefreshToken",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewBufferString(`{"refresh_token":"refresh_token"}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().RefreshToken(gomock.Any(), usecaseauth.RefreshTokenRequest{
+						RefreshToken: "refresh_token",
+					}).Return(usecaseauth.RefreshTokenResponse{
+						Code: http.StatusUnauthorized,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewBufferString(`{"refresh_token":1}`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/token/refresh", handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.RefreshToken(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseAuth := usecaseauth.NewMockUsecaseItf(ctrl)
+
+	expiresAt := time.Now().Add(time.Minute)
+
+	ctx := context.SetTokenClaims(context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	}), token.Claims{
+		Id:        "jti",
+		ExpiresAt: expiresAt,
+	})
+
+	type fields struct {
+		usecase usecaseauth.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/logout", bytes.NewBufferString(`{"refresh_token":"refresh_token"}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().Logout(gomock.Any(), usecaseauth.LogoutRequest{
+						UserId:             "id",
+						AccessTokenId:      "jti",
+						AccessTokenExpires: expiresAt,
+						RefreshToken:       "refresh_token",
+					}).Return(usecaseauth.LogoutResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success without body",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/logout", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().Logout(gomock.Any(), usecaseauth.LogoutRequest{
+						UserId:             "id",
+						AccessTokenId:      "jti",
+						AccessTokenExpires: expiresAt,
+					}).Return(usecaseauth.LogoutResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.Logout",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/logout", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseAuth.EXPECT().Logout(gomock.Any(), usecaseauth.LogoutRequest{
+						UserId:             "id",
+						AccessTokenId:      "jti",
+						AccessTokenExpires: expiresAt,
+					}).Return(usecaseauth.LogoutResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/logout", bytes.NewBufferString(`{"refresh_token":1}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseAuth,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/logout", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.Logout(tt.args.w, tt.args.r)
+		})
+	}
+}
@@ -8,6 +8,11 @@ import (
 
 func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
 	router.HandleFunc("/create_user", h.RegisterUser).Methods(http.MethodPost)
+	router.HandleFunc("/login", h.Login).Methods(http.MethodPost)
+	router.HandleFunc("/token/refresh", h.RefreshToken).Methods(http.MethodPost)
+	router.HandleFunc("/logout", h.Logout).Methods(http.MethodPost)
+
+	router.HandleFunc("/admin/users/{id}/password", h.AdminSetUserPassword).Methods(http.MethodPost)
 
 	return router
 }
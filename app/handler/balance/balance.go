@@ -4,20 +4,26 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/gorilla/mux"
 	jsoniter "github.com/json-iterator/go"
 	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
 	"github.com/kevinsudut/wallet-system/pkg/helper/context"
 	"github.com/kevinsudut/wallet-system/pkg/helper/response"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 )
 
+const (
+	headerIdempotencyKey = "Idempotency-Key"
+)
+
 func (h handler) ReadBalance(w http.ResponseWriter, r *http.Request) {
 	resp, err := h.usecase.ReadBalanceByUserId(r.Context(), usecasebalance.ReadBalanceByUserIdRequest{
 		UserId: context.GetAuth(r.Context()).Id,
 	})
 	if err != nil {
 		log.Errorln("ReadBalance.ReadBalanceByUserId", err)
-		response.WriteErrorResponse(w, resp.Code)
+		response.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -28,7 +34,7 @@ func (h handler) TopupBalance(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		log.Errorln("TopupBalance.ReadAll", err)
-		response.WriteErrorResponse(w, http.StatusBadRequest)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
@@ -37,16 +43,17 @@ func (h handler) TopupBalance(w http.ResponseWriter, r *http.Request) {
 	err = jsoniter.Unmarshal(body, &req)
 	if err != nil {
 		log.Errorln("TopupBalance.Unmarshal", err)
-		response.WriteErrorResponse(w, http.StatusBadRequest)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	req.UserId = context.GetAuth(r.Context()).Id
+	req.IdempotencyKey = r.Header.Get(headerIdempotencyKey)
 
 	resp, err := h.usecase.TopupBalance(r.Context(), req)
 	if err != nil {
 		log.Errorln("TopupBalance.TopupBalance", err)
-		response.WriteErrorResponse(w, resp.Code)
+		response.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -57,7 +64,7 @@ func (h handler) TransferBalance(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		log.Errorln("TransferBalance.ReadAll", err)
-		response.WriteErrorResponse(w, http.StatusBadRequest)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
@@ -66,16 +73,332 @@ func (h handler) TransferBalance(w http.ResponseWriter, r *http.Request) {
 	err = jsoniter.Unmarshal(body, &req)
 	if err != nil {
 		log.Errorln("TransferBalance.Unmarshal", err)
-		response.WriteErrorResponse(w, http.StatusBadRequest)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
 		return
 	}
 
 	req.UserId = context.GetAuth(r.Context()).Id
+	req.IdempotencyKey = r.Header.Get(headerIdempotencyKey)
 
 	resp, err := h.usecase.TransferBalance(r.Context(), req)
 	if err != nil {
 		log.Errorln("TransferBalance.TransferBalance", err)
-		response.WriteErrorResponse(w, resp.Code)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) QuoteTransfer(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("QuoteTransfer.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.QuoteTransferRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("QuoteTransfer.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.QuoteTransfer(r.Context(), req)
+	if err != nil {
+		log.Errorln("QuoteTransfer.QuoteTransfer", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) RefundTransfer(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("RefundTransfer.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.RefundTransferRequest
+
+	// The body is optional, without it the rest of the transfer is refunded.
+	if len(body) > 0 {
+		err = jsoniter.Unmarshal(body, &req)
+		if err != nil {
+			log.Errorln("RefundTransfer.Unmarshal", err)
+			response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+			return
+		}
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.IdempotencyKey = r.Header.Get(headerIdempotencyKey)
+	req.TransferId = mux.Vars(r)["id"]
+
+	resp, err := h.usecase.RefundTransfer(r.Context(), req)
+	if err != nil {
+		log.Errorln("RefundTransfer.RefundTransfer", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) CreateHold(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("CreateHold.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.CreateHoldRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("CreateHold.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.IdempotencyKey = r.Header.Get(headerIdempotencyKey)
+
+	resp, err := h.usecase.CreateHold(r.Context(), req)
+	if err != nil {
+		log.Errorln("CreateHold.CreateHold", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("CaptureHold.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.CaptureHoldRequest
+
+	// The body is optional, without it the whole hold is captured.
+	if len(body) > 0 {
+		err = jsoniter.Unmarshal(body, &req)
+		if err != nil {
+			log.Errorln("CaptureHold.Unmarshal", err)
+			response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+			return
+		}
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.HoldId = mux.Vars(r)["id"]
+
+	resp, err := h.usecase.CaptureHold(r.Context(), req)
+	if err != nil {
+		log.Errorln("CaptureHold.CaptureHold", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) VoidHold(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.VoidHold(r.Context(), usecasebalance.VoidHoldRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+		HoldId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("VoidHold.VoidHold", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) CreatePocket(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("CreatePocket.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.CreatePocketRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("CreatePocket.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.CreatePocket(r.Context(), req)
+	if err != nil {
+		log.Errorln("CreatePocket.CreatePocket", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListPockets(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListPockets(r.Context(), usecasebalance.ListPocketsRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ListPockets.ListPockets", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) MovePocketBalance(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("MovePocketBalance.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.MovePocketBalanceRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("MovePocketBalance.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.IdempotencyKey = r.Header.Get(headerIdempotencyKey)
+
+	resp, err := h.usecase.MovePocketBalance(r.Context(), req)
+	if err != nil {
+		log.Errorln("MovePocketBalance.MovePocketBalance", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) DepositSharedWallet(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("DepositSharedWallet.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.DepositSharedWalletRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("DepositSharedWallet.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.IdempotencyKey = r.Header.Get(headerIdempotencyKey)
+	req.WalletId = mux.Vars(r)["id"]
+
+	resp, err := h.usecase.DepositSharedWallet(r.Context(), req)
+	if err != nil {
+		log.Errorln("DepositSharedWallet.DepositSharedWallet", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListBalances(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListBalances(r.Context(), usecasebalance.ListBalancesRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ListBalances.ListBalances", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) QuoteConversion(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("QuoteConversion.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.QuoteConversionRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("QuoteConversion.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.QuoteConversion(r.Context(), req)
+	if err != nil {
+		log.Errorln("QuoteConversion.QuoteConversion", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ConvertBalance(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("ConvertBalance.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasebalance.ConvertBalanceRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("ConvertBalance.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.IdempotencyKey = r.Header.Get(headerIdempotencyKey)
+
+	resp, err := h.usecase.ConvertBalance(r.Context(), req)
+	if err != nil {
+		log.Errorln("ConvertBalance.ConvertBalance", err)
+		response.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -4,15 +4,18 @@ import (
 	"bytes"
 	ctx "context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/gorilla/mux"
 	"github.com/kevinsudut/wallet-system/app/entity"
 	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
 	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
 	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 	"go.uber.org/mock/gomock"
 )
@@ -132,7 +135,7 @@ func Test_handler_TopupBalance(t *testing.T) {
 				gomock.InOrder(
 					mockUsecaseBalance.EXPECT().TopupBalance(gomock.Any(), usecasebalance.TopupBalanceRequest{
 						UserId: "id",
-						Amount: 1000,
+						Amount: 1000 * money.Unit,
 					}).Return(usecasebalance.TopupBalanceResponse{
 						Code: http.StatusNoContent,
 					}, nil),
@@ -152,7 +155,7 @@ func Test_handler_TopupBalance(t *testing.T) {
 				gomock.InOrder(
 					mockUsecaseBalance.EXPECT().TopupBalance(gomock.Any(), usecasebalance.TopupBalanceRequest{
 						UserId: "id",
-						Amount: 1000,
+						Amount: 1000 * money.Unit,
 					}).Return(usecasebalance.TopupBalanceResponse{
 						Code: http.StatusInternalServerError,
 					}, fmt.Errorf("foo")),
@@ -170,6 +173,17 @@ func Test_handler_TopupBalance(t *testing.T) {
 			},
 			mock: func() {},
 		},
+		{
+			name: "error unmarshal inexact amount",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/balance_topup", bytes.NewBufferString(`{"amount":0.0001}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
 		{
 			name: "error read body",
 			fields: fields{
@@ -230,7 +244,33 @@ func Test_handler_TransferBalance(t *testing.T) {
 					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), usecasebalance.TransferBalanceRequest{
 						UserId:     "id",
 						ToUsername: "tousername",
-						Amount:     1000,
+						Amount:     1000 * money.Unit,
+					}).Return(usecasebalance.TransferBalanceResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success with idempotency key",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest(http.MethodPost, "/transfer", bytes.NewBufferString(`{"to_username":"tousername","amount":1000}`)).WithContext(ctx)
+					r.Header.Set("Idempotency-Key", "key")
+					return r
+				}(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), usecasebalance.TransferBalanceRequest{
+						UserId:         "id",
+						IdempotencyKey: "key",
+						ToUsername:     "tousername",
+						Amount:         1000 * money.Unit,
 					}).Return(usecasebalance.TransferBalanceResponse{
 						Code: http.StatusNoContent,
 					}, nil),
@@ -251,7 +291,7 @@ func Test_handler_TransferBalance(t *testing.T) {
 					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), usecasebalance.TransferBalanceRequest{
 						UserId:     "id",
 						ToUsername: "tousername",
-						Amount:     1000,
+						Amount:     1000 * money.Unit,
 					}).Return(usecasebalance.TransferBalanceResponse{
 						Code: http.StatusInternalServerError,
 					}, fmt.Errorf("foo")),
@@ -269,6 +309,17 @@ func Test_handler_TransferBalance(t *testing.T) {
 			},
 			mock: func() {},
 		},
+		{
+			name: "error unmarshal inexact amount",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/transfer", bytes.NewBufferString(`{"to_username":"tousername","amount":10.0005}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
 		{
 			name: "error read body",
 			fields: fields{
@@ -291,3 +342,1261 @@ func Test_handler_TransferBalance(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_QuoteTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/transfer/quote", bytes.NewBufferString(`{"to_username":"tousername","amount":1000}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().QuoteTransfer(gomock.Any(), usecasebalance.QuoteTransferRequest{
+						UserId:     "id",
+						ToUsername: "tousername",
+						Amount:     1000 * money.Unit,
+					}).Return(usecasebalance.QuoteTransferResponse{
+						Code:   http.StatusOK,
+						Amount: 1000 * money.Unit,
+						Fee:    10 * money.Unit,
+						Total:  1010 * money.Unit,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.QuoteTransfer",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/transfer/quote", bytes.NewBufferString(`{"to_username":"tousername","amount":1000}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().QuoteTransfer(gomock.Any(), usecasebalance.QuoteTransferRequest{
+						UserId:     "id",
+						ToUsername: "tousername",
+						Amount:     1000 * money.Unit,
+					}).Return(usecasebalance.QuoteTransferResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/transfer/quote", bytes.NewBufferString(`{"amount":"1000"}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/transfer/quote", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.QuoteTransfer(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_RefundTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/transfers/tid/refund", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "tid"})
+	}
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":10}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().RefundTransfer(gomock.Any(), usecasebalance.RefundTransferRequest{
+						UserId:     "id",
+						TransferId: "tid",
+						Amount:     10 * money.Unit,
+					}).Return(usecasebalance.RefundTransferResponse{
+						Code:       http.StatusCreated,
+						RefundId:   "rid",
+						TransferId: "tid",
+						Amount:     10 * money.Unit,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success without body with idempotency key",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := newRequest(nil)
+					r.Header.Set("Idempotency-Key", "key")
+					return r
+				}(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().RefundTransfer(gomock.Any(), usecasebalance.RefundTransferRequest{
+						UserId:         "id",
+						IdempotencyKey: "key",
+						TransferId:     "tid",
+					}).Return(usecasebalance.RefundTransferResponse{
+						Code:       http.StatusCreated,
+						RefundId:   "rid",
+						TransferId: "tid",
+						Amount:     10 * money.Unit,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.RefundTransfer",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":10}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().RefundTransfer(gomock.Any(), usecasebalance.RefundTransferRequest{
+						UserId:     "id",
+						TransferId: "tid",
+						Amount:     10 * money.Unit,
+					}).Return(usecasebalance.RefundTransferResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":"10"}`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.RefundTransfer(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_CreateHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest(http.MethodPost, "/holds", bytes.NewBufferString(`{"to_username":"tousername","amount":10,"expires_in":3600}`)).WithContext(ctx)
+					r.Header.Set("Idempotency-Key", "key")
+					return r
+				}(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().CreateHold(gomock.Any(), usecasebalance.CreateHoldRequest{
+						UserId:         "id",
+						IdempotencyKey: "key",
+						ToUsername:     "tousername",
+						Amount:         10 * money.Unit,
+						ExpiresIn:      3600,
+					}).Return(usecasebalance.CreateHoldResponse{
+						Code: http.StatusCreated,
+						Hold: usecasebalance.Hold{
+							Id:     "hid",
+							Amount: 10 * money.Unit,
+							Status: "active",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.CreateHold",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/holds", bytes.NewBufferString(`{"to_username":"tousername","amount":10}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().CreateHold(gomock.Any(), usecasebalance.CreateHoldRequest{
+						UserId:     "id",
+						ToUsername: "tousername",
+						Amount:     10 * money.Unit,
+					}).Return(usecasebalance.CreateHoldResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/holds", bytes.NewBufferString(`{"amount":"10"}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/holds", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CreateHold(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_CaptureHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/holds/hid/capture", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "hid"})
+	}
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":10}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().CaptureHold(gomock.Any(), usecasebalance.CaptureHoldRequest{
+						UserId: "id",
+						HoldId: "hid",
+						Amount: 10 * money.Unit,
+					}).Return(usecasebalance.CaptureHoldResponse{
+						Code: http.StatusOK,
+						Hold: usecasebalance.Hold{
+							Id:             "hid",
+							Amount:         20 * money.Unit,
+							CapturedAmount: 10 * money.Unit,
+							Status:         "captured",
+							TransferId:     "tid",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success without body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().CaptureHold(gomock.Any(), usecasebalance.CaptureHoldRequest{
+						UserId: "id",
+						HoldId: "hid",
+					}).Return(usecasebalance.CaptureHoldResponse{
+						Code: http.StatusOK,
+						Hold: usecasebalance.Hold{
+							Id:             "hid",
+							Amount:         20 * money.Unit,
+							CapturedAmount: 20 * money.Unit,
+							Status:         "captured",
+							TransferId:     "tid",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.CaptureHold",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().CaptureHold(gomock.Any(), usecasebalance.CaptureHoldRequest{
+						UserId: "id",
+						HoldId: "hid",
+					}).Return(usecasebalance.CaptureHoldResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":"10"}`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CaptureHold(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_VoidHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/holds/hid/void", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "hid"})
+	}
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().VoidHold(gomock.Any(), usecasebalance.VoidHoldRequest{
+						UserId: "id",
+						HoldId: "hid",
+					}).Return(usecasebalance.VoidHoldResponse{
+						Code: http.StatusOK,
+						Hold: usecasebalance.Hold{
+							Id:     "hid",
+							Amount: 20 * money.Unit,
+							Status: "voided",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.VoidHold",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().VoidHold(gomock.Any(), usecasebalance.VoidHoldRequest{
+						UserId: "id",
+						HoldId: "hid",
+					}).Return(usecasebalance.VoidHoldResponse{
+						Code: http.StatusNotFound,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.VoidHold(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_CreatePocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/pockets", bytes.NewBufferString(`{"name":"rent","goal_amount":100}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().CreatePocket(gomock.Any(), usecasebalance.CreatePocketRequest{
+						UserId:     "id",
+						Name:       "rent",
+						GoalAmount: 100 * money.Unit,
+					}).Return(usecasebalance.CreatePocketResponse{
+						Code: http.StatusCreated,
+						Pocket: usecasebalance.Pocket{
+							Id:         "pid",
+							Name:       "rent",
+							GoalAmount: 100 * money.Unit,
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.CreatePocket",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/pockets", bytes.NewBufferString(`{"name":"rent"}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().CreatePocket(gomock.Any(), usecasebalance.CreatePocketRequest{
+						UserId: "id",
+						Name:   "rent",
+					}).Return(usecasebalance.CreatePocketResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/pockets", bytes.NewBufferString(`{"name":1}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/pockets", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CreatePocket(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListPockets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/pockets", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().ListPockets(gomock.Any(), usecasebalance.ListPocketsRequest{
+						UserId: "id",
+					}).Return(usecasebalance.ListPocketsResponse{
+						Code: http.StatusOK,
+						Data: []usecasebalance.Pocket{
+							{
+								Id:   "pid",
+								Name: "rent",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.ListPockets",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/pockets", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().ListPockets(gomock.Any(), usecasebalance.ListPocketsRequest{
+						UserId: "id",
+					}).Return(usecasebalance.ListPocketsResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListPockets(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_MovePocketBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest(http.MethodPost, "/pockets/move", bytes.NewBufferString(`{"to_pocket_id":"pid","amount":10}`)).WithContext(ctx)
+					r.Header.Set("Idempotency-Key", "key")
+					return r
+				}(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().MovePocketBalance(gomock.Any(), usecasebalance.MovePocketBalanceRequest{
+						UserId:         "id",
+						IdempotencyKey: "key",
+						ToPocketId:     "pid",
+						Amount:         10 * money.Unit,
+					}).Return(usecasebalance.MovePocketBalanceResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.MovePocketBalance",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/pockets/move", bytes.NewBufferString(`{"from_pocket_id":"pid","amount":10}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().MovePocketBalance(gomock.Any(), usecasebalance.MovePocketBalanceRequest{
+						UserId:       "id",
+						FromPocketId: "pid",
+						Amount:       10 * money.Unit,
+					}).Return(usecasebalance.MovePocketBalanceResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/pockets/move", bytes.NewBufferString(`{"amount":"10"}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/pockets/move", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.MovePocketBalance(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_DepositSharedWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/wallets/wid/deposit", body).WithContext(ctx)
+		r.Header.Set("Idempotency-Key", "key")
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":10}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().DepositSharedWallet(gomock.Any(), usecasebalance.DepositSharedWalletRequest{
+						UserId:         "id",
+						IdempotencyKey: "key",
+						WalletId:       "wid",
+						Amount:         10 * money.Unit,
+					}).Return(usecasebalance.DepositSharedWalletResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.DepositSharedWallet",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":10}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().DepositSharedWallet(gomock.Any(), usecasebalance.DepositSharedWalletRequest{
+						UserId:         "id",
+						IdempotencyKey: "key",
+						WalletId:       "wid",
+						Amount:         10 * money.Unit,
+					}).Return(usecasebalance.DepositSharedWalletResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":"10"}`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.DepositSharedWallet(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/balances", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().ListBalances(gomock.Any(), usecasebalance.ListBalancesRequest{
+						UserId: "id",
+					}).Return(usecasebalance.ListBalancesResponse{
+						Code: http.StatusOK,
+						Data: []usecasebalance.Balance{
+							{
+								Currency: "IDR",
+								Main:     true,
+								Balance:  1000 * money.Unit,
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.ListBalances",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/balances", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().ListBalances(gomock.Any(), usecasebalance.ListBalancesRequest{
+						UserId: "id",
+					}).Return(usecasebalance.ListBalancesResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListBalances(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_QuoteConversion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/convert/quote", bytes.NewBufferString(`{"from_currency":"USD","to_currency":"IDR","amount":1.5}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().QuoteConversion(gomock.Any(), usecasebalance.QuoteConversionRequest{
+						UserId:       "id",
+						FromCurrency: "USD",
+						ToCurrency:   "IDR",
+						Amount:       1500,
+					}).Return(usecasebalance.QuoteConversionResponse{
+						Code: http.StatusOK,
+						Conversion: usecasebalance.Conversion{
+							RateId:       1,
+							FromCurrency: "USD",
+							ToCurrency:   "IDR",
+							Amount:       150,
+							ToAmount:     2437500,
+							Rate:         1625000000000,
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.QuoteConversion",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/convert/quote", bytes.NewBufferString(`{"from_currency":"USD","to_currency":"IDR","amount":1.5}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().QuoteConversion(gomock.Any(), gomock.Any()).Return(usecasebalance.QuoteConversionResponse{
+						Code: http.StatusNotFound,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/convert/quote", bytes.NewBufferString(`{"amount":"1.5"}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/convert/quote", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.QuoteConversion(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ConvertBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/convert", body).WithContext(ctx)
+		r.Header.Set("Idempotency-Key", "key")
+		return r
+	}
+
+	type fields struct {
+		usecase usecasebalance.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"from_currency":"USD","to_currency":"IDR","amount":1.5,"rate_id":1}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().ConvertBalance(gomock.Any(), usecasebalance.ConvertBalanceRequest{
+						UserId:         "id",
+						IdempotencyKey: "key",
+						FromCurrency:   "USD",
+						ToCurrency:     "IDR",
+						Amount:         1500,
+						RateId:         1,
+					}).Return(usecasebalance.ConvertBalanceResponse{
+						Code: http.StatusOK,
+						Conversion: usecasebalance.Conversion{
+							RateId:       1,
+							FromCurrency: "USD",
+							ToCurrency:   "IDR",
+							Amount:       150,
+							ToAmount:     2437500,
+							Rate:         1625000000000,
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.ConvertBalance",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"from_currency":"USD","to_currency":"IDR","amount":1.5,"rate_id":1}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseBalance.EXPECT().ConvertBalance(gomock.Any(), gomock.Any()).Return(usecasebalance.ConvertBalanceResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"amount":"1.5"}`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseBalance,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ConvertBalance(tt.args.w, tt.args.r)
+		})
+	}
+}
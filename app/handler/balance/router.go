@@ -8,8 +8,20 @@ import (
 
 func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
 	router.HandleFunc("/balance_read", h.ReadBalance).Methods(http.MethodGet)
+	router.HandleFunc("/balances", h.ListBalances).Methods(http.MethodGet)
 	router.HandleFunc("/transfer", h.TransferBalance).Methods(http.MethodPost)
+	router.HandleFunc("/transfer/quote", h.QuoteTransfer).Methods(http.MethodPost)
 	router.HandleFunc("/balance_topup", h.TopupBalance).Methods(http.MethodPost)
+	router.HandleFunc("/transfers/{id}/refund", h.RefundTransfer).Methods(http.MethodPost)
+	router.HandleFunc("/holds", h.CreateHold).Methods(http.MethodPost)
+	router.HandleFunc("/holds/{id}/capture", h.CaptureHold).Methods(http.MethodPost)
+	router.HandleFunc("/holds/{id}/void", h.VoidHold).Methods(http.MethodPost)
+	router.HandleFunc("/pockets", h.CreatePocket).Methods(http.MethodPost)
+	router.HandleFunc("/pockets", h.ListPockets).Methods(http.MethodGet)
+	router.HandleFunc("/pockets/move", h.MovePocketBalance).Methods(http.MethodPost)
+	router.HandleFunc("/wallets/{id}/deposit", h.DepositSharedWallet).Methods(http.MethodPost)
+	router.HandleFunc("/convert", h.ConvertBalance).Methods(http.MethodPost)
+	router.HandleFunc("/convert/quote", h.QuoteConversion).Methods(http.MethodPost)
 
 	return router
 }
@@ -1,12 +1,23 @@
 package handler
 
 import (
+	"context"
+
+	handleraccount "github.com/kevinsudut/wallet-system/app/handler/account"
 	handlerauth "github.com/kevinsudut/wallet-system/app/handler/auth"
 	handlerbalance "github.com/kevinsudut/wallet-system/app/handler/balance"
+	handlerlimit "github.com/kevinsudut/wallet-system/app/handler/limit"
+	handlernotification "github.com/kevinsudut/wallet-system/app/handler/notification"
+	handlerpaymentrequest "github.com/kevinsudut/wallet-system/app/handler/paymentrequest"
+	handlerscheduledtransfer "github.com/kevinsudut/wallet-system/app/handler/scheduledtransfer"
 	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
 	handlertransaction "github.com/kevinsudut/wallet-system/app/handler/transaction"
+	handlerwallet "github.com/kevinsudut/wallet-system/app/handler/wallet"
+	handlerwebhook "github.com/kevinsudut/wallet-system/app/handler/webhook"
 	"github.com/kevinsudut/wallet-system/app/usecase"
+	usecaseauth "github.com/kevinsudut/wallet-system/app/usecase/auth"
 	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
 	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
 	"github.com/kevinsudut/wallet-system/pkg/lib/token"
 )
@@ -14,17 +25,26 @@ import (
 type handler struct {
 	handlers []handlertemplate.HandlerItf
 	token    token.TokenItf
+	auth     usecaseauth.UsecaseItf
 }
 
-func Init(token token.TokenItf, db database.DatabaseItf, redis redis.RedisItf) handlertemplate.HandlerItf {
-	usecase := usecase.Init(token, db, redis)
+func Init(ctx context.Context, token token.TokenItf, db database.DatabaseItf, redis redis.RedisItf, cache lrucache.LRUCacheItf) handlertemplate.HandlerItf {
+	usecase := usecase.Init(ctx, token, db, redis, cache)
 
 	return &handler{
 		token: token,
+		auth:  usecase.Auth,
 		handlers: []handlertemplate.HandlerItf{
 			handlerauth.Init(usecase.Auth),
 			handlerbalance.Init(usecase.Balance),
 			handlertransaction.Init(usecase.Transaction),
+			handlerscheduledtransfer.Init(usecase.ScheduledTransfer),
+			handlerpaymentrequest.Init(usecase.PaymentRequest),
+			handlerlimit.Init(usecase.Limit),
+			handleraccount.Init(usecase.Account),
+			handlerwallet.Init(usecase.Wallet),
+			handlerwebhook.Init(usecase.Webhook),
+			handlernotification.Init(usecase.Notification),
 		},
 	}
 }
@@ -0,0 +1,16 @@
+package handlerlimit
+
+import (
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
+)
+
+type handler struct {
+	usecase usecaselimit.UsecaseItf
+}
+
+func Init(usecase usecaselimit.UsecaseItf) handlertemplate.HandlerItf {
+	return &handler{
+		usecase: usecase,
+	}
+}
@@ -0,0 +1,36 @@
+package handlerlimit
+
+import (
+	"reflect"
+	"testing"
+
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
+)
+
+func TestInit(t *testing.T) {
+	type args struct {
+		usecase usecaselimit.UsecaseItf
+	}
+	tests := []struct {
+		name string
+		args args
+		want handlertemplate.HandlerItf
+	}{
+		{
+			args: args{
+				usecase: nil,
+			},
+			want: &handler{
+				usecase: nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Init(tt.args.usecase); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Init() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
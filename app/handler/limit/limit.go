@@ -0,0 +1,23 @@
+package handlerlimit
+
+import (
+	"net/http"
+
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/response"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+func (h handler) ReadLimits(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ReadLimits(r.Context(), usecaselimit.ReadLimitsRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ReadLimits.ReadLimits", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
@@ -0,0 +1,95 @@
+package handlerlimit
+
+import (
+	ctx "context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMain(t *testing.M) {
+	log.Init()
+	os.Exit(t.Run())
+}
+
+func Test_handler_ReadLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseLimit := usecaselimit.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecaselimit.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseLimit,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/limits", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseLimit.EXPECT().ReadLimits(gomock.Any(), usecaselimit.ReadLimitsRequest{
+						UserId: "id",
+					}).Return(usecaselimit.ReadLimitsResponse{
+						Code: http.StatusOK,
+						Tier: "basic",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error limit.ReadLimits",
+			fields: fields{
+				usecase: mockUsecaseLimit,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/limits", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseLimit.EXPECT().ReadLimits(gomock.Any(), usecaselimit.ReadLimitsRequest{
+						UserId: "id",
+					}).Return(usecaselimit.ReadLimitsResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ReadLimits(tt.args.w, tt.args.r)
+		})
+	}
+}
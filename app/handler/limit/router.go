@@ -0,0 +1,13 @@
+package handlerlimit
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
+	router.HandleFunc("/limits", h.ReadLimits).Methods(http.MethodGet)
+
+	return router
+}
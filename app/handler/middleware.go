@@ -2,16 +2,23 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
 	"github.com/kevinsudut/wallet-system/pkg/helper/context"
 	"github.com/kevinsudut/wallet-system/pkg/helper/response"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 )
 
+// Paths under adminPathPrefix are only served to admins.
+const adminPathPrefix = "/admin/"
+
 var noNeedAuth = map[string]bool{
-	"/create_user": true,
+	"/create_user":   true,
+	"/login":         true,
+	"/token/refresh": true,
 }
 
 func (h handler) authMiddleware(next http.Handler) http.Handler {
@@ -19,22 +26,47 @@ func (h handler) authMiddleware(next http.Handler) http.Handler {
 		ctx := r.Context()
 
 		if _, ok := noNeedAuth[r.URL.Path]; !ok {
-			data, err := h.token.Validate(r.Header.Get("Authorization"))
+			claims, err := h.token.Validate(r.Header.Get("Authorization"))
 			if err != nil {
 				log.Errorln("authMiddleware.Validate", err)
-				response.WriteErrorResponse(w, http.StatusUnauthorized)
+				response.WriteErrorResponse(w, apperror.ErrUnauthorized.Wrap(err))
+				return
+			}
+
+			revoked, err := h.auth.IsAccessTokenRevoked(ctx, claims.Id)
+			if err != nil {
+				log.Errorln("authMiddleware.IsAccessTokenRevoked", err)
+				response.WriteErrorResponse(w, apperror.ErrDependency.Wrap(err))
+				return
+			}
+
+			if revoked {
+				response.WriteErrorResponse(w, apperror.ErrUnauthorized)
 				return
 			}
 
 			var user entity.User
-			err = jsoniter.UnmarshalFromString(data.(string), &user)
+			err = jsoniter.UnmarshalFromString(claims.Data, &user)
 			if err != nil {
 				log.Errorln("authMiddleware.UnmarshalFromString", err)
-				response.WriteErrorResponse(w, http.StatusUnauthorized)
+				response.WriteErrorResponse(w, apperror.ErrUnauthorized.Wrap(err))
+				return
+			}
+
+			// The token carries the user as it was at login, its status and role are read again.
+			user, err = h.auth.GetAuthUser(ctx, user.Id)
+			if err != nil {
+				response.WriteErrorResponse(w, apperror.From(err))
+				return
+			}
+
+			if strings.HasPrefix(r.URL.Path, adminPathPrefix) && !user.IsAdmin() {
+				response.WriteErrorResponse(w, apperror.ErrForbidden)
 				return
 			}
 
 			ctx = context.SetAuth(ctx, user)
+			ctx = context.SetTokenClaims(ctx, claims)
 		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
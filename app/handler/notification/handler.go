@@ -0,0 +1,16 @@
+package handlernotification
+
+import (
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasenotification "github.com/kevinsudut/wallet-system/app/usecase/notification"
+)
+
+type handler struct {
+	usecase usecasenotification.UsecaseItf
+}
+
+func Init(usecase usecasenotification.UsecaseItf) handlertemplate.HandlerItf {
+	return &handler{
+		usecase: usecase,
+	}
+}
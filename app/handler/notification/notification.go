@@ -0,0 +1,184 @@
+package handlernotification
+
+import (
+	ctx "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	usecasenotification "github.com/kevinsudut/wallet-system/app/usecase/notification"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/response"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	// heartbeatInterval keeps proxies from closing a stream that has nothing to send.
+	heartbeatInterval = 15 * time.Second
+
+	// Clients only send pings and closes over a websocket, so larger messages are refused.
+	webSocketReadLimit    = 4096
+	webSocketWriteTimeout = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{}
+
+// webSocketMessage is a notification sent over a websocket, which has no fields of its own for the id and type.
+type webSocketMessage struct {
+	Id   string              `json:"id"`
+	Type string              `json:"type"`
+	Data jsoniter.RawMessage `json:"data"`
+}
+
+// StreamNotifications streams the notifications of the user as Server-Sent Events, or over a websocket when the
+// request asks to upgrade. A client resumes with the Last-Event-ID header, or the last_event_id query parameter
+// where it cannot set headers.
+func (h handler) StreamNotifications(w http.ResponseWriter, r *http.Request) {
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = r.URL.Query().Get("last_event_id")
+	}
+
+	streamCtx, cancel := ctx.WithCancel(r.Context())
+	defer cancel()
+
+	resp, err := h.usecase.SubscribeNotifications(streamCtx, usecasenotification.SubscribeNotificationsRequest{
+		UserId:      context.GetAuth(r.Context()).Id,
+		LastEventId: lastEventId,
+	})
+	if err != nil {
+		log.Errorln("StreamNotifications.SubscribeNotifications", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		streamWebSocket(streamCtx, cancel, w, r, resp, lastEventId)
+		return
+	}
+
+	// A stream outlives the read and write timeouts of the server.
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	err = rc.Flush()
+	if err != nil {
+		log.Errorln("StreamNotifications.Flush", err)
+		return
+	}
+
+	streamNotifications(streamCtx, resp, lastEventId, func(notification entity.Notification) error {
+		_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", notification.Id, notification.Type, notification.Data)
+		if err != nil {
+			return err
+		}
+
+		return rc.Flush()
+	}, func() error {
+		_, err := fmt.Fprint(w, ": heartbeat\n\n")
+		if err != nil {
+			return err
+		}
+
+		return rc.Flush()
+	})
+}
+
+// streamWebSocket upgrades r to a websocket and streams resp over it until the client closes it. The upgrader has
+// answered the client already when the upgrade fails.
+func streamWebSocket(streamCtx ctx.Context, cancel ctx.CancelFunc, w http.ResponseWriter, r *http.Request, resp usecasenotification.SubscribeNotificationsResponse, lastEventId string) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Errorln("StreamNotifications.Upgrade", err)
+		return
+	}
+	defer conn.Close()
+
+	// Reading answers the pings and the close of the client, and the stream ends once it fails.
+	conn.SetReadLimit(webSocketReadLimit)
+	go func() {
+		defer cancel()
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	streamNotifications(streamCtx, resp, lastEventId, func(notification entity.Notification) error {
+		message, err := jsoniter.Marshal(webSocketMessage{
+			Id:   notification.Id,
+			Type: notification.Type,
+			Data: notification.Data,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+		if err != nil {
+			return err
+		}
+
+		return conn.WriteMessage(websocket.TextMessage, message)
+	}, func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteTimeout))
+	})
+
+	// A client that closed the websocket first has been answered already.
+	err = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(webSocketWriteTimeout))
+	if err != nil && !errors.Is(err, websocket.ErrCloseSent) {
+		log.Errorln("StreamNotifications.WriteControl", err)
+	}
+}
+
+// streamNotifications sends the missed notifications of resp and then the new ones, until streamCtx is done, the
+// subscription is closed or sending fails. A notification that is not after the last one sent was sent already.
+func streamNotifications(streamCtx ctx.Context, resp usecasenotification.SubscribeNotificationsResponse, lastId string, send func(entity.Notification) error, heartbeat func() error) {
+	for _, notification := range resp.Missed {
+		err := send(notification)
+		if err != nil {
+			return
+		}
+		lastId = notification.Id
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-streamCtx.Done():
+			return
+		case notification, ok := <-resp.Notifications:
+			if !ok {
+				return
+			}
+			if !notification.IsAfter(lastId) {
+				continue
+			}
+
+			err := send(notification)
+			if err != nil {
+				return
+			}
+			lastId = notification.Id
+		case <-ticker.C:
+			err := heartbeat()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
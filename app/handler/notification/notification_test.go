@@ -0,0 +1,218 @@
+package handlernotification
+
+import (
+	ctx "context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	usecasenotification "github.com/kevinsudut/wallet-system/app/usecase/notification"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMain(t *testing.M) {
+	log.Init()
+	os.Exit(t.Run())
+}
+
+func Test_handler_StreamNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseNotification := usecasenotification.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	notification := entity.Notification{
+		Id:        "1-1",
+		UserId:    "id",
+		Type:      "balance.changed",
+		Data:      jsoniter.RawMessage(`{"balance":1000}`),
+		CreatedAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	}
+	newNotifications := func(notifications ...entity.Notification) <-chan entity.Notification {
+		ch := make(chan entity.Notification, len(notifications))
+		for _, notification := range notifications {
+			ch <- notification
+		}
+		close(ch)
+		return ch
+	}
+	newRequest := func(lastEventId string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/events/stream", nil).WithContext(ctx)
+		if lastEventId != "" {
+			r.Header.Set("Last-Event-ID", lastEventId)
+		}
+		return r
+	}
+
+	type fields struct {
+		usecase usecasenotification.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseNotification,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(""),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseNotification.EXPECT().SubscribeNotifications(gomock.Any(), usecasenotification.SubscribeNotificationsRequest{
+						UserId: "id",
+					}).Return(usecasenotification.SubscribeNotificationsResponse{
+						Code:          http.StatusOK,
+						Missed:        []entity.Notification{},
+						Notifications: newNotifications(notification),
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success resume",
+			fields: fields{
+				usecase: mockUsecaseNotification,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest("1-0"),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseNotification.EXPECT().SubscribeNotifications(gomock.Any(), usecasenotification.SubscribeNotificationsRequest{
+						UserId:      "id",
+						LastEventId: "1-0",
+					}).Return(usecasenotification.SubscribeNotificationsResponse{
+						Code:          http.StatusOK,
+						Missed:        []entity.Notification{notification},
+						Notifications: newNotifications(notification),
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error notification.SubscribeNotifications",
+			fields: fields{
+				usecase: mockUsecaseNotification,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest("foo"),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseNotification.EXPECT().SubscribeNotifications(gomock.Any(), usecasenotification.SubscribeNotificationsRequest{
+						UserId:      "id",
+						LastEventId: "foo",
+					}).Return(usecasenotification.SubscribeNotificationsResponse{
+						Code: http.StatusBadRequest,
+					}, apperror.ErrInvalidRequest.Wrap(fmt.Errorf("foo"))),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.StreamNotifications(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_StreamNotificationsWebSocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseNotification := usecasenotification.NewMockUsecaseItf(ctrl)
+
+	notification := entity.Notification{
+		Id:     "1-1",
+		UserId: "id",
+		Type:   "balance.changed",
+		Data:   jsoniter.RawMessage(`{"balance":1000}`),
+	}
+
+	h := handler{
+		usecase: mockUsecaseNotification,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.StreamNotifications(w, r.WithContext(context.SetAuth(r.Context(), entity.User{
+			Id: "id",
+		})))
+	}))
+	defer server.Close()
+
+	// The subscription stays open, so the stream only ends once the client closes the websocket.
+	notifications := make(chan entity.Notification)
+	mockUsecaseNotification.EXPECT().SubscribeNotifications(gomock.Any(), usecasenotification.SubscribeNotificationsRequest{
+		UserId:      "id",
+		LastEventId: "1-0",
+	}).Return(usecasenotification.SubscribeNotificationsResponse{
+		Code:          http.StatusOK,
+		Missed:        []entity.Notification{notification},
+		Notifications: notifications,
+	}, nil)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/events/stream?last_event_id=1-0", nil)
+	if err != nil {
+		t.Fatalf("websocket.Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("websocket.ReadMessage() error = %v", err)
+	}
+
+	var got webSocketMessage
+	err = jsoniter.Unmarshal(message, &got)
+	if err != nil {
+		t.Fatalf("jsoniter.Unmarshal() error = %v", err)
+	}
+
+	want := webSocketMessage{
+		Id:   notification.Id,
+		Type: notification.Type,
+		Data: notification.Data,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("handler.StreamNotifications() sent %v, want %v", got, want)
+	}
+
+	err = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	if err != nil {
+		t.Fatalf("websocket.WriteMessage() error = %v", err)
+	}
+
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("handler.StreamNotifications() closed with %v, want a normal close", err)
+	}
+}
@@ -0,0 +1,13 @@
+package handlernotification
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
+	router.HandleFunc("/events/stream", h.StreamNotifications).Methods(http.MethodGet)
+
+	return router
+}
@@ -0,0 +1,16 @@
+package handlerpaymentrequest
+
+import (
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasepaymentrequest "github.com/kevinsudut/wallet-system/app/usecase/paymentrequest"
+)
+
+type handler struct {
+	usecase usecasepaymentrequest.UsecaseItf
+}
+
+func Init(usecase usecasepaymentrequest.UsecaseItf) handlertemplate.HandlerItf {
+	return &handler{
+		usecase: usecase,
+	}
+}
@@ -0,0 +1,111 @@
+package handlerpaymentrequest
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	jsoniter "github.com/json-iterator/go"
+	usecasepaymentrequest "github.com/kevinsudut/wallet-system/app/usecase/paymentrequest"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/response"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+func (h handler) CreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("CreatePaymentRequest.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasepaymentrequest.CreatePaymentRequestRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("CreatePaymentRequest.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.CreatePaymentRequest(r.Context(), req)
+	if err != nil {
+		log.Errorln("CreatePaymentRequest.CreatePaymentRequest", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListIncomingPaymentRequests(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListIncomingPaymentRequests(r.Context(), usecasepaymentrequest.ListPaymentRequestsRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ListIncomingPaymentRequests.ListIncomingPaymentRequests", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListOutgoingPaymentRequests(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListOutgoingPaymentRequests(r.Context(), usecasepaymentrequest.ListPaymentRequestsRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ListOutgoingPaymentRequests.ListOutgoingPaymentRequests", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) AcceptPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.AcceptPaymentRequest(r.Context(), usecasepaymentrequest.UpdatePaymentRequestRequest{
+		UserId:           context.GetAuth(r.Context()).Id,
+		PaymentRequestId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("AcceptPaymentRequest.AcceptPaymentRequest", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) DeclinePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.DeclinePaymentRequest(r.Context(), usecasepaymentrequest.UpdatePaymentRequestRequest{
+		UserId:           context.GetAuth(r.Context()).Id,
+		PaymentRequestId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("DeclinePaymentRequest.DeclinePaymentRequest", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) CancelPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.CancelPaymentRequest(r.Context(), usecasepaymentrequest.UpdatePaymentRequestRequest{
+		UserId:           context.GetAuth(r.Context()).Id,
+		PaymentRequestId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("CancelPaymentRequest.CancelPaymentRequest", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
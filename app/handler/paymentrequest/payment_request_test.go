@@ -0,0 +1,524 @@
+package handlerpaymentrequest
+
+import (
+	"bytes"
+	ctx "context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasepaymentrequest "github.com/kevinsudut/wallet-system/app/usecase/paymentrequest"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMain(t *testing.M) {
+	log.Init()
+	os.Exit(t.Run())
+}
+
+func Test_handler_CreatePaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecasePaymentRequest := usecasepaymentrequest.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasepaymentrequest.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/payment_requests", bytes.NewBufferString(`{"payer_username":"payerusername","amount":10}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().CreatePaymentRequest(gomock.Any(), usecasepaymentrequest.CreatePaymentRequestRequest{
+						UserId:        "id",
+						PayerUsername: "payerusername",
+						Amount:        10 * money.Unit,
+					}).Return(usecasepaymentrequest.CreatePaymentRequestResponse{
+						Code: http.StatusCreated,
+						PaymentRequest: usecasepaymentrequest.PaymentRequest{
+							Id:                "prid",
+							RequesterUsername: "username",
+							PayerUsername:     "payerusername",
+							Amount:            10 * money.Unit,
+							Status:            "pending",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.CreatePaymentRequest",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/payment_requests", bytes.NewBufferString(`{"payer_username":"payerusername","amount":10}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Return(usecasepaymentrequest.CreatePaymentRequestResponse{
+						Code: http.StatusNotFound,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/payment_requests", bytes.NewBufferString(`{"amount":"ten"}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/payment_requests", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CreatePaymentRequest(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListIncomingPaymentRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecasePaymentRequest := usecasepaymentrequest.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasepaymentrequest.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/payment_requests/incoming", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().ListIncomingPaymentRequests(gomock.Any(), usecasepaymentrequest.ListPaymentRequestsRequest{
+						UserId: "id",
+					}).Return(usecasepaymentrequest.ListPaymentRequestsResponse{
+						Code: http.StatusOK,
+						Data: []usecasepaymentrequest.PaymentRequest{
+							{
+								Id:     "prid",
+								Status: "pending",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.ListIncomingPaymentRequests",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/payment_requests/incoming", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().ListIncomingPaymentRequests(gomock.Any(), gomock.Any()).Return(usecasepaymentrequest.ListPaymentRequestsResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListIncomingPaymentRequests(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListOutgoingPaymentRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecasePaymentRequest := usecasepaymentrequest.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasepaymentrequest.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/payment_requests/outgoing", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().ListOutgoingPaymentRequests(gomock.Any(), usecasepaymentrequest.ListPaymentRequestsRequest{
+						UserId: "id",
+					}).Return(usecasepaymentrequest.ListPaymentRequestsResponse{
+						Code: http.StatusOK,
+						Data: []usecasepaymentrequest.PaymentRequest{
+							{
+								Id:     "prid",
+								Status: "pending",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.ListOutgoingPaymentRequests",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/payment_requests/outgoing", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().ListOutgoingPaymentRequests(gomock.Any(), gomock.Any()).Return(usecasepaymentrequest.ListPaymentRequestsResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListOutgoingPaymentRequests(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_AcceptPaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecasePaymentRequest := usecasepaymentrequest.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/payment_requests/prid/accept", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "prid"})
+	}
+
+	type fields struct {
+		usecase usecasepaymentrequest.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().AcceptPaymentRequest(gomock.Any(), usecasepaymentrequest.UpdatePaymentRequestRequest{
+						UserId:           "id",
+						PaymentRequestId: "prid",
+					}).Return(usecasepaymentrequest.UpdatePaymentRequestResponse{
+						Code: http.StatusOK,
+						PaymentRequest: usecasepaymentrequest.PaymentRequest{
+							Id:     "prid",
+							Status: "accepted",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.AcceptPaymentRequest",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().AcceptPaymentRequest(gomock.Any(), gomock.Any()).Return(usecasepaymentrequest.UpdatePaymentRequestResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.AcceptPaymentRequest(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_DeclinePaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecasePaymentRequest := usecasepaymentrequest.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/payment_requests/prid/decline", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "prid"})
+	}
+
+	type fields struct {
+		usecase usecasepaymentrequest.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().DeclinePaymentRequest(gomock.Any(), usecasepaymentrequest.UpdatePaymentRequestRequest{
+						UserId:           "id",
+						PaymentRequestId: "prid",
+					}).Return(usecasepaymentrequest.UpdatePaymentRequestResponse{
+						Code: http.StatusOK,
+						PaymentRequest: usecasepaymentrequest.PaymentRequest{
+							Id:     "prid",
+							Status: "declined",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.DeclinePaymentRequest",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Any()).Return(usecasepaymentrequest.UpdatePaymentRequestResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.DeclinePaymentRequest(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_CancelPaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecasePaymentRequest := usecasepaymentrequest.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/payment_requests/prid/cancel", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "prid"})
+	}
+
+	type fields struct {
+		usecase usecasepaymentrequest.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().CancelPaymentRequest(gomock.Any(), usecasepaymentrequest.UpdatePaymentRequestRequest{
+						UserId:           "id",
+						PaymentRequestId: "prid",
+					}).Return(usecasepaymentrequest.UpdatePaymentRequestResponse{
+						Code: http.StatusOK,
+						PaymentRequest: usecasepaymentrequest.PaymentRequest{
+							Id:     "prid",
+							Status: "canceled",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.CancelPaymentRequest",
+			fields: fields{
+				usecase: mockUsecasePaymentRequest,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecasePaymentRequest.EXPECT().CancelPaymentRequest(gomock.Any(), gomock.Any()).Return(usecasepaymentrequest.UpdatePaymentRequestResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CancelPaymentRequest(tt.args.w, tt.args.r)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package handlerpaymentrequest
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
+	router.HandleFunc("/payment_requests", h.CreatePaymentRequest).Methods(http.MethodPost)
+	router.HandleFunc("/payment_requests/incoming", h.ListIncomingPaymentRequests).Methods(http.MethodGet)
+	router.HandleFunc("/payment_requests/outgoing", h.ListOutgoingPaymentRequests).Methods(http.MethodGet)
+	router.HandleFunc("/payment_requests/{id}/accept", h.AcceptPaymentRequest).Methods(http.MethodPost)
+	router.HandleFunc("/payment_requests/{id}/decline", h.DeclinePaymentRequest).Methods(http.MethodPost)
+	router.HandleFunc("/payment_requests/{id}/cancel", h.CancelPaymentRequest).Methods(http.MethodPost)
+
+	return router
+}
@@ -1,10 +1,18 @@
 package handler
 
-import "github.com/gorilla/mux"
+import (
+	"expvar"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
 
 func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
 	router.Use(h.authMiddleware)
 
+	// Runtime and cache hit/miss counters, only served to admins like every path under adminPathPrefix.
+	router.Handle(adminPathPrefix+"debug/vars", expvar.Handler()).Methods(http.MethodGet)
+
 	for _, h := range h.handlers {
 		router = h.RegisterHandlers(router)
 	}
@@ -0,0 +1,16 @@
+package handlerscheduledtransfer
+
+import (
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasescheduledtransfer "github.com/kevinsudut/wallet-system/app/usecase/scheduledtransfer"
+)
+
+type handler struct {
+	usecase usecasescheduledtransfer.UsecaseItf
+}
+
+func Init(usecase usecasescheduledtransfer.UsecaseItf) handlertemplate.HandlerItf {
+	return &handler{
+		usecase: usecase,
+	}
+}
@@ -0,0 +1,16 @@
+package handlerscheduledtransfer
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
+	router.HandleFunc("/scheduled_transfers", h.CreateScheduledTransfer).Methods(http.MethodPost)
+	router.HandleFunc("/scheduled_transfers", h.ListScheduledTransfers).Methods(http.MethodGet)
+	router.HandleFunc("/scheduled_transfers/{id}/runs", h.ListScheduledTransferRuns).Methods(http.MethodGet)
+	router.HandleFunc("/scheduled_transfers/{id}/cancel", h.CancelScheduledTransfer).Methods(http.MethodPost)
+
+	return router
+}
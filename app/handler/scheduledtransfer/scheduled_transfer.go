@@ -0,0 +1,84 @@
+package handlerscheduledtransfer
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	jsoniter "github.com/json-iterator/go"
+	usecasescheduledtransfer "github.com/kevinsudut/wallet-system/app/usecase/scheduledtransfer"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/response"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+func (h handler) CreateScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("CreateScheduledTransfer.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasescheduledtransfer.CreateScheduledTransferRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("CreateScheduledTransfer.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.CreateScheduledTransfer(r.Context(), req)
+	if err != nil {
+		log.Errorln("CreateScheduledTransfer.CreateScheduledTransfer", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListScheduledTransfers(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListScheduledTransfers(r.Context(), usecasescheduledtransfer.ListScheduledTransfersRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ListScheduledTransfers.ListScheduledTransfers", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListScheduledTransferRuns(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListScheduledTransferRuns(r.Context(), usecasescheduledtransfer.ListScheduledTransferRunsRequest{
+		UserId:              context.GetAuth(r.Context()).Id,
+		ScheduledTransferId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("ListScheduledTransferRuns.ListScheduledTransferRuns", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) CancelScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.CancelScheduledTransfer(r.Context(), usecasescheduledtransfer.CancelScheduledTransferRequest{
+		UserId:              context.GetAuth(r.Context()).Id,
+		ScheduledTransferId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("CancelScheduledTransfer.CancelScheduledTransfer", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
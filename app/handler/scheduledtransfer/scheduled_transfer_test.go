@@ -0,0 +1,381 @@
+package handlerscheduledtransfer
+
+import (
+	"bytes"
+	ctx "context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasescheduledtransfer "github.com/kevinsudut/wallet-system/app/usecase/scheduledtransfer"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMain(t *testing.M) {
+	log.Init()
+	os.Exit(t.Run())
+}
+
+func Test_handler_CreateScheduledTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseScheduledTransfer := usecasescheduledtransfer.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasescheduledtransfer.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/scheduled_transfers", bytes.NewBufferString(`{"to_username":"tousername","amount":10,"execute_at":"2026-11-01T09:00:00Z","recurrence":"monthly","day_of_month":1,"on_failure":"skip"}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseScheduledTransfer.EXPECT().CreateScheduledTransfer(gomock.Any(), usecasescheduledtransfer.CreateScheduledTransferRequest{
+						UserId:     "id",
+						ToUsername: "tousername",
+						Amount:     10 * money.Unit,
+						ExecuteAt:  time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC),
+						Recurrence: "monthly",
+						DayOfMonth: 1,
+						OnFailure:  "skip",
+					}).Return(usecasescheduledtransfer.CreateScheduledTransferResponse{
+						Code: http.StatusCreated,
+						ScheduledTransfer: usecasescheduledtransfer.ScheduledTransfer{
+							Id:         "sid",
+							ToUsername: "tousername",
+							Amount:     10 * money.Unit,
+							Recurrence: "monthly",
+							DayOfMonth: 1,
+							OnFailure:  "skip",
+							Status:     "active",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.CreateScheduledTransfer",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/scheduled_transfers", bytes.NewBufferString(`{"to_username":"tousername","amount":10}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseScheduledTransfer.EXPECT().CreateScheduledTransfer(gomock.Any(), usecasescheduledtransfer.CreateScheduledTransferRequest{
+						UserId:     "id",
+						ToUsername: "tousername",
+						Amount:     10 * money.Unit,
+					}).Return(usecasescheduledtransfer.CreateScheduledTransferResponse{
+						Code: http.StatusBadRequest,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/scheduled_transfers", bytes.NewBufferString(`{"execute_at":"tomorrow"}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/scheduled_transfers", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CreateScheduledTransfer(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListScheduledTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseScheduledTransfer := usecasescheduledtransfer.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasescheduledtransfer.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/scheduled_transfers", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseScheduledTransfer.EXPECT().ListScheduledTransfers(gomock.Any(), usecasescheduledtransfer.ListScheduledTransfersRequest{
+						UserId: "id",
+					}).Return(usecasescheduledtransfer.ListScheduledTransfersResponse{
+						Code: http.StatusOK,
+						Data: []usecasescheduledtransfer.ScheduledTransfer{
+							{
+								Id:     "sid",
+								Status: "active",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.ListScheduledTransfers",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/scheduled_transfers", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseScheduledTransfer.EXPECT().ListScheduledTransfers(gomock.Any(), gomock.Any()).Return(usecasescheduledtransfer.ListScheduledTransfersResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListScheduledTransfers(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListScheduledTransferRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseScheduledTransfer := usecasescheduledtransfer.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/scheduled_transfers/sid/runs", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "sid"})
+	}
+
+	type fields struct {
+		usecase usecasescheduledtransfer.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseScheduledTransfer.EXPECT().ListScheduledTransferRuns(gomock.Any(), usecasescheduledtransfer.ListScheduledTransferRunsRequest{
+						UserId:              "id",
+						ScheduledTransferId: "sid",
+					}).Return(usecasescheduledtransfer.ListScheduledTransferRunsResponse{
+						Code: http.StatusOK,
+						Data: []usecasescheduledtransfer.ScheduledTransferRun{
+							{
+								Id:        "rid",
+								Attempt:   1,
+								Status:    "failed",
+								ErrorCode: "insufficient_balance",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.ListScheduledTransferRuns",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseScheduledTransfer.EXPECT().ListScheduledTransferRuns(gomock.Any(), gomock.Any()).Return(usecasescheduledtransfer.ListScheduledTransferRunsResponse{
+						Code: http.StatusNotFound,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListScheduledTransferRuns(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_CancelScheduledTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseScheduledTransfer := usecasescheduledtransfer.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/scheduled_transfers/sid/cancel", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "sid"})
+	}
+
+	type fields struct {
+		usecase usecasescheduledtransfer.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseScheduledTransfer.EXPECT().CancelScheduledTransfer(gomock.Any(), usecasescheduledtransfer.CancelScheduledTransferRequest{
+						UserId:              "id",
+						ScheduledTransferId: "sid",
+					}).Return(usecasescheduledtransfer.CancelScheduledTransferResponse{
+						Code: http.StatusOK,
+						ScheduledTransfer: usecasescheduledtransfer.ScheduledTransfer{
+							Id:     "sid",
+							Status: "canceled",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.CancelScheduledTransfer",
+			fields: fields{
+				usecase: mockUsecaseScheduledTransfer,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseScheduledTransfer.EXPECT().CancelScheduledTransfer(gomock.Any(), gomock.Any()).Return(usecasescheduledtransfer.CancelScheduledTransferResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CancelScheduledTransfer(tt.args.w, tt.args.r)
+		})
+	}
+}
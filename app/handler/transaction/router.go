@@ -9,6 +9,7 @@ import (
 func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
 	router.HandleFunc("/top_users", h.ListOverallTopTransactingUsersByValue).Methods(http.MethodGet)
 	router.HandleFunc("/top_transaction_per_user", h.TopTransactionsForUser).Methods(http.MethodGet)
+	router.HandleFunc("/transactions", h.ListTransactions).Methods(http.MethodGet)
 
 	return router
 }
@@ -2,35 +2,136 @@ package handlertransaction
 
 import (
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	usecasetransaction "github.com/kevinsudut/wallet-system/app/usecase/transaction"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
 	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 	"github.com/kevinsudut/wallet-system/pkg/helper/response"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 )
 
 func (h handler) ListOverallTopTransactingUsersByValue(w http.ResponseWriter, r *http.Request) {
-	resp, err := h.usecase.ListOverallTopTransactingUsersByValue(r.Context(), usecasetransaction.ListOverallTopTransactingUsersByValueRequest{
-		UserId: context.GetAuth(r.Context()).Id,
-	})
+	req, err := parseListOverallTopTransactingUsersByValueRequest(r.URL.Query())
+	if err != nil {
+		log.Errorln("ListOverallTopTransactingUsersByValue.parseListOverallTopTransactingUsersByValueRequest", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	resp, err := h.usecase.ListOverallTopTransactingUsersByValue(r.Context(), req)
 	if err != nil {
 		log.Errorln("ListOverallTopTransactingUsersByValue.ListOverallTopTransactingUsersByValue", err)
-		response.WriteErrorResponse(w, resp.Code)
+		response.WriteErrorResponse(w, err)
 		return
 	}
 
-	response.WriteJsonResponse(w, resp.Code, resp.Data)
+	response.WriteJsonResponse(w, resp.Code, resp)
 }
 
 func (h handler) TopTransactionsForUser(w http.ResponseWriter, r *http.Request) {
 	resp, err := h.usecase.TopTransactionsForUser(r.Context(), usecasetransaction.TopTransactionsForUserRequest{
 		UserId: context.GetAuth(r.Context()).Id,
+		Window: r.URL.Query().Get("window"),
 	})
 	if err != nil {
 		log.Errorln("TopTransactionsForUser.TopTransactionsForUser", err)
-		response.WriteErrorResponse(w, resp.Code)
+		response.WriteErrorResponse(w, err)
 		return
 	}
 
 	response.WriteJsonResponse(w, resp.Code, resp.Data)
 }
+
+func (h handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	req, err := parseListTransactionsRequest(r.URL.Query())
+	if err != nil {
+		log.Errorln("ListTransactions.parseListTransactionsRequest", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.ListTransactions(r.Context(), req)
+	if err != nil {
+		log.Errorln("ListTransactions.ListTransactions", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func parseListTransactionsRequest(query url.Values) (req usecasetransaction.ListTransactionsRequest, err error) {
+	req = usecasetransaction.ListTransactionsRequest{
+		Type:         query.Get("type"),
+		Counterparty: query.Get("counterparty"),
+		Cursor:       query.Get("cursor"),
+	}
+
+	if v := query.Get("min_amount"); v != "" {
+		amount, err := money.Parse(v)
+		if err != nil {
+			return req, err
+		}
+		req.MinAmount = &amount
+	}
+
+	if v := query.Get("max_amount"); v != "" {
+		amount, err := money.Parse(v)
+		if err != nil {
+			return req, err
+		}
+		req.MaxAmount = &amount
+	}
+
+	if v := query.Get("from"); v != "" {
+		req.From, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return req, err
+		}
+	}
+
+	if v := query.Get("to"); v != "" {
+		req.To, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return req, err
+		}
+	}
+
+	if v := query.Get("limit"); v != "" {
+		req.Limit, err = strconv.Atoi(v)
+		if err != nil {
+			return req, err
+		}
+	}
+
+	return req, nil
+}
+
+func parseListOverallTopTransactingUsersByValueRequest(query url.Values) (req usecasetransaction.ListOverallTopTransactingUsersByValueRequest, err error) {
+	req = usecasetransaction.ListOverallTopTransactingUsersByValueRequest{
+		Direction: query.Get("direction"),
+		Window:    query.Get("window"),
+	}
+
+	if v := query.Get("offset"); v != "" {
+		req.Offset, err = strconv.Atoi(v)
+		if err != nil {
+			return req, err
+		}
+	}
+
+	if v := query.Get("limit"); v != "" {
+		req.Limit, err = strconv.Atoi(v)
+		if err != nil {
+			return req, err
+		}
+	}
+
+	return req, nil
+}
@@ -7,10 +7,12 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/kevinsudut/wallet-system/app/entity"
 	usecasetransaction "github.com/kevinsudut/wallet-system/app/usecase/transaction"
 	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 	"go.uber.org/mock/gomock"
 )
@@ -50,12 +52,15 @@ func Test_handler_ListOverallTopTransactingUsersByValue(t *testing.T) {
 			},
 			args: args{
 				w: httptest.NewRecorder(),
-				r: httptest.NewRequest(http.MethodGet, "/top_users", nil).WithContext(ctx),
+				r: httptest.NewRequest(http.MethodGet, "/top_users?direction=sent&window=7d&offset=10&limit=5", nil).WithContext(ctx),
 			},
 			mock: func() {
 				gomock.InOrder(
 					mockUsecaseTransaction.EXPECT().ListOverallTopTransactingUsersByValue(gomock.Any(), usecasetransaction.ListOverallTopTransactingUsersByValueRequest{
-						UserId: "id",
+						Direction: "sent",
+						Window:    "7d",
+						Offset:    10,
+						Limit:     5,
 					}).Return(usecasetransaction.ListOverallTopTransactingUsersByValueResponse{
 						Code: http.StatusOK,
 						Data: []usecasetransaction.ListOverallTopTransactingUsersByValue{},
@@ -74,14 +79,23 @@ func Test_handler_ListOverallTopTransactingUsersByValue(t *testing.T) {
 			},
 			mock: func() {
 				gomock.InOrder(
-					mockUsecaseTransaction.EXPECT().ListOverallTopTransactingUsersByValue(gomock.Any(), usecasetransaction.ListOverallTopTransactingUsersByValueRequest{
-						UserId: "id",
-					}).Return(usecasetransaction.ListOverallTopTransactingUsersByValueResponse{
+					mockUsecaseTransaction.EXPECT().ListOverallTopTransactingUsersByValue(gomock.Any(), usecasetransaction.ListOverallTopTransactingUsersByValueRequest{}).Return(usecasetransaction.ListOverallTopTransactingUsersByValueResponse{
 						Code: http.StatusInternalServerError,
 					}, fmt.Errorf("foo")),
 				)
 			},
 		},
+		{
+			name: "error parseListOverallTopTransactingUsersByValueRequest",
+			fields: fields{
+				usecase: mockUsecaseTransaction,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/top_users?offset=foo", nil).WithContext(ctx),
+			},
+			mock: func() {},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -124,12 +138,13 @@ func Test_handler_TopTransactionsForUser(t *testing.T) {
 			},
 			args: args{
 				w: httptest.NewRecorder(),
-				r: httptest.NewRequest(http.MethodGet, "/top_transaction_per_user", nil).WithContext(ctx),
+				r: httptest.NewRequest(http.MethodGet, "/top_transaction_per_user?window=month:2026-09", nil).WithContext(ctx),
 			},
 			mock: func() {
 				gomock.InOrder(
 					mockUsecaseTransaction.EXPECT().TopTransactionsForUser(gomock.Any(), usecasetransaction.TopTransactionsForUserRequest{
 						UserId: "id",
+						Window: "month:2026-09",
 					}).Return(usecasetransaction.TopTransactionsForUserResponse{
 						Code: http.StatusOK,
 						Data: []usecasetransaction.TopTransactionsForUser{},
@@ -167,3 +182,141 @@ func Test_handler_TopTransactionsForUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_ListTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseTransaction := usecasetransaction.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	minAmount := 10 * money.Unit
+	maxAmount := 100 * money.Unit
+
+	type fields struct {
+		usecase usecasetransaction.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseTransaction,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/transactions", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseTransaction.EXPECT().ListTransactions(gomock.Any(), usecasetransaction.ListTransactionsRequest{
+						UserId: "id",
+					}).Return(usecasetransaction.ListTransactionsResponse{
+						Code: http.StatusOK,
+						Data: []usecasetransaction.Transaction{},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success with filter",
+			fields: fields{
+				usecase: mockUsecaseTransaction,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/transactions?type=DEBIT&counterparty=tousername&min_amount=10&max_amount=100&from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z&cursor=cursor&limit=5", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseTransaction.EXPECT().ListTransactions(gomock.Any(), usecasetransaction.ListTransactionsRequest{
+						UserId:       "id",
+						Type:         "DEBIT",
+						Counterparty: "tousername",
+						MinAmount:    &minAmount,
+						MaxAmount:    &maxAmount,
+						From:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						To:           time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+						Cursor:       "cursor",
+						Limit:        5,
+					}).Return(usecasetransaction.ListTransactionsResponse{
+						Code: http.StatusOK,
+						Data: []usecasetransaction.Transaction{},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error transaction.ListTransactions",
+			fields: fields{
+				usecase: mockUsecaseTransaction,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/transactions", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseTransaction.EXPECT().ListTransactions(gomock.Any(), usecasetransaction.ListTransactionsRequest{
+						UserId: "id",
+					}).Return(usecasetransaction.ListTransactionsResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error parse amount",
+			fields: fields{
+				usecase: mockUsecaseTransaction,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/transactions?min_amount=0.0001", nil).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error parse date",
+			fields: fields{
+				usecase: mockUsecaseTransaction,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/transactions?from=yesterday", nil).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error parse limit",
+			fields: fields{
+				usecase: mockUsecaseTransaction,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/transactions?limit=ten", nil).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListTransactions(tt.args.w, tt.args.r)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package handlerwallet
+
+import (
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasewallet "github.com/kevinsudut/wallet-system/app/usecase/wallet"
+)
+
+type handler struct {
+	usecase usecasewallet.UsecaseItf
+}
+
+func Init(usecase usecasewallet.UsecaseItf) handlertemplate.HandlerItf {
+	return &handler{
+		usecase: usecase,
+	}
+}
@@ -0,0 +1,22 @@
+package handlerwallet
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
+	router.HandleFunc("/wallets", h.CreateWallet).Methods(http.MethodPost)
+	router.HandleFunc("/wallets", h.ListWallets).Methods(http.MethodGet)
+	router.HandleFunc("/wallets/{id}", h.ReadWallet).Methods(http.MethodGet)
+	router.HandleFunc("/wallets/{id}/transactions", h.ListWalletTransactions).Methods(http.MethodGet)
+
+	router.HandleFunc("/wallets/{id}/invitations", h.InviteMember).Methods(http.MethodPost)
+	router.HandleFunc("/wallets/{id}/accept", h.AcceptInvitation).Methods(http.MethodPost)
+	router.HandleFunc("/wallets/{id}/decline", h.DeclineInvitation).Methods(http.MethodPost)
+	router.HandleFunc("/wallets/{id}/leave", h.LeaveWallet).Methods(http.MethodPost)
+	router.HandleFunc("/wallets/{id}/members/{user_id}", h.RemoveMember).Methods(http.MethodDelete)
+
+	return router
+}
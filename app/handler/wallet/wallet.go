@@ -0,0 +1,178 @@
+package handlerwallet
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	jsoniter "github.com/json-iterator/go"
+	usecasewallet "github.com/kevinsudut/wallet-system/app/usecase/wallet"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/response"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+func (h handler) CreateWallet(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("CreateWallet.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasewallet.CreateWalletRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("CreateWallet.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.CreateWallet(r.Context(), req)
+	if err != nil {
+		log.Errorln("CreateWallet.CreateWallet", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListWallets(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListWallets(r.Context(), usecasewallet.ListWalletsRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ListWallets.ListWallets", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ReadWallet(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ReadWallet(r.Context(), usecasewallet.ReadWalletRequest{
+		UserId:   context.GetAuth(r.Context()).Id,
+		WalletId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("ReadWallet.ReadWallet", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListWalletTransactions(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListWalletTransactions(r.Context(), usecasewallet.ListWalletTransactionsRequest{
+		UserId:   context.GetAuth(r.Context()).Id,
+		WalletId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("ListWalletTransactions.ListWalletTransactions", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("InviteMember.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasewallet.InviteMemberRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("InviteMember.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+	req.WalletId = mux.Vars(r)["id"]
+
+	resp, err := h.usecase.InviteMember(r.Context(), req)
+	if err != nil {
+		log.Errorln("InviteMember.InviteMember", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.RespondInvitation(r.Context(), usecasewallet.RespondInvitationRequest{
+		UserId:   context.GetAuth(r.Context()).Id,
+		WalletId: mux.Vars(r)["id"],
+		Accept:   true,
+	})
+	if err != nil {
+		log.Errorln("AcceptInvitation.RespondInvitation", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.RespondInvitation(r.Context(), usecasewallet.RespondInvitationRequest{
+		UserId:   context.GetAuth(r.Context()).Id,
+		WalletId: mux.Vars(r)["id"],
+		Accept:   false,
+	})
+	if err != nil {
+		log.Errorln("DeclineInvitation.RespondInvitation", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) LeaveWallet(w http.ResponseWriter, r *http.Request) {
+	userId := context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.RemoveMember(r.Context(), usecasewallet.RemoveMemberRequest{
+		UserId:   userId,
+		WalletId: mux.Vars(r)["id"],
+		MemberId: userId,
+	})
+	if err != nil {
+		log.Errorln("LeaveWallet.RemoveMember", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	resp, err := h.usecase.RemoveMember(r.Context(), usecasewallet.RemoveMemberRequest{
+		UserId:   context.GetAuth(r.Context()).Id,
+		WalletId: vars["id"],
+		MemberId: vars["user_id"],
+	})
+	if err != nil {
+		log.Errorln("RemoveMember.RemoveMember", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
@@ -0,0 +1,807 @@
+package handlerwallet
+
+import (
+	"bytes"
+	ctx "context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasewallet "github.com/kevinsudut/wallet-system/app/usecase/wallet"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMain(t *testing.M) {
+	log.Init()
+	os.Exit(t.Run())
+}
+
+func Test_handler_CreateWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/wallets", body).WithContext(ctx)
+		return r
+	}
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"name":"family"}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().CreateWallet(gomock.Any(), usecasewallet.CreateWalletRequest{
+						UserId: "id",
+						Name:   "family",
+					}).Return(usecasewallet.CreateWalletResponse{
+						Code: http.StatusCreated,
+						Wallet: usecasewallet.Wallet{
+							Id:     "wid",
+							Name:   "family",
+							Role:   "owner",
+							Status: "active",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.CreateWallet",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"name":"family"}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().CreateWallet(gomock.Any(), gomock.Any()).Return(usecasewallet.CreateWalletResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"name":1}`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CreateWallet(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListWallets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/wallets", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().ListWallets(gomock.Any(), usecasewallet.ListWalletsRequest{
+						UserId: "id",
+					}).Return(usecasewallet.ListWalletsResponse{
+						Code: http.StatusOK,
+						Data: []usecasewallet.Wallet{
+							{
+								Id:     "wid",
+								Name:   "family",
+								Role:   "owner",
+								Status: "active",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.ListWallets",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/wallets", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().ListWallets(gomock.Any(), gomock.Any()).Return(usecasewallet.ListWalletsResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListWallets(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ReadWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/wallets/wid", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().ReadWallet(gomock.Any(), usecasewallet.ReadWalletRequest{
+						UserId:   "id",
+						WalletId: "wid",
+					}).Return(usecasewallet.ReadWalletResponse{
+						Code: http.StatusOK,
+						Wallet: usecasewallet.Wallet{
+							Id:     "wid",
+							Name:   "family",
+							Role:   "owner",
+							Status: "active",
+						},
+						Members: []usecasewallet.Member{
+							{
+								UserId:   "id",
+								Username: "username",
+								Role:     "owner",
+								Status:   "active",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.ReadWallet",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().ReadWallet(gomock.Any(), gomock.Any()).Return(usecasewallet.ReadWalletResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ReadWallet(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListWalletTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/wallets/wid/transactions", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().ListWalletTransactions(gomock.Any(), usecasewallet.ListWalletTransactionsRequest{
+						UserId:   "id",
+						WalletId: "wid",
+					}).Return(usecasewallet.ListWalletTransactionsResponse{
+						Code: http.StatusOK,
+						Data: []usecasewallet.WalletTransaction{
+							{
+								Id:                   "hid",
+								InitiatedBy:          "username",
+								CounterpartyUsername: "username",
+								Amount:               10 * money.Unit,
+								Notes:                "Deposit money from member",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.ListWalletTransactions",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().ListWalletTransactions(gomock.Any(), gomock.Any()).Return(usecasewallet.ListWalletTransactionsResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListWalletTransactions(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_InviteMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/wallets/wid/invitations", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"username":"spenderusername","role":"spender","spend_limit":10}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().InviteMember(gomock.Any(), usecasewallet.InviteMemberRequest{
+						UserId:     "id",
+						WalletId:   "wid",
+						Username:   "spenderusername",
+						Role:       "spender",
+						SpendLimit: 10 * money.Unit,
+					}).Return(usecasewallet.InviteMemberResponse{
+						Code: http.StatusCreated,
+						Member: usecasewallet.Member{
+							UserId:     "sid",
+							Username:   "spenderusername",
+							Role:       "spender",
+							Status:     "invited",
+							SpendLimit: 10 * money.Unit,
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.InviteMember",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"username":"spenderusername","role":"spender","spend_limit":10}`)),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().InviteMember(gomock.Any(), gomock.Any()).Return(usecasewallet.InviteMemberResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(bytes.NewBufferString(`{"spend_limit":"10"}`)),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(handlertemplate.ErrReader{}),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.InviteMember(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_AcceptInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/wallets/wid/accept", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().RespondInvitation(gomock.Any(), usecasewallet.RespondInvitationRequest{
+						UserId:   "id",
+						WalletId: "wid",
+						Accept:   true,
+					}).Return(usecasewallet.RespondInvitationResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.RespondInvitation",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().RespondInvitation(gomock.Any(), gomock.Any()).Return(usecasewallet.RespondInvitationResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.AcceptInvitation(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_DeclineInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/wallets/wid/decline", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().RespondInvitation(gomock.Any(), usecasewallet.RespondInvitationRequest{
+						UserId:   "id",
+						WalletId: "wid",
+					}).Return(usecasewallet.RespondInvitationResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.RespondInvitation",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().RespondInvitation(gomock.Any(), gomock.Any()).Return(usecasewallet.RespondInvitationResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.DeclineInvitation(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_LeaveWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/wallets/wid/leave", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().RemoveMember(gomock.Any(), usecasewallet.RemoveMemberRequest{
+						UserId:   "id",
+						WalletId: "wid",
+						MemberId: "id",
+					}).Return(usecasewallet.RemoveMemberResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.RemoveMember",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().RemoveMember(gomock.Any(), gomock.Any()).Return(usecasewallet.RemoveMemberResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.LeaveWallet(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_RemoveMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWallet := usecasewallet.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func(body io.Reader) *http.Request {
+		r := httptest.NewRequest(http.MethodDelete, "/wallets/wid/members/sid", body).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid", "user_id": "sid"})
+	}
+
+	type fields struct {
+		usecase usecasewallet.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().RemoveMember(gomock.Any(), usecasewallet.RemoveMemberRequest{
+						UserId:   "id",
+						WalletId: "wid",
+						MemberId: "sid",
+					}).Return(usecasewallet.RemoveMemberResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error wallet.RemoveMember",
+			fields: fields{
+				usecase: mockUsecaseWallet,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(nil),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWallet.EXPECT().RemoveMember(gomock.Any(), gomock.Any()).Return(usecasewallet.RemoveMemberResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.RemoveMember(tt.args.w, tt.args.r)
+		})
+	}
+}
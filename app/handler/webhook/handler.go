@@ -0,0 +1,16 @@
+package handlerwebhook
+
+import (
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasewebhook "github.com/kevinsudut/wallet-system/app/usecase/webhook"
+)
+
+type handler struct {
+	usecase usecasewebhook.UsecaseItf
+}
+
+func Init(usecase usecasewebhook.UsecaseItf) handlertemplate.HandlerItf {
+	return &handler{
+		usecase: usecase,
+	}
+}
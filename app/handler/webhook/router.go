@@ -0,0 +1,17 @@
+package handlerwebhook
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h handler) RegisterHandlers(router *mux.Router) *mux.Router {
+	router.HandleFunc("/webhooks", h.CreateWebhookEndpoint).Methods(http.MethodPost)
+	router.HandleFunc("/webhooks", h.ListWebhookEndpoints).Methods(http.MethodGet)
+	router.HandleFunc("/webhooks/{id}", h.DeleteWebhookEndpoint).Methods(http.MethodDelete)
+	router.HandleFunc("/webhooks/{id}/deliveries", h.ListWebhookDeliveries).Methods(http.MethodGet)
+	router.HandleFunc("/webhooks/{id}/deliveries/{delivery_id}/redeliver", h.RedeliverWebhookDelivery).Methods(http.MethodPost)
+
+	return router
+}
@@ -0,0 +1,99 @@
+package handlerwebhook
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	jsoniter "github.com/json-iterator/go"
+	usecasewebhook "github.com/kevinsudut/wallet-system/app/usecase/webhook"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/helper/response"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+func (h handler) CreateWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Errorln("CreateWebhookEndpoint.ReadAll", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	var req usecasewebhook.CreateWebhookEndpointRequest
+
+	err = jsoniter.Unmarshal(body, &req)
+	if err != nil {
+		log.Errorln("CreateWebhookEndpoint.Unmarshal", err)
+		response.WriteErrorResponse(w, apperror.ErrInvalidRequest.Wrap(err))
+		return
+	}
+
+	req.UserId = context.GetAuth(r.Context()).Id
+
+	resp, err := h.usecase.CreateWebhookEndpoint(r.Context(), req)
+	if err != nil {
+		log.Errorln("CreateWebhookEndpoint.CreateWebhookEndpoint", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListWebhookEndpoints(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListWebhookEndpoints(r.Context(), usecasewebhook.ListWebhookEndpointsRequest{
+		UserId: context.GetAuth(r.Context()).Id,
+	})
+	if err != nil {
+		log.Errorln("ListWebhookEndpoints.ListWebhookEndpoints", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) DeleteWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.DeleteWebhookEndpoint(r.Context(), usecasewebhook.DeleteWebhookEndpointRequest{
+		UserId:            context.GetAuth(r.Context()).Id,
+		WebhookEndpointId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("DeleteWebhookEndpoint.DeleteWebhookEndpoint", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.ListWebhookDeliveries(r.Context(), usecasewebhook.ListWebhookDeliveriesRequest{
+		UserId:            context.GetAuth(r.Context()).Id,
+		WebhookEndpointId: mux.Vars(r)["id"],
+	})
+	if err != nil {
+		log.Errorln("ListWebhookDeliveries.ListWebhookDeliveries", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
+
+func (h handler) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.usecase.RedeliverWebhookDelivery(r.Context(), usecasewebhook.RedeliverWebhookDeliveryRequest{
+		UserId:            context.GetAuth(r.Context()).Id,
+		WebhookEndpointId: mux.Vars(r)["id"],
+		WebhookDeliveryId: mux.Vars(r)["delivery_id"],
+	})
+	if err != nil {
+		log.Errorln("RedeliverWebhookDelivery.RedeliverWebhookDelivery", err)
+		response.WriteErrorResponse(w, err)
+		return
+	}
+
+	response.WriteJsonResponse(w, resp.Code, resp)
+}
@@ -0,0 +1,456 @@
+package handlerwebhook
+
+import (
+	"bytes"
+	ctx "context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	handlertemplate "github.com/kevinsudut/wallet-system/app/handler/template"
+	usecasewebhook "github.com/kevinsudut/wallet-system/app/usecase/webhook"
+	"github.com/kevinsudut/wallet-system/pkg/helper/context"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMain(t *testing.M) {
+	log.Init()
+	os.Exit(t.Run())
+}
+
+func Test_handler_CreateWebhookEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWebhook := usecasewebhook.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasewebhook.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(`{"url":"https://example.com/webhooks","event_types":["BalanceCredited"]}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().CreateWebhookEndpoint(gomock.Any(), usecasewebhook.CreateWebhookEndpointRequest{
+						UserId:     "id",
+						Url:        "https://example.com/webhooks",
+						EventTypes: []string{"BalanceCredited"},
+					}).Return(usecasewebhook.CreateWebhookEndpointResponse{
+						Code: http.StatusCreated,
+						WebhookEndpoint: usecasewebhook.WebhookEndpoint{
+							Id:         "wid",
+							Url:        "https://example.com/webhooks",
+							EventTypes: []string{"BalanceCredited"},
+							Status:     "active",
+						},
+						Secret: "whsec_secret",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error webhook.CreateWebhookEndpoint",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(`{"url":"/webhooks"}`)).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().CreateWebhookEndpoint(gomock.Any(), usecasewebhook.CreateWebhookEndpointRequest{
+						UserId: "id",
+						Url:    "/webhooks",
+					}).Return(usecasewebhook.CreateWebhookEndpointResponse{
+						Code: http.StatusBadRequest,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error unmarshal",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(`{"event_types":"BalanceCredited"}`)).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+		{
+			name: "error read body",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodPost, "/webhooks", handlertemplate.ErrReader{}).WithContext(ctx),
+			},
+			mock: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.CreateWebhookEndpoint(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListWebhookEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWebhook := usecasewebhook.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	type fields struct {
+		usecase usecasewebhook.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/webhooks", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().ListWebhookEndpoints(gomock.Any(), usecasewebhook.ListWebhookEndpointsRequest{
+						UserId: "id",
+					}).Return(usecasewebhook.ListWebhookEndpointsResponse{
+						Code: http.StatusOK,
+						Data: []usecasewebhook.WebhookEndpoint{
+							{
+								Id:     "wid",
+								Status: "active",
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error webhook.ListWebhookEndpoints",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/webhooks", nil).WithContext(ctx),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().ListWebhookEndpoints(gomock.Any(), gomock.Any()).Return(usecasewebhook.ListWebhookEndpointsResponse{
+						Code: http.StatusBadGateway,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListWebhookEndpoints(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_DeleteWebhookEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWebhook := usecasewebhook.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodDelete, "/webhooks/wid", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasewebhook.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().DeleteWebhookEndpoint(gomock.Any(), usecasewebhook.DeleteWebhookEndpointRequest{
+						UserId:            "id",
+						WebhookEndpointId: "wid",
+					}).Return(usecasewebhook.DeleteWebhookEndpointResponse{
+						Code: http.StatusOK,
+						WebhookEndpoint: usecasewebhook.WebhookEndpoint{
+							Id:     "wid",
+							Status: "deleted",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error webhook.DeleteWebhookEndpoint",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().DeleteWebhookEndpoint(gomock.Any(), gomock.Any()).Return(usecasewebhook.DeleteWebhookEndpointResponse{
+						Code: http.StatusNotFound,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.DeleteWebhookEndpoint(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_ListWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWebhook := usecasewebhook.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/webhooks/wid/deliveries", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid"})
+	}
+
+	type fields struct {
+		usecase usecasewebhook.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().ListWebhookDeliveries(gomock.Any(), usecasewebhook.ListWebhookDeliveriesRequest{
+						UserId:            "id",
+						WebhookEndpointId: "wid",
+					}).Return(usecasewebhook.ListWebhookDeliveriesResponse{
+						Code: http.StatusOK,
+						Data: []usecasewebhook.WebhookDelivery{
+							{
+								Id:             "did",
+								EventId:        "eid",
+								EventType:      "BalanceCredited",
+								Status:         "dead",
+								Attempt:        10,
+								LastStatusCode: http.StatusInternalServerError,
+							},
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error webhook.ListWebhookDeliveries",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).Return(usecasewebhook.ListWebhookDeliveriesResponse{
+						Code: http.StatusNotFound,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.ListWebhookDeliveries(tt.args.w, tt.args.r)
+		})
+	}
+}
+
+func Test_handler_RedeliverWebhookDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecaseWebhook := usecasewebhook.NewMockUsecaseItf(ctrl)
+
+	ctx := context.SetAuth(ctx.Background(), entity.User{
+		Id:       "id",
+		Username: "username",
+	})
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/webhooks/wid/deliveries/did/redeliver", nil).WithContext(ctx)
+		return mux.SetURLVars(r, map[string]string{"id": "wid", "delivery_id": "did"})
+	}
+
+	type fields struct {
+		usecase usecasewebhook.UsecaseItf
+	}
+	type args struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().RedeliverWebhookDelivery(gomock.Any(), usecasewebhook.RedeliverWebhookDeliveryRequest{
+						UserId:            "id",
+						WebhookEndpointId: "wid",
+						WebhookDeliveryId: "did",
+					}).Return(usecasewebhook.RedeliverWebhookDeliveryResponse{
+						Code: http.StatusOK,
+						WebhookDelivery: usecasewebhook.WebhookDelivery{
+							Id:     "did",
+							Status: "pending",
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error webhook.RedeliverWebhookDelivery",
+			fields: fields{
+				usecase: mockUsecaseWebhook,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: newRequest(),
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockUsecaseWebhook.EXPECT().RedeliverWebhookDelivery(gomock.Any(), gomock.Any()).Return(usecasewebhook.RedeliverWebhookDeliveryResponse{
+						Code: http.StatusConflict,
+					}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler{
+				usecase: tt.fields.usecase,
+			}
+			tt.mock()
+			h.RedeliverWebhookDelivery(tt.args.w, tt.args.r)
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package usecaseaccount
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+// defaultCloseReason is recorded when a user closes their account without giving a reason.
+const defaultCloseReason = "closed by the user"
+
+var (
+	errUserNotFound          = apperror.New(http.StatusNotFound, "user_not_found", "user does not exist")
+	errRecipientNotFound     = apperror.New(http.StatusNotFound, "recipient_not_found", "recipient username does not exist")
+	errInvalidRecipient      = apperror.New(http.StatusBadRequest, "invalid_recipient", "balance must be swept to another user")
+	errInvalidStatus         = apperror.New(http.StatusBadRequest, "invalid_status", "status must be one of active, frozen or debit_blocked")
+	errInvalidReason         = apperror.New(http.StatusBadRequest, "invalid_reason", "reason is required")
+	errUserClosed            = apperror.New(http.StatusConflict, "user_closed", "user is closed and its status cannot change anymore")
+	errBalanceNotZero        = apperror.New(http.StatusConflict, "balance_not_zero", "balance must be zero or swept to another user, and no funds may be held, kept in pockets or in other currencies")
+	errAccountNotActive      = apperror.New(http.StatusForbidden, "account_not_active", "the status of the account does not allow this operation")
+	errCounterpartyNotActive = apperror.New(http.StatusUnprocessableEntity, "counterparty_not_active", "the other user of this operation cannot send or receive money")
+	errLastOwner             = apperror.New(http.StatusConflict, "last_owner", "account is the last owner of a shared wallet that still holds money")
+	errCurrencyMismatch      = apperror.New(http.StatusUnprocessableEntity, "currency_mismatch", "balance can only be swept to a user of the same currency")
+)
+
+// statuses maps the request values to their enum. Closing an account goes through CloseAccount, which settles its
+// balance first.
+var statuses = map[string]enum.UserStatus{
+	enum.USER_STATUS_ACTIVE.String():        enum.USER_STATUS_ACTIVE,
+	enum.USER_STATUS_FROZEN.String():        enum.USER_STATUS_FROZEN,
+	enum.USER_STATUS_DEBIT_BLOCKED.String(): enum.USER_STATUS_DEBIT_BLOCKED,
+}
+
+func newAccount(user entity.User) Account {
+	return Account{
+		Id:              user.Id,
+		Username:        user.Username,
+		Currency:        user.Currency,
+		Status:          enum.UserStatus(user.Status).String(),
+		StatusReason:    user.StatusReason,
+		StatusChangedAt: user.StatusChangedAt,
+	}
+}
+
+func (u usecase) ReadAccount(ctx context.Context, req ReadAccountRequest) (resp ReadAccountResponse, err error) {
+	user, err := u.auth.GetUserById(ctx, req.UserId)
+	if err == sql.ErrNoRows {
+		return ReadAccountResponse{
+			Code: http.StatusNotFound,
+		}, errUserNotFound
+	}
+	if err != nil {
+		log.Errorln("ReadAccount.GetUserById", err)
+		return ReadAccountResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return ReadAccountResponse{
+		Code:    http.StatusOK,
+		Account: newAccount(user),
+	}, nil
+}
+
+// CloseAccount closes the account of the user for good, sweeping its balance to another user when one is given.
+func (u usecase) CloseAccount(ctx context.Context, req CloseAccountRequest) (resp CloseAccountResponse, err error) {
+	var sweepToUserId string
+	if req.SweepToUsername != "" {
+		sweepTo, err := u.auth.GetUserByUsername(ctx, req.SweepToUsername)
+		if err == sql.ErrNoRows {
+			return CloseAccountResponse{
+				Code: http.StatusNotFound,
+			}, errRecipientNotFound
+		}
+		if err != nil {
+			log.Errorln("CloseAccount.GetUserByUsername", err)
+			return CloseAccountResponse{
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
+		}
+
+		if sweepTo.Id == req.UserId {
+			return CloseAccountResponse{
+				Code: http.StatusBadRequest,
+			}, errInvalidRecipient
+		}
+		sweepToUserId = sweepTo.Id
+	}
+
+	if req.Reason == "" {
+		req.Reason = defaultCloseReason
+	}
+
+	user, err := u.balance.CloseAccount(ctx, domainbalance.CloseAccountRequest{
+		UserId:        req.UserId,
+		SweepToUserId: sweepToUserId,
+		Reason:        req.Reason,
+		ActorId:       req.ActorId,
+		Admin:         req.Admin,
+	})
+	if err == sql.ErrNoRows {
+		return CloseAccountResponse{
+			Code: http.StatusNotFound,
+		}, errUserNotFound
+	}
+	var balanceNotZeroErr domainbalance.BalanceNotZeroError
+	if errors.As(err, &balanceNotZeroErr) {
+		return CloseAccountResponse{
+			Code: http.StatusConflict,
+		}, errBalanceNotZero.Wrap(balanceNotZeroErr).WithDetails(map[string]interface{}{
+			"balance":    balanceNotZeroErr.Balance,
+			"held":       balanceNotZeroErr.Held,
+			"pockets":    balanceNotZeroErr.Pockets,
+			"currencies": balanceNotZeroErr.Currencies,
+		})
+	}
+	var lastOwnerErr domainbalance.SharedWalletLastOwnerError
+	if errors.As(err, &lastOwnerErr) {
+		return CloseAccountResponse{
+			Code: http.StatusConflict,
+		}, errLastOwner.Wrap(lastOwnerErr).WithDetails(map[string]interface{}{
+			"wallet_ids": lastOwnerErr.WalletIds,
+		})
+	}
+	var currencyMismatchErr domainbalance.CurrencyMismatchError
+	if errors.As(err, &currencyMismatchErr) {
+		return CloseAccountResponse{
+			Code: http.StatusUnprocessableEntity,
+		}, errCurrencyMismatch.Wrap(currencyMismatchErr).WithDetails(map[string]interface{}{
+			"currency":    currencyMismatchErr.Currency,
+			"to_currency": currencyMismatchErr.ToCurrency,
+		})
+	}
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	if errors.As(err, &accountNotActiveErr) {
+		if accountNotActiveErr.UserId != req.UserId {
+			return CloseAccountResponse{
+				Code: http.StatusUnprocessableEntity,
+			}, errCounterpartyNotActive.Wrap(accountNotActiveErr)
+		}
+		if accountNotActiveErr.Status == int(enum.USER_STATUS_CLOSED) {
+			return CloseAccountResponse{
+				Code: http.StatusConflict,
+			}, errUserClosed.Wrap(accountNotActiveErr)
+		}
+
+		return CloseAccountResponse{
+			Code: http.StatusForbidden,
+		}, errAccountNotActive.Wrap(accountNotActiveErr).WithDetails(map[string]interface{}{
+			"status": enum.UserStatus(accountNotActiveErr.Status).String(),
+		})
+	}
+	if err != nil {
+		log.Errorln("CloseAccount.CloseAccount", err)
+		return CloseAccountResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	// The account is closed either way, a stale cache only delays the middleware from noticing it.
+	err = u.auth.DeleteUserCache(ctx, user)
+	if err != nil {
+		log.Errorln("CloseAccount.DeleteUserCache", err)
+	}
+
+	return CloseAccountResponse{
+		Code:    http.StatusOK,
+		Account: newAccount(user),
+	}, nil
+}
+
+func (u usecase) UpdateUserStatus(ctx context.Context, req UpdateUserStatusRequest) (resp UpdateUserStatusResponse, err error) {
+	status, ok := statuses[req.Status]
+	if !ok {
+		return UpdateUserStatusResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidStatus
+	}
+
+	if req.Reason == "" {
+		return UpdateUserStatusResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidReason
+	}
+
+	user, err := u.balance.UpdateUserStatus(ctx, domainbalance.UpdateUserStatusRequest{
+		UserId:  req.UserId,
+		Status:  status,
+		Reason:  req.Reason,
+		ActorId: req.ActorId,
+	})
+	if err == sql.ErrNoRows {
+		return UpdateUserStatusResponse{
+			Code: http.StatusNotFound,
+		}, errUserNotFound
+	}
+	if errors.Is(err, domainbalance.ErrUserClosed) {
+		return UpdateUserStatusResponse{
+			Code: http.StatusConflict,
+		}, errUserClosed.Wrap(err)
+	}
+	if err != nil {
+		log.Errorln("UpdateUserStatus.UpdateUserStatus", err)
+		return UpdateUserStatusResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	err = u.auth.DeleteUserCache(ctx, user)
+	if err != nil {
+		log.Errorln("UpdateUserStatus.DeleteUserCache", err)
+	}
+
+	return UpdateUserStatusResponse{
+		Code:    http.StatusOK,
+		Account: newAccount(user),
+	}, nil
+}
+
+func (u usecase) ListUserStatusChanges(ctx context.Context, req ListUserStatusChangesRequest) (resp ListUserStatusChangesResponse, err error) {
+	_, err = u.auth.GetUserById(ctx, req.UserId)
+	if err == sql.ErrNoRows {
+		return ListUserStatusChangesResponse{
+			Code: http.StatusNotFound,
+		}, errUserNotFound
+	}
+	if err != nil {
+		log.Errorln("ListUserStatusChanges.GetUserById", err)
+		return ListUserStatusChangesResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	changes, err := u.auth.GetUserStatusChanges(ctx, req.UserId)
+	if err != nil {
+		log.Errorln("ListUserStatusChanges.GetUserStatusChanges", err)
+		return ListUserStatusChangesResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListUserStatusChangesResponse{
+		Code: http.StatusOK,
+		Data: make([]UserStatusChange, 0, len(changes)),
+	}
+
+	for _, change := range changes {
+		resp.Data = append(resp.Data, UserStatusChange{
+			Id:         change.Id,
+			FromStatus: enum.UserStatus(change.FromStatus).String(),
+			ToStatus:   enum.UserStatus(change.ToStatus).String(),
+			Reason:     change.Reason,
+			ActorId:    change.ActorId,
+			CreatedAt:  change.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
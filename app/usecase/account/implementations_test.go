@@ -0,0 +1,899 @@
+package usecaseaccount
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	changedAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	user = entity.User{
+		Id:       "id",
+		Username: "username",
+		Status:   int(enum.USER_STATUS_ACTIVE),
+	}
+	sweepTo = entity.User{
+		Id:       "sweepid",
+		Username: "sweepusername",
+		Status:   int(enum.USER_STATUS_ACTIVE),
+	}
+	closedUser = entity.User{
+		Id:              "id",
+		Username:        "username",
+		Status:          int(enum.USER_STATUS_CLOSED),
+		StatusReason:    "closed by the user",
+		StatusChangedAt: &changedAt,
+	}
+	frozenUser = entity.User{
+		Id:              "id",
+		Username:        "username",
+		Status:          int(enum.USER_STATUS_FROZEN),
+		StatusReason:    "compromised",
+		StatusChangedAt: &changedAt,
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_usecase_ReadAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		auth domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ReadAccountRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ReadAccountResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadAccountRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ReadAccountResponse{
+				Code: http.StatusOK,
+				Account: Account{
+					Id:              "id",
+					Username:        "username",
+					Status:          "frozen",
+					StatusReason:    "compromised",
+					StatusChangedAt: &changedAt,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(frozenUser, nil),
+				)
+			},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadAccountRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ReadAccountResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error GetUserById",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadAccountRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ReadAccountResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth: tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.ReadAccount(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ReadAccount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ReadAccount() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_CloseAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	closedAccount := Account{
+		Id:              "id",
+		Username:        "username",
+		Status:          "closed",
+		StatusReason:    "closed by the user",
+		StatusChangedAt: &changedAt,
+	}
+
+	type fields struct {
+		auth    domainauth.DomainItf
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req CloseAccountRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CloseAccountResponse
+		wantErr  error
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:  "id",
+					ActorId: "id",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code:    http.StatusOK,
+				Account: closedAccount,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), domainbalance.CloseAccountRequest{
+						UserId:  "id",
+						Reason:  "closed by the user",
+						ActorId: "id",
+					}).Return(closedUser, nil),
+					mockDomainAuth.EXPECT().DeleteUserCache(gomock.Any(), closedUser).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success sweep by admin",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:          "id",
+					ActorId:         "adminid",
+					Admin:           true,
+					SweepToUsername: "sweepusername",
+					Reason:          "fraud",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code:    http.StatusOK,
+				Account: closedAccount,
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "sweepusername").Return(sweepTo, nil),
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), domainbalance.CloseAccountRequest{
+						UserId:        "id",
+						SweepToUserId: "sweepid",
+						Reason:        "fraud",
+						ActorId:       "adminid",
+						Admin:         true,
+					}).Return(closedUser, nil),
+					mockDomainAuth.EXPECT().DeleteUserCache(gomock.Any(), closedUser).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error recipient not found",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:          "id",
+					SweepToUsername: "sweepusername",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: errRecipientNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "sweepusername").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error GetUserByUsername",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:          "id",
+					SweepToUsername: "sweepusername",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: apperror.ErrDependency,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "sweepusername").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error sweep to self",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:          "id",
+					SweepToUsername: "username",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: errInvalidRecipient,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(user, nil),
+				)
+			},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: errUserNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error balance not zero",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: errBalanceNotZero,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(user, domainbalance.BalanceNotZeroError{Balance: 10, Held: 5}),
+				)
+			},
+		},
+		{
+			name: "error last owner of a shared wallet",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: errLastOwner,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(user, domainbalance.SharedWalletLastOwnerError{WalletIds: []string{"wid"}}),
+				)
+			},
+		},
+		{
+			name: "error account not active",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusForbidden,
+			},
+			wantErr: errAccountNotActive,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(frozenUser, domainbalance.AccountNotActiveError{UserId: "id", Status: int(enum.USER_STATUS_FROZEN)}),
+				)
+			},
+		},
+		{
+			name: "error account already closed",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId: "id",
+					Admin:  true,
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: errUserClosed,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(closedUser, domainbalance.AccountNotActiveError{UserId: "id", Status: int(enum.USER_STATUS_CLOSED)}),
+				)
+			},
+		},
+		{
+			name: "error sweep recipient not active",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:          "id",
+					SweepToUsername: "sweepusername",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: errCounterpartyNotActive,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "sweepusername").Return(sweepTo, nil),
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(user, domainbalance.AccountNotActiveError{UserId: "sweepid", Status: int(enum.USER_STATUS_FROZEN)}),
+				)
+			},
+		},
+		{
+			name: "error sweep currency mismatch",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId:          "id",
+					SweepToUsername: "sweepusername",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: errCurrencyMismatch,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "sweepusername").Return(sweepTo, nil),
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(user, domainbalance.CurrencyMismatchError{Currency: "IDR", ToCurrency: "USD"}),
+				)
+			},
+		},
+		{
+			name: "error CloseAccount",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CloseAccountRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: CloseAccountResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: apperror.ErrDependency,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CloseAccount(gomock.Any(), gomock.Any()).Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:    tt.fields.auth,
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.CloseAccount(tt.args.ctx, tt.args.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("usecase.CloseAccount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CloseAccount() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_UpdateUserStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		auth    domainauth.DomainItf
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req UpdateUserStatusRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp UpdateUserStatusResponse
+		wantErr  error
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					ActorId: "adminid",
+					Status:  "frozen",
+					Reason:  "compromised",
+				},
+			},
+			wantResp: UpdateUserStatusResponse{
+				Code: http.StatusOK,
+				Account: Account{
+					Id:              "id",
+					Username:        "username",
+					Status:          "frozen",
+					StatusReason:    "compromised",
+					StatusChangedAt: &changedAt,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().UpdateUserStatus(gomock.Any(), domainbalance.UpdateUserStatusRequest{
+						UserId:  "id",
+						Status:  enum.USER_STATUS_FROZEN,
+						Reason:  "compromised",
+						ActorId: "adminid",
+					}).Return(frozenUser, nil),
+					mockDomainAuth.EXPECT().DeleteUserCache(gomock.Any(), frozenUser).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success error auth.DeleteUserCache",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId:  "id",
+					ActorId: "adminid",
+					Status:  "frozen",
+					Reason:  "compromised",
+				},
+			},
+			wantResp: UpdateUserStatusResponse{
+				Code: http.StatusOK,
+				Account: Account{
+					Id:              "id",
+					Username:        "username",
+					Status:          "frozen",
+					StatusReason:    "compromised",
+					StatusChangedAt: &changedAt,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().UpdateUserStatus(gomock.Any(), gomock.Any()).Return(frozenUser, nil),
+					mockDomainAuth.EXPECT().DeleteUserCache(gomock.Any(), frozenUser).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error invalid status",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId: "id",
+					Status: "closed",
+					Reason: "compromised",
+				},
+			},
+			wantResp: UpdateUserStatusResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: errInvalidStatus,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid reason",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId: "id",
+					Status: "frozen",
+				},
+			},
+			wantResp: UpdateUserStatusResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: errInvalidReason,
+			mock:    func() {},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId: "id",
+					Status: "active",
+					Reason: "verified",
+				},
+			},
+			wantResp: UpdateUserStatusResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: errUserNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().UpdateUserStatus(gomock.Any(), gomock.Any()).Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error user closed",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId: "id",
+					Status: "active",
+					Reason: "verified",
+				},
+			},
+			wantResp: UpdateUserStatusResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: errUserClosed,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().UpdateUserStatus(gomock.Any(), gomock.Any()).Return(entity.User{}, domainbalance.ErrUserClosed),
+				)
+			},
+		},
+		{
+			name: "error UpdateUserStatus",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdateUserStatusRequest{
+					UserId: "id",
+					Status: "debit_blocked",
+					Reason: "chargeback",
+				},
+			},
+			wantResp: UpdateUserStatusResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: apperror.ErrDependency,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().UpdateUserStatus(gomock.Any(), gomock.Any()).Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:    tt.fields.auth,
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.UpdateUserStatus(tt.args.ctx, tt.args.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("usecase.UpdateUserStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.UpdateUserStatus() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListUserStatusChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		auth domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListUserStatusChangesRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListUserStatusChangesResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListUserStatusChangesRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListUserStatusChangesResponse{
+				Code: http.StatusOK,
+				Data: []UserStatusChange{
+					{
+						Id:         "changeid",
+						FromStatus: "active",
+						ToStatus:   "frozen",
+						Reason:     "compromised",
+						ActorId:    "adminid",
+						CreatedAt:  changedAt,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(frozenUser, nil),
+					mockDomainAuth.EXPECT().GetUserStatusChanges(gomock.Any(), "id").Return([]entity.UserStatusChange{
+						{
+							Id:         "changeid",
+							UserId:     "id",
+							FromStatus: int(enum.USER_STATUS_ACTIVE),
+							ToStatus:   int(enum.USER_STATUS_FROZEN),
+							Reason:     "compromised",
+							ActorId:    "adminid",
+							CreatedAt:  changedAt,
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success without changes",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListUserStatusChangesRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListUserStatusChangesResponse{
+				Code: http.StatusOK,
+				Data: []UserStatusChange{},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainAuth.EXPECT().GetUserStatusChanges(gomock.Any(), "id").Return(nil, nil),
+				)
+			},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListUserStatusChangesRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListUserStatusChangesResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error GetUserStatusChanges",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListUserStatusChangesRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListUserStatusChangesResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainAuth.EXPECT().GetUserStatusChanges(gomock.Any(), "id").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth: tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.ListUserStatusChanges(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListUserStatusChanges() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListUserStatusChanges() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
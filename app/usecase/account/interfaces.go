@@ -0,0 +1,11 @@
+package usecaseaccount
+
+import "context"
+
+type UsecaseItf interface {
+	ReadAccount(ctx context.Context, req ReadAccountRequest) (resp ReadAccountResponse, err error)
+	CloseAccount(ctx context.Context, req CloseAccountRequest) (resp CloseAccountResponse, err error)
+
+	UpdateUserStatus(ctx context.Context, req UpdateUserStatusRequest) (resp UpdateUserStatusResponse, err error)
+	ListUserStatusChanges(ctx context.Context, req ListUserStatusChangesRequest) (resp ListUserStatusChangesResponse, err error)
+}
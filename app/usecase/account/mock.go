@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/usecase/account/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/usecase/account/interfaces.go -destination=app/usecase/account/mock.go -package=usecaseaccount
+//
+
+// Package usecaseaccount is a generated GoMock package.
+package usecaseaccount
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsecaseItf is a mock of UsecaseItf interface.
+type MockUsecaseItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseItfMockRecorder
+}
+
+// MockUsecaseItfMockRecorder is the mock recorder for MockUsecaseItf.
+type MockUsecaseItfMockRecorder struct {
+	mock *MockUsecaseItf
+}
+
+// NewMockUsecaseItf creates a new mock instance.
+func NewMockUsecaseItf(ctrl *gomock.Controller) *MockUsecaseItf {
+	mock := &MockUsecaseItf{ctrl: ctrl}
+	mock.recorder = &MockUsecaseItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
+	return m.recorder
+}
+
+// CloseAccount mocks base method.
+func (m *MockUsecaseItf) CloseAccount(ctx context.Context, req CloseAccountRequest) (CloseAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccount", ctx, req)
+	ret0, _ := ret[0].(CloseAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockUsecaseItfMockRecorder) CloseAccount(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockUsecaseItf)(nil).CloseAccount), ctx, req)
+}
+
+// ListUserStatusChanges mocks base method.
+func (m *MockUsecaseItf) ListUserStatusChanges(ctx context.Context, req ListUserStatusChangesRequest) (ListUserStatusChangesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserStatusChanges", ctx, req)
+	ret0, _ := ret[0].(ListUserStatusChangesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserStatusChanges indicates an expected call of ListUserStatusChanges.
+func (mr *MockUsecaseItfMockRecorder) ListUserStatusChanges(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserStatusChanges", reflect.TypeOf((*MockUsecaseItf)(nil).ListUserStatusChanges), ctx, req)
+}
+
+// ReadAccount mocks base method.
+func (m *MockUsecaseItf) ReadAccount(ctx context.Context, req ReadAccountRequest) (ReadAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAccount", ctx, req)
+	ret0, _ := ret[0].(ReadAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAccount indicates an expected call of ReadAccount.
+func (mr *MockUsecaseItfMockRecorder) ReadAccount(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAccount", reflect.TypeOf((*MockUsecaseItf)(nil).ReadAccount), ctx, req)
+}
+
+// UpdateUserStatus mocks base method.
+func (m *MockUsecaseItf) UpdateUserStatus(ctx context.Context, req UpdateUserStatusRequest) (UpdateUserStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserStatus", ctx, req)
+	ret0, _ := ret[0].(UpdateUserStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserStatus indicates an expected call of UpdateUserStatus.
+func (mr *MockUsecaseItfMockRecorder) UpdateUserStatus(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*MockUsecaseItf)(nil).UpdateUserStatus), ctx, req)
+}
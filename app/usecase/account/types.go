@@ -0,0 +1,68 @@
+package usecaseaccount
+
+import (
+	"time"
+)
+
+type ReadAccountRequest struct {
+	UserId string
+}
+
+type ReadAccountResponse struct {
+	Code int `json:"-"`
+	Account
+}
+
+// CloseAccountRequest closes the account of UserId on behalf of ActorId. A remaining balance is swept to
+// SweepToUsername. Admin skips the checks on the status of the user, so a frozen account can still be closed.
+type CloseAccountRequest struct {
+	UserId          string
+	ActorId         string
+	Admin           bool
+	SweepToUsername string `json:"sweep_to_username"`
+	Reason          string `json:"reason"`
+}
+
+type CloseAccountResponse struct {
+	Code int `json:"-"`
+	Account
+}
+
+type UpdateUserStatusRequest struct {
+	UserId  string
+	ActorId string
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+}
+
+type UpdateUserStatusResponse struct {
+	Code int `json:"-"`
+	Account
+}
+
+type ListUserStatusChangesRequest struct {
+	UserId string
+}
+
+type ListUserStatusChangesResponse struct {
+	Code int                `json:"-"`
+	Data []UserStatusChange `json:"data"`
+}
+
+type Account struct {
+	Id              string     `json:"id"`
+	Username        string     `json:"username"`
+	Currency        string     `json:"currency"`
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+}
+
+type UserStatusChange struct {
+	Id         string    `json:"id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ActorId    string    `json:"actor_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package usecaseaccount
+
+import (
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+)
+
+type usecase struct {
+	auth    domainauth.DomainItf
+	balance domainbalance.DomainItf
+}
+
+func Init(auth domainauth.DomainItf, balance domainbalance.DomainItf) UsecaseItf {
+	return &usecase{
+		auth:    auth,
+		balance: balance,
+	}
+}
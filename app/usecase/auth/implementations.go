@@ -8,48 +8,378 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/currency"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	errPasswordTooShort    = apperror.New(http.StatusBadRequest, "password_too_short", fmt.Sprintf("password must be at least %d characters", minPasswordLength))
+	errUsernameTaken       = apperror.New(http.StatusConflict, "username_taken", "username already exists")
+	errInvalidCredential   = apperror.New(http.StatusUnauthorized, "invalid_credentials", "invalid username or password")
+	errAccountLocked       = apperror.New(http.StatusLocked, "account_locked", "account is locked due to too many failed login attempts")
+	errInvalidRefreshToken = apperror.New(http.StatusUnauthorized, "invalid_refresh_token", "invalid refresh token")
+	errAccountClosed       = apperror.New(http.StatusForbidden, "account_closed", "account is closed")
+	errInvalidCurrency     = apperror.New(http.StatusBadRequest, "invalid_currency", "currency must be a supported ISO 4217 code")
+	errUserNotFound        = apperror.New(http.StatusNotFound, "user_not_found", "user does not exist")
+	errPasswordAlreadySet  = apperror.New(http.StatusConflict, "password_already_set", "user already has a password")
 )
 
 func (u usecase) RegisterUser(ctx context.Context, req RegisterUserRequest) (resp RegisterUserResponse, err error) {
+	if len(req.Password) < minPasswordLength {
+		return RegisterUserResponse{
+			Code: http.StatusBadRequest,
+		}, errPasswordTooShort
+	}
+
+	userCurrency := req.Currency
+	if userCurrency == "" {
+		userCurrency = currency.Default()
+	}
+	if !currency.Valid(userCurrency) {
+		return RegisterUserResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidCurrency
+	}
+
 	user, err := u.auth.GetUserByUsername(ctx, req.Username)
 	if err != nil && err != sql.ErrNoRows {
 		log.Errorln("RegisterUser.GetUserByUsername", err)
 		return RegisterUserResponse{
 			Code: http.StatusBadGateway,
-		}, err
+		}, apperror.ErrDependency.Wrap(err)
 	}
 
 	if user.Id != "" {
 		return RegisterUserResponse{
 			Code: http.StatusConflict,
-		}, fmt.Errorf("username already exists")
+		}, errUsernameTaken
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Errorln("RegisterUser.GenerateFromPassword", err)
+		return RegisterUserResponse{
+			Code: http.StatusInternalServerError,
+		}, apperror.ErrInternal.Wrap(err)
 	}
 
 	user = entity.User{
 		Id:       uuid.NewString(),
 		Username: req.Username,
+		Role:     int(enum.USER_ROLE_USER),
+		Status:   int(enum.USER_STATUS_ACTIVE),
+		Currency: userCurrency,
 	}
 
-	err = u.auth.InsertUser(ctx, user)
+	err = u.auth.InsertUser(ctx, user, entity.UserCredential{
+		UserId:       user.Id,
+		PasswordHash: string(passwordHash),
+	})
 	if err != nil {
 		log.Errorln("RegisterUser.InsertUser", err)
 		return RegisterUserResponse{
 			Code: http.StatusBadGateway,
-		}, err
+		}, apperror.ErrDependency.Wrap(err)
 	}
 
-	token, err := u.token.Create(time.Hour, user)
+	token, refreshToken, err := u.issueTokens(ctx, user)
 	if err != nil {
-		log.Errorln("RegisterUser.Create", err)
+		log.Errorln("RegisterUser.issueTokens", err)
 		return RegisterUserResponse{
 			Code: http.StatusBadGateway,
-		}, err
+		}, apperror.ErrDependency.Wrap(err)
 	}
 
 	return RegisterUserResponse{
-		Code:  http.StatusCreated,
-		Token: token,
+		Code:         http.StatusCreated,
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (u usecase) Login(ctx context.Context, req LoginRequest) (resp LoginResponse, err error) {
+	user, err := u.auth.GetUserByUsername(ctx, req.Username)
+	if err == sql.ErrNoRows {
+		return LoginResponse{
+			Code: http.StatusUnauthorized,
+		}, errInvalidCredential
+	}
+	if err != nil {
+		log.Errorln("Login.GetUserByUsername", err)
+		return LoginResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	attempts, err := u.auth.GetFailedLoginAttempt(ctx, user.Id)
+	if err != nil {
+		log.Errorln("Login.GetFailedLoginAttempt", err)
+		return LoginResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	if attempts >= u.maxFailedLoginAttempts {
+		return LoginResponse{
+			Code: http.StatusLocked,
+		}, errAccountLocked
+	}
+
+	credential, err := u.auth.GetUserCredentialByUserId(ctx, user.Id)
+	if err == sql.ErrNoRows {
+		return LoginResponse{
+			Code: http.StatusUnauthorized,
+		}, errInvalidCredential
+	}
+	if err != nil {
+		log.Errorln("Login.GetUserCredentialByUserId", err)
+		return LoginResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(credential.PasswordHash), []byte(req.Password))
+	if err != nil {
+		_, err = u.auth.IncrFailedLoginAttempt(ctx, user.Id, u.loginLockoutDuration)
+		if err != nil {
+			log.Errorln("Login.IncrFailedLoginAttempt", err)
+		}
+
+		return LoginResponse{
+			Code: http.StatusUnauthorized,
+		}, errInvalidCredential
+	}
+
+	err = u.auth.DeleteFailedLoginAttempt(ctx, user.Id)
+	if err != nil {
+		log.Errorln("Login.DeleteFailedLoginAttempt", err)
+	}
+
+	// Checked after the password, so only the owner learns that the account is closed.
+	if user.IsClosed() {
+		return LoginResponse{
+			Code: http.StatusForbidden,
+		}, errAccountClosed
+	}
+
+	token, refreshToken, err := u.issueTokens(ctx, user)
+	if err != nil {
+		log.Errorln("Login.issueTokens", err)
+		return LoginResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return LoginResponse{
+		Code:         http.StatusOK,
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// SetUserPassword lets an admin give a user registered before passwords existed a password, which is the only way
+// for them to log in. A password that is already set is never replaced.
+func (u usecase) SetUserPassword(ctx context.Context, req SetUserPasswordRequest) (resp SetUserPasswordResponse, err error) {
+	if len(req.Password) < minPasswordLength {
+		return SetUserPasswordResponse{
+			Code: http.StatusBadRequest,
+		}, errPasswordTooShort
+	}
+
+	_, err = u.auth.GetUserById(ctx, req.UserId)
+	if err == sql.ErrNoRows {
+		return SetUserPasswordResponse{
+			Code: http.StatusNotFound,
+		}, errUserNotFound
+	}
+	if err != nil {
+		log.Errorln("SetUserPassword.GetUserById", err)
+		return SetUserPasswordResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Errorln("SetUserPassword.GenerateFromPassword", err)
+		return SetUserPasswordResponse{
+			Code: http.StatusInternalServerError,
+		}, apperror.ErrInternal.Wrap(err)
+	}
+
+	err = u.auth.InsertMissingUserCredential(ctx, entity.UserCredential{
+		UserId:       req.UserId,
+		PasswordHash: string(passwordHash),
+	})
+	if err == domainauth.ErrCredentialExists {
+		return SetUserPasswordResponse{
+			Code: http.StatusConflict,
+		}, errPasswordAlreadySet
+	}
+	if err != nil {
+		log.Errorln("SetUserPassword.InsertMissingUserCredential", err)
+		return SetUserPasswordResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return SetUserPasswordResponse{
+		Code: http.StatusCreated,
 	}, nil
 }
+
+func (u usecase) RefreshToken(ctx context.Context, req RefreshTokenRequest) (resp RefreshTokenResponse, err error) {
+	refreshToken, err := u.auth.GetRefreshTokenByTokenHash(ctx, hashRefreshToken(req.RefreshToken))
+	if err == sql.ErrNoRows {
+		return RefreshTokenResponse{
+			Code: http.StatusUnauthorized,
+		}, errInvalidRefreshToken
+	}
+	if err != nil {
+		log.Errorln("RefreshToken.GetRefreshTokenByTokenHash", err)
+		return RefreshTokenResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	if refreshToken.RevokedAt != nil || refreshToken.IsExpired(time.Now()) {
+		return RefreshTokenResponse{
+			Code: http.StatusUnauthorized,
+		}, errInvalidRefreshToken
+	}
+
+	if refreshToken.RotatedAt != nil {
+		return u.revokeRefreshTokenFamily(ctx, refreshToken)
+	}
+
+	user, err := u.auth.GetUserById(ctx, refreshToken.UserId)
+	if err != nil {
+		log.Errorln("RefreshToken.GetUserById", err)
+		return RefreshTokenResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	if user.IsClosed() {
+		return RefreshTokenResponse{
+			Code: http.StatusForbidden,
+		}, errAccountClosed
+	}
+
+	newToken, newRefreshTokenEntity, err := newRefreshToken(user.Id, refreshToken.FamilyId)
+	if err != nil {
+		log.Errorln("RefreshToken.newRefreshToken", err)
+		return RefreshTokenResponse{
+			Code: http.StatusInternalServerError,
+		}, apperror.ErrInternal.Wrap(err)
+	}
+
+	err = u.auth.RotateRefreshToken(ctx, refreshToken.Id, newRefreshTokenEntity)
+	if err == domainauth.ErrRefreshTokenReused {
+		return u.revokeRefreshTokenFamily(ctx, refreshToken)
+	}
+	if err != nil {
+		log.Errorln("RefreshToken.RotateRefreshToken", err)
+		return RefreshTokenResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	token, err := u.token.Create(accessTokenTTL, user)
+	if err != nil {
+		log.Errorln("RefreshToken.Create", err)
+		return RefreshTokenResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return RefreshTokenResponse{
+		Code:         http.StatusOK,
+		Token:        token,
+		RefreshToken: newToken,
+	}, nil
+}
+
+// revokeRefreshTokenFamily handles reuse of an already rotated refresh token. The token may have been stolen,
+// so every refresh token issued from the same login is revoked and the client has to log in again.
+func (u usecase) revokeRefreshTokenFamily(ctx context.Context, refreshToken entity.RefreshToken) (resp RefreshTokenResponse, err error) {
+	err = u.auth.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyId)
+	if err != nil {
+		log.Errorln("RefreshToken.RevokeRefreshTokenFamily", err)
+		return RefreshTokenResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return RefreshTokenResponse{
+		Code: http.StatusUnauthorized,
+	}, errInvalidRefreshToken
+}
+
+func (u usecase) Logout(ctx context.Context, req LogoutRequest) (resp LogoutResponse, err error) {
+	err = u.auth.RevokeAccessToken(ctx, req.AccessTokenId, time.Until(req.AccessTokenExpires))
+	if err != nil {
+		log.Errorln("Logout.RevokeAccessToken", err)
+		return LogoutResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	if req.RefreshToken == "" {
+		return LogoutResponse{
+			Code: http.StatusNoContent,
+		}, nil
+	}
+
+	refreshToken, err := u.auth.GetRefreshTokenByTokenHash(ctx, hashRefreshToken(req.RefreshToken))
+	if err == sql.ErrNoRows || (err == nil && refreshToken.UserId != req.UserId) {
+		return LogoutResponse{
+			Code: http.StatusNoContent,
+		}, nil
+	}
+	if err != nil {
+		log.Errorln("Logout.GetRefreshTokenByTokenHash", err)
+		return LogoutResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	err = u.auth.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyId)
+	if err != nil {
+		log.Errorln("Logout.RevokeRefreshTokenFamily", err)
+		return LogoutResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return LogoutResponse{
+		Code: http.StatusNoContent,
+	}, nil
+}
+
+func (u usecase) IsAccessTokenRevoked(ctx context.Context, id string) (resp bool, err error) {
+	return u.auth.IsAccessTokenRevoked(ctx, id)
+}
+
+// GetAuthUser returns the current state of the user of an access token, which carries the user as it was at login.
+func (u usecase) GetAuthUser(ctx context.Context, id string) (resp entity.User, err error) {
+	resp, err = u.auth.GetUserById(ctx, id)
+	if err == sql.ErrNoRows {
+		return resp, apperror.ErrUnauthorized
+	}
+	if err != nil {
+		log.Errorln("GetAuthUser.GetUserById", err)
+		return resp, apperror.ErrDependency.Wrap(err)
+	}
+
+	if resp.IsClosed() {
+		return resp, errAccountClosed
+	}
+
+	return resp, nil
+}
@@ -3,6 +3,7 @@ package usecaseauth
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,9 +13,16 @@ import (
 
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 	"github.com/kevinsudut/wallet-system/pkg/lib/token"
 	gomock "go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	passwordHash, _ = bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
 )
 
 func TestMain(m *testing.M) {
@@ -54,18 +62,21 @@ func Test_usecase_RegisterUser(t *testing.T) {
 				ctx: context.Background(),
 				req: RegisterUserRequest{
 					Username: "username",
+					Password: "password",
 				},
 			},
 			wantResp: RegisterUserResponse{
-				Code:  http.StatusCreated,
-				Token: "token",
+				Code:         http.StatusCreated,
+				Token:        "token",
+				RefreshToken: "refresh_token",
 			},
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
 					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{}, sql.ErrNoRows),
-					mockDomainAuth.EXPECT().InsertUser(gomock.Any(), gomock.Any()).Return(nil),
-					mockToken.EXPECT().Create(time.Hour, gomock.Any()).Return("token", nil),
+					mockDomainAuth.EXPECT().InsertUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockToken.EXPECT().Create(accessTokenTTL, gomock.Any()).Return("token", nil),
+					mockDomainAuth.EXPECT().InsertRefreshToken(gomock.Any(), gomock.Any()).Return(nil),
 				)
 			},
 		},
@@ -79,6 +90,7 @@ func Test_usecase_RegisterUser(t *testing.T) {
 				ctx: context.Background(),
 				req: RegisterUserRequest{
 					Username: "username",
+					Password: "password",
 				},
 			},
 			wantResp: RegisterUserResponse{
@@ -88,8 +100,8 @@ func Test_usecase_RegisterUser(t *testing.T) {
 			mock: func() {
 				gomock.InOrder(
 					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{}, sql.ErrNoRows),
-					mockDomainAuth.EXPECT().InsertUser(gomock.Any(), gomock.Any()).Return(nil),
-					mockToken.EXPECT().Create(time.Hour, gomock.Any()).Return("", fmt.Errorf("foo")),
+					mockDomainAuth.EXPECT().InsertUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+					mockToken.EXPECT().Create(accessTokenTTL, gomock.Any()).Return("", fmt.Errorf("foo")),
 				)
 			},
 		},
@@ -103,6 +115,7 @@ func Test_usecase_RegisterUser(t *testing.T) {
 				ctx: context.Background(),
 				req: RegisterUserRequest{
 					Username: "username",
+					Password: "password",
 				},
 			},
 			wantResp: RegisterUserResponse{
@@ -112,7 +125,7 @@ func Test_usecase_RegisterUser(t *testing.T) {
 			mock: func() {
 				gomock.InOrder(
 					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{}, sql.ErrNoRows),
-					mockDomainAuth.EXPECT().InsertUser(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockDomainAuth.EXPECT().InsertUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
 				)
 			},
 		},
@@ -126,6 +139,7 @@ func Test_usecase_RegisterUser(t *testing.T) {
 				ctx: context.Background(),
 				req: RegisterUserRequest{
 					Username: "username",
+					Password: "password",
 				},
 			},
 			wantResp: RegisterUserResponse{
@@ -148,6 +162,7 @@ func Test_usecase_RegisterUser(t *testing.T) {
 				ctx: context.Background(),
 				req: RegisterUserRequest{
 					Username: "username",
+					Password: "password",
 				},
 			},
 			wantResp: RegisterUserResponse{
@@ -163,6 +178,76 @@ func Test_usecase_RegisterUser(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "error password too short",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RegisterUserRequest{
+					Username: "username",
+					Password: "short",
+				},
+			},
+			wantResp: RegisterUserResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "success with currency",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RegisterUserRequest{
+					Username: "username",
+					Password: "password",
+					Currency: "USD",
+				},
+			},
+			wantResp: RegisterUserResponse{
+				Code:         http.StatusCreated,
+				Token:        "token",
+				RefreshToken: "refresh_token",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{}, sql.ErrNoRows),
+					mockDomainAuth.EXPECT().InsertUser(gomock.Any(), gomock.Cond(func(x any) bool {
+						return x.(entity.User).Currency == "USD"
+					}), gomock.Any()).Return(nil),
+					mockToken.EXPECT().Create(accessTokenTTL, gomock.Any()).Return("token", nil),
+					mockDomainAuth.EXPECT().InsertRefreshToken(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error invalid currency",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RegisterUserRequest{
+					Username: "username",
+					Password: "password",
+					Currency: "XYZ",
+				},
+			},
+			wantResp: RegisterUserResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -176,9 +261,1132 @@ func Test_usecase_RegisterUser(t *testing.T) {
 				t.Errorf("usecase.RegisterUser() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			// Refresh tokens are random, only check that one was issued.
+			if (gotResp.RefreshToken != "") != (tt.wantResp.RefreshToken != "") {
+				t.Errorf("usecase.RegisterUser() refresh token = %v, want %v", gotResp.RefreshToken, tt.wantResp.RefreshToken)
+			}
+			gotResp.RefreshToken = tt.wantResp.RefreshToken
 			if !reflect.DeepEqual(gotResp, tt.wantResp) {
 				t.Errorf("usecase.RegisterUser() = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
 	}
 }
+
+func Test_usecase_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockToken := token.NewMockTokenItf(ctrl)
+
+	type fields struct {
+		auth                   domainauth.DomainItf
+		token                  token.TokenItf
+		maxFailedLoginAttempts int64
+		loginLockoutDuration   time.Duration
+	}
+	type args struct {
+		ctx context.Context
+		req LoginRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp LoginResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code:         http.StatusOK,
+				Token:        "token",
+				RefreshToken: "refresh_token",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), nil),
+					mockDomainAuth.EXPECT().GetUserCredentialByUserId(gomock.Any(), "id").Return(entity.UserCredential{UserId: "id", PasswordHash: string(passwordHash)}, nil),
+					mockDomainAuth.EXPECT().DeleteFailedLoginAttempt(gomock.Any(), "id").Return(nil),
+					mockToken.EXPECT().Create(accessTokenTTL, entity.User{Id: "id", Username: "username"}).Return("token", nil),
+					mockDomainAuth.EXPECT().InsertRefreshToken(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error account closed",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusForbidden,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username", Status: int(enum.USER_STATUS_CLOSED)}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), nil),
+					mockDomainAuth.EXPECT().GetUserCredentialByUserId(gomock.Any(), "id").Return(entity.UserCredential{UserId: "id", PasswordHash: string(passwordHash)}, nil),
+					mockDomainAuth.EXPECT().DeleteFailedLoginAttempt(gomock.Any(), "id").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success error auth.DeleteFailedLoginAttempt",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code:         http.StatusOK,
+				Token:        "token",
+				RefreshToken: "refresh_token",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), nil),
+					mockDomainAuth.EXPECT().GetUserCredentialByUserId(gomock.Any(), "id").Return(entity.UserCredential{UserId: "id", PasswordHash: string(passwordHash)}, nil),
+					mockDomainAuth.EXPECT().DeleteFailedLoginAttempt(gomock.Any(), "id").Return(fmt.Errorf("foo")),
+					mockToken.EXPECT().Create(accessTokenTTL, entity.User{Id: "id", Username: "username"}).Return("token", nil),
+					mockDomainAuth.EXPECT().InsertRefreshToken(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error token.Create",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), nil),
+					mockDomainAuth.EXPECT().GetUserCredentialByUserId(gomock.Any(), "id").Return(entity.UserCredential{UserId: "id", PasswordHash: string(passwordHash)}, nil),
+					mockDomainAuth.EXPECT().DeleteFailedLoginAttempt(gomock.Any(), "id").Return(nil),
+					mockToken.EXPECT().Create(accessTokenTTL, entity.User{Id: "id", Username: "username"}).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error wrong password",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "wrong-password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusUnauthorized,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), nil),
+					mockDomainAuth.EXPECT().GetUserCredentialByUserId(gomock.Any(), "id").Return(entity.UserCredential{UserId: "id", PasswordHash: string(passwordHash)}, nil),
+					mockDomainAuth.EXPECT().IncrFailedLoginAttempt(gomock.Any(), "id", time.Minute*15).Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "error wrong password auth.IncrFailedLoginAttempt",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "wrong-password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusUnauthorized,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), nil),
+					mockDomainAuth.EXPECT().GetUserCredentialByUserId(gomock.Any(), "id").Return(entity.UserCredential{UserId: "id", PasswordHash: string(passwordHash)}, nil),
+					mockDomainAuth.EXPECT().IncrFailedLoginAttempt(gomock.Any(), "id", time.Minute*15).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error credential not found",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusUnauthorized,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), nil),
+					mockDomainAuth.EXPECT().GetUserCredentialByUserId(gomock.Any(), "id").Return(entity.UserCredential{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserCredentialByUserId",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), nil),
+					mockDomainAuth.EXPECT().GetUserCredentialByUserId(gomock.Any(), "id").Return(entity.UserCredential{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error account locked",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusLocked,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(5), nil),
+				)
+			},
+		},
+		{
+			name: "error auth.GetFailedLoginAttempt",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetFailedLoginAttempt(gomock.Any(), "id").Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error username not found",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusUnauthorized,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserByUsername",
+			fields: fields{
+				auth:                   mockDomainAuth,
+				token:                  mockToken,
+				maxFailedLoginAttempts: 5,
+				loginLockoutDuration:   time.Minute * 15,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LoginRequest{
+					Username: "username",
+					Password: "password",
+				},
+			},
+			wantResp: LoginResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:                   tt.fields.auth,
+				token:                  tt.fields.token,
+				maxFailedLoginAttempts: tt.fields.maxFailedLoginAttempts,
+				loginLockoutDuration:   tt.fields.loginLockoutDuration,
+			}
+			tt.mock()
+			gotResp, err := u.Login(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.Login() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// Refresh tokens are random, only check that one was issued.
+			if (gotResp.RefreshToken != "") != (tt.wantResp.RefreshToken != "") {
+				t.Errorf("usecase.Login() refresh token = %v, want %v", gotResp.RefreshToken, tt.wantResp.RefreshToken)
+			}
+			gotResp.RefreshToken = tt.wantResp.RefreshToken
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.Login() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_SetUserPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		auth domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req SetUserPasswordRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp SetUserPasswordResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SetUserPasswordRequest{
+					UserId:   "id",
+					Password: "password",
+				},
+			},
+			wantResp: SetUserPasswordResponse{
+				Code: http.StatusCreated,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id"}, nil),
+					mockDomainAuth.EXPECT().InsertMissingUserCredential(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error password too short",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SetUserPasswordRequest{
+					UserId:   "id",
+					Password: "pass",
+				},
+			},
+			wantResp: SetUserPasswordResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SetUserPasswordRequest{
+					UserId:   "id",
+					Password: "password",
+				},
+			},
+			wantResp: SetUserPasswordResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SetUserPasswordRequest{
+					UserId:   "id",
+					Password: "password",
+				},
+			},
+			wantResp: SetUserPasswordResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error password already set",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SetUserPasswordRequest{
+					UserId:   "id",
+					Password: "password",
+				},
+			},
+			wantResp: SetUserPasswordResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id"}, nil),
+					mockDomainAuth.EXPECT().InsertMissingUserCredential(gomock.Any(), gomock.Any()).Return(domainauth.ErrCredentialExists),
+				)
+			},
+		},
+		{
+			name: "error auth.InsertMissingUserCredential",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SetUserPasswordRequest{
+					UserId:   "id",
+					Password: "password",
+				},
+			},
+			wantResp: SetUserPasswordResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id"}, nil),
+					mockDomainAuth.EXPECT().InsertMissingUserCredential(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth: tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.SetUserPassword(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.SetUserPassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.SetUserPassword() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockToken := token.NewMockTokenItf(ctrl)
+
+	now := time.Now()
+
+	type fields struct {
+		auth  domainauth.DomainItf
+		token token.TokenItf
+	}
+	type args struct {
+		ctx context.Context
+		req RefreshTokenRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp RefreshTokenResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusOK, Token: "token", RefreshToken: "refresh_token"},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour)}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().RotateRefreshToken(gomock.Any(), "rt", gomock.Any()).Return(nil),
+					mockToken.EXPECT().Create(accessTokenTTL, entity.User{Id: "id", Username: "username"}).Return("token", nil),
+				)
+			},
+		},
+		{
+			name: "error account closed",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusForbidden},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour)}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username", Status: int(enum.USER_STATUS_CLOSED)}, nil),
+				)
+			},
+		},
+		{
+			name: "error token.Create",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusBadGateway},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour)}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().RotateRefreshToken(gomock.Any(), "rt", gomock.Any()).Return(nil),
+					mockToken.EXPECT().Create(accessTokenTTL, entity.User{Id: "id", Username: "username"}).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error auth.RotateRefreshToken",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusBadGateway},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour)}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().RotateRefreshToken(gomock.Any(), "rt", gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error concurrent reuse revokes family",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusUnauthorized},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour)}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().RotateRefreshToken(gomock.Any(), "rt", gomock.Any()).Return(domainauth.ErrRefreshTokenReused),
+					mockDomainAuth.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusBadGateway},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour)}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error reuse revokes family",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusUnauthorized},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour), RotatedAt: &now}, nil),
+					mockDomainAuth.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error reuse auth.RevokeRefreshTokenFamily",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusBadGateway},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour), RotatedAt: &now}, nil),
+					mockDomainAuth.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error revoked",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusUnauthorized},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &now}, nil),
+				)
+			},
+		},
+		{
+			name: "error expired",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusUnauthorized},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(-time.Hour)}, nil),
+				)
+			},
+		},
+		{
+			name: "error not found",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusUnauthorized},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetRefreshTokenByTokenHash",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefreshTokenRequest{RefreshToken: "refresh_token"},
+			},
+			wantResp: RefreshTokenResponse{Code: http.StatusBadGateway},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:  tt.fields.auth,
+				token: tt.fields.token,
+			}
+			tt.mock()
+			gotResp, err := u.RefreshToken(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.RefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// Refresh tokens are random, only check that one was issued.
+			if (gotResp.RefreshToken != "") != (tt.wantResp.RefreshToken != "") {
+				t.Errorf("usecase.RefreshToken() refresh token = %v, want %v", gotResp.RefreshToken, tt.wantResp.RefreshToken)
+			}
+			gotResp.RefreshToken = tt.wantResp.RefreshToken
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.RefreshToken() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockToken := token.NewMockTokenItf(ctrl)
+
+	expiresAt := time.Now().Add(time.Minute)
+
+	type fields struct {
+		auth  domainauth.DomainItf
+		token token.TokenItf
+	}
+	type args struct {
+		ctx context.Context
+		req LogoutRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp LogoutResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LogoutRequest{UserId: "id", AccessTokenId: "jti", AccessTokenExpires: expiresAt, RefreshToken: "refresh_token"},
+			},
+			wantResp: LogoutResponse{Code: http.StatusNoContent},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().RevokeAccessToken(gomock.Any(), "jti", gomock.Any()).Return(nil),
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour)}, nil),
+					mockDomainAuth.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success without refresh token",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LogoutRequest{UserId: "id", AccessTokenId: "jti", AccessTokenExpires: expiresAt},
+			},
+			wantResp: LogoutResponse{Code: http.StatusNoContent},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().RevokeAccessToken(gomock.Any(), "jti", gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success refresh token of another user",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LogoutRequest{UserId: "id", AccessTokenId: "jti", AccessTokenExpires: expiresAt, RefreshToken: "refresh_token"},
+			},
+			wantResp: LogoutResponse{Code: http.StatusNoContent},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().RevokeAccessToken(gomock.Any(), "jti", gomock.Any()).Return(nil),
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "other", FamilyId: "family"}, nil),
+				)
+			},
+		},
+		{
+			name: "success refresh token not found",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LogoutRequest{UserId: "id", AccessTokenId: "jti", AccessTokenExpires: expiresAt, RefreshToken: "refresh_token"},
+			},
+			wantResp: LogoutResponse{Code: http.StatusNoContent},
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().RevokeAccessToken(gomock.Any(), "jti", gomock.Any()).Return(nil),
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.RevokeRefreshTokenFamily",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LogoutRequest{UserId: "id", AccessTokenId: "jti", AccessTokenExpires: expiresAt, RefreshToken: "refresh_token"},
+			},
+			wantResp: LogoutResponse{Code: http.StatusBadGateway},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().RevokeAccessToken(gomock.Any(), "jti", gomock.Any()).Return(nil),
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{Id: "rt", UserId: "id", FamilyId: "family", TokenHash: hashRefreshToken("refresh_token"), ExpiresAt: time.Now().Add(time.Hour)}, nil),
+					mockDomainAuth.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error auth.GetRefreshTokenByTokenHash",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LogoutRequest{UserId: "id", AccessTokenId: "jti", AccessTokenExpires: expiresAt, RefreshToken: "refresh_token"},
+			},
+			wantResp: LogoutResponse{Code: http.StatusBadGateway},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().RevokeAccessToken(gomock.Any(), "jti", gomock.Any()).Return(nil),
+					mockDomainAuth.EXPECT().GetRefreshTokenByTokenHash(gomock.Any(), hashRefreshToken("refresh_token")).Return(entity.RefreshToken{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error auth.RevokeAccessToken",
+			fields: fields{
+				auth:  mockDomainAuth,
+				token: mockToken,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: LogoutRequest{UserId: "id", AccessTokenId: "jti", AccessTokenExpires: expiresAt, RefreshToken: "refresh_token"},
+			},
+			wantResp: LogoutResponse{Code: http.StatusBadGateway},
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().RevokeAccessToken(gomock.Any(), "jti", gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:  tt.fields.auth,
+				token: tt.fields.token,
+			}
+			tt.mock()
+			gotResp, err := u.Logout(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.Logout() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.Logout() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_GetAuthUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		auth domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp entity.User
+		wantErr  error
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: entity.User{Id: "id", Username: "username", Status: int(enum.USER_STATUS_FROZEN)},
+			wantErr:  nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username", Status: int(enum.USER_STATUS_FROZEN)}, nil),
+				)
+			},
+		},
+		{
+			name: "error account closed",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: entity.User{Id: "id", Username: "username", Status: int(enum.USER_STATUS_CLOSED)},
+			wantErr:  errAccountClosed,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username", Status: int(enum.USER_STATUS_CLOSED)}, nil),
+				)
+			},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: entity.User{},
+			wantErr:  apperror.ErrUnauthorized,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				auth: mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "id",
+			},
+			wantResp: entity.User{},
+			wantErr:  apperror.ErrDependency,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth: tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.GetAuthUser(tt.args.ctx, tt.args.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("usecase.GetAuthUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.GetAuthUser() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
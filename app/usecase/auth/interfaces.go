@@ -1,7 +1,17 @@
 package usecaseauth
 
-import "context"
+import (
+	"context"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
 
 type UsecaseItf interface {
 	RegisterUser(ctx context.Context, req RegisterUserRequest) (resp RegisterUserResponse, err error)
+	Login(ctx context.Context, req LoginRequest) (resp LoginResponse, err error)
+	SetUserPassword(ctx context.Context, req SetUserPasswordRequest) (resp SetUserPasswordResponse, err error)
+	RefreshToken(ctx context.Context, req RefreshTokenRequest) (resp RefreshTokenResponse, err error)
+	Logout(ctx context.Context, req LogoutRequest) (resp LogoutResponse, err error)
+	IsAccessTokenRevoked(ctx context.Context, id string) (resp bool, err error)
+	GetAuthUser(ctx context.Context, id string) (resp entity.User, err error)
 }
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	entity "github.com/kevinsudut/wallet-system/app/entity"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,6 +40,81 @@ func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
 	return m.recorder
 }
 
+// GetAuthUser mocks base method.
+func (m *MockUsecaseItf) GetAuthUser(ctx context.Context, id string) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthUser", ctx, id)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthUser indicates an expected call of GetAuthUser.
+func (mr *MockUsecaseItfMockRecorder) GetAuthUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthUser", reflect.TypeOf((*MockUsecaseItf)(nil).GetAuthUser), ctx, id)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockUsecaseItf) IsAccessTokenRevoked(ctx context.Context, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockUsecaseItfMockRecorder) IsAccessTokenRevoked(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockUsecaseItf)(nil).IsAccessTokenRevoked), ctx, id)
+}
+
+// Login mocks base method.
+func (m *MockUsecaseItf) Login(ctx context.Context, req LoginRequest) (LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, req)
+	ret0, _ := ret[0].(LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockUsecaseItfMockRecorder) Login(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUsecaseItf)(nil).Login), ctx, req)
+}
+
+// Logout mocks base method.
+func (m *MockUsecaseItf) Logout(ctx context.Context, req LogoutRequest) (LogoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, req)
+	ret0, _ := ret[0].(LogoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUsecaseItfMockRecorder) Logout(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUsecaseItf)(nil).Logout), ctx, req)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUsecaseItf) RefreshToken(ctx context.Context, req RefreshTokenRequest) (RefreshTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, req)
+	ret0, _ := ret[0].(RefreshTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUsecaseItfMockRecorder) RefreshToken(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUsecaseItf)(nil).RefreshToken), ctx, req)
+}
+
 // RegisterUser mocks base method.
 func (m *MockUsecaseItf) RegisterUser(ctx context.Context, req RegisterUserRequest) (RegisterUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -53,3 +129,18 @@ func (mr *MockUsecaseItfMockRecorder) RegisterUser(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockUsecaseItf)(nil).RegisterUser), ctx, req)
 }
+
+// SetUserPassword mocks base method.
+func (m *MockUsecaseItf) SetUserPassword(ctx context.Context, req SetUserPasswordRequest) (SetUserPasswordResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserPassword", ctx, req)
+	ret0, _ := ret[0].(SetUserPasswordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserPassword indicates an expected call of SetUserPassword.
+func (mr *MockUsecaseItfMockRecorder) SetUserPassword(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserPassword", reflect.TypeOf((*MockUsecaseItf)(nil).SetUserPassword), ctx, req)
+}
@@ -0,0 +1,58 @@
+package usecaseauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+// newRefreshToken generates an opaque refresh token for the family. Only the hash is returned in the entity,
+// the plain token is handed to the client once and never stored.
+func newRefreshToken(userId string, familyId string) (token string, refreshToken entity.RefreshToken, err error) {
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return token, refreshToken, err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+
+	return token, entity.RefreshToken{
+		Id:        uuid.NewString(),
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: time.Now().Add(refreshTokenTTL).UTC(),
+	}, nil
+}
+
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// issueTokens creates an access token and the first refresh token of a new family for the user.
+func (u usecase) issueTokens(ctx context.Context, user entity.User) (token string, refreshToken string, err error) {
+	token, err = u.token.Create(accessTokenTTL, user)
+	if err != nil {
+		return token, refreshToken, err
+	}
+
+	refreshToken, refreshTokenEntity, err := newRefreshToken(user.Id, uuid.NewString())
+	if err != nil {
+		return token, refreshToken, err
+	}
+
+	err = u.auth.InsertRefreshToken(ctx, refreshTokenEntity)
+	if err != nil {
+		return token, refreshToken, err
+	}
+
+	return token, refreshToken, nil
+}
@@ -1,10 +1,59 @@
 package usecaseauth
 
+import "time"
+
+// RegisterUserRequest registers a user whose balance is kept in Currency, or in the default currency when it is
+// empty. The currency of a user cannot change afterwards.
 type RegisterUserRequest struct {
 	Username string `json:"username"`
+	Password string `json:"password"`
+	Currency string `json:"currency"`
 }
 
 type RegisterUserResponse struct {
-	Code  int    `json:"-"`
-	Token string `json:"token"`
+	Code         int    `json:"-"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	Code         int    `json:"-"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// SetUserPasswordRequest gives UserId, registered before passwords existed, its first password.
+type SetUserPasswordRequest struct {
+	UserId   string `json:"-"`
+	Password string `json:"password"`
+}
+
+type SetUserPasswordResponse struct {
+	Code int `json:"-"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshTokenResponse struct {
+	Code         int    `json:"-"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutRequest struct {
+	UserId             string    `json:"-"`
+	AccessTokenId      string    `json:"-"`
+	AccessTokenExpires time.Time `json:"-"`
+	RefreshToken       string    `json:"refresh_token"`
+}
+
+type LogoutResponse struct {
+	Code int `json:"-"`
 }
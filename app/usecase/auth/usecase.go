@@ -1,18 +1,57 @@
 package usecaseauth
 
 import (
+	"os"
+	"strconv"
+	"time"
+
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	"github.com/kevinsudut/wallet-system/pkg/lib/token"
 )
 
+const (
+	accessTokenTTL  = time.Minute * 15
+	refreshTokenTTL = time.Hour * 24 * 30
+
+	minPasswordLength = 8
+
+	defaultMaxFailedLoginAttempts = 5
+	defaultLoginLockoutDuration   = time.Minute * 15
+)
+
 type usecase struct {
 	auth  domainauth.DomainItf
 	token token.TokenItf
+
+	maxFailedLoginAttempts int64
+	loginLockoutDuration   time.Duration
 }
 
 func Init(auth domainauth.DomainItf, token token.TokenItf) UsecaseItf {
 	return &usecase{
-		auth:  auth,
-		token: token,
+		auth:                   auth,
+		token:                  token,
+		maxFailedLoginAttempts: maxFailedLoginAttempts(),
+		loginLockoutDuration:   loginLockoutDuration(),
+	}
+}
+
+// maxFailedLoginAttempts reads LOGIN_MAX_FAILED_ATTEMPTS, falling back to the default when unset or invalid.
+func maxFailedLoginAttempts() int64 {
+	attempts, err := strconv.ParseInt(os.Getenv("LOGIN_MAX_FAILED_ATTEMPTS"), 10, 64)
+	if err != nil || attempts <= 0 {
+		return defaultMaxFailedLoginAttempts
 	}
+
+	return attempts
+}
+
+// loginLockoutDuration reads LOGIN_LOCKOUT_DURATION (e.g. "15m"), falling back to the default when unset or invalid.
+func loginLockoutDuration() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("LOGIN_LOCKOUT_DURATION"))
+	if err != nil || duration <= 0 {
+		return defaultLoginLockoutDuration
+	}
+
+	return duration
 }
@@ -24,8 +24,10 @@ func TestInit(t *testing.T) {
 				token: nil,
 			},
 			want: &usecase{
-				auth:  nil,
-				token: nil,
+				auth:                   nil,
+				token:                  nil,
+				maxFailedLoginAttempts: defaultMaxFailedLoginAttempts,
+				loginLockoutDuration:   defaultLoginLockoutDuration,
 			},
 		},
 	}
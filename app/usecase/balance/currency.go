@@ -0,0 +1,272 @@
+package usecasebalance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/currency"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+var (
+	errInvalidCurrency          = apperror.New(http.StatusBadRequest, "invalid_currency", "currency must be a supported ISO 4217 code")
+	errSameCurrency             = apperror.New(http.StatusBadRequest, "invalid_currency", "a conversion must be between two different currencies")
+	errInvalidConversionAmount  = apperror.New(http.StatusBadRequest, "invalid_amount", "conversion amount must be greater than 0")
+	errInexactAmount            = apperror.New(http.StatusBadRequest, "invalid_amount", "amount has more decimals than the minor unit of its currency")
+	errConversionAmountTooSmall = apperror.New(http.StatusBadRequest, "invalid_amount", "amount is too small to be converted to the other currency")
+	errRateNotFound             = apperror.New(http.StatusNotFound, "rate_not_found", "there is no exchange rate for the currency pair")
+	errRateExpired              = apperror.New(http.StatusConflict, "rate_expired", "the rate of the quote is no longer current")
+	errCurrencyMismatch         = apperror.New(http.StatusUnprocessableEntity, "currency_mismatch", "the balances of this operation are in different currencies")
+)
+
+// currencyMismatchError tells the client which currencies the operation would have moved money between.
+func currencyMismatchError(err domainbalance.CurrencyMismatchError) (code int, appErr error) {
+	return http.StatusUnprocessableEntity, errCurrencyMismatch.Wrap(err).WithDetails(map[string]interface{}{
+		"currency":    err.Currency,
+		"to_currency": err.ToCurrency,
+	})
+}
+
+// exactAmount rejects an amount with more decimals than the minor unit of currencyCode, such as cents of JPY.
+func exactAmount(amount money.Money, currencyCode string) (code int, err error) {
+	exponent := currency.Exponent(currencyCode)
+	if !amount.IsExact(exponent) {
+		return http.StatusBadRequest, errInexactAmount.WithDetails(map[string]interface{}{
+			"currency": currencyCode,
+			"decimals": exponent,
+		})
+	}
+
+	return http.StatusOK, nil
+}
+
+// mainBalance reads the balance of the user in its main currency. A user that never had money has no balance
+// row, its currency is then read from the user.
+func (u usecase) mainBalance(ctx context.Context, userId string) (resp entity.Balance, code int, err error) {
+	resp, err = u.balance.GetBalanceByUserId(ctx, userId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Errorln("mainBalance.GetBalanceByUserId", err)
+		return entity.Balance{}, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+	if resp.Currency != "" {
+		return resp, http.StatusOK, nil
+	}
+
+	user, err := u.auth.GetUserById(ctx, userId)
+	if err != nil {
+		log.Errorln("mainBalance.GetUserById", err)
+		return entity.Balance{}, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp.UserId = userId
+	resp.Currency = user.Currency
+	return resp, http.StatusOK, nil
+}
+
+func (u usecase) ListBalances(ctx context.Context, req ListBalancesRequest) (resp ListBalancesResponse, err error) {
+	balance, code, err := u.mainBalance(ctx, req.UserId)
+	if err != nil {
+		return ListBalancesResponse{
+			Code: code,
+		}, err
+	}
+
+	currencyBalances, err := u.balance.GetCurrencyBalancesByUserId(ctx, req.UserId)
+	if err != nil {
+		log.Errorln("ListBalances.GetCurrencyBalancesByUserId", err)
+		return ListBalancesResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	data := make([]Balance, 0, len(currencyBalances)+1)
+	data = append(data, Balance{
+		Currency:         balance.Currency,
+		Main:             true,
+		Balance:          balance.Amount,
+		AvailableBalance: balance.Available(),
+		HeldBalance:      balance.HeldAmount,
+	})
+	for _, currencyBalance := range currencyBalances {
+		data = append(data, Balance{
+			Currency:         currencyBalance.Currency,
+			Balance:          currencyBalance.Amount,
+			AvailableBalance: currencyBalance.Amount,
+		})
+	}
+
+	return ListBalancesResponse{
+		Code: http.StatusOK,
+		Data: data,
+	}, nil
+}
+
+func (u usecase) QuoteConversion(ctx context.Context, req QuoteConversionRequest) (resp QuoteConversionResponse, err error) {
+	conversion, code, err := u.quoteConversion(ctx, req.FromCurrency, req.ToCurrency, req.Amount)
+	if err != nil {
+		return QuoteConversionResponse{
+			Code: code,
+		}, err
+	}
+
+	return QuoteConversionResponse{
+		Code:       http.StatusOK,
+		Conversion: conversion,
+	}, nil
+}
+
+// quoteConversion prices amount of fromCurrency in toCurrency at the rate in effect now.
+func (u usecase) quoteConversion(ctx context.Context, fromCurrency string, toCurrency string, amount money.Money) (resp Conversion, code int, err error) {
+	if amount <= 0 {
+		return resp, http.StatusBadRequest, errInvalidConversionAmount
+	}
+
+	if !currency.Valid(fromCurrency) || !currency.Valid(toCurrency) {
+		return resp, http.StatusBadRequest, errInvalidCurrency
+	}
+
+	if fromCurrency == toCurrency {
+		return resp, http.StatusBadRequest, errSameCurrency
+	}
+
+	code, err = exactAmount(amount, fromCurrency)
+	if err != nil {
+		return resp, code, err
+	}
+
+	rate, err := u.fx.GetFxRate(ctx, fromCurrency, toCurrency, time.Now())
+	if err == sql.ErrNoRows {
+		return resp, http.StatusNotFound, errRateNotFound.WithDetails(map[string]interface{}{
+			"from_currency": fromCurrency,
+			"to_currency":   toCurrency,
+		})
+	}
+	if err != nil {
+		log.Errorln("quoteConversion.GetFxRate", err)
+		return resp, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+
+	toAmount := rate.Rate.Convert(amount, currency.Exponent(toCurrency))
+	if toAmount <= 0 {
+		return resp, http.StatusBadRequest, errConversionAmountTooSmall
+	}
+
+	return Conversion{
+		RateId:       rate.Id,
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+		Amount:       amount,
+		ToAmount:     toAmount,
+		Rate:         rate.Rate,
+	}, http.StatusOK, nil
+}
+
+func newDomainConversion(conversion Conversion) domainbalance.Conversion {
+	return domainbalance.Conversion{
+		FromCurrency: conversion.FromCurrency,
+		ToCurrency:   conversion.ToCurrency,
+		ToAmount:     conversion.ToAmount,
+		Rate:         conversion.Rate,
+	}
+}
+
+// ConvertBalance moves Amount between two balances of the user at the current rate. When the client quoted the
+// conversion first, RateId makes sure it is not made at a rate the user did not see.
+func (u usecase) ConvertBalance(ctx context.Context, req ConvertBalanceRequest) (resp ConvertBalanceResponse, err error) {
+	idempotencyKey, err := newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationConvertBalance, req, http.StatusOK, resp)
+	if err != nil {
+		return ConvertBalanceResponse{
+			Code: http.StatusBadRequest,
+		}, err
+	}
+
+	code, found, err := u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+	if err != nil {
+		return ConvertBalanceResponse{
+			Code: code,
+		}, err
+	}
+	if found {
+		resp.Code = code
+		return resp, nil
+	}
+
+	conversion, code, err := u.quoteConversion(ctx, req.FromCurrency, req.ToCurrency, req.Amount)
+	if err != nil {
+		return ConvertBalanceResponse{
+			Code: code,
+		}, err
+	}
+
+	if req.RateId != 0 && req.RateId != conversion.RateId {
+		return ConvertBalanceResponse{
+			Code: http.StatusConflict,
+		}, errRateExpired.WithDetails(map[string]interface{}{
+			"rate_id": conversion.RateId,
+			"rate":    conversion.Rate,
+		})
+	}
+
+	resp = ConvertBalanceResponse{
+		Code:       http.StatusOK,
+		Conversion: conversion,
+	}
+
+	idempotencyKey, err = newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationConvertBalance, req, resp.Code, resp)
+	if err != nil {
+		return ConvertBalanceResponse{
+			Code: http.StatusInternalServerError,
+		}, err
+	}
+
+	err = u.balance.ConvertBalance(ctx, domainbalance.ConvertBalanceRequest{
+		UserId:         req.UserId,
+		Amount:         req.Amount,
+		Conversion:     newDomainConversion(conversion),
+		IdempotencyKey: idempotencyKey,
+	})
+	if errors.Is(err, domainbalance.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race, so replay its result instead.
+		resp = ConvertBalanceResponse{}
+		code, found, err = u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+		if err != nil || !found {
+			return ConvertBalanceResponse{
+				Code: http.StatusConflict,
+			}, errIdempotencyKeyConflict.Wrap(domainbalance.ErrIdempotencyKeyExists)
+		}
+
+		resp.Code = code
+		return resp, nil
+	}
+	var insufficientBalanceErr domainbalance.InsufficientBalanceError
+	if errors.As(err, &insufficientBalanceErr) {
+		return ConvertBalanceResponse{
+			Code: http.StatusBadRequest,
+		}, errInsufficientBalance.Wrap(insufficientBalanceErr).WithDetails(map[string]interface{}{
+			"balance": insufficientBalanceErr.Balance,
+			"amount":  insufficientBalanceErr.Amount,
+		})
+	}
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	if errors.As(err, &accountNotActiveErr) {
+		code, err = accountNotActiveError(accountNotActiveErr, req.UserId)
+		return ConvertBalanceResponse{
+			Code: code,
+		}, err
+	}
+	if err != nil {
+		log.Errorln("ConvertBalance.ConvertBalance", err)
+		return ConvertBalanceResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return resp, nil
+}
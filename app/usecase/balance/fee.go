@@ -0,0 +1,90 @@
+package usecasebalance
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+// QuoteTransfer returns the fee of a transfer without running it. The fee is charged again when the transfer runs,
+// so a transfer that uses up the last free transfer of the month first makes it cost more than quoted.
+func (u usecase) QuoteTransfer(ctx context.Context, req QuoteTransferRequest) (resp QuoteTransferResponse, err error) {
+	if req.Amount <= 0 {
+		return QuoteTransferResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidTransferAmount
+	}
+
+	_, err = u.auth.GetUserByUsername(ctx, req.ToUsername)
+	if err == sql.ErrNoRows {
+		return QuoteTransferResponse{
+			Code: http.StatusNotFound,
+		}, errRecipientNotFound
+	}
+	if err != nil {
+		log.Errorln("QuoteTransfer.GetUserByUsername", err)
+		return QuoteTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	user, err := u.auth.GetUserById(ctx, req.UserId)
+	if err != nil {
+		log.Errorln("QuoteTransfer.GetUserById", err)
+		return QuoteTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	code, err := exactAmount(req.Amount, user.Currency)
+	if err != nil {
+		return QuoteTransferResponse{
+			Code: code,
+		}, err
+	}
+
+	feeRule, err := u.feeRule(ctx, enum.TRANSFER, user.Currency, req.Amount)
+	if err != nil {
+		log.Errorln("QuoteTransfer.feeRule", err)
+		return QuoteTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	fee, err := u.balance.GetFee(ctx, domainbalance.GetFeeRequest{
+		UserId:    req.UserId,
+		Operation: enum.TRANSFER,
+		Amount:    req.Amount,
+		FeeRule:   feeRule,
+	})
+	if err != nil {
+		log.Errorln("QuoteTransfer.GetFee", err)
+		return QuoteTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return QuoteTransferResponse{
+		Code:   http.StatusOK,
+		Amount: req.Amount,
+		Fee:    fee,
+		Total:  req.Amount + fee,
+	}, nil
+}
+
+// feeRule returns the fee rule that prices an operation of amount in currency, a zero rule when it is free.
+func (u usecase) feeRule(ctx context.Context, operation enum.JournalEntryType, currency string, amount money.Money) (resp entity.FeeRule, err error) {
+	rules, err := u.fee.GetFeeRules(ctx, operation, currency)
+	if err != nil {
+		return resp, err
+	}
+
+	return entity.MatchFeeRule(rules, amount), nil
+}
@@ -0,0 +1,323 @@
+package usecasebalance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	defaultHoldExpiry = 24 * time.Hour
+	minHoldExpiry     = time.Minute
+	maxHoldExpiry     = 7 * 24 * time.Hour
+)
+
+var (
+	errInvalidHoldAmount    = apperror.New(http.StatusBadRequest, "invalid_amount", "hold amount must be greater than 0")
+	errInvalidHoldExpiry    = apperror.New(http.StatusBadRequest, "invalid_expiry", fmt.Sprintf("hold expiry must be between %d and %d seconds", int64(minHoldExpiry.Seconds()), int64(maxHoldExpiry.Seconds())))
+	errInvalidCaptureAmount = apperror.New(http.StatusBadRequest, "invalid_amount", "capture amount must be greater than 0, or omitted to capture the whole hold")
+	errHoldNotFound         = apperror.New(http.StatusNotFound, "hold_not_found", "hold does not exist or was not placed for this user")
+	errHoldNotActive        = apperror.New(http.StatusConflict, "hold_not_active", "hold was already captured, voided or has expired")
+	errCaptureExceedsHold   = apperror.New(http.StatusUnprocessableEntity, "capture_exceeds_hold", "capture amount exceeds the amount of the hold")
+)
+
+func newHold(hold entity.Hold) Hold {
+	return Hold{
+		Id:             hold.Id,
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Status:         enum.HoldStatus(hold.Status).String(),
+		TransferId:     hold.TransferId,
+		ExpiresAt:      hold.ExpiresAt,
+	}
+}
+
+// CreateHold reserves funds of the user for the recipient, who can then capture or void them.
+func (u usecase) CreateHold(ctx context.Context, req CreateHoldRequest) (resp CreateHoldResponse, err error) {
+	if req.Amount <= 0 {
+		return CreateHoldResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidHoldAmount
+	}
+
+	expiresIn := defaultHoldExpiry
+	if req.ExpiresIn != 0 {
+		expiresIn = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if expiresIn < minHoldExpiry || expiresIn > maxHoldExpiry {
+		return CreateHoldResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidHoldExpiry
+	}
+
+	idempotencyKey, err := newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationCreateHold, req, http.StatusCreated, resp)
+	if err != nil {
+		return CreateHoldResponse{
+			Code: http.StatusBadRequest,
+		}, err
+	}
+
+	code, found, err := u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+	if err != nil {
+		return CreateHoldResponse{
+			Code: code,
+		}, err
+	}
+	if found {
+		resp.Code = code
+		return resp, nil
+	}
+
+	toUser, err := u.auth.GetUserByUsername(ctx, req.ToUsername)
+	if err == sql.ErrNoRows {
+		return CreateHoldResponse{
+			Code: http.StatusNotFound,
+		}, errRecipientNotFound
+	}
+	if err != nil {
+		log.Errorln("CreateHold.GetUserByUsername", err)
+		return CreateHoldResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	// A hold is only placed for a user of the same currency.
+	code, err = exactAmount(req.Amount, toUser.Currency)
+	if err != nil {
+		return CreateHoldResponse{
+			Code: code,
+		}, err
+	}
+
+	hold := entity.Hold{
+		Id:           uuid.NewString(),
+		UserId:       req.UserId,
+		TargetUserId: toUser.Id,
+		Amount:       req.Amount,
+		Status:       int(enum.HOLD_STATUS_ACTIVE),
+		ExpiresAt:    time.Now().Add(expiresIn).UTC(),
+	}
+
+	resp = CreateHoldResponse{
+		Code: http.StatusCreated,
+		Hold: newHold(hold),
+	}
+
+	idempotencyKey, err = newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationCreateHold, req, resp.Code, resp)
+	if err != nil {
+		return CreateHoldResponse{
+			Code: http.StatusInternalServerError,
+		}, err
+	}
+
+	err = u.balance.CreateHold(ctx, domainbalance.CreateHoldRequest{
+		Hold:           hold,
+		IdempotencyKey: idempotencyKey,
+	})
+	if errors.Is(err, domainbalance.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race, so replay its result instead.
+		resp = CreateHoldResponse{}
+		code, found, err = u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+		if err != nil || !found {
+			return CreateHoldResponse{
+				Code: http.StatusConflict,
+			}, errIdempotencyKeyConflict.Wrap(domainbalance.ErrIdempotencyKeyExists)
+		}
+
+		resp.Code = code
+		return resp, nil
+	}
+	var insufficientBalanceErr domainbalance.InsufficientBalanceError
+	if errors.As(err, &insufficientBalanceErr) {
+		return CreateHoldResponse{
+			Code: http.StatusBadRequest,
+		}, errInsufficientBalance.Wrap(insufficientBalanceErr).WithDetails(map[string]interface{}{
+			"balance": insufficientBalanceErr.Balance,
+			"amount":  insufficientBalanceErr.Amount,
+		})
+	}
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	if errors.As(err, &accountNotActiveErr) {
+		code, err = accountNotActiveError(accountNotActiveErr, req.UserId)
+		return CreateHoldResponse{
+			Code: code,
+		}, err
+	}
+	var currencyMismatchErr domainbalance.CurrencyMismatchError
+	if errors.As(err, &currencyMismatchErr) {
+		code, err = currencyMismatchError(currencyMismatchErr)
+		return CreateHoldResponse{
+			Code: code,
+		}, err
+	}
+	if err != nil {
+		log.Errorln("CreateHold.CreateHold", err)
+		return CreateHoldResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return resp, nil
+}
+
+// getHoldForTarget reads a hold that userId can capture or void, which is a hold placed for them.
+func (u usecase) getHoldForTarget(ctx context.Context, holdId string, userId string) (resp entity.Hold, code int, err error) {
+	hold, err := u.balance.GetHoldById(ctx, holdId)
+	if err == sql.ErrNoRows || (err == nil && hold.TargetUserId != userId) {
+		return resp, http.StatusNotFound, errHoldNotFound
+	}
+	if err != nil {
+		log.Errorln("getHoldForTarget.GetHoldById", err)
+		return resp, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+
+	return hold, http.StatusOK, nil
+}
+
+// holdError maps the errors of capturing or voiding a hold in the domain, userId is the user capturing or voiding
+// it.
+func holdError(err error, userId string) (code int, appErr error) {
+	var captureExceedsHoldErr domainbalance.CaptureExceedsHoldError
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	var currencyMismatchErr domainbalance.CurrencyMismatchError
+	switch {
+	case errors.Is(err, domainbalance.ErrHoldNotFound):
+		return http.StatusNotFound, errHoldNotFound
+	case errors.Is(err, domainbalance.ErrHoldNotActive):
+		return http.StatusConflict, errHoldNotActive
+	case errors.As(err, &captureExceedsHoldErr):
+		return http.StatusUnprocessableEntity, errCaptureExceedsHold.Wrap(captureExceedsHoldErr).WithDetails(map[string]interface{}{
+			"held":   captureExceedsHoldErr.Held,
+			"amount": captureExceedsHoldErr.Amount,
+		})
+	case errors.As(err, &accountNotActiveErr):
+		return accountNotActiveError(accountNotActiveErr, userId)
+	case errors.As(err, &currencyMismatchErr):
+		return currencyMismatchError(currencyMismatchErr)
+	}
+
+	return http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+}
+
+// CaptureHold transfers part or all of a hold to the user it was placed for. An amount of 0 captures the
+// whole hold, and whatever is not captured is released back to the payer. The captured amount counts against the
+// transfer limits of the payer.
+func (u usecase) CaptureHold(ctx context.Context, req CaptureHoldRequest) (resp CaptureHoldResponse, err error) {
+	if req.Amount < 0 {
+		return CaptureHoldResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidCaptureAmount
+	}
+
+	hold, code, err := u.getHoldForTarget(ctx, req.HoldId, req.UserId)
+	if err != nil {
+		return CaptureHoldResponse{
+			Code: code,
+		}, err
+	}
+
+	// The hold is checked again by CaptureHold once it is locked.
+	if !hold.IsActive(time.Now()) {
+		return CaptureHoldResponse{
+			Code: http.StatusConflict,
+		}, errHoldNotActive
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = hold.Amount
+	}
+
+	if amount > hold.Amount {
+		return CaptureHoldResponse{
+			Code: http.StatusUnprocessableEntity,
+		}, errCaptureExceedsHold.WithDetails(map[string]interface{}{
+			"held":   hold.Amount,
+			"amount": amount,
+		})
+	}
+
+	reserved, err := u.limit.ReserveLimit(ctx, usecaselimit.ReserveLimitRequest{
+		UserId:    hold.UserId,
+		Operation: enum.LIMIT_OPERATION_TRANSFER,
+		Amount:    amount,
+	})
+	if err != nil {
+		return CaptureHoldResponse{
+			Code: reserved.Code,
+		}, err
+	}
+
+	transferId := uuid.NewString()
+	err = u.balance.CaptureHold(ctx, domainbalance.CaptureHoldRequest{
+		HoldId:     hold.Id,
+		TransferId: transferId,
+		UserId:     req.UserId,
+		Amount:     amount,
+	})
+	if err != nil {
+		log.Errorln("CaptureHold.CaptureHold", err)
+		u.limit.ReleaseLimit(ctx, usecaselimit.ReleaseLimitRequest{
+			Reservation: reserved.Reservation,
+		})
+		code, err = holdError(err, req.UserId)
+		return CaptureHoldResponse{
+			Code: code,
+		}, err
+	}
+
+	hold.Status = int(enum.HOLD_STATUS_CAPTURED)
+	hold.CapturedAmount = amount
+	hold.TransferId = transferId
+
+	return CaptureHoldResponse{
+		Code: http.StatusOK,
+		Hold: newHold(hold),
+	}, nil
+}
+
+// VoidHold releases a hold back to the payer without moving money.
+func (u usecase) VoidHold(ctx context.Context, req VoidHoldRequest) (resp VoidHoldResponse, err error) {
+	hold, code, err := u.getHoldForTarget(ctx, req.HoldId, req.UserId)
+	if err != nil {
+		return VoidHoldResponse{
+			Code: code,
+		}, err
+	}
+
+	if hold.Status != int(enum.HOLD_STATUS_ACTIVE) {
+		return VoidHoldResponse{
+			Code: http.StatusConflict,
+		}, errHoldNotActive
+	}
+
+	err = u.balance.VoidHold(ctx, domainbalance.VoidHoldRequest{
+		HoldId: hold.Id,
+		UserId: req.UserId,
+	})
+	if err != nil {
+		log.Errorln("VoidHold.VoidHold", err)
+		code, err = holdError(err, req.UserId)
+		return VoidHoldResponse{
+			Code: code,
+		}, err
+	}
+
+	hold.Status = int(enum.HOLD_STATUS_VOIDED)
+
+	return VoidHoldResponse{
+		Code: http.StatusOK,
+		Hold: newHold(hold),
+	}, nil
+}
@@ -0,0 +1,92 @@
+package usecasebalance
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	idempotencyOperationTopupBalance        = "topup_balance"
+	idempotencyOperationTransferBalance     = "transfer_balance"
+	idempotencyOperationRefundTransfer      = "refund_transfer"
+	idempotencyOperationCreateHold          = "create_hold"
+	idempotencyOperationMovePocketBalance   = "move_pocket_balance"
+	idempotencyOperationDepositSharedWallet = "deposit_shared_wallet"
+	idempotencyOperationConvertBalance      = "convert_balance"
+
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	errInvalidIdempotencyKey = apperror.New(http.StatusBadRequest, "invalid_idempotency_key", fmt.Sprintf("idempotency key must be at most %d characters", maxIdempotencyKeyLength))
+	errIdempotencyKeyReused  = apperror.New(http.StatusUnprocessableEntity, "idempotency_key_reused", "idempotency key reused with a different payload")
+)
+
+// newIdempotencyKey builds the record stored alongside the ledger write. The fingerprint covers the
+// operation and the whole request, so reusing a key for a different payload can be detected.
+func newIdempotencyKey(userId string, key string, operation string, req interface{}, code int, resp interface{}) (entity.IdempotencyKey, error) {
+	if key == "" {
+		return entity.IdempotencyKey{}, nil
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		return entity.IdempotencyKey{}, errInvalidIdempotencyKey
+	}
+
+	reqJson, err := jsoniter.MarshalToString(req)
+	if err != nil {
+		return entity.IdempotencyKey{}, apperror.ErrInternal.Wrap(err)
+	}
+
+	respJson, err := jsoniter.MarshalToString(resp)
+	if err != nil {
+		return entity.IdempotencyKey{}, apperror.ErrInternal.Wrap(err)
+	}
+
+	fingerprint := sha256.Sum256([]byte(operation + ":" + reqJson))
+
+	return entity.IdempotencyKey{
+		UserId:       userId,
+		Key:          key,
+		Fingerprint:  hex.EncodeToString(fingerprint[:]),
+		ResponseCode: code,
+		ResponseBody: respJson,
+	}, nil
+}
+
+// replayIdempotencyKey decodes the stored response into resp when the key was already processed.
+func (u usecase) replayIdempotencyKey(ctx context.Context, idempotencyKey entity.IdempotencyKey, resp interface{}) (code int, found bool, err error) {
+	if idempotencyKey.Key == "" {
+		return 0, false, nil
+	}
+
+	stored, err := u.balance.GetIdempotencyKey(ctx, idempotencyKey.UserId, idempotencyKey.Key)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		log.Errorln("replayIdempotencyKey.GetIdempotencyKey", err)
+		return http.StatusBadGateway, false, apperror.ErrDependency.Wrap(err)
+	}
+
+	if stored.Fingerprint != idempotencyKey.Fingerprint {
+		return http.StatusUnprocessableEntity, false, errIdempotencyKeyReused
+	}
+
+	err = jsoniter.UnmarshalFromString(stored.ResponseBody, resp)
+	if err != nil {
+		log.Errorln("replayIdempotencyKey.UnmarshalFromString", err)
+		return http.StatusInternalServerError, false, apperror.ErrInternal.Wrap(err)
+	}
+
+	return stored.ResponseCode, true, nil
+}
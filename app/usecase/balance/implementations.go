@@ -3,88 +3,331 @@ package usecasebalance
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 	"net/http"
 
 	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
-	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 )
 
+var (
+	errInvalidTopupAmount     = apperror.New(http.StatusBadRequest, "invalid_amount", "topup amount must be greater than 0")
+	errTopupAmountBelowFee    = apperror.New(http.StatusBadRequest, "invalid_amount", "topup amount must be greater than its fee")
+	errInvalidTransferAmount  = apperror.New(http.StatusBadRequest, "invalid_amount", "transfer amount must be greater than 0")
+	errRecipientNotFound      = apperror.New(http.StatusNotFound, "recipient_not_found", "recipient username does not exist")
+	errInsufficientBalance    = apperror.New(http.StatusBadRequest, "insufficient_balance", "balance is not sufficient for this transfer")
+	errIdempotencyKeyConflict = apperror.New(http.StatusConflict, "idempotency_key_in_progress", "a request with the same idempotency key is still being processed")
+	errAccountNotActive       = apperror.New(http.StatusForbidden, "account_not_active", "the status of the account does not allow this operation")
+	errCounterpartyNotActive  = apperror.New(http.StatusUnprocessableEntity, "counterparty_not_active", "the other user of this operation cannot send or receive money")
+)
+
 func (u usecase) ReadBalanceByUserId(ctx context.Context, req ReadBalanceByUserIdRequest) (resp ReadBalanceByUserIdResponse, err error) {
-	balance, err := u.balance.GetBalanceByUserId(ctx, req.UserId)
-	if err != nil && err != sql.ErrNoRows {
-		log.Errorln("ReadBalanceByUserId.GetBalanceByUserId", err)
+	balance, code, err := u.mainBalance(ctx, req.UserId)
+	if err != nil {
 		return ReadBalanceByUserIdResponse{
-			Code: http.StatusBadRequest,
+			Code: code,
 		}, err
 	}
 
 	return ReadBalanceByUserIdResponse{
-		Code:    http.StatusOK,
-		Balance: balance.Amount,
+		Code:             http.StatusOK,
+		Currency:         balance.Currency,
+		Balance:          balance.Amount,
+		AvailableBalance: balance.Available(),
+		HeldBalance:      balance.HeldAmount,
 	}, nil
 }
 
 func (u usecase) TopupBalance(ctx context.Context, req TopupBalanceRequest) (resp TopupBalanceResponse, err error) {
-	if req.Amount < 0 || req.Amount > 10000000 {
+	if req.Amount <= 0 {
+		return TopupBalanceResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidTopupAmount
+	}
+
+	resp = TopupBalanceResponse{
+		Code: http.StatusNoContent,
+	}
+
+	idempotencyKey, err := newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationTopupBalance, req, resp.Code, resp)
+	if err != nil {
 		return TopupBalanceResponse{
 			Code: http.StatusBadRequest,
-		}, fmt.Errorf("invalid topup amount")
+		}, err
+	}
+
+	code, found, err := u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+	if err != nil {
+		return TopupBalanceResponse{
+			Code: code,
+		}, err
+	}
+	if found {
+		resp.Code = code
+		return resp, nil
+	}
+
+	user, err := u.auth.GetUserById(ctx, req.UserId)
+	if err != nil {
+		log.Errorln("TopupBalance.GetUserById", err)
+		return TopupBalanceResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	code, err = exactAmount(req.Amount, user.Currency)
+	if err != nil {
+		return TopupBalanceResponse{
+			Code: code,
+		}, err
 	}
 
-	err = u.balance.GrantBalanceByUserId(ctx, entity.Balance{
-		UserId: req.UserId,
-		Amount: req.Amount,
+	feeRule, err := u.feeRule(ctx, enum.TOPUP, user.Currency, req.Amount)
+	if err != nil {
+		log.Errorln("TopupBalance.feeRule", err)
+		return TopupBalanceResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	reserved, err := u.limit.ReserveLimit(ctx, usecaselimit.ReserveLimitRequest{
+		UserId:    req.UserId,
+		Operation: enum.LIMIT_OPERATION_TOPUP,
+		Amount:    req.Amount,
 	})
 	if err != nil {
-		log.Errorln("TopupBalance.GrantBalanceByUserId", err)
+		return TopupBalanceResponse{
+			Code: reserved.Code,
+		}, err
+	}
+
+	err = u.balance.GrantBalanceByUserId(ctx, domainbalance.GrantBalanceByUserIdRequest{
+		UserId:         req.UserId,
+		Amount:         req.Amount,
+		FeeRule:        feeRule,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		// The topup did not happen, or a concurrent request with the same key already counted it.
+		u.limit.ReleaseLimit(ctx, usecaselimit.ReleaseLimitRequest{
+			Reservation: reserved.Reservation,
+		})
+	}
+	if errors.Is(err, domainbalance.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race, so replay its result instead.
+		code, found, err = u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+		if err != nil || !found {
+			return TopupBalanceResponse{
+				Code: http.StatusConflict,
+			}, errIdempotencyKeyConflict.Wrap(domainbalance.ErrIdempotencyKeyExists)
+		}
+
+		resp.Code = code
+		return resp, nil
+	}
+	if errors.Is(err, domainbalance.ErrFeeExceedsAmount) {
 		return TopupBalanceResponse{
 			Code: http.StatusBadRequest,
+		}, errTopupAmountBelowFee
+	}
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	if errors.As(err, &accountNotActiveErr) {
+		code, err = accountNotActiveError(accountNotActiveErr, req.UserId)
+		return TopupBalanceResponse{
+			Code: code,
 		}, err
 	}
+	if err != nil {
+		log.Errorln("TopupBalance.GrantBalanceByUserId", err)
+		return TopupBalanceResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
 
-	return TopupBalanceResponse{
-		Code: http.StatusNoContent,
-	}, nil
+	return resp, nil
 }
 
 func (u usecase) TransferBalance(ctx context.Context, req TransferBalanceRequest) (resp TransferBalanceResponse, err error) {
-	balance, err := u.balance.GetBalanceByUserId(ctx, req.UserId)
+	if req.Amount <= 0 {
+		return TransferBalanceResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidTransferAmount
+	}
+
+	resp = TransferBalanceResponse{
+		Code: http.StatusNoContent,
+	}
+
+	idempotencyKey, err := newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationTransferBalance, req, resp.Code, resp)
 	if err != nil {
-		log.Errorln("TransferBalance.GetBalanceByUserId", err)
 		return TransferBalanceResponse{
 			Code: http.StatusBadRequest,
 		}, err
 	}
 
-	if balance.Amount-req.Amount < 0 {
+	code, found, err := u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+	if err != nil {
 		return TransferBalanceResponse{
-			Code: http.StatusBadRequest,
-		}, fmt.Errorf("insufficient balance")
+			Code: code,
+		}, err
+	}
+	if found {
+		resp.Code = code
+		return resp, nil
 	}
 
+	// Funds are checked by DisburmentBalance against the locked balance row, not the cached balance.
 	toUser, err := u.auth.GetUserByUsername(ctx, req.ToUsername)
+	if err == sql.ErrNoRows {
+		return TransferBalanceResponse{
+			Code: http.StatusNotFound,
+		}, errRecipientNotFound
+	}
 	if err != nil {
 		log.Errorln("TransferBalance.GetUserByUsername", err)
 		return TransferBalanceResponse{
-			Code: http.StatusNotFound,
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	if req.FromWalletId != "" {
+		// Shared wallets only send to users of their own currency.
+		code, err = exactAmount(req.Amount, toUser.Currency)
+		if err != nil {
+			return TransferBalanceResponse{
+				Code: code,
+			}, err
+		}
+
+		return u.transferFromSharedWallet(ctx, req, toUser.Id, idempotencyKey)
+	}
+
+	user, err := u.auth.GetUserById(ctx, req.UserId)
+	if err != nil {
+		log.Errorln("TransferBalance.GetUserById", err)
+		return TransferBalanceResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	code, err = exactAmount(req.Amount, user.Currency)
+	if err != nil {
+		return TransferBalanceResponse{
+			Code: code,
+		}, err
+	}
+
+	// The recipient is credited in its own currency, at the rate in effect when the transfer is made.
+	var conversion Conversion
+	if user.Currency != toUser.Currency {
+		if !req.Convert {
+			code, err = currencyMismatchError(domainbalance.CurrencyMismatchError{
+				Currency:   user.Currency,
+				ToCurrency: toUser.Currency,
+			})
+			return TransferBalanceResponse{
+				Code: code,
+			}, err
+		}
+
+		conversion, code, err = u.quoteConversion(ctx, user.Currency, toUser.Currency, req.Amount)
+		if err != nil {
+			return TransferBalanceResponse{
+				Code: code,
+			}, err
+		}
+	}
+
+	feeRule, err := u.feeRule(ctx, enum.TRANSFER, user.Currency, req.Amount)
+	if err != nil {
+		log.Errorln("TransferBalance.feeRule", err)
+		return TransferBalanceResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	reserved, err := u.limit.ReserveLimit(ctx, usecaselimit.ReserveLimitRequest{
+		UserId:    req.UserId,
+		Operation: enum.LIMIT_OPERATION_TRANSFER,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		return TransferBalanceResponse{
+			Code: reserved.Code,
 		}, err
 	}
 
 	err = u.balance.DisburmentBalance(ctx, domainbalance.DisburmentBalanceRequest{
-		UserId:   req.UserId,
-		ToUserId: toUser.Id,
-		Amount:   req.Amount,
+		UserId:         req.UserId,
+		ToUserId:       toUser.Id,
+		Amount:         req.Amount,
+		FeeRule:        feeRule,
+		Conversion:     newDomainConversion(conversion),
+		IdempotencyKey: idempotencyKey,
 	})
 	if err != nil {
-		log.Errorln("TransferBalance.DisburmentBalance", err)
+		// The transfer did not happen, or a concurrent request with the same key already counted it.
+		u.limit.ReleaseLimit(ctx, usecaselimit.ReleaseLimitRequest{
+			Reservation: reserved.Reservation,
+		})
+	}
+	if errors.Is(err, domainbalance.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race, so replay its result instead.
+		code, found, err = u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+		if err != nil || !found {
+			return TransferBalanceResponse{
+				Code: http.StatusConflict,
+			}, errIdempotencyKeyConflict.Wrap(domainbalance.ErrIdempotencyKeyExists)
+		}
+
+		resp.Code = code
+		return resp, nil
+	}
+	var insufficientBalanceErr domainbalance.InsufficientBalanceError
+	if errors.As(err, &insufficientBalanceErr) {
 		return TransferBalanceResponse{
 			Code: http.StatusBadRequest,
+		}, errInsufficientBalance.Wrap(insufficientBalanceErr).WithDetails(map[string]interface{}{
+			"balance": insufficientBalanceErr.Balance,
+			"amount":  insufficientBalanceErr.Amount,
+			"fee":     insufficientBalanceErr.Fee,
+		})
+	}
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	if errors.As(err, &accountNotActiveErr) {
+		code, err = accountNotActiveError(accountNotActiveErr, req.UserId)
+		return TransferBalanceResponse{
+			Code: code,
 		}, err
 	}
+	var currencyMismatchErr domainbalance.CurrencyMismatchError
+	if errors.As(err, &currencyMismatchErr) {
+		code, err = currencyMismatchError(currencyMismatchErr)
+		return TransferBalanceResponse{
+			Code: code,
+		}, err
+	}
+	if err != nil {
+		log.Errorln("TransferBalance.DisburmentBalance", err)
+		return TransferBalanceResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
 
-	return TransferBalanceResponse{
-		Code: http.StatusNoContent,
-	}, nil
+	return resp, nil
+}
+
+// accountNotActiveError tells userId, who asked for the operation, apart from the other user of it when the
+// status of one of them rejected the operation. The status of another user is not disclosed.
+func accountNotActiveError(err domainbalance.AccountNotActiveError, userId string) (code int, appErr error) {
+	if err.UserId != userId {
+		return http.StatusUnprocessableEntity, errCounterpartyNotActive.Wrap(err)
+	}
+
+	return http.StatusForbidden, errAccountNotActive.Wrap(err).WithDetails(map[string]interface{}{
+		"status": enum.UserStatus(err.Status).String(),
+	})
 }
@@ -7,11 +7,19 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	domainfee "github.com/kevinsudut/wallet-system/app/domain/fee"
+	domainfx "github.com/kevinsudut/wallet-system/app/domain/fx"
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -25,6 +33,7 @@ func Test_usecase_ReadBalanceByUserId(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
 
 	type fields struct {
 		balance domainbalance.DomainItf
@@ -54,15 +63,47 @@ func Test_usecase_ReadBalanceByUserId(t *testing.T) {
 				},
 			},
 			wantResp: ReadBalanceByUserIdResponse{
-				Code:    http.StatusOK,
-				Balance: 100,
+				Code:             http.StatusOK,
+				Currency:         "IDR",
+				Balance:          100,
+				AvailableBalance: 70,
+				HeldBalance:      30,
 			},
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
 					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{
-						UserId: "id",
-						Amount: 100,
+						UserId:     "id",
+						Amount:     100,
+						HeldAmount: 30,
+						Currency:   "IDR",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success without balance",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadBalanceByUserIdRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ReadBalanceByUserIdResponse{
+				Code:     http.StatusOK,
+				Currency: "USD",
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{}, sql.ErrNoRows),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "USD",
 					}, nil),
 				)
 			},
@@ -79,7 +120,7 @@ func Test_usecase_ReadBalanceByUserId(t *testing.T) {
 				},
 			},
 			wantResp: ReadBalanceByUserIdResponse{
-				Code: http.StatusBadRequest,
+				Code: http.StatusBadGateway,
 			},
 			wantErr: true,
 			mock: func() {
@@ -88,6 +129,29 @@ func Test_usecase_ReadBalanceByUserId(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadBalanceByUserIdRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ReadBalanceByUserIdResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{}, sql.ErrNoRows),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -112,10 +176,24 @@ func Test_usecase_TopupBalance(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockUsecaseLimit := usecaselimit.NewMockUsecaseItf(ctrl)
+	mockDomainFee := domainfee.NewMockDomainItf(ctrl)
+
+	reserved := usecaselimit.ReserveLimitResponse{
+		Code: http.StatusOK,
+		Reservation: usecaselimit.Reservation{
+			UserId:    "id",
+			Operation: enum.LIMIT_OPERATION_TOPUP,
+			Amount:    100,
+		},
+	}
 
 	type fields struct {
 		balance domainbalance.DomainItf
 		auth    domainauth.DomainItf
+		limit   usecaselimit.UsecaseItf
+		fee     domainfee.DomainItf
 	}
 	type args struct {
 		ctx context.Context
@@ -133,6 +211,9 @@ func Test_usecase_TopupBalance(t *testing.T) {
 			name: "success",
 			fields: fields{
 				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
 			},
 			args: args{
 				ctx: context.Background(),
@@ -147,7 +228,17 @@ func Test_usecase_TopupBalance(t *testing.T) {
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GrantBalanceByUserId(gomock.Any(), entity.Balance{
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TOPUP, "IDR").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TOPUP,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().GrantBalanceByUserId(gomock.Any(), domainbalance.GrantBalanceByUserIdRequest{
 						UserId: "id",
 						Amount: 100,
 					}).Return(nil),
@@ -158,6 +249,9 @@ func Test_usecase_TopupBalance(t *testing.T) {
 			name: "error balance.GrantBalanceByUserId",
 			fields: fields{
 				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
 			},
 			args: args{
 				ctx: context.Background(),
@@ -167,250 +261,4339 @@ func Test_usecase_TopupBalance(t *testing.T) {
 				},
 			},
 			wantResp: TopupBalanceResponse{
-				Code: http.StatusBadRequest,
+				Code: http.StatusBadGateway,
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GrantBalanceByUserId(gomock.Any(), entity.Balance{
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TOPUP, "IDR").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TOPUP,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().GrantBalanceByUserId(gomock.Any(), domainbalance.GrantBalanceByUserIdRequest{
 						UserId: "id",
 						Amount: 100,
 					}).Return(fmt.Errorf("foo")),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
 				)
 			},
 		},
 		{
-			name: "error invalid amount",
+			name: "success replay idempotency key",
 			fields: fields{
 				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
 			},
 			args: args{
 				ctx: context.Background(),
 				req: TopupBalanceRequest{
-					UserId: "id",
-					Amount: -100,
+					UserId:         "id",
+					IdempotencyKey: "key",
+					Amount:         100,
 				},
 			},
 			wantResp: TopupBalanceResponse{
-				Code: http.StatusBadRequest,
+				Code: http.StatusNoContent,
 			},
-			wantErr: true,
-			mock:    func() {},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			u := usecase{
-				balance: tt.fields.balance,
-				auth:    tt.fields.auth,
-			}
-			tt.mock()
-			gotResp, err := u.TopupBalance(tt.args.ctx, tt.args.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("usecase.TopupBalance() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("usecase.TopupBalance() = %v, want %v", gotResp, tt.wantResp)
-			}
-		})
-	}
-}
-
-func Test_usecase_TransferBalance(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
-	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+			wantErr: false,
+			mock: func() {
+				idempotencyKey, _ := newIdempotencyKey("id", "key", idempotencyOperationTopupBalance, TopupBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					Amount:         100,
+				}, http.StatusNoContent, TopupBalanceResponse{})
 
-	type fields struct {
-		balance domainbalance.DomainItf
-		auth    domainauth.DomainItf
-	}
-	type args struct {
-		ctx context.Context
-		req TransferBalanceRequest
-	}
-	tests := []struct {
-		name     string
-		fields   fields
-		args     args
-		wantResp TransferBalanceResponse
-		wantErr  bool
-		mock     func()
-	}{
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(idempotencyKey, nil),
+				)
+			},
+		},
 		{
-			name: "success",
+			name: "success replay idempotency key after concurrent request",
 			fields: fields{
 				balance: mockDomainBalance,
 				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
 			},
 			args: args{
 				ctx: context.Background(),
-				req: TransferBalanceRequest{
-					UserId:     "id",
-					ToUsername: "tousername",
-					Amount:     100,
+				req: TopupBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					Amount:         100,
 				},
 			},
-			wantResp: TransferBalanceResponse{
+			wantResp: TopupBalanceResponse{
 				Code: http.StatusNoContent,
 			},
 			wantErr: false,
 			mock: func() {
+				idempotencyKey, _ := newIdempotencyKey("id", "key", idempotencyOperationTopupBalance, TopupBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					Amount:         100,
+				}, http.StatusNoContent, TopupBalanceResponse{})
+
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{
-						UserId: "id",
-						Amount: 100,
-					}, nil),
-					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(entity.IdempotencyKey{}, sql.ErrNoRows),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
 						Id:       "id",
-						Username: "tousername",
+						Currency: "IDR",
 					}, nil),
-					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
-						UserId:   "id",
-						ToUserId: "id",
-						Amount:   100,
-					}).Return(nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TOPUP, "IDR").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TOPUP,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().GrantBalanceByUserId(gomock.Any(), domainbalance.GrantBalanceByUserIdRequest{
+						UserId:         "id",
+						Amount:         100,
+						IdempotencyKey: idempotencyKey,
+					}).Return(domainbalance.ErrIdempotencyKeyExists),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(idempotencyKey, nil),
 				)
 			},
 		},
 		{
-			name: "error balance.DisburmentBalance",
+			name: "error idempotency key reused with different payload",
 			fields: fields{
 				balance: mockDomainBalance,
 				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
 			},
 			args: args{
 				ctx: context.Background(),
-				req: TransferBalanceRequest{
-					UserId:     "id",
-					ToUsername: "tousername",
-					Amount:     100,
+				req: TopupBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					Amount:         100,
 				},
 			},
-			wantResp: TransferBalanceResponse{
-				Code: http.StatusBadRequest,
+			wantResp: TopupBalanceResponse{
+				Code: http.StatusUnprocessableEntity,
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{
-						UserId: "id",
-						Amount: 100,
-					}, nil),
-					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
-						Id:       "id",
-						Username: "tousername",
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(entity.IdempotencyKey{
+						UserId:       "id",
+						Key:          "key",
+						Fingerprint:  "other",
+						ResponseCode: http.StatusNoContent,
+						ResponseBody: "{}",
 					}, nil),
-					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
-						UserId:   "id",
-						ToUserId: "id",
-						Amount:   100,
-					}).Return(fmt.Errorf("foo")),
 				)
 			},
 		},
 		{
-			name: "error auth.GetUserByUsername",
+			name: "error idempotency key too long",
 			fields: fields{
 				balance: mockDomainBalance,
 				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
 			},
 			args: args{
 				ctx: context.Background(),
-				req: TransferBalanceRequest{
-					UserId:     "id",
-					ToUsername: "tousername",
-					Amount:     100,
+				req: TopupBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: strings.Repeat("k", maxIdempotencyKeyLength+1),
+					Amount:         100,
 				},
 			},
-			wantResp: TransferBalanceResponse{
-				Code: http.StatusNotFound,
+			wantResp: TopupBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TopupBalanceRequest{
+					UserId: "id",
+					Amount: -100,
+				},
+			},
+			wantResp: TopupBalanceResponse{
+				Code: http.StatusBadRequest,
 			},
 			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "success with fee",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TopupBalanceRequest{
+					UserId: "id",
+					Amount: 100,
+				},
+			},
+			wantResp: TopupBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{
-						UserId: "id",
-						Amount: 100,
-					}, nil),
-					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
 						Id:       "id",
-						Username: "tousername",
-					}, sql.ErrNoRows),
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TOPUP, "IDR").Return([]entity.FeeRule{
+						{Id: 1, Operation: int(enum.TOPUP), FlatFee: 1},
+					}, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TOPUP,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().GrantBalanceByUserId(gomock.Any(), domainbalance.GrantBalanceByUserIdRequest{
+						UserId:  "id",
+						Amount:  100,
+						FeeRule: entity.FeeRule{Id: 1, Operation: int(enum.TOPUP), FlatFee: 1},
+					}).Return(nil),
 				)
 			},
 		},
 		{
-			name: "error balance.GetBalanceByUserId",
+			name: "error fee exceeds amount",
 			fields: fields{
 				balance: mockDomainBalance,
 				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
 			},
 			args: args{
 				ctx: context.Background(),
-				req: TransferBalanceRequest{
-					UserId:     "id",
-					ToUsername: "tousername",
-					Amount:     100,
+				req: TopupBalanceRequest{
+					UserId: "id",
+					Amount: 100,
 				},
 			},
-			wantResp: TransferBalanceResponse{
+			wantResp: TopupBalanceResponse{
 				Code: http.StatusBadRequest,
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{
-						UserId: "id",
-						Amount: 100,
-					}, fmt.Errorf("foo")),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TOPUP, "IDR").Return([]entity.FeeRule{
+						{Id: 1, Operation: int(enum.TOPUP), FlatFee: 100},
+					}, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), gomock.Any()).Return(reserved, nil),
+					mockDomainBalance.EXPECT().GrantBalanceByUserId(gomock.Any(), gomock.Any()).Return(domainbalance.ErrFeeExceedsAmount),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
 				)
 			},
 		},
 		{
-			name: "error insufficient balance",
+			name: "error amount with more decimals than the currency",
 			fields: fields{
 				balance: mockDomainBalance,
 				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
 			},
 			args: args{
 				ctx: context.Background(),
-				req: TransferBalanceRequest{
-					UserId:     "id",
-					ToUsername: "tousername",
-					Amount:     100,
+				req: TopupBalanceRequest{
+					UserId: "id",
+					Amount: 1500,
 				},
 			},
-			wantResp: TransferBalanceResponse{
+			wantResp: TopupBalanceResponse{
 				Code: http.StatusBadRequest,
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{
-						UserId: "id",
-						Amount: 99,
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "JPY",
 					}, nil),
 				)
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			u := usecase{
-				balance: tt.fields.balance,
-				auth:    tt.fields.auth,
-			}
-			tt.mock()
-			gotResp, err := u.TransferBalance(tt.args.ctx, tt.args.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("usecase.TransferBalance() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(gotResp, tt.wantResp) {
-				t.Errorf("usecase.TransferBalance() = %v, want %v", gotResp, tt.wantResp)
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TopupBalanceRequest{
+					UserId: "id",
+					Amount: 100,
+				},
+			},
+			wantResp: TopupBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error fee.GetFeeRules",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TopupBalanceRequest{
+					UserId: "id",
+					Amount: 100,
+				},
+			},
+			wantResp: TopupBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TOPUP, "IDR").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error limit exceeded",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TopupBalanceRequest{
+					UserId: "id",
+					Amount: 100,
+				},
+			},
+			wantResp: TopupBalanceResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TOPUP, "IDR").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), gomock.Any()).Return(usecaselimit.ReserveLimitResponse{
+						Code: http.StatusUnprocessableEntity,
+					}, apperror.New(http.StatusUnprocessableEntity, "limit_exceeded", "amount or number of operations exceeds the limit of the user")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+				auth:    tt.fields.auth,
+				limit:   tt.fields.limit,
+				fee:     tt.fields.fee,
+			}
+			tt.mock()
+			gotResp, err := u.TopupBalance(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.TopupBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.TopupBalance() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_TransferBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockUsecaseLimit := usecaselimit.NewMockUsecaseItf(ctrl)
+	mockDomainFee := domainfee.NewMockDomainItf(ctrl)
+	mockDomainFx := domainfx.NewMockDomainItf(ctrl)
+
+	reserved := usecaselimit.ReserveLimitResponse{
+		Code: http.StatusOK,
+		Reservation: usecaselimit.Reservation{
+			UserId:    "id",
+			Operation: enum.LIMIT_OPERATION_TRANSFER,
+			Amount:    100,
+		},
+	}
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+		auth    domainauth.DomainItf
+		limit   usecaselimit.UsecaseItf
+		fee     domainfee.DomainItf
+		fx      domainfx.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req TransferBalanceRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp TransferBalanceResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "id",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
+						UserId:   "id",
+						ToUserId: "id",
+						Amount:   100,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success from shared wallet",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:       "id",
+					FromWalletId: "wid",
+					ToUsername:   "tousername",
+					Amount:       100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainBalance.EXPECT().TransferFromSharedWallet(gomock.Any(), domainbalance.TransferFromSharedWalletRequest{
+						WalletId: "wid",
+						UserId:   "id",
+						ToUserId: "toid",
+						Amount:   100,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success from shared wallet of another currency than the member",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:       "id",
+					FromWalletId: "wid",
+					ToUsername:   "tousername",
+					Amount:       150000000,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				// The wallet is in IDR and its member in USD, none of the amount is counted against the USD limits of
+				// the member.
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+						Currency: "IDR",
+					}, nil),
+					mockDomainBalance.EXPECT().TransferFromSharedWallet(gomock.Any(), domainbalance.TransferFromSharedWalletRequest{
+						WalletId: "wid",
+						UserId:   "id",
+						ToUserId: "toid",
+						Amount:   150000000,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error from shared wallet spend limit exceeded",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:       "id",
+					FromWalletId: "wid",
+					ToUsername:   "tousername",
+					Amount:       100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainBalance.EXPECT().TransferFromSharedWallet(gomock.Any(), gomock.Any()).Return(domainbalance.SpendLimitExceededError{
+						UserId: "id",
+						Limit:  50,
+						Amount: 100,
+					}),
+				)
+			},
+		},
+		{
+			name: "error from shared wallet not a member",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:       "id",
+					FromWalletId: "wid",
+					ToUsername:   "tousername",
+					Amount:       100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainBalance.EXPECT().TransferFromSharedWallet(gomock.Any(), gomock.Any()).Return(domainbalance.ErrSharedWalletNotFound),
+				)
+			},
+		},
+		{
+			name: "success replay idempotency key",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToUsername:     "tousername",
+					Amount:         100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				idempotencyKey, _ := newIdempotencyKey("id", "key", idempotencyOperationTransferBalance, TransferBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToUsername:     "tousername",
+					Amount:         100,
+				}, http.StatusNoContent, TransferBalanceResponse{})
+
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(idempotencyKey, nil),
+				)
+			},
+		},
+		{
+			name: "error idempotency key reused with different payload",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToUsername:     "tousername",
+					Amount:         200,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				idempotencyKey, _ := newIdempotencyKey("id", "key", idempotencyOperationTransferBalance, TransferBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToUsername:     "tousername",
+					Amount:         100,
+				}, http.StatusNoContent, TransferBalanceResponse{})
+
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(idempotencyKey, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.GetIdempotencyKey",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToUsername:     "tousername",
+					Amount:         100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(entity.IdempotencyKey{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error balance.DisburmentBalance",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "id",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
+						UserId:   "id",
+						ToUserId: "id",
+						Amount:   100,
+					}).Return(fmt.Errorf("foo")),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
+				)
+			},
+		},
+		{
+			name: "error recipient not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "id",
+						Username: "tousername",
+					}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserByUsername",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     0,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error insufficient balance",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
+						UserId:   "id",
+						ToUserId: "toid",
+						Amount:   100,
+					}).Return(domainbalance.InsufficientBalanceError{
+						UserId:  "id",
+						Balance: 100,
+						Amount:  100,
+						Fee:     1,
+					}),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
+				)
+			},
+		},
+		{
+			name: "error sender not active",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusForbidden,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
+						UserId:   "id",
+						ToUserId: "toid",
+						Amount:   100,
+					}).Return(domainbalance.AccountNotActiveError{
+						UserId: "id",
+						Status: int(enum.USER_STATUS_FROZEN),
+					}),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
+				)
+			},
+		},
+		{
+			name: "error recipient not active",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
+						UserId:   "id",
+						ToUserId: "toid",
+						Amount:   100,
+					}).Return(domainbalance.AccountNotActiveError{
+						UserId: "toid",
+						Status: int(enum.USER_STATUS_CLOSED),
+					}),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
+				)
+			},
+		},
+		{
+			name: "success with fee",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return([]entity.FeeRule{
+						{Id: 1, Operation: int(enum.TRANSFER), MaxAmount: 100, FlatFee: 1},
+						{Id: 2, Operation: int(enum.TRANSFER), MinAmount: 100, RateBps: 100},
+					}, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
+						UserId:   "id",
+						ToUserId: "toid",
+						Amount:   100,
+						FeeRule:  entity.FeeRule{Id: 2, Operation: int(enum.TRANSFER), MinAmount: 100, RateBps: 100},
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error fee.GetFeeRules",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error limit exceeded",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), gomock.Any()).Return(usecaselimit.ReserveLimitResponse{
+						Code: http.StatusUnprocessableEntity,
+					}, apperror.New(http.StatusUnprocessableEntity, "limit_exceeded", "amount or number of operations exceeds the limit of the user")),
+				)
+			},
+		},
+		{
+			name: "success with conversion",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+					Convert:    true,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+						Currency: "IDR",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "USD",
+					}, nil),
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(entity.FxRate{
+						Id:            1,
+						BaseCurrency:  "USD",
+						QuoteCurrency: "IDR",
+						Rate:          1625000000000,
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "USD").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), gomock.Any()).Return(reserved, nil),
+					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), domainbalance.DisburmentBalanceRequest{
+						UserId:   "id",
+						ToUserId: "toid",
+						Amount:   100,
+						Conversion: domainbalance.Conversion{
+							FromCurrency: "USD",
+							ToCurrency:   "IDR",
+							ToAmount:     1625000,
+							Rate:         1625000000000,
+						},
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error currency mismatch without convert",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+						Currency: "IDR",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "USD",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error conversion rate not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+					Convert:    true,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+						Currency: "IDR",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "USD",
+					}, nil),
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(entity.FxRate{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error balance.DisburmentBalance currency mismatch",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id: "id",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "").Return(nil, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), gomock.Any()).Return(reserved, nil),
+					mockDomainBalance.EXPECT().DisburmentBalance(gomock.Any(), gomock.Any()).Return(domainbalance.CurrencyMismatchError{
+						Currency:   "USD",
+						ToCurrency: "IDR",
+					}),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
+				)
+			},
+		},
+		{
+			name: "error amount with more decimals than the currency",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     105,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+						Currency: "IDR",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error amount from shared wallet with more decimals than the currency",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:       "id",
+					FromWalletId: "wid",
+					ToUsername:   "tousername",
+					Amount:       1500,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+						Currency: "JPY",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+				fee:     mockDomainFee,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TransferBalanceRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: TransferBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+				auth:    tt.fields.auth,
+				limit:   tt.fields.limit,
+				fee:     tt.fields.fee,
+				fx:      tt.fields.fx,
+			}
+			tt.mock()
+			gotResp, err := u.TransferBalance(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.TransferBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.TransferBalance() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_RefundTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	transfer := entity.Transfer{
+		Id:             "tid",
+		FromUserId:     "fromid",
+		ToUserId:       "id",
+		Amount:         100,
+		RefundedAmount: 40,
+	}
+
+	type fields struct {
+		balance domainbalance.DomainItf
+		auth    domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req RefundTransferRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp RefundTransferResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success partial refund",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+					Amount:     10,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code:       http.StatusCreated,
+				TransferId: "tid",
+				Amount:     10,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(transfer, nil),
+					mockDomainBalance.EXPECT().RefundTransfer(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainbalance.RefundTransferRequest) error {
+						if req.RefundId == "" || req.TransferId != "tid" || req.UserId != "id" || req.Amount != 10 {
+							return fmt.Errorf("unexpected request %+v", req)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "success refund the rest of the transfer",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code:       http.StatusCreated,
+				TransferId: "tid",
+				Amount:     60,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(transfer, nil),
+					mockDomainBalance.EXPECT().RefundTransfer(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success replay idempotency key",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					TransferId:     "tid",
+					Amount:         10,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code:       http.StatusCreated,
+				RefundId:   "rid",
+				TransferId: "tid",
+				Amount:     10,
+			},
+			wantErr: false,
+			mock: func() {
+				idempotencyKey, _ := newIdempotencyKey("id", "key", idempotencyOperationRefundTransfer, RefundTransferRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					TransferId:     "tid",
+					Amount:         10,
+				}, http.StatusCreated, RefundTransferResponse{
+					RefundId:   "rid",
+					TransferId: "tid",
+					Amount:     10,
+				})
+
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(idempotencyKey, nil),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+					Amount:     -10,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error transfer not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+					Amount:     10,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(entity.Transfer{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error transfer not received by user",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "fromid",
+					TransferId: "tid",
+					Amount:     10,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(transfer, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.GetTransferById",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+					Amount:     10,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(entity.Transfer{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error refund exceeds transfer",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+					Amount:     61,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(transfer, nil),
+				)
+			},
+		},
+		{
+			name: "error transfer already fully refunded",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(entity.Transfer{
+						Id:             "tid",
+						FromUserId:     "fromid",
+						ToUserId:       "id",
+						Amount:         100,
+						RefundedAmount: 100,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.RefundTransfer refund exceeds transfer",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+					Amount:     60,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(transfer, nil),
+					mockDomainBalance.EXPECT().RefundTransfer(gomock.Any(), gomock.Any()).Return(domainbalance.RefundExceedsTransferError{
+						TransferId: "tid",
+						Refundable: 50,
+						Amount:     60,
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.RefundTransfer insufficient balance",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+					Amount:     60,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(transfer, nil),
+					mockDomainBalance.EXPECT().RefundTransfer(gomock.Any(), gomock.Any()).Return(domainbalance.InsufficientBalanceError{
+						UserId:  "id",
+						Balance: 10,
+						Amount:  60,
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.RefundTransfer",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RefundTransferRequest{
+					UserId:     "id",
+					TransferId: "tid",
+					Amount:     60,
+				},
+			},
+			wantResp: RefundTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTransferById(gomock.Any(), "tid").Return(transfer, nil),
+					mockDomainBalance.EXPECT().RefundTransfer(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+				auth:    tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.RefundTransfer(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.RefundTransfer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// A new refund gets a random id, so only its presence is checked.
+			if !tt.wantErr && gotResp.RefundId == "" {
+				t.Errorf("usecase.RefundTransfer() refund id is empty")
+			}
+			if tt.wantResp.RefundId == "" {
+				gotResp.RefundId = ""
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.RefundTransfer() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_CreateHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+		auth    domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req CreateHoldRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CreateHoldResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusCreated,
+				Hold: Hold{
+					Amount: 100,
+					Status: "active",
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id: "toid",
+					}, nil),
+					mockDomainBalance.EXPECT().CreateHold(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainbalance.CreateHoldRequest) error {
+						hold := req.Hold
+						if hold.Id == "" || hold.UserId != "id" || hold.TargetUserId != "toid" || hold.Amount != 100 {
+							return fmt.Errorf("unexpected request %+v", req)
+						}
+						if expiresIn := time.Until(hold.ExpiresAt); expiresIn <= defaultHoldExpiry-time.Minute || expiresIn > defaultHoldExpiry {
+							return fmt.Errorf("unexpected expiry %v", hold.ExpiresAt)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "success replay idempotency key",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToUsername:     "tousername",
+					Amount:         100,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusCreated,
+				Hold: Hold{
+					Id:        "hid",
+					Amount:    100,
+					Status:    "active",
+					ExpiresAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				idempotencyKey, _ := newIdempotencyKey("id", "key", idempotencyOperationCreateHold, CreateHoldRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToUsername:     "tousername",
+					Amount:         100,
+				}, http.StatusCreated, CreateHoldResponse{
+					Hold: Hold{
+						Id:        "hid",
+						Amount:    100,
+						Status:    "active",
+						ExpiresAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+					},
+				})
+
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(idempotencyKey, nil),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid expiry",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+					ExpiresIn:  59,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error recipient not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserByUsername",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error amount with more decimals than the currency",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     105,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Currency: "IDR",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.CreateHold insufficient balance",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id: "toid",
+					}, nil),
+					mockDomainBalance.EXPECT().CreateHold(gomock.Any(), gomock.Any()).Return(domainbalance.InsufficientBalanceError{
+						UserId:  "id",
+						Balance: 10,
+						Amount:  100,
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.CreateHold currency mismatch",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id: "toid",
+					}, nil),
+					mockDomainBalance.EXPECT().CreateHold(gomock.Any(), gomock.Any()).Return(domainbalance.CurrencyMismatchError{
+						Currency:   "USD",
+						ToCurrency: "IDR",
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.CreateHold",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateHoldRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: CreateHoldResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id: "toid",
+					}, nil),
+					mockDomainBalance.EXPECT().CreateHold(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+				auth:    tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.CreateHold(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CreateHold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// A new hold gets a random id and an expiry relative to now, so only their presence is checked.
+			if !tt.wantErr && (gotResp.Id == "" || gotResp.ExpiresAt.IsZero()) {
+				t.Errorf("usecase.CreateHold() hold id or expiry is empty")
+			}
+			if tt.wantResp.Id == "" {
+				gotResp.Id = ""
+			}
+			if tt.wantResp.ExpiresAt.IsZero() {
+				gotResp.ExpiresAt = time.Time{}
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CreateHold() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_CaptureHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockUsecaseLimit := usecaselimit.NewMockUsecaseItf(ctrl)
+
+	expiresAt := time.Now().Add(time.Hour)
+	hold := entity.Hold{
+		Id:           "hid",
+		UserId:       "fromid",
+		TargetUserId: "id",
+		Amount:       100,
+		Status:       int(enum.HOLD_STATUS_ACTIVE),
+		ExpiresAt:    expiresAt,
+	}
+	reserved := usecaselimit.ReserveLimitResponse{
+		Code: http.StatusOK,
+		Reservation: usecaselimit.Reservation{
+			UserId:    "fromid",
+			Operation: enum.LIMIT_OPERATION_TRANSFER,
+			Amount:    100,
+		},
+	}
+
+	type fields struct {
+		balance domainbalance.DomainItf
+		auth    domainauth.DomainItf
+		limit   usecaselimit.UsecaseItf
+	}
+	type args struct {
+		ctx context.Context
+		req CaptureHoldRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CaptureHoldResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success partial capture",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+					Amount: 40,
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusOK,
+				Hold: Hold{
+					Id:             "hid",
+					Amount:         100,
+					CapturedAmount: 40,
+					Status:         "captured",
+					ExpiresAt:      expiresAt,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "fromid",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    40,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().CaptureHold(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainbalance.CaptureHoldRequest) error {
+						if req.TransferId == "" || req.HoldId != "hid" || req.UserId != "id" || req.Amount != 40 {
+							return fmt.Errorf("unexpected request %+v", req)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "success capture the whole hold",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusOK,
+				Hold: Hold{
+					Id:             "hid",
+					Amount:         100,
+					CapturedAmount: 100,
+					Status:         "captured",
+					ExpiresAt:      expiresAt,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "fromid",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().CaptureHold(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+					Amount: -10,
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error hold not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(entity.Hold{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error hold not placed for user",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "fromid",
+					HoldId: "hid",
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.GetHoldById",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(entity.Hold{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error hold expired",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				expiredHold := hold
+				expiredHold.ExpiresAt = time.Now().Add(-time.Minute)
+
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(expiredHold, nil),
+				)
+			},
+		},
+		{
+			name: "error capture exceeds hold",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+					Amount: 101,
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+				)
+			},
+		},
+		{
+			name: "error limit exceeded",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusUnprocessableEntity,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), gomock.Any()).Return(usecaselimit.ReserveLimitResponse{
+						Code: http.StatusUnprocessableEntity,
+					}, apperror.New(http.StatusUnprocessableEntity, "limit_exceeded", "amount or number of operations exceeds the limit of the user")),
+				)
+			},
+		},
+		{
+			name: "error balance.CaptureHold hold not active",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "fromid",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().CaptureHold(gomock.Any(), gomock.Any()).Return(domainbalance.ErrHoldNotActive),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.CaptureHold",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				limit:   mockUsecaseLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CaptureHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: CaptureHoldResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+					mockUsecaseLimit.EXPECT().ReserveLimit(gomock.Any(), usecaselimit.ReserveLimitRequest{
+						UserId:    "fromid",
+						Operation: enum.LIMIT_OPERATION_TRANSFER,
+						Amount:    100,
+					}).Return(reserved, nil),
+					mockDomainBalance.EXPECT().CaptureHold(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+					mockUsecaseLimit.EXPECT().ReleaseLimit(gomock.Any(), usecaselimit.ReleaseLimitRequest{
+						Reservation: reserved.Reservation,
+					}),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+				auth:    tt.fields.auth,
+				limit:   tt.fields.limit,
+			}
+			tt.mock()
+			gotResp, err := u.CaptureHold(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CaptureHold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// A capture gets a random transfer id, so only its presence is checked.
+			if !tt.wantErr && gotResp.TransferId == "" {
+				t.Errorf("usecase.CaptureHold() transfer id is empty")
+			}
+			gotResp.TransferId = ""
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CaptureHold() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_VoidHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	expiresAt := time.Now().Add(time.Hour)
+	hold := entity.Hold{
+		Id:           "hid",
+		UserId:       "fromid",
+		TargetUserId: "id",
+		Amount:       100,
+		Status:       int(enum.HOLD_STATUS_ACTIVE),
+		ExpiresAt:    expiresAt,
+	}
+
+	type fields struct {
+		balance domainbalance.DomainItf
+		auth    domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req VoidHoldRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp VoidHoldResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: VoidHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: VoidHoldResponse{
+				Code: http.StatusOK,
+				Hold: Hold{
+					Id:        "hid",
+					Amount:    100,
+					Status:    "voided",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+					mockDomainBalance.EXPECT().VoidHold(gomock.Any(), domainbalance.VoidHoldRequest{
+						HoldId: "hid",
+						UserId: "id",
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error hold not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: VoidHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: VoidHoldResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(entity.Hold{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error hold already captured",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: VoidHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: VoidHoldResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				capturedHold := hold
+				capturedHold.Status = int(enum.HOLD_STATUS_CAPTURED)
+
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(capturedHold, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.VoidHold hold not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: VoidHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: VoidHoldResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+					mockDomainBalance.EXPECT().VoidHold(gomock.Any(), gomock.Any()).Return(domainbalance.ErrHoldNotFound),
+				)
+			},
+		},
+		{
+			name: "error balance.VoidHold",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: VoidHoldRequest{
+					UserId: "id",
+					HoldId: "hid",
+				},
+			},
+			wantResp: VoidHoldResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHoldById(gomock.Any(), "hid").Return(hold, nil),
+					mockDomainBalance.EXPECT().VoidHold(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+				auth:    tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.VoidHold(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.VoidHold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.VoidHold() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_QuoteTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainFee := domainfee.NewMockDomainItf(ctrl)
+
+	rule := entity.FeeRule{Id: 1, Operation: int(enum.TRANSFER), FlatFee: 1, RateBps: 100}
+
+	type fields struct {
+		balance domainbalance.DomainItf
+		auth    domainauth.DomainItf
+		fee     domainfee.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req QuoteTransferRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp QuoteTransferResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: QuoteTransferResponse{
+				Code:   http.StatusOK,
+				Amount: 100,
+				Fee:    2,
+				Total:  102,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "IDR").Return([]entity.FeeRule{rule}, nil),
+					mockDomainBalance.EXPECT().GetFee(gomock.Any(), domainbalance.GetFeeRequest{
+						UserId:    "id",
+						Operation: enum.TRANSFER,
+						Amount:    100,
+						FeeRule:   rule,
+					}).Return(money.Money(2), nil),
+				)
+			},
+		},
+		{
+			name: "error balance.GetFee",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: QuoteTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "IDR").Return([]entity.FeeRule{rule}, nil),
+					mockDomainBalance.EXPECT().GetFee(gomock.Any(), gomock.Any()).Return(money.Money(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error amount with more decimals than the currency",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     105,
+				},
+			},
+			wantResp: QuoteTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: QuoteTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error fee.GetFeeRules",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: QuoteTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainFee.EXPECT().GetFeeRules(gomock.Any(), enum.TRANSFER, "IDR").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error recipient not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: QuoteTransferResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserByUsername",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     100,
+				},
+			},
+			wantResp: QuoteTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+				fee:     mockDomainFee,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     0,
+				},
+			},
+			wantResp: QuoteTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+				auth:    tt.fields.auth,
+				fee:     tt.fields.fee,
+			}
+			tt.mock()
+			gotResp, err := u.QuoteTransfer(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.QuoteTransfer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.QuoteTransfer() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_CreatePocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	lockedUntil := time.Now().Add(time.Hour)
+	lockedSince := time.Now().Add(-time.Hour)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req CreatePocketRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CreatePocketResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePocketRequest{
+					UserId:      "id",
+					Name:        " rent ",
+					GoalAmount:  100,
+					LockedUntil: &lockedUntil,
+				},
+			},
+			wantResp: CreatePocketResponse{
+				Code: http.StatusCreated,
+				Pocket: Pocket{
+					Name:        "rent",
+					GoalAmount:  100,
+					LockedUntil: &lockedUntil,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CreatePocket(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error empty name",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePocketRequest{
+					UserId: "id",
+					Name:   " ",
+				},
+			},
+			wantResp: CreatePocketResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error name too long",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePocketRequest{
+					UserId: "id",
+					Name:   strings.Repeat("a", maxPocketNameLength+1),
+				},
+			},
+			wantResp: CreatePocketResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error negative goal amount",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePocketRequest{
+					UserId:     "id",
+					Name:       "rent",
+					GoalAmount: -1,
+				},
+			},
+			wantResp: CreatePocketResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error locked until in the past",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePocketRequest{
+					UserId:      "id",
+					Name:        "rent",
+					LockedUntil: &lockedSince,
+				},
+			},
+			wantResp: CreatePocketResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error name taken",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePocketRequest{
+					UserId: "id",
+					Name:   "rent",
+				},
+			},
+			wantResp: CreatePocketResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CreatePocket(gomock.Any(), gomock.Any()).Return(domainbalance.ErrPocketNameTaken),
+				)
+			},
+		},
+		{
+			name: "error balance.CreatePocket",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePocketRequest{
+					UserId: "id",
+					Name:   "rent",
+				},
+			},
+			wantResp: CreatePocketResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CreatePocket(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.CreatePocket(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CreatePocket() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// A new pocket gets a random id and is created now, so only their presence is checked.
+			if !tt.wantErr && (gotResp.Id == "" || gotResp.CreatedAt.IsZero()) {
+				t.Errorf("usecase.CreatePocket() pocket id or creation time is empty")
+			}
+			gotResp.Id = ""
+			gotResp.CreatedAt = time.Time{}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CreatePocket() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListPockets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	createdAt := time.Now()
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListPocketsRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListPocketsResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListPocketsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListPocketsResponse{
+				Code: http.StatusOK,
+				Data: []Pocket{
+					{
+						Id:         "pid",
+						Name:       "rent",
+						Amount:     10,
+						GoalAmount: 100,
+						CreatedAt:  createdAt,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetPocketsByUserId(gomock.Any(), "id").Return([]entity.Pocket{
+						{
+							Id:         "pid",
+							UserId:     "id",
+							Name:       "rent",
+							Amount:     10,
+							GoalAmount: 100,
+							CreatedAt:  createdAt,
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success no pockets",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListPocketsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListPocketsResponse{
+				Code: http.StatusOK,
+				Data: []Pocket{},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetPocketsByUserId(gomock.Any(), "id").Return(nil, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.GetPocketsByUserId",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListPocketsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListPocketsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetPocketsByUserId(gomock.Any(), "id").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.ListPockets(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListPockets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListPockets() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_MovePocketBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req MovePocketBalanceRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp MovePocketBalanceResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().MovePocketBalance(gomock.Any(), domainbalance.MovePocketBalanceRequest{
+						UserId:     "id",
+						ToPocketId: "pid",
+						Amount:     10,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success replay idempotency key",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToPocketId:     "pid",
+					Amount:         10,
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				idempotencyKey, _ := newIdempotencyKey("id", "key", idempotencyOperationMovePocketBalance, MovePocketBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					ToPocketId:     "pid",
+					Amount:         10,
+				}, http.StatusNoContent, MovePocketBalanceResponse{
+					Code: http.StatusNoContent,
+				})
+
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(idempotencyKey, nil),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error same wallet",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:       "id",
+					FromPocketId: "pid",
+					ToPocketId:   "pid",
+					Amount:       10,
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error pocket not found",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().MovePocketBalance(gomock.Any(), gomock.Any()).Return(domainbalance.ErrPocketNotFound),
+				)
+			},
+		},
+		{
+			name: "error pocket locked",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:       "id",
+					FromPocketId: "pid",
+					Amount:       10,
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().MovePocketBalance(gomock.Any(), gomock.Any()).Return(domainbalance.PocketLockedError{
+						PocketId:    "pid",
+						LockedUntil: time.Now().Add(time.Hour),
+					}),
+				)
+			},
+		},
+		{
+			name: "error insufficient balance",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().MovePocketBalance(gomock.Any(), gomock.Any()).Return(domainbalance.InsufficientBalanceError{
+						UserId:  "id",
+						Balance: 5,
+						Amount:  10,
+					}),
+				)
+			},
+		},
+		{
+			name: "error account not active",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusForbidden,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().MovePocketBalance(gomock.Any(), gomock.Any()).Return(domainbalance.AccountNotActiveError{
+						UserId: "id",
+						Status: int(enum.USER_STATUS_FROZEN),
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.MovePocketBalance",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: MovePocketBalanceRequest{
+					UserId:     "id",
+					ToPocketId: "pid",
+					Amount:     10,
+				},
+			},
+			wantResp: MovePocketBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().MovePocketBalance(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.MovePocketBalance(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.MovePocketBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.MovePocketBalance() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_DepositSharedWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req DepositSharedWalletRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp DepositSharedWalletResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DepositSharedWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Amount:   10,
+				},
+			},
+			wantResp: DepositSharedWalletResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().DepositSharedWallet(gomock.Any(), domainbalance.DepositSharedWalletRequest{
+						WalletId: "wid",
+						UserId:   "id",
+						Amount:   10,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DepositSharedWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+				},
+			},
+			wantResp: DepositSharedWalletResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error viewer",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DepositSharedWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Amount:   10,
+				},
+			},
+			wantResp: DepositSharedWalletResponse{
+				Code: http.StatusForbidden,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().DepositSharedWallet(gomock.Any(), gomock.Any()).Return(domainbalance.ErrSharedWalletForbidden),
+				)
+			},
+		},
+		{
+			name: "error insufficient balance",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DepositSharedWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Amount:   10,
+				},
+			},
+			wantResp: DepositSharedWalletResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().DepositSharedWallet(gomock.Any(), gomock.Any()).Return(domainbalance.InsufficientBalanceError{
+						UserId:  "id",
+						Balance: 5,
+						Amount:  10,
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.DepositSharedWallet",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DepositSharedWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Amount:   10,
+				},
+			},
+			wantResp: DepositSharedWalletResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().DepositSharedWallet(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.DepositSharedWallet(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.DepositSharedWallet() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.DepositSharedWallet() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListBalancesRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListBalancesResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListBalancesRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListBalancesResponse{
+				Code: http.StatusOK,
+				Data: []Balance{
+					{
+						Currency:         "IDR",
+						Main:             true,
+						Balance:          100,
+						AvailableBalance: 70,
+						HeldBalance:      30,
+					},
+					{
+						Currency:         "USD",
+						Balance:          5,
+						AvailableBalance: 5,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{
+						UserId:     "id",
+						Amount:     100,
+						HeldAmount: 30,
+						Currency:   "IDR",
+					}, nil),
+					mockDomainBalance.EXPECT().GetCurrencyBalancesByUserId(gomock.Any(), "id").Return([]entity.CurrencyBalance{
+						{
+							UserId:   "id",
+							Currency: "USD",
+							Amount:   5,
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error balance.GetBalanceByUserId",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListBalancesRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListBalancesResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error balance.GetCurrencyBalancesByUserId",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListBalancesRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListBalancesResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{
+						UserId:   "id",
+						Currency: "IDR",
+					}, nil),
+					mockDomainBalance.EXPECT().GetCurrencyBalancesByUserId(gomock.Any(), "id").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.ListBalances(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListBalances() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListBalances() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_QuoteConversion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainFx := domainfx.NewMockDomainItf(ctrl)
+
+	rate := entity.FxRate{
+		Id:            1,
+		BaseCurrency:  "USD",
+		QuoteCurrency: "IDR",
+		Rate:          1625000000000,
+	}
+
+	type fields struct {
+		fx domainfx.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req QuoteConversionRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp QuoteConversionResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				fx: mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteConversionRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+				},
+			},
+			wantResp: QuoteConversionResponse{
+				Code: http.StatusOK,
+				Conversion: Conversion{
+					RateId:       1,
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+					ToAmount:     2437500,
+					Rate:         1625000000000,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(rate, nil),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				fx: mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteConversionRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       0,
+				},
+			},
+			wantResp: QuoteConversionResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid currency",
+			fields: fields{
+				fx: mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteConversionRequest{
+					UserId:       "id",
+					FromCurrency: "usd",
+					ToCurrency:   "IDR",
+					Amount:       150,
+				},
+			},
+			wantResp: QuoteConversionResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error same currency",
+			fields: fields{
+				fx: mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteConversionRequest{
+					UserId:       "id",
+					FromCurrency: "IDR",
+					ToCurrency:   "IDR",
+					Amount:       150,
+				},
+			},
+			wantResp: QuoteConversionResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error amount with more decimals than the currency",
+			fields: fields{
+				fx: mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteConversionRequest{
+					UserId:       "id",
+					FromCurrency: "JPY",
+					ToCurrency:   "USD",
+					Amount:       150,
+				},
+			},
+			wantResp: QuoteConversionResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error amount too small",
+			fields: fields{
+				fx: mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteConversionRequest{
+					UserId:       "id",
+					FromCurrency: "IDR",
+					ToCurrency:   "USD",
+					Amount:       100,
+				},
+			},
+			wantResp: QuoteConversionResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "IDR", "USD", gomock.Any()).Return(entity.FxRate{
+						Id:            2,
+						BaseCurrency:  "IDR",
+						QuoteCurrency: "USD",
+						Rate:          6150,
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error rate not found",
+			fields: fields{
+				fx: mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteConversionRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "XAF",
+					Amount:       150,
+				},
+			},
+			wantResp: QuoteConversionResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "XAF", gomock.Any()).Return(entity.FxRate{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error fx.GetFxRate",
+			fields: fields{
+				fx: mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: QuoteConversionRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+				},
+			},
+			wantResp: QuoteConversionResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(entity.FxRate{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				fx: tt.fields.fx,
+			}
+			tt.mock()
+			gotResp, err := u.QuoteConversion(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.QuoteConversion() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.QuoteConversion() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ConvertBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainFx := domainfx.NewMockDomainItf(ctrl)
+
+	rate := entity.FxRate{
+		Id:            1,
+		BaseCurrency:  "USD",
+		QuoteCurrency: "IDR",
+		Rate:          1625000000000,
+	}
+	conversion := Conversion{
+		RateId:       1,
+		FromCurrency: "USD",
+		ToCurrency:   "IDR",
+		Amount:       150,
+		ToAmount:     2437500,
+		Rate:         1625000000000,
+	}
+
+	type fields struct {
+		balance domainbalance.DomainItf
+		fx      domainfx.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ConvertBalanceRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ConvertBalanceResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+					RateId:       1,
+				},
+			},
+			wantResp: ConvertBalanceResponse{
+				Code:       http.StatusOK,
+				Conversion: conversion,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(rate, nil),
+					mockDomainBalance.EXPECT().ConvertBalance(gomock.Any(), domainbalance.ConvertBalanceRequest{
+						UserId: "id",
+						Amount: 150,
+						Conversion: domainbalance.Conversion{
+							FromCurrency: "USD",
+							ToCurrency:   "IDR",
+							ToAmount:     2437500,
+							Rate:         1625000000000,
+						},
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success replay idempotency key",
+			fields: fields{
+				balance: mockDomainBalance,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					FromCurrency:   "USD",
+					ToCurrency:     "IDR",
+					Amount:         150,
+				},
+			},
+			wantResp: ConvertBalanceResponse{
+				Code:       http.StatusOK,
+				Conversion: conversion,
+			},
+			wantErr: false,
+			mock: func() {
+				idempotencyKey, _ := newIdempotencyKey("id", "key", idempotencyOperationConvertBalance, ConvertBalanceRequest{
+					UserId:         "id",
+					IdempotencyKey: "key",
+					FromCurrency:   "USD",
+					ToCurrency:     "IDR",
+					Amount:         150,
+				}, http.StatusOK, ConvertBalanceResponse{
+					Conversion: conversion,
+				})
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetIdempotencyKey(gomock.Any(), "id", "key").Return(idempotencyKey, nil),
+				)
+			},
+		},
+		{
+			name: "error rate expired",
+			fields: fields{
+				balance: mockDomainBalance,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+					RateId:       2,
+				},
+			},
+			wantResp: ConvertBalanceResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(rate, nil),
+				)
+			},
+		},
+		{
+			name: "error rate not found",
+			fields: fields{
+				balance: mockDomainBalance,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+				},
+			},
+			wantResp: ConvertBalanceResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(entity.FxRate{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error balance.ConvertBalance insufficient balance",
+			fields: fields{
+				balance: mockDomainBalance,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+				},
+			},
+			wantResp: ConvertBalanceResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(rate, nil),
+					mockDomainBalance.EXPECT().ConvertBalance(gomock.Any(), gomock.Any()).Return(domainbalance.InsufficientBalanceError{
+						UserId:  "id",
+						Balance: 100,
+						Amount:  150,
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.ConvertBalance account not active",
+			fields: fields{
+				balance: mockDomainBalance,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+				},
+			},
+			wantResp: ConvertBalanceResponse{
+				Code: http.StatusForbidden,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(rate, nil),
+					mockDomainBalance.EXPECT().ConvertBalance(gomock.Any(), gomock.Any()).Return(domainbalance.AccountNotActiveError{
+						UserId: "id",
+						Status: int(enum.USER_STATUS_FROZEN),
+					}),
+				)
+			},
+		},
+		{
+			name: "error balance.ConvertBalance",
+			fields: fields{
+				balance: mockDomainBalance,
+				fx:      mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ConvertBalanceRequest{
+					UserId:       "id",
+					FromCurrency: "USD",
+					ToCurrency:   "IDR",
+					Amount:       150,
+				},
+			},
+			wantResp: ConvertBalanceResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "USD", "IDR", gomock.Any()).Return(rate, nil),
+					mockDomainBalance.EXPECT().ConvertBalance(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+				fx:      tt.fields.fx,
+			}
+			tt.mock()
+			gotResp, err := u.ConvertBalance(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ConvertBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ConvertBalance() = %v, want %v", gotResp, tt.wantResp)
 			}
 		})
 	}
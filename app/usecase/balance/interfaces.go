@@ -4,6 +4,18 @@ import "context"
 
 type UsecaseItf interface {
 	ReadBalanceByUserId(ctx context.Context, req ReadBalanceByUserIdRequest) (resp ReadBalanceByUserIdResponse, err error)
+	ListBalances(ctx context.Context, req ListBalancesRequest) (resp ListBalancesResponse, err error)
 	TopupBalance(ctx context.Context, req TopupBalanceRequest) (resp TopupBalanceResponse, err error)
 	TransferBalance(ctx context.Context, req TransferBalanceRequest) (resp TransferBalanceResponse, err error)
+	QuoteTransfer(ctx context.Context, req QuoteTransferRequest) (resp QuoteTransferResponse, err error)
+	RefundTransfer(ctx context.Context, req RefundTransferRequest) (resp RefundTransferResponse, err error)
+	CreateHold(ctx context.Context, req CreateHoldRequest) (resp CreateHoldResponse, err error)
+	CaptureHold(ctx context.Context, req CaptureHoldRequest) (resp CaptureHoldResponse, err error)
+	VoidHold(ctx context.Context, req VoidHoldRequest) (resp VoidHoldResponse, err error)
+	CreatePocket(ctx context.Context, req CreatePocketRequest) (resp CreatePocketResponse, err error)
+	ListPockets(ctx context.Context, req ListPocketsRequest) (resp ListPocketsResponse, err error)
+	MovePocketBalance(ctx context.Context, req MovePocketBalanceRequest) (resp MovePocketBalanceResponse, err error)
+	QuoteConversion(ctx context.Context, req QuoteConversionRequest) (resp QuoteConversionResponse, err error)
+	ConvertBalance(ctx context.Context, req ConvertBalanceRequest) (resp ConvertBalanceResponse, err error)
+	DepositSharedWallet(ctx context.Context, req DepositSharedWalletRequest) (resp DepositSharedWalletResponse, err error)
 }
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: app/usecase/balance/interfaces.go
+// Source: interfaces.go
 //
 // Generated by this command:
 //
-//	mockgen -source=app/usecase/balance/interfaces.go -destination=app/usecase/balance/mock.go -package=usecasebalance
+//	mockgen -source=interfaces.go -destination=mock.go -package=usecasebalance
 //
 
 // Package usecasebalance is a generated GoMock package.
@@ -39,6 +39,156 @@ func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
 	return m.recorder
 }
 
+// CaptureHold mocks base method.
+func (m *MockUsecaseItf) CaptureHold(ctx context.Context, req CaptureHoldRequest) (CaptureHoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, req)
+	ret0, _ := ret[0].(CaptureHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockUsecaseItfMockRecorder) CaptureHold(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockUsecaseItf)(nil).CaptureHold), ctx, req)
+}
+
+// ConvertBalance mocks base method.
+func (m *MockUsecaseItf) ConvertBalance(ctx context.Context, req ConvertBalanceRequest) (ConvertBalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertBalance", ctx, req)
+	ret0, _ := ret[0].(ConvertBalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConvertBalance indicates an expected call of ConvertBalance.
+func (mr *MockUsecaseItfMockRecorder) ConvertBalance(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertBalance", reflect.TypeOf((*MockUsecaseItf)(nil).ConvertBalance), ctx, req)
+}
+
+// CreateHold mocks base method.
+func (m *MockUsecaseItf) CreateHold(ctx context.Context, req CreateHoldRequest) (CreateHoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, req)
+	ret0, _ := ret[0].(CreateHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockUsecaseItfMockRecorder) CreateHold(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockUsecaseItf)(nil).CreateHold), ctx, req)
+}
+
+// CreatePocket mocks base method.
+func (m *MockUsecaseItf) CreatePocket(ctx context.Context, req CreatePocketRequest) (CreatePocketResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePocket", ctx, req)
+	ret0, _ := ret[0].(CreatePocketResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePocket indicates an expected call of CreatePocket.
+func (mr *MockUsecaseItfMockRecorder) CreatePocket(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePocket", reflect.TypeOf((*MockUsecaseItf)(nil).CreatePocket), ctx, req)
+}
+
+// DepositSharedWallet mocks base method.
+func (m *MockUsecaseItf) DepositSharedWallet(ctx context.Context, req DepositSharedWalletRequest) (DepositSharedWalletResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DepositSharedWallet", ctx, req)
+	ret0, _ := ret[0].(DepositSharedWalletResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DepositSharedWallet indicates an expected call of DepositSharedWallet.
+func (mr *MockUsecaseItfMockRecorder) DepositSharedWallet(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DepositSharedWallet", reflect.TypeOf((*MockUsecaseItf)(nil).DepositSharedWallet), ctx, req)
+}
+
+// ListBalances mocks base method.
+func (m *MockUsecaseItf) ListBalances(ctx context.Context, req ListBalancesRequest) (ListBalancesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalances", ctx, req)
+	ret0, _ := ret[0].(ListBalancesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalances indicates an expected call of ListBalances.
+func (mr *MockUsecaseItfMockRecorder) ListBalances(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalances", reflect.TypeOf((*MockUsecaseItf)(nil).ListBalances), ctx, req)
+}
+
+// ListPockets mocks base method.
+func (m *MockUsecaseItf) ListPockets(ctx context.Context, req ListPocketsRequest) (ListPocketsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPockets", ctx, req)
+	ret0, _ := ret[0].(ListPocketsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPockets indicates an expected call of ListPockets.
+func (mr *MockUsecaseItfMockRecorder) ListPockets(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPockets", reflect.TypeOf((*MockUsecaseItf)(nil).ListPockets), ctx, req)
+}
+
+// MovePocketBalance mocks base method.
+func (m *MockUsecaseItf) MovePocketBalance(ctx context.Context, req MovePocketBalanceRequest) (MovePocketBalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MovePocketBalance", ctx, req)
+	ret0, _ := ret[0].(MovePocketBalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MovePocketBalance indicates an expected call of MovePocketBalance.
+func (mr *MockUsecaseItfMockRecorder) MovePocketBalance(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MovePocketBalance", reflect.TypeOf((*MockUsecaseItf)(nil).MovePocketBalance), ctx, req)
+}
+
+// QuoteConversion mocks base method.
+func (m *MockUsecaseItf) QuoteConversion(ctx context.Context, req QuoteConversionRequest) (QuoteConversionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteConversion", ctx, req)
+	ret0, _ := ret[0].(QuoteConversionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteConversion indicates an expected call of QuoteConversion.
+func (mr *MockUsecaseItfMockRecorder) QuoteConversion(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteConversion", reflect.TypeOf((*MockUsecaseItf)(nil).QuoteConversion), ctx, req)
+}
+
+// QuoteTransfer mocks base method.
+func (m *MockUsecaseItf) QuoteTransfer(ctx context.Context, req QuoteTransferRequest) (QuoteTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteTransfer", ctx, req)
+	ret0, _ := ret[0].(QuoteTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteTransfer indicates an expected call of QuoteTransfer.
+func (mr *MockUsecaseItfMockRecorder) QuoteTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteTransfer", reflect.TypeOf((*MockUsecaseItf)(nil).QuoteTransfer), ctx, req)
+}
+
 // ReadBalanceByUserId mocks base method.
 func (m *MockUsecaseItf) ReadBalanceByUserId(ctx context.Context, req ReadBalanceByUserIdRequest) (ReadBalanceByUserIdResponse, error) {
 	m.ctrl.T.Helper()
@@ -54,6 +204,21 @@ func (mr *MockUsecaseItfMockRecorder) ReadBalanceByUserId(ctx, req any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadBalanceByUserId", reflect.TypeOf((*MockUsecaseItf)(nil).ReadBalanceByUserId), ctx, req)
 }
 
+// RefundTransfer mocks base method.
+func (m *MockUsecaseItf) RefundTransfer(ctx context.Context, req RefundTransferRequest) (RefundTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundTransfer", ctx, req)
+	ret0, _ := ret[0].(RefundTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundTransfer indicates an expected call of RefundTransfer.
+func (mr *MockUsecaseItfMockRecorder) RefundTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTransfer", reflect.TypeOf((*MockUsecaseItf)(nil).RefundTransfer), ctx, req)
+}
+
 // TopupBalance mocks base method.
 func (m *MockUsecaseItf) TopupBalance(ctx context.Context, req TopupBalanceRequest) (TopupBalanceResponse, error) {
 	m.ctrl.T.Helper()
@@ -83,3 +248,18 @@ func (mr *MockUsecaseItfMockRecorder) TransferBalance(ctx, req any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferBalance", reflect.TypeOf((*MockUsecaseItf)(nil).TransferBalance), ctx, req)
 }
+
+// VoidHold mocks base method.
+func (m *MockUsecaseItf) VoidHold(ctx context.Context, req VoidHoldRequest) (VoidHoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", ctx, req)
+	ret0, _ := ret[0].(VoidHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockUsecaseItfMockRecorder) VoidHold(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockUsecaseItf)(nil).VoidHold), ctx, req)
+}
@@ -0,0 +1,207 @@
+package usecasebalance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	maxPocketNameLength = 50
+)
+
+var (
+	errInvalidPocketName       = apperror.New(http.StatusBadRequest, "invalid_name", fmt.Sprintf("pocket name is required and must be at most %d characters", maxPocketNameLength))
+	errInvalidPocketGoalAmount = apperror.New(http.StatusBadRequest, "invalid_goal_amount", "pocket goal amount must not be negative")
+	errInvalidPocketLock       = apperror.New(http.StatusBadRequest, "invalid_locked_until", "pocket can only be locked until a time in the future")
+	errInvalidPocketMoveAmount = apperror.New(http.StatusBadRequest, "invalid_amount", "move amount must be greater than 0")
+	errInvalidPocketMove       = apperror.New(http.StatusBadRequest, "invalid_pocket", "money must move between two different wallets")
+	errPocketNotFound          = apperror.New(http.StatusNotFound, "pocket_not_found", "pocket does not exist or does not belong to the user")
+	errPocketNameTaken         = apperror.New(http.StatusConflict, "pocket_name_taken", "user already has a pocket with this name")
+	errPocketLocked            = apperror.New(http.StatusConflict, "pocket_locked", "money cannot be moved out of the pocket until it is unlocked")
+)
+
+func newPocket(pocket entity.Pocket) Pocket {
+	return Pocket{
+		Id:          pocket.Id,
+		Name:        pocket.Name,
+		Amount:      pocket.Amount,
+		GoalAmount:  pocket.GoalAmount,
+		LockedUntil: pocket.LockedUntil,
+		CreatedAt:   pocket.CreatedAt,
+	}
+}
+
+func (u usecase) CreatePocket(ctx context.Context, req CreatePocketRequest) (resp CreatePocketResponse, err error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxPocketNameLength {
+		return CreatePocketResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidPocketName
+	}
+
+	if req.GoalAmount < 0 {
+		return CreatePocketResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidPocketGoalAmount
+	}
+
+	now := time.Now()
+	if req.LockedUntil != nil && !req.LockedUntil.After(now) {
+		return CreatePocketResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidPocketLock
+	}
+
+	pocket := entity.Pocket{
+		Id:          uuid.NewString(),
+		UserId:      req.UserId,
+		Name:        req.Name,
+		GoalAmount:  req.GoalAmount,
+		LockedUntil: req.LockedUntil,
+		CreatedAt:   now.UTC(),
+	}
+
+	err = u.balance.CreatePocket(ctx, pocket)
+	if errors.Is(err, domainbalance.ErrPocketNameTaken) {
+		return CreatePocketResponse{
+			Code: http.StatusConflict,
+		}, errPocketNameTaken
+	}
+	if err != nil {
+		log.Errorln("CreatePocket.CreatePocket", err)
+		return CreatePocketResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return CreatePocketResponse{
+		Code:   http.StatusCreated,
+		Pocket: newPocket(pocket),
+	}, nil
+}
+
+func (u usecase) ListPockets(ctx context.Context, req ListPocketsRequest) (resp ListPocketsResponse, err error) {
+	pockets, err := u.balance.GetPocketsByUserId(ctx, req.UserId)
+	if err != nil {
+		log.Errorln("ListPockets.GetPocketsByUserId", err)
+		return ListPocketsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListPocketsResponse{
+		Code: http.StatusOK,
+		Data: make([]Pocket, 0, len(pockets)),
+	}
+
+	for _, pocket := range pockets {
+		resp.Data = append(resp.Data, newPocket(pocket))
+	}
+
+	return resp, nil
+}
+
+// MovePocketBalance moves money between the wallets of the user, an empty pocket id being the main wallet. It
+// does not count against the limits of the user since no money leaves their account.
+func (u usecase) MovePocketBalance(ctx context.Context, req MovePocketBalanceRequest) (resp MovePocketBalanceResponse, err error) {
+	if req.Amount <= 0 {
+		return MovePocketBalanceResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidPocketMoveAmount
+	}
+
+	if req.FromPocketId == req.ToPocketId {
+		return MovePocketBalanceResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidPocketMove
+	}
+
+	resp = MovePocketBalanceResponse{
+		Code: http.StatusNoContent,
+	}
+
+	idempotencyKey, err := newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationMovePocketBalance, req, resp.Code, resp)
+	if err != nil {
+		return MovePocketBalanceResponse{
+			Code: http.StatusBadRequest,
+		}, err
+	}
+
+	code, found, err := u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+	if err != nil {
+		return MovePocketBalanceResponse{
+			Code: code,
+		}, err
+	}
+	if found {
+		resp.Code = code
+		return resp, nil
+	}
+
+	err = u.balance.MovePocketBalance(ctx, domainbalance.MovePocketBalanceRequest{
+		UserId:         req.UserId,
+		FromPocketId:   req.FromPocketId,
+		ToPocketId:     req.ToPocketId,
+		Amount:         req.Amount,
+		IdempotencyKey: idempotencyKey,
+	})
+	if errors.Is(err, domainbalance.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race, so replay its result instead.
+		code, found, err = u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+		if err != nil || !found {
+			return MovePocketBalanceResponse{
+				Code: http.StatusConflict,
+			}, errIdempotencyKeyConflict.Wrap(domainbalance.ErrIdempotencyKeyExists)
+		}
+
+		resp.Code = code
+		return resp, nil
+	}
+	if errors.Is(err, domainbalance.ErrPocketNotFound) {
+		return MovePocketBalanceResponse{
+			Code: http.StatusNotFound,
+		}, errPocketNotFound
+	}
+	var pocketLockedErr domainbalance.PocketLockedError
+	if errors.As(err, &pocketLockedErr) {
+		return MovePocketBalanceResponse{
+			Code: http.StatusConflict,
+		}, errPocketLocked.Wrap(pocketLockedErr).WithDetails(map[string]interface{}{
+			"locked_until": pocketLockedErr.LockedUntil,
+		})
+	}
+	var insufficientBalanceErr domainbalance.InsufficientBalanceError
+	if errors.As(err, &insufficientBalanceErr) {
+		return MovePocketBalanceResponse{
+			Code: http.StatusBadRequest,
+		}, errInsufficientBalance.Wrap(insufficientBalanceErr).WithDetails(map[string]interface{}{
+			"balance": insufficientBalanceErr.Balance,
+			"amount":  insufficientBalanceErr.Amount,
+		})
+	}
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	if errors.As(err, &accountNotActiveErr) {
+		code, err = accountNotActiveError(accountNotActiveErr, req.UserId)
+		return MovePocketBalanceResponse{
+			Code: code,
+		}, err
+	}
+	if err != nil {
+		log.Errorln("MovePocketBalance.MovePocketBalance", err)
+		return MovePocketBalanceResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,155 @@
+package usecasebalance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+var (
+	errInvalidRefundAmount   = apperror.New(http.StatusBadRequest, "invalid_amount", "refund amount must be greater than 0, or omitted to refund the rest of the transfer")
+	errTransferNotFound      = apperror.New(http.StatusNotFound, "transfer_not_found", "transfer does not exist or was not received by this user")
+	errRefundExceedsTransfer = apperror.New(http.StatusUnprocessableEntity, "refund_exceeds_transfer", "refund amount exceeds what is left to refund of the transfer")
+)
+
+// RefundTransfer sends part or all of a received transfer back to its sender. An amount of 0 refunds
+// whatever is left of the transfer.
+func (u usecase) RefundTransfer(ctx context.Context, req RefundTransferRequest) (resp RefundTransferResponse, err error) {
+	if req.Amount < 0 {
+		return RefundTransferResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidRefundAmount
+	}
+
+	idempotencyKey, err := newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationRefundTransfer, req, http.StatusCreated, resp)
+	if err != nil {
+		return RefundTransferResponse{
+			Code: http.StatusBadRequest,
+		}, err
+	}
+
+	code, found, err := u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+	if err != nil {
+		return RefundTransferResponse{
+			Code: code,
+		}, err
+	}
+	if found {
+		resp.Code = code
+		return resp, nil
+	}
+
+	transfer, err := u.balance.GetTransferById(ctx, req.TransferId)
+	if err == sql.ErrNoRows || (err == nil && transfer.ToUserId != req.UserId) {
+		return RefundTransferResponse{
+			Code: http.StatusNotFound,
+		}, errTransferNotFound
+	}
+	if err != nil {
+		log.Errorln("RefundTransfer.GetTransferById", err)
+		return RefundTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = transfer.RefundableAmount()
+	}
+
+	// The refundable amount is checked again by RefundTransfer against the locked transfer.
+	if amount <= 0 || amount > transfer.RefundableAmount() {
+		return RefundTransferResponse{
+			Code: http.StatusUnprocessableEntity,
+		}, errRefundExceedsTransfer.WithDetails(map[string]interface{}{
+			"refundable": transfer.RefundableAmount(),
+			"amount":     amount,
+		})
+	}
+
+	resp = RefundTransferResponse{
+		Code:       http.StatusCreated,
+		RefundId:   uuid.NewString(),
+		TransferId: transfer.Id,
+		Amount:     amount,
+	}
+
+	idempotencyKey, err = newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationRefundTransfer, req, resp.Code, resp)
+	if err != nil {
+		return RefundTransferResponse{
+			Code: http.StatusInternalServerError,
+		}, err
+	}
+
+	err = u.balance.RefundTransfer(ctx, domainbalance.RefundTransferRequest{
+		RefundId:       resp.RefundId,
+		TransferId:     transfer.Id,
+		UserId:         req.UserId,
+		Amount:         amount,
+		IdempotencyKey: idempotencyKey,
+	})
+	if errors.Is(err, domainbalance.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race, so replay its result instead.
+		resp = RefundTransferResponse{}
+		code, found, err = u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+		if err != nil || !found {
+			return RefundTransferResponse{
+				Code: http.StatusConflict,
+			}, errIdempotencyKeyConflict.Wrap(domainbalance.ErrIdempotencyKeyExists)
+		}
+
+		resp.Code = code
+		return resp, nil
+	}
+	if errors.Is(err, domainbalance.ErrTransferNotFound) {
+		return RefundTransferResponse{
+			Code: http.StatusNotFound,
+		}, errTransferNotFound
+	}
+	var refundExceedsTransferErr domainbalance.RefundExceedsTransferError
+	if errors.As(err, &refundExceedsTransferErr) {
+		return RefundTransferResponse{
+			Code: http.StatusUnprocessableEntity,
+		}, errRefundExceedsTransfer.Wrap(refundExceedsTransferErr).WithDetails(map[string]interface{}{
+			"refundable": refundExceedsTransferErr.Refundable,
+			"amount":     refundExceedsTransferErr.Amount,
+		})
+	}
+	var insufficientBalanceErr domainbalance.InsufficientBalanceError
+	if errors.As(err, &insufficientBalanceErr) {
+		return RefundTransferResponse{
+			Code: http.StatusBadRequest,
+		}, errInsufficientBalance.Wrap(insufficientBalanceErr).WithDetails(map[string]interface{}{
+			"balance": insufficientBalanceErr.Balance,
+			"amount":  insufficientBalanceErr.Amount,
+		})
+	}
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	if errors.As(err, &accountNotActiveErr) {
+		code, err = accountNotActiveError(accountNotActiveErr, req.UserId)
+		return RefundTransferResponse{
+			Code: code,
+		}, err
+	}
+	var currencyMismatchErr domainbalance.CurrencyMismatchError
+	if errors.As(err, &currencyMismatchErr) {
+		code, err = currencyMismatchError(currencyMismatchErr)
+		return RefundTransferResponse{
+			Code: code,
+		}, err
+	}
+	if err != nil {
+		log.Errorln("RefundTransfer.RefundTransfer", err)
+		return RefundTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,147 @@
+package usecasebalance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+var (
+	errInvalidDepositAmount  = apperror.New(http.StatusBadRequest, "invalid_amount", "deposit amount must be greater than 0")
+	errSharedWalletNotFound  = apperror.New(http.StatusNotFound, "wallet_not_found", "wallet does not exist or the user is not a member of it")
+	errSharedWalletForbidden = apperror.New(http.StatusForbidden, "wallet_forbidden", "the role of the user in the wallet does not allow this operation")
+	errSpendLimitExceeded    = apperror.New(http.StatusUnprocessableEntity, "spend_limit_exceeded", "amount exceeds the spend limit of the user in the wallet")
+)
+
+// sharedWalletError maps the errors of moving money in or out of a shared wallet in the domain, userId is the
+// member moving it.
+func sharedWalletError(err error, userId string) (code int, appErr error) {
+	var spendLimitExceededErr domainbalance.SpendLimitExceededError
+	var insufficientBalanceErr domainbalance.InsufficientBalanceError
+	var accountNotActiveErr domainbalance.AccountNotActiveError
+	var currencyMismatchErr domainbalance.CurrencyMismatchError
+	switch {
+	case errors.Is(err, domainbalance.ErrSharedWalletNotFound):
+		return http.StatusNotFound, errSharedWalletNotFound
+	case errors.Is(err, domainbalance.ErrSharedWalletForbidden):
+		return http.StatusForbidden, errSharedWalletForbidden
+	case errors.As(err, &spendLimitExceededErr):
+		return http.StatusUnprocessableEntity, errSpendLimitExceeded.Wrap(spendLimitExceededErr).WithDetails(map[string]interface{}{
+			"spend_limit": spendLimitExceededErr.Limit,
+			"amount":      spendLimitExceededErr.Amount,
+		})
+	case errors.As(err, &insufficientBalanceErr):
+		return http.StatusBadRequest, errInsufficientBalance.Wrap(insufficientBalanceErr).WithDetails(map[string]interface{}{
+			"balance": insufficientBalanceErr.Balance,
+			"amount":  insufficientBalanceErr.Amount,
+		})
+	case errors.As(err, &accountNotActiveErr):
+		return accountNotActiveError(accountNotActiveErr, userId)
+	case errors.As(err, &currencyMismatchErr):
+		return currencyMismatchError(currencyMismatchErr)
+	}
+
+	return http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+}
+
+// transferFromSharedWallet runs a transfer whose money comes from a shared wallet instead of the main wallet of
+// the user. It is capped by the spend limit of the member in the wallet and not by their own limits, which are in
+// their currency while the amount is in the currency of the wallet, and is not charged a fee.
+func (u usecase) transferFromSharedWallet(ctx context.Context, req TransferBalanceRequest, toUserId string, idempotencyKey entity.IdempotencyKey) (resp TransferBalanceResponse, err error) {
+	resp = TransferBalanceResponse{
+		Code: http.StatusNoContent,
+	}
+
+	err = u.balance.TransferFromSharedWallet(ctx, domainbalance.TransferFromSharedWalletRequest{
+		WalletId:       req.FromWalletId,
+		UserId:         req.UserId,
+		ToUserId:       toUserId,
+		Amount:         req.Amount,
+		IdempotencyKey: idempotencyKey,
+	})
+	if errors.Is(err, domainbalance.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race, so replay its result instead.
+		code, found, err := u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+		if err != nil || !found {
+			return TransferBalanceResponse{
+				Code: http.StatusConflict,
+			}, errIdempotencyKeyConflict.Wrap(domainbalance.ErrIdempotencyKeyExists)
+		}
+
+		resp.Code = code
+		return resp, nil
+	}
+	if err != nil {
+		log.Errorln("TransferBalance.TransferFromSharedWallet", err)
+		code, err := sharedWalletError(err, req.UserId)
+		return TransferBalanceResponse{
+			Code: code,
+		}, err
+	}
+
+	return resp, nil
+}
+
+// DepositSharedWallet moves money from the main wallet of a member into a shared wallet.
+func (u usecase) DepositSharedWallet(ctx context.Context, req DepositSharedWalletRequest) (resp DepositSharedWalletResponse, err error) {
+	if req.Amount <= 0 {
+		return DepositSharedWalletResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidDepositAmount
+	}
+
+	resp = DepositSharedWalletResponse{
+		Code: http.StatusNoContent,
+	}
+
+	idempotencyKey, err := newIdempotencyKey(req.UserId, req.IdempotencyKey, idempotencyOperationDepositSharedWallet, req, resp.Code, resp)
+	if err != nil {
+		return DepositSharedWalletResponse{
+			Code: http.StatusBadRequest,
+		}, err
+	}
+
+	code, found, err := u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+	if err != nil {
+		return DepositSharedWalletResponse{
+			Code: code,
+		}, err
+	}
+	if found {
+		resp.Code = code
+		return resp, nil
+	}
+
+	err = u.balance.DepositSharedWallet(ctx, domainbalance.DepositSharedWalletRequest{
+		WalletId:       req.WalletId,
+		UserId:         req.UserId,
+		Amount:         req.Amount,
+		IdempotencyKey: idempotencyKey,
+	})
+	if errors.Is(err, domainbalance.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race, so replay its result instead.
+		code, found, err = u.replayIdempotencyKey(ctx, idempotencyKey, &resp)
+		if err != nil || !found {
+			return DepositSharedWalletResponse{
+				Code: http.StatusConflict,
+			}, errIdempotencyKeyConflict.Wrap(domainbalance.ErrIdempotencyKeyExists)
+		}
+
+		resp.Code = code
+		return resp, nil
+	}
+	if err != nil {
+		log.Errorln("DepositSharedWallet.DepositSharedWallet", err)
+		code, err = sharedWalletError(err, req.UserId)
+		return DepositSharedWalletResponse{
+			Code: code,
+		}, err
+	}
+
+	return resp, nil
+}
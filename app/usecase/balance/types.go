@@ -1,29 +1,230 @@
 package usecasebalance
 
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/currency"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
 type ReadBalanceByUserIdRequest struct {
 	UserId string
 }
 
+// ReadBalanceByUserIdResponse splits Balance into the part that can be spent and the part reserved by holds.
+// Currency is the main currency of the user, which the balance is kept in.
 type ReadBalanceByUserIdResponse struct {
-	Code    int     `json:"-"`
-	Balance float64 `json:"balance"`
+	Code             int         `json:"-"`
+	Currency         string      `json:"currency"`
+	Balance          money.Money `json:"balance"`
+	AvailableBalance money.Money `json:"available_balance"`
+	HeldBalance      money.Money `json:"held_balance"`
 }
 
 type TopupBalanceRequest struct {
-	UserId string
-	Amount float64 `json:"amount"`
+	UserId         string
+	IdempotencyKey string      `json:"-"`
+	Amount         money.Money `json:"amount"`
 }
 
 type TopupBalanceResponse struct {
 	Code int `json:"-"`
 }
 
+// TransferBalanceRequest sends from the main wallet of the user, or from the shared wallet FromWalletId. Amount is
+// in the currency of the sender, and Convert has to be set to send it to a user of another currency.
 type TransferBalanceRequest struct {
-	UserId     string
-	ToUsername string  `json:"to_username"`
-	Amount     float64 `json:"amount"`
+	UserId         string
+	IdempotencyKey string      `json:"-"`
+	FromWalletId   string      `json:"from_wallet_id"`
+	ToUsername     string      `json:"to_username"`
+	Amount         money.Money `json:"amount"`
+	Convert        bool        `json:"convert"`
 }
 
 type TransferBalanceResponse struct {
 	Code int `json:"-"`
 }
+
+type QuoteTransferRequest struct {
+	UserId     string
+	ToUsername string      `json:"to_username"`
+	Amount     money.Money `json:"amount"`
+}
+
+// QuoteTransferResponse is what a transfer of Amount costs the sender, Total is Amount plus Fee.
+type QuoteTransferResponse struct {
+	Code   int         `json:"-"`
+	Amount money.Money `json:"amount"`
+	Fee    money.Money `json:"fee"`
+	Total  money.Money `json:"total"`
+}
+
+type RefundTransferRequest struct {
+	UserId         string
+	IdempotencyKey string      `json:"-"`
+	TransferId     string      `json:"transfer_id"`
+	Amount         money.Money `json:"amount"`
+}
+
+type RefundTransferResponse struct {
+	Code       int         `json:"-"`
+	RefundId   string      `json:"refund_id"`
+	TransferId string      `json:"transfer_id"`
+	Amount     money.Money `json:"amount"`
+}
+
+type CreateHoldRequest struct {
+	UserId         string
+	IdempotencyKey string      `json:"-"`
+	ToUsername     string      `json:"to_username"`
+	Amount         money.Money `json:"amount"`
+	ExpiresIn      int64       `json:"expires_in"`
+}
+
+type CreateHoldResponse struct {
+	Code int `json:"-"`
+	Hold
+}
+
+type CaptureHoldRequest struct {
+	UserId string
+	HoldId string      `json:"-"`
+	Amount money.Money `json:"amount"`
+}
+
+type CaptureHoldResponse struct {
+	Code int `json:"-"`
+	Hold
+}
+
+type VoidHoldRequest struct {
+	UserId string
+	HoldId string
+}
+
+type VoidHoldResponse struct {
+	Code int `json:"-"`
+	Hold
+}
+
+type Hold struct {
+	Id             string      `json:"id"`
+	Amount         money.Money `json:"amount"`
+	CapturedAmount money.Money `json:"captured_amount"`
+	Status         string      `json:"status"`
+	TransferId     string      `json:"transfer_id,omitempty"`
+	ExpiresAt      time.Time   `json:"expires_at"`
+}
+
+type CreatePocketRequest struct {
+	UserId      string
+	Name        string      `json:"name"`
+	GoalAmount  money.Money `json:"goal_amount"`
+	LockedUntil *time.Time  `json:"locked_until"`
+}
+
+type CreatePocketResponse struct {
+	Code int `json:"-"`
+	Pocket
+}
+
+type ListPocketsRequest struct {
+	UserId string
+}
+
+type ListPocketsResponse struct {
+	Code int      `json:"-"`
+	Data []Pocket `json:"data"`
+}
+
+// MovePocketBalanceRequest moves Amount between two wallets of the user, an empty pocket id is the main wallet.
+type MovePocketBalanceRequest struct {
+	UserId         string
+	IdempotencyKey string      `json:"-"`
+	FromPocketId   string      `json:"from_pocket_id"`
+	ToPocketId     string      `json:"to_pocket_id"`
+	Amount         money.Money `json:"amount"`
+}
+
+type MovePocketBalanceResponse struct {
+	Code int `json:"-"`
+}
+
+// Pocket is a named wallet of the user next to the main one. A GoalAmount of 0 means the pocket has no goal.
+type Pocket struct {
+	Id          string      `json:"id"`
+	Name        string      `json:"name"`
+	Amount      money.Money `json:"amount"`
+	GoalAmount  money.Money `json:"goal_amount"`
+	LockedUntil *time.Time  `json:"locked_until,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type DepositSharedWalletRequest struct {
+	UserId         string
+	IdempotencyKey string      `json:"-"`
+	WalletId       string      `json:"-"`
+	Amount         money.Money `json:"amount"`
+}
+
+type DepositSharedWalletResponse struct {
+	Code int `json:"-"`
+}
+
+type ListBalancesRequest struct {
+	UserId string
+}
+
+// ListBalancesResponse has the main balance of the user first, followed by its balances in other currencies.
+type ListBalancesResponse struct {
+	Code int       `json:"-"`
+	Data []Balance `json:"data"`
+}
+
+// Balance is what the user has in Currency. Only the main currency can have funds held.
+type Balance struct {
+	Currency         string      `json:"currency"`
+	Main             bool        `json:"main"`
+	Balance          money.Money `json:"balance"`
+	AvailableBalance money.Money `json:"available_balance"`
+	HeldBalance      money.Money `json:"held_balance"`
+}
+
+type QuoteConversionRequest struct {
+	UserId       string
+	FromCurrency string      `json:"from_currency"`
+	ToCurrency   string      `json:"to_currency"`
+	Amount       money.Money `json:"amount"`
+}
+
+type QuoteConversionResponse struct {
+	Code int `json:"-"`
+	Conversion
+}
+
+// ConvertBalanceRequest converts Amount of FromCurrency to ToCurrency. RateId is optional, when set the conversion
+// is only made at the rate of that quote.
+type ConvertBalanceRequest struct {
+	UserId         string
+	IdempotencyKey string      `json:"-"`
+	FromCurrency   string      `json:"from_currency"`
+	ToCurrency     string      `json:"to_currency"`
+	Amount         money.Money `json:"amount"`
+	RateId         int64       `json:"rate_id"`
+}
+
+type ConvertBalanceResponse struct {
+	Code int `json:"-"`
+	Conversion
+}
+
+// Conversion prices Amount of FromCurrency as ToAmount of ToCurrency at the rate RateId.
+type Conversion struct {
+	RateId       int64         `json:"rate_id"`
+	FromCurrency string        `json:"from_currency"`
+	ToCurrency   string        `json:"to_currency"`
+	Amount       money.Money   `json:"amount"`
+	ToAmount     money.Money   `json:"to_amount"`
+	Rate         currency.Rate `json:"rate"`
+}
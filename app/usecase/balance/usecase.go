@@ -3,16 +3,25 @@ package usecasebalance
 import (
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	domainfee "github.com/kevinsudut/wallet-system/app/domain/fee"
+	domainfx "github.com/kevinsudut/wallet-system/app/domain/fx"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
 )
 
 type usecase struct {
 	balance domainbalance.DomainItf
 	auth    domainauth.DomainItf
+	limit   usecaselimit.UsecaseItf
+	fee     domainfee.DomainItf
+	fx      domainfx.DomainItf
 }
 
-func Init(balance domainbalance.DomainItf, auth domainauth.DomainItf) UsecaseItf {
+func Init(balance domainbalance.DomainItf, auth domainauth.DomainItf, limit usecaselimit.UsecaseItf, fee domainfee.DomainItf, fx domainfx.DomainItf) UsecaseItf {
 	return &usecase{
 		balance: balance,
 		auth:    auth,
+		limit:   limit,
+		fee:     fee,
+		fx:      fx,
 	}
 }
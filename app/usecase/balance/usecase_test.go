@@ -6,12 +6,18 @@ import (
 
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	domainfee "github.com/kevinsudut/wallet-system/app/domain/fee"
+	domainfx "github.com/kevinsudut/wallet-system/app/domain/fx"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
 )
 
 func TestInit(t *testing.T) {
 	type args struct {
 		balance domainbalance.DomainItf
 		auth    domainauth.DomainItf
+		limit   usecaselimit.UsecaseItf
+		fee     domainfee.DomainItf
+		fx      domainfx.DomainItf
 	}
 	tests := []struct {
 		name string
@@ -22,16 +28,22 @@ func TestInit(t *testing.T) {
 			args: args{
 				balance: nil,
 				auth:    nil,
+				limit:   nil,
+				fee:     nil,
+				fx:      nil,
 			},
 			want: &usecase{
 				balance: nil,
 				auth:    nil,
+				limit:   nil,
+				fee:     nil,
+				fx:      nil,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := Init(tt.args.balance, tt.args.auth); !reflect.DeepEqual(got, tt.want) {
+			if got := Init(tt.args.balance, tt.args.auth, tt.args.limit, tt.args.fee, tt.args.fx); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Init() = %v, want %v", got, tt.want)
 			}
 		})
@@ -0,0 +1,225 @@
+package usecaselimit
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	domainlimit "github.com/kevinsudut/wallet-system/app/domain/limit"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+var (
+	errUserNotFound  = apperror.New(http.StatusNotFound, "user_not_found", "user does not exist")
+	errLimitExceeded = apperror.New(http.StatusUnprocessableEntity, "limit_exceeded", "amount or number of operations exceeds the limit of the user")
+)
+
+func (u usecase) ReadLimits(ctx context.Context, req ReadLimitsRequest) (resp ReadLimitsResponse, err error) {
+	tier, currency, limits, code, err := u.limitsByUserId(ctx, req.UserId)
+	if err != nil {
+		return ReadLimitsResponse{
+			Code: code,
+		}, err
+	}
+
+	now := time.Now()
+	resp = ReadLimitsResponse{
+		Code:     http.StatusOK,
+		Tier:     tier.String(),
+		Currency: currency,
+		Limits:   make([]Limit, 0, len(limits)),
+	}
+
+	for _, limit := range limits {
+		var usage entity.LimitUsage
+		if limit.Period != enum.LIMIT_PERIOD_TRANSACTION {
+			usage, err = u.limit.GetUsage(ctx, domainlimit.GetUsageRequest{
+				UserId:    req.UserId,
+				Operation: limit.Operation,
+				Window:    entity.NewLimitWindow(limit.Period, now, u.location),
+			})
+			if err != nil {
+				log.Errorln("ReadLimits.GetUsage", err)
+				return ReadLimitsResponse{
+					Code: http.StatusBadGateway,
+				}, apperror.ErrDependency.Wrap(err)
+			}
+		}
+
+		resp.Limits = append(resp.Limits, newLimit(limit, usage))
+	}
+
+	return resp, nil
+}
+
+// ReserveLimit counts an operation against every limit of the user before it runs and rejects it when any limit
+// would be exceeded. The counters are reserved so concurrent operations cannot overshoot a limit together, the
+// caller releases the reservation when the operation fails. While Redis is unavailable the limits are checked
+// against the ledger without reserving anything.
+func (u usecase) ReserveLimit(ctx context.Context, req ReserveLimitRequest) (resp ReserveLimitResponse, err error) {
+	_, _, limits, code, err := u.limitsByUserId(ctx, req.UserId)
+	if err != nil {
+		return ReserveLimitResponse{
+			Code: code,
+		}, err
+	}
+
+	now := time.Now()
+	reservation := Reservation{
+		UserId:    req.UserId,
+		Operation: req.Operation,
+		Amount:    req.Amount,
+	}
+
+	for _, limit := range limits {
+		if limit.Operation != req.Operation {
+			continue
+		}
+
+		usage := entity.LimitUsage{
+			Amount: req.Amount,
+			Count:  1,
+		}
+
+		if limit.Period != enum.LIMIT_PERIOD_TRANSACTION {
+			window := entity.NewLimitWindow(limit.Period, now, u.location)
+			usage, err = u.limit.ReserveUsage(ctx, domainlimit.ReserveUsageRequest{
+				UserId:    req.UserId,
+				Operation: req.Operation,
+				Window:    window,
+				Amount:    req.Amount,
+			})
+			if err == nil {
+				reservation.Windows = append(reservation.Windows, window)
+			} else {
+				log.Errorln("ReserveLimit.ReserveUsage", err)
+
+				usage, err = u.limit.GetUsage(ctx, domainlimit.GetUsageRequest{
+					UserId:    req.UserId,
+					Operation: req.Operation,
+					Window:    window,
+				})
+				if err != nil {
+					log.Errorln("ReserveLimit.GetUsage", err)
+					u.ReleaseLimit(ctx, ReleaseLimitRequest{
+						Reservation: reservation,
+					})
+					return ReserveLimitResponse{
+						Code: http.StatusBadGateway,
+					}, apperror.ErrDependency.Wrap(err)
+				}
+
+				usage.Amount += req.Amount
+				usage.Count++
+			}
+		}
+
+		if !limit.Allows(usage) {
+			u.ReleaseLimit(ctx, ReleaseLimitRequest{
+				Reservation: reservation,
+			})
+			return ReserveLimitResponse{
+				Code: http.StatusUnprocessableEntity,
+			}, errLimitExceeded.WithDetails(limitExceededDetails(limit, usage, req.Amount))
+		}
+	}
+
+	return ReserveLimitResponse{
+		Code:        http.StatusOK,
+		Reservation: reservation,
+	}, nil
+}
+
+// ReleaseLimit takes back a reservation of an operation that did not happen. Failures are only logged since the
+// counters are seeded again from the ledger once they expire.
+func (u usecase) ReleaseLimit(ctx context.Context, req ReleaseLimitRequest) {
+	for _, window := range req.Reservation.Windows {
+		err := u.limit.ReleaseUsage(ctx, domainlimit.ReserveUsageRequest{
+			UserId:    req.Reservation.UserId,
+			Operation: req.Reservation.Operation,
+			Window:    window,
+			Amount:    req.Reservation.Amount,
+		})
+		if err != nil {
+			log.Errorln("ReleaseLimit.ReleaseUsage", err)
+		}
+	}
+}
+
+// limitsByUserId returns the limits of the user in its main currency, the currency of the postings they count.
+func (u usecase) limitsByUserId(ctx context.Context, userId string) (tier enum.UserTier, currency string, limits []entity.Limit, code int, err error) {
+	user, err := u.auth.GetUserById(ctx, userId)
+	if err == sql.ErrNoRows {
+		return tier, currency, limits, http.StatusNotFound, errUserNotFound
+	}
+	if err != nil {
+		log.Errorln("limitsByUserId.GetUserById", err)
+		return tier, currency, limits, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+
+	tier, limits, err = u.limitsOfTier(ctx, enum.UserTier(user.Tier), user.Currency)
+	if err != nil {
+		log.Errorln("limitsByUserId.limitsOfTier", err)
+		return tier, currency, limits, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+
+	return tier, user.Currency, limits, http.StatusOK, nil
+}
+
+func newLimit(limit entity.Limit, usage entity.LimitUsage) Limit {
+	resp := Limit{
+		Operation: limit.Operation.String(),
+		Period:    string(limit.Period),
+	}
+
+	if limit.MaxAmount > 0 {
+		remainingAmount := limit.RemainingAmount(usage)
+		resp.MaxAmount = &limit.MaxAmount
+		resp.RemainingAmount = &remainingAmount
+		if limit.Period != enum.LIMIT_PERIOD_TRANSACTION {
+			resp.UsedAmount = &usage.Amount
+		}
+	}
+
+	if limit.MaxCount > 0 {
+		remainingCount := limit.RemainingCount(usage)
+		resp.MaxCount = &limit.MaxCount
+		resp.UsedCount = &usage.Count
+		resp.RemainingCount = &remainingCount
+	}
+
+	return resp
+}
+
+// limitExceededDetails describes the limit an operation of amount breached, usage already includes the operation.
+func limitExceededDetails(limit entity.Limit, usage entity.LimitUsage, amount money.Money) map[string]interface{} {
+	used := entity.LimitUsage{
+		Amount: usage.Amount - amount,
+		Count:  usage.Count - 1,
+	}
+
+	details := map[string]interface{}{
+		"operation": limit.Operation.String(),
+		"period":    string(limit.Period),
+		"amount":    amount,
+	}
+
+	if limit.MaxAmount > 0 && usage.Amount > limit.MaxAmount {
+		details["limit"] = "amount"
+		details["max"] = limit.MaxAmount
+		details["used"] = used.Amount
+		details["remaining"] = limit.RemainingAmount(used)
+		return details
+	}
+
+	details["limit"] = "count"
+	details["max"] = limit.MaxCount
+	details["used"] = used.Count
+	details["remaining"] = limit.RemainingCount(used)
+	return details
+}
@@ -0,0 +1,714 @@
+package usecaselimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainfx "github.com/kevinsudut/wallet-system/app/domain/fx"
+	domainlimit "github.com/kevinsudut/wallet-system/app/domain/limit"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	user = entity.User{
+		Id:       "id",
+		Username: "username",
+		Tier:     int(enum.USER_TIER_BASIC),
+		Currency: "IDR",
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_usecase_ReadLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainLimit := domainlimit.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainFx := domainfx.NewMockDomainItf(ctrl)
+
+	premiumUser := user
+	premiumUser.Tier = int(enum.USER_TIER_PREMIUM)
+
+	usdUser := user
+	usdUser.Currency = "USD"
+
+	type fields struct {
+		limit domainlimit.DomainItf
+		auth  domainauth.DomainItf
+		fx    domainfx.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ReadLimitsRequest
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		wantTier  string
+		wantCount int
+		wantCode  int
+		wantErr   bool
+		mock      func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantTier:  "basic",
+			wantCount: 6,
+			wantCode:  http.StatusOK,
+			wantErr:   false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, nil),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), gomock.Any()).Return(entity.LimitUsage{Amount: 10, Count: 1}, nil).Times(4),
+				)
+			},
+		},
+		{
+			name: "success premium",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantTier:  "premium",
+			wantCount: 6,
+			wantCode:  http.StatusOK,
+			wantErr:   false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(premiumUser, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_PREMIUM, "IDR").Return(nil, nil),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), gomock.Any()).Return(entity.LimitUsage{}, nil).Times(4),
+				)
+			},
+		},
+		{
+			name: "success with the limits of tier_limits",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantTier:  "basic",
+			wantCount: 2,
+			wantCode:  http.StatusOK,
+			wantErr:   false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return([]entity.Limit{
+						{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_TRANSACTION, MaxAmount: 1000 * money.Unit},
+						{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_DAY, MaxCount: 5},
+					}, nil),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), gomock.Any()).Return(entity.LimitUsage{}, nil),
+				)
+			},
+		},
+		{
+			name: "success with the default limits converted to the currency of the user",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantTier:  "basic",
+			wantCount: 6,
+			wantCode:  http.StatusOK,
+			wantErr:   false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(usdUser, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "USD").Return(nil, nil),
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "IDR", "USD", gomock.Any()).Return(entity.FxRate{Id: 1, Rate: 6150}, nil),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), gomock.Any()).Return(entity.LimitUsage{}, nil).Times(4),
+				)
+			},
+		},
+		{
+			name: "error GetFxRate",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantCode: http.StatusBadGateway,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(usdUser, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "USD").Return(nil, nil),
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "IDR", "USD", gomock.Any()).Return(entity.FxRate{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error GetTierLimits",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantCode: http.StatusBadGateway,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error GetUserById",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantCode: http.StatusBadGateway,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error GetUsage",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadLimitsRequest{
+					UserId: "id",
+				},
+			},
+			wantCode: http.StatusBadGateway,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, nil),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), gomock.Any()).Return(entity.LimitUsage{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				limit:    tt.fields.limit,
+				auth:     tt.fields.auth,
+				fx:       tt.fields.fx,
+				location: time.UTC,
+			}
+			tt.mock()
+			gotResp, err := u.ReadLimits(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ReadLimits() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp.Code != tt.wantCode || gotResp.Tier != tt.wantTier || len(gotResp.Limits) != tt.wantCount {
+				t.Errorf("usecase.ReadLimits() = %v, want code %v, tier %v and %v limits", gotResp, tt.wantCode, tt.wantTier, tt.wantCount)
+			}
+		})
+	}
+}
+
+func Test_usecase_ReserveLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainLimit := domainlimit.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainFx := domainfx.NewMockDomainItf(ctrl)
+
+	usdUser := user
+	usdUser.Currency = "USD"
+
+	isPeriod := func(period enum.LimitPeriod) gomock.Matcher {
+		return gomock.Cond(func(x any) bool {
+			switch req := x.(type) {
+			case domainlimit.ReserveUsageRequest:
+				return req.Window.Period == period && req.Operation == enum.LIMIT_OPERATION_TRANSFER && req.UserId == "id"
+			case domainlimit.GetUsageRequest:
+				return req.Window.Period == period && req.Operation == enum.LIMIT_OPERATION_TRANSFER && req.UserId == "id"
+			}
+			return false
+		})
+	}
+
+	type fields struct {
+		limit domainlimit.DomainItf
+		auth  domainauth.DomainItf
+		fx    domainfx.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ReserveLimitRequest
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantCode    int
+		wantWindows int
+		wantDetails map[string]interface{}
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReserveLimitRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Amount:    100 * money.Unit,
+				},
+			},
+			wantCode:    http.StatusOK,
+			wantWindows: 2,
+			wantErr:     false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(entity.LimitUsage{Amount: 100 * money.Unit, Count: 1}, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_MONTH)).Return(entity.LimitUsage{Amount: 100 * money.Unit, Count: 1}, nil),
+				)
+			},
+		},
+		{
+			name: "success redis unavailable",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReserveLimitRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Amount:    100 * money.Unit,
+				},
+			},
+			wantCode:    http.StatusOK,
+			wantWindows: 0,
+			wantErr:     false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(entity.LimitUsage{}, fmt.Errorf("foo")),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(entity.LimitUsage{}, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_MONTH)).Return(entity.LimitUsage{}, fmt.Errorf("foo")),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_MONTH)).Return(entity.LimitUsage{}, nil),
+				)
+			},
+		},
+		{
+			name: "error per transaction limit",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReserveLimitRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Amount:    5000000*money.Unit + 1,
+				},
+			},
+			wantCode: http.StatusUnprocessableEntity,
+			wantDetails: map[string]interface{}{
+				"operation": "transfer",
+				"period":    "transaction",
+				"amount":    5000000*money.Unit + 1,
+				"limit":     "amount",
+				"max":       5000000 * money.Unit,
+				"used":      money.Money(0),
+				"remaining": 5000000 * money.Unit,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, nil),
+				)
+			},
+		},
+		{
+			name: "error per transaction limit converted to the currency of the user",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReserveLimitRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Amount:    308 * money.Unit,
+				},
+			},
+			wantCode: http.StatusUnprocessableEntity,
+			wantDetails: map[string]interface{}{
+				"operation": "transfer",
+				"period":    "transaction",
+				"amount":    308 * money.Unit,
+				"limit":     "amount",
+				"max":       307*money.Unit + money.Unit/2,
+				"used":      money.Money(0),
+				"remaining": 307*money.Unit + money.Unit/2,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(usdUser, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "USD").Return(nil, nil),
+					mockDomainFx.EXPECT().GetFxRate(gomock.Any(), "IDR", "USD", gomock.Any()).Return(entity.FxRate{Id: 1, Rate: 6150}, nil),
+				)
+			},
+		},
+		{
+			name: "error monthly amount limit",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReserveLimitRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Amount:    100 * money.Unit,
+				},
+			},
+			wantCode: http.StatusUnprocessableEntity,
+			wantDetails: map[string]interface{}{
+				"operation": "transfer",
+				"period":    "month",
+				"amount":    100 * money.Unit,
+				"limit":     "amount",
+				"max":       50000000 * money.Unit,
+				"used":      49999950 * money.Unit,
+				"remaining": 50 * money.Unit,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(entity.LimitUsage{Amount: 100 * money.Unit, Count: 1}, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_MONTH)).Return(entity.LimitUsage{Amount: 50000050 * money.Unit, Count: 10}, nil),
+					mockDomainLimit.EXPECT().ReleaseUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(nil),
+					mockDomainLimit.EXPECT().ReleaseUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_MONTH)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error daily count limit with redis unavailable",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReserveLimitRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Amount:    100 * money.Unit,
+				},
+			},
+			wantCode: http.StatusUnprocessableEntity,
+			wantDetails: map[string]interface{}{
+				"operation": "transfer",
+				"period":    "day",
+				"amount":    100 * money.Unit,
+				"limit":     "count",
+				"max":       int64(50),
+				"used":      int64(50),
+				"remaining": int64(0),
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(entity.LimitUsage{}, fmt.Errorf("foo")),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(entity.LimitUsage{Amount: 500 * money.Unit, Count: 50}, nil),
+				)
+			},
+		},
+		{
+			name: "error GetUsage",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReserveLimitRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Amount:    100 * money.Unit,
+				},
+			},
+			wantCode: http.StatusBadGateway,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(user, nil),
+					mockDomainLimit.EXPECT().GetTierLimits(gomock.Any(), enum.USER_TIER_BASIC, "IDR").Return(nil, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(entity.LimitUsage{Amount: 100 * money.Unit, Count: 1}, nil),
+					mockDomainLimit.EXPECT().ReserveUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_MONTH)).Return(entity.LimitUsage{}, fmt.Errorf("foo")),
+					mockDomainLimit.EXPECT().GetUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_MONTH)).Return(entity.LimitUsage{}, fmt.Errorf("foo")),
+					mockDomainLimit.EXPECT().ReleaseUsage(gomock.Any(), isPeriod(enum.LIMIT_PERIOD_DAY)).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error GetUserById",
+			fields: fields{
+				limit: mockDomainLimit,
+				auth:  mockDomainAuth,
+				fx:    mockDomainFx,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReserveLimitRequest{
+					UserId:    "id",
+					Operation: enum.LIMIT_OPERATION_TRANSFER,
+					Amount:    100 * money.Unit,
+				},
+			},
+			wantCode: http.StatusBadGateway,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				limit:    tt.fields.limit,
+				auth:     tt.fields.auth,
+				fx:       tt.fields.fx,
+				location: time.UTC,
+			}
+			tt.mock()
+			gotResp, err := u.ReserveLimit(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ReserveLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp.Code != tt.wantCode || len(gotResp.Reservation.Windows) != tt.wantWindows {
+				t.Errorf("usecase.ReserveLimit() = %v, want code %v and %v reserved windows", gotResp, tt.wantCode, tt.wantWindows)
+			}
+			if tt.wantDetails != nil && !reflect.DeepEqual(apperror.From(err).Details, tt.wantDetails) {
+				t.Errorf("usecase.ReserveLimit() details = %v, want %v", apperror.From(err).Details, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func Test_usecase_ReleaseLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainLimit := domainlimit.NewMockDomainItf(ctrl)
+
+	window := entity.NewLimitWindow(enum.LIMIT_PERIOD_DAY, time.Now(), time.UTC)
+
+	type fields struct {
+		limit domainlimit.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ReleaseLimitRequest
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		mock   func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				limit: mockDomainLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReleaseLimitRequest{
+					Reservation: Reservation{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TOPUP,
+						Amount:    10,
+						Windows:   []entity.LimitWindow{window},
+					},
+				},
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDomainLimit.EXPECT().ReleaseUsage(gomock.Any(), domainlimit.ReserveUsageRequest{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TOPUP,
+						Window:    window,
+						Amount:    10,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "success nothing reserved",
+			fields: fields{
+				limit: mockDomainLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReleaseLimitRequest{
+					Reservation: Reservation{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TOPUP,
+						Amount:    10,
+					},
+				},
+			},
+			mock: func() {},
+		},
+		{
+			name: "error ReleaseUsage",
+			fields: fields{
+				limit: mockDomainLimit,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReleaseLimitRequest{
+					Reservation: Reservation{
+						UserId:    "id",
+						Operation: enum.LIMIT_OPERATION_TOPUP,
+						Amount:    10,
+						Windows:   []entity.LimitWindow{window},
+					},
+				},
+			},
+			mock: func() {
+				gomock.InOrder(
+					mockDomainLimit.EXPECT().ReleaseUsage(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				limit: tt.fields.limit,
+			}
+			tt.mock()
+			u.ReleaseLimit(tt.args.ctx, tt.args.req)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package usecaselimit
+
+import "context"
+
+type UsecaseItf interface {
+	ReadLimits(ctx context.Context, req ReadLimitsRequest) (resp ReadLimitsResponse, err error)
+	ReserveLimit(ctx context.Context, req ReserveLimitRequest) (resp ReserveLimitResponse, err error)
+	ReleaseLimit(ctx context.Context, req ReleaseLimitRequest)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/usecase/limit/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/usecase/limit/interfaces.go -destination=app/usecase/limit/mock.go -package=usecaselimit
+//
+
+// Package usecaselimit is a generated GoMock package.
+package usecaselimit
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsecaseItf is a mock of UsecaseItf interface.
+type MockUsecaseItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseItfMockRecorder
+}
+
+// MockUsecaseItfMockRecorder is the mock recorder for MockUsecaseItf.
+type MockUsecaseItfMockRecorder struct {
+	mock *MockUsecaseItf
+}
+
+// NewMockUsecaseItf creates a new mock instance.
+func NewMockUsecaseItf(ctrl *gomock.Controller) *MockUsecaseItf {
+	mock := &MockUsecaseItf{ctrl: ctrl}
+	mock.recorder = &MockUsecaseItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
+	return m.recorder
+}
+
+// ReadLimits mocks base method.
+func (m *MockUsecaseItf) ReadLimits(ctx context.Context, req ReadLimitsRequest) (ReadLimitsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadLimits", ctx, req)
+	ret0, _ := ret[0].(ReadLimitsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadLimits indicates an expected call of ReadLimits.
+func (mr *MockUsecaseItfMockRecorder) ReadLimits(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadLimits", reflect.TypeOf((*MockUsecaseItf)(nil).ReadLimits), ctx, req)
+}
+
+// ReleaseLimit mocks base method.
+func (m *MockUsecaseItf) ReleaseLimit(ctx context.Context, req ReleaseLimitRequest) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReleaseLimit", ctx, req)
+}
+
+// ReleaseLimit indicates an expected call of ReleaseLimit.
+func (mr *MockUsecaseItfMockRecorder) ReleaseLimit(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLimit", reflect.TypeOf((*MockUsecaseItf)(nil).ReleaseLimit), ctx, req)
+}
+
+// ReserveLimit mocks base method.
+func (m *MockUsecaseItf) ReserveLimit(ctx context.Context, req ReserveLimitRequest) (ReserveLimitResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveLimit", ctx, req)
+	ret0, _ := ret[0].(ReserveLimitResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveLimit indicates an expected call of ReserveLimit.
+func (mr *MockUsecaseItfMockRecorder) ReserveLimit(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveLimit", reflect.TypeOf((*MockUsecaseItf)(nil).ReserveLimit), ctx, req)
+}
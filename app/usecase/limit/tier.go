@@ -0,0 +1,96 @@
+package usecaselimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/currency"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// defaultTierLimitsCurrency is the currency of the amounts of defaultTierLimits.
+const defaultTierLimitsCurrency = "IDR"
+
+// defaultTierLimits holds the limits of every user tier without rows in tier_limits, per-transaction limits first
+// so they are checked before any counter is reserved. A zero cap is unlimited.
+var defaultTierLimits = map[enum.UserTier][]entity.Limit{
+	enum.USER_TIER_BASIC: {
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_TRANSACTION, MaxAmount: 10000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_DAY, MaxAmount: 20000000 * money.Unit, MaxCount: 10},
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_MONTH, MaxAmount: 100000000 * money.Unit, MaxCount: 100},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_TRANSACTION, MaxAmount: 5000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_DAY, MaxAmount: 10000000 * money.Unit, MaxCount: 50},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_MONTH, MaxAmount: 50000000 * money.Unit, MaxCount: 500},
+	},
+	enum.USER_TIER_VERIFIED: {
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_TRANSACTION, MaxAmount: 20000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_DAY, MaxAmount: 50000000 * money.Unit, MaxCount: 20},
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_MONTH, MaxAmount: 300000000 * money.Unit, MaxCount: 200},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_TRANSACTION, MaxAmount: 20000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_DAY, MaxAmount: 50000000 * money.Unit, MaxCount: 100},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_MONTH, MaxAmount: 300000000 * money.Unit, MaxCount: 1000},
+	},
+	enum.USER_TIER_PREMIUM: {
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_TRANSACTION, MaxAmount: 100000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_DAY, MaxAmount: 200000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TOPUP, Period: enum.LIMIT_PERIOD_MONTH, MaxAmount: 1000000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_TRANSACTION, MaxAmount: 100000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_DAY, MaxAmount: 200000000 * money.Unit},
+		{Operation: enum.LIMIT_OPERATION_TRANSFER, Period: enum.LIMIT_PERIOD_MONTH, MaxAmount: 1000000000 * money.Unit},
+	},
+}
+
+// limitsOfTier returns the limits of tier in currency from tier_limits, or its default limits when it has none
+// there. A user without a known tier gets the limits of the basic tier.
+func (u usecase) limitsOfTier(ctx context.Context, tier enum.UserTier, currencyCode string) (enum.UserTier, []entity.Limit, error) {
+	_, ok := defaultTierLimits[tier]
+	if !ok {
+		tier = enum.USER_TIER_BASIC
+	}
+
+	limits, err := u.limit.GetTierLimits(ctx, tier, currencyCode)
+	if err != nil {
+		return tier, nil, err
+	}
+
+	if len(limits) > 0 {
+		return tier, limits, nil
+	}
+
+	if currencyCode == defaultTierLimitsCurrency {
+		return tier, defaultTierLimits[tier], nil
+	}
+
+	limits, err = u.convertLimits(ctx, defaultTierLimits[tier], currencyCode)
+	if err != nil {
+		return tier, nil, err
+	}
+
+	return tier, limits, nil
+}
+
+// convertLimits converts the amounts of limits in defaultTierLimitsCurrency to currencyCode at the rate in effect
+// now, rounded to its minor unit. A cap never rounds down to zero, which would make it unlimited.
+func (u usecase) convertLimits(ctx context.Context, limits []entity.Limit, currencyCode string) ([]entity.Limit, error) {
+	rate, err := u.fx.GetFxRate(ctx, defaultTierLimitsCurrency, currencyCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := currency.Exponent(currencyCode)
+	resp := make([]entity.Limit, 0, len(limits))
+	for _, limit := range limits {
+		if limit.MaxAmount > 0 {
+			limit.MaxAmount = rate.Rate.Convert(limit.MaxAmount, exponent)
+			if limit.MaxAmount <= 0 {
+				limit.MaxAmount = money.MinorUnit(exponent)
+			}
+		}
+
+		resp = append(resp, limit)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package usecaselimit
+
+import (
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+type ReadLimitsRequest struct {
+	UserId string
+}
+
+// ReadLimitsResponse holds amounts in Currency, the main currency of the user.
+type ReadLimitsResponse struct {
+	Code     int     `json:"-"`
+	Tier     string  `json:"tier"`
+	Currency string  `json:"currency"`
+	Limits   []Limit `json:"limits"`
+}
+
+// Limit leaves out the amount or count fields a limit does not cap.
+type Limit struct {
+	Operation       string       `json:"operation"`
+	Period          string       `json:"period"`
+	MaxAmount       *money.Money `json:"max_amount,omitempty"`
+	UsedAmount      *money.Money `json:"used_amount,omitempty"`
+	RemainingAmount *money.Money `json:"remaining_amount,omitempty"`
+	MaxCount        *int64       `json:"max_count,omitempty"`
+	UsedCount       *int64       `json:"used_count,omitempty"`
+	RemainingCount  *int64       `json:"remaining_count,omitempty"`
+}
+
+type ReserveLimitRequest struct {
+	UserId    string
+	Operation enum.LimitOperation
+	Amount    money.Money
+}
+
+type ReserveLimitResponse struct {
+	Code        int
+	Reservation Reservation
+}
+
+// Reservation is an operation counted against the limits of its windows before it runs. Windows only holds
+// the windows whose counters were reserved, it is empty when the limits were checked against the ledger.
+type Reservation struct {
+	UserId    string
+	Operation enum.LimitOperation
+	Amount    money.Money
+	Windows   []entity.LimitWindow
+}
+
+type ReleaseLimitRequest struct {
+	Reservation Reservation
+}
@@ -0,0 +1,26 @@
+package usecaselimit
+
+import (
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainfx "github.com/kevinsudut/wallet-system/app/domain/fx"
+	domainlimit "github.com/kevinsudut/wallet-system/app/domain/limit"
+	"github.com/kevinsudut/wallet-system/pkg/helper/timezone"
+)
+
+type usecase struct {
+	limit    domainlimit.DomainItf
+	auth     domainauth.DomainItf
+	fx       domainfx.DomainItf
+	location *time.Location
+}
+
+func Init(limit domainlimit.DomainItf, auth domainauth.DomainItf, fx domainfx.DomainItf) UsecaseItf {
+	return &usecase{
+		limit:    limit,
+		auth:     auth,
+		fx:       fx,
+		location: timezone.Business(),
+	}
+}
@@ -0,0 +1,45 @@
+package usecaselimit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainfx "github.com/kevinsudut/wallet-system/app/domain/fx"
+	domainlimit "github.com/kevinsudut/wallet-system/app/domain/limit"
+)
+
+func TestInit(t *testing.T) {
+	type args struct {
+		limit domainlimit.DomainItf
+		auth  domainauth.DomainItf
+		fx    domainfx.DomainItf
+	}
+	tests := []struct {
+		name string
+		args args
+		want UsecaseItf
+	}{
+		{
+			args: args{
+				limit: nil,
+				auth:  nil,
+				fx:    nil,
+			},
+			want: &usecase{
+				limit:    nil,
+				auth:     nil,
+				fx:       nil,
+				location: time.UTC,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Init(tt.args.limit, tt.args.auth, tt.args.fx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Init() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
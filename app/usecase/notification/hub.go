@@ -0,0 +1,79 @@
+package usecasenotification
+
+import (
+	"sync"
+
+	"github.com/kevinsudut/wallet-system/app/entity"
+)
+
+// hub hands the notifications published by any instance to the clients connected to this one.
+type hub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan entity.Notification]struct{}
+}
+
+func newHub() *hub {
+	return &hub{
+		subscribers: make(map[string]map[chan entity.Notification]struct{}),
+	}
+}
+
+func (h *hub) subscribe(userId string) chan entity.Notification {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan entity.Notification, notificationBuffer)
+	if h.subscribers[userId] == nil {
+		h.subscribers[userId] = make(map[chan entity.Notification]struct{})
+	}
+	h.subscribers[userId][ch] = struct{}{}
+
+	return ch
+}
+
+// unsubscribe closes ch, unless it was already closed by the hub.
+func (h *hub) unsubscribe(userId string, ch chan entity.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(userId, ch)
+}
+
+// broadcast sends notification to the subscribers of its user. One that is not keeping up is closed rather than
+// waited for, so it resumes instead of holding back every other subscriber.
+func (h *hub) broadcast(notification entity.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[notification.UserId] {
+		select {
+		case ch <- notification:
+		default:
+			h.remove(notification.UserId, ch)
+		}
+	}
+}
+
+// closeAll closes every subscriber, which may have missed notifications and has to resume.
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for userId, subscribers := range h.subscribers {
+		for ch := range subscribers {
+			h.remove(userId, ch)
+		}
+	}
+}
+
+func (h *hub) remove(userId string, ch chan entity.Notification) {
+	if _, ok := h.subscribers[userId][ch]; !ok {
+		return
+	}
+
+	delete(h.subscribers[userId], ch)
+	if len(h.subscribers[userId]) == 0 {
+		delete(h.subscribers, userId)
+	}
+	close(ch)
+}
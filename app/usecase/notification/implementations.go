@@ -0,0 +1,173 @@
+package usecasenotification
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+var (
+	errInvalidLastEventId = apperror.New(http.StatusBadRequest, "invalid_last_event_id", "last event id is not the id of a notification")
+)
+
+func (u usecase) SubscribeNotifications(ctx context.Context, req SubscribeNotificationsRequest) (resp SubscribeNotificationsResponse, err error) {
+	if req.LastEventId != "" && !entity.IsValidNotificationId(req.LastEventId) {
+		return SubscribeNotificationsResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidLastEventId
+	}
+
+	// The subscription starts before the missed notifications are read, so none falls in between.
+	ch := u.hub.subscribe(req.UserId)
+
+	missed := []entity.Notification{}
+	for lastId := req.LastEventId; lastId != ""; {
+		notifications, err := u.notification.GetNotificationsAfter(ctx, req.UserId, lastId, missedNotificationsBatch)
+		if err != nil {
+			log.Errorln("SubscribeNotifications.GetNotificationsAfter", err)
+			u.hub.unsubscribe(req.UserId, ch)
+			return SubscribeNotificationsResponse{
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
+		}
+
+		missed = append(missed, notifications...)
+
+		lastId = ""
+		if len(notifications) == missedNotificationsBatch {
+			lastId = notifications[len(notifications)-1].Id
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		u.hub.unsubscribe(req.UserId, ch)
+	}()
+
+	return SubscribeNotificationsResponse{
+		Code:          http.StatusOK,
+		Missed:        missed,
+		Notifications: ch,
+	}, nil
+}
+
+// notifyBalanceChangedEvent notifies the user of a BalanceCredited or BalanceDebited event of the histories its
+// journal entry wrote for them and of their balance. notified holds what the batch of the event notified already:
+// a journal entry that changes two balances of a user, as a conversion does, writes an event for each, and the
+// balance read for one event of the batch already has the changes of the others. A fee is notified with the
+// journal entry it is charged on, which commits with it, so the event of the fee itself notifies nothing.
+func (u usecase) notifyBalanceChangedEvent(ctx context.Context, event entity.OutboxEvent, notified map[string]bool, now time.Time) (err error) {
+	var balanceChanged entity.BalanceChangedEvent
+	err = jsoniter.UnmarshalFromString(event.Payload, &balanceChanged)
+	if err != nil {
+		// Reading the event again would not make its payload valid.
+		log.Errorln("notifyBalanceChangedEvent.UnmarshalFromString", err)
+		return nil
+	}
+
+	if balanceChanged.EntryType == enum.FEE_CHARGE.String() {
+		return nil
+	}
+
+	historiesKey := balanceChanged.JournalEntryId + ":" + event.UserId
+	if !notified[historiesKey] {
+		err = u.notifyHistories(ctx, event.UserId, balanceChanged.JournalEntryId, now)
+		if err != nil {
+			return err
+		}
+		notified[historiesKey] = true
+	}
+
+	// balance.changed carries the main balance, which an event on a balance in another currency leaves as it is.
+	if balanceChanged.Currency != "" || notified[event.UserId] {
+		return nil
+	}
+
+	err = u.notifyBalance(ctx, event.UserId, now)
+	if err != nil {
+		return err
+	}
+	notified[event.UserId] = true
+
+	return nil
+}
+
+// notifyHistories publishes transaction.created for every history journalEntryId wrote for userId, with the fee
+// charged for it.
+func (u usecase) notifyHistories(ctx context.Context, userId string, journalEntryId string, now time.Time) (err error) {
+	histories, err := u.balance.GetHistoriesByJournalEntryId(ctx, journalEntryId)
+	if err != nil {
+		return err
+	}
+
+	usernames := make(map[string]string)
+	for _, history := range histories {
+		if history.UserId != userId {
+			continue
+		}
+
+		username, ok := usernames[history.TargetUserId]
+		if !ok {
+			user, err := u.auth.GetUserById(ctx, history.TargetUserId)
+			if err != nil {
+				return err
+			}
+			username = user.Username
+			usernames[history.TargetUserId] = username
+		}
+
+		err = u.publish(ctx, userId, enum.NOTIFICATION_TRANSACTION_CREATED, entity.TransactionCreatedNotification{
+			Id:                   history.Id,
+			JournalEntryId:       history.JournalEntryId,
+			Type:                 enum.HistoryType(history.Type).String(),
+			CounterpartyUsername: username,
+			Amount:               history.Amount,
+			Currency:             history.Currency,
+			Fee:                  history.Fee,
+			Notes:                history.Notes,
+			CreatedAt:            history.CreatedAt,
+		}, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notifyBalance publishes balance.changed with the current balance of userId.
+func (u usecase) notifyBalance(ctx context.Context, userId string, now time.Time) (err error) {
+	balance, err := u.balance.GetBalanceByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	return u.publish(ctx, userId, enum.NOTIFICATION_BALANCE_CHANGED, entity.BalanceChangedNotification{
+		Currency:         balance.Currency,
+		Balance:          balance.Amount,
+		AvailableBalance: balance.Available(),
+		HeldBalance:      balance.HeldAmount,
+	}, now)
+}
+
+func (u usecase) publish(ctx context.Context, userId string, notificationType enum.NotificationType, data interface{}, now time.Time) error {
+	bData, err := jsoniter.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.notification.PublishNotification(ctx, entity.Notification{
+		UserId:    userId,
+		Type:      string(notificationType),
+		Data:      bData,
+		CreatedAt: now,
+	})
+
+	return err
+}
@@ -0,0 +1,556 @@
+package usecasenotification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	domainnotification "github.com/kevinsudut/wallet-system/app/domain/notification"
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	createdAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	notification = entity.Notification{
+		Id:        "1-1",
+		UserId:    "id",
+		Type:      "balance.changed",
+		Data:      jsoniter.RawMessage(`{"balance":1000}`),
+		CreatedAt: createdAt,
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_usecase_SubscribeNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainNotification := domainnotification.NewMockDomainItf(ctrl)
+
+	page := make([]entity.Notification, missedNotificationsBatch)
+	for i := range page {
+		page[i] = notification
+		page[i].Id = fmt.Sprintf("2-%d", i)
+	}
+
+	type fields struct {
+		notification domainnotification.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req SubscribeNotificationsRequest
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantCode   int
+		wantMissed []entity.Notification
+		wantErr    bool
+		mock       func()
+	}{
+		{
+			name: "success without last event id",
+			fields: fields{
+				notification: mockDomainNotification,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SubscribeNotificationsRequest{
+					UserId: "id",
+				},
+			},
+			wantCode:   http.StatusOK,
+			wantMissed: []entity.Notification{},
+			wantErr:    false,
+			mock:       func() {},
+		},
+		{
+			name: "success missed notifications over pages",
+			fields: fields{
+				notification: mockDomainNotification,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SubscribeNotificationsRequest{
+					UserId:      "id",
+					LastEventId: "1-0",
+				},
+			},
+			wantCode:   http.StatusOK,
+			wantMissed: append(append([]entity.Notification{}, page...), notification),
+			wantErr:    false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainNotification.EXPECT().GetNotificationsAfter(gomock.Any(), "id", "1-0", int64(missedNotificationsBatch)).Return(page, nil),
+					mockDomainNotification.EXPECT().GetNotificationsAfter(gomock.Any(), "id", page[len(page)-1].Id, int64(missedNotificationsBatch)).Return([]entity.Notification{notification}, nil),
+				)
+			},
+		},
+		{
+			name: "error invalid last event id",
+			fields: fields{
+				notification: mockDomainNotification,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SubscribeNotificationsRequest{
+					UserId:      "id",
+					LastEventId: "foo",
+				},
+			},
+			wantCode:   http.StatusBadRequest,
+			wantMissed: nil,
+			wantErr:    true,
+			mock:       func() {},
+		},
+		{
+			name: "error GetNotificationsAfter",
+			fields: fields{
+				notification: mockDomainNotification,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: SubscribeNotificationsRequest{
+					UserId:      "id",
+					LastEventId: "1-0",
+				},
+			},
+			wantCode:   http.StatusBadGateway,
+			wantMissed: nil,
+			wantErr:    true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainNotification.EXPECT().GetNotificationsAfter(gomock.Any(), "id", "1-0", int64(missedNotificationsBatch)).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			u := usecase{
+				notification: tt.fields.notification,
+				hub:          newHub(),
+			}
+			ctx, cancel := context.WithCancel(tt.args.ctx)
+			defer cancel()
+			gotResp, err := u.SubscribeNotifications(ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.SubscribeNotifications() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp.Code != tt.wantCode {
+				t.Errorf("usecase.SubscribeNotifications() code = %v, want %v", gotResp.Code, tt.wantCode)
+			}
+			if !reflect.DeepEqual(gotResp.Missed, tt.wantMissed) {
+				t.Errorf("usecase.SubscribeNotifications() missed = %v, want %v", gotResp.Missed, tt.wantMissed)
+			}
+			if (gotResp.Notifications != nil) == tt.wantErr {
+				t.Errorf("usecase.SubscribeNotifications() notifications = %v, wantErr %v", gotResp.Notifications, tt.wantErr)
+			}
+			if tt.wantErr && len(u.hub.subscribers) != 0 {
+				t.Errorf("usecase.SubscribeNotifications() left %v subscribed", u.hub.subscribers)
+			}
+		})
+	}
+}
+
+func Test_usecase_dispatchNotificationEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainNotification := domainnotification.NewMockDomainItf(ctrl)
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainOutbox := domainoutbox.NewMockDomainItf(ctrl)
+
+	histories := []entity.History{
+		{Id: "hid1", JournalEntryId: "jid", UserId: "id", TargetUserId: "toid", Amount: -100, Type: int(enum.DEBIT), Notes: "Send money to toid", CreatedAt: createdAt},
+		{Id: "hid2", JournalEntryId: "jid", UserId: "toid", TargetUserId: "id", Amount: 100, Type: int(enum.CREDIT), Notes: "Receive money from id", CreatedAt: createdAt},
+		{Id: "hid3", JournalEntryId: "fid", UserId: "id", TargetUserId: "id", Amount: -10, Type: int(enum.FEE), Notes: "Transfer fee", ReferenceId: "jid", CreatedAt: createdAt},
+	}
+	newEvent := func(streamId string, userId string, eventType enum.EventType, payload string) entity.OutboxEvent {
+		return entity.OutboxEvent{
+			EventId:   "e" + streamId,
+			UserId:    userId,
+			Type:      string(eventType),
+			Version:   enum.EVENT_VERSION,
+			Payload:   payload,
+			CreatedAt: createdAt,
+			StreamId:  streamId,
+		}
+	}
+	debited := newEvent("1-0", "id", enum.EVENT_BALANCE_DEBITED, `{"user_id":"id","journal_entry_id":"jid","entry_type":"transfer","currency":""}`)
+	credited := newEvent("1-1", "toid", enum.EVENT_BALANCE_CREDITED, `{"user_id":"toid","journal_entry_id":"jid","entry_type":"transfer","currency":""}`)
+	feeDebited := newEvent("1-2", "id", enum.EVENT_BALANCE_DEBITED, `{"user_id":"id","journal_entry_id":"fid","entry_type":"fee_charge","currency":""}`)
+	registered := newEvent("1-3", "id", enum.EVENT_USER_REGISTERED, `{"user_id":"id"}`)
+
+	now := time.Date(2026, 10, 18, 9, 0, 1, 0, time.UTC)
+	newNotification := func(userId string, notificationType enum.NotificationType, data string) entity.Notification {
+		return entity.Notification{
+			UserId:    userId,
+			Type:      string(notificationType),
+			Data:      jsoniter.RawMessage(data),
+			CreatedAt: now,
+		}
+	}
+
+	type fields struct {
+		notification domainnotification.DomainItf
+		balance      domainbalance.DomainItf
+		auth         domainauth.DomainItf
+		outbox       domainoutbox.DomainItf
+	}
+	type args struct {
+		ctx     context.Context
+		pending bool
+		now     time.Time
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp int
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 4,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), domainoutbox.ReadEventsRequest{
+						Group:    notificationEventGroup,
+						Consumer: "consumer",
+						Count:    notificationEventBatch,
+						Block:    notificationEventBlock,
+					}).Return([]entity.OutboxEvent{debited, credited, feeDebited, registered}, nil),
+
+					mockDomainBalance.EXPECT().GetHistoriesByJournalEntryId(gomock.Any(), "jid").Return(histories, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "toid").Return(entity.User{Id: "toid", Username: "bar"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), newNotification("id", enum.NOTIFICATION_TRANSACTION_CREATED, `{"id":"hid1","journal_entry_id":"jid","type":"debit","counterparty_username":"bar","amount":-0.10,"fee":0,"notes":"Send money to toid","created_at":"2026-10-18T09:00:00Z"}`)).Return(entity.Notification{}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "foo"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), newNotification("id", enum.NOTIFICATION_TRANSACTION_CREATED, `{"id":"hid3","journal_entry_id":"fid","type":"fee","counterparty_username":"foo","amount":-0.01,"fee":0,"notes":"Transfer fee","created_at":"2026-10-18T09:00:00Z"}`)).Return(entity.Notification{}, nil),
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{UserId: "id", Amount: 890, HeldAmount: 90, Currency: "IDR"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), newNotification("id", enum.NOTIFICATION_BALANCE_CHANGED, `{"currency":"IDR","balance":0.89,"available_balance":0.80,"held_balance":0.09}`)).Return(entity.Notification{}, nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), notificationEventGroup, "1-0").Return(nil),
+
+					mockDomainBalance.EXPECT().GetHistoriesByJournalEntryId(gomock.Any(), "jid").Return(histories, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "foo"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), newNotification("toid", enum.NOTIFICATION_TRANSACTION_CREATED, `{"id":"hid2","journal_entry_id":"jid","type":"credit","counterparty_username":"foo","amount":0.10,"fee":0,"notes":"Receive money from id","created_at":"2026-10-18T09:00:00Z"}`)).Return(entity.Notification{}, nil),
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "toid").Return(entity.Balance{UserId: "toid", Amount: 100, Currency: "IDR"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), newNotification("toid", enum.NOTIFICATION_BALANCE_CHANGED, `{"currency":"IDR","balance":0.10,"available_balance":0.10,"held_balance":0}`)).Return(entity.Notification{}, nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), notificationEventGroup, "1-1").Return(nil),
+
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), notificationEventGroup, "1-2").Return(nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), notificationEventGroup, "1-3").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success conversion is notified once",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 2,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{
+						newEvent("1-0", "id", enum.EVENT_BALANCE_CREDITED, `{"user_id":"id","journal_entry_id":"cid","entry_type":"fx_conversion","currency":"USD"}`),
+						newEvent("1-1", "id", enum.EVENT_BALANCE_DEBITED, `{"user_id":"id","journal_entry_id":"cid","entry_type":"fx_conversion","currency":""}`),
+					}, nil),
+					mockDomainBalance.EXPECT().GetHistoriesByJournalEntryId(gomock.Any(), "cid").Return([]entity.History{
+						{Id: "hid", JournalEntryId: "cid", UserId: "id", TargetUserId: "id", Amount: 100, Currency: "IDR", Type: int(enum.CONVERSION), Notes: "Convert", CreatedAt: createdAt},
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "foo"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), newNotification("id", enum.NOTIFICATION_TRANSACTION_CREATED, `{"id":"hid","journal_entry_id":"cid","type":"conversion","counterparty_username":"foo","amount":0.10,"currency":"IDR","fee":0,"notes":"Convert","created_at":"2026-10-18T09:00:00Z"}`)).Return(entity.Notification{}, nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), notificationEventGroup, "1-0").Return(nil),
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "id").Return(entity.Balance{UserId: "id", Amount: 890, Currency: "IDR"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), newNotification("id", enum.NOTIFICATION_BALANCE_CHANGED, `{"currency":"IDR","balance":0.89,"available_balance":0.89,"held_balance":0}`)).Return(entity.Notification{}, nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), notificationEventGroup, "1-1").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success invalid payload is acknowledged",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 1,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{newEvent("1-0", "id", enum.EVENT_BALANCE_CREDITED, "foo")}, nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), notificationEventGroup, "1-0").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success pending does not block",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: true,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), domainoutbox.ReadEventsRequest{
+						Group:    notificationEventGroup,
+						Consumer: "consumer",
+						Pending:  true,
+						Count:    notificationEventBatch,
+					}).Return(nil, nil),
+				)
+			},
+		},
+		{
+			name: "error outbox.ReadEvents",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error balance.GetHistoriesByJournalEntryId leaves the event unacknowledged",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{debited, credited}, nil),
+					mockDomainBalance.EXPECT().GetHistoriesByJournalEntryId(gomock.Any(), "jid").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{credited}, nil),
+					mockDomainBalance.EXPECT().GetHistoriesByJournalEntryId(gomock.Any(), "jid").Return(histories, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error notification.PublishNotification",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{credited}, nil),
+					mockDomainBalance.EXPECT().GetHistoriesByJournalEntryId(gomock.Any(), "jid").Return(histories, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "foo"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), gomock.Any()).Return(entity.Notification{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error balance.GetBalanceByUserId",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{credited}, nil),
+					mockDomainBalance.EXPECT().GetHistoriesByJournalEntryId(gomock.Any(), "jid").Return(histories, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "foo"}, nil),
+					mockDomainNotification.EXPECT().PublishNotification(gomock.Any(), gomock.Any()).Return(entity.Notification{}, nil),
+					mockDomainBalance.EXPECT().GetBalanceByUserId(gomock.Any(), "toid").Return(entity.Balance{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error outbox.AckEvents",
+			fields: fields{
+				notification: mockDomainNotification,
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				outbox:       mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{registered}, nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), notificationEventGroup, "1-3").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			u := usecase{
+				notification: tt.fields.notification,
+				balance:      tt.fields.balance,
+				auth:         tt.fields.auth,
+				outbox:       tt.fields.outbox,
+				consumer:     "consumer",
+			}
+			gotResp, err := u.dispatchNotificationEvents(tt.args.ctx, tt.args.pending, tt.args.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.dispatchNotificationEvents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp != tt.wantResp {
+				t.Errorf("usecase.dispatchNotificationEvents() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_hub(t *testing.T) {
+	h := newHub()
+
+	ch := h.subscribe("id")
+	slow := h.subscribe("id")
+	other := h.subscribe("toid")
+
+	// Fills the buffer of slow, which is closed on the notification after.
+	for i := 0; i < notificationBuffer; i++ {
+		slow <- notification
+	}
+	h.broadcast(notification)
+
+	if got := <-ch; !reflect.DeepEqual(got, notification) {
+		t.Errorf("hub.broadcast() = %v, want %v", got, notification)
+	}
+	for range slow {
+	}
+	if _, ok := h.subscribers["id"][slow]; ok {
+		t.Errorf("hub.broadcast() kept a subscriber that is not keeping up")
+	}
+	if len(other) != 0 {
+		t.Errorf("hub.broadcast() sent to a subscriber of another user")
+	}
+
+	// Unsubscribing a subscriber the hub closed already does nothing.
+	h.unsubscribe("id", slow)
+
+	h.closeAll()
+	if _, ok := <-ch; ok {
+		t.Errorf("hub.closeAll() did not close a subscriber")
+	}
+	if _, ok := <-other; ok {
+		t.Errorf("hub.closeAll() did not close a subscriber")
+	}
+	if len(h.subscribers) != 0 {
+		t.Errorf("hub.closeAll() left %v subscribed", h.subscribers)
+	}
+}
@@ -0,0 +1,10 @@
+package usecasenotification
+
+import "context"
+
+type UsecaseItf interface {
+	SubscribeNotifications(ctx context.Context, req SubscribeNotificationsRequest) (resp SubscribeNotificationsResponse, err error)
+
+	RunNotificationSubscriber(ctx context.Context)
+	RunNotificationDispatcher(ctx context.Context)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/usecase/notification/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/usecase/notification/interfaces.go -destination=app/usecase/notification/mock.go -package=usecasenotification
+//
+
+// Package usecasenotification is a generated GoMock package.
+package usecasenotification
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsecaseItf is a mock of UsecaseItf interface.
+type MockUsecaseItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseItfMockRecorder
+}
+
+// MockUsecaseItfMockRecorder is the mock recorder for MockUsecaseItf.
+type MockUsecaseItfMockRecorder struct {
+	mock *MockUsecaseItf
+}
+
+// NewMockUsecaseItf creates a new mock instance.
+func NewMockUsecaseItf(ctrl *gomock.Controller) *MockUsecaseItf {
+	mock := &MockUsecaseItf{ctrl: ctrl}
+	mock.recorder = &MockUsecaseItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
+	return m.recorder
+}
+
+// RunNotificationDispatcher mocks base method.
+func (m *MockUsecaseItf) RunNotificationDispatcher(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunNotificationDispatcher", ctx)
+}
+
+// RunNotificationDispatcher indicates an expected call of RunNotificationDispatcher.
+func (mr *MockUsecaseItfMockRecorder) RunNotificationDispatcher(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunNotificationDispatcher", reflect.TypeOf((*MockUsecaseItf)(nil).RunNotificationDispatcher), ctx)
+}
+
+// RunNotificationSubscriber mocks base method.
+func (m *MockUsecaseItf) RunNotificationSubscriber(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunNotificationSubscriber", ctx)
+}
+
+// RunNotificationSubscriber indicates an expected call of RunNotificationSubscriber.
+func (mr *MockUsecaseItfMockRecorder) RunNotificationSubscriber(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunNotificationSubscriber", reflect.TypeOf((*MockUsecaseItf)(nil).RunNotificationSubscriber), ctx)
+}
+
+// SubscribeNotifications mocks base method.
+func (m *MockUsecaseItf) SubscribeNotifications(ctx context.Context, req SubscribeNotificationsRequest) (SubscribeNotificationsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeNotifications", ctx, req)
+	ret0, _ := ret[0].(SubscribeNotificationsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeNotifications indicates an expected call of SubscribeNotifications.
+func (mr *MockUsecaseItfMockRecorder) SubscribeNotifications(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeNotifications", reflect.TypeOf((*MockUsecaseItf)(nil).SubscribeNotifications), ctx, req)
+}
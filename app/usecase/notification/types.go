@@ -0,0 +1,19 @@
+package usecasenotification
+
+import "github.com/kevinsudut/wallet-system/app/entity"
+
+// SubscribeNotificationsRequest subscribes to the notifications of UserId. LastEventId is the id of the last
+// notification the client has seen, to be sent the ones after it first.
+type SubscribeNotificationsRequest struct {
+	UserId      string
+	LastEventId string
+}
+
+// SubscribeNotificationsResponse holds the notifications the client missed, followed by Notifications, which is
+// closed when the client is disconnected. A notification of Notifications that is not after the last one of
+// Missed was already in Missed. The subscription ends with the context it was made with.
+type SubscribeNotificationsResponse struct {
+	Code          int `json:"-"`
+	Missed        []entity.Notification
+	Notifications <-chan entity.Notification
+}
@@ -0,0 +1,49 @@
+package usecasenotification
+
+import (
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	domainnotification "github.com/kevinsudut/wallet-system/app/domain/notification"
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+)
+
+const (
+	// A client that resumes is sent what it missed in pages of missedNotificationsBatch, out of the notifications
+	// its stream still keeps.
+	missedNotificationsBatch = 100
+
+	// notificationBuffer is how many notifications a client can fall behind by before it is disconnected, to
+	// resume from the last one it has seen.
+	notificationBuffer = 64
+
+	notificationSubscribeRetryDelay = 5 * time.Second
+
+	// notificationEventGroup is the consumer group of the event stream every instance reads events for
+	// notifications in.
+	notificationEventGroup      = "notifications"
+	notificationEventBatch      = 100
+	notificationEventBlock      = 5 * time.Second
+	notificationEventRetryDelay = 5 * time.Second
+)
+
+type usecase struct {
+	notification domainnotification.DomainItf
+	balance      domainbalance.DomainItf
+	auth         domainauth.DomainItf
+	outbox       domainoutbox.DomainItf
+	consumer     string
+	hub          *hub
+}
+
+func Init(notification domainnotification.DomainItf, balance domainbalance.DomainItf, auth domainauth.DomainItf, outbox domainoutbox.DomainItf) UsecaseItf {
+	return &usecase{
+		notification: notification,
+		balance:      balance,
+		auth:         auth,
+		outbox:       outbox,
+		consumer:     domainoutbox.ConsumerName(),
+		hub:          newHub(),
+	}
+}
@@ -0,0 +1,49 @@
+package usecasenotification
+
+import (
+	"reflect"
+	"testing"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	domainnotification "github.com/kevinsudut/wallet-system/app/domain/notification"
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+)
+
+func TestInit(t *testing.T) {
+	type args struct {
+		notification domainnotification.DomainItf
+		balance      domainbalance.DomainItf
+		auth         domainauth.DomainItf
+		outbox       domainoutbox.DomainItf
+	}
+	tests := []struct {
+		name string
+		args args
+		want UsecaseItf
+	}{
+		{
+			args: args{
+				notification: nil,
+				balance:      nil,
+				auth:         nil,
+				outbox:       nil,
+			},
+			want: &usecase{
+				notification: nil,
+				balance:      nil,
+				auth:         nil,
+				outbox:       nil,
+				consumer:     domainoutbox.ConsumerName(),
+				hub:          newHub(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Init(tt.args.notification, tt.args.balance, tt.args.auth, tt.args.outbox); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Init() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
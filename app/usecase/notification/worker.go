@@ -0,0 +1,110 @@
+package usecasenotification
+
+import (
+	"context"
+	"time"
+
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+// RunNotificationSubscriber pushes the notifications published by any instance to the clients connected to this
+// one. Notifications published while it is not subscribed never reach them, so they are disconnected to resume.
+// It stops once ctx is done, disconnecting them too.
+func (u usecase) RunNotificationSubscriber(ctx context.Context) {
+	for {
+		err := u.notification.SubscribeNotifications(ctx, u.hub.broadcast)
+		u.hub.closeAll()
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		log.Errorln("RunNotificationSubscriber.SubscribeNotifications", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(notificationSubscribeRetryDelay):
+		}
+	}
+}
+
+// RunNotificationDispatcher turns the balance events of the event stream into notifications. Every instance runs
+// it in the same consumer group, which hands each event to one of them. It first reads back the events it was
+// handed and did not acknowledge, and does so again after every failure. It stops once ctx is done.
+func (u usecase) RunNotificationDispatcher(ctx context.Context) {
+	for {
+		err := u.outbox.CreateEventGroup(ctx, notificationEventGroup)
+		if err == nil {
+			break
+		}
+
+		log.Errorln("RunNotificationDispatcher.CreateEventGroup", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(notificationEventRetryDelay):
+		}
+	}
+
+	pending := true
+	for {
+		count, err := u.dispatchNotificationEvents(ctx, pending, time.Now())
+		if err != nil {
+			log.Errorln("RunNotificationDispatcher.dispatchNotificationEvents", err)
+			pending = true
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(notificationEventRetryDelay):
+			}
+			continue
+		}
+
+		if pending && count == 0 {
+			pending = false
+		}
+	}
+}
+
+// dispatchNotificationEvents reads a batch of events and publishes the notifications of the balance events among
+// them, created at now. An event is acknowledged once its notifications are published, so a failure leaves it and
+// the events after it to be read again, and the notifications of it already published are sent twice.
+func (u usecase) dispatchNotificationEvents(ctx context.Context, pending bool, now time.Time) (resp int, err error) {
+	req := domainoutbox.ReadEventsRequest{
+		Group:    notificationEventGroup,
+		Consumer: u.consumer,
+		Pending:  pending,
+		Count:    notificationEventBatch,
+	}
+	if !pending {
+		req.Block = notificationEventBlock
+	}
+
+	events, err := u.outbox.ReadEvents(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	notified := make(map[string]bool)
+	for _, event := range events {
+		if event.Type == string(enum.EVENT_BALANCE_CREDITED) || event.Type == string(enum.EVENT_BALANCE_DEBITED) {
+			err = u.notifyBalanceChangedEvent(ctx, event, notified, now)
+			if err != nil {
+				return resp, err
+			}
+		}
+
+		err = u.outbox.AckEvents(ctx, notificationEventGroup, event.StreamId)
+		if err != nil {
+			return resp, err
+		}
+
+		resp++
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,334 @@
+package usecasepaymentrequest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	domainpaymentrequest "github.com/kevinsudut/wallet-system/app/domain/paymentrequest"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+// idempotencyKeyPaymentRequest keys the transfer paying a request, so however often it is accepted it is paid once.
+const idempotencyKeyPaymentRequest = "payment_request:%s"
+
+var (
+	errInvalidAmount            = apperror.New(http.StatusBadRequest, "invalid_amount", "payment request amount must be greater than 0")
+	errInvalidPayer             = apperror.New(http.StatusBadRequest, "invalid_payer", "payment request payer must be another user")
+	errPayerNotFound            = apperror.New(http.StatusNotFound, "payer_not_found", "payer username does not exist")
+	errPaymentRequestNotFound   = apperror.New(http.StatusNotFound, "payment_request_not_found", "payment request does not exist")
+	errPaymentRequestNotPending = apperror.New(http.StatusConflict, "payment_request_not_pending", "payment request was already accepted, declined, canceled or has expired")
+)
+
+func newPaymentRequest(paymentRequest entity.PaymentRequest, usernames map[string]string, now time.Time) PaymentRequest {
+	return PaymentRequest{
+		Id:                paymentRequest.Id,
+		RequesterUsername: usernames[paymentRequest.RequesterId],
+		PayerUsername:     usernames[paymentRequest.PayerId],
+		Amount:            paymentRequest.Amount,
+		Status:            paymentRequest.StatusAt(now).String(),
+		ExpiresAt:         paymentRequest.ExpiresAt,
+		CreatedAt:         paymentRequest.CreatedAt,
+	}
+}
+
+// usernames resolves the requester and payer of every payment request to their username.
+func (u usecase) usernames(ctx context.Context, paymentRequests ...entity.PaymentRequest) (map[string]string, error) {
+	usernames := make(map[string]string)
+	for _, paymentRequest := range paymentRequests {
+		for _, userId := range []string{paymentRequest.RequesterId, paymentRequest.PayerId} {
+			if _, ok := usernames[userId]; ok {
+				continue
+			}
+
+			user, err := u.auth.GetUserById(ctx, userId)
+			if err != nil {
+				return nil, err
+			}
+			usernames[userId] = user.Username
+		}
+	}
+
+	return usernames, nil
+}
+
+// CreatePaymentRequest asks the payer to transfer the amount to the user before the request expires.
+func (u usecase) CreatePaymentRequest(ctx context.Context, req CreatePaymentRequestRequest) (resp CreatePaymentRequestResponse, err error) {
+	if req.Amount <= 0 {
+		return CreatePaymentRequestResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidAmount
+	}
+
+	payer, err := u.auth.GetUserByUsername(ctx, req.PayerUsername)
+	if err == sql.ErrNoRows {
+		return CreatePaymentRequestResponse{
+			Code: http.StatusNotFound,
+		}, errPayerNotFound
+	}
+	if err != nil {
+		log.Errorln("CreatePaymentRequest.GetUserByUsername", err)
+		return CreatePaymentRequestResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	if payer.Id == req.UserId {
+		return CreatePaymentRequestResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidPayer
+	}
+
+	requester, err := u.auth.GetUserById(ctx, req.UserId)
+	if err != nil {
+		log.Errorln("CreatePaymentRequest.GetUserById", err)
+		return CreatePaymentRequestResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	paymentRequest := entity.PaymentRequest{
+		Id:          uuid.NewString(),
+		RequesterId: req.UserId,
+		PayerId:     payer.Id,
+		Amount:      req.Amount,
+		Status:      int(enum.PAYMENT_REQUEST_STATUS_PENDING),
+		ExpiresAt:   now.Add(u.paymentRequestTTL),
+		CreatedAt:   now,
+	}
+
+	err = u.paymentRequest.InsertPaymentRequest(ctx, paymentRequest)
+	if err != nil {
+		log.Errorln("CreatePaymentRequest.InsertPaymentRequest", err)
+		return CreatePaymentRequestResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return CreatePaymentRequestResponse{
+		Code: http.StatusCreated,
+		PaymentRequest: newPaymentRequest(paymentRequest, map[string]string{
+			requester.Id: requester.Username,
+			payer.Id:     payer.Username,
+		}, now),
+	}, nil
+}
+
+// ListIncomingPaymentRequests lists the latest payment requests the user was asked to pay.
+func (u usecase) ListIncomingPaymentRequests(ctx context.Context, req ListPaymentRequestsRequest) (resp ListPaymentRequestsResponse, err error) {
+	paymentRequests, err := u.paymentRequest.GetPaymentRequestsByPayerId(ctx, req.UserId, maxListPaymentRequestsLimit)
+	if err != nil {
+		log.Errorln("ListIncomingPaymentRequests.GetPaymentRequestsByPayerId", err)
+		return ListPaymentRequestsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return u.listPaymentRequests(ctx, paymentRequests)
+}
+
+// ListOutgoingPaymentRequests lists the latest payment requests the user made.
+func (u usecase) ListOutgoingPaymentRequests(ctx context.Context, req ListPaymentRequestsRequest) (resp ListPaymentRequestsResponse, err error) {
+	paymentRequests, err := u.paymentRequest.GetPaymentRequestsByRequesterId(ctx, req.UserId, maxListPaymentRequestsLimit)
+	if err != nil {
+		log.Errorln("ListOutgoingPaymentRequests.GetPaymentRequestsByRequesterId", err)
+		return ListPaymentRequestsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return u.listPaymentRequests(ctx, paymentRequests)
+}
+
+func (u usecase) listPaymentRequests(ctx context.Context, paymentRequests []entity.PaymentRequest) (resp ListPaymentRequestsResponse, err error) {
+	usernames, err := u.usernames(ctx, paymentRequests...)
+	if err != nil {
+		log.Errorln("listPaymentRequests.usernames", err)
+		return ListPaymentRequestsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListPaymentRequestsResponse{
+		Code: http.StatusOK,
+		Data: make([]PaymentRequest, len(paymentRequests)),
+	}
+
+	now := time.Now()
+	for idx, paymentRequest := range paymentRequests {
+		resp.Data[idx] = newPaymentRequest(paymentRequest, usernames, now)
+	}
+
+	return resp, nil
+}
+
+// getPaymentRequestForUser reads a payment request that userId is the payer of, or the requester of when asPayer is
+// false. Anyone else is told it does not exist.
+func (u usecase) getPaymentRequestForUser(ctx context.Context, id string, userId string, asPayer bool) (resp entity.PaymentRequest, code int, err error) {
+	paymentRequest, err := u.paymentRequest.GetPaymentRequestById(ctx, id)
+	if err == nil {
+		owner := paymentRequest.RequesterId
+		if asPayer {
+			owner = paymentRequest.PayerId
+		}
+		if owner != userId {
+			err = sql.ErrNoRows
+		}
+	}
+	if err == sql.ErrNoRows {
+		return resp, http.StatusNotFound, errPaymentRequestNotFound
+	}
+	if err != nil {
+		log.Errorln("getPaymentRequestForUser.GetPaymentRequestById", err)
+		return resp, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+
+	return paymentRequest, http.StatusOK, nil
+}
+
+// AcceptPaymentRequest pays a pending request through the same path as /transfer. The request is claimed before
+// the transfer, so it can no longer be declined or canceled, and the transfer is keyed by the request, so
+// accepting again, concurrently or after a failure, replays it instead of paying twice.
+func (u usecase) AcceptPaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (resp UpdatePaymentRequestResponse, err error) {
+	paymentRequest, code, err := u.getPaymentRequestForUser(ctx, req.PaymentRequestId, req.UserId, true)
+	if err != nil {
+		return UpdatePaymentRequestResponse{
+			Code: code,
+		}, err
+	}
+
+	usernames, err := u.usernames(ctx, paymentRequest)
+	if err != nil {
+		log.Errorln("AcceptPaymentRequest.usernames", err)
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	now := time.Now()
+	status := paymentRequest.StatusAt(now)
+	if status == enum.PAYMENT_REQUEST_STATUS_ACCEPTED {
+		return UpdatePaymentRequestResponse{
+			Code:           http.StatusOK,
+			PaymentRequest: newPaymentRequest(paymentRequest, usernames, now),
+		}, nil
+	}
+	if status != enum.PAYMENT_REQUEST_STATUS_PENDING && status != enum.PAYMENT_REQUEST_STATUS_ACCEPTING {
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusConflict,
+		}, errPaymentRequestNotPending
+	}
+
+	err = u.paymentRequest.ClaimPaymentRequest(ctx, paymentRequest.Id, now)
+	if errors.Is(err, domainpaymentrequest.ErrPaymentRequestNotPending) {
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusConflict,
+		}, errPaymentRequestNotPending
+	}
+	if err != nil {
+		log.Errorln("AcceptPaymentRequest.ClaimPaymentRequest", err)
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	transferResp, err := u.balance.TransferBalance(ctx, usecasebalance.TransferBalanceRequest{
+		UserId:         paymentRequest.PayerId,
+		IdempotencyKey: fmt.Sprintf(idempotencyKeyPaymentRequest, paymentRequest.Id),
+		ToUsername:     usernames[paymentRequest.RequesterId],
+		Amount:         paymentRequest.Amount,
+	})
+	if err != nil {
+		// A conflict is a concurrent acceptance still paying the request, so its claim is left alone.
+		if transferResp.Code != http.StatusConflict {
+			releaseErr := u.paymentRequest.UpdatePaymentRequestStatus(ctx, paymentRequest.Id, enum.PAYMENT_REQUEST_STATUS_ACCEPTING, enum.PAYMENT_REQUEST_STATUS_PENDING)
+			if releaseErr != nil && !errors.Is(releaseErr, domainpaymentrequest.ErrPaymentRequestNotPending) {
+				log.Errorln("AcceptPaymentRequest.UpdatePaymentRequestStatus", releaseErr)
+			}
+		}
+
+		return UpdatePaymentRequestResponse{
+			Code: transferResp.Code,
+		}, err
+	}
+
+	// The transfer is done, so a request already accepted by a concurrent acceptance is not an error.
+	err = u.paymentRequest.AcceptPaymentRequest(ctx, paymentRequest.Id)
+	if err != nil && !errors.Is(err, domainpaymentrequest.ErrPaymentRequestNotPending) {
+		log.Errorln("AcceptPaymentRequest.AcceptPaymentRequest", err)
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	paymentRequest.Status = int(enum.PAYMENT_REQUEST_STATUS_ACCEPTED)
+
+	return UpdatePaymentRequestResponse{
+		Code:           http.StatusOK,
+		PaymentRequest: newPaymentRequest(paymentRequest, usernames, now),
+	}, nil
+}
+
+// DeclinePaymentRequest lets the payer turn down a pending request.
+func (u usecase) DeclinePaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (resp UpdatePaymentRequestResponse, err error) {
+	return u.closePaymentRequest(ctx, req, true, enum.PAYMENT_REQUEST_STATUS_DECLINED)
+}
+
+// CancelPaymentRequest lets the requester withdraw a pending request.
+func (u usecase) CancelPaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (resp UpdatePaymentRequestResponse, err error) {
+	return u.closePaymentRequest(ctx, req, false, enum.PAYMENT_REQUEST_STATUS_CANCELED)
+}
+
+// closePaymentRequest moves a pending request of the user to status without paying it.
+func (u usecase) closePaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest, asPayer bool, status enum.PaymentRequestStatus) (resp UpdatePaymentRequestResponse, err error) {
+	paymentRequest, code, err := u.getPaymentRequestForUser(ctx, req.PaymentRequestId, req.UserId, asPayer)
+	if err != nil {
+		return UpdatePaymentRequestResponse{
+			Code: code,
+		}, err
+	}
+
+	now := time.Now()
+	if paymentRequest.StatusAt(now) != enum.PAYMENT_REQUEST_STATUS_PENDING {
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusConflict,
+		}, errPaymentRequestNotPending
+	}
+
+	err = u.paymentRequest.UpdatePaymentRequestStatus(ctx, paymentRequest.Id, enum.PAYMENT_REQUEST_STATUS_PENDING, status)
+	if errors.Is(err, domainpaymentrequest.ErrPaymentRequestNotPending) {
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusConflict,
+		}, errPaymentRequestNotPending
+	}
+	if err != nil {
+		log.Errorln("closePaymentRequest.UpdatePaymentRequestStatus", err)
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	usernames, err := u.usernames(ctx, paymentRequest)
+	if err != nil {
+		log.Errorln("closePaymentRequest.usernames", err)
+		return UpdatePaymentRequestResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	paymentRequest.Status = int(status)
+
+	return UpdatePaymentRequestResponse{
+		Code:           http.StatusOK,
+		PaymentRequest: newPaymentRequest(paymentRequest, usernames, now),
+	}, nil
+}
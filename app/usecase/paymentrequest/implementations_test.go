@@ -0,0 +1,1111 @@
+package usecasepaymentrequest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainpaymentrequest "github.com/kevinsudut/wallet-system/app/domain/paymentrequest"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	createdAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	expiresAt = time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	paymentRequest = entity.PaymentRequest{
+		Id:          "prid",
+		RequesterId: "id",
+		PayerId:     "payerid",
+		Amount:      10,
+		Status:      int(enum.PAYMENT_REQUEST_STATUS_PENDING),
+		ExpiresAt:   expiresAt,
+		CreatedAt:   createdAt,
+	}
+
+	requester = entity.User{
+		Id:       "id",
+		Username: "username",
+	}
+	payer = entity.User{
+		Id:       "payerid",
+		Username: "payerusername",
+	}
+
+	transferRequest = usecasebalance.TransferBalanceRequest{
+		UserId:         "payerid",
+		IdempotencyKey: "payment_request:prid",
+		ToUsername:     "username",
+		Amount:         10,
+	}
+)
+
+func withStatus(status enum.PaymentRequestStatus) entity.PaymentRequest {
+	pr := paymentRequest
+	pr.Status = int(status)
+	return pr
+}
+
+func newResponse(status string) PaymentRequest {
+	return PaymentRequest{
+		Id:                "prid",
+		RequesterUsername: "username",
+		PayerUsername:     "payerusername",
+		Amount:            10,
+		Status:            status,
+		ExpiresAt:         expiresAt,
+		CreatedAt:         createdAt,
+	}
+}
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_usecase_CreatePaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainPaymentRequest := domainpaymentrequest.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		paymentRequest domainpaymentrequest.DomainItf
+		auth           domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req CreatePaymentRequestRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CreatePaymentRequestResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePaymentRequestRequest{
+					UserId:        "id",
+					PayerUsername: "payerusername",
+					Amount:        10,
+				},
+			},
+			wantResp: CreatePaymentRequestResponse{
+				Code: http.StatusCreated,
+				PaymentRequest: PaymentRequest{
+					RequesterUsername: "username",
+					PayerUsername:     "payerusername",
+					Amount:            10,
+					Status:            "pending",
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "payerusername").Return(payer, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainPaymentRequest.EXPECT().InsertPaymentRequest(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, pr entity.PaymentRequest) error {
+						if pr.Id == "" || pr.RequesterId != "id" || pr.PayerId != "payerid" || pr.Status != int(enum.PAYMENT_REQUEST_STATUS_PENDING) || pr.ExpiresAt.Sub(pr.CreatedAt) != time.Hour {
+							return fmt.Errorf("unexpected payment request %+v", pr)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePaymentRequestRequest{
+					UserId:        "id",
+					PayerUsername: "payerusername",
+				},
+			},
+			wantResp: CreatePaymentRequestResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error payer not found",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePaymentRequestRequest{
+					UserId:        "id",
+					PayerUsername: "payerusername",
+					Amount:        10,
+				},
+			},
+			wantResp: CreatePaymentRequestResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "payerusername").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error payer is the requester",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePaymentRequestRequest{
+					UserId:        "id",
+					PayerUsername: "username",
+					Amount:        10,
+				},
+			},
+			wantResp: CreatePaymentRequestResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "username").Return(requester, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserByUsername",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePaymentRequestRequest{
+					UserId:        "id",
+					PayerUsername: "payerusername",
+					Amount:        10,
+				},
+			},
+			wantResp: CreatePaymentRequestResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "payerusername").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePaymentRequestRequest{
+					UserId:        "id",
+					PayerUsername: "payerusername",
+					Amount:        10,
+				},
+			},
+			wantResp: CreatePaymentRequestResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "payerusername").Return(payer, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.InsertPaymentRequest",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreatePaymentRequestRequest{
+					UserId:        "id",
+					PayerUsername: "payerusername",
+					Amount:        10,
+				},
+			},
+			wantResp: CreatePaymentRequestResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "payerusername").Return(payer, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainPaymentRequest.EXPECT().InsertPaymentRequest(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				paymentRequest:    tt.fields.paymentRequest,
+				auth:              tt.fields.auth,
+				paymentRequestTTL: time.Hour,
+			}
+			tt.mock()
+			gotResp, err := u.CreatePaymentRequest(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CreatePaymentRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// A new payment request gets a random id and the current time, so only their presence is checked.
+			if !tt.wantErr && (gotResp.Id == "" || gotResp.ExpiresAt.Sub(gotResp.CreatedAt) != time.Hour) {
+				t.Errorf("usecase.CreatePaymentRequest() = %v, want an id and an expiry an hour after its creation", gotResp)
+			}
+			gotResp.Id, gotResp.ExpiresAt, gotResp.CreatedAt = "", time.Time{}, time.Time{}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CreatePaymentRequest() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListIncomingPaymentRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainPaymentRequest := domainpaymentrequest.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	expiredPaymentRequest := paymentRequest
+	expiredPaymentRequest.ExpiresAt = createdAt
+	expiredResponse := newResponse("expired")
+	expiredResponse.ExpiresAt = createdAt
+
+	type fields struct {
+		paymentRequest domainpaymentrequest.DomainItf
+		auth           domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListPaymentRequestsRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListPaymentRequestsResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListPaymentRequestsRequest{
+					UserId: "payerid",
+				},
+			},
+			wantResp: ListPaymentRequestsResponse{
+				Code: http.StatusOK,
+				Data: []PaymentRequest{newResponse("pending"), expiredResponse},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestsByPayerId(gomock.Any(), "payerid", maxListPaymentRequestsLimit).Return([]entity.PaymentRequest{paymentRequest, expiredPaymentRequest}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListPaymentRequestsRequest{
+					UserId: "payerid",
+				},
+			},
+			wantResp: ListPaymentRequestsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestsByPayerId(gomock.Any(), "payerid", maxListPaymentRequestsLimit).Return([]entity.PaymentRequest{paymentRequest}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.GetPaymentRequestsByPayerId",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListPaymentRequestsRequest{
+					UserId: "payerid",
+				},
+			},
+			wantResp: ListPaymentRequestsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestsByPayerId(gomock.Any(), "payerid", maxListPaymentRequestsLimit).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				paymentRequest: tt.fields.paymentRequest,
+				auth:           tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.ListIncomingPaymentRequests(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListIncomingPaymentRequests() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListIncomingPaymentRequests() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListOutgoingPaymentRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainPaymentRequest := domainpaymentrequest.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		paymentRequest domainpaymentrequest.DomainItf
+		auth           domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListPaymentRequestsRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListPaymentRequestsResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListPaymentRequestsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListPaymentRequestsResponse{
+				Code: http.StatusOK,
+				Data: []PaymentRequest{newResponse("accepted")},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestsByRequesterId(gomock.Any(), "id", maxListPaymentRequestsLimit).Return([]entity.PaymentRequest{withStatus(enum.PAYMENT_REQUEST_STATUS_ACCEPTED)}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.GetPaymentRequestsByRequesterId",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListPaymentRequestsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListPaymentRequestsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestsByRequesterId(gomock.Any(), "id", maxListPaymentRequestsLimit).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				paymentRequest: tt.fields.paymentRequest,
+				auth:           tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.ListOutgoingPaymentRequests(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListOutgoingPaymentRequests() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListOutgoingPaymentRequests() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_AcceptPaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainPaymentRequest := domainpaymentrequest.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	req := UpdatePaymentRequestRequest{
+		UserId:           "payerid",
+		PaymentRequestId: "prid",
+	}
+
+	type fields struct {
+		paymentRequest domainpaymentrequest.DomainItf
+		auth           domainauth.DomainItf
+		balance        usecasebalance.UsecaseItf
+	}
+	type args struct {
+		ctx context.Context
+		req UpdatePaymentRequestRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp UpdatePaymentRequestResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code:           http.StatusOK,
+				PaymentRequest: newResponse("accepted"),
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+					mockDomainPaymentRequest.EXPECT().ClaimPaymentRequest(gomock.Any(), "prid", gomock.Any()).Return(nil),
+					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), transferRequest).Return(usecasebalance.TransferBalanceResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+					mockDomainPaymentRequest.EXPECT().AcceptPaymentRequest(gomock.Any(), "prid").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success retry of an interrupted acceptance",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code:           http.StatusOK,
+				PaymentRequest: newResponse("accepted"),
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(withStatus(enum.PAYMENT_REQUEST_STATUS_ACCEPTING), nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+					mockDomainPaymentRequest.EXPECT().ClaimPaymentRequest(gomock.Any(), "prid", gomock.Any()).Return(nil),
+					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), transferRequest).Return(usecasebalance.TransferBalanceResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+					mockDomainPaymentRequest.EXPECT().AcceptPaymentRequest(gomock.Any(), "prid").Return(domainpaymentrequest.ErrPaymentRequestNotPending),
+				)
+			},
+		},
+		{
+			name: "success already accepted",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code:           http.StatusOK,
+				PaymentRequest: newResponse("accepted"),
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(withStatus(enum.PAYMENT_REQUEST_STATUS_ACCEPTED), nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+				)
+			},
+		},
+		{
+			name: "error payment request of another payer",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdatePaymentRequestRequest{
+					UserId:           "id",
+					PaymentRequestId: "prid",
+				},
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+				)
+			},
+		},
+		{
+			name: "error declined",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(withStatus(enum.PAYMENT_REQUEST_STATUS_DECLINED), nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+				)
+			},
+		},
+		{
+			name: "error claimed by a cancellation",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+					mockDomainPaymentRequest.EXPECT().ClaimPaymentRequest(gomock.Any(), "prid", gomock.Any()).Return(domainpaymentrequest.ErrPaymentRequestNotPending),
+				)
+			},
+		},
+		{
+			name: "error insufficient balance releases the claim",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+					mockDomainPaymentRequest.EXPECT().ClaimPaymentRequest(gomock.Any(), "prid", gomock.Any()).Return(nil),
+					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), transferRequest).Return(usecasebalance.TransferBalanceResponse{
+						Code: http.StatusBadRequest,
+					}, apperror.New(http.StatusBadRequest, "insufficient_balance", "balance is not sufficient for this transfer")),
+					mockDomainPaymentRequest.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), "prid", enum.PAYMENT_REQUEST_STATUS_ACCEPTING, enum.PAYMENT_REQUEST_STATUS_PENDING).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error concurrent acceptance keeps the claim",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+					mockDomainPaymentRequest.EXPECT().ClaimPaymentRequest(gomock.Any(), "prid", gomock.Any()).Return(nil),
+					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), transferRequest).Return(usecasebalance.TransferBalanceResponse{
+						Code: http.StatusConflict,
+					}, apperror.New(http.StatusConflict, "idempotency_key_in_progress", "a request with the same idempotency key is still being processed")),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.AcceptPaymentRequest",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+					mockDomainPaymentRequest.EXPECT().ClaimPaymentRequest(gomock.Any(), "prid", gomock.Any()).Return(nil),
+					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), transferRequest).Return(usecasebalance.TransferBalanceResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+					mockDomainPaymentRequest.EXPECT().AcceptPaymentRequest(gomock.Any(), "prid").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.GetPaymentRequestById",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+				balance:        mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(entity.PaymentRequest{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				paymentRequest: tt.fields.paymentRequest,
+				auth:           tt.fields.auth,
+				balance:        tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.AcceptPaymentRequest(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.AcceptPaymentRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.AcceptPaymentRequest() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_DeclinePaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainPaymentRequest := domainpaymentrequest.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	req := UpdatePaymentRequestRequest{
+		UserId:           "payerid",
+		PaymentRequestId: "prid",
+	}
+
+	type fields struct {
+		paymentRequest domainpaymentrequest.DomainItf
+		auth           domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req UpdatePaymentRequestRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp UpdatePaymentRequestResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code:           http.StatusOK,
+				PaymentRequest: newResponse("declined"),
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainPaymentRequest.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), "prid", enum.PAYMENT_REQUEST_STATUS_PENDING, enum.PAYMENT_REQUEST_STATUS_DECLINED).Return(nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+				)
+			},
+		},
+		{
+			name: "error payment request not found",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(entity.PaymentRequest{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error accepting",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(withStatus(enum.PAYMENT_REQUEST_STATUS_ACCEPTING), nil),
+				)
+			},
+		},
+		{
+			name: "error claimed by an acceptance",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainPaymentRequest.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), "prid", enum.PAYMENT_REQUEST_STATUS_PENDING, enum.PAYMENT_REQUEST_STATUS_DECLINED).Return(domainpaymentrequest.ErrPaymentRequestNotPending),
+				)
+			},
+		},
+		{
+			name: "error paymentRequest.UpdatePaymentRequestStatus",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: req,
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainPaymentRequest.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), "prid", enum.PAYMENT_REQUEST_STATUS_PENDING, enum.PAYMENT_REQUEST_STATUS_DECLINED).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				paymentRequest: tt.fields.paymentRequest,
+				auth:           tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.DeclinePaymentRequest(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.DeclinePaymentRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.DeclinePaymentRequest() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_CancelPaymentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainPaymentRequest := domainpaymentrequest.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	expiredPaymentRequest := paymentRequest
+	expiredPaymentRequest.ExpiresAt = createdAt
+
+	type fields struct {
+		paymentRequest domainpaymentrequest.DomainItf
+		auth           domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req UpdatePaymentRequestRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp UpdatePaymentRequestResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdatePaymentRequestRequest{
+					UserId:           "id",
+					PaymentRequestId: "prid",
+				},
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code:           http.StatusOK,
+				PaymentRequest: newResponse("canceled"),
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainPaymentRequest.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), "prid", enum.PAYMENT_REQUEST_STATUS_PENDING, enum.PAYMENT_REQUEST_STATUS_CANCELED).Return(nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(requester, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "payerid").Return(payer, nil),
+				)
+			},
+		},
+		{
+			name: "error payer cannot cancel",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdatePaymentRequestRequest{
+					UserId:           "payerid",
+					PaymentRequestId: "prid",
+				},
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+				)
+			},
+		},
+		{
+			name: "error expired",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdatePaymentRequestRequest{
+					UserId:           "id",
+					PaymentRequestId: "prid",
+				},
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(expiredPaymentRequest, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				paymentRequest: mockDomainPaymentRequest,
+				auth:           mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: UpdatePaymentRequestRequest{
+					UserId:           "id",
+					PaymentRequestId: "prid",
+				},
+			},
+			wantResp: UpdatePaymentRequestResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainPaymentRequest.EXPECT().GetPaymentRequestById(gomock.Any(), "prid").Return(paymentRequest, nil),
+					mockDomainPaymentRequest.EXPECT().UpdatePaymentRequestStatus(gomock.Any(), "prid", enum.PAYMENT_REQUEST_STATUS_PENDING, enum.PAYMENT_REQUEST_STATUS_CANCELED).Return(nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				paymentRequest: tt.fields.paymentRequest,
+				auth:           tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.CancelPaymentRequest(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CancelPaymentRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CancelPaymentRequest() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
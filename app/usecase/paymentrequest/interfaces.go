@@ -0,0 +1,12 @@
+package usecasepaymentrequest
+
+import "context"
+
+type UsecaseItf interface {
+	CreatePaymentRequest(ctx context.Context, req CreatePaymentRequestRequest) (resp CreatePaymentRequestResponse, err error)
+	ListIncomingPaymentRequests(ctx context.Context, req ListPaymentRequestsRequest) (resp ListPaymentRequestsResponse, err error)
+	ListOutgoingPaymentRequests(ctx context.Context, req ListPaymentRequestsRequest) (resp ListPaymentRequestsResponse, err error)
+	AcceptPaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (resp UpdatePaymentRequestResponse, err error)
+	DeclinePaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (resp UpdatePaymentRequestResponse, err error)
+	CancelPaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (resp UpdatePaymentRequestResponse, err error)
+}
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/usecase/paymentrequest/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/usecase/paymentrequest/interfaces.go -destination=app/usecase/paymentrequest/mock.go -package=usecasepaymentrequest
+//
+
+// Package usecasepaymentrequest is a generated GoMock package.
+package usecasepaymentrequest
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsecaseItf is a mock of UsecaseItf interface.
+type MockUsecaseItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseItfMockRecorder
+}
+
+// MockUsecaseItfMockRecorder is the mock recorder for MockUsecaseItf.
+type MockUsecaseItfMockRecorder struct {
+	mock *MockUsecaseItf
+}
+
+// NewMockUsecaseItf creates a new mock instance.
+func NewMockUsecaseItf(ctrl *gomock.Controller) *MockUsecaseItf {
+	mock := &MockUsecaseItf{ctrl: ctrl}
+	mock.recorder = &MockUsecaseItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
+	return m.recorder
+}
+
+// AcceptPaymentRequest mocks base method.
+func (m *MockUsecaseItf) AcceptPaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (UpdatePaymentRequestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptPaymentRequest", ctx, req)
+	ret0, _ := ret[0].(UpdatePaymentRequestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptPaymentRequest indicates an expected call of AcceptPaymentRequest.
+func (mr *MockUsecaseItfMockRecorder) AcceptPaymentRequest(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptPaymentRequest", reflect.TypeOf((*MockUsecaseItf)(nil).AcceptPaymentRequest), ctx, req)
+}
+
+// CancelPaymentRequest mocks base method.
+func (m *MockUsecaseItf) CancelPaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (UpdatePaymentRequestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPaymentRequest", ctx, req)
+	ret0, _ := ret[0].(UpdatePaymentRequestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelPaymentRequest indicates an expected call of CancelPaymentRequest.
+func (mr *MockUsecaseItfMockRecorder) CancelPaymentRequest(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPaymentRequest", reflect.TypeOf((*MockUsecaseItf)(nil).CancelPaymentRequest), ctx, req)
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockUsecaseItf) CreatePaymentRequest(ctx context.Context, req CreatePaymentRequestRequest) (CreatePaymentRequestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", ctx, req)
+	ret0, _ := ret[0].(CreatePaymentRequestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockUsecaseItfMockRecorder) CreatePaymentRequest(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockUsecaseItf)(nil).CreatePaymentRequest), ctx, req)
+}
+
+// DeclinePaymentRequest mocks base method.
+func (m *MockUsecaseItf) DeclinePaymentRequest(ctx context.Context, req UpdatePaymentRequestRequest) (UpdatePaymentRequestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclinePaymentRequest", ctx, req)
+	ret0, _ := ret[0].(UpdatePaymentRequestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclinePaymentRequest indicates an expected call of DeclinePaymentRequest.
+func (mr *MockUsecaseItfMockRecorder) DeclinePaymentRequest(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclinePaymentRequest", reflect.TypeOf((*MockUsecaseItf)(nil).DeclinePaymentRequest), ctx, req)
+}
+
+// ListIncomingPaymentRequests mocks base method.
+func (m *MockUsecaseItf) ListIncomingPaymentRequests(ctx context.Context, req ListPaymentRequestsRequest) (ListPaymentRequestsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomingPaymentRequests", ctx, req)
+	ret0, _ := ret[0].(ListPaymentRequestsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomingPaymentRequests indicates an expected call of ListIncomingPaymentRequests.
+func (mr *MockUsecaseItfMockRecorder) ListIncomingPaymentRequests(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomingPaymentRequests", reflect.TypeOf((*MockUsecaseItf)(nil).ListIncomingPaymentRequests), ctx, req)
+}
+
+// ListOutgoingPaymentRequests mocks base method.
+func (m *MockUsecaseItf) ListOutgoingPaymentRequests(ctx context.Context, req ListPaymentRequestsRequest) (ListPaymentRequestsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoingPaymentRequests", ctx, req)
+	ret0, _ := ret[0].(ListPaymentRequestsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoingPaymentRequests indicates an expected call of ListOutgoingPaymentRequests.
+func (mr *MockUsecaseItfMockRecorder) ListOutgoingPaymentRequests(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoingPaymentRequests", reflect.TypeOf((*MockUsecaseItf)(nil).ListOutgoingPaymentRequests), ctx, req)
+}
@@ -0,0 +1,48 @@
+package usecasepaymentrequest
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+type CreatePaymentRequestRequest struct {
+	UserId        string
+	PayerUsername string      `json:"payer_username"`
+	Amount        money.Money `json:"amount"`
+}
+
+type CreatePaymentRequestResponse struct {
+	Code int `json:"-"`
+	PaymentRequest
+}
+
+type ListPaymentRequestsRequest struct {
+	UserId string
+}
+
+type ListPaymentRequestsResponse struct {
+	Code int              `json:"-"`
+	Data []PaymentRequest `json:"data"`
+}
+
+// UpdatePaymentRequestRequest accepts, declines or cancels PaymentRequestId on behalf of UserId.
+type UpdatePaymentRequestRequest struct {
+	UserId           string
+	PaymentRequestId string
+}
+
+type UpdatePaymentRequestResponse struct {
+	Code int `json:"-"`
+	PaymentRequest
+}
+
+type PaymentRequest struct {
+	Id                string      `json:"id"`
+	RequesterUsername string      `json:"requester_username"`
+	PayerUsername     string      `json:"payer_username"`
+	Amount            money.Money `json:"amount"`
+	Status            string      `json:"status"`
+	ExpiresAt         time.Time   `json:"expires_at"`
+	CreatedAt         time.Time   `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package usecasepaymentrequest
+
+import (
+	"os"
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainpaymentrequest "github.com/kevinsudut/wallet-system/app/domain/paymentrequest"
+	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+)
+
+const (
+	maxListPaymentRequestsLimit = 100
+
+	defaultPaymentRequestTTL = time.Hour * 72
+)
+
+type usecase struct {
+	paymentRequest domainpaymentrequest.DomainItf
+	auth           domainauth.DomainItf
+	balance        usecasebalance.UsecaseItf
+
+	paymentRequestTTL time.Duration
+}
+
+func Init(paymentRequest domainpaymentrequest.DomainItf, auth domainauth.DomainItf, balance usecasebalance.UsecaseItf) UsecaseItf {
+	return &usecase{
+		paymentRequest:    paymentRequest,
+		auth:              auth,
+		balance:           balance,
+		paymentRequestTTL: paymentRequestTTL(),
+	}
+}
+
+// paymentRequestTTL reads PAYMENT_REQUEST_TTL (e.g. "72h"), falling back to the default when unset or invalid.
+func paymentRequestTTL() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("PAYMENT_REQUEST_TTL"))
+	if err != nil || duration <= 0 {
+		return defaultPaymentRequestTTL
+	}
+
+	return duration
+}
@@ -0,0 +1,44 @@
+package usecasepaymentrequest
+
+import (
+	"reflect"
+	"testing"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainpaymentrequest "github.com/kevinsudut/wallet-system/app/domain/paymentrequest"
+	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+)
+
+func TestInit(t *testing.T) {
+	type args struct {
+		paymentRequest domainpaymentrequest.DomainItf
+		auth           domainauth.DomainItf
+		balance        usecasebalance.UsecaseItf
+	}
+	tests := []struct {
+		name string
+		args args
+		want UsecaseItf
+	}{
+		{
+			args: args{
+				paymentRequest: nil,
+				auth:           nil,
+				balance:        nil,
+			},
+			want: &usecase{
+				paymentRequest:    nil,
+				auth:              nil,
+				balance:           nil,
+				paymentRequestTTL: defaultPaymentRequestTTL,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Init(tt.args.paymentRequest, tt.args.auth, tt.args.balance); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Init() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package usecasescheduledtransfer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	domainscheduledtransfer "github.com/kevinsudut/wallet-system/app/domain/scheduledtransfer"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+var (
+	errInvalidAmount              = apperror.New(http.StatusBadRequest, "invalid_amount", "scheduled transfer amount must be greater than 0")
+	errInvalidExecuteAt           = apperror.New(http.StatusBadRequest, "invalid_schedule", "execute_at must be in the future")
+	errInvalidRecurrence          = apperror.New(http.StatusBadRequest, "invalid_schedule", "recurrence must be one of once, daily, weekly or monthly")
+	errInvalidDayOfMonth          = apperror.New(http.StatusBadRequest, "invalid_schedule", "day_of_month must be between 1 and 31, and is only allowed for a monthly recurrence")
+	errInvalidOnFailure           = apperror.New(http.StatusBadRequest, "invalid_schedule", "on_failure must be one of retry or skip")
+	errRecipientNotFound          = apperror.New(http.StatusNotFound, "recipient_not_found", "recipient does not exist")
+	errScheduledTransferNotFound  = apperror.New(http.StatusNotFound, "scheduled_transfer_not_found", "scheduled transfer does not exist")
+	errScheduledTransferNotActive = apperror.New(http.StatusConflict, "scheduled_transfer_not_active", "scheduled transfer was already completed, canceled or has failed")
+)
+
+// recurrences and failurePolicies map the request values to their enum, an empty value picks the default.
+var (
+	recurrences = map[string]enum.Recurrence{
+		"":        enum.RECURRENCE_ONCE,
+		"once":    enum.RECURRENCE_ONCE,
+		"daily":   enum.RECURRENCE_DAILY,
+		"weekly":  enum.RECURRENCE_WEEKLY,
+		"monthly": enum.RECURRENCE_MONTHLY,
+	}
+	failurePolicies = map[string]enum.FailurePolicy{
+		"":      enum.FAILURE_POLICY_RETRY,
+		"retry": enum.FAILURE_POLICY_RETRY,
+		"skip":  enum.FAILURE_POLICY_SKIP,
+	}
+)
+
+func newScheduledTransfer(scheduledTransfer entity.ScheduledTransfer, toUsername string) ScheduledTransfer {
+	resp := ScheduledTransfer{
+		Id:         scheduledTransfer.Id,
+		ToUsername: toUsername,
+		Amount:     scheduledTransfer.Amount,
+		Recurrence: enum.Recurrence(scheduledTransfer.Recurrence).String(),
+		DayOfMonth: scheduledTransfer.DayOfMonth,
+		OnFailure:  enum.FailurePolicy(scheduledTransfer.FailurePolicy).String(),
+		Status:     enum.ScheduledTransferStatus(scheduledTransfer.Status).String(),
+	}
+
+	if scheduledTransfer.Status == int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE) {
+		nextRunAt := scheduledTransfer.NextRunAt
+		resp.NextRunAt = &nextRunAt
+	}
+
+	return resp
+}
+
+// firstOccurrence is executeAt, or for a monthly transfer on another day the first of that day from executeAt on.
+func firstOccurrence(executeAt time.Time, recurrence enum.Recurrence, dayOfMonth int, location *time.Location) time.Time {
+	if recurrence != enum.RECURRENCE_MONTHLY {
+		return executeAt
+	}
+
+	local := executeAt.In(location)
+	first := entity.DayOfMonth(local.Year(), local.Month(), dayOfMonth, local, location)
+	if first.Before(executeAt) {
+		first = entity.DayOfMonth(local.Year(), local.Month()+1, dayOfMonth, local, location)
+	}
+
+	return first
+}
+
+// CreateScheduledTransfer schedules a transfer of the user at ExecuteAt, repeated on its recurrence.
+func (u usecase) CreateScheduledTransfer(ctx context.Context, req CreateScheduledTransferRequest) (resp CreateScheduledTransferResponse, err error) {
+	if req.Amount <= 0 {
+		return CreateScheduledTransferResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidAmount
+	}
+
+	if !req.ExecuteAt.After(time.Now()) {
+		return CreateScheduledTransferResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidExecuteAt
+	}
+
+	recurrence, ok := recurrences[req.Recurrence]
+	if !ok {
+		return CreateScheduledTransferResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidRecurrence
+	}
+
+	dayOfMonth := req.DayOfMonth
+	if dayOfMonth != 0 && (recurrence != enum.RECURRENCE_MONTHLY || dayOfMonth < 1 || dayOfMonth > 31) {
+		return CreateScheduledTransferResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidDayOfMonth
+	}
+	if recurrence == enum.RECURRENCE_MONTHLY && dayOfMonth == 0 {
+		dayOfMonth = req.ExecuteAt.In(u.location).Day()
+	}
+
+	failurePolicy, ok := failurePolicies[req.OnFailure]
+	if !ok {
+		return CreateScheduledTransferResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidOnFailure
+	}
+
+	toUser, err := u.auth.GetUserByUsername(ctx, req.ToUsername)
+	if err == sql.ErrNoRows {
+		return CreateScheduledTransferResponse{
+			Code: http.StatusNotFound,
+		}, errRecipientNotFound
+	}
+	if err != nil {
+		log.Errorln("CreateScheduledTransfer.GetUserByUsername", err)
+		return CreateScheduledTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	scheduledAt := firstOccurrence(req.ExecuteAt, recurrence, dayOfMonth, u.location).UTC()
+	scheduledTransfer := entity.ScheduledTransfer{
+		Id:            uuid.NewString(),
+		UserId:        req.UserId,
+		ToUserId:      toUser.Id,
+		Amount:        req.Amount,
+		Recurrence:    int(recurrence),
+		DayOfMonth:    dayOfMonth,
+		FailurePolicy: int(failurePolicy),
+		Status:        int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE),
+		ScheduledAt:   scheduledAt,
+		NextRunAt:     scheduledAt,
+	}
+
+	err = u.scheduledTransfer.InsertScheduledTransfer(ctx, scheduledTransfer)
+	if err != nil {
+		log.Errorln("CreateScheduledTransfer.InsertScheduledTransfer", err)
+		return CreateScheduledTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return CreateScheduledTransferResponse{
+		Code:              http.StatusCreated,
+		ScheduledTransfer: newScheduledTransfer(scheduledTransfer, toUser.Username),
+	}, nil
+}
+
+func (u usecase) ListScheduledTransfers(ctx context.Context, req ListScheduledTransfersRequest) (resp ListScheduledTransfersResponse, err error) {
+	scheduledTransfers, err := u.scheduledTransfer.GetScheduledTransfersByUserId(ctx, req.UserId, maxListScheduledTransfersLimit)
+	if err != nil {
+		log.Errorln("ListScheduledTransfers.GetScheduledTransfersByUserId", err)
+		return ListScheduledTransfersResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListScheduledTransfersResponse{
+		Code: http.StatusOK,
+		Data: make([]ScheduledTransfer, len(scheduledTransfers)),
+	}
+
+	usernames := make(map[string]string)
+	for idx, scheduledTransfer := range scheduledTransfers {
+		username, ok := usernames[scheduledTransfer.ToUserId]
+		if !ok {
+			user, err := u.auth.GetUserById(ctx, scheduledTransfer.ToUserId)
+			if err != nil {
+				log.Errorln("ListScheduledTransfers.GetUserById", err)
+				return ListScheduledTransfersResponse{
+					Code: http.StatusBadGateway,
+				}, apperror.ErrDependency.Wrap(err)
+			}
+			username = user.Username
+			usernames[scheduledTransfer.ToUserId] = username
+		}
+
+		resp.Data[idx] = newScheduledTransfer(scheduledTransfer, username)
+	}
+
+	return resp, nil
+}
+
+// getScheduledTransferForUser reads a scheduled transfer of userId, anyone else is told it does not exist.
+func (u usecase) getScheduledTransferForUser(ctx context.Context, id string, userId string) (resp entity.ScheduledTransfer, code int, err error) {
+	scheduledTransfer, err := u.scheduledTransfer.GetScheduledTransferById(ctx, id)
+	if err == sql.ErrNoRows || (err == nil && scheduledTransfer.UserId != userId) {
+		return resp, http.StatusNotFound, errScheduledTransferNotFound
+	}
+	if err != nil {
+		log.Errorln("getScheduledTransferForUser.GetScheduledTransferById", err)
+		return resp, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+
+	return scheduledTransfer, http.StatusOK, nil
+}
+
+func (u usecase) ListScheduledTransferRuns(ctx context.Context, req ListScheduledTransferRunsRequest) (resp ListScheduledTransferRunsResponse, err error) {
+	_, code, err := u.getScheduledTransferForUser(ctx, req.ScheduledTransferId, req.UserId)
+	if err != nil {
+		return ListScheduledTransferRunsResponse{
+			Code: code,
+		}, err
+	}
+
+	runs, err := u.scheduledTransfer.GetScheduledTransferRunsByScheduledTransferId(ctx, req.ScheduledTransferId, maxListScheduledTransferRunsLimit)
+	if err != nil {
+		log.Errorln("ListScheduledTransferRuns.GetScheduledTransferRunsByScheduledTransferId", err)
+		return ListScheduledTransferRunsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListScheduledTransferRunsResponse{
+		Code: http.StatusOK,
+		Data: make([]ScheduledTransferRun, len(runs)),
+	}
+
+	for idx, run := range runs {
+		resp.Data[idx] = ScheduledTransferRun{
+			Id:          run.Id,
+			ScheduledAt: run.ScheduledAt,
+			Attempt:     run.Attempt,
+			Status:      enum.ScheduledTransferRunStatus(run.Status).String(),
+			ErrorCode:   run.ErrorCode,
+			CreatedAt:   run.CreatedAt,
+		}
+	}
+
+	return resp, nil
+}
+
+// CancelScheduledTransfer stops an active scheduled transfer from running again.
+func (u usecase) CancelScheduledTransfer(ctx context.Context, req CancelScheduledTransferRequest) (resp CancelScheduledTransferResponse, err error) {
+	scheduledTransfer, code, err := u.getScheduledTransferForUser(ctx, req.ScheduledTransferId, req.UserId)
+	if err != nil {
+		return CancelScheduledTransferResponse{
+			Code: code,
+		}, err
+	}
+
+	err = u.scheduledTransfer.CancelScheduledTransfer(ctx, scheduledTransfer.Id, req.UserId)
+	if errors.Is(err, domainscheduledtransfer.ErrScheduledTransferNotActive) {
+		return CancelScheduledTransferResponse{
+			Code: http.StatusConflict,
+		}, errScheduledTransferNotActive
+	}
+	if err != nil {
+		log.Errorln("CancelScheduledTransfer.CancelScheduledTransfer", err)
+		return CancelScheduledTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	toUser, err := u.auth.GetUserById(ctx, scheduledTransfer.ToUserId)
+	if err != nil {
+		log.Errorln("CancelScheduledTransfer.GetUserById", err)
+		return CancelScheduledTransferResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	scheduledTransfer.Status = int(enum.SCHEDULED_TRANSFER_STATUS_CANCELED)
+
+	return CancelScheduledTransferResponse{
+		Code:              http.StatusOK,
+		ScheduledTransfer: newScheduledTransfer(scheduledTransfer, toUser.Username),
+	}, nil
+}
@@ -0,0 +1,1150 @@
+package usecasescheduledtransfer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainscheduledtransfer "github.com/kevinsudut/wallet-system/app/domain/scheduledtransfer"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	executeAt = time.Date(2099, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	scheduledTransfer = entity.ScheduledTransfer{
+		Id:            "sid",
+		UserId:        "id",
+		ToUserId:      "toid",
+		Amount:        10,
+		Recurrence:    int(enum.RECURRENCE_MONTHLY),
+		DayOfMonth:    15,
+		FailurePolicy: int(enum.FAILURE_POLICY_RETRY),
+		Status:        int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE),
+		ScheduledAt:   executeAt,
+		NextRunAt:     executeAt,
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_usecase_CreateScheduledTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainScheduledTransfer := domainscheduledtransfer.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	endOfMonth := time.Date(2099, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		scheduledTransfer domainscheduledtransfer.DomainItf
+		auth              domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req CreateScheduledTransferRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CreateScheduledTransferResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success once",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  executeAt,
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusCreated,
+				ScheduledTransfer: ScheduledTransfer{
+					ToUsername: "tousername",
+					Amount:     10,
+					Recurrence: "once",
+					OnFailure:  "retry",
+					Status:     "active",
+					NextRunAt:  &executeAt,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainScheduledTransfer.EXPECT().InsertScheduledTransfer(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, st entity.ScheduledTransfer) error {
+						if st.Id == "" || st.UserId != "id" || st.ToUserId != "toid" || st.Recurrence != int(enum.RECURRENCE_ONCE) || !st.ScheduledAt.Equal(executeAt) || !st.NextRunAt.Equal(executeAt) {
+							return fmt.Errorf("unexpected scheduled transfer %+v", st)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "success monthly on another day",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  executeAt,
+					Recurrence: "monthly",
+					DayOfMonth: 31,
+					OnFailure:  "skip",
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusCreated,
+				ScheduledTransfer: ScheduledTransfer{
+					ToUsername: "tousername",
+					Amount:     10,
+					Recurrence: "monthly",
+					DayOfMonth: 31,
+					OnFailure:  "skip",
+					Status:     "active",
+					NextRunAt:  &endOfMonth,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainScheduledTransfer.EXPECT().InsertScheduledTransfer(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, st entity.ScheduledTransfer) error {
+						if st.FailurePolicy != int(enum.FAILURE_POLICY_SKIP) || st.DayOfMonth != 31 || !st.ScheduledAt.Equal(endOfMonth) {
+							return fmt.Errorf("unexpected scheduled transfer %+v", st)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "error invalid amount",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					ExecuteAt:  executeAt,
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error execute at in the past",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  time.Date(2020, 1, 15, 9, 0, 0, 0, time.UTC),
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid recurrence",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  executeAt,
+					Recurrence: "yearly",
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error day of month without monthly recurrence",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  executeAt,
+					Recurrence: "weekly",
+					DayOfMonth: 1,
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid on failure",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  executeAt,
+					OnFailure:  "ignore",
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error recipient not found",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  executeAt,
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserByUsername",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  executeAt,
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.InsertScheduledTransfer",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateScheduledTransferRequest{
+					UserId:     "id",
+					ToUsername: "tousername",
+					Amount:     10,
+					ExecuteAt:  executeAt,
+				},
+			},
+			wantResp: CreateScheduledTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "tousername").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockDomainScheduledTransfer.EXPECT().InsertScheduledTransfer(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				scheduledTransfer: tt.fields.scheduledTransfer,
+				auth:              tt.fields.auth,
+				location:          time.UTC,
+			}
+			tt.mock()
+			gotResp, err := u.CreateScheduledTransfer(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CreateScheduledTransfer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// A new scheduled transfer gets a random id, so only its presence is checked.
+			if !tt.wantErr && gotResp.Id == "" {
+				t.Errorf("usecase.CreateScheduledTransfer() id is empty")
+			}
+			gotResp.Id = ""
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CreateScheduledTransfer() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListScheduledTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainScheduledTransfer := domainscheduledtransfer.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	canceledScheduledTransfer := scheduledTransfer
+	canceledScheduledTransfer.Id = "sid2"
+	canceledScheduledTransfer.Status = int(enum.SCHEDULED_TRANSFER_STATUS_CANCELED)
+
+	type fields struct {
+		scheduledTransfer domainscheduledtransfer.DomainItf
+		auth              domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListScheduledTransfersRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListScheduledTransfersResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListScheduledTransfersRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListScheduledTransfersResponse{
+				Code: http.StatusOK,
+				Data: []ScheduledTransfer{
+					{
+						Id:         "sid",
+						ToUsername: "tousername",
+						Amount:     10,
+						Recurrence: "monthly",
+						DayOfMonth: 15,
+						OnFailure:  "retry",
+						Status:     "active",
+						NextRunAt:  &executeAt,
+					},
+					{
+						Id:         "sid2",
+						ToUsername: "tousername",
+						Amount:     10,
+						Recurrence: "monthly",
+						DayOfMonth: 15,
+						OnFailure:  "retry",
+						Status:     "canceled",
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransfersByUserId(gomock.Any(), "id", maxListScheduledTransfersLimit).Return([]entity.ScheduledTransfer{scheduledTransfer, canceledScheduledTransfer}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "toid").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListScheduledTransfersRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListScheduledTransfersResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransfersByUserId(gomock.Any(), "id", maxListScheduledTransfersLimit).Return([]entity.ScheduledTransfer{scheduledTransfer}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "toid").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.GetScheduledTransfersByUserId",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListScheduledTransfersRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListScheduledTransfersResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransfersByUserId(gomock.Any(), "id", maxListScheduledTransfersLimit).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				scheduledTransfer: tt.fields.scheduledTransfer,
+				auth:              tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.ListScheduledTransfers(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListScheduledTransfers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListScheduledTransfers() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListScheduledTransferRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainScheduledTransfer := domainscheduledtransfer.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	createdAt := executeAt.Add(time.Second)
+
+	type fields struct {
+		scheduledTransfer domainscheduledtransfer.DomainItf
+		auth              domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListScheduledTransferRunsRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListScheduledTransferRunsResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListScheduledTransferRunsRequest{
+					UserId:              "id",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: ListScheduledTransferRunsResponse{
+				Code: http.StatusOK,
+				Data: []ScheduledTransferRun{
+					{
+						Id:          "rid",
+						ScheduledAt: executeAt,
+						Attempt:     1,
+						Status:      "failed",
+						ErrorCode:   "insufficient_balance",
+						CreatedAt:   createdAt,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(scheduledTransfer, nil),
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferRunsByScheduledTransferId(gomock.Any(), "sid", maxListScheduledTransferRunsLimit).Return([]entity.ScheduledTransferRun{
+						{
+							Id:                  "rid",
+							ScheduledTransferId: "sid",
+							ScheduledAt:         executeAt,
+							Attempt:             1,
+							Status:              int(enum.SCHEDULED_TRANSFER_RUN_STATUS_FAILED),
+							ErrorCode:           "insufficient_balance",
+							CreatedAt:           createdAt,
+						},
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error scheduled transfer of another user",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListScheduledTransferRunsRequest{
+					UserId:              "toid",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: ListScheduledTransferRunsResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(scheduledTransfer, nil),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.GetScheduledTransferById",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListScheduledTransferRunsRequest{
+					UserId:              "id",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: ListScheduledTransferRunsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(entity.ScheduledTransfer{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.GetScheduledTransferRunsByScheduledTransferId",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListScheduledTransferRunsRequest{
+					UserId:              "id",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: ListScheduledTransferRunsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(scheduledTransfer, nil),
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferRunsByScheduledTransferId(gomock.Any(), "sid", maxListScheduledTransferRunsLimit).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				scheduledTransfer: tt.fields.scheduledTransfer,
+				auth:              tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.ListScheduledTransferRuns(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListScheduledTransferRuns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListScheduledTransferRuns() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_CancelScheduledTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainScheduledTransfer := domainscheduledtransfer.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		scheduledTransfer domainscheduledtransfer.DomainItf
+		auth              domainauth.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req CancelScheduledTransferRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CancelScheduledTransferResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CancelScheduledTransferRequest{
+					UserId:              "id",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: CancelScheduledTransferResponse{
+				Code: http.StatusOK,
+				ScheduledTransfer: ScheduledTransfer{
+					Id:         "sid",
+					ToUsername: "tousername",
+					Amount:     10,
+					Recurrence: "monthly",
+					DayOfMonth: 15,
+					OnFailure:  "retry",
+					Status:     "canceled",
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(scheduledTransfer, nil),
+					mockDomainScheduledTransfer.EXPECT().CancelScheduledTransfer(gomock.Any(), "sid", "id").Return(nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "toid").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error scheduled transfer not found",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CancelScheduledTransferRequest{
+					UserId:              "id",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: CancelScheduledTransferResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(entity.ScheduledTransfer{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error scheduled transfer not active",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CancelScheduledTransferRequest{
+					UserId:              "id",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: CancelScheduledTransferResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(scheduledTransfer, nil),
+					mockDomainScheduledTransfer.EXPECT().CancelScheduledTransfer(gomock.Any(), "sid", "id").Return(domainscheduledtransfer.ErrScheduledTransferNotActive),
+				)
+			},
+		},
+		{
+			name: "error scheduledTransfer.CancelScheduledTransfer",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CancelScheduledTransferRequest{
+					UserId:              "id",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: CancelScheduledTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(scheduledTransfer, nil),
+					mockDomainScheduledTransfer.EXPECT().CancelScheduledTransfer(gomock.Any(), "sid", "id").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CancelScheduledTransferRequest{
+					UserId:              "id",
+					ScheduledTransferId: "sid",
+				},
+			},
+			wantResp: CancelScheduledTransferResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().GetScheduledTransferById(gomock.Any(), "sid").Return(scheduledTransfer, nil),
+					mockDomainScheduledTransfer.EXPECT().CancelScheduledTransfer(gomock.Any(), "sid", "id").Return(nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "toid").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				scheduledTransfer: tt.fields.scheduledTransfer,
+				auth:              tt.fields.auth,
+			}
+			tt.mock()
+			gotResp, err := u.CancelScheduledTransfer(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CancelScheduledTransfer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CancelScheduledTransfer() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_runDueScheduledTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainScheduledTransfer := domainscheduledtransfer.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockUsecaseBalance := usecasebalance.NewMockUsecaseItf(ctrl)
+
+	now := executeAt.Add(time.Second)
+	transferRequest := usecasebalance.TransferBalanceRequest{
+		UserId:         "id",
+		IdempotencyKey: fmt.Sprintf("scheduled_transfer:sid:%d", executeAt.Unix()),
+		ToUsername:     "tousername",
+		Amount:         10,
+	}
+
+	type fields struct {
+		scheduledTransfer domainscheduledtransfer.DomainItf
+		auth              domainauth.DomainItf
+		balance           usecasebalance.UsecaseItf
+	}
+	type args struct {
+		ctx   context.Context
+		clock func() time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success moves to the next occurrence",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+				balance:           mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				clock: func() time.Time {
+					return now
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainscheduledtransfer.ClaimDueScheduledTransfersRequest) ([]entity.ScheduledTransfer, error) {
+						if req.LeaseId == "" || !req.Now.Equal(now) || !req.LeaseExpiresAt.Equal(now.Add(scheduledTransferLease)) || req.Limit != scheduledTransferBatch {
+							return nil, fmt.Errorf("unexpected request %+v", req)
+						}
+						return []entity.ScheduledTransfer{scheduledTransfer}, nil
+					}),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "toid").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), transferRequest).Return(usecasebalance.TransferBalanceResponse{
+						Code: http.StatusNoContent,
+					}, nil),
+					mockDomainScheduledTransfer.EXPECT().FinishScheduledTransferRun(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainscheduledtransfer.FinishScheduledTransferRunRequest) error {
+						next := time.Date(2099, 2, 15, 9, 0, 0, 0, time.UTC)
+						if req.LeaseId == "" || req.Run.Status != int(enum.SCHEDULED_TRANSFER_RUN_STATUS_SUCCEEDED) || req.Run.Attempt != 1 || !req.Run.ScheduledAt.Equal(executeAt) {
+							return fmt.Errorf("unexpected run %+v", req.Run)
+						}
+						if !req.ScheduledTransfer.ScheduledAt.Equal(next) || !req.ScheduledTransfer.NextRunAt.Equal(next) || req.ScheduledTransfer.Status != int(enum.SCHEDULED_TRANSFER_STATUS_ACTIVE) {
+							return fmt.Errorf("unexpected scheduled transfer %+v", req.ScheduledTransfer)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "success records a failed run and keeps the rest of the batch",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+				balance:           mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				clock: func() time.Time {
+					return now
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				recipientRemoved := scheduledTransfer
+				recipientRemoved.Id = "sid2"
+				recipientRemoved.ToUserId = "removedid"
+
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Return([]entity.ScheduledTransfer{scheduledTransfer, recipientRemoved}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "toid").Return(entity.User{
+						Id:       "toid",
+						Username: "tousername",
+					}, nil),
+					mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), transferRequest).Return(usecasebalance.TransferBalanceResponse{
+						Code: http.StatusBadRequest,
+					}, apperror.New(http.StatusBadRequest, "insufficient_balance", "balance is not sufficient for this transfer")),
+					mockDomainScheduledTransfer.EXPECT().FinishScheduledTransferRun(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainscheduledtransfer.FinishScheduledTransferRunRequest) error {
+						if req.Run.Status != int(enum.SCHEDULED_TRANSFER_RUN_STATUS_FAILED) || req.Run.ErrorCode != "insufficient_balance" {
+							return fmt.Errorf("unexpected run %+v", req.Run)
+						}
+						if !req.ScheduledTransfer.NextRunAt.Equal(now.Add(scheduledTransferRetryInterval)) || req.ScheduledTransfer.Attempt != 1 {
+							return fmt.Errorf("unexpected scheduled transfer %+v", req.ScheduledTransfer)
+						}
+						return domainscheduledtransfer.ErrLeaseLost
+					}),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "removedid").Return(entity.User{}, sql.ErrNoRows),
+					mockDomainScheduledTransfer.EXPECT().FinishScheduledTransferRun(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainscheduledtransfer.FinishScheduledTransferRunRequest) error {
+						if req.Run.ScheduledTransferId != "sid2" || req.Run.ErrorCode != "recipient_not_found" {
+							return fmt.Errorf("unexpected run %+v", req.Run)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "success claims batches until one comes back short",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+				balance:           mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				clock: func() func() time.Time {
+					batchNow := now
+					return func() time.Time {
+						batchNow = batchNow.Add(time.Minute)
+						return batchNow
+					}
+				}(),
+			},
+			wantErr: false,
+			mock: func() {
+				batch := make([]entity.ScheduledTransfer, scheduledTransferBatch)
+				for idx := range batch {
+					batch[idx] = scheduledTransfer
+				}
+
+				mockDomainScheduledTransfer.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Return(batch, nil)
+				mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "toid").Return(entity.User{
+					Id:       "toid",
+					Username: "tousername",
+				}, nil).Times(scheduledTransferBatch)
+				mockUsecaseBalance.EXPECT().TransferBalance(gomock.Any(), transferRequest).Return(usecasebalance.TransferBalanceResponse{
+					Code: http.StatusNoContent,
+				}, nil).Times(scheduledTransferBatch)
+				mockDomainScheduledTransfer.EXPECT().FinishScheduledTransferRun(gomock.Any(), gomock.Any()).Return(nil).Times(scheduledTransferBatch)
+				mockDomainScheduledTransfer.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainscheduledtransfer.ClaimDueScheduledTransfersRequest) ([]entity.ScheduledTransfer, error) {
+					// The lease of the second batch starts when it is claimed, not when the first one was.
+					if !req.Now.Equal(now.Add(time.Minute*2)) || !req.LeaseExpiresAt.Equal(now.Add(time.Minute*2).Add(scheduledTransferLease)) {
+						return nil, fmt.Errorf("unexpected request %+v", req)
+					}
+					return nil, nil
+				})
+			},
+		},
+		{
+			name: "error scheduledTransfer.ClaimDueScheduledTransfers",
+			fields: fields{
+				scheduledTransfer: mockDomainScheduledTransfer,
+				auth:              mockDomainAuth,
+				balance:           mockUsecaseBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				clock: func() time.Time {
+					return now
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainScheduledTransfer.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				scheduledTransfer: tt.fields.scheduledTransfer,
+				auth:              tt.fields.auth,
+				balance:           tt.fields.balance,
+				location:          time.UTC,
+			}
+			tt.mock()
+			if err := u.runDueScheduledTransfers(tt.args.ctx, tt.args.clock); (err != nil) != tt.wantErr {
+				t.Errorf("usecase.runDueScheduledTransfers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_usecase_nextScheduledTransfer(t *testing.T) {
+	now := executeAt.Add(time.Second)
+	next := time.Date(2099, 2, 15, 9, 0, 0, 0, time.UTC)
+
+	withFields := func(update func(st *entity.ScheduledTransfer)) entity.ScheduledTransfer {
+		st := scheduledTransfer
+		update(&st)
+		return st
+	}
+
+	type args struct {
+		scheduledTransfer entity.ScheduledTransfer
+		succeeded         bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want entity.ScheduledTransfer
+	}{
+		{
+			name: "succeeded recurring",
+			args: args{
+				scheduledTransfer: withFields(func(st *entity.ScheduledTransfer) {
+					st.Attempt = 2
+					st.NextRunAt = now
+				}),
+				succeeded: true,
+			},
+			want: withFields(func(st *entity.ScheduledTransfer) {
+				st.ScheduledAt = next
+				st.NextRunAt = next
+			}),
+		},
+		{
+			name: "succeeded once",
+			args: args{
+				scheduledTransfer: withFields(func(st *entity.ScheduledTransfer) {
+					st.Recurrence = int(enum.RECURRENCE_ONCE)
+				}),
+				succeeded: true,
+			},
+			want: withFields(func(st *entity.ScheduledTransfer) {
+				st.Recurrence = int(enum.RECURRENCE_ONCE)
+				st.Status = int(enum.SCHEDULED_TRANSFER_STATUS_COMPLETED)
+			}),
+		},
+		{
+			name: "failed retried",
+			args: args{
+				scheduledTransfer: scheduledTransfer,
+				succeeded:         false,
+			},
+			want: withFields(func(st *entity.ScheduledTransfer) {
+				st.Attempt = 1
+				st.NextRunAt = now.Add(scheduledTransferRetryInterval)
+			}),
+		},
+		{
+			name: "failed out of attempts skips the occurrence",
+			args: args{
+				scheduledTransfer: withFields(func(st *entity.ScheduledTransfer) {
+					st.Attempt = maxScheduledTransferAttempts - 1
+				}),
+				succeeded: false,
+			},
+			want: withFields(func(st *entity.ScheduledTransfer) {
+				st.ScheduledAt = next
+				st.NextRunAt = next
+			}),
+		},
+		{
+			name: "failed with skip policy skips the occurrence",
+			args: args{
+				scheduledTransfer: withFields(func(st *entity.ScheduledTransfer) {
+					st.FailurePolicy = int(enum.FAILURE_POLICY_SKIP)
+				}),
+				succeeded: false,
+			},
+			want: withFields(func(st *entity.ScheduledTransfer) {
+				st.FailurePolicy = int(enum.FAILURE_POLICY_SKIP)
+				st.ScheduledAt = next
+				st.NextRunAt = next
+			}),
+		},
+		{
+			name: "failed once with skip policy",
+			args: args{
+				scheduledTransfer: withFields(func(st *entity.ScheduledTransfer) {
+					st.Recurrence = int(enum.RECURRENCE_ONCE)
+					st.FailurePolicy = int(enum.FAILURE_POLICY_SKIP)
+				}),
+				succeeded: false,
+			},
+			want: withFields(func(st *entity.ScheduledTransfer) {
+				st.Recurrence = int(enum.RECURRENCE_ONCE)
+				st.FailurePolicy = int(enum.FAILURE_POLICY_SKIP)
+				st.Status = int(enum.SCHEDULED_TRANSFER_STATUS_FAILED)
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				location: time.UTC,
+			}
+			if got := u.nextScheduledTransfer(tt.args.scheduledTransfer, tt.args.succeeded, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("usecase.nextScheduledTransfer() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
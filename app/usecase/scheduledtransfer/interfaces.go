@@ -0,0 +1,12 @@
+package usecasescheduledtransfer
+
+import "context"
+
+type UsecaseItf interface {
+	CreateScheduledTransfer(ctx context.Context, req CreateScheduledTransferRequest) (resp CreateScheduledTransferResponse, err error)
+	ListScheduledTransfers(ctx context.Context, req ListScheduledTransfersRequest) (resp ListScheduledTransfersResponse, err error)
+	ListScheduledTransferRuns(ctx context.Context, req ListScheduledTransferRunsRequest) (resp ListScheduledTransferRunsResponse, err error)
+	CancelScheduledTransfer(ctx context.Context, req CancelScheduledTransferRequest) (resp CancelScheduledTransferResponse, err error)
+
+	RunScheduledTransfers(ctx context.Context)
+}
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/usecase/scheduledtransfer/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/usecase/scheduledtransfer/interfaces.go -destination=app/usecase/scheduledtransfer/mock.go -package=usecasescheduledtransfer
+//
+
+// Package usecasescheduledtransfer is a generated GoMock package.
+package usecasescheduledtransfer
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsecaseItf is a mock of UsecaseItf interface.
+type MockUsecaseItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseItfMockRecorder
+}
+
+// MockUsecaseItfMockRecorder is the mock recorder for MockUsecaseItf.
+type MockUsecaseItfMockRecorder struct {
+	mock *MockUsecaseItf
+}
+
+// NewMockUsecaseItf creates a new mock instance.
+func NewMockUsecaseItf(ctrl *gomock.Controller) *MockUsecaseItf {
+	mock := &MockUsecaseItf{ctrl: ctrl}
+	mock.recorder = &MockUsecaseItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
+	return m.recorder
+}
+
+// CancelScheduledTransfer mocks base method.
+func (m *MockUsecaseItf) CancelScheduledTransfer(ctx context.Context, req CancelScheduledTransferRequest) (CancelScheduledTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledTransfer", ctx, req)
+	ret0, _ := ret[0].(CancelScheduledTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledTransfer indicates an expected call of CancelScheduledTransfer.
+func (mr *MockUsecaseItfMockRecorder) CancelScheduledTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledTransfer", reflect.TypeOf((*MockUsecaseItf)(nil).CancelScheduledTransfer), ctx, req)
+}
+
+// CreateScheduledTransfer mocks base method.
+func (m *MockUsecaseItf) CreateScheduledTransfer(ctx context.Context, req CreateScheduledTransferRequest) (CreateScheduledTransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransfer", ctx, req)
+	ret0, _ := ret[0].(CreateScheduledTransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransfer indicates an expected call of CreateScheduledTransfer.
+func (mr *MockUsecaseItfMockRecorder) CreateScheduledTransfer(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransfer", reflect.TypeOf((*MockUsecaseItf)(nil).CreateScheduledTransfer), ctx, req)
+}
+
+// ListScheduledTransferRuns mocks base method.
+func (m *MockUsecaseItf) ListScheduledTransferRuns(ctx context.Context, req ListScheduledTransferRunsRequest) (ListScheduledTransferRunsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransferRuns", ctx, req)
+	ret0, _ := ret[0].(ListScheduledTransferRunsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransferRuns indicates an expected call of ListScheduledTransferRuns.
+func (mr *MockUsecaseItfMockRecorder) ListScheduledTransferRuns(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransferRuns", reflect.TypeOf((*MockUsecaseItf)(nil).ListScheduledTransferRuns), ctx, req)
+}
+
+// ListScheduledTransfers mocks base method.
+func (m *MockUsecaseItf) ListScheduledTransfers(ctx context.Context, req ListScheduledTransfersRequest) (ListScheduledTransfersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransfers", ctx, req)
+	ret0, _ := ret[0].(ListScheduledTransfersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransfers indicates an expected call of ListScheduledTransfers.
+func (mr *MockUsecaseItfMockRecorder) ListScheduledTransfers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransfers", reflect.TypeOf((*MockUsecaseItf)(nil).ListScheduledTransfers), ctx, req)
+}
+
+// RunScheduledTransfers mocks base method.
+func (m *MockUsecaseItf) RunScheduledTransfers(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunScheduledTransfers", ctx)
+}
+
+// RunScheduledTransfers indicates an expected call of RunScheduledTransfers.
+func (mr *MockUsecaseItfMockRecorder) RunScheduledTransfers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunScheduledTransfers", reflect.TypeOf((*MockUsecaseItf)(nil).RunScheduledTransfers), ctx)
+}
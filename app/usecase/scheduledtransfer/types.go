@@ -0,0 +1,72 @@
+package usecasescheduledtransfer
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+type CreateScheduledTransferRequest struct {
+	UserId     string
+	ToUsername string      `json:"to_username"`
+	Amount     money.Money `json:"amount"`
+	ExecuteAt  time.Time   `json:"execute_at"`
+	Recurrence string      `json:"recurrence"`
+	DayOfMonth int         `json:"day_of_month"`
+	OnFailure  string      `json:"on_failure"`
+}
+
+type CreateScheduledTransferResponse struct {
+	Code int `json:"-"`
+	ScheduledTransfer
+}
+
+type ListScheduledTransfersRequest struct {
+	UserId string
+}
+
+type ListScheduledTransfersResponse struct {
+	Code int                 `json:"-"`
+	Data []ScheduledTransfer `json:"data"`
+}
+
+type ListScheduledTransferRunsRequest struct {
+	UserId              string
+	ScheduledTransferId string
+}
+
+type ListScheduledTransferRunsResponse struct {
+	Code int                    `json:"-"`
+	Data []ScheduledTransferRun `json:"data"`
+}
+
+type CancelScheduledTransferRequest struct {
+	UserId              string
+	ScheduledTransferId string
+}
+
+type CancelScheduledTransferResponse struct {
+	Code int `json:"-"`
+	ScheduledTransfer
+}
+
+// ScheduledTransfer is a scheduled transfer of the user. NextRunAt is only set while it is active.
+type ScheduledTransfer struct {
+	Id         string      `json:"id"`
+	ToUsername string      `json:"to_username"`
+	Amount     money.Money `json:"amount"`
+	Recurrence string      `json:"recurrence"`
+	DayOfMonth int         `json:"day_of_month,omitempty"`
+	OnFailure  string      `json:"on_failure"`
+	Status     string      `json:"status"`
+	NextRunAt  *time.Time  `json:"next_run_at,omitempty"`
+}
+
+type ScheduledTransferRun struct {
+	Id          string    `json:"id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Attempt     int       `json:"attempt"`
+	Status      string    `json:"status"`
+	ErrorCode   string    `json:"error_code,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
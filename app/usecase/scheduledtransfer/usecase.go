@@ -0,0 +1,40 @@
+package usecasescheduledtransfer
+
+import (
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainscheduledtransfer "github.com/kevinsudut/wallet-system/app/domain/scheduledtransfer"
+	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+	"github.com/kevinsudut/wallet-system/pkg/helper/timezone"
+)
+
+const (
+	maxListScheduledTransfersLimit    = 100
+	maxListScheduledTransferRunsLimit = 100
+
+	// A failed occurrence is run up to maxScheduledTransferAttempts times when the failure policy is retry.
+	maxScheduledTransferAttempts   = 4
+	scheduledTransferRetryInterval = time.Hour
+
+	scheduledTransferPollInterval = 10 * time.Second
+	scheduledTransferLease        = 2 * time.Minute
+	scheduledTransferBatch        = 10
+	scheduledTransferRunTimeout   = 5 * time.Second
+)
+
+type usecase struct {
+	scheduledTransfer domainscheduledtransfer.DomainItf
+	auth              domainauth.DomainItf
+	balance           usecasebalance.UsecaseItf
+	location          *time.Location
+}
+
+func Init(scheduledTransfer domainscheduledtransfer.DomainItf, auth domainauth.DomainItf, balance usecasebalance.UsecaseItf) UsecaseItf {
+	return &usecase{
+		scheduledTransfer: scheduledTransfer,
+		auth:              auth,
+		balance:           balance,
+		location:          timezone.Business(),
+	}
+}
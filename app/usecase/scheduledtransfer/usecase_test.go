@@ -0,0 +1,45 @@
+package usecasescheduledtransfer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainscheduledtransfer "github.com/kevinsudut/wallet-system/app/domain/scheduledtransfer"
+	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+)
+
+func TestInit(t *testing.T) {
+	type args struct {
+		scheduledTransfer domainscheduledtransfer.DomainItf
+		auth              domainauth.DomainItf
+		balance           usecasebalance.UsecaseItf
+	}
+	tests := []struct {
+		name string
+		args args
+		want UsecaseItf
+	}{
+		{
+			args: args{
+				scheduledTransfer: nil,
+				auth:              nil,
+				balance:           nil,
+			},
+			want: &usecase{
+				scheduledTransfer: nil,
+				auth:              nil,
+				balance:           nil,
+				location:          time.UTC,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Init(tt.args.scheduledTransfer, tt.args.auth, tt.args.balance); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Init() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
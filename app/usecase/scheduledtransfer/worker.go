@@ -0,0 +1,150 @@
+package usecasescheduledtransfer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainscheduledtransfer "github.com/kevinsudut/wallet-system/app/domain/scheduledtransfer"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	idempotencyKeyScheduledTransfer = "scheduled_transfer:%s:%d"
+)
+
+// RunScheduledTransfers runs the due scheduled transfers on start and then every poll interval, until ctx is done.
+// Every instance runs it, the leases taken by ClaimDueScheduledTransfers keep them from running the same transfer.
+func (u usecase) RunScheduledTransfers(ctx context.Context) {
+	ticker := time.NewTicker(scheduledTransferPollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := u.runDueScheduledTransfers(ctx, time.Now)
+		if err != nil {
+			log.Errorln("RunScheduledTransfers.runDueScheduledTransfers", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDueScheduledTransfers claims and runs the due transfers batch by batch, until a batch comes back short. Every
+// batch reads clock again, running a batch can take long enough for the lease of the next one to start expired.
+func (u usecase) runDueScheduledTransfers(ctx context.Context, clock func() time.Time) (err error) {
+	for {
+		now := clock()
+		leaseId := uuid.NewString()
+		scheduledTransfers, err := u.scheduledTransfer.ClaimDueScheduledTransfers(ctx, domainscheduledtransfer.ClaimDueScheduledTransfersRequest{
+			LeaseId:        leaseId,
+			LeaseExpiresAt: now.Add(scheduledTransferLease),
+			Now:            now,
+			Limit:          scheduledTransferBatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, scheduledTransfer := range scheduledTransfers {
+			// A transfer that could not be finished keeps its lease until it expires and is claimed again then.
+			err = u.runScheduledTransfer(ctx, leaseId, scheduledTransfer, now)
+			if err != nil {
+				log.Errorln("runDueScheduledTransfers.runScheduledTransfer", err)
+			}
+		}
+
+		if len(scheduledTransfers) < scheduledTransferBatch {
+			return nil
+		}
+	}
+}
+
+// runScheduledTransfer runs the current occurrence of a claimed transfer and records the run. The transfer is made
+// with an idempotency key of the occurrence, so running it again after a lost lease or a crash replays the first
+// transfer instead of moving the money twice.
+func (u usecase) runScheduledTransfer(ctx context.Context, leaseId string, scheduledTransfer entity.ScheduledTransfer, now time.Time) (err error) {
+	transferCtx, cancel := context.WithTimeout(ctx, scheduledTransferRunTimeout)
+	transferErr := u.transferScheduledTransfer(transferCtx, scheduledTransfer)
+	cancel()
+
+	run := entity.ScheduledTransferRun{
+		Id:                  uuid.NewString(),
+		ScheduledTransferId: scheduledTransfer.Id,
+		ScheduledAt:         scheduledTransfer.ScheduledAt,
+		Attempt:             scheduledTransfer.Attempt + 1,
+		Status:              int(enum.SCHEDULED_TRANSFER_RUN_STATUS_SUCCEEDED),
+	}
+	if transferErr != nil {
+		run.Status = int(enum.SCHEDULED_TRANSFER_RUN_STATUS_FAILED)
+		run.ErrorCode = apperror.From(transferErr).Code
+	}
+
+	return u.scheduledTransfer.FinishScheduledTransferRun(ctx, domainscheduledtransfer.FinishScheduledTransferRunRequest{
+		LeaseId:           leaseId,
+		ScheduledTransfer: u.nextScheduledTransfer(scheduledTransfer, transferErr == nil, now),
+		Run:               run,
+	})
+}
+
+func (u usecase) transferScheduledTransfer(ctx context.Context, scheduledTransfer entity.ScheduledTransfer) (err error) {
+	toUser, err := u.auth.GetUserById(ctx, scheduledTransfer.ToUserId)
+	if err == sql.ErrNoRows {
+		return errRecipientNotFound
+	}
+	if err != nil {
+		return apperror.ErrDependency.Wrap(err)
+	}
+
+	_, err = u.balance.TransferBalance(ctx, usecasebalance.TransferBalanceRequest{
+		UserId:         scheduledTransfer.UserId,
+		IdempotencyKey: fmt.Sprintf(idempotencyKeyScheduledTransfer, scheduledTransfer.Id, scheduledTransfer.ScheduledAt.Unix()),
+		ToUsername:     toUser.Username,
+		Amount:         scheduledTransfer.Amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// nextScheduledTransfer is the state of the transfer after a run at now. A failed occurrence is retried while the
+// failure policy allows it. Otherwise the transfer moves on to its next occurrence, or ends as completed or failed
+// when it is one-off.
+func (u usecase) nextScheduledTransfer(scheduledTransfer entity.ScheduledTransfer, succeeded bool, now time.Time) entity.ScheduledTransfer {
+	next := scheduledTransfer
+
+	if !succeeded {
+		next.Attempt++
+		if next.FailurePolicy == int(enum.FAILURE_POLICY_RETRY) && next.Attempt < maxScheduledTransferAttempts {
+			next.NextRunAt = now.Add(scheduledTransferRetryInterval)
+			return next
+		}
+	}
+
+	next.Attempt = 0
+
+	occurrence := scheduledTransfer.NextOccurrence(now, u.location)
+	if occurrence.IsZero() {
+		next.Status = int(enum.SCHEDULED_TRANSFER_STATUS_COMPLETED)
+		if !succeeded {
+			next.Status = int(enum.SCHEDULED_TRANSFER_STATUS_FAILED)
+		}
+		return next
+	}
+
+	next.ScheduledAt = occurrence.UTC()
+	next.NextRunAt = next.ScheduledAt
+
+	return next
+}
@@ -0,0 +1,37 @@
+package usecasetransaction
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	cursorSeparator = ","
+)
+
+// encodeCursor builds the opaque cursor pointing after the given history. Histories are ordered
+// by (created_at, id), so the pair is unique even when several histories share a timestamp.
+func encodeCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + cursorSeparator + id))
+}
+
+func decodeCursor(cursor string) (createdAt time.Time, id string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return createdAt, id, err
+	}
+
+	createdAtStr, id, found := strings.Cut(string(raw), cursorSeparator)
+	if !found || id == "" {
+		return createdAt, id, fmt.Errorf("malformed cursor")
+	}
+
+	createdAt, err = time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return createdAt, id, err
+	}
+
+	return createdAt, id, nil
+}
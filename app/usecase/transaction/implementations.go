@@ -2,44 +2,116 @@ package usecasetransaction
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
 	"github.com/kevinsudut/wallet-system/app/entity"
 	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 )
 
+var (
+	errInvalidLimit       = apperror.New(http.StatusBadRequest, "invalid_filter", fmt.Sprintf("limit must be between 1 and %d", maxListTransactionsLimit))
+	errInvalidType        = apperror.New(http.StatusBadRequest, "invalid_filter", "type must be CREDIT, DEBIT, FEE, POCKET, SHARED_WALLET or CONVERSION")
+	errInvalidAmountRange = apperror.New(http.StatusBadRequest, "invalid_filter", "min_amount must not be greater than max_amount")
+	errInvalidDateRange   = apperror.New(http.StatusBadRequest, "invalid_filter", "from must be before to")
+	errInvalidCursor      = apperror.New(http.StatusBadRequest, "invalid_cursor", "cursor is invalid")
+
+	errInvalidDirection        = apperror.New(http.StatusBadRequest, "invalid_filter", "direction must be sent, received or all")
+	errInvalidLeaderboardLimit = apperror.New(http.StatusBadRequest, "invalid_filter", fmt.Sprintf("limit must be between 1 and %d", maxLeaderboardLimit))
+	errInvalidOffset           = apperror.New(http.StatusBadRequest, "invalid_filter", "offset must not be negative")
+	errInvalidWindow           = apperror.New(http.StatusBadRequest, "invalid_filter", fmt.Sprintf("window must be <days>d of at most %d days, week:YYYY-Www or month:YYYY-MM", maxRollingWindowDays))
+)
+
+var historyTypes = map[string]enum.HistoryType{
+	"CREDIT":        enum.CREDIT,
+	"DEBIT":         enum.DEBIT,
+	"FEE":           enum.FEE,
+	"POCKET":        enum.POCKET,
+	"SHARED_WALLET": enum.SHARED_WALLET,
+	"CONVERSION":    enum.CONVERSION,
+}
+
 func (u usecase) ListOverallTopTransactingUsersByValue(ctx context.Context, req ListOverallTopTransactingUsersByValueRequest) (resp ListOverallTopTransactingUsersByValueResponse, err error) {
-	result, err, _ := u.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyListOverallTopTransactingUsersByValue, req.UserId), func() (interface{}, error) {
+	direction := enum.LEADERBOARD_ALL
+	if req.Direction != "" {
+		direction = enum.LeaderboardDirection(strings.ToLower(req.Direction))
+	}
+
+	if direction != enum.LEADERBOARD_SENT && direction != enum.LEADERBOARD_RECEIVED && direction != enum.LEADERBOARD_ALL {
+		return ListOverallTopTransactingUsersByValueResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidDirection
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultLeaderboardLimit
+	}
+
+	if limit < 0 || limit > maxLeaderboardLimit {
+		return ListOverallTopTransactingUsersByValueResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidLeaderboardLimit
+	}
+
+	if req.Offset < 0 {
+		return ListOverallTopTransactingUsersByValueResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidOffset
+	}
+
+	window, err := resolveWindow(req.Window, time.Now(), u.location)
+	if err != nil {
+		return ListOverallTopTransactingUsersByValueResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidWindow.Wrap(err)
+	}
+
+	result, err, _ := u.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyListOverallTopTransactingUsersByValue, direction, req.Window, req.Offset, limit), func() (interface{}, error) {
 		var resp ListOverallTopTransactingUsersByValueResponse
 
-		historySummaries, err := u.balance.GetHistorySummaryByUserIdAndType(ctx, req.UserId, int(enum.DEBIT))
+		entries, err := u.balance.GetLeaderboard(ctx, domainbalance.GetLeaderboardRequest{
+			Direction: direction,
+			Window:    window,
+			Offset:    int64(req.Offset),
+			Limit:     int64(limit + 1),
+		})
 		if err != nil {
-			log.Errorln("ListOverallTopTransactingUsersByValue.GetHistorySummaryByUserIdAndType", err)
+			log.Errorln("ListOverallTopTransactingUsersByValue.GetLeaderboard", err)
 			return ListOverallTopTransactingUsersByValueResponse{
-				Code: http.StatusUnauthorized,
-			}, err
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
+		}
+
+		if len(entries) > limit {
+			entries = entries[:limit]
+			resp.NextOffset = req.Offset + limit
 		}
 
 		var wg sync.WaitGroup
 		goroutineSem := make(chan struct{}, 5)
-		errors := make(chan error, len(historySummaries))
+		errors := make(chan error, len(entries))
 
-		resp.Data = make([]ListOverallTopTransactingUsersByValue, len(historySummaries))
-		for idx, historySummary := range historySummaries {
+		resp.Data = make([]ListOverallTopTransactingUsersByValue, len(entries))
+		for idx, entry := range entries {
 			wg.Add(1)
 
-			go func(idx int, historySummary entity.HistorySummary) {
+			go func(idx int, entry entity.LeaderboardEntry) {
 				goroutineSem <- struct{}{}
 				defer func() {
 					<-goroutineSem
 					wg.Done()
 				}()
 
-				user, err := u.auth.GetUserById(ctx, historySummary.TargetUserId)
+				user, err := u.auth.GetUserById(ctx, entry.UserId)
 				if err != nil {
 					errors <- err
 					log.Errorln("ListOverallTopTransactingUsersByValue.GetUserById", err)
@@ -47,10 +119,11 @@ func (u usecase) ListOverallTopTransactingUsersByValue(ctx context.Context, req
 				}
 
 				resp.Data[idx] = ListOverallTopTransactingUsersByValue{
+					Rank:            req.Offset + idx + 1,
 					Username:        user.Username,
-					TransactedValue: historySummary.Amount,
+					TransactedValue: entry.Amount,
 				}
-			}(idx, historySummary)
+			}(idx, entry)
 		}
 
 		wg.Wait()
@@ -62,8 +135,8 @@ func (u usecase) ListOverallTopTransactingUsersByValue(ctx context.Context, req
 
 		if len(errors) > 0 {
 			return ListOverallTopTransactingUsersByValueResponse{
-				Code: http.StatusUnauthorized,
-			}, fmt.Errorf("goroutine error")
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(<-errors)
 		}
 
 		resp.Code = http.StatusOK
@@ -78,15 +151,30 @@ func (u usecase) ListOverallTopTransactingUsersByValue(ctx context.Context, req
 }
 
 func (u usecase) TopTransactionsForUser(ctx context.Context, req TopTransactionsForUserRequest) (resp TopTransactionsForUserResponse, err error) {
-	result, err, _ := u.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyListOverallTopTransactingUsersByValue, req.UserId), func() (interface{}, error) {
+	window, err := resolveWindow(req.Window, time.Now(), u.location)
+	if err != nil {
+		return TopTransactionsForUserResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidWindow.Wrap(err)
+	}
+
+	result, err, _ := u.singleflight.DoSingleFlight(ctx, fmt.Sprintf(singleFlightKeyTopTransactionsForUser, req.UserId, req.Window), func() (interface{}, error) {
 		var resp TopTransactionsForUserResponse
 
-		histories, err := u.balance.GetLatestHistoryByUserId(ctx, req.UserId)
+		var (
+			histories []entity.History
+			err       error
+		)
+		if window.IsZero() {
+			histories, err = u.balance.GetLatestHistoryByUserId(ctx, req.UserId)
+		} else {
+			histories, err = u.balance.GetTopHistoriesByUserId(ctx, req.UserId, window.From, window.To)
+		}
 		if err != nil {
-			log.Errorln("TopTransactionsForUser.GetLatestHistoryByUserId", err)
+			log.Errorln("TopTransactionsForUser.GetHistories", err)
 			return TopTransactionsForUserResponse{
-				Code: http.StatusUnauthorized,
-			}, err
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
 		}
 
 		sort.Slice(histories, func(i, j int) bool {
@@ -131,8 +219,8 @@ func (u usecase) TopTransactionsForUser(ctx context.Context, req TopTransactions
 
 		if len(errors) > 0 {
 			return TopTransactionsForUserResponse{
-				Code: http.StatusUnauthorized,
-			}, fmt.Errorf("goroutine error")
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(<-errors)
 		}
 
 		resp.Code = http.StatusOK
@@ -145,3 +233,128 @@ func (u usecase) TopTransactionsForUser(ctx context.Context, req TopTransactions
 
 	return result.(TopTransactionsForUserResponse), nil
 }
+
+func (u usecase) ListTransactions(ctx context.Context, req ListTransactionsRequest) (resp ListTransactionsResponse, err error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultListTransactionsLimit
+	}
+	if limit < 0 || limit > maxListTransactionsLimit {
+		return ListTransactionsResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidLimit
+	}
+
+	if req.MinAmount != nil && req.MaxAmount != nil && *req.MinAmount > *req.MaxAmount {
+		return ListTransactionsResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidAmountRange
+	}
+
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return ListTransactionsResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidDateRange
+	}
+
+	// One extra history is fetched to know whether there is a next page.
+	filter := domainbalance.GetHistoriesByUserIdRequest{
+		UserId:    req.UserId,
+		MinAmount: req.MinAmount,
+		MaxAmount: req.MaxAmount,
+		From:      req.From,
+		To:        req.To,
+		Limit:     limit + 1,
+	}
+
+	if req.Type != "" {
+		historyType, ok := historyTypes[strings.ToUpper(req.Type)]
+		if !ok {
+			return ListTransactionsResponse{
+				Code: http.StatusBadRequest,
+			}, errInvalidType
+		}
+		filter.Type = int(historyType)
+	}
+
+	if req.Cursor != "" {
+		filter.CursorCreatedAt, filter.CursorId, err = decodeCursor(req.Cursor)
+		if err != nil {
+			return ListTransactionsResponse{
+				Code: http.StatusBadRequest,
+			}, errInvalidCursor.Wrap(err)
+		}
+	}
+
+	if req.Counterparty != "" {
+		counterparty, err := u.auth.GetUserByUsername(ctx, req.Counterparty)
+		if err == sql.ErrNoRows {
+			return ListTransactionsResponse{
+				Code: http.StatusOK,
+				Data: []Transaction{},
+			}, nil
+		}
+		if err != nil {
+			log.Errorln("ListTransactions.GetUserByUsername", err)
+			return ListTransactionsResponse{
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
+		}
+		filter.TargetUserId = counterparty.Id
+	}
+
+	histories, err := u.balance.GetHistoriesByUserId(ctx, filter)
+	if err != nil {
+		log.Errorln("ListTransactions.GetHistoriesByUserId", err)
+		return ListTransactionsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp.Code = http.StatusOK
+	if len(histories) > limit {
+		histories = histories[:limit]
+		last := histories[len(histories)-1]
+		resp.NextCursor = encodeCursor(last.CreatedAt, last.Id)
+	}
+
+	usernames := make(map[string]string)
+	resp.Data = make([]Transaction, len(histories))
+	for idx, history := range histories {
+		username, ok := usernames[history.TargetUserId]
+		if !ok {
+			user, err := u.auth.GetUserById(ctx, history.TargetUserId)
+			if err != nil {
+				log.Errorln("ListTransactions.GetUserById", err)
+				return ListTransactionsResponse{
+					Code: http.StatusBadGateway,
+				}, apperror.ErrDependency.Wrap(err)
+			}
+			username = user.Username
+			usernames[history.TargetUserId] = username
+		}
+
+		resp.Data[idx] = Transaction{
+			Id:                   history.Id,
+			CounterpartyUsername: username,
+			Amount:               history.Amount,
+			Currency:             history.Currency,
+			Fee:                  history.Fee,
+			Notes:                history.Notes,
+			CreatedAt:            history.CreatedAt,
+		}
+
+		// Top-ups and fees are booked against the user itself, refunds and fees point at the journal entry they
+		// belong to instead.
+		switch {
+		case history.Type == int(enum.FEE):
+			resp.Data[idx].FeeOf = history.ReferenceId
+		case history.ReferenceId != "":
+			resp.Data[idx].RefundedTransferId = history.ReferenceId
+		case history.TargetUserId != history.UserId:
+			resp.Data[idx].TransferId = history.JournalEntryId
+		}
+	}
+
+	return resp, nil
+}
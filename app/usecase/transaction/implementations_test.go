@@ -2,15 +2,20 @@ package usecasetransaction
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
 	"github.com/kevinsudut/wallet-system/pkg/helper/singleflight"
 	"github.com/kevinsudut/wallet-system/pkg/lib/log"
 	gomock "go.uber.org/mock/gomock"
@@ -55,47 +60,80 @@ func Test_usecase_ListOverallTopTransactingUsersByValue(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				req: ListOverallTopTransactingUsersByValueRequest{
-					UserId: "id",
+					Direction: "SENT",
+					Offset:    10,
+					Limit:     2,
 				},
 			},
 			wantResp: ListOverallTopTransactingUsersByValueResponse{
 				Code: http.StatusOK,
 				Data: []ListOverallTopTransactingUsersByValue{
 					{
+						Rank:            11,
 						Username:        "user1",
-						TransactedValue: 100,
+						TransactedValue: 200,
 					},
 					{
-						Username:        "user1",
-						TransactedValue: 200,
+						Rank:            12,
+						Username:        "user2",
+						TransactedValue: 100,
 					},
 				},
+				NextOffset: 12,
+			},
+			wantErr: false,
+			mock: func() {
+				mockDomainBalance.EXPECT().GetLeaderboard(gomock.Any(), domainbalance.GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_SENT,
+					Offset:    int64(10),
+					Limit:     int64(3),
+				}).Return([]entity.LeaderboardEntry{
+					{
+						UserId: "target1",
+						Amount: 200,
+					},
+					{
+						UserId: "target2",
+						Amount: 100,
+					},
+					{
+						UserId: "target3",
+						Amount: 50,
+					},
+				}, nil)
+				mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "target1").Return(entity.User{
+					Id:       "target1",
+					Username: "user1",
+				}, nil)
+				mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "target2").Return(entity.User{
+					Id:       "target2",
+					Username: "user2",
+				}, nil)
+			},
+		},
+		{
+			name: "success default",
+			fields: fields{
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				singleflight: mockSingleFlight,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListOverallTopTransactingUsersByValueRequest{},
+			},
+			wantResp: ListOverallTopTransactingUsersByValueResponse{
+				Code: http.StatusOK,
+				Data: []ListOverallTopTransactingUsersByValue{},
 			},
 			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GetHistorySummaryByUserIdAndType(gomock.Any(), "id", 2).Return([]entity.HistorySummary{
-						{
-							UserId:       "id",
-							TargetUserId: "target1",
-							Amount:       100,
-							Type:         2,
-						},
-						{
-							UserId:       "id",
-							TargetUserId: "target1",
-							Amount:       200,
-							Type:         2,
-						},
-					}, nil),
-					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "target1").Return(entity.User{
-						Id:       "target1",
-						Username: "user1",
-					}, nil),
-					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "target1").Return(entity.User{
-						Id:       "target1",
-						Username: "user1",
-					}, nil),
+					mockDomainBalance.EXPECT().GetLeaderboard(gomock.Any(), domainbalance.GetLeaderboardRequest{
+						Direction: enum.LEADERBOARD_ALL,
+						Offset:    int64(0),
+						Limit:     int64(defaultLeaderboardLimit + 1),
+					}).Return([]entity.LeaderboardEntry{}, nil),
 				)
 			},
 		},
@@ -109,39 +147,67 @@ func Test_usecase_ListOverallTopTransactingUsersByValue(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				req: ListOverallTopTransactingUsersByValueRequest{
-					UserId: "id",
+					Direction: "received",
+					Limit:     2,
 				},
 			},
 			wantResp: ListOverallTopTransactingUsersByValueResponse{
-				Code: http.StatusUnauthorized,
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				mockDomainBalance.EXPECT().GetLeaderboard(gomock.Any(), domainbalance.GetLeaderboardRequest{
+					Direction: enum.LEADERBOARD_RECEIVED,
+					Offset:    int64(0),
+					Limit:     int64(3),
+				}).Return([]entity.LeaderboardEntry{
+					{
+						UserId: "target1",
+						Amount: 200,
+					},
+					{
+						UserId: "target2",
+						Amount: 100,
+					},
+					{
+						UserId: "target3",
+						Amount: 50,
+					},
+				}, nil)
+				mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "target1").Return(entity.User{
+					Id:       "target1",
+					Username: "user1",
+				}, nil)
+				mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "target2").Return(entity.User{}, fmt.Errorf("foo"))
+			},
+		},
+		{
+			name: "error balance.GetLeaderboard",
+			fields: fields{
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				singleflight: mockSingleFlight,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListOverallTopTransactingUsersByValueRequest{},
+			},
+			wantResp: ListOverallTopTransactingUsersByValueResponse{
+				Code: http.StatusBadGateway,
 			},
 			wantErr: true,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GetHistorySummaryByUserIdAndType(gomock.Any(), "id", 2).Return([]entity.HistorySummary{
-						{
-							UserId:       "id",
-							TargetUserId: "target1",
-							Amount:       100,
-							Type:         2,
-						},
-						{
-							UserId:       "id",
-							TargetUserId: "target1",
-							Amount:       200,
-							Type:         2,
-						},
-					}, nil),
-					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "target1").Return(entity.User{
-						Id:       "target1",
-						Username: "user1",
-					}, nil),
-					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "target1").Return(entity.User{}, fmt.Errorf("foo")),
+					mockDomainBalance.EXPECT().GetLeaderboard(gomock.Any(), domainbalance.GetLeaderboardRequest{
+						Direction: enum.LEADERBOARD_ALL,
+						Offset:    int64(0),
+						Limit:     int64(defaultLeaderboardLimit + 1),
+					}).Return(nil, fmt.Errorf("foo")),
 				)
 			},
 		},
 		{
-			name: "error balance.GetHistorySummaryByUserIdAndType",
+			name: "success window",
 			fields: fields{
 				balance:      mockDomainBalance,
 				auth:         mockDomainAuth,
@@ -150,19 +216,105 @@ func Test_usecase_ListOverallTopTransactingUsersByValue(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 				req: ListOverallTopTransactingUsersByValueRequest{
-					UserId: "id",
+					Window: "month:2026-09",
 				},
 			},
 			wantResp: ListOverallTopTransactingUsersByValueResponse{
-				Code: http.StatusUnauthorized,
+				Code: http.StatusOK,
+				Data: []ListOverallTopTransactingUsersByValue{},
 			},
-			wantErr: true,
+			wantErr: false,
 			mock: func() {
 				gomock.InOrder(
-					mockDomainBalance.EXPECT().GetHistorySummaryByUserIdAndType(gomock.Any(), "id", 2).Return([]entity.HistorySummary{}, fmt.Errorf("foo")),
+					mockDomainBalance.EXPECT().GetLeaderboard(gomock.Any(), domainbalance.GetLeaderboardRequest{
+						Direction: enum.LEADERBOARD_ALL,
+						Window: domainbalance.SummaryWindow{
+							Period: enum.SUMMARY_PERIOD_MONTH,
+							From:   time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+							To:     time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+						},
+						Offset: int64(0),
+						Limit:  int64(defaultLeaderboardLimit + 1),
+					}).Return([]entity.LeaderboardEntry{}, nil),
 				)
 			},
 		},
+		{
+			name: "error invalid window",
+			fields: fields{
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				singleflight: mockSingleFlight,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListOverallTopTransactingUsersByValueRequest{
+					Window: "31d",
+				},
+			},
+			wantResp: ListOverallTopTransactingUsersByValueResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid direction",
+			fields: fields{
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				singleflight: mockSingleFlight,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListOverallTopTransactingUsersByValueRequest{
+					Direction: "foo",
+				},
+			},
+			wantResp: ListOverallTopTransactingUsersByValueResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid limit",
+			fields: fields{
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				singleflight: mockSingleFlight,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListOverallTopTransactingUsersByValueRequest{
+					Limit: maxLeaderboardLimit + 1,
+				},
+			},
+			wantResp: ListOverallTopTransactingUsersByValueResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid offset",
+			fields: fields{
+				balance:      mockDomainBalance,
+				auth:         mockDomainAuth,
+				singleflight: mockSingleFlight,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListOverallTopTransactingUsersByValueRequest{
+					Offset: -1,
+				},
+			},
+			wantResp: ListOverallTopTransactingUsersByValueResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -170,6 +322,7 @@ func Test_usecase_ListOverallTopTransactingUsersByValue(t *testing.T) {
 				auth:         tt.fields.auth,
 				balance:      tt.fields.balance,
 				singleflight: tt.fields.singleflight,
+				location:     time.UTC,
 			}
 			tt.mock()
 			gotResp, err := u.ListOverallTopTransactingUsersByValue(tt.args.ctx, tt.args.req)
@@ -276,7 +429,7 @@ func Test_usecase_TopTransactionsForUser(t *testing.T) {
 				},
 			},
 			wantResp: TopTransactionsForUserResponse{
-				Code: http.StatusUnauthorized,
+				Code: http.StatusBadGateway,
 			},
 			wantErr: true,
 			mock: func() {
@@ -293,6 +446,67 @@ func Test_usecase_TopTransactionsForUser(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "success window",
+			fields: fields{
+				auth:         mockDomainAuth,
+				balance:      mockDomainBalance,
+				singleflight: mockSingleFlight,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TopTransactionsForUserRequest{
+					UserId: "id",
+					Window: "month:2026-09",
+				},
+			},
+			wantResp: TopTransactionsForUserResponse{
+				Code: http.StatusOK,
+				Data: []TopTransactionsForUser{
+					{
+						Username: "username",
+						Amount:   50,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetTopHistoriesByUserId(gomock.Any(), "id", time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)).Return([]entity.History{
+						{
+							UserId:       "id",
+							TargetUserId: "id",
+							Amount:       50,
+							Type:         1,
+						},
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Username: "username",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "error invalid window",
+			fields: fields{
+				auth:         mockDomainAuth,
+				balance:      mockDomainBalance,
+				singleflight: mockSingleFlight,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: TopTransactionsForUserRequest{
+					UserId: "id",
+					Window: "foo",
+				},
+			},
+			wantResp: TopTransactionsForUserResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
 		{
 			name: "error auth.GetLatestHistoryByUserId",
 			fields: fields{
@@ -307,7 +521,7 @@ func Test_usecase_TopTransactionsForUser(t *testing.T) {
 				},
 			},
 			wantResp: TopTransactionsForUserResponse{
-				Code: http.StatusUnauthorized,
+				Code: http.StatusBadGateway,
 			},
 			wantErr: true,
 			mock: func() {
@@ -323,6 +537,7 @@ func Test_usecase_TopTransactionsForUser(t *testing.T) {
 				auth:         tt.fields.auth,
 				balance:      tt.fields.balance,
 				singleflight: tt.fields.singleflight,
+				location:     time.UTC,
 			}
 			tt.mock()
 			gotResp, err := u.TopTransactionsForUser(tt.args.ctx, tt.args.req)
@@ -336,3 +551,591 @@ func Test_usecase_TopTransactionsForUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_usecase_ListTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursorCreatedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	minAmount := money.Money(200)
+	maxAmount := money.Money(100)
+
+	type fields struct {
+		auth    domainauth.DomainItf
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListTransactionsRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListTransactionsResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusOK,
+				Data: []Transaction{
+					{
+						Id:                   "history1",
+						TransferId:           "transfer1",
+						CounterpartyUsername: "target",
+						Amount:               -100,
+						Currency:             "IDR",
+						Notes:                "Transfer money to target",
+						CreatedAt:            createdAt,
+					},
+					{
+						Id:                   "history2",
+						RefundedTransferId:   "transfer1",
+						CounterpartyUsername: "target",
+						Amount:               100,
+						Notes:                "Refund of transfer to target",
+						CreatedAt:            createdAt,
+					},
+					{
+						Id:                   "history3",
+						FeeOf:                "transfer2",
+						CounterpartyUsername: "username",
+						Amount:               -1,
+						Notes:                "Fee of transfer to target",
+						CreatedAt:            createdAt,
+					},
+					{
+						Id:                   "history4",
+						TransferId:           "transfer2",
+						CounterpartyUsername: "target",
+						Amount:               -100,
+						Fee:                  1,
+						Notes:                "Transfer money to target",
+						CreatedAt:            createdAt,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHistoriesByUserId(gomock.Any(), domainbalance.GetHistoriesByUserIdRequest{
+						UserId: "id",
+						Limit:  defaultListTransactionsLimit + 1,
+					}).Return([]entity.History{
+						{
+							Id:             "history1",
+							JournalEntryId: "transfer1",
+							UserId:         "id",
+							TargetUserId:   "targetid",
+							Amount:         -100,
+							Currency:       "IDR",
+							Notes:          "Transfer money to target",
+							CreatedAt:      createdAt,
+						},
+						{
+							Id:             "history2",
+							JournalEntryId: "refund1",
+							UserId:         "id",
+							TargetUserId:   "targetid",
+							Amount:         100,
+							Notes:          "Refund of transfer to target",
+							ReferenceId:    "transfer1",
+							CreatedAt:      createdAt,
+						},
+						{
+							Id:             "history3",
+							JournalEntryId: "fee1",
+							UserId:         "id",
+							TargetUserId:   "id",
+							Amount:         -1,
+							Type:           int(enum.FEE),
+							Notes:          "Fee of transfer to target",
+							ReferenceId:    "transfer2",
+							CreatedAt:      createdAt,
+						},
+						{
+							Id:             "history4",
+							JournalEntryId: "transfer2",
+							UserId:         "id",
+							TargetUserId:   "targetid",
+							Amount:         -100,
+							Fee:            1,
+							Type:           int(enum.DEBIT),
+							Notes:          "Transfer money to target",
+							CreatedAt:      createdAt,
+						},
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "targetid").Return(entity.User{
+						Id:       "targetid",
+						Username: "target",
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{
+						Id:       "id",
+						Username: "username",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success with next page",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId:       "id",
+					Type:         "debit",
+					Counterparty: "target",
+					Cursor:       encodeCursor(cursorCreatedAt, "cursor"),
+					Limit:        1,
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusOK,
+				Data: []Transaction{
+					{
+						Id:                   "history1",
+						CounterpartyUsername: "target",
+						Amount:               -100,
+						CreatedAt:            createdAt,
+					},
+				},
+				NextCursor: encodeCursor(createdAt, "history1"),
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "target").Return(entity.User{
+						Id:       "targetid",
+						Username: "target",
+					}, nil),
+					mockDomainBalance.EXPECT().GetHistoriesByUserId(gomock.Any(), domainbalance.GetHistoriesByUserIdRequest{
+						UserId:          "id",
+						Type:            int(enum.DEBIT),
+						TargetUserId:    "targetid",
+						CursorCreatedAt: cursorCreatedAt,
+						CursorId:        "cursor",
+						Limit:           2,
+					}).Return([]entity.History{
+						{
+							Id:           "history1",
+							TargetUserId: "targetid",
+							Amount:       -100,
+							CreatedAt:    createdAt,
+						},
+						{
+							Id:           "history2",
+							TargetUserId: "targetid",
+							Amount:       -100,
+							CreatedAt:    createdAt,
+						},
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "targetid").Return(entity.User{
+						Id:       "targetid",
+						Username: "target",
+					}, nil),
+				)
+			},
+		},
+		{
+			name: "success unknown counterparty",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId:       "id",
+					Counterparty: "target",
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusOK,
+				Data: []Transaction{},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "target").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserByUsername",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId:       "id",
+					Counterparty: "target",
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "target").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error balance.GetHistoriesByUserId",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHistoriesByUserId(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error auth.GetUserById",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetHistoriesByUserId(gomock.Any(), gomock.Any()).Return([]entity.History{
+						{
+							Id:           "history1",
+							TargetUserId: "targetid",
+						},
+					}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "targetid").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error invalid limit",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId: "id",
+					Limit:  maxListTransactionsLimit + 1,
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid type",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId: "id",
+					Type:   "REFUND",
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid cursor",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId: "id",
+					Cursor: "cursor",
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid amount range",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId:    "id",
+					MinAmount: &minAmount,
+					MaxAmount: &maxAmount,
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error invalid date range",
+			fields: fields{
+				balance: mockDomainBalance,
+				auth:    mockDomainAuth,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListTransactionsRequest{
+					UserId: "id",
+					From:   cursorCreatedAt,
+					To:     createdAt,
+				},
+			},
+			wantResp: ListTransactionsResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:    tt.fields.auth,
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.ListTransactions(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListTransactions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListTransactions() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_decodeCursor(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 123456000, time.UTC)
+
+	tests := []struct {
+		name          string
+		cursor        string
+		wantCreatedAt time.Time
+		wantId        string
+		wantErr       bool
+	}{
+		{
+			name:          "success",
+			cursor:        encodeCursor(createdAt, "id"),
+			wantCreatedAt: createdAt,
+			wantId:        "id",
+			wantErr:       false,
+		},
+		{
+			name:    "error base64",
+			cursor:  "!",
+			wantErr: true,
+		},
+		{
+			name:    "error missing id",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte(createdAt.Format(time.RFC3339Nano))),
+			wantErr: true,
+		},
+		{
+			name:    "error timestamp",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte("yesterday,id")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCreatedAt, gotId, err := decodeCursor(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !gotCreatedAt.Equal(tt.wantCreatedAt) || gotId != tt.wantId {
+				t.Errorf("decodeCursor() = %v %v, want %v %v", gotCreatedAt, gotId, tt.wantCreatedAt, tt.wantId)
+			}
+		})
+	}
+}
+
+func Test_resolveWindow(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 9, 15, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		window   string
+		location *time.Location
+		wantResp domainbalance.SummaryWindow
+		wantErr  bool
+	}{
+		{
+			name:     "success all time",
+			window:   "",
+			location: time.UTC,
+			wantResp: domainbalance.SummaryWindow{},
+			wantErr:  false,
+		},
+		{
+			name:     "success 7d",
+			window:   "7d",
+			location: time.UTC,
+			wantResp: domainbalance.SummaryWindow{
+				Period: enum.SUMMARY_PERIOD_DAY,
+				From:   time.Date(2026, 9, 9, 0, 0, 0, 0, time.UTC),
+				To:     time.Date(2026, 9, 16, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name:     "success 30d in business time zone",
+			window:   "30d",
+			location: jakarta,
+			wantResp: domainbalance.SummaryWindow{
+				Period: enum.SUMMARY_PERIOD_DAY,
+				From:   time.Date(2026, 8, 18, 0, 0, 0, 0, jakarta),
+				To:     time.Date(2026, 9, 17, 0, 0, 0, 0, jakarta),
+			},
+			wantErr: false,
+		},
+		{
+			name:     "success week",
+			window:   "week:2026-W38",
+			location: time.UTC,
+			wantResp: domainbalance.SummaryWindow{
+				Period: enum.SUMMARY_PERIOD_WEEK,
+				From:   time.Date(2026, 9, 14, 0, 0, 0, 0, time.UTC),
+				To:     time.Date(2026, 9, 21, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name:     "success first week starting in previous year",
+			window:   "week:2026-W01",
+			location: time.UTC,
+			wantResp: domainbalance.SummaryWindow{
+				Period: enum.SUMMARY_PERIOD_WEEK,
+				From:   time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC),
+				To:     time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name:     "success month",
+			window:   "month:2026-09",
+			location: jakarta,
+			wantResp: domainbalance.SummaryWindow{
+				Period: enum.SUMMARY_PERIOD_MONTH,
+				From:   time.Date(2026, 9, 1, 0, 0, 0, 0, jakarta),
+				To:     time.Date(2026, 10, 1, 0, 0, 0, 0, jakarta),
+			},
+			wantErr: false,
+		},
+		{
+			name:     "error days out of range",
+			window:   "31d",
+			location: time.UTC,
+			wantErr:  true,
+		},
+		{
+			name:     "error days",
+			window:   "xd",
+			location: time.UTC,
+			wantErr:  true,
+		},
+		{
+			name:     "error week does not exist",
+			window:   "week:2025-W53",
+			location: time.UTC,
+			wantErr:  true,
+		},
+		{
+			name:     "error month",
+			window:   "month:2026-13",
+			location: time.UTC,
+			wantErr:  true,
+		},
+		{
+			name:     "error unknown",
+			window:   "foo",
+			location: time.UTC,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResp, err := resolveWindow(tt.window, now, tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveWindow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("resolveWindow() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
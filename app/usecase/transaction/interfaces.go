@@ -5,4 +5,5 @@ import "context"
 type UsecaseItf interface {
 	ListOverallTopTransactingUsersByValue(ctx context.Context, req ListOverallTopTransactingUsersByValueRequest) (resp ListOverallTopTransactingUsersByValueResponse, err error)
 	TopTransactionsForUser(ctx context.Context, req TopTransactionsForUserRequest) (resp TopTransactionsForUserResponse, err error)
+	ListTransactions(ctx context.Context, req ListTransactionsRequest) (resp ListTransactionsResponse, err error)
 }
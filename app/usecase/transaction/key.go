@@ -1,6 +1,6 @@
 package usecasetransaction
 
 const (
-	singleFlightKeyListOverallTopTransactingUsersByValue = "sf:usecase:transaction:ListOverallTopTransactingUsersByValue:user_id:%s"
-	singleFlightKeyTopTransactionsForUser                = "sf:usecase:transaction:TopTransactionsForUser:user_id:%s"
+	singleFlightKeyListOverallTopTransactingUsersByValue = "sf:usecase:transaction:ListOverallTopTransactingUsersByValue:direction:%s:window:%s:offset:%d:limit:%d"
+	singleFlightKeyTopTransactionsForUser                = "sf:usecase:transaction:TopTransactionsForUser:user_id:%s:window:%s"
 )
@@ -54,6 +54,21 @@ func (mr *MockUsecaseItfMockRecorder) ListOverallTopTransactingUsersByValue(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverallTopTransactingUsersByValue", reflect.TypeOf((*MockUsecaseItf)(nil).ListOverallTopTransactingUsersByValue), ctx, req)
 }
 
+// ListTransactions mocks base method.
+func (m *MockUsecaseItf) ListTransactions(ctx context.Context, req ListTransactionsRequest) (ListTransactionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, req)
+	ret0, _ := ret[0].(ListTransactionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockUsecaseItfMockRecorder) ListTransactions(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockUsecaseItf)(nil).ListTransactions), ctx, req)
+}
+
 // TopTransactionsForUser mocks base method.
 func (m *MockUsecaseItf) TopTransactionsForUser(ctx context.Context, req TopTransactionsForUserRequest) (TopTransactionsForUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -1,29 +1,76 @@
 package usecasetransaction
 
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
 type ListOverallTopTransactingUsersByValueRequest struct {
-	UserId string
+	Direction string
+	Window    string
+	Offset    int
+	Limit     int
 }
 
 type ListOverallTopTransactingUsersByValue struct {
-	Username        string  `json:"username"`
-	TransactedValue float64 `json:"transacted_value"`
+	Rank            int         `json:"rank"`
+	Username        string      `json:"username"`
+	TransactedValue money.Money `json:"transacted_value"`
 }
 
 type ListOverallTopTransactingUsersByValueResponse struct {
-	Code int `json:"-"`
-	Data []ListOverallTopTransactingUsersByValue
+	Code       int                                     `json:"-"`
+	Data       []ListOverallTopTransactingUsersByValue `json:"data"`
+	NextOffset int                                     `json:"next_offset,omitempty"`
 }
 
 type TopTransactionsForUserRequest struct {
 	UserId string
+	Window string
 }
 
 type TopTransactionsForUser struct {
-	Username string  `json:"username"`
-	Amount   float64 `json:"amount"`
+	Username string      `json:"username"`
+	Amount   money.Money `json:"amount"`
 }
 
 type TopTransactionsForUserResponse struct {
 	Code int `json:"-"`
 	Data []TopTransactionsForUser
 }
+
+type ListTransactionsRequest struct {
+	UserId       string
+	Type         string
+	Counterparty string
+	MinAmount    *money.Money
+	MaxAmount    *money.Money
+	From         time.Time
+	To           time.Time
+	Cursor       string
+	Limit        int
+}
+
+// Transaction is one history row of the user. TransferId is set on both legs of a transfer, and
+// RefundedTransferId on both legs of a refund, pointing at the transfer it refunds. Fee is what the transfer or
+// top-up was charged on top of Amount, which is also listed as a row of its own with FeeOf pointing at the
+// transfer or top-up. Amount is in Currency, a conversion is listed in the currency it converted from.
+type Transaction struct {
+	Id                   string      `json:"id"`
+	TransferId           string      `json:"transfer_id,omitempty"`
+	RefundedTransferId   string      `json:"refunded_transfer_id,omitempty"`
+	FeeOf                string      `json:"fee_of,omitempty"`
+	CounterpartyUsername string      `json:"counterparty_username"`
+	Amount               money.Money `json:"amount"`
+	Currency             string      `json:"currency"`
+	Fee                  money.Money `json:"fee,omitempty"`
+	Notes                string      `json:"notes"`
+	CreatedAt            time.Time   `json:"created_at"`
+}
+
+type ListTransactionsResponse struct {
+	Code       int           `json:"-"`
+	Data       []Transaction `json:"data"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -1,15 +1,27 @@
 package usecasetransaction
 
 import (
+	"time"
+
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
 	"github.com/kevinsudut/wallet-system/pkg/helper/singleflight"
+	"github.com/kevinsudut/wallet-system/pkg/helper/timezone"
+)
+
+const (
+	defaultListTransactionsLimit = 20
+	maxListTransactionsLimit     = 100
+
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
 )
 
 type usecase struct {
 	auth         domainauth.DomainItf
 	balance      domainbalance.DomainItf
 	singleflight singleflight.SingleFlightItf
+	location     *time.Location
 }
 
 func Init(auth domainauth.DomainItf, balance domainbalance.DomainItf) UsecaseItf {
@@ -17,5 +29,6 @@ func Init(auth domainauth.DomainItf, balance domainbalance.DomainItf) UsecaseItf
 		auth:         auth,
 		balance:      balance,
 		singleflight: singleflight.Init(),
+		location:     timezone.Business(),
 	}
 }
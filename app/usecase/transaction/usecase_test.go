@@ -3,6 +3,7 @@ package usecasetransaction
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
@@ -28,6 +29,7 @@ func TestInit(t *testing.T) {
 				balance:      nil,
 				auth:         nil,
 				singleflight: singleflight.Init(),
+				location:     time.UTC,
 			},
 		},
 	}
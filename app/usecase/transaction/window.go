@@ -0,0 +1,81 @@
+package usecasetransaction
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/timezone"
+)
+
+const (
+	windowWeekPrefix  = "week:"
+	windowMonthPrefix = "month:"
+	windowDaysSuffix  = "d"
+
+	// Rolling windows are summed from day buckets, which are only kept for a little longer than this.
+	maxRollingWindowDays = 30
+)
+
+// resolveWindow turns a window such as "7d", "week:2026-W38" or "month:2026-09" into the summary buckets it
+// covers in location. Rolling windows of N days end today, and an empty window means all time.
+func resolveWindow(window string, now time.Time, location *time.Location) (resp domainbalance.SummaryWindow, err error) {
+	switch {
+	case window == "":
+		return resp, nil
+
+	case strings.HasPrefix(window, windowMonthPrefix):
+		from, err := time.ParseInLocation("2006-01", strings.TrimPrefix(window, windowMonthPrefix), location)
+		if err != nil {
+			return resp, err
+		}
+
+		return domainbalance.SummaryWindow{
+			Period: enum.SUMMARY_PERIOD_MONTH,
+			From:   from,
+			To:     from.AddDate(0, 1, 0),
+		}, nil
+
+	case strings.HasPrefix(window, windowWeekPrefix):
+		var year, week int
+		_, err := fmt.Sscanf(strings.TrimPrefix(window, windowWeekPrefix), "%4d-W%2d", &year, &week)
+		if err != nil {
+			return resp, err
+		}
+
+		// The first ISO week of a year is the one containing January 4th, weeks start on Monday.
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, location)
+		from := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+		if isoYear, isoWeek := from.ISOWeek(); isoYear != year || isoWeek != week {
+			return resp, fmt.Errorf("week %d does not exist in %d", week, year)
+		}
+
+		return domainbalance.SummaryWindow{
+			Period: enum.SUMMARY_PERIOD_WEEK,
+			From:   from,
+			To:     from.AddDate(0, 0, 7),
+		}, nil
+
+	case strings.HasSuffix(window, windowDaysSuffix):
+		days, err := strconv.Atoi(strings.TrimSuffix(window, windowDaysSuffix))
+		if err != nil {
+			return resp, err
+		}
+		if days < 1 || days > maxRollingWindowDays {
+			return resp, fmt.Errorf("window must be between 1 and %d days", maxRollingWindowDays)
+		}
+
+		today := timezone.StartOfDay(now, location)
+
+		return domainbalance.SummaryWindow{
+			Period: enum.SUMMARY_PERIOD_DAY,
+			From:   today.AddDate(0, 0, 1-days),
+			To:     today.AddDate(0, 0, 1),
+		}, nil
+	}
+
+	return resp, fmt.Errorf("unknown window %q", window)
+}
@@ -1,29 +1,82 @@
 package usecase
 
 import (
+	"context"
+
 	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
 	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	domainfee "github.com/kevinsudut/wallet-system/app/domain/fee"
+	domainfx "github.com/kevinsudut/wallet-system/app/domain/fx"
+	domainlimit "github.com/kevinsudut/wallet-system/app/domain/limit"
+	domainnotification "github.com/kevinsudut/wallet-system/app/domain/notification"
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+	domainpaymentrequest "github.com/kevinsudut/wallet-system/app/domain/paymentrequest"
+	domainscheduledtransfer "github.com/kevinsudut/wallet-system/app/domain/scheduledtransfer"
+	domainwebhook "github.com/kevinsudut/wallet-system/app/domain/webhook"
+	usecaseaccount "github.com/kevinsudut/wallet-system/app/usecase/account"
 	usecaseauth "github.com/kevinsudut/wallet-system/app/usecase/auth"
 	usecasebalance "github.com/kevinsudut/wallet-system/app/usecase/balance"
+	usecaselimit "github.com/kevinsudut/wallet-system/app/usecase/limit"
+	usecasenotification "github.com/kevinsudut/wallet-system/app/usecase/notification"
+	usecasepaymentrequest "github.com/kevinsudut/wallet-system/app/usecase/paymentrequest"
+	usecasescheduledtransfer "github.com/kevinsudut/wallet-system/app/usecase/scheduledtransfer"
 	usecasetransaction "github.com/kevinsudut/wallet-system/app/usecase/transaction"
+	usecasewallet "github.com/kevinsudut/wallet-system/app/usecase/wallet"
+	usecasewebhook "github.com/kevinsudut/wallet-system/app/usecase/webhook"
 	"github.com/kevinsudut/wallet-system/pkg/lib/database"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
 	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
 	"github.com/kevinsudut/wallet-system/pkg/lib/token"
 )
 
 type usecase struct {
-	Auth        usecaseauth.UsecaseItf
-	Balance     usecasebalance.UsecaseItf
-	Transaction usecasetransaction.UsecaseItf
+	Auth              usecaseauth.UsecaseItf
+	Balance           usecasebalance.UsecaseItf
+	Transaction       usecasetransaction.UsecaseItf
+	ScheduledTransfer usecasescheduledtransfer.UsecaseItf
+	PaymentRequest    usecasepaymentrequest.UsecaseItf
+	Limit             usecaselimit.UsecaseItf
+	Account           usecaseaccount.UsecaseItf
+	Wallet            usecasewallet.UsecaseItf
+	Webhook           usecasewebhook.UsecaseItf
+	Notification      usecasenotification.UsecaseItf
 }
 
-func Init(token token.TokenItf, db database.DatabaseItf, redis redis.RedisItf) usecase {
-	domainAuth := domainauth.Init(db, redis)
-	domainBalance := domainbalance.Init(db, redis)
+func Init(ctx context.Context, token token.TokenItf, db database.DatabaseItf, redis redis.RedisItf, cache lrucache.LRUCacheItf) usecase {
+	domainAuth := domainauth.Init(db, redis, cache)
+	domainBalance := domainbalance.Init(ctx, db, redis, cache)
+	domainScheduledTransfer := domainscheduledtransfer.Init(db)
+	domainPaymentRequest := domainpaymentrequest.Init(db)
+	domainLimit := domainlimit.Init(db, redis)
+	domainFee := domainfee.Init(db)
+	domainFx := domainfx.Init(db)
+	domainOutbox := domainoutbox.Init(ctx, db, redis)
+	domainWebhook := domainwebhook.Init(db)
+	domainNotification := domainnotification.Init(redis)
+
+	notification := usecasenotification.Init(domainNotification, domainBalance, domainAuth, domainOutbox)
+	limit := usecaselimit.Init(domainLimit, domainAuth, domainFx)
+	balance := usecasebalance.Init(domainBalance, domainAuth, limit, domainFee, domainFx)
+	scheduledTransfer := usecasescheduledtransfer.Init(domainScheduledTransfer, domainAuth, balance)
+
+	webhook := usecasewebhook.Init(domainWebhook, domainOutbox)
+
+	go scheduledTransfer.RunScheduledTransfers(ctx)
+	go webhook.RunWebhookDispatcher(ctx)
+	go webhook.RunWebhookDeliveries(ctx)
+	go notification.RunNotificationSubscriber(ctx)
+	go notification.RunNotificationDispatcher(ctx)
 
 	return usecase{
-		Auth:        usecaseauth.Init(domainAuth, token),
-		Balance:     usecasebalance.Init(domainBalance, domainAuth),
-		Transaction: usecasetransaction.Init(domainAuth, domainBalance),
+		Auth:              usecaseauth.Init(domainAuth, token),
+		Balance:           balance,
+		Transaction:       usecasetransaction.Init(domainAuth, domainBalance),
+		ScheduledTransfer: scheduledTransfer,
+		PaymentRequest:    usecasepaymentrequest.Init(domainPaymentRequest, domainAuth, balance),
+		Limit:             limit,
+		Account:           usecaseaccount.Init(domainAuth, domainBalance),
+		Wallet:            usecasewallet.Init(domainAuth, domainBalance),
+		Webhook:           webhook,
+		Notification:      notification,
 	}
 }
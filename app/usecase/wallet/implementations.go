@@ -0,0 +1,382 @@
+package usecasewallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+const (
+	maxWalletNameLength = 50
+
+	// maxWalletTransactions is how many of the latest movements of a wallet are listed.
+	maxWalletTransactions = 50
+)
+
+var (
+	errInvalidName        = apperror.New(http.StatusBadRequest, "invalid_name", fmt.Sprintf("name is required and must be at most %d characters", maxWalletNameLength))
+	errInvalidRole        = apperror.New(http.StatusBadRequest, "invalid_role", "role must be one of owner, spender or viewer")
+	errInvalidSpendLimit  = apperror.New(http.StatusBadRequest, "invalid_spend_limit", "spend_limit must be greater than 0 for a spender and omitted for the other roles")
+	errUserNotFound       = apperror.New(http.StatusNotFound, "user_not_found", "username does not exist")
+	errWalletNotFound     = apperror.New(http.StatusNotFound, "wallet_not_found", "wallet does not exist or the user is not a member of it")
+	errWalletForbidden    = apperror.New(http.StatusForbidden, "wallet_forbidden", "the role of the user in the wallet does not allow this operation")
+	errMemberExists       = apperror.New(http.StatusConflict, "member_exists", "user is already invited to or a member of the wallet")
+	errMemberNotFound     = apperror.New(http.StatusNotFound, "member_not_found", "user is not invited to or a member of the wallet")
+	errInvitationNotFound = apperror.New(http.StatusNotFound, "invitation_not_found", "user has no pending invitation to the wallet")
+	errLastOwner          = apperror.New(http.StatusConflict, "last_owner", "wallet must keep at least one owner")
+)
+
+var roles = map[string]enum.SharedWalletRole{
+	enum.SHARED_WALLET_ROLE_OWNER.String():   enum.SHARED_WALLET_ROLE_OWNER,
+	enum.SHARED_WALLET_ROLE_SPENDER.String(): enum.SHARED_WALLET_ROLE_SPENDER,
+	enum.SHARED_WALLET_ROLE_VIEWER.String():  enum.SHARED_WALLET_ROLE_VIEWER,
+}
+
+// walletError maps the errors of changing the members of a wallet in the domain.
+func walletError(err error) (code int, appErr error) {
+	switch {
+	case errors.Is(err, domainbalance.ErrSharedWalletNotFound):
+		return http.StatusNotFound, errWalletNotFound
+	case errors.Is(err, domainbalance.ErrSharedWalletForbidden):
+		return http.StatusForbidden, errWalletForbidden
+	case errors.Is(err, domainbalance.ErrSharedWalletMemberExists):
+		return http.StatusConflict, errMemberExists
+	case errors.Is(err, domainbalance.ErrSharedWalletMemberNotFound):
+		return http.StatusNotFound, errMemberNotFound
+	case errors.Is(err, domainbalance.ErrSharedWalletInvitationNotFound):
+		return http.StatusNotFound, errInvitationNotFound
+	case errors.Is(err, domainbalance.ErrSharedWalletLastOwner):
+		return http.StatusConflict, errLastOwner
+	}
+
+	return http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+}
+
+func newWallet(wallet entity.SharedWallet, member entity.SharedWalletMember) Wallet {
+	return Wallet{
+		Id:         wallet.Id,
+		Name:       wallet.Name,
+		Amount:     wallet.Amount,
+		Role:       enum.SharedWalletRole(member.Role).String(),
+		Status:     enum.SharedWalletMemberStatus(member.Status).String(),
+		SpendLimit: member.SpendLimit,
+		CreatedAt:  wallet.CreatedAt,
+	}
+}
+
+func newMember(member entity.SharedWalletMember, username string) Member {
+	return Member{
+		UserId:     member.UserId,
+		Username:   username,
+		Role:       enum.SharedWalletRole(member.Role).String(),
+		Status:     enum.SharedWalletMemberStatus(member.Status).String(),
+		SpendLimit: member.SpendLimit,
+	}
+}
+
+// activeMember returns the wallet members together with the active membership of userId. The wallet is not found
+// for users who are not active members of it.
+func (u usecase) activeMember(ctx context.Context, walletId string, userId string) (members []entity.SharedWalletMember, member entity.SharedWalletMember, err error) {
+	members, err = u.balance.GetSharedWalletMembersByWalletId(ctx, walletId)
+	if err != nil {
+		return members, member, err
+	}
+
+	for _, m := range members {
+		if m.UserId == userId && m.IsActive() {
+			return members, m, nil
+		}
+	}
+
+	return members, member, domainbalance.ErrSharedWalletNotFound
+}
+
+// usernames resolves the usernames of user ids, remembering the ones already resolved.
+type usernames struct {
+	u     usecase
+	cache map[string]string
+}
+
+func (n usernames) get(ctx context.Context, userId string) (username string, err error) {
+	if username, ok := n.cache[userId]; ok {
+		return username, nil
+	}
+
+	user, err := n.u.auth.GetUserById(ctx, userId)
+	if err != nil {
+		return username, err
+	}
+
+	n.cache[userId] = user.Username
+	return user.Username, nil
+}
+
+func (u usecase) CreateWallet(ctx context.Context, req CreateWalletRequest) (resp CreateWalletResponse, err error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxWalletNameLength {
+		return CreateWalletResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidName
+	}
+
+	wallet := entity.SharedWallet{
+		Id:        uuid.NewString(),
+		Name:      req.Name,
+		CreatedBy: req.UserId,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err = u.balance.CreateSharedWallet(ctx, wallet)
+	if err != nil {
+		log.Errorln("CreateWallet.CreateSharedWallet", err)
+		return CreateWalletResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return CreateWalletResponse{
+		Code: http.StatusCreated,
+		Wallet: newWallet(wallet, entity.SharedWalletMember{
+			Role:   int(enum.SHARED_WALLET_ROLE_OWNER),
+			Status: int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE),
+		}),
+	}, nil
+}
+
+// ListWallets lists the wallets the user is a member of or has a pending invitation to.
+func (u usecase) ListWallets(ctx context.Context, req ListWalletsRequest) (resp ListWalletsResponse, err error) {
+	members, err := u.balance.GetSharedWalletMembersByUserId(ctx, req.UserId)
+	if err != nil {
+		log.Errorln("ListWallets.GetSharedWalletMembersByUserId", err)
+		return ListWalletsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListWalletsResponse{
+		Code: http.StatusOK,
+		Data: make([]Wallet, 0, len(members)),
+	}
+
+	for _, member := range members {
+		wallet, err := u.balance.GetSharedWalletById(ctx, member.WalletId)
+		if err != nil {
+			log.Errorln("ListWallets.GetSharedWalletById", err)
+			return ListWalletsResponse{
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
+		}
+
+		resp.Data = append(resp.Data, newWallet(wallet, member))
+	}
+
+	return resp, nil
+}
+
+// ReadWallet returns the wallet with its members and pending invitations, for its active members only.
+func (u usecase) ReadWallet(ctx context.Context, req ReadWalletRequest) (resp ReadWalletResponse, err error) {
+	members, member, err := u.activeMember(ctx, req.WalletId, req.UserId)
+	if err != nil {
+		log.Errorln("ReadWallet.activeMember", err)
+		code, err := walletError(err)
+		return ReadWalletResponse{
+			Code: code,
+		}, err
+	}
+
+	wallet, err := u.balance.GetSharedWalletById(ctx, req.WalletId)
+	if err != nil {
+		log.Errorln("ReadWallet.GetSharedWalletById", err)
+		return ReadWalletResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ReadWalletResponse{
+		Code:    http.StatusOK,
+		Wallet:  newWallet(wallet, member),
+		Members: make([]Member, 0, len(members)),
+	}
+
+	names := usernames{u: u, cache: map[string]string{}}
+	for _, m := range members {
+		if !m.IsPending() {
+			continue
+		}
+
+		username, err := names.get(ctx, m.UserId)
+		if err != nil {
+			log.Errorln("ReadWallet.GetUserById", err)
+			return ReadWalletResponse{
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
+		}
+
+		resp.Members = append(resp.Members, newMember(m, username))
+	}
+
+	return resp, nil
+}
+
+// ListWalletTransactions lists the latest movements of the wallet, newest first, for its active members only.
+func (u usecase) ListWalletTransactions(ctx context.Context, req ListWalletTransactionsRequest) (resp ListWalletTransactionsResponse, err error) {
+	_, _, err = u.activeMember(ctx, req.WalletId, req.UserId)
+	if err != nil {
+		log.Errorln("ListWalletTransactions.activeMember", err)
+		code, err := walletError(err)
+		return ListWalletTransactionsResponse{
+			Code: code,
+		}, err
+	}
+
+	histories, err := u.balance.GetSharedWalletHistoriesByWalletId(ctx, req.WalletId, maxWalletTransactions)
+	if err != nil {
+		log.Errorln("ListWalletTransactions.GetSharedWalletHistoriesByWalletId", err)
+		return ListWalletTransactionsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListWalletTransactionsResponse{
+		Code: http.StatusOK,
+		Data: make([]WalletTransaction, 0, len(histories)),
+	}
+
+	names := usernames{u: u, cache: map[string]string{}}
+	for _, history := range histories {
+		initiatedBy, err := names.get(ctx, history.InitiatedBy)
+		if err != nil {
+			log.Errorln("ListWalletTransactions.GetUserById", err)
+			return ListWalletTransactionsResponse{
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
+		}
+
+		counterparty, err := names.get(ctx, history.TargetUserId)
+		if err != nil {
+			log.Errorln("ListWalletTransactions.GetUserById", err)
+			return ListWalletTransactionsResponse{
+				Code: http.StatusBadGateway,
+			}, apperror.ErrDependency.Wrap(err)
+		}
+
+		amount := history.Amount
+		if history.Type == int(enum.DEBIT) {
+			amount = -amount
+		}
+
+		resp.Data = append(resp.Data, WalletTransaction{
+			Id:                   history.Id,
+			InitiatedBy:          initiatedBy,
+			CounterpartyUsername: counterparty,
+			Amount:               amount,
+			Notes:                history.Notes,
+			CreatedAt:            history.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+func (u usecase) InviteMember(ctx context.Context, req InviteMemberRequest) (resp InviteMemberResponse, err error) {
+	role, ok := roles[req.Role]
+	if !ok {
+		return InviteMemberResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidRole
+	}
+
+	if (role == enum.SHARED_WALLET_ROLE_SPENDER) != (req.SpendLimit > 0) || req.SpendLimit < 0 {
+		return InviteMemberResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidSpendLimit
+	}
+
+	user, err := u.auth.GetUserByUsername(ctx, req.Username)
+	if err == sql.ErrNoRows {
+		return InviteMemberResponse{
+			Code: http.StatusNotFound,
+		}, errUserNotFound
+	}
+	if err != nil {
+		log.Errorln("InviteMember.GetUserByUsername", err)
+		return InviteMemberResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	err = u.balance.InviteSharedWalletMember(ctx, domainbalance.InviteSharedWalletMemberRequest{
+		WalletId:   req.WalletId,
+		ActorId:    req.UserId,
+		UserId:     user.Id,
+		Role:       role,
+		SpendLimit: req.SpendLimit,
+	})
+	if err != nil {
+		log.Errorln("InviteMember.InviteSharedWalletMember", err)
+		code, err := walletError(err)
+		return InviteMemberResponse{
+			Code: code,
+		}, err
+	}
+
+	return InviteMemberResponse{
+		Code: http.StatusCreated,
+		Member: newMember(entity.SharedWalletMember{
+			UserId:     user.Id,
+			Role:       int(role),
+			SpendLimit: req.SpendLimit,
+			Status:     int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED),
+		}, user.Username),
+	}, nil
+}
+
+// RespondInvitation accepts or declines the pending invitation of the user to the wallet.
+func (u usecase) RespondInvitation(ctx context.Context, req RespondInvitationRequest) (resp RespondInvitationResponse, err error) {
+	err = u.balance.RespondSharedWalletInvitation(ctx, domainbalance.RespondSharedWalletInvitationRequest{
+		WalletId: req.WalletId,
+		UserId:   req.UserId,
+		Accept:   req.Accept,
+	})
+	if err != nil {
+		log.Errorln("RespondInvitation.RespondSharedWalletInvitation", err)
+		code, err := walletError(err)
+		return RespondInvitationResponse{
+			Code: code,
+		}, err
+	}
+
+	return RespondInvitationResponse{
+		Code: http.StatusNoContent,
+	}, nil
+}
+
+func (u usecase) RemoveMember(ctx context.Context, req RemoveMemberRequest) (resp RemoveMemberResponse, err error) {
+	err = u.balance.RemoveSharedWalletMember(ctx, domainbalance.RemoveSharedWalletMemberRequest{
+		WalletId: req.WalletId,
+		ActorId:  req.UserId,
+		UserId:   req.MemberId,
+	})
+	if err != nil {
+		log.Errorln("RemoveMember.RemoveSharedWalletMember", err)
+		code, err := walletError(err)
+		return RemoveMemberResponse{
+			Code: code,
+		}, err
+	}
+
+	return RemoveMemberResponse{
+		Code: http.StatusNoContent,
+	}, nil
+}
@@ -0,0 +1,1198 @@
+package usecasewallet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	createdAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	sharedWallet = entity.SharedWallet{
+		Id:        "wid",
+		Name:      "family",
+		Amount:    100 * money.Unit,
+		CreatedBy: "id",
+		CreatedAt: createdAt,
+	}
+	owner = entity.SharedWalletMember{
+		WalletId:  "wid",
+		UserId:    "id",
+		Role:      int(enum.SHARED_WALLET_ROLE_OWNER),
+		Status:    int(enum.SHARED_WALLET_MEMBER_STATUS_ACTIVE),
+		InvitedBy: "id",
+	}
+	spender = entity.SharedWalletMember{
+		WalletId:   "wid",
+		UserId:     "sid",
+		Role:       int(enum.SHARED_WALLET_ROLE_SPENDER),
+		SpendLimit: 10 * money.Unit,
+		Status:     int(enum.SHARED_WALLET_MEMBER_STATUS_INVITED),
+		InvitedBy:  "id",
+	}
+	leftViewer = entity.SharedWalletMember{
+		WalletId:  "wid",
+		UserId:    "vid",
+		Role:      int(enum.SHARED_WALLET_ROLE_VIEWER),
+		Status:    int(enum.SHARED_WALLET_MEMBER_STATUS_LEFT),
+		InvitedBy: "id",
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_usecase_CreateWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req CreateWalletRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CreateWalletResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWalletRequest{
+					UserId: "id",
+					Name:   " family ",
+				},
+			},
+			wantResp: CreateWalletResponse{
+				Code: http.StatusCreated,
+				Wallet: Wallet{
+					Name:   "family",
+					Role:   "owner",
+					Status: "active",
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CreateSharedWallet(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, wallet entity.SharedWallet) error {
+						if wallet.Name != "family" || wallet.CreatedBy != "id" {
+							t.Errorf("CreateSharedWallet() wallet = %v", wallet)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "error invalid name",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWalletRequest{
+					UserId: "id",
+					Name:   "  ",
+				},
+			},
+			wantResp: CreateWalletResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error CreateSharedWallet",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWalletRequest{
+					UserId: "id",
+					Name:   "family",
+				},
+			},
+			wantResp: CreateWalletResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().CreateSharedWallet(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.CreateWallet(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CreateWallet() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// A new wallet gets a random id and is created now, so only their presence is checked.
+			if !tt.wantErr && (gotResp.Id == "" || gotResp.CreatedAt.IsZero()) {
+				t.Errorf("usecase.CreateWallet() wallet id or creation time is empty")
+			}
+			gotResp.Id = ""
+			gotResp.CreatedAt = time.Time{}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CreateWallet() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListWallets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	invited := spender
+	invited.UserId = "id"
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListWalletsRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListWalletsResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWalletsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListWalletsResponse{
+				Code: http.StatusOK,
+				Data: []Wallet{
+					{
+						Id:         "wid",
+						Name:       "family",
+						Amount:     100 * money.Unit,
+						Role:       "spender",
+						Status:     "invited",
+						SpendLimit: 10 * money.Unit,
+						CreatedAt:  createdAt,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByUserId(gomock.Any(), "id").Return([]entity.SharedWalletMember{invited}, nil),
+					mockDomainBalance.EXPECT().GetSharedWalletById(gomock.Any(), "wid").Return(sharedWallet, nil),
+				)
+			},
+		},
+		{
+			name: "error GetSharedWalletById",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWalletsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListWalletsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByUserId(gomock.Any(), "id").Return([]entity.SharedWalletMember{owner}, nil),
+					mockDomainBalance.EXPECT().GetSharedWalletById(gomock.Any(), "wid").Return(entity.SharedWallet{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error GetSharedWalletMembersByUserId",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWalletsRequest{
+					UserId: "id",
+				},
+			},
+			wantResp: ListWalletsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByUserId(gomock.Any(), "id").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.ListWallets(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListWallets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListWallets() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ReadWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	members := []entity.SharedWalletMember{owner, spender, leftViewer}
+
+	type fields struct {
+		auth    domainauth.DomainItf
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ReadWalletRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ReadWalletResponse
+		wantErr  error
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ReadWalletResponse{
+				Code: http.StatusOK,
+				Wallet: Wallet{
+					Id:        "wid",
+					Name:      "family",
+					Amount:    100 * money.Unit,
+					Role:      "owner",
+					Status:    "active",
+					CreatedAt: createdAt,
+				},
+				Members: []Member{
+					{
+						UserId:   "id",
+						Username: "username",
+						Role:     "owner",
+						Status:   "active",
+					},
+					{
+						UserId:     "sid",
+						Username:   "spenderusername",
+						Role:       "spender",
+						Status:     "invited",
+						SpendLimit: 10 * money.Unit,
+					},
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return(members, nil),
+					mockDomainBalance.EXPECT().GetSharedWalletById(gomock.Any(), "wid").Return(sharedWallet, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "sid").Return(entity.User{Id: "sid", Username: "spenderusername"}, nil),
+				)
+			},
+		},
+		{
+			name: "error not an active member",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadWalletRequest{
+					UserId:   "sid",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ReadWalletResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: errWalletNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return(members, nil),
+				)
+			},
+		},
+		{
+			name: "error GetUserById",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ReadWalletResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return(members, nil),
+					mockDomainBalance.EXPECT().GetSharedWalletById(gomock.Any(), "wid").Return(sharedWallet, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error GetSharedWalletById",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ReadWalletResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return(members, nil),
+					mockDomainBalance.EXPECT().GetSharedWalletById(gomock.Any(), "wid").Return(entity.SharedWallet{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error GetSharedWalletMembersByWalletId",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ReadWalletRequest{
+					UserId:   "id",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ReadWalletResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:    tt.fields.auth,
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.ReadWallet(tt.args.ctx, tt.args.req)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("usecase.ReadWallet() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp.Code != http.StatusBadGateway && !errors.Is(err, tt.wantErr) {
+				t.Errorf("usecase.ReadWallet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ReadWallet() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListWalletTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	histories := []entity.SharedWalletHistory{
+		{
+			Id:           "h2",
+			WalletId:     "wid",
+			InitiatedBy:  "id",
+			TargetUserId: "tid",
+			Amount:       5 * money.Unit,
+			Type:         int(enum.DEBIT),
+			Notes:        "Transfer money to recipient",
+			CreatedAt:    createdAt,
+		},
+		{
+			Id:           "h1",
+			WalletId:     "wid",
+			InitiatedBy:  "id",
+			TargetUserId: "id",
+			Amount:       10 * money.Unit,
+			Type:         int(enum.CREDIT),
+			Notes:        "Deposit money from member",
+			CreatedAt:    createdAt,
+		},
+	}
+
+	type fields struct {
+		auth    domainauth.DomainItf
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListWalletTransactionsRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListWalletTransactionsResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWalletTransactionsRequest{
+					UserId:   "id",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ListWalletTransactionsResponse{
+				Code: http.StatusOK,
+				Data: []WalletTransaction{
+					{
+						Id:                   "h2",
+						InitiatedBy:          "username",
+						CounterpartyUsername: "tousername",
+						Amount:               -5 * money.Unit,
+						Notes:                "Transfer money to recipient",
+						CreatedAt:            createdAt,
+					},
+					{
+						Id:                   "h1",
+						InitiatedBy:          "username",
+						CounterpartyUsername: "username",
+						Amount:               10 * money.Unit,
+						Notes:                "Deposit money from member",
+						CreatedAt:            createdAt,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return([]entity.SharedWalletMember{owner}, nil),
+					mockDomainBalance.EXPECT().GetSharedWalletHistoriesByWalletId(gomock.Any(), "wid", maxWalletTransactions).Return(histories, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "tid").Return(entity.User{Id: "tid", Username: "tousername"}, nil),
+				)
+			},
+		},
+		{
+			name: "error GetUserById",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWalletTransactionsRequest{
+					UserId:   "id",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ListWalletTransactionsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return([]entity.SharedWalletMember{owner}, nil),
+					mockDomainBalance.EXPECT().GetSharedWalletHistoriesByWalletId(gomock.Any(), "wid", maxWalletTransactions).Return(histories, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "id").Return(entity.User{Id: "id", Username: "username"}, nil),
+					mockDomainAuth.EXPECT().GetUserById(gomock.Any(), "tid").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error GetSharedWalletHistoriesByWalletId",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWalletTransactionsRequest{
+					UserId:   "id",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ListWalletTransactionsResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return([]entity.SharedWalletMember{owner}, nil),
+					mockDomainBalance.EXPECT().GetSharedWalletHistoriesByWalletId(gomock.Any(), "wid", maxWalletTransactions).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error not a member",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWalletTransactionsRequest{
+					UserId:   "vid",
+					WalletId: "wid",
+				},
+			},
+			wantResp: ListWalletTransactionsResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().GetSharedWalletMembersByWalletId(gomock.Any(), "wid").Return([]entity.SharedWalletMember{owner, leftViewer}, nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:    tt.fields.auth,
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.ListWalletTransactions(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListWalletTransactions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListWalletTransactions() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_InviteMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainAuth := domainauth.NewMockDomainItf(ctrl)
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	invitee := entity.User{
+		Id:       "sid",
+		Username: "spenderusername",
+	}
+
+	type fields struct {
+		auth    domainauth.DomainItf
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req InviteMemberRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp InviteMemberResponse
+		wantErr  error
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:     "id",
+					WalletId:   "wid",
+					Username:   "spenderusername",
+					Role:       "spender",
+					SpendLimit: 10 * money.Unit,
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusCreated,
+				Member: Member{
+					UserId:     "sid",
+					Username:   "spenderusername",
+					Role:       "spender",
+					Status:     "invited",
+					SpendLimit: 10 * money.Unit,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "spenderusername").Return(invitee, nil),
+					mockDomainBalance.EXPECT().InviteSharedWalletMember(gomock.Any(), domainbalance.InviteSharedWalletMemberRequest{
+						WalletId:   "wid",
+						ActorId:    "id",
+						UserId:     "sid",
+						Role:       enum.SHARED_WALLET_ROLE_SPENDER,
+						SpendLimit: 10 * money.Unit,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error invalid role",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Username: "spenderusername",
+					Role:     "admin",
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: errInvalidRole,
+			mock:    func() {},
+		},
+		{
+			name: "error spender without spend limit",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Username: "spenderusername",
+					Role:     "spender",
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: errInvalidSpendLimit,
+			mock:    func() {},
+		},
+		{
+			name: "error viewer with spend limit",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:     "id",
+					WalletId:   "wid",
+					Username:   "spenderusername",
+					Role:       "viewer",
+					SpendLimit: 10 * money.Unit,
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: errInvalidSpendLimit,
+			mock:    func() {},
+		},
+		{
+			name: "error user not found",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Username: "spenderusername",
+					Role:     "viewer",
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: errUserNotFound,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "spenderusername").Return(entity.User{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error GetUserByUsername",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Username: "spenderusername",
+					Role:     "viewer",
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "spenderusername").Return(entity.User{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error member exists",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Username: "spenderusername",
+					Role:     "viewer",
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: errMemberExists,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "spenderusername").Return(invitee, nil),
+					mockDomainBalance.EXPECT().InviteSharedWalletMember(gomock.Any(), gomock.Any()).Return(domainbalance.ErrSharedWalletMemberExists),
+				)
+			},
+		},
+		{
+			name: "error not an owner",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Username: "spenderusername",
+					Role:     "viewer",
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusForbidden,
+			},
+			wantErr: errWalletForbidden,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "spenderusername").Return(invitee, nil),
+					mockDomainBalance.EXPECT().InviteSharedWalletMember(gomock.Any(), gomock.Any()).Return(domainbalance.ErrSharedWalletForbidden),
+				)
+			},
+		},
+		{
+			name: "error InviteSharedWalletMember",
+			fields: fields{
+				auth:    mockDomainAuth,
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: InviteMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					Username: "spenderusername",
+					Role:     "owner",
+				},
+			},
+			wantResp: InviteMemberResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: fmt.Errorf("foo"),
+			mock: func() {
+				gomock.InOrder(
+					mockDomainAuth.EXPECT().GetUserByUsername(gomock.Any(), "spenderusername").Return(invitee, nil),
+					mockDomainBalance.EXPECT().InviteSharedWalletMember(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				auth:    tt.fields.auth,
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.InviteMember(tt.args.ctx, tt.args.req)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("usecase.InviteMember() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp.Code != http.StatusBadGateway && !errors.Is(err, tt.wantErr) {
+				t.Errorf("usecase.InviteMember() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.InviteMember() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_RespondInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req RespondInvitationRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp RespondInvitationResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success accept",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RespondInvitationRequest{
+					UserId:   "sid",
+					WalletId: "wid",
+					Accept:   true,
+				},
+			},
+			wantResp: RespondInvitationResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().RespondSharedWalletInvitation(gomock.Any(), domainbalance.RespondSharedWalletInvitationRequest{
+						WalletId: "wid",
+						UserId:   "sid",
+						Accept:   true,
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error invitation not found",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RespondInvitationRequest{
+					UserId:   "sid",
+					WalletId: "wid",
+				},
+			},
+			wantResp: RespondInvitationResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().RespondSharedWalletInvitation(gomock.Any(), domainbalance.RespondSharedWalletInvitationRequest{
+						WalletId: "wid",
+						UserId:   "sid",
+					}).Return(domainbalance.ErrSharedWalletInvitationNotFound),
+				)
+			},
+		},
+		{
+			name: "error RespondSharedWalletInvitation",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RespondInvitationRequest{
+					UserId:   "sid",
+					WalletId: "wid",
+				},
+			},
+			wantResp: RespondInvitationResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().RespondSharedWalletInvitation(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.RespondInvitation(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.RespondInvitation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.RespondInvitation() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_RemoveMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainBalance := domainbalance.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		balance domainbalance.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req RemoveMemberRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp RemoveMemberResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					MemberId: "sid",
+				},
+			},
+			wantResp: RemoveMemberResponse{
+				Code: http.StatusNoContent,
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().RemoveSharedWalletMember(gomock.Any(), domainbalance.RemoveSharedWalletMemberRequest{
+						WalletId: "wid",
+						ActorId:  "id",
+						UserId:   "sid",
+					}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "error last owner",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					MemberId: "id",
+				},
+			},
+			wantResp: RemoveMemberResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().RemoveSharedWalletMember(gomock.Any(), gomock.Any()).Return(domainbalance.ErrSharedWalletLastOwner),
+				)
+			},
+		},
+		{
+			name: "error member not found",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					MemberId: "vid",
+				},
+			},
+			wantResp: RemoveMemberResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().RemoveSharedWalletMember(gomock.Any(), gomock.Any()).Return(domainbalance.ErrSharedWalletMemberNotFound),
+				)
+			},
+		},
+		{
+			name: "error RemoveSharedWalletMember",
+			fields: fields{
+				balance: mockDomainBalance,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RemoveMemberRequest{
+					UserId:   "id",
+					WalletId: "wid",
+					MemberId: "sid",
+				},
+			},
+			wantResp: RemoveMemberResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainBalance.EXPECT().RemoveSharedWalletMember(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				balance: tt.fields.balance,
+			}
+			tt.mock()
+			gotResp, err := u.RemoveMember(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.RemoveMember() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.RemoveMember() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package usecasewallet
+
+import "context"
+
+type UsecaseItf interface {
+	CreateWallet(ctx context.Context, req CreateWalletRequest) (resp CreateWalletResponse, err error)
+	ListWallets(ctx context.Context, req ListWalletsRequest) (resp ListWalletsResponse, err error)
+	ReadWallet(ctx context.Context, req ReadWalletRequest) (resp ReadWalletResponse, err error)
+	ListWalletTransactions(ctx context.Context, req ListWalletTransactionsRequest) (resp ListWalletTransactionsResponse, err error)
+
+	InviteMember(ctx context.Context, req InviteMemberRequest) (resp InviteMemberResponse, err error)
+	RespondInvitation(ctx context.Context, req RespondInvitationRequest) (resp RespondInvitationResponse, err error)
+	RemoveMember(ctx context.Context, req RemoveMemberRequest) (resp RemoveMemberResponse, err error)
+}
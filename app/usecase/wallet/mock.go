@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/usecase/wallet/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/usecase/wallet/interfaces.go -destination=app/usecase/wallet/mock.go -package=usecasewallet
+//
+
+// Package usecasewallet is a generated GoMock package.
+package usecasewallet
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsecaseItf is a mock of UsecaseItf interface.
+type MockUsecaseItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseItfMockRecorder
+}
+
+// MockUsecaseItfMockRecorder is the mock recorder for MockUsecaseItf.
+type MockUsecaseItfMockRecorder struct {
+	mock *MockUsecaseItf
+}
+
+// NewMockUsecaseItf creates a new mock instance.
+func NewMockUsecaseItf(ctrl *gomock.Controller) *MockUsecaseItf {
+	mock := &MockUsecaseItf{ctrl: ctrl}
+	mock.recorder = &MockUsecaseItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
+	return m.recorder
+}
+
+// CreateWallet mocks base method.
+func (m *MockUsecaseItf) CreateWallet(ctx context.Context, req CreateWalletRequest) (CreateWalletResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWallet", ctx, req)
+	ret0, _ := ret[0].(CreateWalletResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWallet indicates an expected call of CreateWallet.
+func (mr *MockUsecaseItfMockRecorder) CreateWallet(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockUsecaseItf)(nil).CreateWallet), ctx, req)
+}
+
+// InviteMember mocks base method.
+func (m *MockUsecaseItf) InviteMember(ctx context.Context, req InviteMemberRequest) (InviteMemberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteMember", ctx, req)
+	ret0, _ := ret[0].(InviteMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InviteMember indicates an expected call of InviteMember.
+func (mr *MockUsecaseItfMockRecorder) InviteMember(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteMember", reflect.TypeOf((*MockUsecaseItf)(nil).InviteMember), ctx, req)
+}
+
+// ListWalletTransactions mocks base method.
+func (m *MockUsecaseItf) ListWalletTransactions(ctx context.Context, req ListWalletTransactionsRequest) (ListWalletTransactionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletTransactions", ctx, req)
+	ret0, _ := ret[0].(ListWalletTransactionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWalletTransactions indicates an expected call of ListWalletTransactions.
+func (mr *MockUsecaseItfMockRecorder) ListWalletTransactions(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletTransactions", reflect.TypeOf((*MockUsecaseItf)(nil).ListWalletTransactions), ctx, req)
+}
+
+// ListWallets mocks base method.
+func (m *MockUsecaseItf) ListWallets(ctx context.Context, req ListWalletsRequest) (ListWalletsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWallets", ctx, req)
+	ret0, _ := ret[0].(ListWalletsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWallets indicates an expected call of ListWallets.
+func (mr *MockUsecaseItfMockRecorder) ListWallets(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWallets", reflect.TypeOf((*MockUsecaseItf)(nil).ListWallets), ctx, req)
+}
+
+// ReadWallet mocks base method.
+func (m *MockUsecaseItf) ReadWallet(ctx context.Context, req ReadWalletRequest) (ReadWalletResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadWallet", ctx, req)
+	ret0, _ := ret[0].(ReadWalletResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadWallet indicates an expected call of ReadWallet.
+func (mr *MockUsecaseItfMockRecorder) ReadWallet(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadWallet", reflect.TypeOf((*MockUsecaseItf)(nil).ReadWallet), ctx, req)
+}
+
+// RemoveMember mocks base method.
+func (m *MockUsecaseItf) RemoveMember(ctx context.Context, req RemoveMemberRequest) (RemoveMemberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, req)
+	ret0, _ := ret[0].(RemoveMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockUsecaseItfMockRecorder) RemoveMember(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockUsecaseItf)(nil).RemoveMember), ctx, req)
+}
+
+// RespondInvitation mocks base method.
+func (m *MockUsecaseItf) RespondInvitation(ctx context.Context, req RespondInvitationRequest) (RespondInvitationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondInvitation", ctx, req)
+	ret0, _ := ret[0].(RespondInvitationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondInvitation indicates an expected call of RespondInvitation.
+func (mr *MockUsecaseItfMockRecorder) RespondInvitation(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondInvitation", reflect.TypeOf((*MockUsecaseItf)(nil).RespondInvitation), ctx, req)
+}
@@ -0,0 +1,114 @@
+package usecasewallet
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+type CreateWalletRequest struct {
+	UserId string
+	Name   string `json:"name"`
+}
+
+type CreateWalletResponse struct {
+	Code int `json:"-"`
+	Wallet
+}
+
+type ListWalletsRequest struct {
+	UserId string
+}
+
+type ListWalletsResponse struct {
+	Code int      `json:"-"`
+	Data []Wallet `json:"data"`
+}
+
+type ReadWalletRequest struct {
+	UserId   string
+	WalletId string
+}
+
+type ReadWalletResponse struct {
+	Code int `json:"-"`
+	Wallet
+	Members []Member `json:"members"`
+}
+
+type ListWalletTransactionsRequest struct {
+	UserId   string
+	WalletId string
+}
+
+type ListWalletTransactionsResponse struct {
+	Code int                 `json:"-"`
+	Data []WalletTransaction `json:"data"`
+}
+
+// InviteMemberRequest invites Username to the wallet with Role. SpendLimit is required for a spender and must be
+// omitted for the other roles.
+type InviteMemberRequest struct {
+	UserId     string
+	WalletId   string
+	Username   string      `json:"username"`
+	Role       string      `json:"role"`
+	SpendLimit money.Money `json:"spend_limit"`
+}
+
+type InviteMemberResponse struct {
+	Code int `json:"-"`
+	Member
+}
+
+type RespondInvitationRequest struct {
+	UserId   string
+	WalletId string
+	Accept   bool
+}
+
+type RespondInvitationResponse struct {
+	Code int `json:"-"`
+}
+
+// RemoveMemberRequest removes MemberId from the wallet on behalf of UserId, which is leaving it when both are the
+// same user.
+type RemoveMemberRequest struct {
+	UserId   string
+	WalletId string
+	MemberId string
+}
+
+type RemoveMemberResponse struct {
+	Code int `json:"-"`
+}
+
+// Wallet is a shared wallet as seen by one of its members, with the role and status of that member.
+type Wallet struct {
+	Id         string      `json:"id"`
+	Name       string      `json:"name"`
+	Amount     money.Money `json:"amount"`
+	Role       string      `json:"role"`
+	Status     string      `json:"status"`
+	SpendLimit money.Money `json:"spend_limit,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type Member struct {
+	UserId     string      `json:"user_id"`
+	Username   string      `json:"username"`
+	Role       string      `json:"role"`
+	Status     string      `json:"status"`
+	SpendLimit money.Money `json:"spend_limit,omitempty"`
+}
+
+// WalletTransaction is a movement of the money of the wallet. InitiatedBy is the member who moved it, and
+// CounterpartyUsername the recipient of a transfer or the member who deposited.
+type WalletTransaction struct {
+	Id                   string      `json:"id"`
+	InitiatedBy          string      `json:"initiated_by"`
+	CounterpartyUsername string      `json:"counterparty_username"`
+	Amount               money.Money `json:"amount"`
+	Notes                string      `json:"notes"`
+	CreatedAt            time.Time   `json:"created_at"`
+}
@@ -0,0 +1,39 @@
+package usecasewallet
+
+import (
+	"reflect"
+	"testing"
+
+	domainauth "github.com/kevinsudut/wallet-system/app/domain/auth"
+	domainbalance "github.com/kevinsudut/wallet-system/app/domain/balance"
+)
+
+func TestInit(t *testing.T) {
+	type args struct {
+		auth    domainauth.DomainItf
+		balance domainbalance.DomainItf
+	}
+	tests := []struct {
+		name string
+		args args
+		want UsecaseItf
+	}{
+		{
+			args: args{
+				auth:    nil,
+				balance: nil,
+			},
+			want: &usecase{
+				auth:    nil,
+				balance: nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Init(tt.args.auth, tt.args.balance); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Init() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package usecasewebhook
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	domainwebhook "github.com/kevinsudut/wallet-system/app/domain/webhook"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
+	"github.com/kevinsudut/wallet-system/pkg/helper/publicip"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+var (
+	errInvalidUrl              = apperror.New(http.StatusBadRequest, "invalid_url", "url must be an absolute http or https URL of at most 2048 characters on a public host")
+	errInvalidEventTypes       = apperror.New(http.StatusBadRequest, "invalid_event_types", "event_types must list at least one of BalanceCredited, BalanceDebited or TransferCompleted")
+	errTooManyWebhookEndpoints = apperror.New(http.StatusConflict, "too_many_webhook_endpoints", "a user can have at most 10 webhook endpoints")
+	errWebhookEndpointNotFound = apperror.New(http.StatusNotFound, "webhook_endpoint_not_found", "webhook endpoint does not exist")
+	errWebhookDeliveryNotFound = apperror.New(http.StatusNotFound, "webhook_delivery_not_found", "webhook delivery does not exist")
+	errWebhookDeliveryPending  = apperror.New(http.StatusConflict, "webhook_delivery_pending", "webhook delivery is still being attempted")
+)
+
+// webhookEventTypes are the events an endpoint can subscribe to.
+var webhookEventTypes = map[string]bool{
+	string(enum.EVENT_BALANCE_CREDITED):   true,
+	string(enum.EVENT_BALANCE_DEBITED):    true,
+	string(enum.EVENT_TRANSFER_COMPLETED): true,
+}
+
+func newWebhookEndpoint(endpoint entity.WebhookEndpoint) WebhookEndpoint {
+	return WebhookEndpoint{
+		Id:         endpoint.Id,
+		Url:        endpoint.Url,
+		EventTypes: endpoint.EventTypeList(),
+		Status:     enum.WebhookEndpointStatus(endpoint.Status).String(),
+		CreatedAt:  endpoint.CreatedAt,
+	}
+}
+
+func newWebhookDelivery(delivery entity.WebhookDelivery) WebhookDelivery {
+	resp := WebhookDelivery{
+		Id:             delivery.Id,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		Status:         enum.WebhookDeliveryStatus(delivery.Status).String(),
+		Attempt:        delivery.Attempt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	if delivery.Status == int(enum.WEBHOOK_DELIVERY_STATUS_PENDING) {
+		nextAttemptAt := delivery.NextAttemptAt
+		resp.NextAttemptAt = &nextAttemptAt
+	}
+
+	return resp
+}
+
+// validWebhookUrl rejects hosts that are addresses of this host or its internal networks. A host name is only
+// checked once it is resolved, when a delivery connects to it.
+func validWebhookUrl(rawUrl string) bool {
+	if len(rawUrl) > maxWebhookUrlLength {
+		return false
+	}
+
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host != ""
+	}
+
+	return publicip.IsPublic(addr)
+}
+
+// webhookEventTypeList validates the event types of a request and joins them for the endpoint, without duplicates.
+func webhookEventTypeList(eventTypes []string) (string, bool) {
+	seen := make(map[string]bool, len(eventTypes))
+	list := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !webhookEventTypes[eventType] {
+			return "", false
+		}
+		if seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		list = append(list, eventType)
+	}
+
+	return strings.Join(list, ","), len(list) > 0
+}
+
+// newWebhookSecret generates the secret an endpoint verifies the signature of its deliveries with.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// CreateWebhookEndpoint registers an endpoint of the user for the event types of the request. The events of the
+// user from then on are delivered to it.
+func (u usecase) CreateWebhookEndpoint(ctx context.Context, req CreateWebhookEndpointRequest) (resp CreateWebhookEndpointResponse, err error) {
+	if !validWebhookUrl(req.Url) {
+		return CreateWebhookEndpointResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidUrl
+	}
+
+	eventTypes, ok := webhookEventTypeList(req.EventTypes)
+	if !ok {
+		return CreateWebhookEndpointResponse{
+			Code: http.StatusBadRequest,
+		}, errInvalidEventTypes
+	}
+
+	endpoints, err := u.webhook.GetWebhookEndpointsByUserId(ctx, req.UserId, maxWebhookEndpoints)
+	if err != nil {
+		log.Errorln("CreateWebhookEndpoint.GetWebhookEndpointsByUserId", err)
+		return CreateWebhookEndpointResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	if len(endpoints) >= maxWebhookEndpoints {
+		return CreateWebhookEndpointResponse{
+			Code: http.StatusConflict,
+		}, errTooManyWebhookEndpoints
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		log.Errorln("CreateWebhookEndpoint.newWebhookSecret", err)
+		return CreateWebhookEndpointResponse{
+			Code: http.StatusInternalServerError,
+		}, apperror.ErrInternal.Wrap(err)
+	}
+
+	endpoint := entity.WebhookEndpoint{
+		Id:         uuid.NewString(),
+		UserId:     req.UserId,
+		Url:        req.Url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Status:     int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE),
+	}
+
+	err = u.webhook.InsertWebhookEndpoint(ctx, endpoint)
+	if err != nil {
+		log.Errorln("CreateWebhookEndpoint.InsertWebhookEndpoint", err)
+		return CreateWebhookEndpointResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	return CreateWebhookEndpointResponse{
+		Code:            http.StatusCreated,
+		WebhookEndpoint: newWebhookEndpoint(endpoint),
+		Secret:          secret,
+	}, nil
+}
+
+func (u usecase) ListWebhookEndpoints(ctx context.Context, req ListWebhookEndpointsRequest) (resp ListWebhookEndpointsResponse, err error) {
+	endpoints, err := u.webhook.GetWebhookEndpointsByUserId(ctx, req.UserId, maxWebhookEndpoints)
+	if err != nil {
+		log.Errorln("ListWebhookEndpoints.GetWebhookEndpointsByUserId", err)
+		return ListWebhookEndpointsResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListWebhookEndpointsResponse{
+		Code: http.StatusOK,
+		Data: make([]WebhookEndpoint, len(endpoints)),
+	}
+
+	for idx, endpoint := range endpoints {
+		resp.Data[idx] = newWebhookEndpoint(endpoint)
+	}
+
+	return resp, nil
+}
+
+// getWebhookEndpointForUser reads an active endpoint of userId, anyone else is told it does not exist.
+func (u usecase) getWebhookEndpointForUser(ctx context.Context, id string, userId string) (resp entity.WebhookEndpoint, code int, err error) {
+	endpoint, err := u.webhook.GetWebhookEndpointById(ctx, id)
+	if err == sql.ErrNoRows || (err == nil && (endpoint.UserId != userId || endpoint.Status != int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE))) {
+		return resp, http.StatusNotFound, errWebhookEndpointNotFound
+	}
+	if err != nil {
+		log.Errorln("getWebhookEndpointForUser.GetWebhookEndpointById", err)
+		return resp, http.StatusBadGateway, apperror.ErrDependency.Wrap(err)
+	}
+
+	return endpoint, http.StatusOK, nil
+}
+
+// DeleteWebhookEndpoint stops delivering events to an endpoint, its pending deliveries are not attempted again.
+func (u usecase) DeleteWebhookEndpoint(ctx context.Context, req DeleteWebhookEndpointRequest) (resp DeleteWebhookEndpointResponse, err error) {
+	endpoint, code, err := u.getWebhookEndpointForUser(ctx, req.WebhookEndpointId, req.UserId)
+	if err != nil {
+		return DeleteWebhookEndpointResponse{
+			Code: code,
+		}, err
+	}
+
+	err = u.webhook.DeleteWebhookEndpoint(ctx, endpoint.Id, req.UserId)
+	if errors.Is(err, domainwebhook.ErrWebhookEndpointNotActive) {
+		return DeleteWebhookEndpointResponse{
+			Code: http.StatusNotFound,
+		}, errWebhookEndpointNotFound
+	}
+	if err != nil {
+		log.Errorln("DeleteWebhookEndpoint.DeleteWebhookEndpoint", err)
+		return DeleteWebhookEndpointResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	endpoint.Status = int(enum.WEBHOOK_ENDPOINT_STATUS_DELETED)
+
+	return DeleteWebhookEndpointResponse{
+		Code:            http.StatusOK,
+		WebhookEndpoint: newWebhookEndpoint(endpoint),
+	}, nil
+}
+
+// ListWebhookDeliveries is the delivery log of an endpoint, newest first.
+func (u usecase) ListWebhookDeliveries(ctx context.Context, req ListWebhookDeliveriesRequest) (resp ListWebhookDeliveriesResponse, err error) {
+	_, code, err := u.getWebhookEndpointForUser(ctx, req.WebhookEndpointId, req.UserId)
+	if err != nil {
+		return ListWebhookDeliveriesResponse{
+			Code: code,
+		}, err
+	}
+
+	deliveries, err := u.webhook.GetWebhookDeliveriesByEndpointId(ctx, req.WebhookEndpointId, maxListWebhookDeliveriesLimit)
+	if err != nil {
+		log.Errorln("ListWebhookDeliveries.GetWebhookDeliveriesByEndpointId", err)
+		return ListWebhookDeliveriesResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	resp = ListWebhookDeliveriesResponse{
+		Code: http.StatusOK,
+		Data: make([]WebhookDelivery, len(deliveries)),
+	}
+
+	for idx, delivery := range deliveries {
+		resp.Data[idx] = newWebhookDelivery(delivery)
+	}
+
+	return resp, nil
+}
+
+// RedeliverWebhookDelivery attempts a delivered or dead delivery again right away, with the full retry schedule.
+// The endpoint receives the same event id, so one that processed the event already can tell.
+func (u usecase) RedeliverWebhookDelivery(ctx context.Context, req RedeliverWebhookDeliveryRequest) (resp RedeliverWebhookDeliveryResponse, err error) {
+	endpoint, code, err := u.getWebhookEndpointForUser(ctx, req.WebhookEndpointId, req.UserId)
+	if err != nil {
+		return RedeliverWebhookDeliveryResponse{
+			Code: code,
+		}, err
+	}
+
+	delivery, err := u.webhook.GetWebhookDeliveryById(ctx, req.WebhookDeliveryId)
+	if err == sql.ErrNoRows || (err == nil && delivery.EndpointId != endpoint.Id) {
+		return RedeliverWebhookDeliveryResponse{
+			Code: http.StatusNotFound,
+		}, errWebhookDeliveryNotFound
+	}
+	if err != nil {
+		log.Errorln("RedeliverWebhookDelivery.GetWebhookDeliveryById", err)
+		return RedeliverWebhookDeliveryResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	err = u.webhook.RedeliverWebhookDelivery(ctx, delivery.Id, endpoint.Id)
+	if errors.Is(err, domainwebhook.ErrWebhookDeliveryPending) {
+		return RedeliverWebhookDeliveryResponse{
+			Code: http.StatusConflict,
+		}, errWebhookDeliveryPending
+	}
+	if err != nil {
+		log.Errorln("RedeliverWebhookDelivery.RedeliverWebhookDelivery", err)
+		return RedeliverWebhookDeliveryResponse{
+			Code: http.StatusBadGateway,
+		}, apperror.ErrDependency.Wrap(err)
+	}
+
+	delivery.Status = int(enum.WEBHOOK_DELIVERY_STATUS_PENDING)
+	delivery.Attempt = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+
+	return RedeliverWebhookDeliveryResponse{
+		Code:            http.StatusOK,
+		WebhookDelivery: newWebhookDelivery(delivery),
+	}, nil
+}
@@ -0,0 +1,1274 @@
+package usecasewebhook
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+	domainwebhook "github.com/kevinsudut/wallet-system/app/domain/webhook"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	gomock "go.uber.org/mock/gomock"
+)
+
+var (
+	createdAt = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	endpoint = entity.WebhookEndpoint{
+		Id:         "wid",
+		UserId:     "id",
+		Url:        "https://example.com/webhooks",
+		Secret:     "whsec_secret",
+		EventTypes: "BalanceCredited,TransferCompleted",
+		Status:     int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE),
+		CreatedAt:  createdAt,
+	}
+
+	delivery = entity.WebhookDelivery{
+		Id:            "did",
+		EndpointId:    "wid",
+		EventId:       "eid",
+		EventType:     "BalanceCredited",
+		Payload:       `{"id":"eid"}`,
+		Status:        int(enum.WEBHOOK_DELIVERY_STATUS_PENDING),
+		NextAttemptAt: createdAt,
+		CreatedAt:     createdAt,
+	}
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_usecase_CreateWebhookEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainWebhook := domainwebhook.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		webhook domainwebhook.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req CreateWebhookEndpointRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp CreateWebhookEndpointResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "https://example.com/webhooks",
+					EventTypes: []string{"BalanceCredited", "TransferCompleted", "BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusCreated,
+				WebhookEndpoint: WebhookEndpoint{
+					Url:        "https://example.com/webhooks",
+					EventTypes: []string{"BalanceCredited", "TransferCompleted"},
+					Status:     "active",
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointsByUserId(gomock.Any(), "id", maxWebhookEndpoints).Return([]entity.WebhookEndpoint{endpoint}, nil),
+					mockDomainWebhook.EXPECT().InsertWebhookEndpoint(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, we entity.WebhookEndpoint) error {
+						if we.Id == "" || we.UserId != "id" || we.EventTypes != "BalanceCredited,TransferCompleted" || we.Status != int(enum.WEBHOOK_ENDPOINT_STATUS_ACTIVE) || !strings.HasPrefix(we.Secret, "whsec_") {
+							return fmt.Errorf("unexpected webhook endpoint %+v", we)
+						}
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name: "error invalid url",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "ftp://example.com/webhooks",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error relative url",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "/webhooks",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error loopback url",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "http://127.0.0.1:8000/webhooks",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error metadata url",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "http://169.254.169.254/latest/meta-data",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error private ipv6 url",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "https://[fd00::1]/webhooks",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error localhost url",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "http://localhost/webhooks",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error unknown event type",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "https://example.com/webhooks",
+					EventTypes: []string{"UserRegistered"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error no event types",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId: "id",
+					Url:    "https://example.com/webhooks",
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadRequest,
+			},
+			wantErr: true,
+			mock:    func() {},
+		},
+		{
+			name: "error too many endpoints",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "https://example.com/webhooks",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointsByUserId(gomock.Any(), "id", maxWebhookEndpoints).Return(make([]entity.WebhookEndpoint, maxWebhookEndpoints), nil),
+				)
+			},
+		},
+		{
+			name: "error webhook.GetWebhookEndpointsByUserId",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "https://example.com/webhooks",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointsByUserId(gomock.Any(), "id", maxWebhookEndpoints).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error webhook.InsertWebhookEndpoint",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: CreateWebhookEndpointRequest{
+					UserId:     "id",
+					Url:        "https://example.com/webhooks",
+					EventTypes: []string{"BalanceCredited"},
+				},
+			},
+			wantResp: CreateWebhookEndpointResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointsByUserId(gomock.Any(), "id", maxWebhookEndpoints).Return(nil, nil),
+					mockDomainWebhook.EXPECT().InsertWebhookEndpoint(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				webhook: tt.fields.webhook,
+			}
+			tt.mock()
+			gotResp, err := u.CreateWebhookEndpoint(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.CreateWebhookEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// A new endpoint gets a random id and secret, so only their presence is checked.
+			if !tt.wantErr && (gotResp.Id == "" || !strings.HasPrefix(gotResp.Secret, "whsec_")) {
+				t.Errorf("usecase.CreateWebhookEndpoint() id or secret is empty")
+			}
+			gotResp.Id = ""
+			gotResp.Secret = ""
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.CreateWebhookEndpoint() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_DeleteWebhookEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainWebhook := domainwebhook.NewMockDomainItf(ctrl)
+
+	type fields struct {
+		webhook domainwebhook.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req DeleteWebhookEndpointRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp DeleteWebhookEndpointResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DeleteWebhookEndpointRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: DeleteWebhookEndpointResponse{
+				Code: http.StatusOK,
+				WebhookEndpoint: WebhookEndpoint{
+					Id:         "wid",
+					Url:        "https://example.com/webhooks",
+					EventTypes: []string{"BalanceCredited", "TransferCompleted"},
+					Status:     "deleted",
+					CreatedAt:  createdAt,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().DeleteWebhookEndpoint(gomock.Any(), "wid", "id").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error endpoint of another user",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DeleteWebhookEndpointRequest{
+					UserId:            "otherid",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: DeleteWebhookEndpointResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+				)
+			},
+		},
+		{
+			name: "error endpoint not found",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DeleteWebhookEndpointRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: DeleteWebhookEndpointResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(entity.WebhookEndpoint{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error deleted concurrently",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DeleteWebhookEndpointRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: DeleteWebhookEndpointResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().DeleteWebhookEndpoint(gomock.Any(), "wid", "id").Return(domainwebhook.ErrWebhookEndpointNotActive),
+				)
+			},
+		},
+		{
+			name: "error webhook.DeleteWebhookEndpoint",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: DeleteWebhookEndpointRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: DeleteWebhookEndpointResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().DeleteWebhookEndpoint(gomock.Any(), "wid", "id").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				webhook: tt.fields.webhook,
+			}
+			tt.mock()
+			gotResp, err := u.DeleteWebhookEndpoint(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.DeleteWebhookEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.DeleteWebhookEndpoint() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_ListWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainWebhook := domainwebhook.NewMockDomainItf(ctrl)
+
+	deliveredAt := createdAt.Add(time.Second)
+	delivered := delivery
+	delivered.Id = "did2"
+	delivered.Status = int(enum.WEBHOOK_DELIVERY_STATUS_DELIVERED)
+	delivered.Attempt = 2
+	delivered.LastStatusCode = http.StatusOK
+	delivered.DeliveredAt = &deliveredAt
+
+	type fields struct {
+		webhook domainwebhook.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req ListWebhookDeliveriesRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp ListWebhookDeliveriesResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWebhookDeliveriesRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: ListWebhookDeliveriesResponse{
+				Code: http.StatusOK,
+				Data: []WebhookDelivery{
+					{
+						Id:            "did",
+						EventId:       "eid",
+						EventType:     "BalanceCredited",
+						Status:        "pending",
+						NextAttemptAt: &createdAt,
+						CreatedAt:     createdAt,
+					},
+					{
+						Id:             "did2",
+						EventId:        "eid",
+						EventType:      "BalanceCredited",
+						Status:         "delivered",
+						Attempt:        2,
+						LastStatusCode: http.StatusOK,
+						DeliveredAt:    &deliveredAt,
+						CreatedAt:      createdAt,
+					},
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().GetWebhookDeliveriesByEndpointId(gomock.Any(), "wid", maxListWebhookDeliveriesLimit).Return([]entity.WebhookDelivery{delivery, delivered}, nil),
+				)
+			},
+		},
+		{
+			name: "error deleted endpoint",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWebhookDeliveriesRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: ListWebhookDeliveriesResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				deleted := endpoint
+				deleted.Status = int(enum.WEBHOOK_ENDPOINT_STATUS_DELETED)
+
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(deleted, nil),
+				)
+			},
+		},
+		{
+			name: "error webhook.GetWebhookEndpointById",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWebhookDeliveriesRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: ListWebhookDeliveriesResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(entity.WebhookEndpoint{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error webhook.GetWebhookDeliveriesByEndpointId",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: ListWebhookDeliveriesRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+				},
+			},
+			wantResp: ListWebhookDeliveriesResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().GetWebhookDeliveriesByEndpointId(gomock.Any(), "wid", maxListWebhookDeliveriesLimit).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				webhook: tt.fields.webhook,
+			}
+			tt.mock()
+			gotResp, err := u.ListWebhookDeliveries(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.ListWebhookDeliveries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.ListWebhookDeliveries() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_RedeliverWebhookDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainWebhook := domainwebhook.NewMockDomainItf(ctrl)
+
+	dead := delivery
+	dead.Status = int(enum.WEBHOOK_DELIVERY_STATUS_DEAD)
+	dead.Attempt = maxWebhookDeliveryAttempts
+	dead.LastStatusCode = http.StatusInternalServerError
+	dead.LastError = "webhook endpoint responded with status 500"
+
+	type fields struct {
+		webhook domainwebhook.DomainItf
+	}
+	type args struct {
+		ctx context.Context
+		req RedeliverWebhookDeliveryRequest
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp RedeliverWebhookDeliveryResponse
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RedeliverWebhookDeliveryRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+					WebhookDeliveryId: "did",
+				},
+			},
+			wantResp: RedeliverWebhookDeliveryResponse{
+				Code: http.StatusOK,
+				WebhookDelivery: WebhookDelivery{
+					Id:             "did",
+					EventId:        "eid",
+					EventType:      "BalanceCredited",
+					Status:         "pending",
+					LastStatusCode: http.StatusInternalServerError,
+					LastError:      "webhook endpoint responded with status 500",
+					CreatedAt:      createdAt,
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().GetWebhookDeliveryById(gomock.Any(), "did").Return(dead, nil),
+					mockDomainWebhook.EXPECT().RedeliverWebhookDelivery(gomock.Any(), "did", "wid").Return(nil),
+				)
+			},
+		},
+		{
+			name: "error delivery of another endpoint",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RedeliverWebhookDeliveryRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+					WebhookDeliveryId: "did",
+				},
+			},
+			wantResp: RedeliverWebhookDeliveryResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				other := dead
+				other.EndpointId = "otherwid"
+
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().GetWebhookDeliveryById(gomock.Any(), "did").Return(other, nil),
+				)
+			},
+		},
+		{
+			name: "error delivery not found",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RedeliverWebhookDeliveryRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+					WebhookDeliveryId: "did",
+				},
+			},
+			wantResp: RedeliverWebhookDeliveryResponse{
+				Code: http.StatusNotFound,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().GetWebhookDeliveryById(gomock.Any(), "did").Return(entity.WebhookDelivery{}, sql.ErrNoRows),
+				)
+			},
+		},
+		{
+			name: "error delivery pending",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RedeliverWebhookDeliveryRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+					WebhookDeliveryId: "did",
+				},
+			},
+			wantResp: RedeliverWebhookDeliveryResponse{
+				Code: http.StatusConflict,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().GetWebhookDeliveryById(gomock.Any(), "did").Return(delivery, nil),
+					mockDomainWebhook.EXPECT().RedeliverWebhookDelivery(gomock.Any(), "did", "wid").Return(domainwebhook.ErrWebhookDeliveryPending),
+				)
+			},
+		},
+		{
+			name: "error webhook.RedeliverWebhookDelivery",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				req: RedeliverWebhookDeliveryRequest{
+					UserId:            "id",
+					WebhookEndpointId: "wid",
+					WebhookDeliveryId: "did",
+				},
+			},
+			wantResp: RedeliverWebhookDeliveryResponse{
+				Code: http.StatusBadGateway,
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().GetWebhookDeliveryById(gomock.Any(), "did").Return(dead, nil),
+					mockDomainWebhook.EXPECT().RedeliverWebhookDelivery(gomock.Any(), "did", "wid").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				webhook: tt.fields.webhook,
+			}
+			tt.mock()
+			gotResp, err := u.RedeliverWebhookDelivery(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.RedeliverWebhookDelivery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// The delivery is due the moment it is redelivered, so only its presence is checked.
+			if !tt.wantErr && gotResp.NextAttemptAt == nil {
+				t.Errorf("usecase.RedeliverWebhookDelivery() next_attempt_at is empty")
+			}
+			gotResp.NextAttemptAt = nil
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("usecase.RedeliverWebhookDelivery() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_dispatchWebhookEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainWebhook := domainwebhook.NewMockDomainItf(ctrl)
+	mockDomainOutbox := domainoutbox.NewMockDomainItf(ctrl)
+
+	now := createdAt.Add(time.Second)
+	event := entity.OutboxEvent{
+		EventId:   "eid",
+		UserId:    "id",
+		Type:      "BalanceCredited",
+		Version:   1,
+		Payload:   `{"user_id":"id"}`,
+		CreatedAt: createdAt,
+		StreamId:  "1-0",
+	}
+	unsubscribed := event
+	unsubscribed.EventId = "eid2"
+	unsubscribed.Type = "BalanceDebited"
+	unsubscribed.StreamId = "1-1"
+
+	type fields struct {
+		webhook domainwebhook.DomainItf
+		outbox  domainoutbox.DomainItf
+	}
+	type args struct {
+		ctx     context.Context
+		pending bool
+		now     time.Time
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantResp int
+		wantErr  bool
+		mock     func()
+	}{
+		{
+			name: "success",
+			fields: fields{
+				webhook: mockDomainWebhook,
+				outbox:  mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 2,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), domainoutbox.ReadEventsRequest{
+						Group:    webhookEventGroup,
+						Consumer: "consumer",
+						Count:    webhookEventBatch,
+						Block:    webhookEventBlock,
+					}).Return([]entity.OutboxEvent{event, unsubscribed}, nil),
+					mockDomainWebhook.EXPECT().GetSubscribedWebhookEndpoints(gomock.Any(), "id", "BalanceCredited").Return([]entity.WebhookEndpoint{endpoint}, nil),
+					mockDomainWebhook.EXPECT().InsertWebhookDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, wd entity.WebhookDelivery) error {
+						payload := `{"id":"eid","type":"BalanceCredited","version":1,"occurred_at":"2026-10-18T09:00:00Z","data":{"user_id":"id"}}`
+						if wd.Id == "" || wd.EndpointId != "wid" || wd.EventId != "eid" || wd.Payload != payload || wd.Status != int(enum.WEBHOOK_DELIVERY_STATUS_PENDING) || !wd.NextAttemptAt.Equal(now) {
+							return fmt.Errorf("unexpected webhook delivery %+v", wd)
+						}
+						return nil
+					}),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), webhookEventGroup, "1-0").Return(nil),
+					mockDomainWebhook.EXPECT().GetSubscribedWebhookEndpoints(gomock.Any(), "id", "BalanceDebited").Return(nil, nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), webhookEventGroup, "1-1").Return(nil),
+				)
+			},
+		},
+		{
+			name: "success pending does not block",
+			fields: fields{
+				webhook: mockDomainWebhook,
+				outbox:  mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: true,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), domainoutbox.ReadEventsRequest{
+						Group:    webhookEventGroup,
+						Consumer: "consumer",
+						Pending:  true,
+						Count:    webhookEventBatch,
+					}).Return(nil, nil),
+				)
+			},
+		},
+		{
+			name: "error outbox.ReadEvents",
+			fields: fields{
+				webhook: mockDomainWebhook,
+				outbox:  mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error webhook.InsertWebhookDelivery leaves the event unacknowledged",
+			fields: fields{
+				webhook: mockDomainWebhook,
+				outbox:  mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{event, unsubscribed}, nil),
+					mockDomainWebhook.EXPECT().GetSubscribedWebhookEndpoints(gomock.Any(), "id", "BalanceCredited").Return([]entity.WebhookEndpoint{endpoint}, nil),
+					mockDomainWebhook.EXPECT().InsertWebhookDelivery(gomock.Any(), gomock.Any()).Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error outbox.AckEvents",
+			fields: fields{
+				webhook: mockDomainWebhook,
+				outbox:  mockDomainOutbox,
+			},
+			args: args{
+				ctx:     context.Background(),
+				pending: false,
+				now:     now,
+			},
+			wantResp: 0,
+			wantErr:  true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainOutbox.EXPECT().ReadEvents(gomock.Any(), gomock.Any()).Return([]entity.OutboxEvent{unsubscribed}, nil),
+					mockDomainWebhook.EXPECT().GetSubscribedWebhookEndpoints(gomock.Any(), "id", "BalanceDebited").Return(nil, nil),
+					mockDomainOutbox.EXPECT().AckEvents(gomock.Any(), webhookEventGroup, "1-1").Return(fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				webhook:  tt.fields.webhook,
+				outbox:   tt.fields.outbox,
+				consumer: "consumer",
+			}
+			tt.mock()
+			gotResp, err := u.dispatchWebhookEvents(tt.args.ctx, tt.args.pending, tt.args.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("usecase.dispatchWebhookEvents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotResp != tt.wantResp {
+				t.Errorf("usecase.dispatchWebhookEvents() = %v, want %v", gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_usecase_attemptDueWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDomainWebhook := domainwebhook.NewMockDomainItf(ctrl)
+
+	now := createdAt.Add(time.Second)
+
+	type fields struct {
+		webhook domainwebhook.DomainItf
+	}
+	type args struct {
+		ctx   context.Context
+		clock func() time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name: "success records the attempts and reads each endpoint once",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				clock: func() time.Time {
+					return now
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				failing := delivery
+				failing.Id = "did2"
+
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainwebhook.ClaimDueWebhookDeliveriesRequest) ([]entity.WebhookDelivery, error) {
+						if req.LeaseId == "" || !req.Now.Equal(now) || !req.LeaseExpiresAt.Equal(now.Add(webhookDeliveryLease)) || req.Limit != webhookDeliveryBatch {
+							return nil, fmt.Errorf("unexpected request %+v", req)
+						}
+						return []entity.WebhookDelivery{delivery, failing}, nil
+					}),
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil),
+					mockDomainWebhook.EXPECT().SendWebhookDelivery(gomock.Any(), endpoint, delivery).Return(http.StatusOK, nil),
+					mockDomainWebhook.EXPECT().FinishWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainwebhook.FinishWebhookDeliveryAttemptRequest) error {
+						if req.LeaseId == "" || req.Delivery.Status != int(enum.WEBHOOK_DELIVERY_STATUS_DELIVERED) || req.Delivery.Attempt != 1 {
+							return fmt.Errorf("unexpected webhook delivery %+v", req.Delivery)
+						}
+						return nil
+					}),
+					mockDomainWebhook.EXPECT().SendWebhookDelivery(gomock.Any(), endpoint, failing).Return(http.StatusServiceUnavailable, fmt.Errorf("webhook endpoint responded with status 503")),
+					mockDomainWebhook.EXPECT().FinishWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainwebhook.FinishWebhookDeliveryAttemptRequest) error {
+						if req.Delivery.Id != "did2" || req.Delivery.Status != int(enum.WEBHOOK_DELIVERY_STATUS_PENDING) || !req.Delivery.NextAttemptAt.Equal(now.Add(webhookRetryInterval)) {
+							return fmt.Errorf("unexpected webhook delivery %+v", req.Delivery)
+						}
+						return domainwebhook.ErrLeaseLost
+					}),
+				)
+			},
+		},
+		{
+			name: "success claims batches until one comes back short",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				clock: func() func() time.Time {
+					batchNow := now
+					return func() time.Time {
+						batchNow = batchNow.Add(time.Minute)
+						return batchNow
+					}
+				}(),
+			},
+			wantErr: false,
+			mock: func() {
+				batch := make([]entity.WebhookDelivery, webhookDeliveryBatch)
+				for idx := range batch {
+					batch[idx] = delivery
+				}
+
+				mockDomainWebhook.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).Return(batch, nil)
+				mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(endpoint, nil)
+				mockDomainWebhook.EXPECT().SendWebhookDelivery(gomock.Any(), endpoint, delivery).Return(http.StatusOK, nil).Times(webhookDeliveryBatch)
+				mockDomainWebhook.EXPECT().FinishWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).Return(nil).Times(webhookDeliveryBatch)
+				mockDomainWebhook.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req domainwebhook.ClaimDueWebhookDeliveriesRequest) ([]entity.WebhookDelivery, error) {
+					// The clock is read once for the first lease and once for every attempt of the first batch.
+					claimedAt := now.Add(time.Minute * time.Duration(webhookDeliveryBatch+2))
+					if !req.Now.Equal(claimedAt) || !req.LeaseExpiresAt.Equal(claimedAt.Add(webhookDeliveryLease)) {
+						return nil, fmt.Errorf("unexpected request %+v", req)
+					}
+					return nil, nil
+				})
+			},
+		},
+		{
+			name: "success keeps the lease of a delivery whose endpoint could not be read",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				clock: func() time.Time {
+					return now
+				},
+			},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).Return([]entity.WebhookDelivery{delivery}, nil),
+					mockDomainWebhook.EXPECT().GetWebhookEndpointById(gomock.Any(), "wid").Return(entity.WebhookEndpoint{}, fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name: "error webhook.ClaimDueWebhookDeliveries",
+			fields: fields{
+				webhook: mockDomainWebhook,
+			},
+			args: args{
+				ctx: context.Background(),
+				clock: func() time.Time {
+					return now
+				},
+			},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockDomainWebhook.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := usecase{
+				webhook: tt.fields.webhook,
+			}
+			tt.mock()
+			if err := u.attemptDueWebhookDeliveries(tt.args.ctx, tt.args.clock); (err != nil) != tt.wantErr {
+				t.Errorf("usecase.attemptDueWebhookDeliveries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_nextWebhookDelivery(t *testing.T) {
+	now := createdAt.Add(time.Second)
+	sendErr := fmt.Errorf("webhook endpoint responded with status 500")
+
+	withFields := func(update func(wd *entity.WebhookDelivery)) entity.WebhookDelivery {
+		wd := delivery
+		update(&wd)
+		return wd
+	}
+
+	type args struct {
+		delivery   entity.WebhookDelivery
+		statusCode int
+		sendErr    error
+	}
+	tests := []struct {
+		name string
+		args args
+		want entity.WebhookDelivery
+	}{
+		{
+			name: "delivered",
+			args: args{
+				delivery: withFields(func(wd *entity.WebhookDelivery) {
+					wd.Attempt = 2
+					wd.LastError = "connection refused"
+				}),
+				statusCode: http.StatusNoContent,
+				sendErr:    nil,
+			},
+			want: withFields(func(wd *entity.WebhookDelivery) {
+				wd.Status = int(enum.WEBHOOK_DELIVERY_STATUS_DELIVERED)
+				wd.Attempt = 3
+				wd.LastStatusCode = http.StatusNoContent
+				wd.DeliveredAt = &now
+			}),
+		},
+		{
+			name: "failed first attempt",
+			args: args{
+				delivery:   delivery,
+				statusCode: http.StatusInternalServerError,
+				sendErr:    sendErr,
+			},
+			want: withFields(func(wd *entity.WebhookDelivery) {
+				wd.Attempt = 1
+				wd.NextAttemptAt = now.Add(time.Minute)
+				wd.LastStatusCode = http.StatusInternalServerError
+				wd.LastError = sendErr.Error()
+			}),
+		},
+		{
+			name: "failed backs off exponentially",
+			args: args{
+				delivery: withFields(func(wd *entity.WebhookDelivery) {
+					wd.Attempt = 3
+				}),
+				statusCode: 0,
+				sendErr:    sendErr,
+			},
+			want: withFields(func(wd *entity.WebhookDelivery) {
+				wd.Attempt = 4
+				wd.NextAttemptAt = now.Add(8 * time.Minute)
+				wd.LastError = sendErr.Error()
+			}),
+		},
+		{
+			name: "failed out of attempts is dead",
+			args: args{
+				delivery: withFields(func(wd *entity.WebhookDelivery) {
+					wd.Attempt = maxWebhookDeliveryAttempts - 1
+				}),
+				statusCode: http.StatusInternalServerError,
+				sendErr:    sendErr,
+			},
+			want: withFields(func(wd *entity.WebhookDelivery) {
+				wd.Status = int(enum.WEBHOOK_DELIVERY_STATUS_DEAD)
+				wd.Attempt = maxWebhookDeliveryAttempts
+				wd.LastStatusCode = http.StatusInternalServerError
+				wd.LastError = sendErr.Error()
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextWebhookDelivery(tt.args.delivery, tt.args.statusCode, tt.args.sendErr, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextWebhookDelivery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
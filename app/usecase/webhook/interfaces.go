@@ -0,0 +1,14 @@
+package usecasewebhook
+
+import "context"
+
+type UsecaseItf interface {
+	CreateWebhookEndpoint(ctx context.Context, req CreateWebhookEndpointRequest) (resp CreateWebhookEndpointResponse, err error)
+	ListWebhookEndpoints(ctx context.Context, req ListWebhookEndpointsRequest) (resp ListWebhookEndpointsResponse, err error)
+	DeleteWebhookEndpoint(ctx context.Context, req DeleteWebhookEndpointRequest) (resp DeleteWebhookEndpointResponse, err error)
+	ListWebhookDeliveries(ctx context.Context, req ListWebhookDeliveriesRequest) (resp ListWebhookDeliveriesResponse, err error)
+	RedeliverWebhookDelivery(ctx context.Context, req RedeliverWebhookDeliveryRequest) (resp RedeliverWebhookDeliveryResponse, err error)
+
+	RunWebhookDispatcher(ctx context.Context)
+	RunWebhookDeliveries(ctx context.Context)
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/usecase/webhook/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=app/usecase/webhook/interfaces.go -destination=app/usecase/webhook/mock.go -package=usecasewebhook
+//
+
+// Package usecasewebhook is a generated GoMock package.
+package usecasewebhook
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsecaseItf is a mock of UsecaseItf interface.
+type MockUsecaseItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseItfMockRecorder
+}
+
+// MockUsecaseItfMockRecorder is the mock recorder for MockUsecaseItf.
+type MockUsecaseItfMockRecorder struct {
+	mock *MockUsecaseItf
+}
+
+// NewMockUsecaseItf creates a new mock instance.
+func NewMockUsecaseItf(ctrl *gomock.Controller) *MockUsecaseItf {
+	mock := &MockUsecaseItf{ctrl: ctrl}
+	mock.recorder = &MockUsecaseItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecaseItf) EXPECT() *MockUsecaseItfMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhookEndpoint mocks base method.
+func (m *MockUsecaseItf) CreateWebhookEndpoint(ctx context.Context, req CreateWebhookEndpointRequest) (CreateWebhookEndpointResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", ctx, req)
+	ret0, _ := ret[0].(CreateWebhookEndpointResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockUsecaseItfMockRecorder) CreateWebhookEndpoint(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockUsecaseItf)(nil).CreateWebhookEndpoint), ctx, req)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockUsecaseItf) DeleteWebhookEndpoint(ctx context.Context, req DeleteWebhookEndpointRequest) (DeleteWebhookEndpointResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", ctx, req)
+	ret0, _ := ret[0].(DeleteWebhookEndpointResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockUsecaseItfMockRecorder) DeleteWebhookEndpoint(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockUsecaseItf)(nil).DeleteWebhookEndpoint), ctx, req)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockUsecaseItf) ListWebhookDeliveries(ctx context.Context, req ListWebhookDeliveriesRequest) (ListWebhookDeliveriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, req)
+	ret0, _ := ret[0].(ListWebhookDeliveriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockUsecaseItfMockRecorder) ListWebhookDeliveries(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockUsecaseItf)(nil).ListWebhookDeliveries), ctx, req)
+}
+
+// ListWebhookEndpoints mocks base method.
+func (m *MockUsecaseItf) ListWebhookEndpoints(ctx context.Context, req ListWebhookEndpointsRequest) (ListWebhookEndpointsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookEndpoints", ctx, req)
+	ret0, _ := ret[0].(ListWebhookEndpointsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookEndpoints indicates an expected call of ListWebhookEndpoints.
+func (mr *MockUsecaseItfMockRecorder) ListWebhookEndpoints(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpoints", reflect.TypeOf((*MockUsecaseItf)(nil).ListWebhookEndpoints), ctx, req)
+}
+
+// RedeliverWebhookDelivery mocks base method.
+func (m *MockUsecaseItf) RedeliverWebhookDelivery(ctx context.Context, req RedeliverWebhookDeliveryRequest) (RedeliverWebhookDeliveryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhookDelivery", ctx, req)
+	ret0, _ := ret[0].(RedeliverWebhookDeliveryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhookDelivery indicates an expected call of RedeliverWebhookDelivery.
+func (mr *MockUsecaseItfMockRecorder) RedeliverWebhookDelivery(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookDelivery", reflect.TypeOf((*MockUsecaseItf)(nil).RedeliverWebhookDelivery), ctx, req)
+}
+
+// RunWebhookDeliveries mocks base method.
+func (m *MockUsecaseItf) RunWebhookDeliveries(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunWebhookDeliveries", ctx)
+}
+
+// RunWebhookDeliveries indicates an expected call of RunWebhookDeliveries.
+func (mr *MockUsecaseItfMockRecorder) RunWebhookDeliveries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWebhookDeliveries", reflect.TypeOf((*MockUsecaseItf)(nil).RunWebhookDeliveries), ctx)
+}
+
+// RunWebhookDispatcher mocks base method.
+func (m *MockUsecaseItf) RunWebhookDispatcher(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunWebhookDispatcher", ctx)
+}
+
+// RunWebhookDispatcher indicates an expected call of RunWebhookDispatcher.
+func (mr *MockUsecaseItfMockRecorder) RunWebhookDispatcher(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWebhookDispatcher", reflect.TypeOf((*MockUsecaseItf)(nil).RunWebhookDispatcher), ctx)
+}
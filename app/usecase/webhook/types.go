@@ -0,0 +1,81 @@
+package usecasewebhook
+
+import (
+	"time"
+)
+
+type CreateWebhookEndpointRequest struct {
+	UserId     string
+	Url        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+// CreateWebhookEndpointResponse is the only response with the secret of the endpoint.
+type CreateWebhookEndpointResponse struct {
+	Code int `json:"-"`
+	WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+type ListWebhookEndpointsRequest struct {
+	UserId string
+}
+
+type ListWebhookEndpointsResponse struct {
+	Code int               `json:"-"`
+	Data []WebhookEndpoint `json:"data"`
+}
+
+type DeleteWebhookEndpointRequest struct {
+	UserId            string
+	WebhookEndpointId string
+}
+
+type DeleteWebhookEndpointResponse struct {
+	Code int `json:"-"`
+	WebhookEndpoint
+}
+
+type ListWebhookDeliveriesRequest struct {
+	UserId            string
+	WebhookEndpointId string
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Code int               `json:"-"`
+	Data []WebhookDelivery `json:"data"`
+}
+
+type RedeliverWebhookDeliveryRequest struct {
+	UserId            string
+	WebhookEndpointId string
+	WebhookDeliveryId string
+}
+
+type RedeliverWebhookDeliveryResponse struct {
+	Code int `json:"-"`
+	WebhookDelivery
+}
+
+type WebhookEndpoint struct {
+	Id         string    `json:"id"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is a delivery of an event to the endpoint. NextAttemptAt is only set while it is pending, and
+// LastStatusCode and LastError once an attempt got a response or failed.
+type WebhookDelivery struct {
+	Id             string     `json:"id"`
+	EventId        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempt        int        `json:"attempt"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
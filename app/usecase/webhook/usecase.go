@@ -0,0 +1,43 @@
+package usecasewebhook
+
+import (
+	"time"
+
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+	domainwebhook "github.com/kevinsudut/wallet-system/app/domain/webhook"
+)
+
+const (
+	maxWebhookEndpoints           = 10
+	maxWebhookUrlLength           = 2048
+	maxListWebhookDeliveriesLimit = 100
+
+	// A failed delivery is attempted again after webhookRetryInterval, doubled on every attempt, and is dead once
+	// maxWebhookDeliveryAttempts have failed, about 8.5 hours after the first.
+	maxWebhookDeliveryAttempts = 10
+	webhookRetryInterval       = time.Minute
+
+	// webhookEventGroup is the consumer group of the event stream every instance reads events for webhooks in.
+	webhookEventGroup      = "webhooks"
+	webhookEventBatch      = 100
+	webhookEventBlock      = 5 * time.Second
+	webhookEventRetryDelay = 5 * time.Second
+
+	webhookDeliveryPollInterval = 5 * time.Second
+	webhookDeliveryLease        = 3 * time.Minute
+	webhookDeliveryBatch        = 10
+)
+
+type usecase struct {
+	webhook  domainwebhook.DomainItf
+	outbox   domainoutbox.DomainItf
+	consumer string
+}
+
+func Init(webhook domainwebhook.DomainItf, outbox domainoutbox.DomainItf) UsecaseItf {
+	return &usecase{
+		webhook:  webhook,
+		outbox:   outbox,
+		consumer: domainoutbox.ConsumerName(),
+	}
+}
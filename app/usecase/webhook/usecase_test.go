@@ -0,0 +1,40 @@
+package usecasewebhook
+
+import (
+	"reflect"
+	"testing"
+
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+	domainwebhook "github.com/kevinsudut/wallet-system/app/domain/webhook"
+)
+
+func TestInit(t *testing.T) {
+	type args struct {
+		webhook domainwebhook.DomainItf
+		outbox  domainoutbox.DomainItf
+	}
+	tests := []struct {
+		name string
+		args args
+		want UsecaseItf
+	}{
+		{
+			args: args{
+				webhook: nil,
+				outbox:  nil,
+			},
+			want: &usecase{
+				webhook:  nil,
+				outbox:   nil,
+				consumer: domainoutbox.ConsumerName(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Init(tt.args.webhook, tt.args.outbox); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Init() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package usecasewebhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	domainoutbox "github.com/kevinsudut/wallet-system/app/domain/outbox"
+	domainwebhook "github.com/kevinsudut/wallet-system/app/domain/webhook"
+	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/app/enum"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+)
+
+// RunWebhookDispatcher turns the events of the event stream into deliveries to the endpoints subscribed to them.
+// Every instance runs it in the same consumer group, which hands each event to one of them. It first reads back
+// the events it was handed and did not acknowledge, and does so again after every failure. It stops once ctx is done.
+func (u usecase) RunWebhookDispatcher(ctx context.Context) {
+	for {
+		err := u.outbox.CreateEventGroup(ctx, webhookEventGroup)
+		if err == nil {
+			break
+		}
+
+		log.Errorln("RunWebhookDispatcher.CreateEventGroup", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(webhookEventRetryDelay):
+		}
+	}
+
+	pending := true
+	for {
+		count, err := u.dispatchWebhookEvents(ctx, pending, time.Now())
+		if err != nil {
+			log.Errorln("RunWebhookDispatcher.dispatchWebhookEvents", err)
+			pending = true
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(webhookEventRetryDelay):
+			}
+			continue
+		}
+
+		if pending && count == 0 {
+			pending = false
+		}
+	}
+}
+
+// dispatchWebhookEvents reads a batch of events and creates their deliveries, due at now. An event is acknowledged
+// once its deliveries are created, so a failure leaves it and the events after it to be read again.
+func (u usecase) dispatchWebhookEvents(ctx context.Context, pending bool, now time.Time) (resp int, err error) {
+	req := domainoutbox.ReadEventsRequest{
+		Group:    webhookEventGroup,
+		Consumer: u.consumer,
+		Pending:  pending,
+		Count:    webhookEventBatch,
+	}
+	if !pending {
+		req.Block = webhookEventBlock
+	}
+
+	events, err := u.outbox.ReadEvents(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, event := range events {
+		err = u.dispatchWebhookEvent(ctx, event, now)
+		if err != nil {
+			return resp, err
+		}
+
+		err = u.outbox.AckEvents(ctx, webhookEventGroup, event.StreamId)
+		if err != nil {
+			return resp, err
+		}
+
+		resp++
+	}
+
+	return resp, nil
+}
+
+// dispatchWebhookEvent creates a delivery of event to every endpoint of its user subscribed to it. An endpoint
+// that already has one, because the event was read before, keeps it.
+func (u usecase) dispatchWebhookEvent(ctx context.Context, event entity.OutboxEvent, now time.Time) (err error) {
+	endpoints, err := u.webhook.GetSubscribedWebhookEndpoints(ctx, event.UserId, event.Type)
+	if err != nil {
+		return err
+	}
+
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	payload, err := jsoniter.MarshalToString(entity.NewWebhookPayload(event))
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		err = u.webhook.InsertWebhookDelivery(ctx, entity.WebhookDelivery{
+			Id:            uuid.NewString(),
+			EndpointId:    endpoint.Id,
+			EventId:       event.EventId,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        int(enum.WEBHOOK_DELIVERY_STATUS_PENDING),
+			NextAttemptAt: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunWebhookDeliveries attempts the due deliveries on start and then every poll interval, until ctx is done. Every
+// instance runs it, the leases taken by ClaimDueWebhookDeliveries keep them from attempting the same delivery.
+func (u usecase) RunWebhookDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(webhookDeliveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := u.attemptDueWebhookDeliveries(ctx, time.Now)
+		if err != nil {
+			log.Errorln("RunWebhookDeliveries.attemptDueWebhookDeliveries", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// attemptDueWebhookDeliveries claims and attempts the due deliveries batch by batch, until a batch comes back short.
+// Sending a batch takes up to a timeout per delivery, so every lease and attempt reads clock again.
+func (u usecase) attemptDueWebhookDeliveries(ctx context.Context, clock func() time.Time) (err error) {
+	for {
+		now := clock()
+		leaseId := uuid.NewString()
+		deliveries, err := u.webhook.ClaimDueWebhookDeliveries(ctx, domainwebhook.ClaimDueWebhookDeliveriesRequest{
+			LeaseId:        leaseId,
+			LeaseExpiresAt: now.Add(webhookDeliveryLease),
+			Now:            now,
+			Limit:          webhookDeliveryBatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		endpoints := make(map[string]entity.WebhookEndpoint)
+		for _, delivery := range deliveries {
+			// A delivery whose attempt could not be finished keeps its lease until it expires and is claimed again.
+			err = u.attemptWebhookDelivery(ctx, leaseId, delivery, endpoints, clock)
+			if err != nil {
+				log.Errorln("attemptDueWebhookDeliveries.attemptWebhookDelivery", err)
+			}
+		}
+
+		if len(deliveries) < webhookDeliveryBatch {
+			return nil
+		}
+	}
+}
+
+// attemptWebhookDelivery sends a claimed delivery to its endpoint and records the attempt at the time clock reads
+// once it is sent. endpoints holds the endpoints read for the batch so far.
+func (u usecase) attemptWebhookDelivery(ctx context.Context, leaseId string, delivery entity.WebhookDelivery, endpoints map[string]entity.WebhookEndpoint, clock func() time.Time) (err error) {
+	endpoint, ok := endpoints[delivery.EndpointId]
+	if !ok {
+		endpoint, err = u.webhook.GetWebhookEndpointById(ctx, delivery.EndpointId)
+		if err != nil {
+			return err
+		}
+		endpoints[delivery.EndpointId] = endpoint
+	}
+
+	statusCode, sendErr := u.webhook.SendWebhookDelivery(ctx, endpoint, delivery)
+
+	return u.webhook.FinishWebhookDeliveryAttempt(ctx, domainwebhook.FinishWebhookDeliveryAttemptRequest{
+		LeaseId:  leaseId,
+		Delivery: nextWebhookDelivery(delivery, statusCode, sendErr, clock()),
+	})
+}
+
+// nextWebhookDelivery is the state of the delivery after an attempt at now that got statusCode and sendErr. A
+// failed delivery is attempted again after the retry interval doubled for every attempt before, until it runs out
+// of attempts and is dead.
+func nextWebhookDelivery(delivery entity.WebhookDelivery, statusCode int, sendErr error, now time.Time) entity.WebhookDelivery {
+	next := delivery
+	next.Attempt++
+	next.LastStatusCode = statusCode
+	next.LastError = ""
+
+	if sendErr == nil {
+		deliveredAt := now.UTC()
+		next.Status = int(enum.WEBHOOK_DELIVERY_STATUS_DELIVERED)
+		next.DeliveredAt = &deliveredAt
+		return next
+	}
+
+	next.LastError = sendErr.Error()
+	if next.Attempt >= maxWebhookDeliveryAttempts {
+		next.Status = int(enum.WEBHOOK_DELIVERY_STATUS_DEAD)
+		return next
+	}
+
+	next.NextAttemptAt = now.Add(webhookRetryInterval << (next.Attempt - 1)).UTC()
+
+	return next
+}
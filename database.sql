@@ -1,37 +1,451 @@
 CREATE TABLE IF NOT EXISTS users (
   id CHAR(36) PRIMARY KEY,
   username VARCHAR NOT NULL,
+  -- Tier picks the transaction limits of the user, see enum.UserTier.
+  tier SMALLINT NOT NULL DEFAULT 1,
+  -- Admins can change the status of other users, see enum.UserRole. Admins are only granted in the database.
+  role SMALLINT NOT NULL DEFAULT 1,
+  -- See enum.UserStatus. Every change is recorded in user_status_changes.
+  status SMALLINT NOT NULL DEFAULT 1,
+  status_reason VARCHAR NOT NULL DEFAULT '',
+  status_changed_at TIMESTAMP WITH TIME ZONE NULL,
+  -- ISO 4217 code of the main currency of the user, which is the currency of balances, pockets and transfers.
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
   updated_at TIMESTAMP WITH TIME ZONE NULL
 );
 
+CREATE TABLE IF NOT EXISTS user_credentials (
+  user_id CHAR(36) PRIMARY KEY REFERENCES users (id),
+  password_hash VARCHAR NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- actor_id is the admin who changed the status, or the user itself when they closed their own account.
+CREATE TABLE IF NOT EXISTS user_status_changes (
+  id CHAR(36) PRIMARY KEY,
+  user_id CHAR(36) NOT NULL REFERENCES users (id),
+  from_status SMALLINT NOT NULL,
+  to_status SMALLINT NOT NULL,
+  reason VARCHAR NOT NULL,
+  actor_id CHAR(36) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  id CHAR(36) PRIMARY KEY,
+  user_id CHAR(36) NOT NULL REFERENCES users (id),
+  family_id CHAR(36) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  rotated_at TIMESTAMP WITH TIME ZONE NULL,
+  revoked_at TIMESTAMP WITH TIME ZONE NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- held_amount is the part of amount reserved by the active holds of the user, the rest is available. currency is
+-- the main currency of the user.
 CREATE TABLE IF NOT EXISTS balances (
   user_id CHAR(36) PRIMARY KEY,
-  amount NUMERIC NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  held_amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Balances of user_id in currencies other than its main one, filled by POST /convert. amount is the projection of
+-- the postings on the currency:<user_id>:<currency> ledger account.
+CREATE TABLE IF NOT EXISTS currency_balances (
+  user_id CHAR(36) NOT NULL,
+  currency CHAR(3) NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL,
+  PRIMARY KEY (user_id, currency)
+);
+
+-- One unit of base_currency buys rate units of quote_currency from effective_at until the next rate of the pair.
+-- Rates are directional, so a pair and its inverse can carry a spread. Rows are only inserted, by hand or a feed.
+CREATE TABLE IF NOT EXISTS fx_rates (
+  id BIGSERIAL PRIMARY KEY,
+  base_currency CHAR(3) NOT NULL,
+  quote_currency CHAR(3) NOT NULL,
+  rate NUMERIC(20, 8) NOT NULL,
+  effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Named wallets of user_id next to the main wallet in balances. amount is the projection of the postings on the
+-- pocket:<id> ledger account. A goal_amount of 0 is no goal, and money cannot leave the pocket before locked_until.
+CREATE TABLE IF NOT EXISTS pockets (
+  id CHAR(36) PRIMARY KEY,
+  user_id CHAR(36) NOT NULL REFERENCES users (id),
+  name VARCHAR NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  goal_amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  locked_until TIMESTAMP WITH TIME ZONE NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Wallets owned by several users. amount is the projection of the postings on the wallet:<id> ledger account, in
+-- the main currency of the user who created the wallet.
+CREATE TABLE IF NOT EXISTS shared_wallets (
+  id CHAR(36) PRIMARY KEY,
+  name VARCHAR NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  created_by CHAR(36) NOT NULL REFERENCES users (id),
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- role is an enum.SharedWalletRole and status an enum.SharedWalletMemberStatus. A member who declined, left or
+-- was removed keeps their row, which is reused when they are invited again. spend_limit caps every transaction
+-- of a spender.
+CREATE TABLE IF NOT EXISTS shared_wallet_members (
+  wallet_id CHAR(36) NOT NULL REFERENCES shared_wallets (id),
+  user_id CHAR(36) NOT NULL REFERENCES users (id),
+  "role" SMALLINT NOT NULL,
+  spend_limit NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  status SMALLINT NOT NULL,
+  invited_by CHAR(36) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL,
+  PRIMARY KEY (wallet_id, user_id)
+);
+
+-- Movements of shared wallets, initiated_by is the member who made them. A deposit is also a history of type 5 of
+-- the member, and a transfer a credit history of the recipient.
+CREATE TABLE IF NOT EXISTS shared_wallet_histories (
+  id CHAR(36) PRIMARY KEY,
+  journal_entry_id CHAR(36) NOT NULL,
+  wallet_id CHAR(36) NOT NULL REFERENCES shared_wallets (id),
+  initiated_by CHAR(36) NOT NULL,
+  target_user_id CHAR(36) NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  "type" SMALLINT NOT NULL,
+  notes VARCHAR NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Funds of user_id reserved for target_user_id until they are captured into a transfer, voided or expire.
+-- journal_entry_id is the transfer written by the capture.
+CREATE TABLE IF NOT EXISTS holds (
+  id CHAR(36) PRIMARY KEY,
+  user_id CHAR(36) NOT NULL,
+  target_user_id CHAR(36) NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  captured_amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  status SMALLINT NOT NULL,
+  journal_entry_id CHAR(36) NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Transfers of user_id to to_user_id run by the scheduler. scheduled_at is the occurrence being run and next_run_at
+-- when it is tried next, which is later than scheduled_at while a failed occurrence is retried. attempt counts the
+-- failed runs of the occurrence. A worker owns the row while lease_id is set and lease_expires_at has not passed.
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+  id CHAR(36) PRIMARY KEY,
+  user_id CHAR(36) NOT NULL,
+  to_user_id CHAR(36) NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  recurrence SMALLINT NOT NULL,
+  day_of_month SMALLINT NOT NULL DEFAULT 0,
+  failure_policy SMALLINT NOT NULL,
+  status SMALLINT NOT NULL,
+  scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  attempt INT NOT NULL DEFAULT 0,
+  lease_id CHAR(36) NULL,
+  lease_expires_at TIMESTAMP WITH TIME ZONE NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- One row per execution of a scheduled transfer. error_code is the API error code of a failed run.
+CREATE TABLE IF NOT EXISTS scheduled_transfer_runs (
+  id CHAR(36) PRIMARY KEY,
+  scheduled_transfer_id CHAR(36) NOT NULL REFERENCES scheduled_transfers (id),
+  scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  attempt INT NOT NULL,
+  status SMALLINT NOT NULL,
+  error_code VARCHAR NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Money asked by requester_id from payer_id. status 2 (accepting) is held while the paying transfer runs, and
+-- a pending request past expires_at is expired.
+CREATE TABLE IF NOT EXISTS payment_requests (
+  id CHAR(36) PRIMARY KEY,
+  requester_id CHAR(36) NOT NULL,
+  payer_id CHAR(36) NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  status SMALLINT NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
   updated_at TIMESTAMP WITH TIME ZONE NULL
 );
 
 CREATE TABLE IF NOT EXISTS histories (
   id CHAR(36) PRIMARY KEY,
+  journal_entry_id CHAR(36) NOT NULL,
   user_id CHAR(36) NOT NULL,
   target_user_id CHAR(36) NOT NULL,
-  amount NUMERIC NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  -- Currency of amount and fee. A conversion is written in the currency it converts from.
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
   "type" SMALLINT NOT NULL,
   notes VARCHAR NOT NULL,
+  -- Fee charged on top of amount by the transfer or top-up of this history, the fee itself is a history of type 3.
+  fee NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  -- Journal entry of the transfer a refund history reverses or of the operation a fee history is charged on, NULL
+  -- for every other history.
+  reference_id CHAR(36) NULL,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 );
 
+-- Fees of an operation, the journal entry type it posts, in a currency with an amount in [min_amount, max_amount).
+-- A max_amount of 0 is unbounded and the matching rule with the highest min_amount wins. The fee is flat_fee plus
+-- rate_bps basis points of the amount, free for the first free_per_month operations of the user in a calendar month.
+CREATE TABLE IF NOT EXISTS fee_rules (
+  id BIGSERIAL PRIMARY KEY,
+  operation SMALLINT NOT NULL,
+  currency CHAR(3) NOT NULL,
+  min_amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  max_amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  flat_fee NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  rate_bps INT NOT NULL DEFAULT 0,
+  free_per_month INT NOT NULL DEFAULT 0,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Limits of a user tier in a currency, overriding the built-in limits of the tier in that currency once it has any
+-- row. A max_amount or max_count of 0 is unlimited, and a transaction period only caps the amount of a single
+-- operation.
+CREATE TABLE IF NOT EXISTS tier_limits (
+  id BIGSERIAL PRIMARY KEY,
+  tier SMALLINT NOT NULL,
+  currency CHAR(3) NOT NULL,
+  operation SMALLINT NOT NULL,
+  period VARCHAR(16) NOT NULL,
+  max_amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  max_count BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
 CREATE TABLE IF NOT EXISTS history_summaries (
   id VARCHAR PRIMARY KEY,
   user_id CHAR(36) NOT NULL,
   target_user_id CHAR(36),
-  amount NUMERIC NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  "type" SMALLINT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Summaries of history_summaries per calendar bucket of the business time zone. bucket_start is the first
+-- day of the day, ISO week (Monday) or month bucket. Old day buckets are compacted away since the week and
+-- month buckets already hold their totals.
+CREATE TABLE IF NOT EXISTS history_summary_buckets (
+  user_id CHAR(36) NOT NULL,
+  target_user_id CHAR(36) NOT NULL,
+  "type" SMALLINT NOT NULL,
+  period VARCHAR(5) NOT NULL,
+  bucket_start DATE NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL,
+  PRIMARY KEY (user_id, target_user_id, "type", period, bucket_start)
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  user_id CHAR(36) NOT NULL,
+  key VARCHAR(255) NOT NULL,
+  fingerprint VARCHAR NOT NULL,
+  response_code SMALLINT NOT NULL,
+  response_body VARCHAR NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, key)
+);
+
+-- Double-entry ledger. Every money movement is a journal entry whose postings sum to zero;
+-- balances and histories are projections written in the same transaction.
+CREATE TABLE IF NOT EXISTS journal_entries (
+  id CHAR(36) PRIMARY KEY,
   "type" SMALLINT NOT NULL,
+  description VARCHAR NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS postings (
+  id CHAR(36) PRIMARY KEY,
+  journal_entry_id CHAR(36) NOT NULL REFERENCES journal_entries (id),
+  account_id VARCHAR NOT NULL,
+  amount NUMERIC(20, 3) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Events written in the transaction of the change they describe, see entity.OutboxEvent. id orders the events
+-- of a user, since they are written under the lock of the balance or user row. published_at is set by the relay
+-- once the event is on the event stream, and published events are deleted after 7 days.
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id BIGSERIAL PRIMARY KEY,
+  event_id CHAR(36) NOT NULL,
+  user_id CHAR(36) NOT NULL REFERENCES users (id),
+  "type" VARCHAR NOT NULL,
+  version SMALLINT NOT NULL,
+  payload JSONB NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  published_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- URLs users are sent their events at. event_types is a comma separated list of event types, and secret signs
+-- every delivery.
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+  id CHAR(36) PRIMARY KEY,
+  user_id CHAR(36) NOT NULL REFERENCES users (id),
+  url VARCHAR NOT NULL,
+  secret VARCHAR NOT NULL,
+  event_types VARCHAR NOT NULL,
+  status SMALLINT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- One row per event per endpoint, kept as the delivery log. payload is the exact body sent on every attempt.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id CHAR(36) PRIMARY KEY,
+  endpoint_id CHAR(36) NOT NULL REFERENCES webhook_endpoints (id),
+  event_id CHAR(36) NOT NULL,
+  event_type VARCHAR NOT NULL,
+  payload TEXT NOT NULL,
+  status SMALLINT NOT NULL,
+  attempt INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  last_status_code INT NULL,
+  last_error VARCHAR NULL,
+  delivered_at TIMESTAMP WITH TIME ZONE NULL,
+  lease_id CHAR(36) NULL,
+  lease_expires_at TIMESTAMP WITH TIME ZONE NULL,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
   updated_at TIMESTAMP WITH TIME ZONE NULL
 );
 
-CREATE UNIQUE INDEX users_username_unq ON users (username); 
-CREATE INDEX histories_user_id_created_at_desc_idx ON histories (user_id, created_at DESC);
-CREATE INDEX history_summaries_user_id_amount_desc_type_idx ON history_summaries (user_id, amount DESC, type);
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced() RETURNS TRIGGER AS $$
+BEGIN
+  IF (SELECT SUM(amount) FROM postings WHERE journal_entry_id = NEW.journal_entry_id) <> 0 THEN
+    RAISE EXCEPTION 'journal entry % is not balanced', NEW.journal_entry_id;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Checked at commit time so every posting of an entry is visible to the check.
+DROP TRIGGER IF EXISTS postings_balanced_trg ON postings;
+CREATE CONSTRAINT TRIGGER postings_balanced_trg
+  AFTER INSERT ON postings
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balanced();
+
+-- Balance of every ledger account, including system accounts, derived from postings only.
+CREATE OR REPLACE VIEW account_balances AS
+  SELECT account_id, SUM(amount) AS amount FROM postings GROUP BY account_id;
+
+-- Databases created before the ledger get the columns added to their tables since. A balance kept before the ledger
+-- is put on it with an opening entry against system:funding, so it stays the sum of the postings of its account, and
+-- a history written before the ledger keeps its own id as its journal entry. Every statement below can run again.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS tier SMALLINT NOT NULL DEFAULT 1,
+  ADD COLUMN IF NOT EXISTS role SMALLINT NOT NULL DEFAULT 1,
+  ADD COLUMN IF NOT EXISTS status SMALLINT NOT NULL DEFAULT 1,
+  ADD COLUMN IF NOT EXISTS status_reason VARCHAR NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE NULL,
+  ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE balances
+  ALTER COLUMN amount TYPE NUMERIC(20, 3),
+  ADD COLUMN IF NOT EXISTS held_amount NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE histories
+  ALTER COLUMN amount TYPE NUMERIC(20, 3),
+  ADD COLUMN IF NOT EXISTS journal_entry_id CHAR(36) NULL,
+  ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  ADD COLUMN IF NOT EXISTS fee NUMERIC(20, 3) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS reference_id CHAR(36) NULL;
+
+UPDATE histories SET journal_entry_id = id WHERE journal_entry_id IS NULL;
+ALTER TABLE histories ALTER COLUMN journal_entry_id SET NOT NULL;
+
+ALTER TABLE history_summaries ALTER COLUMN amount TYPE NUMERIC(20, 3);
+
+-- Opening entries are of type 8, see enum.OPENING_BALANCE. Their ids are derived from the user so they are written
+-- once, and only for balances whose account has no postings yet.
+INSERT INTO journal_entries (id, "type", description, created_at)
+SELECT
+  md5('opening:' || b.user_id)::uuid::text,
+  8,
+  'Opening balance',
+  b.created_at
+FROM
+  balances b
+WHERE
+  b.amount <> 0
+  AND NOT EXISTS (SELECT 1 FROM postings p WHERE p.account_id = b.user_id)
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO postings (id, journal_entry_id, account_id, amount, created_at)
+SELECT
+  md5('opening:' || b.user_id || ':' || a.account_id)::uuid::text,
+  je.id,
+  a.account_id,
+  a.amount,
+  b.created_at
+FROM
+  balances b
+  JOIN journal_entries je ON je.id = md5('opening:' || b.user_id)::uuid::text
+  CROSS JOIN LATERAL (VALUES (b.user_id::VARCHAR, b.amount), ('system:funding', -b.amount)) AS a (account_id, amount)
+WHERE
+  NOT EXISTS (SELECT 1 FROM postings p WHERE p.journal_entry_id = je.id);
+
+DROP INDEX IF EXISTS histories_user_id_created_at_desc_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_unq ON users (username); 
+CREATE INDEX IF NOT EXISTS histories_user_id_created_at_desc_id_desc_idx ON histories (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS histories_journal_entry_id_idx ON histories (journal_entry_id);
+CREATE INDEX IF NOT EXISTS histories_reference_id_idx ON histories (reference_id) WHERE reference_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS pockets_user_id_name_unq ON pockets (user_id, name);
+CREATE INDEX IF NOT EXISTS shared_wallet_members_user_id_idx ON shared_wallet_members (user_id);
+CREATE INDEX IF NOT EXISTS shared_wallet_histories_wallet_id_created_at_desc_idx ON shared_wallet_histories (wallet_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS holds_expires_at_active_idx ON holds (expires_at) WHERE status = 1;
+CREATE INDEX IF NOT EXISTS scheduled_transfers_next_run_at_active_idx ON scheduled_transfers (next_run_at) WHERE status = 1;
+CREATE INDEX IF NOT EXISTS scheduled_transfers_user_id_created_at_desc_idx ON scheduled_transfers (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS scheduled_transfer_runs_scheduled_transfer_id_created_at_desc_idx ON scheduled_transfer_runs (scheduled_transfer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS payment_requests_requester_id_created_at_desc_idx ON payment_requests (requester_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS payment_requests_payer_id_created_at_desc_idx ON payment_requests (payer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS fx_rates_base_currency_quote_currency_effective_at_desc_idx ON fx_rates (base_currency, quote_currency, effective_at DESC);
+CREATE INDEX IF NOT EXISTS fee_rules_operation_currency_active_idx ON fee_rules (operation, currency) WHERE active;
+CREATE UNIQUE INDEX IF NOT EXISTS tier_limits_tier_currency_operation_period_unq ON tier_limits (tier, currency, operation, period);
+CREATE INDEX IF NOT EXISTS history_summaries_user_id_amount_desc_type_idx ON history_summaries (user_id, amount DESC, type);
+CREATE INDEX IF NOT EXISTS history_summary_buckets_period_bucket_start_idx ON history_summary_buckets (period, bucket_start);
+CREATE INDEX IF NOT EXISTS postings_journal_entry_id_idx ON postings (journal_entry_id);
+CREATE INDEX IF NOT EXISTS postings_account_id_created_at_desc_idx ON postings (account_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS user_status_changes_user_id_created_at_desc_idx ON user_status_changes (user_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS refresh_tokens_token_hash_unq ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS outbox_events_event_id_unq ON outbox_events (event_id);
+CREATE INDEX IF NOT EXISTS outbox_events_id_unpublished_idx ON outbox_events (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_events_published_at_idx ON outbox_events (published_at) WHERE published_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS webhook_endpoints_user_id_created_at_desc_idx ON webhook_endpoints (user_id, created_at DESC) WHERE status = 1;
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_endpoint_id_event_id_unq ON webhook_deliveries (endpoint_id, event_id);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_next_attempt_at_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 1;
+CREATE INDEX IF NOT EXISTS webhook_deliveries_endpoint_id_created_at_desc_idx ON webhook_deliveries (endpoint_id, created_at DESC);
@@ -0,0 +1,75 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Error is an application error that can be rendered to clients. Code is a stable machine-readable
+// identifier, Message is meant for humans and Err keeps the underlying cause for logging only.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details map[string]interface{}
+	Err     error
+}
+
+var (
+	ErrInvalidRequest = New(http.StatusBadRequest, "invalid_request", "request is malformed")
+	ErrUnauthorized   = New(http.StatusUnauthorized, "unauthorized", "authentication is required")
+	ErrForbidden      = New(http.StatusForbidden, "forbidden", "permission is required")
+	ErrInternal       = New(http.StatusInternalServerError, "internal_error", "internal server error")
+	ErrDependency     = New(http.StatusBadGateway, "dependency_error", "a dependency failed, please retry later")
+)
+
+func New(status int, code string, message string) *Error {
+	return &Error{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an application error with the same code, so errors.Is matches
+// copies made by Wrap and WithDetails against the original.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of e with err recorded as the cause.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithDetails returns a copy of e carrying details rendered next to the code and message.
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// From returns the application error in err's chain, or ErrInternal wrapping err when there is none.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return ErrInternal.Wrap(err)
+}
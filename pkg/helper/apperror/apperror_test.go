@@ -0,0 +1,103 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{
+			name: "without cause",
+			err:  ErrInvalidRequest,
+			want: "invalid_request: request is malformed",
+		},
+		{
+			name: "with cause",
+			err:  ErrDependency.Wrap(fmt.Errorf("foo")),
+			want: "dependency_error: a dependency failed, please retry later: foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_Wrap(t *testing.T) {
+	cause := fmt.Errorf("foo")
+	err := ErrDependency.Wrap(cause)
+
+	if ErrDependency.Err != nil {
+		t.Errorf("Error.Wrap() modified the original error")
+	}
+	if !errors.Is(err, ErrDependency) {
+		t.Errorf("errors.Is(err, ErrDependency) = false, want true")
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("errors.Is(err, cause) = false, want true")
+	}
+	if errors.Is(err, ErrInternal) {
+		t.Errorf("errors.Is(err, ErrInternal) = true, want false")
+	}
+}
+
+func TestError_WithDetails(t *testing.T) {
+	details := map[string]interface{}{
+		"foo": "bar",
+	}
+	err := ErrInvalidRequest.WithDetails(details)
+
+	if ErrInvalidRequest.Details != nil {
+		t.Errorf("Error.WithDetails() modified the original error")
+	}
+	if !reflect.DeepEqual(err.Details, details) {
+		t.Errorf("Error.WithDetails() = %v, want %v", err.Details, details)
+	}
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "application error",
+			err:        ErrUnauthorized,
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "unauthorized",
+		},
+		{
+			name:       "wrapped application error",
+			err:        fmt.Errorf("bar: %w", ErrDependency.Wrap(fmt.Errorf("foo"))),
+			wantStatus: http.StatusBadGateway,
+			wantCode:   "dependency_error",
+		},
+		{
+			name:       "unknown error",
+			err:        fmt.Errorf("foo"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal_error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := From(tt.err)
+			if got.Status != tt.wantStatus || got.Code != tt.wantCode {
+				t.Errorf("From() = %v %v, want %v %v", got.Status, got.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
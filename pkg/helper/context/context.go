@@ -4,12 +4,14 @@ import (
 	"context"
 
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/lib/token"
 )
 
 type ctx string
 
 const (
-	contextAuth ctx = "context.auth"
+	contextAuth        ctx = "context.auth"
+	contextTokenClaims ctx = "context.token_claims"
 )
 
 func SetAuth(ctx context.Context, user entity.User) context.Context {
@@ -20,3 +22,12 @@ func GetAuth(ctx context.Context) entity.User {
 	user, _ := ctx.Value(contextAuth).(entity.User)
 	return user
 }
+
+func SetTokenClaims(ctx context.Context, claims token.Claims) context.Context {
+	return context.WithValue(ctx, contextTokenClaims, claims)
+}
+
+func GetTokenClaims(ctx context.Context) token.Claims {
+	claims, _ := ctx.Value(contextTokenClaims).(token.Claims)
+	return claims
+}
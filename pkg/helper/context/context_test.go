@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/kevinsudut/wallet-system/app/entity"
+	"github.com/kevinsudut/wallet-system/pkg/lib/token"
 )
 
 func TestSetAuth(t *testing.T) {
@@ -59,3 +60,61 @@ func TestGetAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestSetTokenClaims(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		claims token.Claims
+	}
+	tests := []struct {
+		name string
+		args args
+		want context.Context
+	}{
+		{
+			args: args{
+				ctx:    context.Background(),
+				claims: token.Claims{},
+			},
+			want: context.WithValue(context.Background(), contextTokenClaims, token.Claims{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SetTokenClaims(tt.args.ctx, tt.args.claims); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetTokenClaims() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTokenClaims(t *testing.T) {
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name string
+		args args
+		want token.Claims
+	}{
+		{
+			args: args{
+				ctx: context.Background(),
+			},
+			want: token.Claims{},
+		},
+		{
+			args: args{
+				ctx: SetTokenClaims(context.Background(), token.Claims{Id: "id"}),
+			},
+			want: token.Claims{Id: "id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetTokenClaims(tt.args.ctx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTokenClaims() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package currency
+
+import (
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{
+			name: "success",
+			code: "USD",
+			want: true,
+		},
+		{
+			name: "lower case",
+			code: "usd",
+		},
+		{
+			name: "unknown",
+			code: "XYZ",
+		},
+		{
+			name: "empty",
+			code: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.code); got != tt.want {
+				t.Errorf("Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExponent(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want int
+	}{
+		{
+			name: "two decimals",
+			code: "USD",
+			want: 2,
+		},
+		{
+			name: "no decimals",
+			code: "JPY",
+			want: 0,
+		},
+		{
+			name: "three decimals",
+			code: "KWD",
+			want: 3,
+		},
+		{
+			name: "unknown",
+			code: "XYZ",
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Exponent(tt.code); got != tt.want {
+				t.Errorf("Exponent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{
+			name: "success",
+			env:  "USD",
+			want: "USD",
+		},
+		{
+			name: "success unset",
+			env:  "",
+			want: "IDR",
+		},
+		{
+			name: "success invalid",
+			env:  "dollar",
+			want: "IDR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEFAULT_CURRENCY", tt.env)
+			if got := Default(); got != tt.want {
+				t.Errorf("Default() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package currency
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+// Rate is an exchange rate stored as an integer number of 1/10^RateScale, which is how many units of the quote
+// currency one unit of the base currency buys.
+type Rate int64
+
+const (
+	RateScale = 8
+
+	// RateUnit is a rate of 1 expressed in Rate.
+	RateUnit Rate = 100000000
+)
+
+var (
+	ErrInvalidRate = fmt.Errorf("invalid rate")
+	ErrInexactRate = fmt.Errorf("rate cannot be represented exactly")
+)
+
+var (
+	rateUnitRat = big.NewRat(int64(RateUnit), 1)
+	maxRateRat  = new(big.Rat).SetInt64(math.MaxInt64)
+)
+
+// ParseRate converts a positive decimal string such as "16250" or "0.0000615" into a Rate, rejecting values with
+// more than RateScale fractional digits.
+func ParseRate(s string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 {
+		return 0, ErrInvalidRate
+	}
+
+	r.Mul(r, rateUnitRat)
+	if !r.IsInt() {
+		return 0, ErrInexactRate
+	}
+
+	if r.Cmp(maxRateRat) > 0 {
+		return 0, ErrInvalidRate
+	}
+
+	return Rate(r.Num().Int64()), nil
+}
+
+// Convert returns amount of the base currency in the quote currency, rounded half up to the minor unit of a
+// currency with exponent decimals.
+func (r Rate) Convert(amount money.Money, exponent int) money.Money {
+	step := big.NewInt(int64(money.MinorUnit(exponent)))
+	divisor := new(big.Int).Mul(big.NewInt(int64(RateUnit)), step)
+
+	converted := new(big.Int).Mul(big.NewInt(int64(amount)), big.NewInt(int64(r)))
+	converted.Add(converted, new(big.Int).Quo(divisor, big.NewInt(2)))
+	converted.Quo(converted, divisor)
+	converted.Mul(converted, step)
+
+	return money.Money(converted.Int64())
+}
+
+func (r Rate) String() string {
+	major := int64(r) / int64(RateUnit)
+	minor := int64(r) % int64(RateUnit)
+	if minor == 0 {
+		return strconv.FormatInt(major, 10)
+	}
+
+	return strings.TrimRight(fmt.Sprintf("%d.%0*d", major, RateScale, minor), "0")
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+
+	if strings.HasPrefix(s, `"`) {
+		return ErrInvalidRate
+	}
+
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = v
+	return nil
+}
+
+func (r *Rate) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return r.scanString(string(v))
+	case string:
+		return r.scanString(v)
+	}
+
+	return fmt.Errorf("rate: unsupported scan type %T", src)
+}
+
+func (r *Rate) scanString(s string) error {
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = v
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
@@ -0,0 +1,232 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/money"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Rate
+		wantErr error
+	}{
+		{
+			name: "integer",
+			s:    "16250",
+			want: 1625000000000,
+		},
+		{
+			name: "fraction",
+			s:    "0.0000615",
+			want: 6150,
+		},
+		{
+			name:    "error inexact",
+			s:       "0.000000001",
+			wantErr: ErrInexactRate,
+		},
+		{
+			name:    "error zero",
+			s:       "0",
+			wantErr: ErrInvalidRate,
+		},
+		{
+			name:    "error negative",
+			s:       "-1",
+			wantErr: ErrInvalidRate,
+		},
+		{
+			name:    "error not a number",
+			s:       "abc",
+			wantErr: ErrInvalidRate,
+		},
+		{
+			name:    "error range",
+			s:       "1e20",
+			wantErr: ErrInvalidRate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRate(tt.s)
+			if err != tt.wantErr {
+				t.Errorf("ParseRate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRate_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        Rate
+		amount   money.Money
+		exponent int
+		want     money.Money
+	}{
+		{
+			name:     "success",
+			r:        1625000000000,
+			amount:   10000,
+			exponent: 2,
+			want:     162500000,
+		},
+		{
+			name:     "round half up without decimals",
+			r:        15050000000,
+			amount:   10250,
+			exponent: 0,
+			want:     1543000,
+		},
+		{
+			name:     "round half up to three decimals",
+			r:        30745000,
+			amount:   10000,
+			exponent: 3,
+			want:     3075,
+		},
+		{
+			name:     "round down",
+			r:        6150,
+			amount:   700000,
+			exponent: 2,
+			want:     40,
+		},
+		{
+			name:     "too small",
+			r:        6150,
+			amount:   1000,
+			exponent: 2,
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Convert(tt.amount, tt.exponent); got != tt.want {
+				t.Errorf("Rate.Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRate_String(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Rate
+		want string
+	}{
+		{
+			name: "integer",
+			r:    1625000000000,
+			want: "16250",
+		},
+		{
+			name: "fraction",
+			r:    6150,
+			want: "0.0000615",
+		},
+		{
+			name: "mixed",
+			r:    150000000,
+			want: "1.5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.String(); got != tt.want {
+				t.Errorf("Rate.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRate_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       []byte
+		want    Rate
+		wantErr bool
+	}{
+		{
+			name: "number",
+			b:    []byte(`16250`),
+			want: 1625000000000,
+		},
+		{
+			name: "fraction",
+			b:    []byte(`0.0000615`),
+			want: 6150,
+		},
+		{
+			name: "null",
+			b:    []byte(`null`),
+			want: 0,
+		},
+		{
+			name:    "error string",
+			b:       []byte(`"16250"`),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Rate
+			if err := got.UnmarshalJSON(tt.b); (err != nil) != tt.wantErr {
+				t.Errorf("Rate.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Rate.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRate_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Rate
+		wantErr bool
+	}{
+		{
+			name: "bytes",
+			src:  []byte("16250.00000000"),
+			want: 1625000000000,
+		},
+		{
+			name: "string",
+			src:  "0.00006150",
+			want: 6150,
+		},
+		{
+			name:    "error type",
+			src:     int64(1),
+			wantErr: true,
+		},
+		{
+			name:    "error value",
+			src:     "abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Rate
+			if err := got.Scan(tt.src); (err != nil) != tt.wantErr {
+				t.Errorf("Rate.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Rate.Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
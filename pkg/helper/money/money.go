@@ -0,0 +1,153 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount stored as an integer number of thousandths of the major unit, the smallest minor unit
+// of any ISO 4217 currency. Amounts of currencies with a larger minor unit are kept multiples of it, see IsExact.
+type Money int64
+
+const (
+	Scale = 3
+
+	// Unit is one major unit expressed in thousandths.
+	Unit Money = 1000
+)
+
+var (
+	ErrInvalidAmount = fmt.Errorf("invalid amount")
+	ErrInexactAmount = fmt.Errorf("amount cannot be represented exactly")
+	ErrAmountRange   = fmt.Errorf("amount out of range")
+)
+
+var (
+	unitRat = big.NewRat(int64(Unit), 1)
+	minRat  = new(big.Rat).SetInt64(math.MinInt64)
+	maxRat  = new(big.Rat).SetInt64(math.MaxInt64)
+)
+
+// Parse converts a decimal string such as "1500", "10.5" or "1e3" into Money,
+// rejecting values with more than Scale fractional digits.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrInvalidAmount
+	}
+
+	r.Mul(r, unitRat)
+	if !r.IsInt() {
+		return 0, ErrInexactAmount
+	}
+
+	if r.Cmp(minRat) < 0 || r.Cmp(maxRat) > 0 {
+		return 0, ErrAmountRange
+	}
+
+	return Money(r.Num().Int64()), nil
+}
+
+func (m Money) String() string {
+	sign := ""
+	abs := uint64(m)
+	if m < 0 {
+		sign = "-"
+		abs = uint64(-m)
+	}
+
+	major := abs / uint64(Unit)
+	minor := abs % uint64(Unit)
+	if minor == 0 {
+		return sign + strconv.FormatUint(major, 10)
+	}
+
+	// Trailing zeros are dropped down to the two decimals most currencies have.
+	fraction := strings.TrimRight(fmt.Sprintf("%0*d", Scale, minor), "0")
+	if len(fraction) < 2 {
+		fraction += strings.Repeat("0", 2-len(fraction))
+	}
+
+	return fmt.Sprintf("%s%d.%s", sign, major, fraction)
+}
+
+// IsExact reports whether m has no more than exponent decimals.
+func (m Money) IsExact(exponent int) bool {
+	return m%MinorUnit(exponent) == 0
+}
+
+// MinorUnit returns the minor unit of a currency with exponent decimals, in thousandths.
+func MinorUnit(exponent int) Money {
+	step := Money(1)
+	for i := exponent; i < Scale; i++ {
+		step *= 10
+	}
+
+	return step
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+
+	if strings.HasPrefix(s, `"`) {
+		return ErrInvalidAmount
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		if v > math.MaxInt64/int64(Unit) || v < math.MinInt64/int64(Unit) {
+			return ErrAmountRange
+		}
+		*m = Money(v) * Unit
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return fmt.Errorf("money: unsupported scan type %T", src)
+	}
+
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,330 @@
+package money
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Money
+		wantErr error
+	}{
+		{
+			name: "integer",
+			args: args{
+				s: "1500",
+			},
+			want: 1500000,
+		},
+		{
+			name: "fraction",
+			args: args{
+				s: "10.5",
+			},
+			want: 10500,
+		},
+		{
+			name: "thousandths",
+			args: args{
+				s: "10.005",
+			},
+			want: 10005,
+		},
+		{
+			name: "trailing zeros",
+			args: args{
+				s: "10.5000",
+			},
+			want: 10500,
+		},
+		{
+			name: "negative",
+			args: args{
+				s: "-0.01",
+			},
+			want: -10,
+		},
+		{
+			name: "exponent",
+			args: args{
+				s: "1e3",
+			},
+			want: 1000000,
+		},
+		{
+			name: "error inexact",
+			args: args{
+				s: "10.0005",
+			},
+			wantErr: ErrInexactAmount,
+		},
+		{
+			name: "error out of range",
+			args: args{
+				s: "1e30",
+			},
+			wantErr: ErrAmountRange,
+		},
+		{
+			name: "error invalid",
+			args: args{
+				s: "abc",
+			},
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name: "error empty",
+			args: args{
+				s: "",
+			},
+			wantErr: ErrInvalidAmount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.s)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Money
+		want string
+	}{
+		{
+			name: "integer",
+			m:    1500000,
+			want: "1500",
+		},
+		{
+			name: "fraction",
+			m:    10050,
+			want: "10.05",
+		},
+		{
+			name: "thousandths",
+			m:    10005,
+			want: "10.005",
+		},
+		{
+			name: "negative",
+			m:    -10,
+			want: "-0.01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.String(); got != tt.want {
+				t.Errorf("Money.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	type args struct {
+		b []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Money
+		wantErr bool
+	}{
+		{
+			name: "number",
+			args: args{
+				b: []byte(`1000.25`),
+			},
+			want: 1000250,
+		},
+		{
+			name: "null",
+			args: args{
+				b: []byte(`null`),
+			},
+			want: 0,
+		},
+		{
+			name: "error string",
+			args: args{
+				b: []byte(`"1000"`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "error inexact",
+			args: args{
+				b: []byte(`0.0001`),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			err := m.UnmarshalJSON(tt.args.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Money.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if m != tt.want {
+				t.Errorf("Money.UnmarshalJSON() = %v, want %v", m, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	type args struct {
+		src interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Money
+		wantErr bool
+	}{
+		{
+			name: "numeric bytes",
+			args: args{
+				src: []byte("150000.10"),
+			},
+			want: 150000100,
+		},
+		{
+			name: "string",
+			args: args{
+				src: "25",
+			},
+			want: 25000,
+		},
+		{
+			name: "int64",
+			args: args{
+				src: int64(7),
+			},
+			want: 7000,
+		},
+		{
+			name: "nil",
+			args: args{
+				src: nil,
+			},
+			want: 0,
+		},
+		{
+			name: "error unsupported type",
+			args: args{
+				src: 1.5,
+			},
+			wantErr: true,
+		},
+		{
+			name: "error inexact",
+			args: args{
+				src: []byte("0.1255"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			err := m.Scan(tt.args.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Money.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if m != tt.want {
+				t.Errorf("Money.Scan() = %v, want %v", m, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Value(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		want    driver.Value
+		wantErr bool
+	}{
+		{
+			m:    10500,
+			want: "10.50",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Value()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Money.Value() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Money.Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_IsExact(t *testing.T) {
+	type args struct {
+		exponent int
+	}
+	tests := []struct {
+		name string
+		m    Money
+		args args
+		want bool
+	}{
+		{
+			name: "exact",
+			m:    10050,
+			args: args{
+				exponent: 2,
+			},
+			want: true,
+		},
+		{
+			name: "too many decimals",
+			m:    10005,
+			args: args{
+				exponent: 2,
+			},
+			want: false,
+		},
+		{
+			name: "fraction of a currency without decimals",
+			m:    1500,
+			args: args{
+				exponent: 0,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.IsExact(tt.args.exponent); got != tt.want {
+				t.Errorf("Money.IsExact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
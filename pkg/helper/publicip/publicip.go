@@ -0,0 +1,54 @@
+package publicip
+
+import (
+	"fmt"
+	"net/netip"
+	"syscall"
+)
+
+var ErrNotPublic = fmt.Errorf("address is not public")
+
+// reserved are the ranges netip does not classify that still never reach the public internet: "this network",
+// carrier-grade NAT, IETF protocol assignments, benchmarking, the former class E and NAT64, which can map to any
+// IPv4 address.
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// IsPublic reports whether addr is a unicast address on the public internet. Loopback, private, link-local (which
+// includes the cloud metadata address 169.254.169.254), unspecified, multicast and reserved addresses are not, and
+// an IPv4-mapped IPv6 address is judged by its IPv4 address.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range reserved {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Control refuses connections to addresses that are not public, as the Control of a net.Dialer. It runs on the
+// resolved address, so a host name that resolves to an internal address is refused too.
+func Control(network string, address string, c syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !IsPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrNotPublic, addrPort.Addr())
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package publicip
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	type args struct {
+		addr netip.Addr
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "public ipv4",
+			args: args{
+				addr: netip.MustParseAddr("93.184.216.34"),
+			},
+			want: true,
+		},
+		{
+			name: "public ipv6",
+			args: args{
+				addr: netip.MustParseAddr("2606:2800:220:1:248:1893:25c8:1946"),
+			},
+			want: true,
+		},
+		{
+			name: "loopback",
+			args: args{
+				addr: netip.MustParseAddr("127.0.0.1"),
+			},
+			want: false,
+		},
+		{
+			name: "loopback ipv6",
+			args: args{
+				addr: netip.MustParseAddr("::1"),
+			},
+			want: false,
+		},
+		{
+			name: "private",
+			args: args{
+				addr: netip.MustParseAddr("10.0.0.1"),
+			},
+			want: false,
+		},
+		{
+			name: "private ipv6",
+			args: args{
+				addr: netip.MustParseAddr("fd00::1"),
+			},
+			want: false,
+		},
+		{
+			name: "metadata",
+			args: args{
+				addr: netip.MustParseAddr("169.254.169.254"),
+			},
+			want: false,
+		},
+		{
+			name: "unspecified",
+			args: args{
+				addr: netip.MustParseAddr("0.0.0.0"),
+			},
+			want: false,
+		},
+		{
+			name: "carrier-grade nat",
+			args: args{
+				addr: netip.MustParseAddr("100.64.0.1"),
+			},
+			want: false,
+		},
+		{
+			name: "ipv4-mapped loopback",
+			args: args{
+				addr: netip.MustParseAddr("::ffff:127.0.0.1"),
+			},
+			want: false,
+		},
+		{
+			name: "invalid",
+			args: args{
+				addr: netip.Addr{},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPublic(tt.args.addr); got != tt.want {
+				t.Errorf("IsPublic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControl(t *testing.T) {
+	type args struct {
+		network string
+		address string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "public",
+			args: args{
+				network: "tcp4",
+				address: "93.184.216.34:443",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "not public",
+			args: args{
+				network: "tcp4",
+				address: "169.254.169.254:80",
+			},
+			wantErr: ErrNotPublic,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Control(tt.args.network, tt.args.address, nil); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Control() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,10 +4,31 @@ import (
 	"net/http"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
 )
 
-func WriteErrorResponse(w http.ResponseWriter, statusCode int) {
-	w.WriteHeader(statusCode)
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// WriteErrorResponse renders err as a JSON error body. Errors that are not application errors
+// are reported as internal errors so their message never reaches the client.
+func WriteErrorResponse(w http.ResponseWriter, err error) {
+	appErr := apperror.From(err)
+
+	WriteJsonResponse(w, appErr.Status, errorResponse{
+		Error: errorBody{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+			Details: appErr.Details,
+		},
+	})
 }
 
 func WriteJsonResponse(w http.ResponseWriter, statusCode int, content interface{}) {
@@ -1,30 +1,61 @@
 package response
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/apperror"
 )
 
 func TestWriteErrorResponse(t *testing.T) {
 	type args struct {
-		w          http.ResponseWriter
-		statusCode int
+		err error
 	}
 	tests := []struct {
-		name string
-		args args
+		name       string
+		args       args
+		wantStatus int
+		wantBody   string
 	}{
 		{
+			name: "application error",
 			args: args{
-				w:          httptest.NewRecorder(),
-				statusCode: 200,
+				err: apperror.ErrInvalidRequest.Wrap(fmt.Errorf("foo")),
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"invalid_request","message":"request is malformed"}}`,
+		},
+		{
+			name: "application error with details",
+			args: args{
+				err: apperror.ErrInvalidRequest.WithDetails(map[string]interface{}{
+					"field": "amount",
+				}),
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"invalid_request","message":"request is malformed","details":{"field":"amount"}}}`,
+		},
+		{
+			name: "unknown error",
+			args: args{
+				err: fmt.Errorf("foo"),
 			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":{"code":"internal_error","message":"internal server error"}}`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			WriteErrorResponse(tt.args.w, tt.args.statusCode)
+			w := httptest.NewRecorder()
+			WriteErrorResponse(w, tt.args.err)
+			if w.Code != tt.wantStatus {
+				t.Errorf("WriteErrorResponse() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("WriteErrorResponse() body = %v, want %v", w.Body.String(), tt.wantBody)
+			}
 		})
 	}
 }
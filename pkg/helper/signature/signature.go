@@ -0,0 +1,32 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// version prefixes every signature, so the scheme can change without receivers mistaking one scheme for another.
+const version = "v1="
+
+// Sign signs body sent at timestamp (in Unix seconds) with HMAC-SHA256 of "<timestamp>.<body>" under secret.
+// The timestamp is signed too, so a receiver that rejects old timestamps cannot be sent a captured request again.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return version + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body sent at timestamp under secret, in constant time.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, version) {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
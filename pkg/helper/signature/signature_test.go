@@ -0,0 +1,109 @@
+package signature
+
+import (
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	type args struct {
+		secret    string
+		timestamp int64
+		body      []byte
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "success",
+			args: args{
+				secret:    "secret",
+				timestamp: 1792324800,
+				body:      []byte(`{"id":"eid"}`),
+			},
+			want: "v1=69b8f7752206ee8d85a45cb697c1c3fb2bffcb582d93eafed198cc8559d482d3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.args.secret, tt.args.timestamp, tt.args.body); got != tt.want {
+				t.Errorf("Sign() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"eid"}`)
+
+	type args struct {
+		secret    string
+		timestamp int64
+		body      []byte
+		signature string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "valid",
+			args: args{
+				secret:    "secret",
+				timestamp: 1792324800,
+				body:      body,
+				signature: "v1=69b8f7752206ee8d85a45cb697c1c3fb2bffcb582d93eafed198cc8559d482d3",
+			},
+			want: true,
+		},
+		{
+			name: "other secret",
+			args: args{
+				secret:    "other",
+				timestamp: 1792324800,
+				body:      body,
+				signature: "v1=69b8f7752206ee8d85a45cb697c1c3fb2bffcb582d93eafed198cc8559d482d3",
+			},
+			want: false,
+		},
+		{
+			name: "other timestamp",
+			args: args{
+				secret:    "secret",
+				timestamp: 1792324801,
+				body:      body,
+				signature: "v1=69b8f7752206ee8d85a45cb697c1c3fb2bffcb582d93eafed198cc8559d482d3",
+			},
+			want: false,
+		},
+		{
+			name: "other body",
+			args: args{
+				secret:    "secret",
+				timestamp: 1792324800,
+				body:      []byte(`{"id":"other"}`),
+				signature: "v1=69b8f7752206ee8d85a45cb697c1c3fb2bffcb582d93eafed198cc8559d482d3",
+			},
+			want: false,
+		},
+		{
+			name: "without version",
+			args: args{
+				secret:    "secret",
+				timestamp: 1792324800,
+				body:      body,
+				signature: "69b8f7752206ee8d85a45cb697c1c3fb2bffcb582d93eafed198cc8559d482d3",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.args.secret, tt.args.timestamp, tt.args.body, tt.args.signature); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
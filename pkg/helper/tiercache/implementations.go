@@ -0,0 +1,275 @@
+package tiercache
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+// Load returns the value of key from the first tier that has it, or from fetch, which must return
+// Config.NotFound when the value does not exist. A stale value is returned right away and fetched again in the
+// background.
+func (l *Loader[K, T]) Load(ctx context.Context, key K, fetch func(ctx context.Context) (T, error)) (resp T, err error) {
+	cacheKey := l.config.Key(key)
+
+	e, err, _ := l.singleflight.DoSingleFlight(ctx, cacheKey, func() (interface{}, error) {
+		return l.load(ctx, cacheKey, fetch)
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	return e.(entry[T]).result(l.config.NotFound)
+}
+
+// Set stores value in every tier, and removes the copies of other instances.
+func (l *Loader[K, T]) Set(ctx context.Context, key K, value T) (err error) {
+	cacheKey := l.config.Key(key)
+	now := time.Now()
+	l.generations.change(cacheKey)
+
+	if l.config.Redis != nil {
+		err = l.setRedis(ctx, cacheKey, l.newEntry(value, false, l.config.RedisTTL, now), now)
+		if err != nil {
+			return err
+		}
+	}
+
+	if l.config.Local != nil {
+		err = l.config.Local.Invalidate(ctx, cacheKey)
+		if err != nil {
+			return err
+		}
+
+		l.setLocal(cacheKey, l.newEntry(value, false, l.config.LocalTTL, now))
+	}
+
+	return nil
+}
+
+// Invalidate removes key from every tier of every instance. A fetch of key in flight on this instance does not
+// cache what it read.
+func (l *Loader[K, T]) Invalidate(ctx context.Context, key K) (err error) {
+	cacheKey := l.config.Key(key)
+	l.generations.change(cacheKey)
+
+	if l.config.Redis != nil {
+		_, err = l.config.Redis.Delete(ctx, cacheKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	if l.config.Local != nil {
+		err = l.config.Local.Invalidate(ctx, cacheKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *Loader[K, T]) load(ctx context.Context, cacheKey string, fetch func(ctx context.Context) (T, error)) (entry[T], error) {
+	now := time.Now()
+
+	if l.config.Local != nil {
+		e, ok := l.getLocal(cacheKey)
+		l.count(tierLocal, ok)
+		if ok {
+			if e.stale(now) {
+				l.revalidate(cacheKey, fetch, false)
+			}
+			return e, nil
+		}
+	}
+
+	if l.config.Redis != nil {
+		e, ok := l.getRedis(ctx, cacheKey)
+		l.count(tierRedis, ok)
+		if ok {
+			if e.stale(now) {
+				l.revalidate(cacheKey, fetch, true)
+			} else {
+				l.setLocal(cacheKey, l.localEntry(e, now))
+			}
+			return e, nil
+		}
+	}
+
+	return l.fetch(ctx, cacheKey, fetch)
+}
+
+// revalidate refreshes a stale value in the background. A value that is only stale in the local tier is taken
+// from Redis when it is fresh there.
+func (l *Loader[K, T]) revalidate(cacheKey string, fetch func(ctx context.Context) (T, error), skipRedis bool) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), revalidateTimeout)
+		defer cancel()
+
+		_, err, _ := l.singleflight.DoSingleFlight(ctx, "revalidate:"+cacheKey, func() (interface{}, error) {
+			if !skipRedis && l.config.Redis != nil {
+				changes := l.generations.start(cacheKey)
+				e, ok := l.getRedis(ctx, cacheKey)
+				if ok && !e.stale(time.Now()) {
+					if l.generations.current(cacheKey, changes) {
+						l.setLocal(cacheKey, l.localEntry(e, time.Now()))
+					}
+					l.generations.done(cacheKey)
+					return e, nil
+				}
+				l.generations.done(cacheKey)
+			}
+
+			return l.fetch(ctx, cacheKey, fetch)
+		})
+		if err != nil {
+			log.Errorln("TierCache.revalidate", l.config.Name, cacheKey, err)
+		}
+	}()
+}
+
+func (l *Loader[K, T]) fetch(ctx context.Context, cacheKey string, fetch func(ctx context.Context) (T, error)) (entry[T], error) {
+	metrics.Add(l.config.Name+".fetch", 1)
+
+	changes := l.generations.start(cacheKey)
+	defer l.generations.done(cacheKey)
+
+	value, err := fetch(ctx)
+	notFound := l.config.NotFound != nil && errors.Is(err, l.config.NotFound)
+	if err != nil && !notFound {
+		return entry[T]{}, err
+	}
+
+	if notFound && l.config.NegativeTTL <= 0 {
+		return entry[T]{
+			NotFound: true,
+		}, nil
+	}
+
+	now := time.Now()
+	redisTTL, localTTL := l.config.RedisTTL, l.config.LocalTTL
+	if notFound {
+		redisTTL, localTTL = l.config.NegativeTTL, l.config.NegativeTTL
+	}
+
+	e := l.newEntry(value, notFound, redisTTL, now)
+
+	// The value may have been read before a concurrent Invalidate or Set, so it is not cached over the change.
+	if !l.generations.current(cacheKey, changes) {
+		metrics.Add(l.config.Name+".fetch.discarded", 1)
+		return e, nil
+	}
+
+	if l.config.Redis != nil {
+		err = l.setRedis(ctx, cacheKey, e, now)
+		if err != nil {
+			log.Errorln("TierCache.fetch.setRedis", cacheKey, err)
+		}
+	}
+
+	if l.config.Local != nil {
+		l.setLocal(cacheKey, l.newEntry(value, notFound, localTTL, now))
+	}
+
+	// A change made while the value was being stored may have been removed before it, so it is removed again.
+	if !l.generations.current(cacheKey, changes) {
+		metrics.Add(l.config.Name+".fetch.discarded", 1)
+		l.discard(ctx, cacheKey)
+	}
+
+	return e, nil
+}
+
+// discard removes a value cached by this instance over a change.
+func (l *Loader[K, T]) discard(ctx context.Context, cacheKey string) {
+	if l.config.Redis != nil {
+		_, err := l.config.Redis.Delete(ctx, cacheKey)
+		if err != nil {
+			log.Errorln("TierCache.discard.Delete", cacheKey, err)
+		}
+	}
+
+	if l.config.Local != nil {
+		l.config.Local.Delete(cacheKey)
+	}
+}
+
+func (l *Loader[K, T]) getLocal(cacheKey string) (entry[T], bool) {
+	item := l.config.Local.Get(cacheKey)
+	if item == nil || item.Expired() {
+		return entry[T]{}, false
+	}
+
+	e, ok := item.Value().(entry[T])
+	return e, ok
+}
+
+func (l *Loader[K, T]) setLocal(cacheKey string, e entry[T]) {
+	l.config.Local.Set(cacheKey, e, time.Until(e.FreshUntil)+l.config.StaleWhileRevalidate)
+}
+
+// localEntry copies an entry of Redis to the local tier, fresh for no longer than it is fresh in Redis.
+func (l *Loader[K, T]) localEntry(e entry[T], now time.Time) entry[T] {
+	ttl := l.config.LocalTTL
+	if e.NotFound {
+		ttl = l.config.NegativeTTL
+	}
+
+	local := l.newEntry(e.Value, e.NotFound, ttl, now)
+	if local.FreshUntil.After(e.FreshUntil) {
+		local.FreshUntil = e.FreshUntil
+	}
+
+	return local
+}
+
+func (l *Loader[K, T]) getRedis(ctx context.Context, cacheKey string) (entry[T], bool) {
+	json, err := l.config.Redis.Get(ctx, cacheKey)
+	if err != nil {
+		if !redis.IsNil(err) {
+			log.Errorln("TierCache.getRedis.Get", cacheKey, err)
+		}
+		return entry[T]{}, false
+	}
+
+	var e entry[T]
+	err = jsoniter.UnmarshalFromString(json, &e)
+	if err != nil || e.FreshUntil.IsZero() {
+		// Values written before the loader are not wrapped in an entry and are fetched again.
+		return entry[T]{}, false
+	}
+
+	return e, true
+}
+
+func (l *Loader[K, T]) setRedis(ctx context.Context, cacheKey string, e entry[T], now time.Time) error {
+	json, err := jsoniter.MarshalToString(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.config.Redis.SetEx(ctx, cacheKey, json, e.FreshUntil.Sub(now)+l.config.StaleWhileRevalidate)
+	return err
+}
+
+func (l *Loader[K, T]) newEntry(value T, notFound bool, ttl time.Duration, now time.Time) entry[T] {
+	return entry[T]{
+		Value:      value,
+		NotFound:   notFound,
+		FreshUntil: now.Add(l.jitter(ttl)),
+	}
+}
+
+func (l *Loader[K, T]) jitter(ttl time.Duration) time.Duration {
+	if l.config.Jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+
+	return ttl - time.Duration(rand.Float64()*l.config.Jitter*float64(ttl))
+}
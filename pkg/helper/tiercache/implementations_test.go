@@ -0,0 +1,461 @@
+package tiercache
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+	goredis "github.com/redis/go-redis/v9"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+type user struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func newTestLoader(mockRedis redis.RedisItf, local lrucache.LRUCacheItf) *Loader[string, user] {
+	return New[string, user](Config[string]{
+		Name:                 "user",
+		Key:                  func(id string) string { return "user:" + id },
+		Local:                local,
+		LocalTTL:             time.Minute,
+		Redis:                mockRedis,
+		RedisTTL:             time.Hour,
+		NotFound:             sql.ErrNoRows,
+		NegativeTTL:          time.Second * 30,
+		StaleWhileRevalidate: time.Minute,
+	})
+}
+
+func redisEntry(value user, notFound bool, freshUntil time.Time) string {
+	json, _ := jsoniter.MarshalToString(entry[user]{
+		Value:      value,
+		NotFound:   notFound,
+		FreshUntil: freshUntil,
+	})
+	return json
+}
+
+func TestLoader_Load(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	type args struct {
+		ctx context.Context
+		key string
+	}
+	tests := []struct {
+		name      string
+		local     func() lrucache.LRUCacheItf
+		args      args
+		fetch     func(ctx context.Context) (user, error)
+		wantResp  user
+		wantErr   error
+		wantLocal bool
+		mock      func()
+	}{
+		{
+			name: "success local hit",
+			local: func() lrucache.LRUCacheItf {
+				local := lrucache.Init()
+				local.Set("user:id", entry[user]{Value: user{Id: "id"}, FreshUntil: time.Now().Add(time.Minute)}, time.Minute)
+				return local
+			},
+			args: args{
+				ctx: context.Background(),
+				key: "id",
+			},
+			wantResp:  user{Id: "id"},
+			wantLocal: true,
+			mock:      func() {},
+		},
+		{
+			name:  "success redis hit",
+			local: lrucache.Init,
+			args: args{
+				ctx: context.Background(),
+				key: "id",
+			},
+			wantResp:  user{Id: "id"},
+			wantLocal: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return(redisEntry(user{Id: "id"}, false, time.Now().Add(time.Hour)), nil),
+				)
+			},
+		},
+		{
+			name:  "success fetch",
+			local: lrucache.Init,
+			args: args{
+				ctx: context.Background(),
+				key: "id",
+			},
+			fetch: func(ctx context.Context) (user, error) {
+				return user{Id: "id", Name: "name"}, nil
+			},
+			wantResp:  user{Id: "id", Name: "name"},
+			wantLocal: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return("", goredis.Nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), "user:id", gomock.Any(), time.Hour+time.Minute).Return("OK", nil),
+				)
+			},
+		},
+		{
+			name:  "success fetch over value written before the loader",
+			local: lrucache.Init,
+			args: args{
+				ctx: context.Background(),
+				key: "id",
+			},
+			fetch: func(ctx context.Context) (user, error) {
+				return user{Id: "id"}, nil
+			},
+			wantResp:  user{Id: "id"},
+			wantLocal: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return(`{"id":"id"}`, nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), "user:id", gomock.Any(), gomock.Any()).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+		{
+			name:  "error not found cached",
+			local: lrucache.Init,
+			args: args{
+				ctx: context.Background(),
+				key: "id",
+			},
+			fetch: func(ctx context.Context) (user, error) {
+				return user{}, sql.ErrNoRows
+			},
+			wantResp:  user{},
+			wantErr:   sql.ErrNoRows,
+			wantLocal: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return("", fmt.Errorf("foo")),
+					mockRedis.EXPECT().SetEx(gomock.Any(), "user:id", gomock.Any(), time.Second*30+time.Minute).Return("OK", nil),
+				)
+			},
+		},
+		{
+			name:  "error not found in redis",
+			local: lrucache.Init,
+			args: args{
+				ctx: context.Background(),
+				key: "id",
+			},
+			wantResp:  user{},
+			wantErr:   sql.ErrNoRows,
+			wantLocal: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return(redisEntry(user{}, true, time.Now().Add(time.Second*30)), nil),
+				)
+			},
+		},
+		{
+			name:  "error fetch",
+			local: lrucache.Init,
+			args: args{
+				ctx: context.Background(),
+				key: "id",
+			},
+			fetch: func(ctx context.Context) (user, error) {
+				return user{}, fmt.Errorf("foo")
+			},
+			wantResp:  user{},
+			wantErr:   fmt.Errorf("foo"),
+			wantLocal: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return("", goredis.Nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			local := tt.local()
+			l := newTestLoader(mockRedis, local)
+			gotResp, err := l.Load(tt.args.ctx, tt.args.key, tt.fetch)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("Loader.Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotResp, tt.wantResp) {
+				t.Errorf("Loader.Load() = %v, want %v", gotResp, tt.wantResp)
+			}
+			if gotLocal := local.Get("user:"+tt.args.key) != nil; gotLocal != tt.wantLocal {
+				t.Errorf("Loader.Load() cached locally = %v, want %v", gotLocal, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_staleWhileRevalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	revalidated := make(chan struct{})
+	gomock.InOrder(
+		mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return(redisEntry(user{Id: "id", Name: "old"}, false, time.Now().Add(-time.Second)), nil),
+		mockRedis.EXPECT().SetEx(gomock.Any(), "user:id", gomock.Any(), time.Hour+time.Minute).DoAndReturn(func(ctx context.Context, key string, value interface{}, expiration time.Duration) (string, error) {
+			close(revalidated)
+			return "OK", nil
+		}),
+	)
+
+	local := lrucache.Init()
+	l := newTestLoader(mockRedis, local)
+	gotResp, err := l.Load(context.Background(), "id", func(ctx context.Context) (user, error) {
+		return user{Id: "id", Name: "new"}, nil
+	})
+	if err != nil || gotResp.Name != "old" {
+		t.Fatalf("Loader.Load() = %v, %v, want the stale value", gotResp, err)
+	}
+
+	select {
+	case <-revalidated:
+	case <-time.After(time.Second):
+		t.Fatalf("Loader.Load() did not revalidate the stale value")
+	}
+	for deadline := time.Now().Add(time.Second); local.Get("user:id") == nil && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	gotResp, err = l.Load(context.Background(), "id", nil)
+	if err != nil || gotResp.Name != "new" {
+		t.Errorf("Loader.Load() = %v, %v, want the revalidated value", gotResp, err)
+	}
+}
+
+func TestLoader_Load_changedWhileFetching(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	var l *Loader[string, user]
+	tests := []struct {
+		name      string
+		fetch     func(ctx context.Context) (user, error)
+		wantLocal bool
+		mock      func()
+	}{
+		{
+			name: "success invalidated before it is stored",
+			fetch: func(ctx context.Context) (user, error) {
+				err := l.Invalidate(ctx, "id")
+				return user{Id: "id", Name: "old"}, err
+			},
+			wantLocal: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return("", goredis.Nil),
+					mockRedis.EXPECT().Delete(gomock.Any(), "user:id").Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name: "success invalidated while it is stored",
+			fetch: func(ctx context.Context) (user, error) {
+				return user{Id: "id", Name: "old"}, nil
+			},
+			wantLocal: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return("", goredis.Nil),
+					mockRedis.EXPECT().SetEx(gomock.Any(), "user:id", gomock.Any(), time.Hour+time.Minute).DoAndReturn(func(ctx context.Context, key string, value interface{}, expiration time.Duration) (string, error) {
+						l.generations.change(key)
+						return "OK", nil
+					}),
+					mockRedis.EXPECT().Delete(gomock.Any(), "user:id").Return(int64(1), nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			local := lrucache.Init()
+			l = newTestLoader(mockRedis, local)
+			gotResp, err := l.Load(context.Background(), "id", tt.fetch)
+			if err != nil || gotResp.Name != "old" {
+				t.Errorf("Loader.Load() = %v, %v, want the fetched value", gotResp, err)
+			}
+			if gotLocal := local.Get("user:id") != nil; gotLocal != tt.wantLocal {
+				t.Errorf("Loader.Load() cached locally = %v, want %v", gotLocal, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_revalidateInvalidated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	gomock.InOrder(
+		mockRedis.EXPECT().Get(gomock.Any(), "user:id").Return(redisEntry(user{Id: "id", Name: "old"}, false, time.Now().Add(-time.Second)), nil),
+		mockRedis.EXPECT().Delete(gomock.Any(), "user:id").Return(int64(1), nil),
+	)
+
+	local := lrucache.Init()
+	l := newTestLoader(mockRedis, local)
+	fetched := make(chan struct{})
+	gotResp, err := l.Load(context.Background(), "id", func(ctx context.Context) (user, error) {
+		defer close(fetched)
+		// The value is changed and invalidated after the revalidation read it.
+		err := l.Invalidate(ctx, "id")
+		return user{Id: "id", Name: "stale"}, err
+	})
+	if err != nil || gotResp.Name != "old" {
+		t.Fatalf("Loader.Load() = %v, %v, want the stale value", gotResp, err)
+	}
+
+	select {
+	case <-fetched:
+	case <-time.After(time.Second):
+		t.Fatalf("Loader.Load() did not revalidate the stale value")
+	}
+	inFlight := func() bool {
+		l.generations.mu.Lock()
+		defer l.generations.mu.Unlock()
+		_, ok := l.generations.keys["user:id"]
+		return ok
+	}
+	for deadline := time.Now().Add(time.Second); inFlight() && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	if local.Get("user:id") != nil {
+		t.Errorf("Loader.Load() cached the value revalidated before the change")
+	}
+}
+
+func TestLoader_Invalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		wantLocal bool
+		mock      func()
+	}{
+		{
+			name:      "success",
+			wantErr:   false,
+			wantLocal: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Delete(gomock.Any(), "user:id").Return(int64(1), nil),
+				)
+			},
+		},
+		{
+			name:      "error Delete redis",
+			wantErr:   true,
+			wantLocal: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Delete(gomock.Any(), "user:id").Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			local := lrucache.Init()
+			local.Set("user:id", entry[user]{Value: user{Id: "id"}, FreshUntil: time.Now().Add(time.Minute)}, time.Minute)
+			l := newTestLoader(mockRedis, local)
+			if err := l.Invalidate(context.Background(), "id"); (err != nil) != tt.wantErr {
+				t.Errorf("Loader.Invalidate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotLocal := local.Get("user:id") != nil; gotLocal != tt.wantLocal {
+				t.Errorf("Loader.Invalidate() cached locally = %v, want %v", gotLocal, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestLoader_Set(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		wantLocal bool
+		mock      func()
+	}{
+		{
+			name:      "success",
+			wantErr:   false,
+			wantLocal: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().SetEx(gomock.Any(), "user:id", gomock.Any(), time.Hour+time.Minute).Return("OK", nil),
+				)
+			},
+		},
+		{
+			name:      "error SetEx redis",
+			wantErr:   true,
+			wantLocal: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().SetEx(gomock.Any(), "user:id", gomock.Any(), time.Hour+time.Minute).Return("", fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			local := lrucache.Init()
+			l := newTestLoader(mockRedis, local)
+			if err := l.Set(context.Background(), "id", user{Id: "id"}); (err != nil) != tt.wantErr {
+				t.Errorf("Loader.Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotLocal := local.Get("user:id") != nil; gotLocal != tt.wantLocal {
+				t.Errorf("Loader.Set() cached locally = %v, want %v", gotLocal, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestLoader_jitter(t *testing.T) {
+	l := New[string, user](Config[string]{
+		Jitter: 0.2,
+	})
+	for i := 0; i < 100; i++ {
+		if got := l.jitter(time.Minute); got > time.Minute || got < time.Second*48 {
+			t.Fatalf("Loader.jitter() = %v, want between 48s and 1m", got)
+		}
+	}
+}
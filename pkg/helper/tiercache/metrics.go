@@ -0,0 +1,16 @@
+package tiercache
+
+import "expvar"
+
+// metrics counts the hits and misses of every loader per tier as "<name>.<tier>.hit" and "<name>.<tier>.miss",
+// and the values fetched past every tier as "<name>.fetch". They are published with the other expvar variables.
+var metrics = expvar.NewMap("tiercache")
+
+func (l *Loader[K, T]) count(tier string, hit bool) {
+	if hit {
+		metrics.Add(l.config.Name+"."+tier+".hit", 1)
+		return
+	}
+
+	metrics.Add(l.config.Name+"."+tier+".miss", 1)
+}
@@ -0,0 +1,53 @@
+package tiercache
+
+import (
+	"time"
+
+	"github.com/kevinsudut/wallet-system/pkg/helper/singleflight"
+	lrucache "github.com/kevinsudut/wallet-system/pkg/lib/lru-cache"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+// revalidateTimeout bounds a background refresh of a stale value, which outlives the request that found it.
+const revalidateTimeout = 10 * time.Second
+
+// Config describes the tiers of a Loader. A tier without a client is skipped.
+type Config[K any] struct {
+	// Name identifies the loader in metrics.
+	Name string
+	// Key formats the cache key of a typed key, the same in every tier.
+	Key func(K) string
+
+	Local    lrucache.LRUCacheItf
+	LocalTTL time.Duration
+	Redis    redis.RedisItf
+	RedisTTL time.Duration
+	// Jitter shortens every TTL by a random fraction up to Jitter, so values cached together do not expire together.
+	Jitter float64
+
+	// NotFound is the error a fetch returns when the value does not exist. It is cached for NegativeTTL in every
+	// tier and returned by Load while cached. Without NotFound or NegativeTTL nothing is cached negatively.
+	NotFound    error
+	NegativeTTL time.Duration
+
+	// StaleWhileRevalidate is how long a value is still served after its TTL while it is fetched again in the
+	// background. A change made on another instance while it is fetched can be overwritten by the older value until
+	// its TTL, so values that must not be served stale leave it at zero.
+	StaleWhileRevalidate time.Duration
+}
+
+// Loader reads a value of type T through a local and a Redis tier before fetching it, and stores what it fetched
+// in both. Concurrent loads of the same key share one lookup.
+type Loader[K any, T any] struct {
+	config       Config[K]
+	singleflight singleflight.SingleFlightItf
+	generations  *generations
+}
+
+func New[K any, T any](config Config[K]) *Loader[K, T] {
+	return &Loader[K, T]{
+		config:       config,
+		singleflight: singleflight.Init(),
+		generations:  newGenerations(),
+	}
+}
@@ -0,0 +1,100 @@
+package tiercache
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	tierLocal = "local"
+	tierRedis = "redis"
+)
+
+// entry is what a tier stores for a key, a value or the fact that it does not exist.
+type entry[T any] struct {
+	Value      T         `json:"value"`
+	NotFound   bool      `json:"not_found,omitempty"`
+	FreshUntil time.Time `json:"fresh_until"`
+}
+
+// stale reports whether the entry is past its TTL and only served while it is fetched again.
+func (e entry[T]) stale(now time.Time) bool {
+	return !now.Before(e.FreshUntil)
+}
+
+func (e entry[T]) result(notFound error) (T, error) {
+	if e.NotFound {
+		var zero T
+		return zero, notFound
+	}
+
+	return e.Value, nil
+}
+
+// generations counts the changes of the keys being fetched, so a value fetched before a change is not cached
+// after it. Only keys with a fetch in flight are kept.
+type generations struct {
+	mu   sync.Mutex
+	keys map[string]*generation
+}
+
+type generation struct {
+	fetches int
+	changes uint64
+}
+
+func newGenerations() *generations {
+	return &generations{
+		keys: make(map[string]*generation),
+	}
+}
+
+// start registers a fetch of key and returns the generation it reads, done must be called once it is cached.
+func (g *generations) start(key string) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	gen, ok := g.keys[key]
+	if !ok {
+		gen = &generation{}
+		g.keys[key] = gen
+	}
+	gen.fetches++
+
+	return gen.changes
+}
+
+func (g *generations) done(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	gen, ok := g.keys[key]
+	if !ok {
+		return
+	}
+
+	gen.fetches--
+	if gen.fetches <= 0 {
+		delete(g.keys, key)
+	}
+}
+
+// current reports whether key did not change since a fetch of it started at changes.
+func (g *generations) current(key string, changes uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	gen, ok := g.keys[key]
+	return !ok || gen.changes == changes
+}
+
+// change marks key as changed for the fetches of it in flight.
+func (g *generations) change(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	gen, ok := g.keys[key]
+	if ok {
+		gen.changes++
+	}
+}
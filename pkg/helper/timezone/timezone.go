@@ -0,0 +1,28 @@
+package timezone
+
+import (
+	"os"
+	"time"
+)
+
+// Business reads BUSINESS_TIME_ZONE (an IANA name such as "Asia/Jakarta"), falling back to UTC when
+// unset or invalid. Calendar windows such as days, ISO weeks and months follow this time zone.
+func Business() *time.Location {
+	name := os.Getenv("BUSINESS_TIME_ZONE")
+	if name == "" || name == "Local" {
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// StartOfDay returns midnight of the day t falls on in location.
+func StartOfDay(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
@@ -0,0 +1,77 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusiness(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{
+			name: "success",
+			env:  "Asia/Jakarta",
+			want: "Asia/Jakarta",
+		},
+		{
+			name: "success unset",
+			env:  "",
+			want: "UTC",
+		},
+		{
+			name: "success local",
+			env:  "Local",
+			want: "UTC",
+		},
+		{
+			name: "success invalid",
+			env:  "Foo/Bar",
+			want: "UTC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BUSINESS_TIME_ZONE", tt.env)
+			if got := Business(); got.String() != tt.want {
+				t.Errorf("Business() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		location *time.Location
+		want     time.Time
+	}{
+		{
+			name:     "success",
+			t:        time.Date(2026, 9, 15, 10, 30, 0, 0, time.UTC),
+			location: time.UTC,
+			want:     time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "success next day in location",
+			t:        time.Date(2026, 9, 15, 20, 0, 0, 0, time.UTC),
+			location: jakarta,
+			want:     time.Date(2026, 9, 16, 0, 0, 0, 0, jakarta),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StartOfDay(tt.t, tt.location); !got.Equal(tt.want) {
+				t.Errorf("StartOfDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
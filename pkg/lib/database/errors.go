@@ -0,0 +1,30 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrNoRowsAffected is returned by the Exec methods when the statement did not change any row.
+var ErrNoRowsAffected = errors.New("no rows affected")
+
+const (
+	errCodeUniqueViolation      = "23505"
+	errCodeSerializationFailure = "40001"
+	errCodeDeadlockDetected     = "40P01"
+)
+
+func IsUniqueViolation(err error) bool {
+	return hasErrorCode(err, errCodeUniqueViolation)
+}
+
+// IsRetryable reports whether Postgres aborted the transaction in a way that is safe to run again.
+func IsRetryable(err error) bool {
+	return hasErrorCode(err, errCodeSerializationFailure) || hasErrorCode(err, errCodeDeadlockDetected)
+}
+
+func hasErrorCode(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
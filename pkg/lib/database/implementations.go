@@ -37,15 +37,35 @@ func (db database) SelectContextStmt(ctx context.Context, stmt *sqlx.Stmt, dest
 	return stmt.SelectContext(ctx, dest, args...)
 }
 
+func (db database) GetContextStmtTx(ctx context.Context, tx *sql.Tx, stmt *sqlx.Stmt, dest interface{}, args ...interface{}) error {
+	return db.txStmt(ctx, tx, stmt).GetContext(ctx, dest, args...)
+}
+
+func (db database) SelectContextStmtTx(ctx context.Context, tx *sql.Tx, stmt *sqlx.Stmt, dest interface{}, args ...interface{}) error {
+	return db.txStmt(ctx, tx, stmt).SelectContext(ctx, dest, args...)
+}
+
+// txStmt binds a prepared statement to tx while keeping sqlx struct scanning.
+func (db database) txStmt(ctx context.Context, tx *sql.Tx, stmt *sqlx.Stmt) *sqlx.Stmt {
+	return &sqlx.Stmt{
+		Stmt:   tx.StmtContext(ctx, stmt.Stmt),
+		Mapper: stmt.Mapper,
+	}
+}
+
 func (db database) ExecContextStmt(ctx context.Context, stmt *sqlx.Stmt, args ...interface{}) error {
 	result, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return err
 	}
 
-	if row, err := result.RowsAffected(); err != nil || row <= 0 {
+	row, err := result.RowsAffected()
+	if err != nil {
 		return fmt.Errorf("failed ExecContextStmt %s", err.Error())
 	}
+	if row <= 0 {
+		return ErrNoRowsAffected
+	}
 
 	return nil
 }
@@ -56,9 +76,13 @@ func (db database) ExecContextStmtTx(ctx context.Context, tx *sql.Tx, stmt *sqlx
 		return err
 	}
 
-	if row, err := result.RowsAffected(); err != nil || row <= 0 {
+	row, err := result.RowsAffected()
+	if err != nil {
 		return fmt.Errorf("failed ExecContextStmtTx %s", err.Error())
 	}
+	if row <= 0 {
+		return ErrNoRowsAffected
+	}
 
 	return nil
 }
@@ -16,6 +16,8 @@ type DatabaseItf interface {
 
 	GetContextStmt(ctx context.Context, stmt *sqlx.Stmt, dest interface{}, args ...interface{}) error
 	SelectContextStmt(ctx context.Context, stmt *sqlx.Stmt, dest interface{}, args ...interface{}) error
+	GetContextStmtTx(ctx context.Context, tx *sql.Tx, stmt *sqlx.Stmt, dest interface{}, args ...interface{}) error
+	SelectContextStmtTx(ctx context.Context, tx *sql.Tx, stmt *sqlx.Stmt, dest interface{}, args ...interface{}) error
 
 	ExecContextStmt(ctx context.Context, stmt *sqlx.Stmt, args ...interface{}) error
 	ExecContextStmtTx(ctx context.Context, tx *sql.Tx, stmt *sqlx.Stmt, args ...interface{}) error
@@ -127,6 +127,25 @@ func (mr *MockDatabaseItfMockRecorder) GetContextStmt(ctx, stmt, dest any, args
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContextStmt", reflect.TypeOf((*MockDatabaseItf)(nil).GetContextStmt), varargs...)
 }
 
+// GetContextStmtTx mocks base method.
+func (m *MockDatabaseItf) GetContextStmtTx(ctx context.Context, tx *sql.Tx, stmt *sqlx.Stmt, dest any, args ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, tx, stmt, dest}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetContextStmtTx", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetContextStmtTx indicates an expected call of GetContextStmtTx.
+func (mr *MockDatabaseItfMockRecorder) GetContextStmtTx(ctx, tx, stmt, dest any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, tx, stmt, dest}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContextStmtTx", reflect.TypeOf((*MockDatabaseItf)(nil).GetContextStmtTx), varargs...)
+}
+
 // PreparexContext mocks base method.
 func (m *MockDatabaseItf) PreparexContext(ctx context.Context, query string) *sqlx.Stmt {
 	m.ctrl.T.Helper()
@@ -173,3 +192,22 @@ func (mr *MockDatabaseItfMockRecorder) SelectContextStmt(ctx, stmt, dest any, ar
 	varargs := append([]any{ctx, stmt, dest}, args...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectContextStmt", reflect.TypeOf((*MockDatabaseItf)(nil).SelectContextStmt), varargs...)
 }
+
+// SelectContextStmtTx mocks base method.
+func (m *MockDatabaseItf) SelectContextStmtTx(ctx context.Context, tx *sql.Tx, stmt *sqlx.Stmt, dest any, args ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, tx, stmt, dest}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SelectContextStmtTx", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SelectContextStmtTx indicates an expected call of SelectContextStmtTx.
+func (mr *MockDatabaseItfMockRecorder) SelectContextStmtTx(ctx, tx, stmt, dest any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, tx, stmt, dest}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectContextStmtTx", reflect.TypeOf((*MockDatabaseItf)(nil).SelectContextStmtTx), varargs...)
+}
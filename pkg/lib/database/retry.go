@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+const (
+	retryMaxAttempts = 3
+	retryBaseBackoff = 20 * time.Millisecond
+)
+
+// RetryTx runs fn, which must open and finish its own transaction, again with exponential backoff
+// and jitter when Postgres reports a deadlock or serialization failure.
+func RetryTx(ctx context.Context, fn func() error) (err error) {
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !IsRetryable(err) || attempt+1 >= retryMaxAttempts {
+			return err
+		}
+
+		backoff := retryBaseBackoff << attempt
+		backoff += time.Duration(rand.Int63n(int64(backoff)))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
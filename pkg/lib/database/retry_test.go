@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestRetryTx(t *testing.T) {
+	type args struct {
+		errs []error
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name: "success",
+			args: args{
+				errs: []error{nil},
+			},
+			wantCalls: 1,
+			wantErr:   false,
+		},
+		{
+			name: "success after deadlock",
+			args: args{
+				errs: []error{&pq.Error{Code: errCodeDeadlockDetected}, nil},
+			},
+			wantCalls: 2,
+			wantErr:   false,
+		},
+		{
+			name: "error serialization failure exhausts attempts",
+			args: args{
+				errs: []error{
+					&pq.Error{Code: errCodeSerializationFailure},
+					&pq.Error{Code: errCodeSerializationFailure},
+					&pq.Error{Code: errCodeSerializationFailure},
+				},
+			},
+			wantCalls: retryMaxAttempts,
+			wantErr:   true,
+		},
+		{
+			name: "error not retryable",
+			args: args{
+				errs: []error{fmt.Errorf("foo")},
+			},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := RetryTx(context.Background(), func() error {
+				err := tt.args.errs[calls]
+				calls++
+				return err
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RetryTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("RetryTx() calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "unique violation",
+			err:  &pq.Error{Code: errCodeUniqueViolation},
+			want: true,
+		},
+		{
+			name: "wrapped unique violation",
+			err:  fmt.Errorf("insert: %w", &pq.Error{Code: errCodeUniqueViolation}),
+			want: true,
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("foo"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUniqueViolation(tt.err); got != tt.want {
+				t.Errorf("IsUniqueViolation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
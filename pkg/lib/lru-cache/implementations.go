@@ -1,6 +1,7 @@
 package lrucache
 
 import (
+	"context"
 	"time"
 
 	"github.com/karlseguin/ccache/v3"
@@ -11,13 +12,28 @@ func (c lruCache) Get(key string) *ccache.Item[interface{}] {
 }
 
 func (c lruCache) Set(key string, value interface{}, duration time.Duration) {
-	c.cache.Set(key, value, duration)
+	c.cache.Set(key, value, c.ttl(duration))
 }
 
+// Delete removes key from this process only, see Invalidate to remove it from every instance.
 func (c lruCache) Delete(key string) bool {
 	return c.cache.Delete(key)
 }
 
 func (c lruCache) Fetch(key string, duration time.Duration, fetch func() (interface{}, error)) (*ccache.Item[interface{}], error) {
-	return c.cache.Fetch(key, duration, fetch)
+	return c.cache.Fetch(key, c.ttl(duration), fetch)
+}
+
+// Invalidate removes keys from this process and, for a shared cache, publishes them to every other instance.
+// An error means other instances may keep their copy until it expires.
+func (c lruCache) Invalidate(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.cache.Delete(key)
+	}
+
+	if c.redis == nil {
+		return nil
+	}
+
+	return c.publish(ctx, keys)
 }
@@ -0,0 +1,247 @@
+package lrucache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/karlseguin/ccache/v3"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestMain(m *testing.M) {
+	log.Init()
+	os.Exit(m.Run())
+}
+
+func Test_lruCache_Invalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	type fields struct {
+		redis redis.RedisItf
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		keys    []string
+		wantErr bool
+		mock    func()
+	}{
+		{
+			name:    "success local",
+			fields:  fields{},
+			keys:    []string{"a", "b"},
+			wantErr: false,
+			mock:    func() {},
+		},
+		{
+			name: "success shared",
+			fields: fields{
+				redis: mockRedis,
+			},
+			keys:    []string{"a", "b"},
+			wantErr: false,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Publish(gomock.Any(), channelInvalidations, `{"keys":["a","b"]}`).Return(int64(2), nil),
+				)
+			},
+		},
+		{
+			name: "error Publish redis",
+			fields: fields{
+				redis: mockRedis,
+			},
+			keys:    []string{"a", "b"},
+			wantErr: true,
+			mock: func() {
+				gomock.InOrder(
+					mockRedis.EXPECT().Publish(gomock.Any(), channelInvalidations, `{"keys":["a","b"]}`).Return(int64(0), fmt.Errorf("foo")),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			c := lruCache{
+				cache:         ccache.New(ccache.Configure[interface{}]()),
+				redis:         tt.fields.redis,
+				lastMessageAt: &atomic.Int64{},
+			}
+			c.cache.Set("a", 1, time.Minute)
+			c.cache.Set("b", 2, time.Minute)
+			c.cache.Set("c", 3, time.Minute)
+
+			err := c.Invalidate(context.Background(), tt.keys...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("lruCache.Invalidate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if c.Get("a") != nil || c.Get("b") != nil || c.Get("c") == nil {
+				t.Errorf("lruCache.Invalidate() did not delete only %v", tt.keys)
+			}
+		})
+	}
+}
+
+func Test_lruCache_handleInvalidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		wantDeleted bool
+	}{
+		{
+			name:        "invalidation",
+			payload:     `{"keys":["a"]}`,
+			wantDeleted: true,
+		},
+		{
+			name:        "heartbeat",
+			payload:     `{"keys":null}`,
+			wantDeleted: false,
+		},
+		{
+			name:        "malformed",
+			payload:     "{",
+			wantDeleted: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := lruCache{
+				cache:         ccache.New(ccache.Configure[interface{}]()),
+				redis:         redis.NewMockRedisItf(gomock.NewController(t)),
+				lastMessageAt: &atomic.Int64{},
+			}
+			c.cache.Set("a", 1, time.Minute)
+
+			c.handleInvalidation(redis.Message{
+				Channel: channelInvalidations,
+				Payload: tt.payload,
+			})
+			if got := c.Get("a") == nil; got != tt.wantDeleted {
+				t.Errorf("lruCache.handleInvalidation() deleted = %v, want %v", got, tt.wantDeleted)
+			}
+			if !c.connected() {
+				t.Errorf("lruCache.handleInvalidation() did not mark the bus connected")
+			}
+		})
+	}
+}
+
+func Test_lruCache_ttl(t *testing.T) {
+	tests := []struct {
+		name          string
+		shared        bool
+		lastMessageAt time.Time
+		duration      time.Duration
+		want          time.Duration
+	}{
+		{
+			name:     "local",
+			shared:   false,
+			duration: time.Minute,
+			want:     time.Minute,
+		},
+		{
+			name:          "shared connected",
+			shared:        true,
+			lastMessageAt: time.Now(),
+			duration:      time.Minute,
+			want:          time.Minute,
+		},
+		{
+			name:     "shared never connected",
+			shared:   true,
+			duration: time.Minute,
+			want:     disconnectedTTL,
+		},
+		{
+			name:          "shared lost",
+			shared:        true,
+			lastMessageAt: time.Now().Add(-disconnectedAfter - time.Second),
+			duration:      time.Minute,
+			want:          disconnectedTTL,
+		},
+		{
+			name:     "shared shorter than fallback",
+			shared:   true,
+			duration: time.Second,
+			want:     time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := lruCache{
+				lastMessageAt: &atomic.Int64{},
+			}
+			if tt.shared {
+				c.redis = redis.NewMockRedisItf(gomock.NewController(t))
+			}
+			if !tt.lastMessageAt.IsZero() {
+				c.lastMessageAt.Store(tt.lastMessageAt.UnixNano())
+			}
+			if got := c.ttl(tt.duration); got != tt.want {
+				t.Errorf("lruCache.ttl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_lruCache_disconnect(t *testing.T) {
+	c := lruCache{
+		cache:         ccache.New(ccache.Configure[interface{}]()),
+		lastMessageAt: &atomic.Int64{},
+	}
+	c.lastMessageAt.Store(time.Now().UnixNano())
+	c.cache.Set("a", 1, time.Minute)
+
+	c.disconnect()
+	if c.Get("a") != nil {
+		t.Errorf("lruCache.disconnect() kept a value set while connected")
+	}
+
+	c.cache.Set("b", 2, time.Minute)
+	c.disconnect()
+	if c.Get("b") == nil {
+		t.Errorf("lruCache.disconnect() cleared the cache again while already disconnected")
+	}
+}
+
+func Test_lruCache_runInvalidationSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRedis := redis.NewMockRedisItf(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := lruCache{
+		cache:         ccache.New(ccache.Configure[interface{}]()),
+		redis:         mockRedis,
+		lastMessageAt: &atomic.Int64{},
+	}
+
+	mockRedis.EXPECT().Subscribe(gomock.Any(), channelInvalidations, gomock.Any()).DoAndReturn(func(ctx context.Context, channel string, handle func(redis.Message)) error {
+		cancel()
+		return ctx.Err()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		c.runInvalidationSubscriber(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("lruCache.runInvalidationSubscriber() did not stop once ctx was done")
+	}
+}
@@ -1,6 +1,7 @@
 package lrucache
 
 import (
+	"context"
 	"time"
 
 	"github.com/karlseguin/ccache/v3"
@@ -11,4 +12,5 @@ type LRUCacheItf interface {
 	Set(key string, value interface{}, duration time.Duration)
 	Delete(key string) bool
 	Fetch(key string, duration time.Duration, fetch func() (interface{}, error)) (*ccache.Item[interface{}], error)
+	Invalidate(ctx context.Context, keys ...string) error
 }
@@ -0,0 +1,109 @@
+package lrucache
+
+import (
+	"context"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kevinsudut/wallet-system/pkg/lib/log"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+// invalidation is the message published on channelInvalidations. A heartbeat has no keys.
+type invalidation struct {
+	Keys []string `json:"keys"`
+}
+
+func (c lruCache) publish(ctx context.Context, keys []string) error {
+	payload, err := jsoniter.MarshalToString(invalidation{
+		Keys: keys,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.redis.Publish(ctx, channelInvalidations, payload)
+	return err
+}
+
+// ttl shortens duration to disconnectedTTL while a shared cache is not connected to the bus.
+func (c lruCache) ttl(duration time.Duration) time.Duration {
+	if c.redis == nil || c.connected() || duration <= disconnectedTTL {
+		return duration
+	}
+
+	return disconnectedTTL
+}
+
+func (c lruCache) connected() bool {
+	lastMessageAt := c.lastMessageAt.Load()
+	return lastMessageAt != 0 && time.Since(time.Unix(0, lastMessageAt)) <= disconnectedAfter
+}
+
+// disconnect clears the cache the first time the bus is found lost, because invalidations published since the
+// last message may never arrive.
+func (c lruCache) disconnect() {
+	lastMessageAt := c.lastMessageAt.Load()
+	if lastMessageAt != 0 && c.lastMessageAt.CompareAndSwap(lastMessageAt, 0) {
+		c.cache.Clear()
+	}
+}
+
+func (c lruCache) handleInvalidation(message redis.Message) {
+	c.lastMessageAt.Store(time.Now().UnixNano())
+
+	var inv invalidation
+	err := jsoniter.UnmarshalFromString(message.Payload, &inv)
+	if err != nil {
+		log.Errorln("LRUCache.handleInvalidation", message.Payload, err)
+		return
+	}
+
+	for _, key := range inv.Keys {
+		c.cache.Delete(key)
+	}
+}
+
+func (c lruCache) runInvalidationSubscriber(ctx context.Context) {
+	for {
+		err := c.redis.Subscribe(ctx, channelInvalidations, c.handleInvalidation)
+		c.disconnect()
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		log.Errorln("LRUCache.runInvalidationSubscriber", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(subscribeRetryDelay):
+		}
+	}
+}
+
+func (c lruCache) runInvalidationHeartbeat(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		publishCtx, cancel := context.WithTimeout(ctx, heartbeatInterval)
+		err := c.publish(publishCtx, nil)
+		cancel()
+		if err != nil {
+			log.Errorln("LRUCache.runInvalidationHeartbeat", err)
+		}
+
+		if !c.connected() {
+			c.disconnect()
+		}
+	}
+}
@@ -1,13 +1,56 @@
 package lrucache
 
-import "github.com/karlseguin/ccache/v3"
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/karlseguin/ccache/v3"
+	"github.com/kevinsudut/wallet-system/pkg/lib/redis"
+)
+
+const (
+	channelInvalidations = "lrucache:invalidations"
+
+	// heartbeatInterval is how often every shared cache publishes an empty invalidation, so a bus that stopped
+	// delivering is noticed even when nothing is written.
+	heartbeatInterval = 5 * time.Second
+	// disconnectedAfter is how long a shared cache waits for any message before it considers the bus lost.
+	disconnectedAfter = 3 * heartbeatInterval
+	// disconnectedTTL caps how long values are kept while the bus is lost, since invalidations of other instances
+	// do not arrive then.
+	disconnectedTTL     = 5 * time.Second
+	subscribeRetryDelay = 5 * time.Second
+)
 
 type lruCache struct {
 	cache *ccache.Cache[interface{}]
+	redis redis.RedisItf
+	// lastMessageAt is the unix nano time the last message was received from the bus, 0 while it is lost.
+	lastMessageAt *atomic.Int64
 }
 
+// Init creates a cache that only this process sees. Use it for values that are only ever refreshed by expiring.
 func Init() LRUCacheItf {
 	return &lruCache{
-		cache: ccache.New(ccache.Configure[interface{}]()),
+		cache:         ccache.New(ccache.Configure[interface{}]()),
+		lastMessageAt: &atomic.Int64{},
+	}
+}
+
+// InitShared creates a cache whose invalidations are broadcast over Redis pub/sub to the shared caches of every
+// instance. Until the bus delivers its first message, and whenever it is lost, values are kept for at most
+// disconnectedTTL. Each process creates one and shares it between its domains, and it stops listening to the bus
+// once ctx is done.
+func InitShared(ctx context.Context, redis redis.RedisItf) LRUCacheItf {
+	c := &lruCache{
+		cache:         ccache.New(ccache.Configure[interface{}]()),
+		redis:         redis,
+		lastMessageAt: &atomic.Int64{},
 	}
+
+	go c.runInvalidationSubscriber(ctx)
+	go c.runInvalidationHeartbeat(ctx)
+
+	return c
 }
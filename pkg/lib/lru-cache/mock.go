@@ -10,6 +10,7 @@
 package lrucache
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
@@ -83,6 +84,25 @@ func (mr *MockLRUCacheItfMockRecorder) Get(key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLRUCacheItf)(nil).Get), key)
 }
 
+// Invalidate mocks base method.
+func (m *MockLRUCacheItf) Invalidate(ctx context.Context, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Invalidate", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockLRUCacheItfMockRecorder) Invalidate(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockLRUCacheItf)(nil).Invalidate), varargs...)
+}
+
 // Set mocks base method.
 func (m *MockLRUCacheItf) Set(key string, value any, duration time.Duration) {
 	m.ctrl.T.Helper()
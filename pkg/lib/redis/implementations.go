@@ -3,6 +3,8 @@ package redis
 import (
 	"context"
 	"errors"
+	"net"
+	"strings"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -18,10 +20,180 @@ func (r rdb) SetEx(ctx context.Context, key string, value interface{}, expiratio
 	return r.client.SetEx(ctx, key, value, expiration).Result()
 }
 
+func (r rdb) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, expiration).Result()
+}
+
 func (r rdb) Delete(ctx context.Context, keys ...string) (int64, error) {
 	return r.client.Del(ctx, keys...).Result()
 }
 
+func (r rdb) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r rdb) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return r.client.IncrBy(ctx, key, value).Result()
+}
+
+// IncrByEx adds value to the counter at key and sets it to expire after expiration when it has no expiry yet, in
+// one step, so a counter is never left without one. Later increments do not extend the expiry.
+func (r rdb) IncrByEx(ctx context.Context, key string, value int64, expiration time.Duration) (int64, error) {
+	return incrByExScript.Run(ctx, r.client, []string{key}, value, expiration.Milliseconds()).Int64()
+}
+
+func (r rdb) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	return r.client.Expire(ctx, key, expiration).Result()
+}
+
+func (r rdb) Rename(ctx context.Context, key string, newKey string) (string, error) {
+	return r.client.Rename(ctx, key, newKey).Result()
+}
+
+func (r rdb) ZAdd(ctx context.Context, key string, members ...Z) (int64, error) {
+	zs := make([]redis.Z, len(members))
+	for i, member := range members {
+		zs[i] = redis.Z{
+			Score:  member.Score,
+			Member: member.Member,
+		}
+	}
+
+	return r.client.ZAdd(ctx, key, zs...).Result()
+}
+
+func (r rdb) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	return r.client.ZIncrBy(ctx, key, increment, member).Result()
+}
+
+func (r rdb) ZRevRangeWithScores(ctx context.Context, key string, start int64, stop int64) ([]Z, error) {
+	zs, err := r.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]Z, len(zs))
+	for i, z := range zs {
+		member, _ := z.Member.(string)
+		members[i] = Z{
+			Member: member,
+			Score:  z.Score,
+		}
+	}
+
+	return members, nil
+}
+
+// XAdd appends an entry to the stream at key and returns its id. The stream is trimmed to about maxLen entries,
+// which lets Redis trim whole nodes instead of exactly maxLen.
+func (r rdb) XAdd(ctx context.Context, key string, maxLen int64, values map[string]interface{}) (string, error) {
+	return r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	}).Result()
+}
+
+// XGroupCreateMkStream creates the consumer group at start of the stream at key, creating the stream when it
+// does not exist. A group that already exists is left as it is.
+func (r rdb) XGroupCreateMkStream(ctx context.Context, key string, group string, start string) (string, error) {
+	resp, err := r.client.XGroupCreateMkStream(ctx, key, group, start).Result()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return resp, nil
+	}
+
+	return resp, err
+}
+
+// XReadGroup reads up to count entries of the stream at key for consumer of group, after id, waiting up to block
+// for new ones. Id ">" reads entries never delivered to the group, "0" the ones delivered to consumer and not
+// acknowledged yet.
+func (r rdb) XReadGroup(ctx context.Context, group string, consumer string, key string, id string, count int64, block time.Duration) ([]XMessage, error) {
+	streams, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{key, id},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return []XMessage{}, nil
+	} else if err != nil {
+		return []XMessage{}, err
+	}
+
+	messages := []XMessage{}
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			messages = append(messages, XMessage{
+				ID:     message.ID,
+				Values: message.Values,
+			})
+		}
+	}
+
+	return messages, nil
+}
+
+func (r rdb) XAck(ctx context.Context, key string, group string, ids ...string) (int64, error) {
+	return r.client.XAck(ctx, key, group, ids...).Result()
+}
+
+// XRange reads up to count entries of the stream at key from start to stop. Prefixing start with "(" leaves
+// out the entry with that id.
+func (r rdb) XRange(ctx context.Context, key string, start string, stop string, count int64) ([]XMessage, error) {
+	resp, err := r.client.XRangeN(ctx, key, start, stop, count).Result()
+	if err != nil {
+		return []XMessage{}, err
+	}
+
+	messages := make([]XMessage, 0, len(resp))
+	for _, message := range resp {
+		messages = append(messages, XMessage{
+			ID:     message.ID,
+			Values: message.Values,
+		})
+	}
+
+	return messages, nil
+}
+
+// Publish sends message to the subscribers of channel, on any instance, and returns how many received it.
+func (r rdb) Publish(ctx context.Context, channel string, message interface{}) (int64, error) {
+	return r.client.Publish(ctx, channel, message).Result()
+}
+
+// Subscribe calls handle with every message published to channel until ctx is done or the connection is lost,
+// and returns why it stopped. Messages published while nobody is subscribed are not delivered later, and a
+// quiet connection is pinged so a dead one is noticed.
+func (r rdb) Subscribe(ctx context.Context, channel string, handle func(Message)) error {
+	pubsub := r.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.ReceiveTimeout(ctx, subscribePingInterval)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() && ctx.Err() == nil {
+			err = pubsub.Ping(ctx)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg, ok := msg.(*redis.Message); ok {
+			handle(Message{
+				Channel: msg.Channel,
+				Payload: msg.Payload,
+			})
+		}
+	}
+}
+
 func (r rdb) Fetch(ctx context.Context, key string, expiration time.Duration, fetch func() (interface{}, error)) (string, error) {
 	resp, err := r.Get(ctx, key)
 	if err == nil {
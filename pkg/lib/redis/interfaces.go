@@ -8,6 +8,22 @@ import (
 type RedisItf interface {
 	Get(ctx context.Context, key string) (string, error)
 	SetEx(ctx context.Context, key string, value interface{}, expiration time.Duration) (string, error)
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
 	Delete(ctx context.Context, keys ...string) (int64, error)
+	Incr(ctx context.Context, key string) (int64, error)
+	IncrBy(ctx context.Context, key string, value int64) (int64, error)
+	IncrByEx(ctx context.Context, key string, value int64, expiration time.Duration) (int64, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) (bool, error)
+	Rename(ctx context.Context, key string, newKey string) (string, error)
+	ZAdd(ctx context.Context, key string, members ...Z) (int64, error)
+	ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error)
+	ZRevRangeWithScores(ctx context.Context, key string, start int64, stop int64) ([]Z, error)
+	XAdd(ctx context.Context, key string, maxLen int64, values map[string]interface{}) (string, error)
+	XGroupCreateMkStream(ctx context.Context, key string, group string, start string) (string, error)
+	XReadGroup(ctx context.Context, group string, consumer string, key string, id string, count int64, block time.Duration) ([]XMessage, error)
+	XAck(ctx context.Context, key string, group string, ids ...string) (int64, error)
+	XRange(ctx context.Context, key string, start string, stop string, count int64) ([]XMessage, error)
+	Publish(ctx context.Context, channel string, message interface{}) (int64, error)
+	Subscribe(ctx context.Context, channel string, handle func(Message)) error
 	Fetch(ctx context.Context, key string, expiration time.Duration, fetch func() (interface{}, error)) (string, error)
 }
@@ -60,6 +60,21 @@ func (mr *MockRedisItfMockRecorder) Delete(ctx any, keys ...any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRedisItf)(nil).Delete), varargs...)
 }
 
+// Expire mocks base method.
+func (m *MockRedisItf) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, key, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockRedisItfMockRecorder) Expire(ctx, key, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockRedisItf)(nil).Expire), ctx, key, expiration)
+}
+
 // Fetch mocks base method.
 func (m *MockRedisItf) Fetch(ctx context.Context, key string, expiration time.Duration, fetch func() (any, error)) (string, error) {
 	m.ctrl.T.Helper()
@@ -90,6 +105,81 @@ func (mr *MockRedisItfMockRecorder) Get(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRedisItf)(nil).Get), ctx, key)
 }
 
+// Incr mocks base method.
+func (m *MockRedisItf) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockRedisItfMockRecorder) Incr(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockRedisItf)(nil).Incr), ctx, key)
+}
+
+// IncrBy mocks base method.
+func (m *MockRedisItf) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrBy", ctx, key, value)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrBy indicates an expected call of IncrBy.
+func (mr *MockRedisItfMockRecorder) IncrBy(ctx, key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockRedisItf)(nil).IncrBy), ctx, key, value)
+}
+
+// IncrByEx mocks base method.
+func (m *MockRedisItf) IncrByEx(ctx context.Context, key string, value int64, expiration time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrByEx", ctx, key, value, expiration)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrByEx indicates an expected call of IncrByEx.
+func (mr *MockRedisItfMockRecorder) IncrByEx(ctx, key, value, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrByEx", reflect.TypeOf((*MockRedisItf)(nil).IncrByEx), ctx, key, value, expiration)
+}
+
+// Publish mocks base method.
+func (m *MockRedisItf) Publish(ctx context.Context, channel string, message any) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, channel, message)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockRedisItfMockRecorder) Publish(ctx, channel, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRedisItf)(nil).Publish), ctx, channel, message)
+}
+
+// Rename mocks base method.
+func (m *MockRedisItf) Rename(ctx context.Context, key, newKey string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, key, newKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockRedisItfMockRecorder) Rename(ctx, key, newKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockRedisItf)(nil).Rename), ctx, key, newKey)
+}
+
 // SetEx mocks base method.
 func (m *MockRedisItf) SetEx(ctx context.Context, key string, value any, expiration time.Duration) (string, error) {
 	m.ctrl.T.Helper()
@@ -104,3 +194,162 @@ func (mr *MockRedisItfMockRecorder) SetEx(ctx, key, value, expiration any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEx", reflect.TypeOf((*MockRedisItf)(nil).SetEx), ctx, key, value, expiration)
 }
+
+// SetNX mocks base method.
+func (m *MockRedisItf) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, value, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockRedisItfMockRecorder) SetNX(ctx, key, value, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockRedisItf)(nil).SetNX), ctx, key, value, expiration)
+}
+
+// Subscribe mocks base method.
+func (m *MockRedisItf) Subscribe(ctx context.Context, channel string, handle func(Message)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, channel, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockRedisItfMockRecorder) Subscribe(ctx, channel, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockRedisItf)(nil).Subscribe), ctx, channel, handle)
+}
+
+// XAck mocks base method.
+func (m *MockRedisItf) XAck(ctx context.Context, key, group string, ids ...string) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, group}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "XAck", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XAck indicates an expected call of XAck.
+func (mr *MockRedisItfMockRecorder) XAck(ctx, key, group any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, group}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XAck", reflect.TypeOf((*MockRedisItf)(nil).XAck), varargs...)
+}
+
+// XAdd mocks base method.
+func (m *MockRedisItf) XAdd(ctx context.Context, key string, maxLen int64, values map[string]any) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XAdd", ctx, key, maxLen, values)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XAdd indicates an expected call of XAdd.
+func (mr *MockRedisItfMockRecorder) XAdd(ctx, key, maxLen, values any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XAdd", reflect.TypeOf((*MockRedisItf)(nil).XAdd), ctx, key, maxLen, values)
+}
+
+// XGroupCreateMkStream mocks base method.
+func (m *MockRedisItf) XGroupCreateMkStream(ctx context.Context, key, group, start string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XGroupCreateMkStream", ctx, key, group, start)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XGroupCreateMkStream indicates an expected call of XGroupCreateMkStream.
+func (mr *MockRedisItfMockRecorder) XGroupCreateMkStream(ctx, key, group, start any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XGroupCreateMkStream", reflect.TypeOf((*MockRedisItf)(nil).XGroupCreateMkStream), ctx, key, group, start)
+}
+
+// XRange mocks base method.
+func (m *MockRedisItf) XRange(ctx context.Context, key, start, stop string, count int64) ([]XMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XRange", ctx, key, start, stop, count)
+	ret0, _ := ret[0].([]XMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XRange indicates an expected call of XRange.
+func (mr *MockRedisItfMockRecorder) XRange(ctx, key, start, stop, count any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XRange", reflect.TypeOf((*MockRedisItf)(nil).XRange), ctx, key, start, stop, count)
+}
+
+// XReadGroup mocks base method.
+func (m *MockRedisItf) XReadGroup(ctx context.Context, group, consumer, key, id string, count int64, block time.Duration) ([]XMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XReadGroup", ctx, group, consumer, key, id, count, block)
+	ret0, _ := ret[0].([]XMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// XReadGroup indicates an expected call of XReadGroup.
+func (mr *MockRedisItfMockRecorder) XReadGroup(ctx, group, consumer, key, id, count, block any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XReadGroup", reflect.TypeOf((*MockRedisItf)(nil).XReadGroup), ctx, group, consumer, key, id, count, block)
+}
+
+// ZAdd mocks base method.
+func (m *MockRedisItf) ZAdd(ctx context.Context, key string, members ...Z) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZAdd", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockRedisItfMockRecorder) ZAdd(ctx, key any, members ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockRedisItf)(nil).ZAdd), varargs...)
+}
+
+// ZIncrBy mocks base method.
+func (m *MockRedisItf) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZIncrBy", ctx, key, increment, member)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZIncrBy indicates an expected call of ZIncrBy.
+func (mr *MockRedisItfMockRecorder) ZIncrBy(ctx, key, increment, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZIncrBy", reflect.TypeOf((*MockRedisItf)(nil).ZIncrBy), ctx, key, increment, member)
+}
+
+// ZRevRangeWithScores mocks base method.
+func (m *MockRedisItf) ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]Z, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRevRangeWithScores", ctx, key, start, stop)
+	ret0, _ := ret[0].([]Z)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRevRangeWithScores indicates an expected call of ZRevRangeWithScores.
+func (mr *MockRedisItfMockRecorder) ZRevRangeWithScores(ctx, key, start, stop any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRevRangeWithScores", reflect.TypeOf((*MockRedisItf)(nil).ZRevRangeWithScores), ctx, key, start, stop)
+}
@@ -3,10 +3,24 @@ package redis
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// subscribePingInterval is how long Subscribe waits for a message before it pings the connection.
+const subscribePingInterval = 30 * time.Second
+
+// incrByExScript increments KEYS[1] by ARGV[1] and makes it expire after ARGV[2] milliseconds when it has no
+// expiry yet, which is the case for a key the increment created.
+var incrByExScript = redis.NewScript(`
+	local resp = redis.call('INCRBY', KEYS[1], ARGV[1])
+	if redis.call('PTTL', KEYS[1]) < 0 then
+		redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	end
+	return resp
+`)
+
 type rdb struct {
 	client *redis.Client
 }
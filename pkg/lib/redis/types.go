@@ -0,0 +1,19 @@
+package redis
+
+// Z is a member of a sorted set with its score.
+type Z struct {
+	Member string
+	Score  float64
+}
+
+// XMessage is an entry of a stream.
+type XMessage struct {
+	ID     string
+	Values map[string]interface{}
+}
+
+// Message is a message published to a channel.
+type Message struct {
+	Channel string
+	Payload string
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 )
 
@@ -24,6 +25,7 @@ func (t token) Create(ttl time.Duration, content interface{}) (string, error) {
 
 	claims := make(jwt.MapClaims)
 	claims["dat"] = str
+	claims["jti"] = uuid.NewString()
 	claims["exp"] = now.Add(ttl).Unix()
 	claims["iat"] = now.Unix()
 	claims["nbf"] = now.Unix()
@@ -36,10 +38,10 @@ func (t token) Create(ttl time.Duration, content interface{}) (string, error) {
 	return token, nil
 }
 
-func (t token) Validate(token string) (interface{}, error) {
+func (t token) Validate(token string) (Claims, error) {
 	key, err := jwt.ParseRSAPublicKeyFromPEM(t.publicKey)
 	if err != nil {
-		return "", err
+		return Claims{}, err
 	}
 
 	if strings.HasPrefix(token, "Bearer ") {
@@ -54,13 +56,24 @@ func (t token) Validate(token string) (interface{}, error) {
 		return key, nil
 	})
 	if err != nil {
-		return nil, err
+		return Claims{}, err
 	}
 
 	claims, ok := tok.Claims.(jwt.MapClaims)
 	if !ok || !tok.Valid {
-		return nil, fmt.Errorf("invalid token")
+		return Claims{}, fmt.Errorf("invalid token")
+	}
+
+	jti, _ := claims["jti"].(string)
+	exp, _ := claims["exp"].(float64)
+	data, _ := claims["dat"].(string)
+	if jti == "" {
+		return Claims{}, fmt.Errorf("invalid token")
 	}
 
-	return claims["dat"], nil
+	return Claims{
+		Id:        jti,
+		ExpiresAt: time.Unix(int64(exp), 0).UTC(),
+		Data:      data,
+	}, nil
 }
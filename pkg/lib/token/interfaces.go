@@ -4,5 +4,5 @@ import "time"
 
 type TokenItf interface {
 	Create(ttl time.Duration, content interface{}) (string, error)
-	Validate(token string) (interface{}, error)
+	Validate(token string) (Claims, error)
 }
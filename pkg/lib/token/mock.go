@@ -55,10 +55,10 @@ func (mr *MockTokenItfMockRecorder) Create(ttl, content any) *gomock.Call {
 }
 
 // Validate mocks base method.
-func (m *MockTokenItf) Validate(token string) (any, error) {
+func (m *MockTokenItf) Validate(token string) (Claims, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Validate", token)
-	ret0, _ := ret[0].(any)
+	ret0, _ := ret[0].(Claims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
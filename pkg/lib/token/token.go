@@ -1,12 +1,22 @@
 package token
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
 type token struct {
 	privateKey []byte
 	publicKey  []byte
 }
 
+// Claims is the validated content of a token. Id is the unique token id (jti) that is used to revoke the token.
+type Claims struct {
+	Id        string
+	ExpiresAt time.Time
+	Data      string
+}
+
 func Init() (TokenItf, error) {
 	prvKey, err := os.ReadFile(os.Getenv("PRIVATE_KEY"))
 	if err != nil {
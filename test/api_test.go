@@ -55,13 +55,78 @@ func getTestCases() []TestCase {
 			Steps: []TestCaseStep{
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username"}`))
+						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username","password":"password"}`))
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusCreated, resp.StatusCode)
 						require.NotEmpty(t, data["token"].(string))
 					},
 				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest(http.MethodPost, ApiUrl+"/login", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username","password":"wrong-password"}`))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+						require.Equal(t, "invalid_credentials", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest(http.MethodPost, ApiUrl+"/login", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username","password":"password"}`))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.NotEmpty(t, data["token"].(string))
+						require.NotEmpty(t, data["refresh_token"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest(http.MethodPost, ApiUrl+"/token/refresh", bytes.NewBufferString(`{"refresh_token":"`+tc.Steps[2].Result["refresh_token"].(string)+`"}`))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.NotEmpty(t, data["token"].(string))
+						require.NotEmpty(t, data["refresh_token"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest(http.MethodPost, ApiUrl+"/token/refresh", bytes.NewBufferString(`{"refresh_token":"`+tc.Steps[2].Result["refresh_token"].(string)+`"}`))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest(http.MethodPost, ApiUrl+"/token/refresh", bytes.NewBufferString(`{"refresh_token":"`+tc.Steps[3].Result["refresh_token"].(string)+`"}`))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/logout", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[3].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusNoContent, resp.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/balance_read", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[3].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+					},
+				},
 			},
 		},
 		{
@@ -69,7 +134,7 @@ func getTestCases() []TestCase {
 			Steps: []TestCaseStep{
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username1"}`))
+						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username1","password":"password"}`))
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusCreated, resp.StatusCode)
@@ -94,7 +159,7 @@ func getTestCases() []TestCase {
 			Steps: []TestCaseStep{
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username3"}`))
+						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username3","password":"password"}`))
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusCreated, resp.StatusCode)
@@ -139,7 +204,7 @@ func getTestCases() []TestCase {
 			Steps: []TestCaseStep{
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username4"}`))
+						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username4","password":"password"}`))
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusCreated, resp.StatusCode)
@@ -148,7 +213,7 @@ func getTestCases() []TestCase {
 				},
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username5"}`))
+						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username5","password":"password"}`))
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusCreated, resp.StatusCode)
@@ -236,7 +301,7 @@ func getTestCases() []TestCase {
 			Steps: []TestCaseStep{
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username6"}`))
+						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username6","password":"password"}`))
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusCreated, resp.StatusCode)
@@ -245,7 +310,7 @@ func getTestCases() []TestCase {
 				},
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username7"}`))
+						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username7","password":"password"}`))
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusCreated, resp.StatusCode)
@@ -391,28 +456,444 @@ func getTestCases() []TestCase {
 				},
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/top_users", nil)
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/top_users?direction=sent&limit=5", nil)
 						req.Header.Set("Authorization", "Bearer "+tc.Steps[0].Result["token"].(string))
 						return req, err
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusOK, resp.StatusCode)
-						require.Equal(t, int(1), len(data2))
-						require.Equal(t, float64(100000), data2[0]["transacted_value"].(float64))
-						require.Equal(t, PrefixUsername+"username7", data2[0]["username"].(string))
+						require.NotEmpty(t, data["data"].([]any))
+						require.LessOrEqual(t, len(data["data"].([]any)), 5)
+
+						first := data["data"].([]any)[0].(map[string]any)
+						require.Equal(t, float64(1), first["rank"].(float64))
+						require.GreaterOrEqual(t, first["transacted_value"].(float64), float64(100000))
 					},
 				},
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/top_users", nil)
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/top_users?direction=foo", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+						require.Equal(t, "invalid_filter", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/transactions?limit=2", nil)
 						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
 						return req, err
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
 						require.Equal(t, http.StatusOK, resp.StatusCode)
-						require.Equal(t, int(1), len(data2))
-						require.Equal(t, float64(25000), data2[0]["transacted_value"].(float64))
-						require.Equal(t, PrefixUsername+"username6", data2[0]["username"].(string))
+						require.Equal(t, int(2), len(data["data"].([]any)))
+						require.NotEmpty(t, data["next_cursor"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/transactions?limit=2&cursor="+tc.Steps[15].Result["next_cursor"].(string), nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, int(1), len(data["data"].([]any)))
+						require.Nil(t, data["next_cursor"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/transactions?type=DEBIT", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, int(1), len(data["data"].([]any)))
+
+						transaction := data["data"].([]any)[0].(map[string]any)
+						require.Equal(t, float64(-25000), transaction["amount"].(float64))
+						require.Equal(t, PrefixUsername+"username6", transaction["counterparty_username"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/top_transaction_per_user?window=7d", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, int(3), len(data2))
+
+						require.Equal(t, float64(100000), data2[0]["amount"].(float64))
+						require.Equal(t, float64(-25000), data2[2]["amount"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/top_users?window=7d&direction=received", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.NotEmpty(t, data["data"].([]any))
+
+						first := data["data"].([]any)[0].(map[string]any)
+						require.Equal(t, float64(1), first["rank"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/top_users?window=month:2026-13", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+						require.Equal(t, "invalid_filter", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/transactions?type=CREDIT&counterparty="+PrefixUsername+"username6", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, int(1), len(data["data"].([]any)))
+
+						transaction := data["data"].([]any)[0].(map[string]any)
+						require.Equal(t, float64(100000), transaction["amount"].(float64))
+						require.NotEmpty(t, transaction["transfer_id"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						transferId := tc.Steps[21].Result["data"].([]any)[0].(map[string]any)["transfer_id"].(string)
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/transfers/"+transferId+"/refund", bytes.NewBufferString(`{"amount":40000}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusCreated, resp.StatusCode)
+						require.NotEmpty(t, data["refund_id"].(string))
+						require.Equal(t, float64(40000), data["amount"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						transferId := tc.Steps[21].Result["data"].([]any)[0].(map[string]any)["transfer_id"].(string)
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/transfers/"+transferId+"/refund", bytes.NewBufferString(`{"amount":70000}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+						require.Equal(t, "refund_exceeds_transfer", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						transferId := tc.Steps[21].Result["data"].([]any)[0].(map[string]any)["transfer_id"].(string)
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/transfers/"+transferId+"/refund", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[0].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusNotFound, resp.StatusCode)
+						require.Equal(t, "transfer_not_found", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						transferId := tc.Steps[21].Result["data"].([]any)[0].(map[string]any)["transfer_id"].(string)
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/transfers/"+transferId+"/refund", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusCreated, resp.StatusCode)
+						require.Equal(t, float64(60000), data["amount"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/balance_read", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, float64(25000), data["balance"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/holds", bytes.NewBufferString(`{"amount":20000,"to_username":"`+PrefixUsername+`username6"}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusCreated, resp.StatusCode)
+						require.NotEmpty(t, data["id"].(string))
+						require.Equal(t, "active", data["status"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/balance_read", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, float64(25000), data["balance"].(float64))
+						require.Equal(t, float64(20000), data["held_balance"].(float64))
+						require.Equal(t, float64(5000), data["available_balance"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/transfer", bytes.NewBufferString(`{"amount":10000,"to_username":"`+PrefixUsername+`username6"}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+						require.Equal(t, "insufficient_balance", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/holds/"+tc.Steps[27].Result["id"].(string)+"/capture", bytes.NewBufferString(`{"amount":5000}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[0].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, "captured", data["status"].(string))
+						require.Equal(t, float64(5000), data["captured_amount"].(float64))
+						require.NotEmpty(t, data["transfer_id"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/holds/"+tc.Steps[27].Result["id"].(string)+"/void", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[0].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusConflict, resp.StatusCode)
+						require.Equal(t, "hold_not_active", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/balance_read", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, float64(20000), data["balance"].(float64))
+						require.Equal(t, float64(0), data["held_balance"].(float64))
+						require.Equal(t, float64(20000), data["available_balance"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/payment_requests", bytes.NewBufferString(`{"amount":5000,"payer_username":"`+PrefixUsername+`username7"}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[0].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusCreated, resp.StatusCode)
+						require.NotEmpty(t, data["id"].(string))
+						require.Equal(t, "pending", data["status"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/payment_requests/incoming", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						paymentRequest := data["data"].([]any)[0].(map[string]any)
+						require.Equal(t, tc.Steps[33].Result["id"].(string), paymentRequest["id"].(string))
+						require.Equal(t, PrefixUsername+"username6", paymentRequest["requester_username"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/payment_requests/"+tc.Steps[33].Result["id"].(string)+"/accept", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, "accepted", data["status"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/payment_requests/"+tc.Steps[33].Result["id"].(string)+"/accept", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, "accepted", data["status"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/payment_requests/"+tc.Steps[33].Result["id"].(string)+"/cancel", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[0].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusConflict, resp.StatusCode)
+						require.Equal(t, "payment_request_not_pending", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/balance_read", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, float64(15000), data["balance"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/transfer", bytes.NewBufferString(`{"amount":5000001,"to_username":"`+PrefixUsername+`username6"}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+						require.Equal(t, "limit_exceeded", data["error"].(map[string]any)["code"].(string))
+						require.Equal(t, "transaction", data["error"].(map[string]any)["details"].(map[string]any)["period"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/limits", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, "basic", data["tier"].(string))
+						require.Len(t, data["limits"].([]any), 6)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/transfer/quote", bytes.NewBufferString(`{"amount":5000,"to_username":"`+PrefixUsername+`username6"}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, float64(5000), data["amount"].(float64))
+						require.Equal(t, data["amount"].(float64)+data["fee"].(float64), data["total"].(float64))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/account", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, "active", data["status"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/admin/users/"+PrefixUsername+"id/status", bytes.NewBufferString(`{"status":"frozen","reason":"test"}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusForbidden, resp.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/pockets", bytes.NewBufferString(`{"name":"savings","goal_amount":1000}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusCreated, resp.StatusCode)
+						require.Equal(t, "savings", data["name"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/wallets", bytes.NewBufferString(`{"name":"family"}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusCreated, resp.StatusCode)
+						require.Equal(t, "owner", data["role"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest(http.MethodPost, ApiUrl+"/create_user", bytes.NewBufferString(`{"username":"`+PrefixUsername+`username8","password":"password","currency":"USD"}`))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusCreated, resp.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/transfer", bytes.NewBufferString(`{"amount":1000,"to_username":"`+PrefixUsername+`username8"}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+						require.Equal(t, "currency_mismatch", data["error"].(map[string]any)["code"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodGet, ApiUrl+"/balances", nil)
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusOK, resp.StatusCode)
+						require.Equal(t, "IDR", data["data"].([]any)[0].(map[string]any)["currency"].(string))
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest(http.MethodPost, ApiUrl+"/convert", bytes.NewBufferString(`{"from_currency":"IDR","to_currency":"IDR","amount":1000}`))
+						req.Header.Set("Authorization", "Bearer "+tc.Steps[1].Result["token"].(string))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, resp *http.Response, data map[string]any, data2 []map[string]any) {
+						require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+						require.Equal(t, "invalid_currency", data["error"].(map[string]any)["code"].(string))
 					},
 				},
 			},